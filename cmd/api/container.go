@@ -1,26 +1,38 @@
 package main
 
 import (
+	"context"
+
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/prawirdani/golang-restapi/config"
+	"github.com/prawirdani/golang-restapi/internal/domain/audit"
 	"github.com/prawirdani/golang-restapi/internal/domain/auth"
+	"github.com/prawirdani/golang-restapi/internal/domain/organization"
 	"github.com/prawirdani/golang-restapi/internal/domain/user"
+	"github.com/prawirdani/golang-restapi/internal/infrastructure/identity/oidc"
+	"github.com/prawirdani/golang-restapi/internal/infrastructure/identity/webauthn"
 	"github.com/prawirdani/golang-restapi/internal/infrastructure/messaging/rabbitmq"
+	"github.com/prawirdani/golang-restapi/internal/infrastructure/repository/memory"
 	"github.com/prawirdani/golang-restapi/internal/infrastructure/repository/postgres"
 	"github.com/prawirdani/golang-restapi/internal/infrastructure/storage/r2"
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
 type Services struct {
-	UserService *user.Service
-	AuthService *auth.Service
+	UserService         *user.Service
+	AuthService         *auth.Service
+	OAuthService        *auth.OAuthService
+	AuditService        *audit.Service
+	OrganizationService *organization.Service
 }
 
 // Container holds all application dependencies
 type Container struct {
-	Config   *config.Config
-	Services *Services
-	pgpool   *pgxpool.Pool
+	Config      *config.Config
+	Services    *Services
+	KeySet      *auth.KeySet
+	Revocations auth.TokenRevocationStore
+	pgpool      *pgxpool.Pool
 }
 
 // NewContainer initializes all dependencies
@@ -45,24 +57,84 @@ func NewContainer(
 	}
 
 	// Setup Services
-	userService := user.NewService(transactor, repoFactory.User(), r2PublicStorage)
+	userService := user.NewService(transactor, repoFactory.User(), r2PublicStorage, repoFactory.Audit())
+
+	keySet, err := auth.LoadKeySet(cfg.Auth)
+	if err != nil {
+		return nil, err
+	}
+
+	breachedPasswords, err := auth.LoadBreachedPasswords(cfg.Auth.BreachedPasswordsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	identityProviders, err := oidc.NewProviders(context.Background(), cfg.Auth.OIDCProviders)
+	if err != nil {
+		return nil, err
+	}
+
+	// Passkeys stay disabled without a relying party ID
+	var relyingParty auth.WebAuthnRelyingParty
+	if cfg.Auth.WebAuthnRPID != "" {
+		relyingParty, err = webauthn.New(cfg.Auth)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Revoked access tokens are shared across instances through postgres, unless configured otherwise
+	var revocations auth.TokenRevocationStore = repoFactory.TokenRevocation()
+	if cfg.Auth.TokenRevocationStore == "memory" {
+		revocations = memory.NewTokenRevocationStore()
+	}
 
 	authMessagePublisher := rabbitmq.NewAuthMessagePublisher(rmqconn)
 	authService := auth.NewService(
 		cfg.Auth,
+		keySet,
+		breachedPasswords,
 		transactor,
 		repoFactory.User(),
 		repoFactory.Auth(),
+		repoFactory.Role(),
+		repoFactory.OAuth(),
 		authMessagePublisher,
+		repoFactory.Audit(),
+		revocations,
+		relyingParty,
+		identityProviders...,
+	)
+	oauthService := auth.NewOAuthService(
+		cfg.Auth,
+		keySet,
+		transactor,
+		repoFactory.User(),
+		repoFactory.OAuth(),
+		repoFactory.Role(),
+		revocations,
+	)
+	organizationService := organization.NewService(
+		cfg.Organization,
+		transactor,
+		repoFactory.Organization(),
+		repoFactory.User(),
+		rabbitmq.NewOrganizationMessagePublisher(rmqconn),
+		repoFactory.Audit(),
 	)
 
 	c := &Container{
 		Config: cfg,
 		Services: &Services{
-			UserService: userService,
-			AuthService: authService,
+			UserService:         userService,
+			AuthService:         authService,
+			OAuthService:        oauthService,
+			AuditService:        audit.NewService(repoFactory.Audit()),
+			OrganizationService: organizationService,
 		},
-		pgpool: pgpool,
+		KeySet:      keySet,
+		Revocations: revocations,
+		pgpool:      pgpool,
 	}
 
 	return c, nil
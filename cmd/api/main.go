@@ -78,6 +78,14 @@ func initRabbitMQ(url string) (*amqp.Connection, error) {
 	if err := rabbitmq.SetupTopologies(
 		conn,
 		rabbitmq.ResetPasswordEmailTopology,
+		rabbitmq.VerificationEmailTopology,
+		rabbitmq.EmailChangeConfirmationTopology,
+		rabbitmq.EmailChangeNotificationTopology,
+		rabbitmq.AccountLockedEmailTopology,
+		rabbitmq.MagicLinkEmailTopology,
+		rabbitmq.AccountExistsEmailTopology,
+		rabbitmq.NewDeviceLoginEmailTopology,
+		rabbitmq.InvitationEmailTopology,
 	); err != nil {
 		return nil, fmt.Errorf("setup topologies: %w", err)
 	}
@@ -34,6 +34,9 @@ func NewServer(container *Container) (*Server, error) {
 		container.Config.App.Port+1,
 	)
 
+	// Resolved first, the rate limiter and audit log key on the client IP
+	router.Use(middleware.ClientIP(container.Config.App.TrustedProxies))
+
 	if container.Config.IsProduction() {
 		router.Use(middleware.RequestID)
 		router.Use(middleware.RateLimit(50, 1*time.Minute))
@@ -45,11 +48,13 @@ func NewServer(container *Container) (*Server, error) {
 	// Apply common middlewares
 	router.Use(middleware.MaxBodySizeMiddleware(handler.MaxBodySize))
 	router.Use(handler.Middleware(middleware.PanicRecoverer))
+	router.Use(handler.Middleware(middleware.AuditRequest))
 	router.Use(middleware.Gzip)
 	router.Use(middleware.Cors(
 		container.Config.Cors.Origins,
 		container.Config.Cors.Credentials,
 		!container.Config.IsProduction(),
+		container.Config.Organization.TenantHeader,
 	))
 
 	// Custom 404 and 405 handlers
@@ -151,16 +156,58 @@ func (s *Server) setupHandlers() {
 	svcs := s.container.Services
 
 	// Initialize Handlers
-	userHandler := handler.NewUserHandler(svcs.UserService)
-	authHandler := handler.NewAuthHandler(s.container.Config, svcs.AuthService, svcs.UserService)
+	userHandler := handler.NewUserHandler(
+		s.container.Config,
+		svcs.UserService,
+		svcs.AuthService,
+		svcs.AuditService,
+	)
+	authHandler := handler.NewAuthHandler(
+		s.container.Config,
+		svcs.AuthService,
+		svcs.UserService,
+		svcs.OrganizationService,
+	)
+	roleHandler := handler.NewRoleHandler(svcs.AuthService)
+	adminHandler := handler.NewAdminHandler(svcs.UserService, svcs.AuthService, svcs.AuditService)
+	oauthHandler := handler.NewOAuthHandler(s.container.Config, svcs.OAuthService)
+	organizationHandler := handler.NewOrganizationHandler(svcs.OrganizationService)
+
+	authMiddleware := handler.Middleware(
+		middleware.Auth(s.container.KeySet, s.container.Revocations, svcs.AuthService, svcs.AuthService),
+	)
+	optionalAuthMiddleware := handler.Middleware(
+		middleware.OptionalAuth(s.container.KeySet, s.container.Revocations, svcs.AuthService),
+	)
+	recentAuthMiddleware := handler.Middleware(
+		middleware.RequireRecentAuth(s.container.Config.Auth.ReauthenticationMaxAge),
+	)
+	tenantMiddleware := handler.Middleware(middleware.Tenant(
+		svcs.OrganizationService,
+		s.container.Config.Organization.TenantHeader,
+		s.container.Config.Organization.TenantBaseDomain,
+	))
+
+	// Public keys for verifying our access tokens
+	httptransport.RegisterWellKnownRoutes(s.router, authHandler)
 
-	authMiddleware := handler.Middleware(middleware.Auth(s.container.Config.Auth.JwtSecret))
+	// OAuth2 authorization server for first-party apps
+	httptransport.RegisterOAuthRoutes(s.router, oauthHandler, optionalAuthMiddleware)
 
 	// Register API routes
 	s.router.Route("/api", func(r chi.Router) {
 		r.Route("/v1", func(r chi.Router) {
-			httptransport.RegisterUserRoutes(r, userHandler, authMiddleware)
-			httptransport.RegisterAuthRoutes(r, authHandler, authMiddleware)
+			// Login routes start a session, so there is no CSRF token yet, any stale one is replaced on success
+			r.Use(handler.Middleware(middleware.CSRF(
+				"/api/v1/auth/login",
+				"/api/v1/auth/magic-link/verify",
+				"/api/v1/auth/refresh",
+			)))
+
+			httptransport.RegisterUserRoutes(r, userHandler, authHandler, authMiddleware, recentAuthMiddleware)
+			httptransport.RegisterAuthRoutes(r, authHandler, authMiddleware, recentAuthMiddleware)
+			httptransport.RegisterAdminRoutes(r, adminHandler, roleHandler, oauthHandler, authMiddleware)
+			httptransport.RegisterOrganizationRoutes(r, organizationHandler, authMiddleware, tenantMiddleware)
 		})
 	})
 }
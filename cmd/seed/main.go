@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+
+	stdlog "log"
+
+	"github.com/prawirdani/golang-restapi/config"
+	"github.com/prawirdani/golang-restapi/internal/domain/auth"
+	"github.com/prawirdani/golang-restapi/internal/domain/user"
+	"github.com/prawirdani/golang-restapi/internal/infrastructure/repository/postgres"
+	"github.com/prawirdani/golang-restapi/pkg/log"
+)
+
+// Seeds an administrator account, granting the admin role to an existing user or creating the user first.
+// Safe to run repeatedly.
+func main() {
+	name := flag.String("name", os.Getenv("SEED_ADMIN_NAME"), "admin display name")
+	email := flag.String("email", os.Getenv("SEED_ADMIN_EMAIL"), "admin email address")
+	password := flag.String("password", os.Getenv("SEED_ADMIN_PASSWORD"), "admin password, only used when creating the user")
+	flag.Parse()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		stdlog.Fatal("Failed to load config", err)
+	}
+	log.SetLogger(log.NewZerologAdapter(cfg))
+
+	if *email == "" {
+		log.Error("Missing admin email", errors.New("set -email or SEED_ADMIN_EMAIL"))
+		os.Exit(1)
+	}
+
+	pgpool, err := postgres.NewPool(cfg.Postgres)
+	if err != nil {
+		log.Error("Failed to create postgres connection", err)
+		os.Exit(1)
+	}
+	defer pgpool.Close()
+
+	repoFactory := postgres.NewRepositoryFactory(pgpool)
+	transactor := postgres.NewTransactor(pgpool)
+	userRepo := repoFactory.User()
+	roleRepo := repoFactory.Role()
+
+	ctx := context.Background()
+	err = transactor.Transact(ctx, func(ctx context.Context) error {
+		admin, err := userRepo.GetByEmail(ctx, *email)
+		if err != nil && !errors.Is(err, user.ErrNotFound) {
+			return err
+		}
+
+		if admin == nil {
+			if *name == "" || *password == "" {
+				return errors.New("user does not exist, name and password are required to create it")
+			}
+
+			hashedPassword, err := auth.NewPasswordManager(cfg.Auth).Hash(*password)
+			if err != nil {
+				return err
+			}
+
+			admin, err = user.New(*name, *email, "", hashedPassword)
+			if err != nil {
+				return err
+			}
+			admin.MarkVerified()
+
+			if err := userRepo.Store(ctx, admin); err != nil {
+				return err
+			}
+			log.Info("Admin user created", "email", admin.Email)
+		}
+
+		role, err := roleRepo.GetRoleByName(ctx, auth.RoleAdmin)
+		if err != nil {
+			return err
+		}
+
+		return roleRepo.AssignRole(ctx, admin.ID.String(), role.ID)
+	})
+	if err != nil {
+		log.Error("Failed to seed admin", err)
+		os.Exit(1)
+	}
+
+	log.Info("Admin role granted", "email", *email)
+}
@@ -6,11 +6,15 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	stdlog "log"
 
 	"github.com/prawirdani/golang-restapi/config"
+	"github.com/prawirdani/golang-restapi/internal/domain/user"
 	"github.com/prawirdani/golang-restapi/internal/infrastructure/messaging/rabbitmq"
+	"github.com/prawirdani/golang-restapi/internal/infrastructure/repository/postgres"
+	"github.com/prawirdani/golang-restapi/internal/infrastructure/storage/r2"
 	"github.com/prawirdani/golang-restapi/internal/transport/amqp/consumer"
 	"github.com/prawirdani/golang-restapi/pkg/log"
 	"github.com/prawirdani/golang-restapi/pkg/mailer"
@@ -24,6 +28,13 @@ func main() {
 	}
 	log.SetLogger(log.NewZerologAdapter(cfg))
 
+	pgpool, err := postgres.NewPool(cfg.Postgres)
+	if err != nil {
+		log.Error("Failed to create postgres connection", err)
+		os.Exit(1)
+	}
+	defer pgpool.Close()
+
 	rmqconn, err := initRabbitMQ(cfg.RabbitMQURL)
 	if err != nil {
 		log.Error("Failed to init rabbit mq", err)
@@ -43,6 +54,22 @@ func main() {
 		cancel()
 	}()
 
+	r2PublicStorage, err := r2.New(r2.Config{
+		BucketURL:       cfg.R2.PublicBucketURL,
+		BucketName:      cfg.R2.PublicBucket,
+		AccountID:       cfg.R2.AccountID,
+		AccessKeyID:     cfg.R2.AccessKeyID,
+		AccessKeySecret: cfg.R2.AccessKeySecret,
+	})
+	if err != nil {
+		log.Error("Failed to create r2 storage", err)
+		os.Exit(1)
+	}
+
+	repoFactory := postgres.NewRepositoryFactory(pgpool)
+	userService := user.NewService(postgres.NewTransactor(pgpool), repoFactory.User(), r2PublicStorage, repoFactory.Audit())
+	go startPurgeDeletedUsersJob(ctx, userService, cfg.User)
+
 	if err := startMessageConsumers(ctx, rmqconn, cfg); err != nil && err != context.Canceled {
 		log.Error("Worker exited with error", err)
 		cancel()
@@ -60,6 +87,14 @@ func initRabbitMQ(url string) (*amqp.Connection, error) {
 	if err := rabbitmq.SetupTopologies(
 		conn,
 		rabbitmq.ResetPasswordEmailTopology,
+		rabbitmq.VerificationEmailTopology,
+		rabbitmq.EmailChangeConfirmationTopology,
+		rabbitmq.EmailChangeNotificationTopology,
+		rabbitmq.AccountLockedEmailTopology,
+		rabbitmq.MagicLinkEmailTopology,
+		rabbitmq.AccountExistsEmailTopology,
+		rabbitmq.NewDeviceLoginEmailTopology,
+		rabbitmq.InvitationEmailTopology,
 	); err != nil {
 		return nil, fmt.Errorf("setup topologies: %w", err)
 	}
@@ -75,21 +110,34 @@ func startMessageConsumers(
 ) error {
 	m := mailer.New(cfg.SMTP)
 	authConsumers := consumer.NewAuthMessageConsumer(m)
+	orgConsumers := consumer.NewOrganizationMessageConsumer(m)
 	consumerClient := consumer.NewConsumerClient(conn)
 
-	errCh := make(chan error, 1)
+	consumers := []struct {
+		topology *rabbitmq.Topology
+		handler  consumer.HandlerFunc
+	}{
+		{rabbitmq.ResetPasswordEmailTopology, authConsumers.EmailResetPasswordHandler},
+		{rabbitmq.VerificationEmailTopology, authConsumers.EmailVerificationHandler},
+		{rabbitmq.EmailChangeConfirmationTopology, authConsumers.EmailChangeConfirmationHandler},
+		{rabbitmq.EmailChangeNotificationTopology, authConsumers.EmailChangeNotificationHandler},
+		{rabbitmq.AccountLockedEmailTopology, authConsumers.AccountLockedEmailHandler},
+		{rabbitmq.MagicLinkEmailTopology, authConsumers.MagicLinkEmailHandler},
+		{rabbitmq.AccountExistsEmailTopology, authConsumers.AccountExistsEmailHandler},
+		{rabbitmq.NewDeviceLoginEmailTopology, authConsumers.NewDeviceLoginEmailHandler},
+		{rabbitmq.InvitationEmailTopology, orgConsumers.InvitationEmailHandler},
+	}
+
+	errCh := make(chan error, len(consumers))
 
 	// Run consumers in background
-	// TODO: As things grows, consider using slice of [topology+handler] and run all of it through loops
-	go func() {
-		if err := consumerClient.Consume(
-			ctx,
-			rabbitmq.ResetPasswordEmailTopology,
-			authConsumers.EmailResetPasswordHandler,
-		); err != nil {
-			errCh <- err
-		}
-	}()
+	for _, c := range consumers {
+		go func() {
+			if err := consumerClient.Consume(ctx, c.topology, c.handler); err != nil {
+				errCh <- err
+			}
+		}()
+	}
 
 	select {
 	case <-ctx.Done():
@@ -102,3 +150,28 @@ func startMessageConsumers(
 		return nil
 	}
 }
+
+// Periodically purges soft deleted users whose grace period is over, blocks until ctx is done
+func startPurgeDeletedUsersJob(ctx context.Context, userService *user.Service, cfg config.User) {
+	ticker := time.NewTicker(cfg.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := userService.PurgeDeletedUsers(
+			ctx,
+			cfg.DeletionGracePeriod,
+			user.PurgeMode(cfg.DeletionPurgeMode),
+		)
+		if err != nil {
+			log.ErrorCtx(ctx, "Failed to purge deleted users", err)
+		} else if purged > 0 {
+			log.InfoCtx(ctx, "Purged deleted users", "count", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -1,16 +1,19 @@
 package config
 
 import (
+	"fmt"
+	"net/netip"
 	"os"
 	"strconv"
 	"strings"
 )
 
 type App struct {
-	Name        string
-	Version     string
-	Port        int
-	Environment AppEnv
+	Name           string
+	Version        string
+	Port           int
+	Environment    AppEnv
+	TrustedProxies []netip.Prefix // Proxies whose X-Forwarded-For header is trusted, empty trusts none
 }
 
 func (a *App) Parse() error {
@@ -25,5 +28,27 @@ func (a *App) Parse() error {
 		}
 		a.Port = port
 	}
+	if val := os.Getenv("APP_TRUSTED_PROXIES"); val != "" {
+		for _, entry := range strings.Split(val, ",") {
+			prefix, err := parsePrefix(strings.TrimSpace(entry))
+			if err != nil {
+				return fmt.Errorf("invalid APP_TRUSTED_PROXIES entry %q: %w", entry, err)
+			}
+			a.TrustedProxies = append(a.TrustedProxies, prefix)
+		}
+	}
 	return nil
 }
+
+// parsePrefix parses a CIDR, or a single IP address as the prefix holding only that address.
+func parsePrefix(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		return netip.ParsePrefix(s)
+	}
+
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
@@ -2,20 +2,125 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
 	"time"
 )
 
+const (
+	defaultReauthenticationMaxAge = 10 * time.Minute
+	defaultMFAChallengeTTL        = 5 * time.Minute
+	defaultMagicLinkTTL           = 15 * time.Minute
+)
+
 type Auth struct {
-	JwtSecret                 string
-	JwtTTL                    time.Duration
-	SessionTTL                time.Duration
-	ResetPasswordTTL          time.Duration
-	ResetPasswordFormEndpoint string
+	JwtSecret                  string
+	JwtSigningKeyFile          string
+	JwtVerificationKeyFiles    []string
+	JwtTTL                     time.Duration
+	SessionTTL                 time.Duration
+	ResetPasswordTTL           time.Duration
+	ResetPasswordFormEndpoint  string
+	EmailVerificationTTL       time.Duration
+	EmailVerificationEndpoint  string
+	EmailChangeTTL             time.Duration
+	EmailChangeEndpoint        string
+	MagicLinkTTL               time.Duration // How long a sign in link mailed to the user stays valid
+	MagicLinkEndpoint          string
+	RequireVerifiedEmail       bool
+	EnumerationProtection      bool // Answer the same whether or not an account exists for the given email
+	MFAIssuer                  string
+	MFAChallengeTTL            time.Duration // How long a login has to complete its second factor
+	LoginMaxFailures           int           // Failed logins that lock an account, zero disables the lockout
+	LoginIPMaxFailures         int           // Failed logins that block a client IP, zero disables the block
+	LoginFailureWindow         time.Duration // Failures older than this are forgotten
+	LoginLockoutDuration       time.Duration
+	LoginBaseDelay             time.Duration // Wait after the first failed login, doubled on every further failure
+	AccountUnlockTTL           time.Duration
+	AccountUnlockEndpoint      string
+	NewDeviceRevokeTTL         time.Duration // Validity of the "this wasn't me" link mailed on a login from a new device
+	NewDeviceRevokeEndpoint    string
+	RejectUserAgentChange      bool   // Reject refreshes from another user agent than the session's, not only flag them
+	PasswordAlgorithm          string // "argon2id" or "bcrypt", stored hashes of the other are upgraded on login
+	Argon2Memory               uint32 // Memory in KiB, zero uses the default
+	Argon2Iterations           uint32
+	Argon2Parallelism          uint8
+	BcryptCost                 int
+	PasswordMinLength          int
+	PasswordMaxLength          int
+	PasswordMinCharClasses     int    // Distinct classes required among lowercase, uppercase, digits and symbols
+	PasswordHistorySize        int    // Most recent passwords that can't be reused, zero disables the check
+	PasswordRejectPersonalInfo bool   // Reject passwords containing the user's name or email
+	BreachedPasswordsFile      string // SHA-1 hash list of breached passwords, empty disables the check
+	OIDCProviders              []OIDCProvider
+	OAuthCodeTTL               time.Duration // Zero uses the default of 10 minutes
+	OAuthAccessTokenTTL        time.Duration // Zero uses JwtTTL
+	OAuthRefreshTokenTTL       time.Duration // Zero uses SessionTTL
+	OAuthLoginEndpoint         string        // Web UI the authorize endpoint sends signed out users to
+	WebAuthnRPID               string        // Domain passkeys are bound to, empty disables passkeys
+	WebAuthnRPName             string        // Shown by authenticators, defaults to APP_NAME
+	WebAuthnOrigins            []string      // Origins of the web apps allowed to use the passkeys
+	WebAuthnChallengeTTL       time.Duration // Zero uses the default of 5 minutes
+	TokenRevocationStore       string        // "postgres" or "memory", where revoked access tokens are denylisted
+	ImpersonationTTL           time.Duration // Zero uses the default of 15 minutes
+	ReauthenticationMaxAge     time.Duration // How long after authenticating sensitive operations are allowed
+	CookieDomain               string        // Domain of the token cookies, empty scopes them to the API host
+	CookieSameSite             string        // "lax", "strict" or "none", SameSite attribute of the token cookies
+}
+
+// OIDCProvider configures an external OpenID Connect provider users can sign in with.
+type OIDCProvider struct {
+	Name         string // Used in the login routes, e.g. /auth/oidc/google/login
+	IssuerURL    string // Discovery document is fetched from {IssuerURL}/.well-known/openid-configuration
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string // Requested on top of the openid scope, defaults to email and profile
 }
 
 func (t *Auth) Parse() error {
 	t.JwtSecret = os.Getenv("AUTH_JWT_SECRET")
+	t.JwtSigningKeyFile = os.Getenv("AUTH_JWT_SIGNING_KEY_FILE")
+	if val := os.Getenv("AUTH_JWT_VERIFICATION_KEY_FILES"); val != "" {
+		t.JwtVerificationKeyFiles = strings.Split(val, ",")
+	}
 	t.ResetPasswordFormEndpoint = os.Getenv("AUTH_RESET_PASSWORD_FORM_ENDPOINT")
+	t.EmailVerificationEndpoint = os.Getenv("AUTH_EMAIL_VERIFICATION_ENDPOINT")
+	t.EmailChangeEndpoint = os.Getenv("AUTH_EMAIL_CHANGE_ENDPOINT")
+	t.AccountUnlockEndpoint = os.Getenv("AUTH_ACCOUNT_UNLOCK_ENDPOINT")
+	t.NewDeviceRevokeEndpoint = os.Getenv("AUTH_NEW_DEVICE_REVOKE_ENDPOINT")
+	t.MagicLinkEndpoint = os.Getenv("AUTH_MAGIC_LINK_ENDPOINT")
+	t.OAuthLoginEndpoint = os.Getenv("AUTH_OAUTH_LOGIN_ENDPOINT")
+	t.PasswordAlgorithm = os.Getenv("AUTH_PASSWORD_ALGORITHM")
+	if t.PasswordAlgorithm == "" {
+		t.PasswordAlgorithm = "argon2id"
+	}
+	t.BreachedPasswordsFile = os.Getenv("AUTH_BREACHED_PASSWORDS_FILE")
+	t.PasswordRejectPersonalInfo = true
+	t.MFAIssuer = os.Getenv("AUTH_MFA_ISSUER")
+	if t.MFAIssuer == "" {
+		t.MFAIssuer = os.Getenv("APP_NAME")
+	}
+	t.WebAuthnRPID = os.Getenv("AUTH_WEBAUTHN_RP_ID")
+	t.WebAuthnRPName = os.Getenv("AUTH_WEBAUTHN_RP_NAME")
+	if t.WebAuthnRPName == "" {
+		t.WebAuthnRPName = os.Getenv("APP_NAME")
+	}
+	if val := os.Getenv("AUTH_WEBAUTHN_ORIGINS"); val != "" {
+		t.WebAuthnOrigins = strings.Split(val, ",")
+	}
+	t.TokenRevocationStore = os.Getenv("AUTH_TOKEN_REVOCATION_STORE")
+	if t.TokenRevocationStore == "" {
+		t.TokenRevocationStore = "postgres"
+	}
+	t.ReauthenticationMaxAge = defaultReauthenticationMaxAge
+	t.MFAChallengeTTL = defaultMFAChallengeTTL
+	t.MagicLinkTTL = defaultMagicLinkTTL
+	t.CookieDomain = os.Getenv("AUTH_COOKIE_DOMAIN")
+	t.CookieSameSite = strings.ToLower(os.Getenv("AUTH_COOKIE_SAME_SITE"))
+	if t.CookieSameSite == "" {
+		t.CookieSameSite = "lax"
+	}
 
 	if val := os.Getenv("AUTH_JWT_TTL"); val != "" {
 		if d, err := time.ParseDuration(val); err == nil {
@@ -32,5 +137,172 @@ func (t *Auth) Parse() error {
 			t.ResetPasswordTTL = d
 		}
 	}
+	if val := os.Getenv("AUTH_EMAIL_VERIFICATION_TTL"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil {
+			t.EmailVerificationTTL = d
+		}
+	}
+	if val := os.Getenv("AUTH_EMAIL_CHANGE_TTL"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil {
+			t.EmailChangeTTL = d
+		}
+	}
+	if val := os.Getenv("AUTH_MFA_CHALLENGE_TTL"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil {
+			t.MFAChallengeTTL = d
+		}
+	}
+	if val := os.Getenv("AUTH_MAGIC_LINK_TTL"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil {
+			t.MagicLinkTTL = d
+		}
+	}
+	if val := os.Getenv("AUTH_OAUTH_CODE_TTL"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil {
+			t.OAuthCodeTTL = d
+		}
+	}
+	if val := os.Getenv("AUTH_OAUTH_ACCESS_TOKEN_TTL"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil {
+			t.OAuthAccessTokenTTL = d
+		}
+	}
+	if val := os.Getenv("AUTH_OAUTH_REFRESH_TOKEN_TTL"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil {
+			t.OAuthRefreshTokenTTL = d
+		}
+	}
+	if val := os.Getenv("AUTH_WEBAUTHN_CHALLENGE_TTL"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil {
+			t.WebAuthnChallengeTTL = d
+		}
+	}
+	if val := os.Getenv("AUTH_REAUTHENTICATION_MAX_AGE"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil {
+			t.ReauthenticationMaxAge = d
+		}
+	}
+	if val := os.Getenv("AUTH_IMPERSONATION_TTL"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil {
+			t.ImpersonationTTL = d
+		}
+	}
+	if val := os.Getenv("AUTH_LOGIN_MAX_FAILURES"); val != "" {
+		if n, err := strconv.Atoi(val); err == nil {
+			t.LoginMaxFailures = n
+		}
+	}
+	if val := os.Getenv("AUTH_LOGIN_IP_MAX_FAILURES"); val != "" {
+		if n, err := strconv.Atoi(val); err == nil {
+			t.LoginIPMaxFailures = n
+		}
+	}
+	if val := os.Getenv("AUTH_LOGIN_FAILURE_WINDOW"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil {
+			t.LoginFailureWindow = d
+		}
+	}
+	if val := os.Getenv("AUTH_LOGIN_LOCKOUT_DURATION"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil {
+			t.LoginLockoutDuration = d
+		}
+	}
+	if val := os.Getenv("AUTH_LOGIN_BASE_DELAY"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil {
+			t.LoginBaseDelay = d
+		}
+	}
+	if val := os.Getenv("AUTH_ACCOUNT_UNLOCK_TTL"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil {
+			t.AccountUnlockTTL = d
+		}
+	}
+	if val := os.Getenv("AUTH_NEW_DEVICE_REVOKE_TTL"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil {
+			t.NewDeviceRevokeTTL = d
+		}
+	}
+	if val := os.Getenv("AUTH_REJECT_USER_AGENT_CHANGE"); val != "" {
+		if b, err := strconv.ParseBool(val); err == nil {
+			t.RejectUserAgentChange = b
+		}
+	}
+	if val := os.Getenv("AUTH_ARGON2_MEMORY"); val != "" {
+		if n, err := strconv.ParseUint(val, 10, 32); err == nil {
+			t.Argon2Memory = uint32(n)
+		}
+	}
+	if val := os.Getenv("AUTH_ARGON2_ITERATIONS"); val != "" {
+		if n, err := strconv.ParseUint(val, 10, 32); err == nil {
+			t.Argon2Iterations = uint32(n)
+		}
+	}
+	if val := os.Getenv("AUTH_ARGON2_PARALLELISM"); val != "" {
+		if n, err := strconv.ParseUint(val, 10, 8); err == nil {
+			t.Argon2Parallelism = uint8(n)
+		}
+	}
+	if val := os.Getenv("AUTH_BCRYPT_COST"); val != "" {
+		if n, err := strconv.Atoi(val); err == nil {
+			t.BcryptCost = n
+		}
+	}
+	if val := os.Getenv("AUTH_PASSWORD_MIN_LENGTH"); val != "" {
+		if n, err := strconv.Atoi(val); err == nil {
+			t.PasswordMinLength = n
+		}
+	}
+	if val := os.Getenv("AUTH_PASSWORD_MAX_LENGTH"); val != "" {
+		if n, err := strconv.Atoi(val); err == nil {
+			t.PasswordMaxLength = n
+		}
+	}
+	if val := os.Getenv("AUTH_PASSWORD_MIN_CHAR_CLASSES"); val != "" {
+		if n, err := strconv.Atoi(val); err == nil {
+			t.PasswordMinCharClasses = n
+		}
+	}
+	if val := os.Getenv("AUTH_PASSWORD_HISTORY_SIZE"); val != "" {
+		if n, err := strconv.Atoi(val); err == nil {
+			t.PasswordHistorySize = n
+		}
+	}
+	if val := os.Getenv("AUTH_PASSWORD_REJECT_PERSONAL_INFO"); val != "" {
+		if b, err := strconv.ParseBool(val); err == nil {
+			t.PasswordRejectPersonalInfo = b
+		}
+	}
+	if val := os.Getenv("AUTH_REQUIRE_VERIFIED_EMAIL"); val != "" {
+		if b, err := strconv.ParseBool(val); err == nil {
+			t.RequireVerifiedEmail = b
+		}
+	}
+	if val := os.Getenv("AUTH_ENUMERATION_PROTECTION"); val != "" {
+		if b, err := strconv.ParseBool(val); err == nil {
+			t.EnumerationProtection = b
+		}
+	}
+	if val := os.Getenv("AUTH_OIDC_PROVIDERS"); val != "" {
+		for _, name := range strings.Split(val, ",") {
+			t.OIDCProviders = append(t.OIDCProviders, parseOIDCProvider(strings.TrimSpace(name)))
+		}
+	}
 	return nil
 }
+
+// parseOIDCProvider reads the AUTH_OIDC_<NAME>_* variables of a provider.
+func parseOIDCProvider(name string) OIDCProvider {
+	prefix := "AUTH_OIDC_" + strings.ToUpper(name) + "_"
+	p := OIDCProvider{
+		Name:         name,
+		IssuerURL:    os.Getenv(prefix + "ISSUER"),
+		ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+		ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+		RedirectURL:  os.Getenv(prefix + "REDIRECT_URL"),
+		Scopes:       []string{"email", "profile"},
+	}
+	if val := os.Getenv(prefix + "SCOPES"); val != "" {
+		p.Scopes = strings.Split(val, ",")
+	}
+	return p
+}
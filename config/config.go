@@ -17,13 +17,15 @@ const (
 )
 
 type Config struct {
-	App         App
-	Postgres    Postgres
-	Cors        Cors
-	Auth        Auth
-	SMTP        SMTP
-	R2          R2
-	RabbitMQURL string
+	App          App
+	Postgres     Postgres
+	Cors         Cors
+	Auth         Auth
+	User         User
+	Organization Organization
+	SMTP         SMTP
+	R2           R2
+	RabbitMQURL  string
 }
 
 func (c Config) IsProduction() bool {
@@ -48,6 +50,12 @@ func LoadConfig() (*Config, error) {
 	if err := cfg.Auth.Parse(); err != nil {
 		return nil, err
 	}
+	if err := cfg.User.Parse(); err != nil {
+		return nil, err
+	}
+	if err := cfg.Organization.Parse(); err != nil {
+		return nil, err
+	}
 	if err := cfg.SMTP.Parse(); err != nil {
 		return nil, err
 	}
@@ -69,6 +77,38 @@ func (c *Config) Validate() error {
 	if c.App.Environment != EnvProduction && c.App.Environment != EnvDevelopment {
 		return fmt.Errorf("invalid APP_ENV, expecting %s or %s", EnvDevelopment, EnvProduction)
 	}
+	if c.Auth.PasswordAlgorithm != "argon2id" && c.Auth.PasswordAlgorithm != "bcrypt" {
+		return fmt.Errorf("invalid AUTH_PASSWORD_ALGORITHM, expecting argon2id or bcrypt")
+	}
+	for _, p := range c.Auth.OIDCProviders {
+		if p.IssuerURL == "" || p.ClientID == "" || p.RedirectURL == "" {
+			return fmt.Errorf("incomplete OIDC provider %q, expecting issuer, client id and redirect url", p.Name)
+		}
+	}
+	if c.Auth.WebAuthnRPID != "" && len(c.Auth.WebAuthnOrigins) == 0 {
+		return fmt.Errorf("missing AUTH_WEBAUTHN_ORIGINS, required when AUTH_WEBAUTHN_RP_ID is set")
+	}
+	if c.Auth.ReauthenticationMaxAge <= 0 {
+		return fmt.Errorf("invalid AUTH_REAUTHENTICATION_MAX_AGE, expecting a positive duration")
+	}
+	if c.Auth.TokenRevocationStore != "postgres" && c.Auth.TokenRevocationStore != "memory" {
+		return fmt.Errorf("invalid AUTH_TOKEN_REVOCATION_STORE, expecting postgres or memory")
+	}
+	switch c.Auth.CookieSameSite {
+	case "lax", "strict":
+	case "none":
+		if !c.IsProduction() {
+			log.Printf("warning: AUTH_COOKIE_SAME_SITE=none requires secure cookies, browsers only accept them over https\n")
+		}
+	default:
+		return fmt.Errorf("invalid AUTH_COOKIE_SAME_SITE, expecting lax, strict or none")
+	}
+	if c.User.DeletionPurgeMode != "delete" && c.User.DeletionPurgeMode != "anonymize" {
+		return fmt.Errorf("invalid USER_DELETION_PURGE_MODE, expecting delete or anonymize")
+	}
+	if c.Organization.InvitationTTL <= 0 {
+		return fmt.Errorf("invalid ORG_INVITATION_TTL, expecting a positive duration")
+	}
 	for _, origin := range c.Cors.Origins {
 		if _, err := url.ParseRequestURI(origin); err != nil {
 			log.Printf("warning: invalid CORS origin: %s\n", origin)
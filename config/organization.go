@@ -0,0 +1,37 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	defaultInvitationTTL = 7 * 24 * time.Hour
+	defaultTenantHeader  = "X-Organization"
+)
+
+type Organization struct {
+	InvitationTTL      time.Duration // How long an invitation can be accepted
+	InvitationEndpoint string        // Web app page answering an invitation, receives the token as query param
+	TenantHeader       string        // Request header carrying the ID or slug of the active organization
+	TenantBaseDomain   string        // Requests to <slug>.<base domain> resolve the organization from the subdomain
+}
+
+func (o *Organization) Parse() error {
+	o.InvitationTTL = defaultInvitationTTL
+	o.InvitationEndpoint = os.Getenv("ORG_INVITATION_ENDPOINT")
+	o.TenantBaseDomain = strings.ToLower(strings.TrimPrefix(os.Getenv("ORG_TENANT_BASE_DOMAIN"), "."))
+
+	o.TenantHeader = os.Getenv("ORG_TENANT_HEADER")
+	if o.TenantHeader == "" {
+		o.TenantHeader = defaultTenantHeader
+	}
+
+	if val := os.Getenv("ORG_INVITATION_TTL"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil {
+			o.InvitationTTL = d
+		}
+	}
+	return nil
+}
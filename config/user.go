@@ -0,0 +1,38 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+const (
+	defaultDeletionGracePeriod = 30 * 24 * time.Hour
+	defaultPurgeInterval       = time.Hour
+)
+
+type User struct {
+	DeletionGracePeriod time.Duration // Time a deleted account can still be recovered before it is purged
+	DeletionPurgeMode   string        // "delete" removes the row, "anonymize" strips personal data
+	PurgeInterval       time.Duration // How often the worker looks for accounts to purge
+}
+
+func (u *User) Parse() error {
+	u.DeletionGracePeriod = defaultDeletionGracePeriod
+	u.PurgeInterval = defaultPurgeInterval
+	u.DeletionPurgeMode = os.Getenv("USER_DELETION_PURGE_MODE")
+	if u.DeletionPurgeMode == "" {
+		u.DeletionPurgeMode = "anonymize"
+	}
+
+	if val := os.Getenv("USER_DELETION_GRACE_PERIOD"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil {
+			u.DeletionGracePeriod = d
+		}
+	}
+	if val := os.Getenv("USER_PURGE_INTERVAL"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil {
+			u.PurgeInterval = d
+		}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+// Package audit records security relevant authentication and account events, who did what to which account,
+// from where, so users and administrators can review them later.
+package audit
+
+import "context"
+
+// Request holds the details of the request an event originates from.
+type Request struct {
+	ActorID   string // ID of the authenticated user, the impersonator on impersonated requests
+	IPAddress string
+	UserAgent string
+	RequestID string
+}
+
+type requestCtxKey struct{}
+
+// WithRequest attaches the details of a request to ctx.
+func WithRequest(ctx context.Context, req Request) context.Context {
+	return context.WithValue(ctx, requestCtxKey{}, req)
+}
+
+// WithActor records the authenticated user of the request in ctx.
+func WithActor(ctx context.Context, actorID string) context.Context {
+	req := requestFromContext(ctx)
+	req.ActorID = actorID
+	return context.WithValue(ctx, requestCtxKey{}, req)
+}
+
+func requestFromContext(ctx context.Context) Request {
+	req, _ := ctx.Value(requestCtxKey{}).(Request)
+	return req
+}
@@ -0,0 +1,150 @@
+// Package audit records security relevant authentication and account events, who did what to which account,
+// from where, so users and administrators can review them later.
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PermissionAuditRead allows querying the audit events of every user.
+const PermissionAuditRead = "audit:read"
+
+// EventType names what happened, as "subject.action".
+type EventType string
+
+// Authentication events
+const (
+	EventRegistered             EventType = "account.registered"
+	EventRegistrationDuplicate  EventType = "account.registration_duplicate"
+	EventLoginSucceeded         EventType = "login.succeeded"
+	EventLoginFailed            EventType = "login.failed"
+	EventLoginNewDevice         EventType = "login.new_device"
+	EventMFAFailed              EventType = "login.mfa_failed"
+	EventLogout                 EventType = "logout"
+	EventSessionReused          EventType = "session.reuse_detected"
+	EventSessionRevoked         EventType = "session.revoked"
+	EventSessionAgentChanged    EventType = "session.user_agent_changed"
+	EventOtherSessionsRevoked   EventType = "session.others_revoked"
+	EventAccountLocked          EventType = "account.locked"
+	EventAccountUnlocked        EventType = "account.unlocked"
+	EventAccountDeleted         EventType = "account.deleted"
+	EventPasswordChanged        EventType = "password.changed"
+	EventPasswordResetRequested EventType = "password.reset_requested"
+	EventPasswordReset          EventType = "password.reset"
+	EventPasswordResetForced    EventType = "password.reset_forced"
+	EventEmailVerificationSent  EventType = "email.verification_sent"
+	EventEmailVerified          EventType = "email.verified"
+	EventEmailChangeRequested   EventType = "email.change_requested"
+	EventEmailChanged           EventType = "email.changed"
+	EventMagicLinkRequested     EventType = "magic_link.requested"
+	EventExternalIdentityLinked EventType = "external_identity.linked"
+	EventAccountClaimed         EventType = "account.claimed"
+	EventTOTPEnrolled           EventType = "mfa.totp_enrolled"
+	EventTOTPEnabled            EventType = "mfa.totp_enabled"
+	EventTOTPDisabled           EventType = "mfa.totp_disabled"
+	EventRecoveryCodesRenewed   EventType = "mfa.recovery_codes_regenerated"
+	EventPasskeyRegistered      EventType = "passkey.registered"
+	EventPasskeyDeleted         EventType = "passkey.deleted"
+	EventDeviceForgotten        EventType = "device.forgotten"
+	EventTokenCreated           EventType = "personal_access_token.created"
+	EventTokenRevoked           EventType = "personal_access_token.revoked"
+	EventRoleAssigned           EventType = "role.assigned"
+	EventRoleRevoked            EventType = "role.revoked"
+	EventReauthenticated        EventType = "reauthentication.succeeded"
+	EventReauthenticationFailed EventType = "reauthentication.failed"
+	EventImpersonationStarted   EventType = "impersonation.started"
+	EventImpersonationStopped   EventType = "impersonation.stopped"
+	EventImpersonationExpired   EventType = "impersonation.expired"
+)
+
+// Account events
+const (
+	EventProfileUpdated        EventType = "profile.updated"
+	EventProfilePictureChanged EventType = "profile.picture_changed"
+	EventAccountSuspended      EventType = "account.suspended"
+	EventAccountUnsuspended    EventType = "account.unsuspended"
+	EventAccountPurged         EventType = "account.purged"
+)
+
+// Organization events, the organization is recorded in the metadata
+const (
+	EventOrganizationCreated  EventType = "organization.created"
+	EventOrganizationSwitched EventType = "organization.switched"
+	EventMemberInvited        EventType = "organization.member_invited"
+	EventInvitationAccepted   EventType = "organization.invitation_accepted"
+	EventInvitationDeclined   EventType = "organization.invitation_declined"
+	EventInvitationRevoked    EventType = "organization.invitation_revoked"
+	EventMemberRoleChanged    EventType = "organization.member_role_changed"
+	EventMemberRemoved        EventType = "organization.member_removed"
+)
+
+// Metadata holds the event specific details, e.g. the session or role involved. Never put secrets in it.
+type Metadata map[string]any
+
+// Event is a single entry of the audit log.
+type Event struct {
+	ID   uuid.UUID `db:"id"   json:"id"`
+	Type EventType `db:"type" json:"type"`
+	// UserID is the account the event is about, null when it is unknown, e.g. a login to an unregistered email.
+	UserID uuid.NullUUID `db:"user_id" json:"user_id"`
+	// ActorID is the authenticated user who caused the event. It differs from UserID on administrative actions
+	// and is null for unauthenticated requests, such as logins, and background jobs.
+	ActorID   uuid.NullUUID `db:"actor_id"   json:"actor_id"`
+	IPAddress string        `db:"ip_address" json:"ip_address"`
+	UserAgent string        `db:"user_agent" json:"user_agent"`
+	RequestID string        `db:"request_id" json:"request_id"`
+	Metadata  Metadata      `db:"metadata"   json:"metadata"`
+	CreatedAt time.Time     `db:"created_at" json:"created_at"`
+}
+
+// NewEvent creates an event about the user, filling in the actor and client details the request carries in ctx.
+// An empty or malformed userID leaves the subject unknown.
+func NewEvent(ctx context.Context, eventType EventType, userID string, metadata Metadata) (*Event, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+
+	if metadata == nil {
+		metadata = Metadata{}
+	}
+
+	req := requestFromContext(ctx)
+	return &Event{
+		ID:        id,
+		Type:      eventType,
+		UserID:    parseNullUUID(userID),
+		ActorID:   parseNullUUID(req.ActorID),
+		IPAddress: req.IPAddress,
+		UserAgent: req.UserAgent,
+		RequestID: req.RequestID,
+		Metadata:  metadata,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// Record creates an event and writes it with w, inside the transaction of ctx if any so the event commits or
+// rolls back along with the change it records. A nil writer disables auditing.
+func Record(ctx context.Context, w Writer, eventType EventType, userID string, metadata Metadata) error {
+	if w == nil {
+		return nil
+	}
+
+	event, err := NewEvent(ctx, eventType, userID, metadata)
+	if err != nil {
+		return err
+	}
+
+	return w.Write(ctx, event)
+}
+
+func parseNullUUID(s string) uuid.NullUUID {
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return uuid.NullUUID{}
+	}
+	return uuid.NullUUID{UUID: id, Valid: true}
+}
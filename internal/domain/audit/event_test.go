@@ -0,0 +1,103 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type writerFunc func(ctx context.Context, event *Event) error
+
+func (f writerFunc) Write(ctx context.Context, event *Event) error {
+	return f(ctx, event)
+}
+
+func TestNewEvent(t *testing.T) {
+	userID := uuid.New()
+	actorID := uuid.New()
+
+	t.Run("WithRequest", func(t *testing.T) {
+		ctx := WithRequest(context.Background(), Request{
+			IPAddress: "203.0.113.7",
+			UserAgent: "Mozilla/5.0",
+			RequestID: "req-1",
+		})
+		ctx = WithActor(ctx, actorID.String())
+
+		event, err := NewEvent(ctx, EventPasswordChanged, userID.String(), Metadata{"key": "value"})
+		require.NoError(t, err)
+
+		assert.NotEqual(t, uuid.Nil, event.ID)
+		assert.Equal(t, EventPasswordChanged, event.Type)
+		assert.Equal(t, uuid.NullUUID{UUID: userID, Valid: true}, event.UserID)
+		assert.Equal(t, uuid.NullUUID{UUID: actorID, Valid: true}, event.ActorID)
+		assert.Equal(t, "203.0.113.7", event.IPAddress)
+		assert.Equal(t, "Mozilla/5.0", event.UserAgent)
+		assert.Equal(t, "req-1", event.RequestID)
+		assert.Equal(t, Metadata{"key": "value"}, event.Metadata)
+		assert.False(t, event.CreatedAt.IsZero())
+	})
+
+	t.Run("WithoutRequest", func(t *testing.T) {
+		event, err := NewEvent(context.Background(), EventLoginFailed, "", nil)
+		require.NoError(t, err)
+
+		assert.False(t, event.UserID.Valid)
+		assert.False(t, event.ActorID.Valid)
+		assert.Empty(t, event.IPAddress)
+		assert.NotNil(t, event.Metadata)
+	})
+
+	t.Run("ActorWithoutRequest", func(t *testing.T) {
+		ctx := WithActor(context.Background(), actorID.String())
+
+		event, err := NewEvent(ctx, EventAccountSuspended, userID.String(), nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, actorID, event.ActorID.UUID)
+		assert.Empty(t, event.RequestID)
+	})
+
+	t.Run("MalformedUserID", func(t *testing.T) {
+		event, err := NewEvent(context.Background(), EventLoginFailed, "not-a-uuid", nil)
+		require.NoError(t, err)
+
+		assert.False(t, event.UserID.Valid)
+	})
+}
+
+func TestRecord(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.NewString()
+
+	t.Run("NilWriter", func(t *testing.T) {
+		assert.NoError(t, Record(ctx, nil, EventLogout, userID, nil))
+	})
+
+	t.Run("Writes", func(t *testing.T) {
+		var written *Event
+		w := writerFunc(func(ctx context.Context, event *Event) error {
+			written = event
+			return nil
+		})
+
+		require.NoError(t, Record(ctx, w, EventLogout, userID, Metadata{"session_id": "s1"}))
+		require.NotNil(t, written)
+		assert.Equal(t, EventLogout, written.Type)
+		assert.Equal(t, userID, written.UserID.UUID.String())
+		assert.Equal(t, "s1", written.Metadata["session_id"])
+	})
+
+	t.Run("WriteError", func(t *testing.T) {
+		writeErr := errors.New("write failed")
+		w := writerFunc(func(ctx context.Context, event *Event) error {
+			return writeErr
+		})
+
+		assert.ErrorIs(t, Record(ctx, w, EventLogout, userID, nil), writeErr)
+	})
+}
@@ -0,0 +1,57 @@
+// Package audit records security relevant authentication and account events, who did what to which account,
+// from where, so users and administrators can review them later.
+package audit
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prawirdani/golang-restapi/internal/domain"
+)
+
+const listDateLayout = "2006-01-02"
+
+// ListEventsInput holds the raw query parameters of an audit event listing.
+type ListEventsInput struct {
+	UserID  string `json:"user_id"  validate:"omitempty,uuid"`
+	ActorID string `json:"actor_id" validate:"omitempty,uuid"`
+	Type    string `json:"type"     validate:"omitempty,max=64"`
+	From    string `json:"from"     validate:"omitempty,datetime=2006-01-02"`
+	To      string `json:"to"       validate:"omitempty,datetime=2006-01-02"`
+	Page    string `json:"page"     validate:"omitempty,number"`
+	PerPage string `json:"per_page" validate:"omitempty,number"`
+}
+
+// Filter converts the validated input into a [ListFilter].
+func (i ListEventsInput) Filter() ListFilter {
+	f := ListFilter{
+		UserID:  parseNullUUID(i.UserID),
+		ActorID: parseNullUUID(i.ActorID),
+		Type:    EventType(i.Type),
+	}
+
+	if t, err := time.Parse(listDateLayout, i.From); err == nil {
+		f.From = t
+	}
+	// Inclusive of the whole end day
+	if t, err := time.Parse(listDateLayout, i.To); err == nil {
+		f.To = t.AddDate(0, 0, 1)
+	}
+
+	f.Page, _ = strconv.Atoi(i.Page)
+	f.PerPage, _ = strconv.Atoi(i.PerPage)
+	f.PageRequest = f.Normalize()
+
+	return f
+}
+
+// ListFilter narrows down an audit event listing, zero values are ignored.
+type ListFilter struct {
+	UserID  uuid.NullUUID // Account the events are about
+	ActorID uuid.NullUUID // User who caused the events
+	Type    EventType
+	From    time.Time // Inclusive lower bound of the event time
+	To      time.Time // Exclusive upper bound of the event time
+	domain.PageRequest
+}
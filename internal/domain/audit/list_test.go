@@ -0,0 +1,40 @@
+package audit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListEventsInput_Filter(t *testing.T) {
+	userID := uuid.New()
+
+	f := ListEventsInput{
+		UserID:  userID.String(),
+		Type:    string(EventLoginFailed),
+		From:    "2026-01-01",
+		To:      "2026-01-31",
+		Page:    "2",
+		PerPage: "50",
+	}.Filter()
+
+	assert.Equal(t, uuid.NullUUID{UUID: userID, Valid: true}, f.UserID)
+	assert.False(t, f.ActorID.Valid)
+	assert.Equal(t, EventLoginFailed, f.Type)
+	assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), f.From)
+	assert.Equal(t, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), f.To)
+	assert.Equal(t, 2, f.Page)
+	assert.Equal(t, 50, f.PerPage)
+}
+
+func TestListEventsInput_FilterDefaults(t *testing.T) {
+	f := ListEventsInput{}.Filter()
+
+	assert.False(t, f.UserID.Valid)
+	assert.True(t, f.From.IsZero())
+	assert.True(t, f.To.IsZero())
+	assert.Equal(t, 1, f.Page)
+	assert.Positive(t, f.PerPage)
+}
@@ -0,0 +1,20 @@
+// Package audit records security relevant authentication and account events, who did what to which account,
+// from where, so users and administrators can review them later.
+package audit
+
+import "context"
+
+// Writer appends events to the audit log.
+type Writer interface {
+	// Write stores the event, inside the transaction of ctx if any.
+	Write(ctx context.Context, event *Event) error
+}
+
+// Repository defines the persistence operations of the audit log.
+type Repository interface {
+	Writer
+
+	// List retrieves a page of events matching the filter, newest first,
+	// along with the total number of matching events.
+	List(ctx context.Context, filter ListFilter) ([]*Event, int, error)
+}
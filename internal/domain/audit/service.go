@@ -0,0 +1,47 @@
+// Package audit records security relevant authentication and account events, who did what to which account,
+// from where, so users and administrators can review them later.
+package audit
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/prawirdani/golang-restapi/internal/domain"
+)
+
+type Service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// ListUserEvents returns a page of the events about the user, for the user to review their account activity.
+func (s *Service) ListUserEvents(
+	ctx context.Context,
+	userID string,
+	page domain.PageRequest,
+) (*domain.Page[*Event], error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.ListEvents(ctx, ListFilter{
+		UserID:      uuid.NullUUID{UUID: id, Valid: true},
+		PageRequest: page,
+	})
+}
+
+// ListEvents returns a page of the events matching the filter, across every user.
+func (s *Service) ListEvents(ctx context.Context, filter ListFilter) (*domain.Page[*Event], error) {
+	filter.PageRequest = filter.Normalize()
+
+	events, total, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.NewPage(events, total, filter.PageRequest), nil
+}
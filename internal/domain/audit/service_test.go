@@ -0,0 +1,72 @@
+package audit_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/prawirdani/golang-restapi/internal/domain"
+	"github.com/prawirdani/golang-restapi/internal/domain/audit"
+	"github.com/prawirdani/golang-restapi/internal/testing/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_ListUserEvents(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := mocks.NewAuditRepository(t)
+		service := audit.NewService(mockRepo)
+
+		events := []*audit.Event{{ID: uuid.New(), Type: audit.EventLoginSucceeded}}
+		mockRepo.EXPECT().List(ctx, audit.ListFilter{
+			UserID:      uuid.NullUUID{UUID: userID, Valid: true},
+			PageRequest: domain.PageRequest{Page: 2, PerPage: 10},
+		}).Return(events, 11, nil)
+
+		page, err := service.ListUserEvents(ctx, userID.String(), domain.PageRequest{Page: 2, PerPage: 10})
+		require.NoError(t, err)
+		assert.Equal(t, events, page.Items)
+		assert.Equal(t, 11, page.Total)
+	})
+
+	t.Run("InvalidUserID", func(t *testing.T) {
+		service := audit.NewService(mocks.NewAuditRepository(t))
+
+		page, err := service.ListUserEvents(ctx, "not-a-uuid", domain.PageRequest{})
+		assert.Error(t, err)
+		assert.Nil(t, page)
+	})
+}
+
+func TestService_ListEvents(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := mocks.NewAuditRepository(t)
+		service := audit.NewService(mockRepo)
+
+		filter := audit.ListFilter{Type: audit.EventLoginFailed, PageRequest: domain.PageRequest{Page: 1, PerPage: 20}}
+		mockRepo.EXPECT().List(ctx, filter).Return([]*audit.Event{}, 0, nil)
+
+		page, err := service.ListEvents(ctx, filter)
+		require.NoError(t, err)
+		assert.Empty(t, page.Items)
+	})
+
+	t.Run("RepositoryError", func(t *testing.T) {
+		mockRepo := mocks.NewAuditRepository(t)
+		service := audit.NewService(mockRepo)
+
+		repoErr := errors.New("db down")
+		mockRepo.EXPECT().List(ctx, audit.ListFilter{PageRequest: domain.PageRequest{Page: 1, PerPage: 20}}).
+			Return(nil, 0, repoErr)
+
+		page, err := service.ListEvents(ctx, audit.ListFilter{})
+		assert.ErrorIs(t, err, repoErr)
+		assert.Nil(t, page)
+	})
+}
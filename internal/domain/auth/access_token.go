@@ -9,50 +9,125 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/prawirdani/golang-restapi/internal/domain"
 )
 
 var (
 	ErrAccessTokenExpired        = domain.ErrUnauthorized("Access token expired")
+	ErrAccessTokenRevoked        = domain.ErrUnauthorized("Access token revoked")
+	ErrAccessTokenDelegated      = domain.ErrUnauthorized("Access token is not valid for this API")
 	ErrAccessTokenClaimsNotFound = errors.New("access token claims not found in context")
 )
 
+// Authentication method references recorded in the amr claim, values follow RFC 8176.
+const (
+	AMRPassword    = "pwd" // Password-based authentication
+	AMROneTimeCode = "otp" // One-time code, either a TOTP code or a recovery code
+	AMRMultiFactor = "mfa" // Multiple-factor authentication
+)
+
+// OAuthAudience is the aud claim of tokens issued by the OAuth authorization server. They are meant for the
+// resource servers of the clients, which check them through introspection, and aren't accepted by this API.
+const OAuthAudience = "oauth"
+
 type AccessTokenClaims struct {
 	UserID string `json:"uid"`
+	// SessionID is the family ID of the session the token was issued from.
+	SessionID string   `json:"sid,omitempty"`
+	AMR       []string `json:"amr,omitempty"`
+	// Roles and Permissions are resolved when the token is issued, changes apply on the next refresh.
+	Roles       []string `json:"roles,omitempty"`
+	Permissions []string `json:"perms,omitempty"`
+	// ClientID and Scope are set on tokens issued by the OAuth authorization server, Scope is space separated.
+	ClientID string `json:"client_id,omitempty"`
+	Scope    string `json:"scope,omitempty"`
+	// OrgID is the active organization the user switched to, the default tenant of the request.
+	OrgID string `json:"org_id,omitempty"`
+	// Actor is set on impersonation tokens, UserID is then the impersonated user.
+	Actor *Actor `json:"act,omitempty"`
+	// AuthTime is when the user last proved their credentials within the session, at login or when
+	// re-authenticating. Unset on tokens not issued from a session.
+	AuthTime *jwt.NumericDate `json:"auth_time,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// SignAccessToken generates a new JWT for access token
+// Actor is the party acting on behalf of the token subject, the act claim of RFC 8693.
+type Actor struct {
+	UserID string `json:"sub"`
+}
+
+// Impersonated reports whether the token was issued to an administrator acting as the user.
+func (c *AccessTokenClaims) Impersonated() bool {
+	return c.Actor != nil
+}
+
+// ActorID returns the ID of the user actually making the request, the impersonator on impersonation tokens.
+func (c *AccessTokenClaims) ActorID() string {
+	if c.Actor != nil {
+		return c.Actor.UserID
+	}
+	return c.UserID
+}
+
+// Delegated reports whether the token was issued by the OAuth authorization server to a client, rather than to
+// the user through a first-party login.
+func (c *AccessTokenClaims) Delegated() bool {
+	return c.ClientID != "" || slices.Contains(c.Audience, OAuthAudience)
+}
+
+// PersonalAccessToken reports whether the request was authenticated with a personal access token.
+func (c *AccessTokenClaims) PersonalAccessToken() bool {
+	return slices.Contains(c.AMR, AMRPersonalAccessToken)
+}
+
+// MultiFactor reports whether the token was issued after a multi-factor authentication.
+func (c *AccessTokenClaims) MultiFactor() bool {
+	return slices.Contains(c.AMR, AMRMultiFactor)
+}
+
+// AuthenticatedWithin reports whether the user proved their credentials within maxAge, tokens without an auth_time
+// never do.
+func (c *AccessTokenClaims) AuthenticatedWithin(maxAge time.Duration) bool {
+	return c.AuthTime != nil && time.Since(c.AuthTime.Time) <= maxAge
+}
+
+// HasPermission reports whether the token grants the permission.
+func (c *AccessTokenClaims) HasPermission(permission string) bool {
+	return PermissionGranted(c.Permissions, permission)
+}
+
+// SignAccessToken generates a new JWT for access token, signed with the active key of the key set.
+// Every token gets a unique jti so it can be revoked on its own, generated unless the caller set one. The aud
+// set by the caller is kept too.
 func SignAccessToken(
-	secretKey string,
+	keys *KeySet,
 	claims AccessTokenClaims,
 	ttl time.Duration,
 ) (string, error) {
+	id := claims.ID
+	if id == "" {
+		id = uuid.NewString()
+	}
+
 	now := time.Now()
 	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ID:        id,
+		Audience:  claims.Audience,
 		IssuedAt:  jwt.NewNumericDate(now),
 		ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(secretKey))
-}
-
-// VerifyAccessToken parses and validates the token, returning the claims if valid.
-func VerifyAccessToken(secretKey, tokenStr string) (*AccessTokenClaims, error) {
-	token, err := jwt.ParseWithClaims(
-		tokenStr,
-		&AccessTokenClaims{},
-		func(t *jwt.Token) (any, error) {
-			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrSignatureInvalid
-			}
-			return []byte(secretKey), nil
-		},
-	)
+	return keys.Sign(claims)
+}
+
+// VerifyAccessToken parses and validates the token against the key set, returning the claims if valid.
+func VerifyAccessToken(keys *KeySet, tokenStr string) (*AccessTokenClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenStr, &AccessTokenClaims{}, keys.Keyfunc)
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
 			return nil, ErrAccessTokenExpired
@@ -72,6 +147,58 @@ func VerifyAccessToken(secretKey, tokenStr string) (*AccessTokenClaims, error) {
 	return claims, nil
 }
 
+// VerifyExpiredAccessToken verifies the signature of an access token whether or not it expired, to tell what an
+// expired token was issued for. The claims must never be used to authenticate a request.
+func VerifyExpiredAccessToken(keys *KeySet, tokenStr string) (*AccessTokenClaims, error) {
+	claims := &AccessTokenClaims{}
+	if _, err := jwt.ParseWithClaims(tokenStr, claims, keys.Keyfunc, jwt.WithoutClaimsValidation()); err != nil {
+		return nil, fmt.Errorf("parsing token: %w", err)
+	}
+
+	return claims, nil
+}
+
+// CheckFirstPartyAccessToken returns [ErrAccessTokenDelegated] if the token wasn't issued to a user of this API,
+// either delegated to an OAuth client or carrying no user at all.
+func CheckFirstPartyAccessToken(claims *AccessTokenClaims) error {
+	if claims.Delegated() || claims.UserID == "" {
+		return ErrAccessTokenDelegated
+	}
+	return nil
+}
+
+// CheckAccessTokenRevoked returns [ErrAccessTokenRevoked] if the token, or the session it was issued from,
+// has been revoked before it expired. Without a revocation store nothing is ever revoked.
+func CheckAccessTokenRevoked(
+	ctx context.Context,
+	revocations TokenRevocationStore,
+	claims *AccessTokenClaims,
+) error {
+	if revocations == nil {
+		return nil
+	}
+
+	ids := make([]string, 0, 2)
+	if claims.ID != "" {
+		ids = append(ids, claims.ID)
+	}
+	if claims.SessionID != "" {
+		ids = append(ids, claims.SessionID)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	revoked, err := revocations.IsRevoked(ctx, ids...)
+	if err != nil {
+		return err
+	}
+	if revoked {
+		return ErrAccessTokenRevoked
+	}
+	return nil
+}
+
 type accessTokenCtxKey struct{}
 
 var atCtx accessTokenCtxKey
@@ -89,3 +216,17 @@ func GetAccessTokenCtx(ctx context.Context) (*AccessTokenClaims, error) {
 	}
 	return claims, nil
 }
+
+type actorCtxKey struct{}
+
+// SetActorCtx sets the actor of an impersonated request to the context.
+func SetActorCtx(ctx context.Context, actor *Actor) context.Context {
+	return context.WithValue(ctx, actorCtxKey{}, actor)
+}
+
+// GetActorCtx retrieves the actor of an impersonated request from the context, false if the request isn't
+// impersonated.
+func GetActorCtx(ctx context.Context) (*Actor, bool) {
+	actor, ok := ctx.Value(actorCtxKey{}).(*Actor)
+	return actor, ok && actor != nil
+}
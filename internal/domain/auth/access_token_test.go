@@ -2,49 +2,80 @@ package auth
 
 import (
 	"context"
+	"slices"
 	"testing"
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-const secret = "secret"
+var testKeys = NewHMACKeySet("secret")
 
 var mockClaims = AccessTokenClaims{
 	UserID: "user-id",
 }
 
 func TestSignAccessToken(t *testing.T) {
-	token, err := SignAccessToken(secret, mockClaims, time.Minute*5)
+	token, err := SignAccessToken(testKeys, mockClaims, time.Minute*5)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, token)
 }
 
 func TestVerifyAccessToken(t *testing.T) {
-	token, err := SignAccessToken(secret, mockClaims, time.Minute*5)
+	token, err := SignAccessToken(testKeys, mockClaims, time.Minute*5)
 	require.NoError(t, err)
 
-	claims, err := VerifyAccessToken(secret, token)
+	claims, err := VerifyAccessToken(testKeys, token)
 	require.NoError(t, err)
 	assert.Equal(t, mockClaims.UserID, claims.UserID)
+	assert.NotEmpty(t, claims.ID)
 
 	t.Run("Expired", func(t *testing.T) {
-		token, err := SignAccessToken(secret, mockClaims, -time.Minute*1)
+		token, err := SignAccessToken(testKeys, mockClaims, -time.Minute*1)
 		require.NoError(t, err)
 
-		_, err = VerifyAccessToken(secret, token)
+		_, err = VerifyAccessToken(testKeys, token)
 		assert.Error(t, err)
 		assert.Equal(t, ErrAccessTokenExpired, err)
 	})
 
 	t.Run("Invalid", func(t *testing.T) {
-		claims, err := VerifyAccessToken(secret, "invalid-token-string")
+		claims, err := VerifyAccessToken(testKeys, "invalid-token-string")
 		assert.Error(t, err)
 		assert.Nil(t, claims)
 	})
 }
 
+// revocationList is a [TokenRevocationStore] holding revoked IDs without expiration.
+type revocationList []string
+
+func (l revocationList) Revoke(context.Context, string, time.Time) error { return nil }
+
+func (l revocationList) IsRevoked(_ context.Context, ids ...string) (bool, error) {
+	for _, id := range ids {
+		if slices.Contains(l, id) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestCheckAccessTokenRevoked(t *testing.T) {
+	ctx := context.Background()
+	claims := &AccessTokenClaims{
+		UserID:           "user-id",
+		SessionID:        "session-family-id",
+		RegisteredClaims: jwt.RegisteredClaims{ID: "token-id"},
+	}
+
+	assert.NoError(t, CheckAccessTokenRevoked(ctx, revocationList{"other-token-id"}, claims))
+	assert.Equal(t, ErrAccessTokenRevoked, CheckAccessTokenRevoked(ctx, revocationList{"token-id"}, claims))
+	assert.Equal(t, ErrAccessTokenRevoked, CheckAccessTokenRevoked(ctx, revocationList{"session-family-id"}, claims))
+	assert.NoError(t, CheckAccessTokenRevoked(ctx, nil, claims))
+}
+
 func TestAccessTokenContext(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		ctx := SetAccessTokenCtx(context.Background(), &mockClaims)
@@ -60,3 +91,56 @@ func TestAccessTokenContext(t *testing.T) {
 		assert.Equal(t, ErrAccessTokenClaimsNotFound, err)
 	})
 }
+
+func TestImpersonationClaims(t *testing.T) {
+	claims := AccessTokenClaims{
+		UserID:           "user-id",
+		Actor:            &Actor{UserID: "admin-id"},
+		RegisteredClaims: jwt.RegisteredClaims{ID: "token-id"},
+	}
+
+	token, err := SignAccessToken(testKeys, claims, time.Minute*5)
+	require.NoError(t, err)
+
+	verified, err := VerifyAccessToken(testKeys, token)
+	require.NoError(t, err)
+	assert.True(t, verified.Impersonated())
+	assert.Equal(t, "user-id", verified.UserID)
+	assert.Equal(t, "admin-id", verified.ActorID())
+	assert.Equal(t, "token-id", verified.ID)
+
+	assert.False(t, mockClaims.Impersonated())
+	assert.Equal(t, mockClaims.UserID, mockClaims.ActorID())
+}
+
+func TestActorContext(t *testing.T) {
+	_, ok := GetActorCtx(context.Background())
+	assert.False(t, ok)
+
+	ctx := SetActorCtx(context.Background(), &Actor{UserID: "admin-id"})
+	actor, ok := GetActorCtx(ctx)
+	require.True(t, ok)
+	assert.Equal(t, "admin-id", actor.UserID)
+}
+
+func TestAccessTokenClaims_AuthenticatedWithin(t *testing.T) {
+	claims := AccessTokenClaims{UserID: "user-id"}
+	assert.False(t, claims.AuthenticatedWithin(time.Hour))
+
+	claims.AuthTime = jwt.NewNumericDate(time.Now().Add(-5 * time.Minute))
+	assert.True(t, claims.AuthenticatedWithin(10*time.Minute))
+	assert.False(t, claims.AuthenticatedWithin(time.Minute))
+}
+
+func TestCheckFirstPartyAccessToken(t *testing.T) {
+	assert.NoError(t, CheckFirstPartyAccessToken(&AccessTokenClaims{UserID: "user-id", SessionID: "session-id"}))
+
+	oauth := &AccessTokenClaims{UserID: "user-id"}
+	oauth.Audience = jwt.ClaimStrings{OAuthAudience}
+	assert.ErrorIs(t, CheckFirstPartyAccessToken(oauth), ErrAccessTokenDelegated)
+
+	client := &AccessTokenClaims{ClientID: "client-id", Permissions: []string{PermissionRolesWrite}}
+	assert.ErrorIs(t, CheckFirstPartyAccessToken(client), ErrAccessTokenDelegated)
+
+	assert.ErrorIs(t, CheckFirstPartyAccessToken(&AccessTokenClaims{}), ErrAccessTokenDelegated)
+}
@@ -0,0 +1,61 @@
+// Package auth provides authentication and authorization functionality.
+// This package handles user authentication through sessions, access tokens, and
+// password management including secure hashing and password reset flows. It manages
+// the complete authentication lifecycle from login through logout, including token
+// generation, validation, and session management.
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prawirdani/golang-restapi/internal/domain"
+	"github.com/prawirdani/golang-restapi/pkg/nullable"
+)
+
+var (
+	// ErrAccountUnlockTokenInvalid is returned when the unlock token is invalid or expired.
+	ErrAccountUnlockTokenInvalid = domain.ErrForbidden("The unlock token is invalid or expired")
+
+	// ErrAccountUnlockTokenNotFound is returned when no matching unlock token exists.
+	ErrAccountUnlockTokenNotFound = domain.ErrNotFound("Account unlock token not found")
+)
+
+// AccountUnlockToken represents a one-time token mailed to the owner of a locked account to lift the lockout.
+type AccountUnlockToken struct {
+	UserID    uuid.UUID                    `db:"user_id"    json:"user_id"`
+	Value     string                       `db:"value"      json:"value"`
+	ExpiresAt time.Time                    `db:"expires_at" json:"expires_at"`
+	UsedAt    nullable.Nullable[time.Time] `db:"used_at"    json:"used_at"`
+}
+
+// NewAccountUnlockToken creates a new token for the given user with a specified expiration.
+func NewAccountUnlockToken(userID uuid.UUID, ttl time.Duration) (*AccountUnlockToken, error) {
+	bs := make([]byte, 32)
+	if _, err := rand.Read(bs); err != nil {
+		return nil, err
+	}
+
+	return &AccountUnlockToken{
+		UserID:    userID,
+		Value:     hex.EncodeToString(bs),
+		ExpiresAt: time.Now().Add(ttl),
+	}, nil
+}
+
+// Expired reports whether the token has passed its expiration time.
+func (t AccountUnlockToken) Expired() bool {
+	return t.ExpiresAt.Before(time.Now())
+}
+
+// Used reports whether the token has already been used.
+func (t AccountUnlockToken) Used() bool {
+	return t.UsedAt.NotNull()
+}
+
+// Revoke marks the token as used immediately.
+func (t *AccountUnlockToken) Revoke() {
+	t.UsedAt = nullable.New(time.Now(), false)
+}
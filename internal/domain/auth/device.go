@@ -0,0 +1,101 @@
+// Package auth provides authentication and authorization functionality.
+// This package handles user authentication through sessions, access tokens, and
+// password management including secure hashing and password reset flows. It manages
+// the complete authentication lifecycle from login through logout, including token
+// generation, validation, and session management.
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prawirdani/golang-restapi/internal/domain"
+)
+
+var (
+	ErrKnownDeviceNotFound = domain.ErrNotFound("Device not found")
+	ErrKnownDeviceEmptyUID = errors.New("user_id must not be empty")
+
+	// ErrSessionUserAgentChanged is returned when refreshing a session from another user agent than the one it was
+	// created with, while such refreshes are rejected.
+	ErrSessionUserAgentChanged = domain.ErrUnauthorized("Session was used from another device, please login again")
+)
+
+// userAgentVersions matches the version numbers of a User-Agent header, which change on every browser update.
+var userAgentVersions = regexp.MustCompile(`[0-9]+([._][0-9]+)*`)
+
+// KnownDevice is a device the user has logged in from before. Logins from a device that isn't known yet trigger a
+// security notification, unless it's the user's first device.
+type KnownDevice struct {
+	ID          uuid.UUID `db:"id"           json:"id"`
+	UserID      uuid.UUID `db:"user_id"      json:"-"`
+	Fingerprint string    `db:"fingerprint"  json:"-"`
+	UserAgent   string    `db:"user_agent"   json:"user_agent"` // As sent on the latest login from the device
+	IPAddress   string    `db:"ip_address"   json:"ip_address"` // Client IP of the latest login from the device
+	CreatedAt   time.Time `db:"created_at"   json:"created_at"`
+	LastSeenAt  time.Time `db:"last_seen_at" json:"last_seen_at"`
+}
+
+// NewKnownDevice creates a known device of the user from the client of a login.
+func NewKnownDevice(userID uuid.UUID, userAgent, ip string) (*KnownDevice, error) {
+	if userID == uuid.Nil {
+		return nil, ErrKnownDeviceEmptyUID
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &KnownDevice{
+		ID:          id,
+		UserID:      userID,
+		Fingerprint: DeviceFingerprint(userAgent, ip),
+		UserAgent:   userAgent,
+		IPAddress:   ip,
+		CreatedAt:   now,
+		LastSeenAt:  now,
+	}, nil
+}
+
+// Seen records another login from the device.
+func (d *KnownDevice) Seen(userAgent, ip string) {
+	d.UserAgent = userAgent
+	d.IPAddress = ip
+	d.LastSeenAt = time.Now()
+}
+
+// DeviceFingerprint identifies the device of a client by its User-Agent header and the network of its IP address.
+// Version numbers are left out of the user agent and only the /24 (IPv4) or /48 (IPv6) prefix of the address is
+// used, so browser updates and address changes within the same network keep the device recognized.
+func DeviceFingerprint(userAgent, ip string) string {
+	sum := sha256.Sum256([]byte(NormalizeUserAgent(userAgent) + "|" + ipNetwork(ip)))
+	return hex.EncodeToString(sum[:])
+}
+
+// NormalizeUserAgent strips the version numbers off a User-Agent header, leaving the browser, OS and device it
+// names, so it can be compared across browser updates.
+func NormalizeUserAgent(userAgent string) string {
+	stripped := userAgentVersions.ReplaceAllString(strings.ToLower(userAgent), "")
+	return strings.Join(strings.Fields(stripped), " ")
+}
+
+// ipNetwork returns the network prefix of the IP address, or the address as is when it can't be parsed.
+func ipNetwork(ip string) string {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return ip
+	}
+
+	if v4 := parsed.To4(); v4 != nil {
+		return v4.Mask(net.CIDRMask(24, 32)).String()
+	}
+	return parsed.Mask(net.CIDRMask(48, 128)).String()
+}
@@ -0,0 +1,131 @@
+// Package auth provides authentication and authorization functionality.
+// This package handles user authentication through sessions, access tokens, and
+// password management including secure hashing and password reset flows. It manages
+// the complete authentication lifecycle from login through logout, including token
+// generation, validation, and session management.
+package auth
+
+import (
+	"context"
+
+	"github.com/prawirdani/golang-restapi/internal/domain/audit"
+	"github.com/prawirdani/golang-restapi/internal/domain/user"
+	"github.com/prawirdani/golang-restapi/pkg/log"
+)
+
+// ListKnownDevices returns the devices the user has logged in from, most recently seen first.
+func (s *Service) ListKnownDevices(ctx context.Context, userID string) ([]*KnownDevice, error) {
+	return s.authRepo.ListKnownDevicesByUser(ctx, userID)
+}
+
+// ForgetKnownDevice removes a device of the user, the next login from it is announced as a new device again.
+func (s *Service) ForgetKnownDevice(ctx context.Context, userID, deviceID string) error {
+	if err := s.authRepo.DeleteKnownDevice(ctx, userID, deviceID); err != nil {
+		return err
+	}
+
+	return s.recordEvent(ctx, audit.EventDeviceForgotten, userID, audit.Metadata{"device_id": deviceID})
+}
+
+// RevokeUnrecognizedSession revokes the session of a login from a new device using the token of the "this wasn't
+// me" link in the notification email. The device is forgotten too, so another login from it is announced again.
+func (s *Service) RevokeUnrecognizedSession(ctx context.Context, inp RevokeUnrecognizedSessionInput) error {
+	return s.transactor.Transact(ctx, func(ctx context.Context) error {
+		token, err := s.authRepo.GetSessionRevokeToken(ctx, inp.Token)
+		if err != nil {
+			return err
+		}
+
+		if token.Expired() || token.Used() {
+			return ErrSessionRevokeTokenInvalid
+		}
+
+		token.Revoke()
+		if err := s.authRepo.UpdateSessionRevokeToken(ctx, token); err != nil {
+			return err
+		}
+
+		if err := s.revokeSessionFamily(ctx, token.FamilyID.String()); err != nil {
+			return err
+		}
+
+		err = s.authRepo.DeleteKnownDevice(ctx, token.UserID.String(), token.DeviceID.String())
+		if err != nil && err != ErrKnownDeviceNotFound {
+			return err
+		}
+
+		return s.recordEvent(ctx, audit.EventSessionRevoked, token.UserID.String(), audit.Metadata{
+			"session_id": token.FamilyID.String(),
+			"reason":     "unrecognized_device",
+		})
+	})
+}
+
+// recognizeDevice records the device of a new session among the user's known devices. A login from a device that
+// isn't known yet is announced to the user by email, unless it's the user's first device.
+func (s *Service) recognizeDevice(ctx context.Context, usr *user.User, sess *Session, ip string) error {
+	devices, err := s.authRepo.ListKnownDevicesByUser(ctx, usr.ID.String())
+	if err != nil {
+		return err
+	}
+
+	fingerprint := DeviceFingerprint(sess.UserAgent, ip)
+	for _, device := range devices {
+		if device.Fingerprint == fingerprint {
+			device.Seen(sess.UserAgent, ip)
+			return s.authRepo.UpdateKnownDevice(ctx, device)
+		}
+	}
+
+	device, err := NewKnownDevice(usr.ID, sess.UserAgent, ip)
+	if err != nil {
+		log.ErrorCtx(ctx, "Failed to create known device", err)
+		return err
+	}
+
+	if err := s.authRepo.StoreKnownDevice(ctx, device); err != nil {
+		return err
+	}
+
+	if len(devices) == 0 {
+		return nil
+	}
+
+	if err := s.recordEvent(ctx, audit.EventLoginNewDevice, usr.ID.String(), audit.Metadata{
+		"session_id": sess.FamilyID.String(),
+		"device_id":  device.ID.String(),
+	}); err != nil {
+		return err
+	}
+
+	return s.sendNewDeviceLoginEmail(ctx, usr, sess, device)
+}
+
+func (s *Service) sendNewDeviceLoginEmail(
+	ctx context.Context,
+	usr *user.User,
+	sess *Session,
+	device *KnownDevice,
+) error {
+	token, err := NewSessionRevokeToken(sess, device, s.cfg.NewDeviceRevokeTTL)
+	if err != nil {
+		log.ErrorCtx(ctx, "Failed to create session revoke token", err)
+		return err
+	}
+
+	if err := s.authRepo.StoreSessionRevokeToken(ctx, token); err != nil {
+		return err
+	}
+
+	msg := NewDeviceLoginEmailMessage{
+		To:        usr.Email,
+		Name:      usr.Name,
+		UserAgent: device.UserAgent,
+		IPAddress: device.IPAddress,
+		LoginAt:   device.CreatedAt,
+		RevokeURL: s.cfg.NewDeviceRevokeEndpoint + "?token=" + token.Value,
+		Expiry:    s.cfg.NewDeviceRevokeTTL,
+	}
+
+	return s.publisher.SendNewDeviceLoginEmail(ctx, msg)
+}
@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	chromeMacUA  = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36"
+	chromeMacUA2 = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/125.0.6422.60 Safari/537.36"
+	firefoxUA    = "Mozilla/5.0 (X11; Linux x86_64; rv:126.0) Gecko/20100101 Firefox/126.0"
+)
+
+func TestNormalizeUserAgent(t *testing.T) {
+	assert.Equal(t, NormalizeUserAgent(chromeMacUA), NormalizeUserAgent(chromeMacUA2))
+	assert.NotEqual(t, NormalizeUserAgent(chromeMacUA), NormalizeUserAgent(firefoxUA))
+	assert.Equal(t, "curl/", NormalizeUserAgent("  curl/8.5.0 "))
+}
+
+func TestDeviceFingerprint(t *testing.T) {
+	base := DeviceFingerprint(chromeMacUA, "203.0.113.10")
+
+	t.Run("BrowserUpdateSameNetwork", func(t *testing.T) {
+		assert.Equal(t, base, DeviceFingerprint(chromeMacUA2, "203.0.113.200"))
+	})
+
+	t.Run("OtherNetwork", func(t *testing.T) {
+		assert.NotEqual(t, base, DeviceFingerprint(chromeMacUA, "198.51.100.10"))
+	})
+
+	t.Run("OtherBrowser", func(t *testing.T) {
+		assert.NotEqual(t, base, DeviceFingerprint(firefoxUA, "203.0.113.10"))
+	})
+
+	t.Run("IPv6Prefix", func(t *testing.T) {
+		assert.Equal(t,
+			DeviceFingerprint(firefoxUA, "2001:db8:1234:1::1"),
+			DeviceFingerprint(firefoxUA, "2001:db8:1234:ffff::2"),
+		)
+		assert.NotEqual(t,
+			DeviceFingerprint(firefoxUA, "2001:db8:1234::1"),
+			DeviceFingerprint(firefoxUA, "2001:db8:1235::1"),
+		)
+	})
+}
+
+func TestNewKnownDevice(t *testing.T) {
+	userID := uuid.New()
+
+	device, err := NewKnownDevice(userID, chromeMacUA, "203.0.113.10")
+	require.NoError(t, err)
+
+	assert.NotEqual(t, uuid.Nil, device.ID)
+	assert.Equal(t, userID, device.UserID)
+	assert.Equal(t, DeviceFingerprint(chromeMacUA, "203.0.113.10"), device.Fingerprint)
+	assert.Equal(t, device.CreatedAt, device.LastSeenAt)
+
+	_, err = NewKnownDevice(uuid.Nil, chromeMacUA, "203.0.113.10")
+	assert.ErrorIs(t, err, ErrKnownDeviceEmptyUID)
+}
+
+func TestKnownDevice_Seen(t *testing.T) {
+	device, err := NewKnownDevice(uuid.New(), chromeMacUA, "203.0.113.10")
+	require.NoError(t, err)
+	device.LastSeenAt = time.Now().Add(-time.Hour)
+
+	device.Seen(chromeMacUA2, "203.0.113.20")
+
+	assert.Equal(t, chromeMacUA2, device.UserAgent)
+	assert.Equal(t, "203.0.113.20", device.IPAddress)
+	assert.WithinDuration(t, time.Now(), device.LastSeenAt, time.Second)
+}
+
+func TestSessionRevokeToken(t *testing.T) {
+	sess, err := NewSession(uuid.New(), chromeMacUA, time.Hour)
+	require.NoError(t, err)
+	device, err := NewKnownDevice(sess.UserID, chromeMacUA, "203.0.113.10")
+	require.NoError(t, err)
+
+	token, err := NewSessionRevokeToken(sess, device, time.Hour)
+	require.NoError(t, err)
+
+	assert.Equal(t, sess.FamilyID, token.FamilyID)
+	assert.Equal(t, device.ID, token.DeviceID)
+	assert.Len(t, token.Value, 64)
+	assert.False(t, token.Expired())
+	assert.False(t, token.Used())
+
+	token.Revoke()
+	assert.True(t, token.Used())
+}
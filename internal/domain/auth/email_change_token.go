@@ -0,0 +1,69 @@
+// Package auth provides authentication and authorization functionality.
+// This package handles user authentication through sessions, access tokens, and
+// password management including secure hashing and password reset flows. It manages
+// the complete authentication lifecycle from login through logout, including token
+// generation, validation, and session management.
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prawirdani/golang-restapi/internal/domain"
+	"github.com/prawirdani/golang-restapi/pkg/nullable"
+)
+
+var (
+	// ErrEmailChangeTokenInvalid is returned when the email change token is invalid or expired.
+	ErrEmailChangeTokenInvalid = domain.ErrForbidden(
+		"The email change token is invalid or expired. Please request the email change again",
+	)
+
+	// ErrEmailChangeTokenNotFound is returned when no matching email change token exists.
+	ErrEmailChangeTokenNotFound = domain.ErrNotFound("Email change token not found")
+
+	// ErrEmailUnchanged is returned when requesting an email change to the current address.
+	ErrEmailUnchanged = domain.ErrValidation("New email is the same as the current email")
+)
+
+// EmailChangeToken represents a one-time token used to confirm ownership of the new address
+// before it replaces the user's current email.
+type EmailChangeToken struct {
+	UserID    uuid.UUID                    `db:"user_id"    json:"user_id"`
+	NewEmail  string                       `db:"new_email"  json:"new_email"`
+	Value     string                       `db:"value"      json:"value"`
+	ExpiresAt time.Time                    `db:"expires_at" json:"expires_at"`
+	UsedAt    nullable.Nullable[time.Time] `db:"used_at"    json:"used_at"`
+}
+
+// NewEmailChangeToken creates a new token for changing the user's email to newEmail with a specified expiration.
+func NewEmailChangeToken(userID uuid.UUID, newEmail string, ttl time.Duration) (*EmailChangeToken, error) {
+	bs := make([]byte, 32)
+	if _, err := rand.Read(bs); err != nil {
+		return nil, err
+	}
+
+	return &EmailChangeToken{
+		UserID:    userID,
+		NewEmail:  newEmail,
+		Value:     hex.EncodeToString(bs),
+		ExpiresAt: time.Now().Add(ttl),
+	}, nil
+}
+
+// Expired reports whether the token has passed its expiration time.
+func (t EmailChangeToken) Expired() bool {
+	return t.ExpiresAt.Before(time.Now())
+}
+
+// Used reports whether the token has already been used.
+func (t EmailChangeToken) Used() bool {
+	return t.UsedAt.NotNull()
+}
+
+// Revoke marks the token as used immediately.
+func (t *EmailChangeToken) Revoke() {
+	t.UsedAt = nullable.New(time.Now(), false)
+}
@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEmailChangeToken(t *testing.T) {
+	userID := uuid.New()
+
+	token, err := NewEmailChangeToken(userID, "new@example.com", time.Hour)
+	require.NoError(t, err)
+	assert.NotEmpty(t, token.Value)
+	assert.Equal(t, userID, token.UserID)
+	assert.Equal(t, "new@example.com", token.NewEmail)
+	assert.False(t, token.Expired())
+	assert.False(t, token.Used())
+}
+
+func TestEmailChangeTokenExpired(t *testing.T) {
+	token, err := NewEmailChangeToken(uuid.New(), "new@example.com", -time.Hour)
+	require.NoError(t, err)
+	assert.True(t, token.Expired())
+}
+
+func TestEmailChangeTokenRevoke(t *testing.T) {
+	token, err := NewEmailChangeToken(uuid.New(), "new@example.com", time.Hour)
+	require.NoError(t, err)
+
+	token.Revoke()
+	assert.True(t, token.Used())
+}
@@ -0,0 +1,66 @@
+// Package auth provides authentication and authorization functionality.
+// This package handles user authentication through sessions, access tokens, and
+// password management including secure hashing and password reset flows. It manages
+// the complete authentication lifecycle from login through logout, including token
+// generation, validation, and session management.
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prawirdani/golang-restapi/internal/domain"
+	"github.com/prawirdani/golang-restapi/pkg/nullable"
+)
+
+var (
+	// ErrEmailVerificationTokenInvalid is returned when the verification token is invalid or expired.
+	ErrEmailVerificationTokenInvalid = domain.ErrForbidden(
+		"The verification token is invalid or expired. Please request a new verification email",
+	)
+
+	// ErrEmailVerificationTokenNotFound is returned when no matching verification token exists.
+	ErrEmailVerificationTokenNotFound = domain.ErrNotFound("Email verification token not found")
+
+	// ErrEmailAlreadyVerified is returned when requesting verification for an already verified email.
+	ErrEmailAlreadyVerified = domain.ErrValidation("Email is already verified")
+)
+
+// EmailVerificationToken represents a one-time token used to confirm ownership of a user's email address.
+type EmailVerificationToken struct {
+	UserID    uuid.UUID                    `db:"user_id"    json:"user_id"`
+	Value     string                       `db:"value"      json:"value"`
+	ExpiresAt time.Time                    `db:"expires_at" json:"expires_at"`
+	UsedAt    nullable.Nullable[time.Time] `db:"used_at"    json:"used_at"`
+}
+
+// NewEmailVerificationToken creates a new token for the given user with a specified expiration.
+func NewEmailVerificationToken(userID uuid.UUID, ttl time.Duration) (*EmailVerificationToken, error) {
+	bs := make([]byte, 32)
+	if _, err := rand.Read(bs); err != nil {
+		return nil, err
+	}
+
+	return &EmailVerificationToken{
+		UserID:    userID,
+		Value:     hex.EncodeToString(bs),
+		ExpiresAt: time.Now().Add(ttl),
+	}, nil
+}
+
+// Expired reports whether the token has passed its expiration time.
+func (t EmailVerificationToken) Expired() bool {
+	return t.ExpiresAt.Before(time.Now())
+}
+
+// Used reports whether the token has already been used.
+func (t EmailVerificationToken) Used() bool {
+	return t.UsedAt.NotNull()
+}
+
+// Revoke marks the token as used immediately.
+func (t *EmailVerificationToken) Revoke() {
+	t.UsedAt = nullable.New(time.Now(), false)
+}
@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEmailVerificationToken(t *testing.T) {
+	userID := uuid.New()
+	ttl := time.Hour
+
+	token, err := NewEmailVerificationToken(userID, ttl)
+	require.NoError(t, err)
+	assert.NotEmpty(t, token.Value)
+	assert.Equal(t, userID, token.UserID)
+	assert.False(t, token.Expired())
+	assert.False(t, token.Used())
+	assert.True(t, token.ExpiresAt.After(time.Now()))
+}
+
+func TestEmailVerificationTokenExpired(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("not expired", func(t *testing.T) {
+		token, err := NewEmailVerificationToken(userID, time.Hour)
+		require.NoError(t, err)
+		assert.False(t, token.Expired())
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		token, err := NewEmailVerificationToken(userID, -time.Hour)
+		require.NoError(t, err)
+		assert.True(t, token.Expired())
+	})
+}
+
+func TestEmailVerificationTokenUsed(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("not used", func(t *testing.T) {
+		token, err := NewEmailVerificationToken(userID, time.Hour)
+		require.NoError(t, err)
+		assert.False(t, token.Used())
+	})
+
+	t.Run("used", func(t *testing.T) {
+		token, err := NewEmailVerificationToken(userID, time.Hour)
+		require.NoError(t, err)
+		token.Revoke()
+		assert.True(t, token.Used())
+	})
+}
+
+func TestEmailVerificationTokenRevoke(t *testing.T) {
+	userID := uuid.New()
+	token, err := NewEmailVerificationToken(userID, time.Hour)
+	require.NoError(t, err)
+
+	assert.False(t, token.Used())
+	token.Revoke()
+	assert.True(t, token.Used())
+	assert.True(t, token.UsedAt.NotNull())
+}
@@ -0,0 +1,108 @@
+// Package auth provides authentication and authorization functionality.
+// This package handles user authentication through sessions, access tokens, and
+// password management including secure hashing and password reset flows. It manages
+// the complete authentication lifecycle from login through logout, including token
+// generation, validation, and session management.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prawirdani/golang-restapi/internal/domain"
+)
+
+// AMRFederated is recorded in the amr claim of sessions started through an external identity provider.
+const AMRFederated = "fed"
+
+var (
+	// ErrIdentityProviderNotFound is returned when no identity provider is configured under the requested name.
+	ErrIdentityProviderNotFound = domain.ErrNotFound("Identity provider not found")
+
+	// ErrExternalLoginInvalid is returned when the provider callback can't be trusted, e.g. a state mismatch or
+	// an invalid ID token.
+	ErrExternalLoginInvalid = domain.ErrUnauthorized("The external login is invalid or expired. Please try again")
+
+	// ErrExternalEmailNotVerified is returned when the provider doesn't vouch for the email address, which is
+	// required to link or create an account.
+	ErrExternalEmailNotVerified = domain.ErrForbidden(
+		"The email address of the external account is not verified",
+	)
+
+	// ErrExternalIdentityNotFound is returned when no user is linked to the external identity.
+	ErrExternalIdentityNotFound = domain.ErrNotFound("External identity not found")
+)
+
+// IdentityProvider is an external OpenID Connect provider users can sign in with.
+type IdentityProvider interface {
+	// Name identifies the provider, it's stored along with the linked identities.
+	Name() string
+
+	// AuthCodeURL returns the URL of the provider's consent page. The state and nonce are echoed back through
+	// the callback and the ID token, the verifier is the PKCE code verifier sent as an S256 challenge.
+	AuthCodeURL(state, nonce, verifier string) string
+
+	// Exchange redeems the authorization code and returns the claims of the validated ID token.
+	// Returns [ErrExternalLoginInvalid] if the code or the ID token is rejected.
+	Exchange(ctx context.Context, code, verifier, nonce string) (*ExternalClaims, error)
+}
+
+// ExternalClaims are the identity claims asserted by an external provider.
+type ExternalClaims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// ExternalIdentity links an account of an external provider to a user.
+type ExternalIdentity struct {
+	UserID    uuid.UUID `db:"user_id"    json:"user_id"`
+	Provider  string    `db:"provider"   json:"provider"`
+	Subject   string    `db:"subject"    json:"subject"`
+	Email     string    `db:"email"      json:"email"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// NewExternalIdentity links the provider account described by claims to the given user.
+func NewExternalIdentity(userID uuid.UUID, provider string, claims *ExternalClaims) *ExternalIdentity {
+	return &ExternalIdentity{
+		UserID:    userID,
+		Provider:  provider,
+		Subject:   claims.Subject,
+		Email:     claims.Email,
+		CreatedAt: time.Now(),
+	}
+}
+
+// ExternalLoginRequest holds the values to start an authorization code flow. URL is where the user is sent,
+// State, Nonce and Verifier have to be kept by the client until the callback.
+type ExternalLoginRequest struct {
+	URL      string
+	State    string
+	Nonce    string
+	Verifier string
+}
+
+// NewExternalLoginRequest generates a fresh state, nonce and PKCE verifier for the provider.
+func NewExternalLoginRequest(provider IdentityProvider) (*ExternalLoginRequest, error) {
+	values := make([]string, 3)
+	for i := range values {
+		bs := make([]byte, 32)
+		if _, err := rand.Read(bs); err != nil {
+			return nil, err
+		}
+		values[i] = base64.RawURLEncoding.EncodeToString(bs)
+	}
+
+	req := &ExternalLoginRequest{
+		State:    values[0],
+		Nonce:    values[1],
+		Verifier: values[2],
+	}
+	req.URL = provider.AuthCodeURL(req.State, req.Nonce, req.Verifier)
+	return req, nil
+}
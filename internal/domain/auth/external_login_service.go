@@ -0,0 +1,184 @@
+// Package auth provides authentication and authorization functionality.
+// This package handles user authentication through sessions, access tokens, and
+// password management including secure hashing and password reset flows. It manages
+// the complete authentication lifecycle from login through logout, including token
+// generation, validation, and session management.
+package auth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/prawirdani/golang-restapi/internal/domain/audit"
+	"github.com/prawirdani/golang-restapi/internal/domain/user"
+	"github.com/prawirdani/golang-restapi/pkg/log"
+)
+
+// BeginExternalLogin starts the authorization code flow of the named provider.
+func (s *Service) BeginExternalLogin(providerName string) (*ExternalLoginRequest, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, ErrIdentityProviderNotFound
+	}
+
+	return NewExternalLoginRequest(provider)
+}
+
+// CompleteExternalLogin logs the user in with the authorization code returned by the provider. A known identity
+// logs its linked user in. An unknown identity is linked to the user owning the same email address, or to a new
+// passwordless user when the address isn't registered. Linking requires the provider to have verified the address,
+// otherwise anyone could take over an account by registering its email at the provider. Linking to an unverified
+// account strips the credentials of whoever registered it, see [Service.claimUnverifiedAccount].
+func (s *Service) CompleteExternalLogin(ctx context.Context, inp CompleteExternalLoginInput) (*LoginResult, error) {
+	provider, ok := s.providers[inp.Provider]
+	if !ok {
+		return nil, ErrIdentityProviderNotFound
+	}
+
+	claims, err := provider.Exchange(ctx, inp.Code, inp.Verifier, inp.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *LoginResult
+	err = s.transactor.Transact(ctx, func(ctx context.Context) error {
+		usr, err := s.externalIdentityUser(ctx, provider.Name(), claims)
+		if err != nil {
+			return err
+		}
+
+		result, err = s.completeLogin(ctx, usr, inp.UserAgent, inp.IPAddress, AMRFederated)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// externalIdentityUser resolves the user of an external identity, linking the identity first if needed.
+func (s *Service) externalIdentityUser(
+	ctx context.Context,
+	providerName string,
+	claims *ExternalClaims,
+) (*user.User, error) {
+	identity, err := s.authRepo.GetExternalIdentity(ctx, providerName, claims.Subject)
+	if err == nil {
+		return s.userRepo.GetByID(ctx, identity.UserID.String())
+	}
+	if !errors.Is(err, ErrExternalIdentityNotFound) {
+		return nil, err
+	}
+
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, ErrExternalEmailNotVerified
+	}
+
+	usr, err := s.userRepo.GetByEmail(ctx, claims.Email)
+	switch {
+	case err == nil:
+		if !usr.IsVerified() {
+			if err := s.claimUnverifiedAccount(ctx, providerName, usr); err != nil {
+				return nil, err
+			}
+		}
+	case errors.Is(err, user.ErrNotFound):
+		name := claims.Name
+		if name == "" {
+			name = claims.Email
+		}
+
+		usr, err = user.NewExternal(name, claims.Email)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := s.userRepo.Store(ctx, usr); err != nil {
+			return nil, err
+		}
+
+		if err := s.recordEvent(ctx, audit.EventRegistered, usr.ID.String(), audit.Metadata{
+			"provider": providerName,
+		}); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	if err := s.authRepo.StoreExternalIdentity(ctx, NewExternalIdentity(usr.ID, providerName, claims)); err != nil {
+		return nil, err
+	}
+
+	if err := s.recordEvent(ctx, audit.EventExternalIdentityLinked, usr.ID.String(), audit.Metadata{
+		"provider": providerName,
+	}); err != nil {
+		return nil, err
+	}
+
+	log.InfoCtx(ctx, "External identity linked", "provider", providerName, "user_id", usr.ID.String())
+	return usr, nil
+}
+
+// claimUnverifiedAccount verifies the account once the provider proved ownership of its address. Whoever registered
+// it never did, they may have registered someone else's address to take the account over once its owner signs in,
+// so every credential, session, grant and pending token they may hold is dropped. Must be called inside a
+// transaction.
+func (s *Service) claimUnverifiedAccount(ctx context.Context, providerName string, usr *user.User) error {
+	userID := usr.ID.String()
+
+	usr.MarkVerified()
+	usr.ClearPassword()
+	if err := s.userRepo.Update(ctx, usr); err != nil {
+		return err
+	}
+
+	if err := s.revokeUserSessions(ctx, userID, ""); err != nil {
+		return err
+	}
+
+	tokens, err := s.authRepo.ListPersonalAccessTokensByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, tok := range tokens {
+		if err := s.authRepo.DeletePersonalAccessToken(ctx, userID, tok.ID.String()); err != nil {
+			return err
+		}
+	}
+
+	passkeys, err := s.authRepo.ListWebAuthnCredentialsByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, passkey := range passkeys {
+		if err := s.authRepo.DeleteWebAuthnCredential(ctx, userID, passkey.ID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.authRepo.DeleteTOTPFactor(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.authRepo.DeleteRecoveryCodes(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := s.authRepo.RevokePendingTokensByUser(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.revokeOAuthGrants(ctx, userID); err != nil {
+		return err
+	}
+
+	log.WarnCtx(ctx, "Unverified account claimed through an external identity",
+		"provider", providerName,
+		"user_id", userID,
+	)
+	return s.recordEvent(ctx, audit.EventAccountClaimed, userID, audit.Metadata{
+		"provider":         providerName,
+		"revoked_tokens":   len(tokens),
+		"revoked_passkeys": len(passkeys),
+	})
+}
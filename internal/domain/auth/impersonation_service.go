@@ -0,0 +1,174 @@
+// Package auth provides authentication and authorization functionality.
+// This package handles user authentication through sessions, access tokens, and
+// password management including secure hashing and password reset flows. It manages
+// the complete authentication lifecycle from login through logout, including token
+// generation, validation, and session management.
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prawirdani/golang-restapi/internal/domain"
+	"github.com/prawirdani/golang-restapi/internal/domain/audit"
+	"github.com/prawirdani/golang-restapi/internal/domain/user"
+	"github.com/prawirdani/golang-restapi/pkg/log"
+)
+
+// PermissionUsersImpersonate allows acting as another user, to reproduce their issues.
+const PermissionUsersImpersonate = "users:impersonate"
+
+const defaultImpersonationTTL = 15 * time.Minute
+
+var (
+	ErrImpersonationNotAllowed   = domain.ErrForbidden("This action is not allowed while impersonating a user")
+	ErrImpersonationNotPermitted = domain.ErrForbidden("You cannot impersonate a user holding permissions you lack")
+	ErrImpersonateSelf           = domain.ErrValidation("You cannot impersonate yourself")
+	ErrNotImpersonating          = domain.ErrValidation("The access token is not an impersonation token")
+)
+
+// Impersonate issues a short-lived access token to act as the user, with the administrator recorded in its act
+// claim. The token is bound to no session, so it can't be refreshed and ends once it expires or is stopped.
+// Users holding permissions the administrator lacks can't be impersonated, so it never escalates privileges.
+func (s *Service) Impersonate(
+	ctx context.Context,
+	actor *AccessTokenClaims,
+	userID string,
+	inp ImpersonateInput,
+) (*ImpersonationResult, error) {
+	if actor.Impersonated() {
+		return nil, ErrImpersonationNotAllowed
+	}
+
+	if actor.UserID == userID {
+		return nil, ErrImpersonateSelf
+	}
+
+	// The token permissions may be stale, check the current ones
+	actorGrants, err := s.authorizer.Grants(ctx, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !PermissionGranted(actorGrants.Permissions, PermissionUsersImpersonate) {
+		return nil, ErrPermissionDenied
+	}
+
+	usr, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if usr.IsSuspended() {
+		return nil, user.ErrSuspended
+	}
+
+	grants, err := s.authorizer.Grants(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, perm := range grants.Permissions {
+		if !PermissionGranted(actorGrants.Permissions, perm) {
+			return nil, ErrImpersonationNotPermitted
+		}
+	}
+
+	ttl := s.impersonationTTL()
+
+	claims := AccessTokenClaims{
+		UserID:      usr.ID.String(),
+		Roles:       grants.Roles,
+		Permissions: grants.Permissions,
+		Actor:       &Actor{UserID: actor.UserID},
+	}
+	claims.ID = uuid.NewString()
+
+	accessToken, err := SignAccessToken(s.keys, claims, ttl)
+	if err != nil {
+		log.ErrorCtx(ctx, "Failed to sign impersonation token", err)
+		return nil, err
+	}
+	expiresAt := time.Now().Add(ttl)
+
+	if err := s.recordEvent(ctx, audit.EventImpersonationStarted, userID, audit.Metadata{
+		"token_id":   claims.ID,
+		"reason":     inp.Reason,
+		"expires_at": expiresAt,
+	}); err != nil {
+		return nil, err
+	}
+
+	log.InfoCtx(ctx, "Impersonation started", "actor_id", actor.UserID, "user_id", userID)
+
+	return &ImpersonationResult{AccessToken: accessToken, ExpiresAt: expiresAt}, nil
+}
+
+// StopImpersonation revokes the impersonation token of the request before it expires.
+func (s *Service) StopImpersonation(ctx context.Context, claims *AccessTokenClaims) error {
+	if !claims.Impersonated() {
+		return ErrNotImpersonating
+	}
+
+	// Kept past the expiration, so the stopped impersonation isn't audited as expired later on
+	if s.revocations != nil && claims.ExpiresAt != nil {
+		if err := s.revocations.Revoke(ctx, claims.ID, claims.ExpiresAt.Add(s.impersonationTTL())); err != nil {
+			return err
+		}
+	}
+
+	if err := s.recordEvent(ctx, audit.EventImpersonationStopped, claims.UserID, audit.Metadata{
+		"token_id": claims.ID,
+	}); err != nil {
+		return err
+	}
+
+	log.InfoCtx(ctx, "Impersonation stopped", "actor_id", claims.Actor.UserID, "user_id", claims.UserID)
+
+	return nil
+}
+
+// EndExpiredImpersonation audits the end of an impersonation whose token is presented after it expired, on behalf of
+// the administrator who started it. The token ID is denylisted for another impersonation TTL so retries with the same
+// token aren't audited again. Anything but an expired impersonation token is ignored.
+func (s *Service) EndExpiredImpersonation(ctx context.Context, token string) error {
+	claims, err := VerifyExpiredAccessToken(s.keys, token)
+	if err != nil || !claims.Impersonated() || claims.ExpiresAt == nil || claims.ExpiresAt.After(time.Now()) {
+		return nil
+	}
+
+	if s.revocations != nil {
+		ended, err := s.revocations.IsRevoked(ctx, claims.ID)
+		if err != nil {
+			return err
+		}
+		if ended {
+			return nil
+		}
+
+		if err := s.revocations.Revoke(ctx, claims.ID, time.Now().Add(s.impersonationTTL())); err != nil {
+			return err
+		}
+	}
+
+	ctx = audit.WithActor(ctx, claims.Actor.UserID)
+	if err := s.recordEvent(ctx, audit.EventImpersonationExpired, claims.UserID, audit.Metadata{
+		"token_id":   claims.ID,
+		"expired_at": claims.ExpiresAt.Time,
+	}); err != nil {
+		return err
+	}
+
+	log.InfoCtx(ctx, "Impersonation expired", "actor_id", claims.Actor.UserID, "user_id", claims.UserID)
+
+	return nil
+}
+
+// impersonationTTL returns how long impersonation tokens are valid.
+func (s *Service) impersonationTTL() time.Duration {
+	if s.cfg.ImpersonationTTL == 0 {
+		return defaultImpersonationTTL
+	}
+	return s.cfg.ImpersonationTTL
+}
@@ -5,7 +5,10 @@
 // generation, validation, and session management.
 package auth
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Repository defines the persistence operations for authentication data.
 type Repository interface {
@@ -15,9 +18,23 @@ type Repository interface {
 	// GetSession retrieves a session by its ID.
 	GetSession(ctx context.Context, sessionID string) (*Session, error)
 
-	// UpdateSession updates an existing session (typically expiration).
+	// UpdateSession updates an existing session (typically expiration or rotation).
 	UpdateSession(ctx context.Context, session *Session) error
 
+	// RevokeSessionFamily expires every active session belonging to the given session family.
+	RevokeSessionFamily(ctx context.Context, familyID string) error
+
+	// ListSessionsByUser retrieves the active (neither expired nor rotated) sessions of a user,
+	// most recently accessed first.
+	ListSessionsByUser(ctx context.Context, userID string) ([]*Session, error)
+
+	// RevokeSessionsByUser expires every active session of a user, except the sessions of the
+	// given family. An empty exceptFamilyID revokes all sessions.
+	RevokeSessionsByUser(ctx context.Context, userID, exceptFamilyID string) error
+
+	// RevokePendingTokensByUser revokes the unused reset password, magic link and email change tokens of a user.
+	RevokePendingTokensByUser(ctx context.Context, userID string) error
+
 	// StoreResetPasswordToken creates a new password-reset token.
 	StoreResetPasswordToken(ctx context.Context, token *ResetPasswordToken) error
 
@@ -26,6 +43,231 @@ type Repository interface {
 
 	// GetResetPasswordToken retrieves a token by its value.
 	GetResetPasswordToken(ctx context.Context, value string) (*ResetPasswordToken, error)
+
+	// StoreEmailVerificationToken creates a new email verification token.
+	StoreEmailVerificationToken(ctx context.Context, token *EmailVerificationToken) error
+
+	// UpdateEmailVerificationToken updates an existing token (e.g., marking it used).
+	UpdateEmailVerificationToken(ctx context.Context, token *EmailVerificationToken) error
+
+	// GetEmailVerificationToken retrieves a token by its value.
+	GetEmailVerificationToken(ctx context.Context, value string) (*EmailVerificationToken, error)
+
+	// StoreEmailChangeToken creates a new email change token.
+	StoreEmailChangeToken(ctx context.Context, token *EmailChangeToken) error
+
+	// UpdateEmailChangeToken updates an existing token (e.g., marking it used).
+	UpdateEmailChangeToken(ctx context.Context, token *EmailChangeToken) error
+
+	// GetEmailChangeToken retrieves a token by its value.
+	GetEmailChangeToken(ctx context.Context, value string) (*EmailChangeToken, error)
+
+	// StoreMagicLinkToken creates a new magic link token.
+	StoreMagicLinkToken(ctx context.Context, token *MagicLinkToken) error
+
+	// UpdateMagicLinkToken updates an existing token (e.g., marking it used).
+	UpdateMagicLinkToken(ctx context.Context, token *MagicLinkToken) error
+
+	// GetMagicLinkToken retrieves a token by its value.
+	GetMagicLinkToken(ctx context.Context, value string) (*MagicLinkToken, error)
+
+	// GetExternalIdentity retrieves the identity linked to the subject of an external provider.
+	// Returns [ErrExternalIdentityNotFound] if the identity isn't linked to any user.
+	GetExternalIdentity(ctx context.Context, provider, subject string) (*ExternalIdentity, error)
+
+	// StoreExternalIdentity links an external identity to its user.
+	StoreExternalIdentity(ctx context.Context, identity *ExternalIdentity) error
+
+	// StoreAccountUnlockToken creates a new account unlock token.
+	StoreAccountUnlockToken(ctx context.Context, token *AccountUnlockToken) error
+
+	// UpdateAccountUnlockToken updates an existing token (e.g., marking it used).
+	UpdateAccountUnlockToken(ctx context.Context, token *AccountUnlockToken) error
+
+	// GetAccountUnlockToken retrieves a token by its value.
+	GetAccountUnlockToken(ctx context.Context, value string) (*AccountUnlockToken, error)
+
+	// StoreKnownDevice creates a new known device record.
+	StoreKnownDevice(ctx context.Context, device *KnownDevice) error
+
+	// ListKnownDevicesByUser retrieves the known devices of a user, most recently seen first.
+	ListKnownDevicesByUser(ctx context.Context, userID string) ([]*KnownDevice, error)
+
+	// UpdateKnownDevice updates an existing known device (e.g., last seen time).
+	UpdateKnownDevice(ctx context.Context, device *KnownDevice) error
+
+	// DeleteKnownDevice removes a known device owned by the user.
+	// Returns [ErrKnownDeviceNotFound] if the user has no such device.
+	DeleteKnownDevice(ctx context.Context, userID, deviceID string) error
+
+	// StoreSessionRevokeToken creates a new session revoke token.
+	StoreSessionRevokeToken(ctx context.Context, token *SessionRevokeToken) error
+
+	// UpdateSessionRevokeToken updates an existing token (e.g., marking it used).
+	UpdateSessionRevokeToken(ctx context.Context, token *SessionRevokeToken) error
+
+	// GetSessionRevokeToken retrieves a token by its value.
+	GetSessionRevokeToken(ctx context.Context, value string) (*SessionRevokeToken, error)
+
+	// GetLoginAttempts retrieves the failed login record tracked under the given key.
+	// Returns an empty record when no failure has been tracked yet.
+	GetLoginAttempts(ctx context.Context, key string) (*LoginAttempts, error)
+
+	// StoreLoginAttempts creates or replaces the failed login record of its key.
+	StoreLoginAttempts(ctx context.Context, attempts *LoginAttempts) error
+
+	// DeleteLoginAttempts removes the failed login record tracked under the given key.
+	DeleteLoginAttempts(ctx context.Context, key string) error
+
+	// GetPasswordHistory retrieves the hashes of a user's previous passwords, newest first, at most limit of them.
+	GetPasswordHistory(ctx context.Context, userID string, limit int) ([]string, error)
+
+	// StorePasswordHistory records a previous password hash of a user, keeping only the newest keep entries.
+	StorePasswordHistory(ctx context.Context, userID, passwordHash string, keep int) error
+
+	// StoreTOTPFactor creates the user's TOTP factor, replacing any previous factor.
+	StoreTOTPFactor(ctx context.Context, factor *TOTPFactor) error
+
+	// GetTOTPFactor retrieves the TOTP factor of a user.
+	GetTOTPFactor(ctx context.Context, userID string) (*TOTPFactor, error)
+
+	// UpdateTOTPFactor updates an existing TOTP factor (e.g., confirmation or last used step).
+	UpdateTOTPFactor(ctx context.Context, factor *TOTPFactor) error
+
+	// DeleteTOTPFactor removes the TOTP factor of a user.
+	DeleteTOTPFactor(ctx context.Context, userID string) error
+
+	// StoreRecoveryCodes replaces all recovery codes of a user with the given codes.
+	StoreRecoveryCodes(ctx context.Context, userID string, codes []*RecoveryCode) error
+
+	// GetRecoveryCode retrieves a user's recovery code by its hash.
+	GetRecoveryCode(ctx context.Context, userID, codeHash string) (*RecoveryCode, error)
+
+	// UpdateRecoveryCode updates an existing recovery code (e.g., marking it used).
+	UpdateRecoveryCode(ctx context.Context, code *RecoveryCode) error
+
+	// DeleteRecoveryCodes removes all recovery codes of a user.
+	DeleteRecoveryCodes(ctx context.Context, userID string) error
+
+	// StoreMFAChallenge creates a new MFA challenge.
+	StoreMFAChallenge(ctx context.Context, challenge *MFAChallenge) error
+
+	// GetMFAChallenge retrieves an MFA challenge by its value.
+	GetMFAChallenge(ctx context.Context, value string) (*MFAChallenge, error)
+
+	// UpdateMFAChallenge updates an existing challenge (e.g., attempts or marking it used).
+	UpdateMFAChallenge(ctx context.Context, challenge *MFAChallenge) error
+
+	// StoreWebAuthnCredential creates a new WebAuthn credential.
+	StoreWebAuthnCredential(ctx context.Context, credential *WebAuthnCredential) error
+
+	// ListWebAuthnCredentialsByUser retrieves the WebAuthn credentials of a user, oldest first.
+	ListWebAuthnCredentialsByUser(ctx context.Context, userID string) ([]*WebAuthnCredential, error)
+
+	// UpdateWebAuthnCredential updates an existing credential (e.g., sign count or last used time).
+	UpdateWebAuthnCredential(ctx context.Context, credential *WebAuthnCredential) error
+
+	// DeleteWebAuthnCredential removes a WebAuthn credential owned by the user.
+	// Returns [ErrWebAuthnCredentialNotFound] if the user has no such credential.
+	DeleteWebAuthnCredential(ctx context.Context, userID, credentialID string) error
+
+	// StoreWebAuthnChallenge creates a new WebAuthn challenge.
+	StoreWebAuthnChallenge(ctx context.Context, challenge *WebAuthnChallenge) error
+
+	// GetWebAuthnChallenge retrieves a WebAuthn challenge by its value.
+	GetWebAuthnChallenge(ctx context.Context, value string) (*WebAuthnChallenge, error)
+
+	// UpdateWebAuthnChallenge updates an existing challenge (e.g., marking it used).
+	UpdateWebAuthnChallenge(ctx context.Context, challenge *WebAuthnChallenge) error
+
+	// StorePersonalAccessToken creates a new personal access token.
+	StorePersonalAccessToken(ctx context.Context, token *PersonalAccessToken) error
+
+	// GetPersonalAccessTokenByHash retrieves a personal access token by the hash of its value.
+	GetPersonalAccessTokenByHash(ctx context.Context, tokenHash string) (*PersonalAccessToken, error)
+
+	// ListPersonalAccessTokensByUser retrieves the personal access tokens of a user, newest first.
+	ListPersonalAccessTokensByUser(ctx context.Context, userID string) ([]*PersonalAccessToken, error)
+
+	// UpdatePersonalAccessToken updates an existing personal access token (e.g., last used time).
+	UpdatePersonalAccessToken(ctx context.Context, token *PersonalAccessToken) error
+
+	// DeletePersonalAccessToken removes a personal access token owned by the user.
+	// Returns [ErrPersonalAccessTokenNotFound] if the user has no such token.
+	DeletePersonalAccessToken(ctx context.Context, userID, tokenID string) error
+}
+
+// TokenRevocationStore keeps the denylist of revoked access tokens. Entries are either a token ID (jti) or a
+// session family ID (sid), the latter revoking every access token issued from the session.
+type TokenRevocationStore interface {
+	// Revoke denylists the token or session family ID until expiresAt, when the last token it covers expires.
+	// Revoking an already revoked ID keeps the later expiration.
+	Revoke(ctx context.Context, id string, expiresAt time.Time) error
+
+	// IsRevoked reports whether any of the IDs is denylisted and not yet expired.
+	IsRevoked(ctx context.Context, ids ...string) (bool, error)
+}
+
+// RoleRepository defines the persistence operations for roles and their assignment to users.
+type RoleRepository interface {
+	// ListRoles retrieves every role along with its permissions.
+	ListRoles(ctx context.Context) ([]*Role, error)
+
+	// GetRoleByName retrieves a role along with its permissions.
+	GetRoleByName(ctx context.Context, name string) (*Role, error)
+
+	// GetRolesByUser retrieves the roles assigned to a user along with their permissions.
+	GetRolesByUser(ctx context.Context, userID string) ([]*Role, error)
+
+	// AssignRole assigns a role to a user, assigning an already held role is a no-op.
+	AssignRole(ctx context.Context, userID string, roleID int) error
+
+	// RevokeRole removes a role from a user.
+	RevokeRole(ctx context.Context, userID string, roleID int) error
+}
+
+// OAuthRepository defines the persistence operations of the OAuth authorization server.
+type OAuthRepository interface {
+	// StoreOAuthClient creates a new client.
+	StoreOAuthClient(ctx context.Context, client *OAuthClient) error
+
+	// GetOAuthClient retrieves a client by its ID.
+	// Returns [ErrOAuthClientNotFound] if the client doesn't exist.
+	GetOAuthClient(ctx context.Context, clientID string) (*OAuthClient, error)
+
+	// ListOAuthClients retrieves every client, newest first.
+	ListOAuthClients(ctx context.Context) ([]*OAuthClient, error)
+
+	// DeleteOAuthClient removes a client along with its codes and tokens.
+	// Returns [ErrOAuthClientNotFound] if the client doesn't exist.
+	DeleteOAuthClient(ctx context.Context, clientID string) error
+
+	// StoreOAuthAuthorizationCode creates a new authorization code.
+	StoreOAuthAuthorizationCode(ctx context.Context, code *OAuthAuthorizationCode) error
+
+	// GetOAuthAuthorizationCode retrieves an authorization code by its hash.
+	// Returns [ErrOAuthAuthorizationCodeNotFound] if no such code exists.
+	GetOAuthAuthorizationCode(ctx context.Context, codeHash string) (*OAuthAuthorizationCode, error)
+
+	// UpdateOAuthAuthorizationCode updates an existing code (e.g., marking it used).
+	UpdateOAuthAuthorizationCode(ctx context.Context, code *OAuthAuthorizationCode) error
+
+	// StoreOAuthRefreshToken creates a new refresh token.
+	StoreOAuthRefreshToken(ctx context.Context, token *OAuthRefreshToken) error
+
+	// GetOAuthRefreshTokenByHash retrieves a refresh token by the hash of its value.
+	// Returns [ErrOAuthRefreshTokenNotFound] if no such token exists.
+	GetOAuthRefreshTokenByHash(ctx context.Context, tokenHash string) (*OAuthRefreshToken, error)
+
+	// UpdateOAuthRefreshToken updates an existing refresh token (e.g., revoking it).
+	UpdateOAuthRefreshToken(ctx context.Context, token *OAuthRefreshToken) error
+
+	// RevokeOAuthRefreshTokens revokes every active refresh token the client holds for the user.
+	RevokeOAuthRefreshTokens(ctx context.Context, clientID, userID string) error
+
+	// RevokeOAuthGrantsByUser revokes every active refresh token and unused authorization code of the user,
+	// across all clients.
+	RevokeOAuthGrantsByUser(ctx context.Context, userID string) error
 }
 
 // MessagePublisher defines the contract for publishing authentication-related
@@ -37,4 +279,35 @@ type MessagePublisher interface {
 	// consumed by an email service worker.
 	// Returns an error if the message cannot be published to the queue.
 	SendResetPasswordEmail(ctx context.Context, msg ResetPasswordEmailMessage) error
+
+	// SendVerificationEmail publishes a message to trigger an email address verification email.
+	// Returns an error if the message cannot be published to the queue.
+	SendVerificationEmail(ctx context.Context, msg VerificationEmailMessage) error
+
+	// SendEmailChangeConfirmation publishes a message to trigger the email that confirms a new address.
+	// Returns an error if the message cannot be published to the queue.
+	SendEmailChangeConfirmation(ctx context.Context, msg EmailChangeConfirmationMessage) error
+
+	// SendEmailChangeNotification publishes a message to warn the current address about a requested email change.
+	// Returns an error if the message cannot be published to the queue.
+	SendEmailChangeNotification(ctx context.Context, msg EmailChangeNotificationMessage) error
+
+	// SendAccountLockedEmail publishes a message to warn the user that their account got locked
+	// after too many failed logins, along with a link to unlock it.
+	// Returns an error if the message cannot be published to the queue.
+	SendAccountLockedEmail(ctx context.Context, msg AccountLockedEmailMessage) error
+
+	// SendMagicLinkEmail publishes a message to trigger an email carrying a passwordless login link.
+	// Returns an error if the message cannot be published to the queue.
+	SendMagicLinkEmail(ctx context.Context, msg MagicLinkEmailMessage) error
+
+	// SendAccountExistsEmail publishes a message to tell the owner of an account that someone tried to register
+	// with their email address, sent in place of a conflict error when enumeration protection is enabled.
+	// Returns an error if the message cannot be published to the queue.
+	SendAccountExistsEmail(ctx context.Context, msg AccountExistsEmailMessage) error
+
+	// SendNewDeviceLoginEmail publishes a message to warn the user about a login from an unrecognized device,
+	// along with a link to revoke the session if it wasn't them.
+	// Returns an error if the message cannot be published to the queue.
+	SendNewDeviceLoginEmail(ctx context.Context, msg NewDeviceLoginEmailMessage) error
 }
@@ -0,0 +1,302 @@
+// Package auth provides authentication and authorization functionality.
+// This package handles user authentication through sessions, access tokens, and
+// password management including secure hashing and password reset flows. It manages
+// the complete authentication lifecycle from login through logout, including token
+// generation, validation, and session management.
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/prawirdani/golang-restapi/config"
+)
+
+var (
+	ErrSigningKeyUnsupported = errors.New("unsupported signing key type, expecting RSA or Ed25519")
+	ErrSigningKeyNotPrivate  = errors.New("active signing key must be a private key")
+	ErrSigningKeyMissing     = errors.New("no signing key configured")
+	ErrSigningKeyUnknown     = errors.New("unknown signing key id")
+)
+
+// hmacKeyID identifies the shared-secret key, which is never published in the JWKS.
+const hmacKeyID = "hs256"
+
+// SigningKey is a single key of a [KeySet]. Keys without a private part can only verify tokens,
+// which is how retired keys are kept around until every token they signed has expired.
+type SigningKey struct {
+	ID        string
+	Method    jwt.SigningMethod
+	Private   any // *rsa.PrivateKey, ed25519.PrivateKey or []byte for HMAC; nil for verification-only keys
+	PublicKey crypto.PublicKey
+}
+
+// KeySet holds the key used to sign new access tokens, plus every key still accepted for verification.
+// Tokens carry the ID of their signing key in the kid header, so keys can be rotated without
+// invalidating tokens that are still in flight.
+type KeySet struct {
+	active *SigningKey
+	keys   map[string]*SigningKey
+	order  []string
+}
+
+// NewKeySet creates a key set signing with the active key and verifying with it and the additional keys.
+func NewKeySet(active *SigningKey, verification ...*SigningKey) (*KeySet, error) {
+	if active == nil {
+		return nil, ErrSigningKeyMissing
+	}
+	if active.Private == nil {
+		return nil, ErrSigningKeyNotPrivate
+	}
+
+	ks := &KeySet{
+		active: active,
+		keys:   make(map[string]*SigningKey, len(verification)+1),
+	}
+
+	for _, key := range append([]*SigningKey{active}, verification...) {
+		if _, ok := ks.keys[key.ID]; ok {
+			continue
+		}
+		ks.keys[key.ID] = key
+		ks.order = append(ks.order, key.ID)
+	}
+
+	return ks, nil
+}
+
+// NewHMACKeySet creates a key set using a single shared secret with HS256.
+// Tokens signed this way can only be verified by holders of the secret.
+func NewHMACKeySet(secret string) *KeySet {
+	ks, _ := NewKeySet(&SigningKey{
+		ID:      hmacKeyID,
+		Method:  jwt.SigningMethodHS256,
+		Private: []byte(secret),
+	})
+	return ks
+}
+
+// LoadKeySet builds the key set from the auth configuration. When no signing key file is configured
+// it falls back to HS256 with the shared JWT secret.
+func LoadKeySet(cfg config.Auth) (*KeySet, error) {
+	if cfg.JwtSigningKeyFile == "" {
+		if cfg.JwtSecret == "" {
+			return nil, ErrSigningKeyMissing
+		}
+		return NewHMACKeySet(cfg.JwtSecret), nil
+	}
+
+	active, err := loadSigningKeyFile(cfg.JwtSigningKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	verification := make([]*SigningKey, 0, len(cfg.JwtVerificationKeyFiles))
+	for _, file := range cfg.JwtVerificationKeyFiles {
+		key, err := loadSigningKeyFile(file)
+		if err != nil {
+			return nil, err
+		}
+		verification = append(verification, key)
+	}
+
+	return NewKeySet(active, verification...)
+}
+
+func loadSigningKeyFile(path string) (*SigningKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading signing key %s: %w", path, err)
+	}
+
+	key, err := ParseSigningKeyPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing signing key %s: %w", path, err)
+	}
+
+	return key, nil
+}
+
+// ParseSigningKeyPEM parses a PKCS#8 private key or a PKIX public key in PEM format.
+// RSA keys sign with RS256 and Ed25519 keys with EdDSA, the key ID is the RFC 7638 thumbprint of the public key.
+func ParseSigningKeyPEM(data []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var (
+		private any
+		public  crypto.PublicKey
+	)
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, ErrSigningKeyUnsupported
+		}
+		private, public = key, signer.Public()
+	case "RSA PRIVATE KEY":
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		private, public = key, key.Public()
+	case "PUBLIC KEY":
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		public = key
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+
+	var method jwt.SigningMethod
+	switch public.(type) {
+	case *rsa.PublicKey:
+		method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		method = jwt.SigningMethodEdDSA
+	default:
+		return nil, ErrSigningKeyUnsupported
+	}
+
+	key := &SigningKey{
+		Method:    method,
+		Private:   private,
+		PublicKey: public,
+	}
+
+	thumbprint, err := key.JWK().Thumbprint()
+	if err != nil {
+		return nil, err
+	}
+	key.ID = thumbprint
+
+	return key, nil
+}
+
+// Sign signs the claims with the active key, setting its ID in the kid header.
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.active.Method, claims)
+	token.Header["kid"] = ks.active.ID
+	return token.SignedString(ks.active.Private)
+}
+
+// Keyfunc resolves the verification key of a token by its kid header, to be used with jwt.Parse.
+// Tokens without a kid are checked against the active key, which covers tokens issued before key IDs were used.
+func (ks *KeySet) Keyfunc(t *jwt.Token) (any, error) {
+	key := ks.active
+	if kid, ok := t.Header["kid"].(string); ok {
+		if key, ok = ks.keys[kid]; !ok {
+			return nil, ErrSigningKeyUnknown
+		}
+	}
+
+	if t.Method.Alg() != key.Method.Alg() {
+		return nil, jwt.ErrSignatureInvalid
+	}
+
+	if secret, ok := key.Private.([]byte); ok {
+		return secret, nil
+	}
+	return key.PublicKey, nil
+}
+
+// JWKS returns the public keys of the set as a JSON Web Key Set. Shared secrets are never included.
+func (ks *KeySet) JWKS() JWKSet {
+	set := JWKSet{Keys: make([]JWK, 0, len(ks.order))}
+	for _, id := range ks.order {
+		key := ks.keys[id]
+		if key.PublicKey == nil {
+			continue
+		}
+		set.Keys = append(set.Keys, key.JWK())
+	}
+	return set
+}
+
+// JWKSet is a JSON Web Key Set as defined in RFC 7517.
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWK is the public part of a signing key as a JSON Web Key.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`   // RSA modulus
+	E   string `json:"e,omitempty"`   // RSA exponent
+	Crv string `json:"crv,omitempty"` // OKP curve
+	X   string `json:"x,omitempty"`   // OKP public key
+}
+
+// JWK returns the public part of the key as a JSON Web Key.
+func (k *SigningKey) JWK() JWK {
+	jwk := JWK{
+		Kid: k.ID,
+		Use: "sig",
+		Alg: k.Method.Alg(),
+	}
+
+	switch pub := k.PublicKey.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	}
+
+	return jwk
+}
+
+// Thumbprint computes the RFC 7638 SHA-256 thumbprint of the key.
+func (j JWK) Thumbprint() (string, error) {
+	var members any
+	switch j.Kty {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{j.E, j.Kty, j.N}
+	case "OKP":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{j.Crv, j.Kty, j.X}
+	default:
+		return "", ErrSigningKeyUnsupported
+	}
+
+	bs, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(bs)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,178 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prawirdani/golang-restapi/config"
+)
+
+func encodePrivateKeyPEM(t *testing.T, key any) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func encodePublicKeyPEM(t *testing.T, key any) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func TestParseSigningKeyPEM(t *testing.T) {
+	t.Run("RSA", func(t *testing.T) {
+		private, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		key, err := ParseSigningKeyPEM(encodePrivateKeyPEM(t, private))
+		require.NoError(t, err)
+		assert.Equal(t, jwt.SigningMethodRS256, key.Method)
+		assert.NotEmpty(t, key.ID)
+		assert.NotNil(t, key.Private)
+	})
+
+	t.Run("Ed25519", func(t *testing.T) {
+		public, private, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		key, err := ParseSigningKeyPEM(encodePrivateKeyPEM(t, private))
+		require.NoError(t, err)
+		assert.Equal(t, jwt.SigningMethodEdDSA, key.Method)
+
+		// The public key alone yields the same key ID
+		pubKey, err := ParseSigningKeyPEM(encodePublicKeyPEM(t, public))
+		require.NoError(t, err)
+		assert.Nil(t, pubKey.Private)
+		assert.Equal(t, key.ID, pubKey.ID)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := ParseSigningKeyPEM([]byte("not a pem"))
+		assert.Error(t, err)
+	})
+}
+
+func TestJWKThumbprint(t *testing.T) {
+	// Example key and thumbprint from RFC 7638 section 3.1
+	jwk := JWK{
+		Kty: "RSA",
+		E:   "AQAB",
+		N: "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMs" +
+			"tn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n9" +
+			"1CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+	}
+
+	thumbprint, err := jwk.Thumbprint()
+	require.NoError(t, err)
+	assert.Equal(t, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", thumbprint)
+}
+
+func TestKeySetRotation(t *testing.T) {
+	_, oldPrivate, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	oldKey, err := ParseSigningKeyPEM(encodePrivateKeyPEM(t, oldPrivate))
+	require.NoError(t, err)
+
+	newPrivate, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	newKey, err := ParseSigningKeyPEM(encodePrivateKeyPEM(t, newPrivate))
+	require.NoError(t, err)
+
+	oldSet, err := NewKeySet(oldKey)
+	require.NoError(t, err)
+	oldToken, err := SignAccessToken(oldSet, mockClaims, time.Minute)
+	require.NoError(t, err)
+
+	// Rotate: sign with the new key, keep verifying with the old one
+	rotated, err := NewKeySet(newKey, oldKey)
+	require.NoError(t, err)
+	newToken, err := SignAccessToken(rotated, mockClaims, time.Minute)
+	require.NoError(t, err)
+
+	for _, token := range []string{oldToken, newToken} {
+		claims, err := VerifyAccessToken(rotated, token)
+		require.NoError(t, err)
+		assert.Equal(t, mockClaims.UserID, claims.UserID)
+	}
+
+	jwks := rotated.JWKS()
+	require.Len(t, jwks.Keys, 2)
+	assert.Equal(t, newKey.ID, jwks.Keys[0].Kid)
+	assert.Equal(t, "RSA", jwks.Keys[0].Kty)
+	assert.Equal(t, "OKP", jwks.Keys[1].Kty)
+
+	t.Run("Retired key", func(t *testing.T) {
+		retired, err := NewKeySet(newKey)
+		require.NoError(t, err)
+
+		_, err = VerifyAccessToken(retired, oldToken)
+		assert.ErrorIs(t, err, ErrSigningKeyUnknown)
+	})
+
+	t.Run("Verification only key cannot sign", func(t *testing.T) {
+		pubKey, err := ParseSigningKeyPEM(encodePublicKeyPEM(t, newPrivate.Public()))
+		require.NoError(t, err)
+
+		_, err = NewKeySet(pubKey)
+		assert.ErrorIs(t, err, ErrSigningKeyNotPrivate)
+	})
+}
+
+func TestKeySetHMAC(t *testing.T) {
+	ks := NewHMACKeySet("secret")
+	assert.Empty(t, ks.JWKS().Keys)
+
+	token, err := SignAccessToken(ks, mockClaims, time.Minute)
+	require.NoError(t, err)
+
+	_, err = VerifyAccessToken(NewHMACKeySet("other-secret"), token)
+	assert.Error(t, err)
+}
+
+func TestLoadKeySet(t *testing.T) {
+	t.Run("HMAC fallback", func(t *testing.T) {
+		ks, err := LoadKeySet(config.Auth{JwtSecret: "secret"})
+		require.NoError(t, err)
+		assert.Equal(t, jwt.SigningMethodHS256, ks.active.Method)
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		_, err := LoadKeySet(config.Auth{})
+		assert.ErrorIs(t, err, ErrSigningKeyMissing)
+	})
+
+	t.Run("PEM files", func(t *testing.T) {
+		dir := t.TempDir()
+
+		_, signing, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		verification, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		signingFile := filepath.Join(dir, "signing.pem")
+		verificationFile := filepath.Join(dir, "verification.pem")
+		require.NoError(t, os.WriteFile(signingFile, encodePrivateKeyPEM(t, signing), 0o600))
+		require.NoError(t, os.WriteFile(verificationFile, encodePublicKeyPEM(t, verification), 0o600))
+
+		ks, err := LoadKeySet(config.Auth{
+			JwtSigningKeyFile:       signingFile,
+			JwtVerificationKeyFiles: []string{verificationFile},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, jwt.SigningMethodEdDSA, ks.active.Method)
+		assert.Len(t, ks.JWKS().Keys, 2)
+	})
+}
@@ -0,0 +1,250 @@
+// Package auth provides authentication and authorization functionality.
+// This package handles user authentication through sessions, access tokens, and
+// password management including secure hashing and password reset flows. It manages
+// the complete authentication lifecycle from login through logout, including token
+// generation, validation, and session management.
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/prawirdani/golang-restapi/internal/domain/audit"
+	"github.com/prawirdani/golang-restapi/internal/domain/user"
+	"github.com/prawirdani/golang-restapi/pkg/log"
+)
+
+// checkIPThrottle rejects a login from a client IP that is blocked after too many failed logins.
+func (s *Service) checkIPThrottle(ctx context.Context, ip string) error {
+	if !IPLoginPolicy(s.cfg).Enabled() || ip == "" {
+		return nil
+	}
+
+	attempts, err := s.authRepo.GetLoginAttempts(ctx, IPAttemptsKey(ip))
+	if err != nil {
+		return err
+	}
+
+	if attempts.Locked(time.Now()) {
+		return ErrTooManyLoginAttempts
+	}
+
+	return nil
+}
+
+// checkAccountThrottle rejects a login to an account that is locked, or that must wait out the progressive delay
+// since its last failed login. Checked before the password so a locked account can't be used as a password oracle.
+func (s *Service) checkAccountThrottle(ctx context.Context, userID string) error {
+	return s.checkAttemptsThrottle(ctx, AccountAttemptsKey(userID))
+}
+
+// checkAttemptsThrottle applies the account throttle policy to the failed logins tracked under key.
+func (s *Service) checkAttemptsThrottle(ctx context.Context, key string) error {
+	policy := AccountLoginPolicy(s.cfg)
+	if !policy.Enabled() {
+		return nil
+	}
+
+	attempts, err := s.authRepo.GetLoginAttempts(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if attempts.Locked(now) {
+		return ErrAccountLocked
+	}
+
+	if attempts.Throttled(policy, now) {
+		return ErrTooManyLoginAttempts
+	}
+
+	return nil
+}
+
+// recordIPFailure counts a failed login against the client IP.
+func (s *Service) recordIPFailure(ctx context.Context, ip string) error {
+	policy := IPLoginPolicy(s.cfg)
+	if !policy.Enabled() || ip == "" {
+		return nil
+	}
+
+	return s.transactor.Transact(ctx, func(ctx context.Context) error {
+		attempts, err := s.authRepo.GetLoginAttempts(ctx, IPAttemptsKey(ip))
+		if err != nil {
+			return err
+		}
+
+		if attempts.RecordFailure(policy, time.Now()) {
+			log.WarnCtx(ctx, "Client IP blocked after too many failed logins", "ip", ip)
+		}
+
+		return s.authRepo.StoreLoginAttempts(ctx, attempts)
+	})
+}
+
+// recordAccountFailure counts a failed login against the account. Once the account gets locked, its owner is mailed
+// a security notification with a link to unlock it, and [ErrAccountLocked] is returned.
+func (s *Service) recordAccountFailure(ctx context.Context, usr *user.User, ip string) error {
+	policy := AccountLoginPolicy(s.cfg)
+	if !policy.Enabled() {
+		return nil
+	}
+
+	var locked bool
+	err := s.transactor.Transact(ctx, func(ctx context.Context) error {
+		attempts, err := s.authRepo.GetLoginAttempts(ctx, AccountAttemptsKey(usr.ID.String()))
+		if err != nil {
+			return err
+		}
+
+		locked = attempts.RecordFailure(policy, time.Now())
+		if err := s.authRepo.StoreLoginAttempts(ctx, attempts); err != nil {
+			return err
+		}
+
+		if !locked {
+			return nil
+		}
+
+		if err := s.recordEvent(ctx, audit.EventAccountLocked, usr.ID.String(), audit.Metadata{
+			"locked_until": attempts.LockedUntil.Get(),
+		}); err != nil {
+			return err
+		}
+
+		return s.sendAccountLockedEmail(ctx, usr, ip, attempts.LockedUntil.Get())
+	})
+	if err != nil {
+		return err
+	}
+
+	if locked {
+		return ErrAccountLocked
+	}
+
+	return nil
+}
+
+// recordLoginFailure counts a failed login, or a failed confirmation of a credential, against both the client IP
+// and the account. Returns [ErrAccountLocked] once the account gets locked.
+func (s *Service) recordLoginFailure(ctx context.Context, usr *user.User, ip string) error {
+	if err := s.recordIPFailure(ctx, ip); err != nil {
+		return err
+	}
+
+	return s.recordAccountFailure(ctx, usr, ip)
+}
+
+// recordUnknownEmailFailure counts a failed login for an email without an account like a failure of an account, so
+// with enumeration protection the throttle answers the same whether or not the email is registered. Once the email
+// gets locked [ErrAccountLocked] is returned, there is no owner to notify.
+func (s *Service) recordUnknownEmailFailure(ctx context.Context, email string) error {
+	policy := AccountLoginPolicy(s.cfg)
+	if !policy.Enabled() {
+		return nil
+	}
+
+	var locked bool
+	err := s.transactor.Transact(ctx, func(ctx context.Context) error {
+		attempts, err := s.authRepo.GetLoginAttempts(ctx, UnknownEmailAttemptsKey(email))
+		if err != nil {
+			return err
+		}
+
+		locked = attempts.RecordFailure(policy, time.Now())
+		return s.authRepo.StoreLoginAttempts(ctx, attempts)
+	})
+	if err != nil {
+		return err
+	}
+
+	if locked {
+		return ErrAccountLocked
+	}
+
+	return nil
+}
+
+// resetAccountFailures forgets the failed logins of an account.
+func (s *Service) resetAccountFailures(ctx context.Context, userID string) error {
+	if !AccountLoginPolicy(s.cfg).Enabled() {
+		return nil
+	}
+
+	return s.authRepo.DeleteLoginAttempts(ctx, AccountAttemptsKey(userID))
+}
+
+// confirmPassword re-confirms the password of a signed in user before a sensitive operation. It is throttled like a
+// login and a wrong password is counted like a failed login, so it can't be used to guess the password of a hijacked
+// session.
+func (s *Service) confirmPassword(ctx context.Context, usr *user.User, plain, ip string) error {
+	if err := s.checkAccountThrottle(ctx, usr.ID.String()); err != nil {
+		return err
+	}
+
+	if _, err := s.passwords.Verify(plain, usr.Password); err != nil {
+		if err != ErrWrongCredentials {
+			return err
+		}
+		if err := s.recordLoginFailure(ctx, usr, ip); err != nil {
+			return err
+		}
+		return ErrWrongCredentials
+	}
+
+	return s.resetAccountFailures(ctx, usr.ID.String())
+}
+
+// UnlockAccount lifts the lockout of an account using the unlock token from the security notification email.
+func (s *Service) UnlockAccount(ctx context.Context, inp UnlockAccountInput) error {
+	return s.transactor.Transact(ctx, func(ctx context.Context) error {
+		token, err := s.authRepo.GetAccountUnlockToken(ctx, inp.Token)
+		if err != nil {
+			return err
+		}
+
+		if token.Expired() || token.Used() {
+			return ErrAccountUnlockTokenInvalid
+		}
+
+		token.Revoke()
+		if err := s.authRepo.UpdateAccountUnlockToken(ctx, token); err != nil {
+			return err
+		}
+
+		if err := s.authRepo.DeleteLoginAttempts(ctx, AccountAttemptsKey(token.UserID.String())); err != nil {
+			return err
+		}
+
+		return s.recordEvent(ctx, audit.EventAccountUnlocked, token.UserID.String(), nil)
+	})
+}
+
+func (s *Service) sendAccountLockedEmail(
+	ctx context.Context,
+	usr *user.User,
+	ip string,
+	lockedUntil time.Time,
+) error {
+	token, err := NewAccountUnlockToken(usr.ID, s.cfg.AccountUnlockTTL)
+	if err != nil {
+		log.ErrorCtx(ctx, "Failed to create account unlock token", err)
+		return err
+	}
+
+	if err := s.authRepo.StoreAccountUnlockToken(ctx, token); err != nil {
+		return err
+	}
+
+	msg := AccountLockedEmailMessage{
+		To:          usr.Email,
+		Name:        usr.Name,
+		IPAddress:   ip,
+		LockedUntil: lockedUntil,
+		UnlockURL:   s.cfg.AccountUnlockEndpoint + "?token=" + token.Value,
+		Expiry:      s.cfg.AccountUnlockTTL,
+	}
+
+	return s.publisher.SendAccountLockedEmail(ctx, msg)
+}
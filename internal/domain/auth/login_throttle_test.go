@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testThrottlePolicy = LoginThrottlePolicy{
+	MaxFailures:     3,
+	FailureWindow:   15 * time.Minute,
+	LockoutDuration: 15 * time.Minute,
+	BaseDelay:       time.Second,
+}
+
+func TestLoginThrottlePolicyEnabled(t *testing.T) {
+	assert.True(t, testThrottlePolicy.Enabled())
+	assert.False(t, LoginThrottlePolicy{}.Enabled())
+}
+
+func TestLoginThrottlePolicyDelay(t *testing.T) {
+	assert.Zero(t, testThrottlePolicy.delay(0))
+	assert.Equal(t, time.Second, testThrottlePolicy.delay(1))
+	assert.Equal(t, 2*time.Second, testThrottlePolicy.delay(2))
+	assert.Equal(t, 4*time.Second, testThrottlePolicy.delay(3))
+
+	// Capped at the lockout duration
+	assert.Equal(t, testThrottlePolicy.LockoutDuration, testThrottlePolicy.delay(20))
+	assert.Equal(t, testThrottlePolicy.LockoutDuration, testThrottlePolicy.delay(100))
+
+	assert.Zero(t, LoginThrottlePolicy{MaxFailures: 3}.delay(2))
+}
+
+func TestLoginAttemptsRecordFailure(t *testing.T) {
+	now := time.Now()
+	attempts := &LoginAttempts{Key: AccountAttemptsKey(uuid.NewString())}
+
+	assert.False(t, attempts.RecordFailure(testThrottlePolicy, now))
+	assert.False(t, attempts.RecordFailure(testThrottlePolicy, now))
+	assert.Equal(t, 2, attempts.Failures)
+	assert.False(t, attempts.Locked(now))
+
+	assert.True(t, attempts.RecordFailure(testThrottlePolicy, now))
+	assert.Zero(t, attempts.Failures)
+	assert.True(t, attempts.Locked(now))
+	assert.False(t, attempts.Locked(now.Add(testThrottlePolicy.LockoutDuration)))
+}
+
+func TestLoginAttemptsRecordFailureWindowExpired(t *testing.T) {
+	now := time.Now()
+	attempts := &LoginAttempts{
+		Key:          IPAttemptsKey("127.0.0.1"),
+		Failures:     2,
+		LastFailedAt: now.Add(-time.Hour),
+	}
+
+	assert.False(t, attempts.RecordFailure(testThrottlePolicy, now))
+	assert.Equal(t, 1, attempts.Failures)
+	assert.Equal(t, now, attempts.LastFailedAt)
+}
+
+func TestLoginAttemptsThrottled(t *testing.T) {
+	now := time.Now()
+	attempts := &LoginAttempts{Failures: 2, LastFailedAt: now}
+
+	assert.True(t, attempts.Throttled(testThrottlePolicy, now.Add(time.Second)))
+	assert.False(t, attempts.Throttled(testThrottlePolicy, now.Add(2*time.Second)))
+
+	var fresh LoginAttempts
+	assert.False(t, fresh.Throttled(testThrottlePolicy, now))
+}
+
+func TestNewAccountUnlockToken(t *testing.T) {
+	userID := uuid.New()
+
+	token, err := NewAccountUnlockToken(userID, time.Hour)
+	require.NoError(t, err)
+	assert.NotEmpty(t, token.Value)
+	assert.Equal(t, userID, token.UserID)
+	assert.False(t, token.Expired())
+	assert.False(t, token.Used())
+
+	token.Revoke()
+	assert.True(t, token.Used())
+
+	expired, err := NewAccountUnlockToken(userID, -time.Hour)
+	require.NoError(t, err)
+	assert.True(t, expired.Expired())
+}
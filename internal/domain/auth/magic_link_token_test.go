@@ -0,0 +1,35 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMagicLinkToken(t *testing.T) {
+	userID := uuid.New()
+
+	token, err := NewMagicLinkToken(userID, 15*time.Minute)
+	require.NoError(t, err)
+	assert.NotEmpty(t, token.Value)
+	assert.Equal(t, userID, token.UserID)
+	assert.False(t, token.Expired())
+	assert.False(t, token.Used())
+}
+
+func TestMagicLinkTokenExpired(t *testing.T) {
+	token, err := NewMagicLinkToken(uuid.New(), -time.Minute)
+	require.NoError(t, err)
+	assert.True(t, token.Expired())
+}
+
+func TestMagicLinkTokenRevoke(t *testing.T) {
+	token, err := NewMagicLinkToken(uuid.New(), 15*time.Minute)
+	require.NoError(t, err)
+
+	token.Revoke()
+	assert.True(t, token.Used())
+}
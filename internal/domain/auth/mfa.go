@@ -0,0 +1,246 @@
+// Package auth provides authentication and authorization functionality.
+// This package handles user authentication through sessions, access tokens, and
+// password management including secure hashing and password reset flows. It manages
+// the complete authentication lifecycle from login through logout, including token
+// generation, validation, and session management.
+package auth
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"image/png"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+	"github.com/prawirdani/golang-restapi/internal/domain"
+	"github.com/prawirdani/golang-restapi/pkg/nullable"
+)
+
+const (
+	totpPeriod           = 30  // Seconds a TOTP code is valid for
+	totpQRCodeSize       = 256 // Width and height of the enrolment QR code in pixels
+	recoveryCodeCount    = 10
+	maxMFAChallengeTries = 5
+)
+
+var totpOpts = totp.ValidateOpts{
+	Period:    totpPeriod,
+	Digits:    otp.DigitsSix,
+	Algorithm: otp.AlgorithmSHA1,
+}
+
+var (
+	ErrMFAAlreadyEnabled     = domain.ErrDuplicate("Two-factor authentication is already enabled")
+	ErrMFANotEnabled         = domain.ErrNotFound("Two-factor authentication is not enabled")
+	ErrMFACodeInvalid        = domain.ErrUnauthorized("Invalid authentication code")
+	ErrMFAChallengeInvalid   = domain.ErrUnauthorized("The MFA challenge is invalid or expired, please login again")
+	ErrMFAChallengeNotFound  = domain.ErrNotFound("MFA challenge not found")
+	ErrTOTPFactorNotFound    = domain.ErrNotFound("TOTP factor not found")
+	ErrRecoveryCodeNotFound  = domain.ErrNotFound("Recovery code not found")
+	ErrTOTPEnrollmentMissing = domain.ErrValidation("Start TOTP enrolment before confirming it")
+)
+
+// TOTPFactor is a time-based one-time password second factor enrolled by a user.
+// A factor only protects the account once it has been confirmed with a valid code,
+// which proves the authenticator app has been set up correctly.
+type TOTPFactor struct {
+	UserID      uuid.UUID                    `db:"user_id"`
+	Secret      string                       `db:"secret"`
+	ConfirmedAt nullable.Nullable[time.Time] `db:"confirmed_at"`
+	// LastUsedStep is the TOTP time step of the last accepted code, used to reject replayed codes.
+	LastUsedStep int64     `db:"last_used_step"`
+	CreatedAt    time.Time `db:"created_at"`
+}
+
+// TOTPEnrollment holds what an authenticator app needs to register a new TOTP factor.
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`     // otpauth:// key URI
+	QRCode string `json:"qr_code"` // PNG data URI encoding the key URI
+}
+
+// NewTOTPFactor generates a new unconfirmed TOTP factor for the given user, along with its enrolment payload.
+func NewTOTPFactor(userID uuid.UUID, issuer, account string) (*TOTPFactor, *TOTPEnrollment, error) {
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      issuer,
+		AccountName: account,
+		Period:      totpPeriod,
+		Digits:      totpOpts.Digits,
+		Algorithm:   totpOpts.Algorithm,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	img, err := key.Image(totpQRCodeSize, totpQRCodeSize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, nil, err
+	}
+
+	factor := &TOTPFactor{
+		UserID:    userID,
+		Secret:    key.Secret(),
+		CreatedAt: time.Now(),
+	}
+
+	enrollment := &TOTPEnrollment{
+		Secret: key.Secret(),
+		URI:    key.URL(),
+		QRCode: "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()),
+	}
+
+	return factor, enrollment, nil
+}
+
+// Confirmed reports whether the factor has been confirmed and is active.
+func (f TOTPFactor) Confirmed() bool {
+	return f.ConfirmedAt.NotNull()
+}
+
+// Confirm activates the factor.
+func (f *TOTPFactor) Confirm() {
+	f.ConfirmedAt = nullable.New(time.Now(), false)
+}
+
+// Verify checks the code against the current time step and one step on either side to tolerate clock drift.
+// A code from a time step that was already used is rejected; on success LastUsedStep is advanced.
+func (f *TOTPFactor) Verify(code string, now time.Time) bool {
+	current := now.Unix() / totpPeriod
+	for _, step := range []int64{current, current - 1, current + 1} {
+		if step <= f.LastUsedStep {
+			continue
+		}
+
+		expected, err := totp.GenerateCodeCustom(f.Secret, time.Unix(step*totpPeriod, 0), totpOpts)
+		if err != nil {
+			return false
+		}
+
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			f.LastUsedStep = step
+			return true
+		}
+	}
+
+	return false
+}
+
+// RecoveryCode is a single-use backup code that can replace a TOTP code when the authenticator is unavailable.
+// Only the hash of the code is stored.
+type RecoveryCode struct {
+	UserID   uuid.UUID                    `db:"user_id"`
+	CodeHash string                       `db:"code_hash"`
+	UsedAt   nullable.Nullable[time.Time] `db:"used_at"`
+}
+
+// NewRecoveryCodes generates a fresh set of recovery codes for the user.
+// The plain codes are returned once to be shown to the user, the hashed codes are meant for storage.
+func NewRecoveryCodes(userID uuid.UUID) ([]string, []*RecoveryCode, error) {
+	plain := make([]string, 0, recoveryCodeCount)
+	codes := make([]*RecoveryCode, 0, recoveryCodeCount)
+
+	for range recoveryCodeCount {
+		bs := make([]byte, 8)
+		if _, err := rand.Read(bs); err != nil {
+			return nil, nil, err
+		}
+
+		raw := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(bs))[:10]
+		code := raw[:5] + "-" + raw[5:]
+
+		plain = append(plain, code)
+		codes = append(codes, &RecoveryCode{
+			UserID:   userID,
+			CodeHash: HashRecoveryCode(code),
+		})
+	}
+
+	return plain, codes, nil
+}
+
+// HashRecoveryCode normalizes and hashes a recovery code. Codes carry enough entropy that a fast hash is sufficient.
+func HashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// Used reports whether the recovery code has already been used.
+func (c RecoveryCode) Used() bool {
+	return c.UsedAt.NotNull()
+}
+
+// Revoke marks the recovery code as used immediately.
+func (c *RecoveryCode) Revoke() {
+	c.UsedAt = nullable.New(time.Now(), false)
+}
+
+// MFAChallenge is a short-lived token issued by Login once the password has been verified for a user with a
+// second factor enabled. The login is completed by posting the challenge along with a valid code.
+type MFAChallenge struct {
+	Value       string                       `db:"value"      json:"mfa_token"`
+	UserID      uuid.UUID                    `db:"user_id"    json:"-"`
+	UserAgent   string                       `db:"user_agent"   json:"-"`
+	FirstFactor string                       `db:"first_factor" json:"-"` // AMR value of the factor already passed
+	Attempts    int                          `db:"attempts"     json:"-"`
+	ExpiresAt   time.Time                    `db:"expires_at"   json:"expires_at"`
+	UsedAt      nullable.Nullable[time.Time] `db:"used_at"      json:"-"`
+	Methods     []string                     `db:"-"            json:"methods"` // Second factors the user can pass
+}
+
+// NewMFAChallenge creates a new challenge for the given user, who already passed firstFactor and can complete the
+// login with any of methods, with a specified expiration.
+func NewMFAChallenge(
+	userID uuid.UUID,
+	userAgent string,
+	firstFactor string,
+	methods []string,
+	ttl time.Duration,
+) (*MFAChallenge, error) {
+	bs := make([]byte, 32)
+	if _, err := rand.Read(bs); err != nil {
+		return nil, err
+	}
+
+	return &MFAChallenge{
+		Value:       hex.EncodeToString(bs),
+		UserID:      userID,
+		UserAgent:   userAgent,
+		FirstFactor: firstFactor,
+		ExpiresAt:   time.Now().Add(ttl),
+		Methods:     methods,
+	}, nil
+}
+
+// Expired reports whether the challenge has passed its expiration time.
+func (c MFAChallenge) Expired() bool {
+	return c.ExpiresAt.Before(time.Now())
+}
+
+// Used reports whether the challenge has already been completed.
+func (c MFAChallenge) Used() bool {
+	return c.UsedAt.NotNull()
+}
+
+// Exhausted reports whether too many wrong codes have been posted for the challenge.
+func (c MFAChallenge) Exhausted() bool {
+	return c.Attempts >= maxMFAChallengeTries
+}
+
+// Revoke marks the challenge as used immediately.
+func (c *MFAChallenge) Revoke() {
+	c.UsedAt = nullable.New(time.Now(), false)
+}
@@ -0,0 +1,359 @@
+// Package auth provides authentication and authorization functionality.
+// This package handles user authentication through sessions, access tokens, and
+// password management including secure hashing and password reset flows. It manages
+// the complete authentication lifecycle from login through logout, including token
+// generation, validation, and session management.
+package auth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prawirdani/golang-restapi/internal/domain/audit"
+	"github.com/prawirdani/golang-restapi/internal/domain/user"
+	"github.com/prawirdani/golang-restapi/pkg/log"
+)
+
+// EnrollTOTP starts the TOTP enrolment for the user, returning the secret and QR payload for an authenticator app.
+// The factor stays inactive until it is confirmed with [Service.ConfirmTOTP].
+func (s *Service) EnrollTOTP(ctx context.Context, userID string) (*TOTPEnrollment, error) {
+	usr, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled, err := s.totpEnabled(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if enabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	factor, enrollment, err := NewTOTPFactor(usr.ID, s.cfg.MFAIssuer, usr.Email)
+	if err != nil {
+		log.ErrorCtx(ctx, "Failed to create totp factor", err)
+		return nil, err
+	}
+
+	if err := s.authRepo.StoreTOTPFactor(ctx, factor); err != nil {
+		return nil, err
+	}
+
+	if err := s.recordEvent(ctx, audit.EventTOTPEnrolled, userID, nil); err != nil {
+		return nil, err
+	}
+
+	return enrollment, nil
+}
+
+// ConfirmTOTP activates the pending TOTP factor after verifying a code from the authenticator app.
+// Returns a fresh set of recovery codes, which are only ever shown once.
+func (s *Service) ConfirmTOTP(
+	ctx context.Context,
+	userID string,
+	inp ConfirmTOTPInput,
+) ([]string, error) {
+	var recoveryCodes []string
+	err := s.transactor.Transact(ctx, func(ctx context.Context) error {
+		factor, err := s.authRepo.GetTOTPFactor(ctx, userID)
+		if err != nil {
+			if errors.Is(err, ErrTOTPFactorNotFound) {
+				return ErrTOTPEnrollmentMissing
+			}
+			return err
+		}
+
+		if factor.Confirmed() {
+			return ErrMFAAlreadyEnabled
+		}
+
+		if !factor.Verify(inp.Code, time.Now()) {
+			return ErrMFACodeInvalid
+		}
+
+		factor.Confirm()
+		if err := s.authRepo.UpdateTOTPFactor(ctx, factor); err != nil {
+			return err
+		}
+
+		recoveryCodes, err = s.replaceRecoveryCodes(ctx, factor.UserID)
+		if err != nil {
+			return err
+		}
+
+		return s.recordEvent(ctx, audit.EventTOTPEnabled, userID, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+// DisableTOTP removes the user's TOTP factor after re-confirming the password. The recovery codes are removed as
+// well, unless the user still has passkeys they back up.
+func (s *Service) DisableTOTP(ctx context.Context, userID string, inp DisableTOTPInput) error {
+	usr, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.confirmPassword(ctx, usr, inp.Password, inp.IPAddress); err != nil {
+		return err
+	}
+
+	return s.transactor.Transact(ctx, func(ctx context.Context) error {
+		if err := s.authRepo.DeleteTOTPFactor(ctx, userID); err != nil {
+			return err
+		}
+
+		if err := s.recordEvent(ctx, audit.EventTOTPDisabled, userID, nil); err != nil {
+			return err
+		}
+
+		hasPasskeys, err := s.hasWebAuthnCredentials(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		if hasPasskeys {
+			return nil
+		}
+
+		return s.authRepo.DeleteRecoveryCodes(ctx, userID)
+	})
+}
+
+// RegenerateRecoveryCodes invalidates all existing recovery codes and issues a new set,
+// the request must be authorized with a current TOTP code.
+func (s *Service) RegenerateRecoveryCodes(
+	ctx context.Context,
+	userID string,
+	inp RegenerateRecoveryCodesInput,
+) ([]string, error) {
+	var recoveryCodes []string
+	err := s.transactor.Transact(ctx, func(ctx context.Context) error {
+		factor, err := s.authRepo.GetTOTPFactor(ctx, userID)
+		if err != nil {
+			if errors.Is(err, ErrTOTPFactorNotFound) {
+				return ErrMFANotEnabled
+			}
+			return err
+		}
+
+		if !factor.Confirmed() {
+			return ErrMFANotEnabled
+		}
+
+		if !factor.Verify(inp.Code, time.Now()) {
+			return ErrMFACodeInvalid
+		}
+
+		if err := s.authRepo.UpdateTOTPFactor(ctx, factor); err != nil {
+			return err
+		}
+
+		recoveryCodes, err = s.replaceRecoveryCodes(ctx, factor.UserID)
+		if err != nil {
+			return err
+		}
+
+		return s.recordEvent(ctx, audit.EventRecoveryCodesRenewed, userID, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+// VerifyMFA completes a login started by [Service.Login] using a TOTP code, a recovery code or a passkey.
+// Wrong codes are counted against the challenge, which becomes unusable after too many attempts, and like failed
+// logins against the account and the client IP.
+func (s *Service) VerifyMFA(ctx context.Context, inp VerifyMFAInput) (*LoginResult, error) {
+	if err := s.checkIPThrottle(ctx, inp.IPAddress); err != nil {
+		return nil, err
+	}
+
+	var (
+		usr    *user.User
+		result *LoginResult
+	)
+	err := s.transactor.Transact(ctx, func(ctx context.Context) error {
+		challenge, err := s.authRepo.GetMFAChallenge(ctx, inp.MFAToken)
+		if err != nil {
+			if errors.Is(err, ErrMFAChallengeNotFound) {
+				return ErrMFAChallengeInvalid
+			}
+			return err
+		}
+
+		if challenge.Expired() || challenge.Used() || challenge.Exhausted() {
+			return ErrMFAChallengeInvalid
+		}
+
+		usr, err = s.userRepo.GetByID(ctx, challenge.UserID.String())
+		if err != nil {
+			return err
+		}
+
+		// The account may have been locked by failures of other logins while the challenge was pending
+		if err := s.checkAccountThrottle(ctx, usr.ID.String()); err != nil {
+			return err
+		}
+
+		amr, err := s.verifySecondFactor(ctx, challenge.UserID.String(), inp)
+		if err != nil {
+			return err
+		}
+
+		if amr == "" {
+			// Persist the failed attempt, the transaction must commit for it to count
+			challenge.Attempts++
+			if err := s.authRepo.UpdateMFAChallenge(ctx, challenge); err != nil {
+				return err
+			}
+
+			return s.recordEvent(ctx, audit.EventMFAFailed, challenge.UserID.String(), audit.Metadata{
+				"attempts": challenge.Attempts,
+			})
+		}
+
+		challenge.Revoke()
+		if err := s.authRepo.UpdateMFAChallenge(ctx, challenge); err != nil {
+			return err
+		}
+
+		// The account may have been suspended or flagged for a password reset while the challenge was pending
+		if err := s.checkLoginAllowed(usr); err != nil {
+			return err
+		}
+
+		result, err = s.createSession(
+			ctx,
+			usr,
+			inp.UserAgent,
+			inp.IPAddress,
+			challenge.FirstFactor,
+			amr,
+			AMRMultiFactor,
+		)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if result == nil {
+		if err := s.recordLoginFailure(ctx, usr, inp.IPAddress); err != nil {
+			return nil, err
+		}
+		return nil, ErrMFACodeInvalid
+	}
+
+	if err := s.resetAccountFailures(ctx, usr.ID.String()); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// verifySecondFactor checks the passkey assertion, recovery code or TOTP code of the input, in that order, for the
+// user. Returns the AMR value of the verified factor, or an empty string when verification failed.
+// A matching recovery code is consumed, a matching TOTP code advances the factor's last used step.
+// Must be called inside a transaction.
+func (s *Service) verifySecondFactor(ctx context.Context, userID string, inp VerifyMFAInput) (string, error) {
+	if len(inp.Credential) > 0 {
+		verified, err := s.verifyPasskey(ctx, userID, inp.ChallengeID, inp.Credential)
+		if err != nil || !verified {
+			return "", err
+		}
+		return AMRHardwareKey, nil
+	}
+
+	if inp.RecoveryCode != "" {
+		rc, err := s.authRepo.GetRecoveryCode(ctx, userID, HashRecoveryCode(inp.RecoveryCode))
+		if err != nil {
+			if errors.Is(err, ErrRecoveryCodeNotFound) {
+				return "", nil
+			}
+			return "", err
+		}
+
+		if rc.Used() {
+			return "", nil
+		}
+
+		rc.Revoke()
+		return AMROneTimeCode, s.authRepo.UpdateRecoveryCode(ctx, rc)
+	}
+
+	factor, err := s.authRepo.GetTOTPFactor(ctx, userID)
+	if err != nil {
+		if errors.Is(err, ErrTOTPFactorNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	if !factor.Confirmed() || !factor.Verify(inp.Code, time.Now()) {
+		return "", nil
+	}
+
+	return AMROneTimeCode, s.authRepo.UpdateTOTPFactor(ctx, factor)
+}
+
+// mfaMethods returns the second factors the user has enabled, none when the user signs in with a single factor.
+func (s *Service) mfaMethods(ctx context.Context, userID string) ([]string, error) {
+	var methods []string
+
+	totp, err := s.totpEnabled(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if totp {
+		methods = append(methods, MFAMethodTOTP)
+	}
+
+	passkeys, err := s.hasWebAuthnCredentials(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if passkeys {
+		methods = append(methods, MFAMethodWebAuthn)
+	}
+
+	return methods, nil
+}
+
+// totpEnabled reports whether the user has a confirmed TOTP factor.
+func (s *Service) totpEnabled(ctx context.Context, userID string) (bool, error) {
+	factor, err := s.authRepo.GetTOTPFactor(ctx, userID)
+	if err != nil {
+		if errors.Is(err, ErrTOTPFactorNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return factor.Confirmed(), nil
+}
+
+// replaceRecoveryCodes generates and stores a new set of recovery codes, returning the plain codes.
+func (s *Service) replaceRecoveryCodes(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	plain, codes, err := NewRecoveryCodes(userID)
+	if err != nil {
+		log.ErrorCtx(ctx, "Failed to generate recovery codes", err)
+		return nil, err
+	}
+
+	if err := s.authRepo.StoreRecoveryCodes(ctx, userID.String(), codes); err != nil {
+		return nil, err
+	}
+
+	return plain, nil
+}
@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTOTPFactor(t *testing.T) {
+	userID := uuid.New()
+
+	factor, enrollment, err := NewTOTPFactor(userID, "golang-restapi", "john@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, userID, factor.UserID)
+	assert.NotEmpty(t, factor.Secret)
+	assert.False(t, factor.Confirmed())
+	assert.Equal(t, factor.Secret, enrollment.Secret)
+	assert.True(t, strings.HasPrefix(enrollment.URI, "otpauth://totp/"))
+	assert.Contains(t, enrollment.URI, "issuer=golang-restapi")
+	assert.True(t, strings.HasPrefix(enrollment.QRCode, "data:image/png;base64,"))
+}
+
+func TestTOTPFactorVerify(t *testing.T) {
+	factor, _, err := NewTOTPFactor(uuid.New(), "golang-restapi", "john@example.com")
+	require.NoError(t, err)
+
+	now := time.Now()
+	code, err := totp.GenerateCodeCustom(factor.Secret, now, totpOpts)
+	require.NoError(t, err)
+
+	t.Run("invalid code", func(t *testing.T) {
+		assert.False(t, factor.Verify("abcdef", now))
+		assert.Zero(t, factor.LastUsedStep)
+	})
+
+	t.Run("valid code", func(t *testing.T) {
+		assert.True(t, factor.Verify(code, now))
+		assert.Equal(t, now.Unix()/totpPeriod, factor.LastUsedStep)
+	})
+
+	t.Run("replayed code", func(t *testing.T) {
+		assert.False(t, factor.Verify(code, now))
+	})
+
+	t.Run("clock drift", func(t *testing.T) {
+		next := now.Add(totpPeriod * time.Second)
+		nextCode, err := totp.GenerateCodeCustom(factor.Secret, next, totpOpts)
+		require.NoError(t, err)
+		assert.True(t, factor.Verify(nextCode, now))
+	})
+}
+
+func TestNewRecoveryCodes(t *testing.T) {
+	userID := uuid.New()
+
+	plain, codes, err := NewRecoveryCodes(userID)
+	require.NoError(t, err)
+	require.Len(t, plain, recoveryCodeCount)
+	require.Len(t, codes, recoveryCodeCount)
+
+	for i, code := range plain {
+		assert.Len(t, code, 11)
+		assert.Equal(t, userID, codes[i].UserID)
+		assert.Equal(t, HashRecoveryCode(code), codes[i].CodeHash)
+		assert.False(t, codes[i].Used())
+	}
+}
+
+func TestHashRecoveryCode(t *testing.T) {
+	assert.Equal(t, HashRecoveryCode("abcde-fghij"), HashRecoveryCode("ABCDEFGHIJ"))
+	assert.NotEqual(t, HashRecoveryCode("abcde-fghij"), HashRecoveryCode("abcde-fghik"))
+}
+
+func TestMFAChallenge(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("new challenge", func(t *testing.T) {
+		challenge, err := NewMFAChallenge(userID, "test-agent", AMRPassword, []string{MFAMethodTOTP}, time.Minute)
+		require.NoError(t, err)
+		assert.NotEmpty(t, challenge.Value)
+		assert.Equal(t, userID, challenge.UserID)
+		assert.False(t, challenge.Expired())
+		assert.False(t, challenge.Used())
+		assert.False(t, challenge.Exhausted())
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		challenge, err := NewMFAChallenge(userID, "test-agent", AMRPassword, []string{MFAMethodTOTP}, -time.Minute)
+		require.NoError(t, err)
+		assert.True(t, challenge.Expired())
+	})
+
+	t.Run("exhausted", func(t *testing.T) {
+		challenge, err := NewMFAChallenge(userID, "test-agent", AMRPassword, []string{MFAMethodTOTP}, time.Minute)
+		require.NoError(t, err)
+		challenge.Attempts = maxMFAChallengeTries
+		assert.True(t, challenge.Exhausted())
+	})
+
+	t.Run("revoke", func(t *testing.T) {
+		challenge, err := NewMFAChallenge(userID, "test-agent", AMRPassword, []string{MFAMethodTOTP}, time.Minute)
+		require.NoError(t, err)
+		challenge.Revoke()
+		assert.True(t, challenge.Used())
+	})
+}
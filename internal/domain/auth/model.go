@@ -6,6 +6,7 @@
 package auth
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/prawirdani/golang-restapi/pkg/strings"
@@ -16,8 +17,8 @@ type RegisterInput struct {
 	Name           string `json:"name"            validate:"required"`
 	Email          string `json:"email"           validate:"required,email"`
 	Phone          string `json:"phone"`
-	Password       string `json:"password"        validate:"required,min=8"`
-	RepeatPassword string `json:"repeat_password" validate:"required,eqfield=Password,min=8"`
+	Password       string `json:"password"        validate:"required"`
+	RepeatPassword string `json:"repeat_password" validate:"required,eqfield=Password"`
 }
 
 // Validate implements [handler.JSONRequestBody]
@@ -37,6 +38,7 @@ type LoginInput struct {
 	Email     string `json:"email"    validate:"required,email"`
 	Password  string `json:"password" validate:"required"`
 	UserAgent string
+	IPAddress string `json:"-"`
 }
 
 type ForgotPasswordInput struct {
@@ -45,14 +47,213 @@ type ForgotPasswordInput struct {
 
 type ResetPasswordInput struct {
 	Token             string `json:"token"               validate:"required"`
-	NewPassword       string `json:"new_password"        validate:"required,min=8"`
+	NewPassword       string `json:"new_password"        validate:"required"`
 	RepeatNewPassword string `json:"repeat_new_password" validate:"required,eqfield=NewPassword"`
 }
 
 type ChangePasswordInput struct {
 	Password          string
-	NewPassword       string `json:"new_password"        validate:"required,min=8"`
+	NewPassword       string `json:"new_password"        validate:"required"`
 	RepeatNewPassword string `json:"repeat_new_password" validate:"required,eqfield=NewPassword"`
+	SessionID         string `json:"-"` // Session family kept signed in after the change
+	IPAddress         string `json:"-"`
+}
+
+type DeleteAccountInput struct {
+	Password  string `json:"password"` // Required unless the account has no password
+	IPAddress string `json:"-"`
+}
+
+type UnlockAccountInput struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type RevokeUnrecognizedSessionInput struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type VerifyEmailInput struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type ConfirmEmailChangeInput struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type CreatePersonalAccessTokenInput struct {
+	Name          string   `json:"name"            validate:"required,max=100"`
+	Scopes        []string `json:"scopes"          validate:"omitempty,dive,required,max=50"`
+	ExpiresInDays int      `json:"expires_in_days" validate:"omitempty,min=1,max=365"` // Never expires when omitted
+}
+
+// CreatedPersonalAccessToken is a newly created personal access token along with its plain value,
+// which is only ever returned here.
+type CreatedPersonalAccessToken struct {
+	*PersonalAccessToken
+	Token string `json:"token"`
+}
+
+type ResendVerificationEmailInput struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type MagicLinkInput struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type VerifyMagicLinkInput struct {
+	Token     string `json:"token" validate:"required"`
+	UserAgent string `json:"-"`
+	IPAddress string `json:"-"`
+}
+
+type CompleteExternalLoginInput struct {
+	Provider  string `json:"-"`
+	Code      string `json:"-"`
+	Verifier  string `json:"-"`
+	Nonce     string `json:"-"`
+	UserAgent string `json:"-"`
+	IPAddress string `json:"-"`
+}
+
+type RegisterOAuthClientInput struct {
+	Name         string   `json:"name"          validate:"required,max=100"`
+	RedirectURIs []string `json:"redirect_uris" validate:"omitempty,dive,required,url"`
+	GrantTypes   []string `json:"grant_types"   validate:"required,min=1,dive,oneof=authorization_code refresh_token client_credentials"`
+	Scopes       []string `json:"scopes"        validate:"omitempty,dive,required,max=50"`
+	Confidential bool     `json:"confidential"` // Public clients get no secret and must use PKCE
+}
+
+// CreatedOAuthClient is a newly registered client along with its plain secret, which is only ever returned here.
+type CreatedOAuthClient struct {
+	*OAuthClient
+	ClientSecret string `json:"client_secret,omitempty"`
+}
+
+// OAuthAuthorizeInput holds the query parameters of an authorization request, RFC 6749 section 4.1.1.
+type OAuthAuthorizeInput struct {
+	ResponseType        string
+	ClientID            string
+	RedirectURI         string
+	Scope               string
+	State               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+}
+
+// OAuthTokenInput holds the form parameters of a token request along with the client credentials, which are
+// taken from either the Authorization header or the form.
+type OAuthTokenInput struct {
+	GrantType    string
+	Code         string
+	RedirectURI  string
+	CodeVerifier string
+	RefreshToken string
+	Scope        string
+	ClientID     string
+	ClientSecret string
+}
+
+// VerifyMFAInput completes a login with one of a TOTP code, a recovery code, or a passkey assertion answering the
+// challenge started through [Service.BeginMFAPasskey].
+type VerifyMFAInput struct {
+	MFAToken     string          `json:"mfa_token"     validate:"required"`
+	Code         string          `json:"code"          validate:"required_without_all=RecoveryCode Credential"`
+	RecoveryCode string          `json:"recovery_code" validate:"required_without_all=Code Credential"`
+	ChallengeID  string          `json:"challenge_id"  validate:"required_with=Credential"`
+	Credential   json.RawMessage `json:"credential"    validate:"required_with=ChallengeID"`
+	UserAgent    string          `json:"-"`
+	IPAddress    string          `json:"-"`
+}
+
+type BeginMFAPasskeyInput struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+}
+
+// FinishPasskeyRegistrationInput carries the PublicKeyCredential returned by navigator.credentials.create().
+type FinishPasskeyRegistrationInput struct {
+	ChallengeID string          `json:"challenge_id" validate:"required"`
+	Name        string          `json:"name"         validate:"omitempty,max=100"`
+	Credential  json.RawMessage `json:"credential"   validate:"required"`
+}
+
+// FinishPasskeyLoginInput carries the PublicKeyCredential returned by navigator.credentials.get().
+type FinishPasskeyLoginInput struct {
+	ChallengeID string          `json:"challenge_id" validate:"required"`
+	Credential  json.RawMessage `json:"credential"   validate:"required"`
+	UserAgent   string          `json:"-"`
+	IPAddress   string          `json:"-"`
+}
+
+// WebAuthnOptions are the options to pass to navigator.credentials.create() or navigator.credentials.get().
+// The challenge ID has to be sent back along with the resulting credential.
+type WebAuthnOptions struct {
+	ChallengeID string          `json:"challenge_id"`
+	Options     json.RawMessage `json:"options"`
+	ExpiresAt   time.Time       `json:"expires_at"`
+}
+
+// RegisteredPasskey is a newly registered credential. When it is the user's first second factor, it comes with
+// a set of recovery codes, which are only ever shown once.
+type RegisteredPasskey struct {
+	*WebAuthnCredential
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
+
+type ConfirmTOTPInput struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+type DisableTOTPInput struct {
+	Password  string `json:"password" validate:"required"`
+	IPAddress string `json:"-"`
+}
+
+type RegenerateRecoveryCodesInput struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+type AssignRoleInput struct {
+	Role string `json:"role" validate:"required"`
+}
+
+// LoginResult is the outcome of a successful credential check. It either carries the new session tokens, or, when
+// the user has a second factor enabled, only the MFA challenge that must be completed to obtain them.
+type LoginResult struct {
+	AccessToken  string
+	SessionID    string
+	MFAChallenge *MFAChallenge
+}
+
+// MFARequired reports whether the login must be completed with a second factor.
+func (r LoginResult) MFARequired() bool {
+	return r.MFAChallenge != nil
+}
+
+// ReauthenticateInput proves the user's credentials with one of their password, a TOTP code, a recovery code, or a
+// passkey assertion answering the challenge started through [Service.BeginReauthenticationPasskey].
+type ReauthenticateInput struct {
+	Password     string          `json:"password"      validate:"required_without_all=Code RecoveryCode Credential"`
+	Code         string          `json:"code"          validate:"required_without_all=Password RecoveryCode Credential"`
+	RecoveryCode string          `json:"recovery_code" validate:"required_without_all=Password Code Credential"`
+	ChallengeID  string          `json:"challenge_id"  validate:"required_with=Credential"`
+	Credential   json.RawMessage `json:"credential"    validate:"required_with=ChallengeID"`
+	SessionID    string          `json:"-"` // Session family whose authentication time is refreshed
+	IPAddress    string          `json:"-"`
+}
+
+type ImpersonateInput struct {
+	Reason string `json:"reason" validate:"required,max=500"` // Recorded in the audit log, e.g. a support ticket
+}
+
+// ImpersonationResult is an access token to act as another user, it can't be refreshed.
+type ImpersonationResult struct {
+	AccessToken string    `json:"access_token"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+type SwitchOrganizationInput struct {
+	Organization string `json:"organization" validate:"omitempty,max=50"` // ID or slug, empty leaves none active
 }
 
 type TokenPair struct {
@@ -66,3 +267,54 @@ type ResetPasswordEmailMessage struct {
 	ResetURL string        `json:"reset_url"`  // Link for resetting the password
 	Expiry   time.Duration `json:"expiry_min"` // Expiration time of the reset token in minutes
 }
+
+type VerificationEmailMessage struct {
+	To              string        `json:"to"`               // Recipient's email address
+	Name            string        `json:"name"`             // Recipient's name
+	VerificationURL string        `json:"verification_url"` // Link for verifying the email address
+	Expiry          time.Duration `json:"expiry_min"`       // Expiration time of the verification token in minutes
+}
+
+type EmailChangeConfirmationMessage struct {
+	To              string        `json:"to"`               // New email address to confirm
+	Name            string        `json:"name"`             // Recipient's name
+	ConfirmationURL string        `json:"confirmation_url"` // Link for confirming the new address
+	Expiry          time.Duration `json:"expiry_min"`       // Expiration time of the change token in minutes
+}
+
+type EmailChangeNotificationMessage struct {
+	To       string `json:"to"`        // Current email address of the user
+	Name     string `json:"name"`      // Recipient's name
+	NewEmail string `json:"new_email"` // Address the email is being changed to
+}
+
+type AccountLockedEmailMessage struct {
+	To          string        `json:"to"`           // Recipient's email address
+	Name        string        `json:"name"`         // Recipient's name
+	IPAddress   string        `json:"ip_address"`   // Client IP of the failed attempt that locked the account
+	LockedUntil time.Time     `json:"locked_until"` // Time the lockout ends on its own
+	UnlockURL   string        `json:"unlock_url"`   // Link for unlocking the account right away
+	Expiry      time.Duration `json:"expiry_min"`   // Expiration time of the unlock token in minutes
+}
+
+type AccountExistsEmailMessage struct {
+	To   string `json:"to"`   // Email address someone tried to register with
+	Name string `json:"name"` // Recipient's name
+}
+
+type NewDeviceLoginEmailMessage struct {
+	To        string        `json:"to"`         // Recipient's email address
+	Name      string        `json:"name"`       // Recipient's name
+	UserAgent string        `json:"user_agent"` // User-Agent header of the login
+	IPAddress string        `json:"ip_address"` // Client IP of the login
+	LoginAt   time.Time     `json:"login_at"`   // Time of the login
+	RevokeURL string        `json:"revoke_url"` // Link for revoking the session of the login
+	Expiry    time.Duration `json:"expiry_min"` // Expiration time of the revoke token in minutes
+}
+
+type MagicLinkEmailMessage struct {
+	To       string        `json:"to"`         // Recipient's email address
+	Name     string        `json:"name"`       // Recipient's name
+	LoginURL string        `json:"login_url"`  // Link for logging in
+	Expiry   time.Duration `json:"expiry_min"` // Expiration time of the magic link token in minutes
+}
@@ -0,0 +1,178 @@
+// Package auth provides authentication and authorization functionality.
+// This package handles user authentication through sessions, access tokens, and
+// password management including secure hashing and password reset flows. It manages
+// the complete authentication lifecycle from login through logout, including token
+// generation, validation, and session management.
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prawirdani/golang-restapi/internal/domain"
+)
+
+// OAuth2 grant types supported by the authorization server.
+const (
+	GrantTypeAuthorizationCode = "authorization_code"
+	GrantTypeRefreshToken      = "refresh_token"
+	GrantTypeClientCredentials = "client_credentials"
+)
+
+// Error codes of RFC 6749 section 5.2 and 4.1.2.1.
+const (
+	OAuthErrInvalidRequest       = "invalid_request"
+	OAuthErrInvalidClient        = "invalid_client"
+	OAuthErrInvalidGrant         = "invalid_grant"
+	OAuthErrUnauthorizedClient   = "unauthorized_client"
+	OAuthErrUnsupportedGrantType = "unsupported_grant_type"
+	OAuthErrUnsupportedResponse  = "unsupported_response_type"
+	OAuthErrInvalidScope         = "invalid_scope"
+	OAuthErrAccessDenied         = "access_denied"
+)
+
+// PermissionOAuthClientsWrite allows registering and deleting OAuth clients.
+const PermissionOAuthClientsWrite = "oauth_clients:write"
+
+// OAuthClientSecretPrefix marks a credential as an OAuth client secret.
+const OAuthClientSecretPrefix = "ocs_"
+
+var (
+	ErrOAuthClientNotFound = domain.ErrNotFound("OAuth client not found")
+	ErrOAuthClientScope    = domain.ErrValidation("Client scopes must be permissions you currently hold")
+
+	ErrOAuthClientRedirectURIs = domain.ErrValidation(
+		"Clients using the authorization code grant need at least one redirect URI",
+	)
+	ErrOAuthPublicClientCredentials = domain.ErrValidation(
+		"Public clients can't use the client credentials grant",
+	)
+)
+
+// OAuthError is an error response of the authorization server, reported to clients as defined by RFC 6749
+// rather than through the regular error body.
+type OAuthError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
+
+func NewOAuthError(code, description string) *OAuthError {
+	return &OAuthError{Code: code, Description: description}
+}
+
+func (e *OAuthError) Error() string {
+	if e.Description == "" {
+		return e.Code
+	}
+	return e.Code + ": " + e.Description
+}
+
+// OAuthClient is an application registered to obtain tokens from the authorization server. Confidential clients
+// authenticate with a secret, of which only the hash is stored. Public clients, such as single page or mobile
+// apps, can't keep a secret and must use PKCE instead.
+type OAuthClient struct {
+	ID           string    `db:"id"            json:"client_id"`
+	Name         string    `db:"name"          json:"name"`
+	SecretHash   string    `db:"secret_hash"   json:"-"`
+	RedirectURIs []string  `db:"redirect_uris" json:"redirect_uris"`
+	GrantTypes   []string  `db:"grant_types"   json:"grant_types"`
+	Scopes       []string  `db:"scopes"        json:"scopes"`
+	CreatedAt    time.Time `db:"created_at"    json:"created_at"`
+}
+
+// NewOAuthClient registers a client, returning it along with its plain secret. Public clients get no secret.
+func NewOAuthClient(
+	name string,
+	redirectURIs, grantTypes, scopes []string,
+	confidential bool,
+) (*OAuthClient, string, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, "", err
+	}
+
+	client := &OAuthClient{
+		ID:           id.String(),
+		Name:         name,
+		RedirectURIs: redirectURIs,
+		GrantTypes:   grantTypes,
+		Scopes:       scopes,
+		CreatedAt:    time.Now(),
+	}
+
+	if !confidential {
+		return client, "", nil
+	}
+
+	bs := make([]byte, 32)
+	if _, err := rand.Read(bs); err != nil {
+		return nil, "", err
+	}
+	secret := OAuthClientSecretPrefix + hex.EncodeToString(bs)
+	client.SecretHash = hashOAuthCredential(secret)
+
+	return client, secret, nil
+}
+
+// Confidential reports whether the client authenticates with a secret.
+func (c OAuthClient) Confidential() bool {
+	return c.SecretHash != ""
+}
+
+// VerifySecret reports whether the secret belongs to the client.
+func (c OAuthClient) VerifySecret(secret string) bool {
+	if !c.Confidential() {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(c.SecretHash), []byte(hashOAuthCredential(secret))) == 1
+}
+
+// AllowsRedirectURI reports whether the redirect URI is registered, it must match exactly.
+func (c OAuthClient) AllowsRedirectURI(uri string) bool {
+	return slices.Contains(c.RedirectURIs, uri)
+}
+
+// AllowsGrantType reports whether the client may use the grant type.
+func (c OAuthClient) AllowsGrantType(grantType string) bool {
+	return slices.Contains(c.GrantTypes, grantType)
+}
+
+// ResolveScopes validates the space separated scope parameter against the scopes registered for the client.
+// An empty parameter requests every registered scope.
+func (c OAuthClient) ResolveScopes(scope string) ([]string, error) {
+	requested := ParseScope(scope)
+	if len(requested) == 0 {
+		return slices.Clone(c.Scopes), nil
+	}
+
+	for _, s := range requested {
+		if !slices.Contains(c.Scopes, s) {
+			return nil, NewOAuthError(OAuthErrInvalidScope, "Scope "+s+" is not allowed for this client")
+		}
+	}
+	return requested, nil
+}
+
+// ParseScope splits a space separated scope parameter, dropping duplicates.
+func ParseScope(scope string) []string {
+	var scopes []string
+	for _, s := range strings.Fields(scope) {
+		if !slices.Contains(scopes, s) {
+			scopes = append(scopes, s)
+		}
+	}
+	return scopes
+}
+
+// hashOAuthCredential hashes a client secret, authorization code or refresh token. They carry enough entropy
+// that a fast hash is sufficient.
+func hashOAuthCredential(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
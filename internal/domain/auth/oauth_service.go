@@ -0,0 +1,602 @@
+// Package auth provides authentication and authorization functionality.
+// This package handles user authentication through sessions, access tokens, and
+// password management including secure hashing and password reset flows. It manages
+// the complete authentication lifecycle from login through logout, including token
+// generation, validation, and session management.
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/prawirdani/golang-restapi/config"
+	"github.com/prawirdani/golang-restapi/internal/domain/user"
+	"github.com/prawirdani/golang-restapi/internal/infrastructure/repository"
+	"github.com/prawirdani/golang-restapi/pkg/log"
+)
+
+const defaultOAuthCodeTTL = 10 * time.Minute
+
+// OAuthService is the OAuth2 authorization server other applications delegate their login to. It implements
+// the authorization code grant with mandatory PKCE, the refresh token and client credentials grants, token
+// introspection (RFC 7662) and revocation (RFC 7009). Access tokens are the same JWTs [Service] issues,
+// carrying the client ID and the granted scopes.
+type OAuthService struct {
+	cfg         config.Auth
+	keys        *KeySet
+	transactor  repository.Transactor
+	userRepo    user.Repository
+	oauthRepo   OAuthRepository
+	authorizer  *Authorizer
+	revocations TokenRevocationStore
+}
+
+func NewOAuthService(
+	cfg config.Auth,
+	keys *KeySet,
+	transactor repository.Transactor,
+	userRepo user.Repository,
+	oauthRepo OAuthRepository,
+	roleRepo RoleRepository,
+	revocations TokenRevocationStore,
+) *OAuthService {
+	if cfg.OAuthCodeTTL == 0 {
+		cfg.OAuthCodeTTL = defaultOAuthCodeTTL
+	}
+	if cfg.OAuthAccessTokenTTL == 0 {
+		cfg.OAuthAccessTokenTTL = cfg.JwtTTL
+	}
+	if cfg.OAuthRefreshTokenTTL == 0 {
+		cfg.OAuthRefreshTokenTTL = cfg.SessionTTL
+	}
+
+	return &OAuthService{
+		cfg:         cfg,
+		keys:        keys,
+		transactor:  transactor,
+		userRepo:    userRepo,
+		oauthRepo:   oauthRepo,
+		authorizer:  NewAuthorizer(roleRepo),
+		revocations: revocations,
+	}
+}
+
+// RegisterClient registers a client on behalf of an administrator. Every scope must be covered by the permissions
+// the administrator currently holds, so registering a client can't escalate privileges.
+func (s *OAuthService) RegisterClient(
+	ctx context.Context,
+	adminID string,
+	inp RegisterOAuthClientInput,
+) (*CreatedOAuthClient, error) {
+	grants, err := s.authorizer.Grants(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := inp.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+	for _, scope := range scopes {
+		if !PermissionGranted(grants.Permissions, scope) {
+			return nil, ErrOAuthClientScope
+		}
+	}
+
+	redirectURIs := inp.RedirectURIs
+	if redirectURIs == nil {
+		redirectURIs = []string{}
+	}
+	if slices.Contains(inp.GrantTypes, GrantTypeAuthorizationCode) && len(redirectURIs) == 0 {
+		return nil, ErrOAuthClientRedirectURIs
+	}
+	if slices.Contains(inp.GrantTypes, GrantTypeClientCredentials) && !inp.Confidential {
+		return nil, ErrOAuthPublicClientCredentials
+	}
+
+	client, secret, err := NewOAuthClient(inp.Name, redirectURIs, inp.GrantTypes, scopes, inp.Confidential)
+	if err != nil {
+		log.ErrorCtx(ctx, "Failed to create oauth client", err)
+		return nil, err
+	}
+
+	if err := s.oauthRepo.StoreOAuthClient(ctx, client); err != nil {
+		return nil, err
+	}
+
+	return &CreatedOAuthClient{OAuthClient: client, ClientSecret: secret}, nil
+}
+
+// ListClients returns every registered client.
+func (s *OAuthService) ListClients(ctx context.Context) ([]*OAuthClient, error) {
+	return s.oauthRepo.ListOAuthClients(ctx)
+}
+
+// DeleteClient removes a client, its refresh tokens stop working immediately.
+func (s *OAuthService) DeleteClient(ctx context.Context, clientID string) error {
+	return s.oauthRepo.DeleteOAuthClient(ctx, clientID)
+}
+
+// Authorize handles an authorization request of the signed in user and returns the URL to redirect the user
+// agent to. Errors about the request that can be reported to the client are encoded into that URL. An
+// [*OAuthError] is only returned when the client or the redirect URI can't be trusted, in which case the user
+// must not be redirected.
+//
+// Clients are first-party apps, so the user isn't asked for consent.
+func (s *OAuthService) Authorize(ctx context.Context, userID string, inp OAuthAuthorizeInput) (string, error) {
+	client, err := s.oauthRepo.GetOAuthClient(ctx, inp.ClientID)
+	if err != nil {
+		if errors.Is(err, ErrOAuthClientNotFound) {
+			return "", NewOAuthError(OAuthErrInvalidClient, "Unknown client")
+		}
+		return "", err
+	}
+
+	if inp.RedirectURI == "" || !client.AllowsRedirectURI(inp.RedirectURI) {
+		return "", NewOAuthError(OAuthErrInvalidRequest, "The redirect_uri is not registered for this client")
+	}
+
+	redirect := func(params url.Values) (string, error) {
+		if inp.State != "" {
+			params.Set("state", inp.State)
+		}
+		return appendQuery(inp.RedirectURI, params), nil
+	}
+	redirectErr := func(code, description string) (string, error) {
+		return redirect(url.Values{"error": {code}, "error_description": {description}})
+	}
+
+	if inp.ResponseType != "code" {
+		return redirectErr(OAuthErrUnsupportedResponse, "Only the code response type is supported")
+	}
+	if !client.AllowsGrantType(GrantTypeAuthorizationCode) {
+		return redirectErr(OAuthErrUnauthorizedClient, "The client may not use the authorization code grant")
+	}
+	if inp.CodeChallenge == "" || inp.CodeChallengeMethod != PKCEMethodS256 {
+		return redirectErr(OAuthErrInvalidRequest, "PKCE with the S256 code challenge method is required")
+	}
+
+	scopes, err := client.ResolveScopes(inp.Scope)
+	if err != nil {
+		var oauthErr *OAuthError
+		if errors.As(err, &oauthErr) {
+			return redirectErr(oauthErr.Code, oauthErr.Description)
+		}
+		return "", err
+	}
+
+	usr, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if usr.IsSuspended() {
+		return redirectErr(OAuthErrAccessDenied, "The account is suspended")
+	}
+
+	code, plain, err := NewOAuthAuthorizationCode(
+		client.ID,
+		usr.ID,
+		inp.RedirectURI,
+		scopes,
+		inp.CodeChallenge,
+		s.cfg.OAuthCodeTTL,
+	)
+	if err != nil {
+		log.ErrorCtx(ctx, "Failed to create oauth authorization code", err)
+		return "", err
+	}
+
+	if err := s.oauthRepo.StoreOAuthAuthorizationCode(ctx, code); err != nil {
+		return "", err
+	}
+
+	return redirect(url.Values{"code": {plain}})
+}
+
+// Token handles a token request, authenticating the client first. Request errors are returned as [*OAuthError].
+func (s *OAuthService) Token(ctx context.Context, inp OAuthTokenInput) (*OAuthTokenResponse, error) {
+	client, err := s.authenticateClient(ctx, inp.ClientID, inp.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	switch inp.GrantType {
+	case GrantTypeAuthorizationCode, GrantTypeRefreshToken, GrantTypeClientCredentials:
+	default:
+		return nil, NewOAuthError(OAuthErrUnsupportedGrantType, "")
+	}
+
+	if !client.AllowsGrantType(inp.GrantType) {
+		return nil, NewOAuthError(OAuthErrUnauthorizedClient, "The client may not use the "+inp.GrantType+" grant")
+	}
+
+	switch inp.GrantType {
+	case GrantTypeAuthorizationCode:
+		return s.exchangeAuthorizationCode(ctx, client, inp)
+	case GrantTypeRefreshToken:
+		return s.exchangeRefreshToken(ctx, client, inp)
+	default:
+		return s.clientCredentials(client, inp)
+	}
+}
+
+// exchangeAuthorizationCode redeems an authorization code. Presenting an already redeemed code revokes the
+// refresh tokens the client holds for the user, since the code may have been intercepted.
+func (s *OAuthService) exchangeAuthorizationCode(
+	ctx context.Context,
+	client *OAuthClient,
+	inp OAuthTokenInput,
+) (*OAuthTokenResponse, error) {
+	var (
+		res    *OAuthTokenResponse
+		reused *OAuthAuthorizationCode
+	)
+
+	err := s.transactor.Transact(ctx, func(ctx context.Context) error {
+		code, err := s.oauthRepo.GetOAuthAuthorizationCode(ctx, HashOAuthAuthorizationCode(inp.Code))
+		if err != nil {
+			if errors.Is(err, ErrOAuthAuthorizationCodeNotFound) {
+				return NewOAuthError(OAuthErrInvalidGrant, "The authorization code is invalid")
+			}
+			return err
+		}
+
+		if code.ClientID != client.ID {
+			return NewOAuthError(OAuthErrInvalidGrant, "The authorization code is invalid")
+		}
+		if code.Used() {
+			// The revocation must commit, so it happens outside this transaction
+			reused = code
+			return nil
+		}
+		if code.Expired() {
+			return NewOAuthError(OAuthErrInvalidGrant, "The authorization code has expired")
+		}
+		if code.RedirectURI != inp.RedirectURI {
+			return NewOAuthError(OAuthErrInvalidGrant, "The redirect_uri does not match the authorization request")
+		}
+		if !code.VerifyPKCE(inp.CodeVerifier) {
+			return NewOAuthError(OAuthErrInvalidGrant, "The code_verifier does not match the code challenge")
+		}
+
+		code.Revoke()
+		if err := s.oauthRepo.UpdateOAuthAuthorizationCode(ctx, code); err != nil {
+			return err
+		}
+
+		res, err = s.issueUserTokens(ctx, client, code.UserID.String(), code.Scopes, code.Scopes)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if reused != nil {
+		log.WarnCtx(ctx, "OAuth authorization code reused, revoking refresh tokens",
+			"client_id", client.ID,
+			"user_id", reused.UserID.String(),
+		)
+		if err := s.oauthRepo.RevokeOAuthRefreshTokens(ctx, client.ID, reused.UserID.String()); err != nil {
+			return nil, err
+		}
+		return nil, NewOAuthError(OAuthErrInvalidGrant, "The authorization code has already been used")
+	}
+
+	return res, nil
+}
+
+// exchangeRefreshToken rotates a refresh token. The scope parameter may narrow the scopes of the new access
+// token, the new refresh token keeps the originally granted scopes.
+func (s *OAuthService) exchangeRefreshToken(
+	ctx context.Context,
+	client *OAuthClient,
+	inp OAuthTokenInput,
+) (*OAuthTokenResponse, error) {
+	var res *OAuthTokenResponse
+	err := s.transactor.Transact(ctx, func(ctx context.Context) error {
+		token, err := s.oauthRepo.GetOAuthRefreshTokenByHash(ctx, HashOAuthRefreshToken(inp.RefreshToken))
+		if err != nil {
+			if errors.Is(err, ErrOAuthRefreshTokenNotFound) {
+				return NewOAuthError(OAuthErrInvalidGrant, "The refresh token is invalid")
+			}
+			return err
+		}
+
+		if token.ClientID != client.ID || !token.Active() {
+			return NewOAuthError(OAuthErrInvalidGrant, "The refresh token is invalid, expired or revoked")
+		}
+
+		scopes := token.Scopes
+		if requested := ParseScope(inp.Scope); len(requested) > 0 {
+			for _, scope := range requested {
+				if !slices.Contains(token.Scopes, scope) {
+					return NewOAuthError(OAuthErrInvalidScope, "Scope "+scope+" was not granted")
+				}
+			}
+			scopes = requested
+		}
+
+		token.Revoke()
+		if err := s.oauthRepo.UpdateOAuthRefreshToken(ctx, token); err != nil {
+			return err
+		}
+
+		res, err = s.issueUserTokens(ctx, client, token.UserID.String(), token.Scopes, scopes)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// clientCredentials issues an access token to the client itself, no user is involved.
+func (s *OAuthService) clientCredentials(client *OAuthClient, inp OAuthTokenInput) (*OAuthTokenResponse, error) {
+	scopes, err := client.ResolveScopes(inp.Scope)
+	if err != nil {
+		return nil, err
+	}
+
+	scope := strings.Join(scopes, " ")
+	accessToken, err := SignAccessToken(
+		s.keys,
+		AccessTokenClaims{
+			ClientID:    client.ID,
+			Scope:       scope,
+			Permissions: scopes,
+			RegisteredClaims: jwt.RegisteredClaims{
+				Audience: jwt.ClaimStrings{OAuthAudience},
+			},
+		},
+		s.cfg.OAuthAccessTokenTTL,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OAuthTokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(s.cfg.OAuthAccessTokenTTL.Seconds()),
+		Scope:       scope,
+	}, nil
+}
+
+// issueUserTokens signs an access token for the user carrying the requested scopes the user still holds as
+// permissions. A refresh token with the granted scopes is issued too if the client may use it.
+func (s *OAuthService) issueUserTokens(
+	ctx context.Context,
+	client *OAuthClient,
+	userID string,
+	granted, requested []string,
+) (*OAuthTokenResponse, error) {
+	usr, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, user.ErrNotFound) {
+			return nil, NewOAuthError(OAuthErrInvalidGrant, "The user no longer exists")
+		}
+		return nil, err
+	}
+	if usr.IsSuspended() {
+		return nil, NewOAuthError(OAuthErrInvalidGrant, "The account is suspended")
+	}
+
+	grants, err := s.authorizer.Grants(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions := make([]string, 0, len(requested))
+	for _, scope := range requested {
+		if PermissionGranted(grants.Permissions, scope) {
+			permissions = append(permissions, scope)
+		}
+	}
+
+	scope := strings.Join(requested, " ")
+	accessToken, err := SignAccessToken(
+		s.keys,
+		AccessTokenClaims{
+			UserID:      userID,
+			ClientID:    client.ID,
+			Scope:       scope,
+			Permissions: permissions,
+			RegisteredClaims: jwt.RegisteredClaims{
+				Audience: jwt.ClaimStrings{OAuthAudience},
+			},
+		},
+		s.cfg.OAuthAccessTokenTTL,
+	)
+	if err != nil {
+		log.ErrorCtx(ctx, "Failed to sign oauth access token", err)
+		return nil, err
+	}
+
+	res := &OAuthTokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(s.cfg.OAuthAccessTokenTTL.Seconds()),
+		Scope:       scope,
+	}
+
+	if client.AllowsGrantType(GrantTypeRefreshToken) {
+		token, plain, err := NewOAuthRefreshToken(client.ID, usr.ID, granted, s.cfg.OAuthRefreshTokenTTL)
+		if err != nil {
+			log.ErrorCtx(ctx, "Failed to create oauth refresh token", err)
+			return nil, err
+		}
+
+		if err := s.oauthRepo.StoreOAuthRefreshToken(ctx, token); err != nil {
+			return nil, err
+		}
+		res.RefreshToken = plain
+	}
+
+	return res, nil
+}
+
+// Introspect reports whether a token is active along with its metadata, the caller must authenticate as a
+// client. Tokens are only reported active to the client they were issued to, first-party access tokens never are.
+func (s *OAuthService) Introspect(
+	ctx context.Context,
+	clientID, clientSecret, token string,
+) (*OAuthIntrospection, error) {
+	client, err := s.authenticateClient(ctx, clientID, clientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	inactive := &OAuthIntrospection{Active: false}
+
+	if IsOAuthRefreshToken(token) {
+		rt, err := s.oauthRepo.GetOAuthRefreshTokenByHash(ctx, HashOAuthRefreshToken(token))
+		if err != nil {
+			if errors.Is(err, ErrOAuthRefreshTokenNotFound) {
+				return inactive, nil
+			}
+			return nil, err
+		}
+
+		if rt.ClientID != client.ID || !rt.Active() {
+			return inactive, nil
+		}
+
+		return &OAuthIntrospection{
+			Active:    true,
+			Scope:     strings.Join(rt.Scopes, " "),
+			ClientID:  rt.ClientID,
+			Subject:   rt.UserID.String(),
+			TokenType: "refresh_token",
+			ExpiresAt: rt.ExpiresAt.Unix(),
+			IssuedAt:  rt.CreatedAt.Unix(),
+		}, nil
+	}
+
+	claims, err := VerifyAccessToken(s.keys, token)
+	if err != nil {
+		return inactive, nil
+	}
+
+	// Another client learns nothing about a token, not even whether it is valid
+	if claims.ClientID != client.ID {
+		return inactive, nil
+	}
+
+	if s.revocations != nil {
+		if err := CheckAccessTokenRevoked(ctx, s.revocations, claims); err != nil {
+			if errors.Is(err, ErrAccessTokenRevoked) {
+				return inactive, nil
+			}
+			return nil, err
+		}
+	}
+
+	res := &OAuthIntrospection{
+		Active:    true,
+		Scope:     claims.Scope,
+		ClientID:  claims.ClientID,
+		Subject:   claims.UserID,
+		TokenType: "access_token",
+	}
+	if claims.ExpiresAt != nil {
+		res.ExpiresAt = claims.ExpiresAt.Unix()
+	}
+	if claims.IssuedAt != nil {
+		res.IssuedAt = claims.IssuedAt.Unix()
+	}
+	return res, nil
+}
+
+// Revoke revokes a refresh or access token of the authenticated client. Unknown tokens and tokens of other
+// clients are ignored as RFC 7009 requires. Access tokens are denylisted by their ID until they expire.
+func (s *OAuthService) Revoke(ctx context.Context, clientID, clientSecret, token string) error {
+	client, err := s.authenticateClient(ctx, clientID, clientSecret)
+	if err != nil {
+		return err
+	}
+
+	if !IsOAuthRefreshToken(token) {
+		return s.revokeAccessToken(ctx, client, token)
+	}
+
+	return s.transactor.Transact(ctx, func(ctx context.Context) error {
+		rt, err := s.oauthRepo.GetOAuthRefreshTokenByHash(ctx, HashOAuthRefreshToken(token))
+		if err != nil {
+			if errors.Is(err, ErrOAuthRefreshTokenNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		if rt.ClientID != client.ID || !rt.Active() {
+			return nil
+		}
+
+		rt.Revoke()
+		return s.oauthRepo.UpdateOAuthRefreshToken(ctx, rt)
+	})
+}
+
+// revokeAccessToken denylists an access token issued to the client, invalid and expired tokens are ignored.
+func (s *OAuthService) revokeAccessToken(ctx context.Context, client *OAuthClient, token string) error {
+	if s.revocations == nil {
+		return nil
+	}
+
+	claims, err := VerifyAccessToken(s.keys, token)
+	if err != nil || claims.ClientID != client.ID || claims.ID == "" || claims.ExpiresAt == nil {
+		return nil
+	}
+
+	return s.revocations.Revoke(ctx, claims.ID, claims.ExpiresAt.Time)
+}
+
+// authenticateClient verifies the client credentials. Confidential clients must present their secret, public
+// clients must not present one.
+func (s *OAuthService) authenticateClient(ctx context.Context, clientID, clientSecret string) (*OAuthClient, error) {
+	errInvalidClient := NewOAuthError(OAuthErrInvalidClient, "Client authentication failed")
+
+	if clientID == "" {
+		return nil, errInvalidClient
+	}
+
+	client, err := s.oauthRepo.GetOAuthClient(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, ErrOAuthClientNotFound) {
+			return nil, errInvalidClient
+		}
+		return nil, err
+	}
+
+	if client.Confidential() != (clientSecret != "") {
+		return nil, errInvalidClient
+	}
+	if client.Confidential() && !client.VerifySecret(clientSecret) {
+		return nil, errInvalidClient
+	}
+
+	return client, nil
+}
+
+// appendQuery adds the params to the query of the uri, keeping the query it already has.
+func appendQuery(uri string, params url.Values) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return uri
+	}
+
+	q := u.Query()
+	for k, vs := range params {
+		for _, v := range vs {
+			q.Add(k, v)
+		}
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
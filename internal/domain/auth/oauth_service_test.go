@@ -0,0 +1,485 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prawirdani/golang-restapi/config"
+	"github.com/prawirdani/golang-restapi/internal/domain/auth"
+	"github.com/prawirdani/golang-restapi/internal/domain/user"
+	"github.com/prawirdani/golang-restapi/internal/testing/mocks"
+)
+
+const (
+	testRedirectURI  = "https://app.example.com/callback"
+	testCodeVerifier = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+)
+
+func testCodeChallenge() string {
+	sum := sha256.Sum256([]byte(testCodeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+type oauthTestDeps struct {
+	transactor  *mocks.Transactor
+	userRepo    *mocks.UserRepository
+	oauthRepo   *mocks.OAuthRepository
+	roleRepo    *mocks.RoleRepository
+	revocations *mocks.TokenRevocationStore
+}
+
+func newOAuthTestService(t *testing.T) (*auth.OAuthService, oauthTestDeps) {
+	deps := oauthTestDeps{
+		transactor:  mocks.NewTransactor(t),
+		userRepo:    mocks.NewUserRepository(t),
+		oauthRepo:   mocks.NewOAuthRepository(t),
+		roleRepo:    mocks.NewRoleRepository(t),
+		revocations: mocks.NewTokenRevocationStore(t),
+	}
+	cfg := config.Auth{JwtTTL: time.Hour, SessionTTL: 24 * time.Hour}
+
+	svc := auth.NewOAuthService(
+		cfg,
+		testKeys,
+		deps.transactor,
+		deps.userRepo,
+		deps.oauthRepo,
+		deps.roleRepo,
+		deps.revocations,
+	)
+	return svc, deps
+}
+
+func newTestOAuthClient(t *testing.T, confidential bool, grantTypes ...string) (*auth.OAuthClient, string) {
+	client, secret, err := auth.NewOAuthClient(
+		"dashboard",
+		[]string{testRedirectURI},
+		grantTypes,
+		[]string{auth.PermissionUsersRead},
+		confidential,
+	)
+	require.NoError(t, err)
+	return client, secret
+}
+
+func TestOAuthService_RegisterClient(t *testing.T) {
+	ctx := context.Background()
+	adminID := uuid.NewString()
+	adminRoles := []*auth.Role{{Name: auth.RoleSupport, Permissions: []string{auth.PermissionUsersRead}}}
+
+	t.Run("Success", func(t *testing.T) {
+		svc, deps := newOAuthTestService(t)
+		deps.roleRepo.EXPECT().GetRolesByUser(ctx, adminID).Return(adminRoles, nil)
+		deps.oauthRepo.EXPECT().StoreOAuthClient(ctx, mock.AnythingOfType("*auth.OAuthClient")).Return(nil)
+
+		created, err := svc.RegisterClient(ctx, adminID, auth.RegisterOAuthClientInput{
+			Name:         "dashboard",
+			RedirectURIs: []string{testRedirectURI},
+			GrantTypes:   []string{auth.GrantTypeAuthorizationCode},
+			Scopes:       []string{auth.PermissionUsersRead},
+			Confidential: true,
+		})
+		require.NoError(t, err)
+		assert.NotEmpty(t, created.ClientSecret)
+		assert.True(t, created.VerifySecret(created.ClientSecret))
+	})
+
+	t.Run("ScopeNotHeld", func(t *testing.T) {
+		svc, deps := newOAuthTestService(t)
+		deps.roleRepo.EXPECT().GetRolesByUser(ctx, adminID).Return(adminRoles, nil)
+
+		_, err := svc.RegisterClient(ctx, adminID, auth.RegisterOAuthClientInput{
+			Name:         "dashboard",
+			RedirectURIs: []string{testRedirectURI},
+			GrantTypes:   []string{auth.GrantTypeAuthorizationCode},
+			Scopes:       []string{auth.PermissionUsersWrite},
+		})
+		assert.ErrorIs(t, err, auth.ErrOAuthClientScope)
+	})
+
+	t.Run("PublicClientCredentials", func(t *testing.T) {
+		svc, deps := newOAuthTestService(t)
+		deps.roleRepo.EXPECT().GetRolesByUser(ctx, adminID).Return(adminRoles, nil)
+
+		_, err := svc.RegisterClient(ctx, adminID, auth.RegisterOAuthClientInput{
+			Name:       "cli",
+			GrantTypes: []string{auth.GrantTypeClientCredentials},
+		})
+		assert.ErrorIs(t, err, auth.ErrOAuthPublicClientCredentials)
+	})
+}
+
+func TestOAuthService_Authorize(t *testing.T) {
+	ctx := context.Background()
+	testUser := &user.User{ID: uuid.New(), Name: "John Doe", Email: "john@example.com"}
+	userID := testUser.ID.String()
+
+	validInput := func(clientID string) auth.OAuthAuthorizeInput {
+		return auth.OAuthAuthorizeInput{
+			ResponseType:        "code",
+			ClientID:            clientID,
+			RedirectURI:         testRedirectURI,
+			Scope:               auth.PermissionUsersRead,
+			State:               "xyz",
+			CodeChallenge:       testCodeChallenge(),
+			CodeChallengeMethod: auth.PKCEMethodS256,
+		}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		svc, deps := newOAuthTestService(t)
+		client, _ := newTestOAuthClient(t, false, auth.GrantTypeAuthorizationCode)
+
+		deps.oauthRepo.EXPECT().GetOAuthClient(ctx, client.ID).Return(client, nil)
+		deps.userRepo.EXPECT().GetByID(ctx, userID).Return(testUser, nil)
+		deps.oauthRepo.EXPECT().
+			StoreOAuthAuthorizationCode(ctx, mock.MatchedBy(func(c *auth.OAuthAuthorizationCode) bool {
+				return c.ClientID == client.ID && c.UserID == testUser.ID && c.RedirectURI == testRedirectURI
+			})).
+			Return(nil)
+
+		redirect, err := svc.Authorize(ctx, userID, validInput(client.ID))
+		require.NoError(t, err)
+
+		u, err := url.Parse(redirect)
+		require.NoError(t, err)
+		assert.Equal(t, "app.example.com", u.Host)
+		assert.NotEmpty(t, u.Query().Get("code"))
+		assert.Equal(t, "xyz", u.Query().Get("state"))
+	})
+
+	t.Run("UnregisteredRedirectURI", func(t *testing.T) {
+		svc, deps := newOAuthTestService(t)
+		client, _ := newTestOAuthClient(t, false, auth.GrantTypeAuthorizationCode)
+		deps.oauthRepo.EXPECT().GetOAuthClient(ctx, client.ID).Return(client, nil)
+
+		inp := validInput(client.ID)
+		inp.RedirectURI = "https://evil.example.com/callback"
+
+		_, err := svc.Authorize(ctx, userID, inp)
+		var oauthErr *auth.OAuthError
+		require.ErrorAs(t, err, &oauthErr)
+		assert.Equal(t, auth.OAuthErrInvalidRequest, oauthErr.Code)
+	})
+
+	t.Run("MissingPKCE", func(t *testing.T) {
+		svc, deps := newOAuthTestService(t)
+		client, _ := newTestOAuthClient(t, false, auth.GrantTypeAuthorizationCode)
+		deps.oauthRepo.EXPECT().GetOAuthClient(ctx, client.ID).Return(client, nil)
+
+		inp := validInput(client.ID)
+		inp.CodeChallenge = ""
+
+		redirect, err := svc.Authorize(ctx, userID, inp)
+		require.NoError(t, err)
+
+		u, err := url.Parse(redirect)
+		require.NoError(t, err)
+		assert.Equal(t, auth.OAuthErrInvalidRequest, u.Query().Get("error"))
+		assert.Equal(t, "xyz", u.Query().Get("state"))
+		assert.Empty(t, u.Query().Get("code"))
+	})
+}
+
+func TestOAuthService_Token_AuthorizationCode(t *testing.T) {
+	ctx := context.Background()
+	testUser := &user.User{ID: uuid.New(), Name: "John Doe", Email: "john@example.com"}
+	userID := testUser.ID.String()
+	userRoles := []*auth.Role{{Name: auth.RoleAdmin, Permissions: []string{auth.PermissionAll}}}
+
+	newCode := func(t *testing.T, clientID string) (*auth.OAuthAuthorizationCode, string) {
+		code, plain, err := auth.NewOAuthAuthorizationCode(
+			clientID, testUser.ID, testRedirectURI, []string{auth.PermissionUsersRead}, testCodeChallenge(), time.Minute,
+		)
+		require.NoError(t, err)
+		return code, plain
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		svc, deps := newOAuthTestService(t)
+		client, secret := newTestOAuthClient(t, true, auth.GrantTypeAuthorizationCode, auth.GrantTypeRefreshToken)
+		code, plain := newCode(t, client.ID)
+
+		deps.oauthRepo.EXPECT().GetOAuthClient(ctx, client.ID).Return(client, nil)
+		deps.transactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			deps.oauthRepo.EXPECT().GetOAuthAuthorizationCode(ctx, code.CodeHash).Return(code, nil)
+			deps.oauthRepo.EXPECT().UpdateOAuthAuthorizationCode(ctx, code).Return(nil)
+			deps.userRepo.EXPECT().GetByID(ctx, userID).Return(testUser, nil)
+			deps.roleRepo.EXPECT().GetRolesByUser(ctx, userID).Return(userRoles, nil)
+			deps.oauthRepo.EXPECT().StoreOAuthRefreshToken(ctx, mock.AnythingOfType("*auth.OAuthRefreshToken")).Return(nil)
+			return fn(ctx)
+		})
+
+		res, err := svc.Token(ctx, auth.OAuthTokenInput{
+			GrantType:    auth.GrantTypeAuthorizationCode,
+			Code:         plain,
+			RedirectURI:  testRedirectURI,
+			CodeVerifier: testCodeVerifier,
+			ClientID:     client.ID,
+			ClientSecret: secret,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer", res.TokenType)
+		assert.True(t, auth.IsOAuthRefreshToken(res.RefreshToken))
+		assert.Equal(t, auth.PermissionUsersRead, res.Scope)
+		assert.True(t, code.Used())
+
+		claims, err := auth.VerifyAccessToken(testKeys, res.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, userID, claims.UserID)
+		assert.Equal(t, client.ID, claims.ClientID)
+		assert.Equal(t, auth.PermissionUsersRead, claims.Scope)
+		assert.Equal(t, []string{auth.PermissionUsersRead}, claims.Permissions)
+		assert.True(t, claims.Delegated())
+		assert.Equal(t, jwt.ClaimStrings{auth.OAuthAudience}, claims.Audience)
+	})
+
+	t.Run("WrongVerifier", func(t *testing.T) {
+		svc, deps := newOAuthTestService(t)
+		client, _ := newTestOAuthClient(t, false, auth.GrantTypeAuthorizationCode)
+		code, plain := newCode(t, client.ID)
+
+		deps.oauthRepo.EXPECT().GetOAuthClient(ctx, client.ID).Return(client, nil)
+		deps.transactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			deps.oauthRepo.EXPECT().GetOAuthAuthorizationCode(ctx, code.CodeHash).Return(code, nil)
+			return fn(ctx)
+		})
+
+		_, err := svc.Token(ctx, auth.OAuthTokenInput{
+			GrantType:    auth.GrantTypeAuthorizationCode,
+			Code:         plain,
+			RedirectURI:  testRedirectURI,
+			CodeVerifier: "another-verifier",
+			ClientID:     client.ID,
+		})
+		var oauthErr *auth.OAuthError
+		require.ErrorAs(t, err, &oauthErr)
+		assert.Equal(t, auth.OAuthErrInvalidGrant, oauthErr.Code)
+	})
+
+	t.Run("CodeReused", func(t *testing.T) {
+		svc, deps := newOAuthTestService(t)
+		client, _ := newTestOAuthClient(t, false, auth.GrantTypeAuthorizationCode)
+		code, plain := newCode(t, client.ID)
+		code.Revoke()
+
+		deps.oauthRepo.EXPECT().GetOAuthClient(ctx, client.ID).Return(client, nil)
+		deps.transactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			deps.oauthRepo.EXPECT().GetOAuthAuthorizationCode(ctx, code.CodeHash).Return(code, nil)
+			return fn(ctx)
+		})
+		deps.oauthRepo.EXPECT().RevokeOAuthRefreshTokens(ctx, client.ID, userID).Return(nil)
+
+		_, err := svc.Token(ctx, auth.OAuthTokenInput{
+			GrantType:    auth.GrantTypeAuthorizationCode,
+			Code:         plain,
+			RedirectURI:  testRedirectURI,
+			CodeVerifier: testCodeVerifier,
+			ClientID:     client.ID,
+		})
+		var oauthErr *auth.OAuthError
+		require.ErrorAs(t, err, &oauthErr)
+		assert.Equal(t, auth.OAuthErrInvalidGrant, oauthErr.Code)
+	})
+
+	t.Run("WrongSecret", func(t *testing.T) {
+		svc, deps := newOAuthTestService(t)
+		client, _ := newTestOAuthClient(t, true, auth.GrantTypeAuthorizationCode)
+		deps.oauthRepo.EXPECT().GetOAuthClient(ctx, client.ID).Return(client, nil)
+
+		_, err := svc.Token(ctx, auth.OAuthTokenInput{
+			GrantType:    auth.GrantTypeAuthorizationCode,
+			ClientID:     client.ID,
+			ClientSecret: "wrong",
+		})
+		var oauthErr *auth.OAuthError
+		require.ErrorAs(t, err, &oauthErr)
+		assert.Equal(t, auth.OAuthErrInvalidClient, oauthErr.Code)
+	})
+}
+
+func TestOAuthService_Token_RefreshToken(t *testing.T) {
+	ctx := context.Background()
+	testUser := &user.User{ID: uuid.New(), Name: "John Doe", Email: "john@example.com"}
+	userID := testUser.ID.String()
+
+	svc, deps := newOAuthTestService(t)
+	client, secret := newTestOAuthClient(t, true, auth.GrantTypeAuthorizationCode, auth.GrantTypeRefreshToken)
+	token, plain, err := auth.NewOAuthRefreshToken(client.ID, testUser.ID, []string{auth.PermissionUsersRead}, time.Hour)
+	require.NoError(t, err)
+
+	deps.oauthRepo.EXPECT().GetOAuthClient(ctx, client.ID).Return(client, nil)
+	deps.transactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		deps.oauthRepo.EXPECT().GetOAuthRefreshTokenByHash(ctx, token.TokenHash).Return(token, nil)
+		deps.oauthRepo.EXPECT().UpdateOAuthRefreshToken(ctx, token).Return(nil)
+		deps.userRepo.EXPECT().GetByID(ctx, userID).Return(testUser, nil)
+		deps.roleRepo.EXPECT().GetRolesByUser(ctx, userID).Return([]*auth.Role{}, nil)
+		deps.oauthRepo.EXPECT().
+			StoreOAuthRefreshToken(ctx, mock.MatchedBy(func(rt *auth.OAuthRefreshToken) bool {
+				return rt.ID != token.ID && rt.ClientID == client.ID
+			})).
+			Return(nil)
+		return fn(ctx)
+	})
+
+	res, err := svc.Token(ctx, auth.OAuthTokenInput{
+		GrantType:    auth.GrantTypeRefreshToken,
+		RefreshToken: plain,
+		ClientID:     client.ID,
+		ClientSecret: secret,
+	})
+	require.NoError(t, err)
+	assert.NotEqual(t, plain, res.RefreshToken)
+	assert.False(t, token.Active())
+
+	// The user no longer holds the scope, so the token carries no permission
+	claims, err := auth.VerifyAccessToken(testKeys, res.AccessToken)
+	require.NoError(t, err)
+	assert.Empty(t, claims.Permissions)
+	assert.Equal(t, auth.PermissionUsersRead, claims.Scope)
+}
+
+func TestOAuthService_Token_ClientCredentials(t *testing.T) {
+	ctx := context.Background()
+
+	svc, deps := newOAuthTestService(t)
+	client, secret := newTestOAuthClient(t, true, auth.GrantTypeClientCredentials)
+	deps.oauthRepo.EXPECT().GetOAuthClient(ctx, client.ID).Return(client, nil)
+
+	res, err := svc.Token(ctx, auth.OAuthTokenInput{
+		GrantType:    auth.GrantTypeClientCredentials,
+		ClientID:     client.ID,
+		ClientSecret: secret,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, res.RefreshToken)
+
+	claims, err := auth.VerifyAccessToken(testKeys, res.AccessToken)
+	require.NoError(t, err)
+	assert.Empty(t, claims.UserID)
+	assert.Equal(t, client.ID, claims.ClientID)
+	assert.Equal(t, []string{auth.PermissionUsersRead}, claims.Permissions)
+	assert.Equal(t, jwt.ClaimStrings{auth.OAuthAudience}, claims.Audience)
+	assert.ErrorIs(t, auth.CheckFirstPartyAccessToken(claims), auth.ErrAccessTokenDelegated)
+}
+
+func TestOAuthService_Introspect(t *testing.T) {
+	ctx := context.Background()
+
+	svc, deps := newOAuthTestService(t)
+	client, secret := newTestOAuthClient(t, true, auth.GrantTypeClientCredentials)
+	deps.oauthRepo.EXPECT().GetOAuthClient(ctx, client.ID).Return(client, nil)
+
+	accessToken, err := auth.SignAccessToken(testKeys, auth.AccessTokenClaims{
+		UserID:   "user-1",
+		ClientID: client.ID,
+		Scope:    auth.PermissionUsersRead,
+	}, time.Hour)
+	require.NoError(t, err)
+	claims, err := auth.VerifyAccessToken(testKeys, accessToken)
+	require.NoError(t, err)
+
+	deps.revocations.EXPECT().IsRevoked(ctx, []string{claims.ID}).Return(false, nil).Once()
+	res, err := svc.Introspect(ctx, client.ID, secret, accessToken)
+	require.NoError(t, err)
+	assert.True(t, res.Active)
+	assert.Equal(t, "user-1", res.Subject)
+	assert.Equal(t, auth.PermissionUsersRead, res.Scope)
+
+	deps.revocations.EXPECT().IsRevoked(ctx, []string{claims.ID}).Return(true, nil).Once()
+	res, err = svc.Introspect(ctx, client.ID, secret, accessToken)
+	require.NoError(t, err)
+	assert.Equal(t, &auth.OAuthIntrospection{Active: false}, res)
+
+	res, err = svc.Introspect(ctx, client.ID, secret, "not-a-token")
+	require.NoError(t, err)
+	assert.Equal(t, &auth.OAuthIntrospection{Active: false}, res)
+
+	// Tokens of other clients and first-party tokens are inactive to the client
+	for name, claims := range map[string]auth.AccessTokenClaims{
+		"OtherClient": {UserID: "user-1", ClientID: "other-client", Scope: auth.PermissionUsersRead},
+		"FirstParty":  {UserID: "user-1", SessionID: "session-id"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			token, err := auth.SignAccessToken(testKeys, claims, time.Hour)
+			require.NoError(t, err)
+
+			res, err := svc.Introspect(ctx, client.ID, secret, token)
+			require.NoError(t, err)
+			assert.Equal(t, &auth.OAuthIntrospection{Active: false}, res)
+		})
+	}
+}
+
+func TestOAuthService_Revoke(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("OwnToken", func(t *testing.T) {
+		svc, deps := newOAuthTestService(t)
+		client, secret := newTestOAuthClient(t, true, auth.GrantTypeRefreshToken)
+		token, plain, err := auth.NewOAuthRefreshToken(client.ID, uuid.New(), nil, time.Hour)
+		require.NoError(t, err)
+
+		deps.oauthRepo.EXPECT().GetOAuthClient(ctx, client.ID).Return(client, nil)
+		deps.transactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			deps.oauthRepo.EXPECT().GetOAuthRefreshTokenByHash(ctx, token.TokenHash).Return(token, nil)
+			deps.oauthRepo.EXPECT().UpdateOAuthRefreshToken(ctx, token).Return(nil)
+			return fn(ctx)
+		})
+
+		require.NoError(t, svc.Revoke(ctx, client.ID, secret, plain))
+		assert.False(t, token.Active())
+	})
+
+	t.Run("OtherClientToken", func(t *testing.T) {
+		svc, deps := newOAuthTestService(t)
+		client, secret := newTestOAuthClient(t, true, auth.GrantTypeRefreshToken)
+		token, plain, err := auth.NewOAuthRefreshToken("another-client", uuid.New(), nil, time.Hour)
+		require.NoError(t, err)
+
+		deps.oauthRepo.EXPECT().GetOAuthClient(ctx, client.ID).Return(client, nil)
+		deps.transactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			deps.oauthRepo.EXPECT().GetOAuthRefreshTokenByHash(ctx, token.TokenHash).Return(token, nil)
+			return fn(ctx)
+		})
+
+		require.NoError(t, svc.Revoke(ctx, client.ID, secret, plain))
+		assert.True(t, token.Active())
+	})
+
+	t.Run("AccessToken", func(t *testing.T) {
+		svc, deps := newOAuthTestService(t)
+		client, secret := newTestOAuthClient(t, true, auth.GrantTypeClientCredentials)
+		accessToken, err := auth.SignAccessToken(testKeys, auth.AccessTokenClaims{ClientID: client.ID}, time.Hour)
+		require.NoError(t, err)
+		claims, err := auth.VerifyAccessToken(testKeys, accessToken)
+		require.NoError(t, err)
+
+		deps.oauthRepo.EXPECT().GetOAuthClient(ctx, client.ID).Return(client, nil)
+		deps.revocations.EXPECT().Revoke(ctx, claims.ID, claims.ExpiresAt.Time).Return(nil)
+
+		require.NoError(t, svc.Revoke(ctx, client.ID, secret, accessToken))
+	})
+
+	t.Run("OtherClientAccessToken", func(t *testing.T) {
+		svc, deps := newOAuthTestService(t)
+		client, secret := newTestOAuthClient(t, true, auth.GrantTypeClientCredentials)
+		accessToken, err := auth.SignAccessToken(testKeys, auth.AccessTokenClaims{ClientID: "another-client"}, time.Hour)
+		require.NoError(t, err)
+
+		deps.oauthRepo.EXPECT().GetOAuthClient(ctx, client.ID).Return(client, nil)
+
+		// Not revoked, the mock fails the test on an unexpected call
+		require.NoError(t, svc.Revoke(ctx, client.ID, secret, accessToken))
+	})
+}
@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOAuthClient(t *testing.T) {
+	t.Run("confidential", func(t *testing.T) {
+		client, secret, err := NewOAuthClient(
+			"dashboard",
+			[]string{"https://app.example.com/callback"},
+			[]string{GrantTypeAuthorizationCode},
+			[]string{PermissionUsersRead},
+			true,
+		)
+		require.NoError(t, err)
+		assert.True(t, client.Confidential())
+		assert.NotEmpty(t, secret)
+		assert.True(t, client.VerifySecret(secret))
+		assert.False(t, client.VerifySecret("wrong"))
+		assert.NotContains(t, client.SecretHash, OAuthClientSecretPrefix)
+	})
+
+	t.Run("public", func(t *testing.T) {
+		client, secret, err := NewOAuthClient("mobile", nil, []string{GrantTypeAuthorizationCode}, nil, false)
+		require.NoError(t, err)
+		assert.False(t, client.Confidential())
+		assert.Empty(t, secret)
+		assert.False(t, client.VerifySecret(""))
+	})
+}
+
+func TestOAuthClient_ResolveScopes(t *testing.T) {
+	client := OAuthClient{Scopes: []string{PermissionUsersRead, PermissionRolesRead}}
+
+	scopes, err := client.ResolveScopes("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{PermissionUsersRead, PermissionRolesRead}, scopes)
+
+	scopes, err = client.ResolveScopes("users:read users:read")
+	require.NoError(t, err)
+	assert.Equal(t, []string{PermissionUsersRead}, scopes)
+
+	_, err = client.ResolveScopes("users:write")
+	var oauthErr *OAuthError
+	require.ErrorAs(t, err, &oauthErr)
+	assert.Equal(t, OAuthErrInvalidScope, oauthErr.Code)
+}
+
+func TestOAuthAuthorizationCode_VerifyPKCE(t *testing.T) {
+	verifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	sum := sha256.Sum256([]byte(verifier))
+	challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	code, plain, err := NewOAuthAuthorizationCode(
+		"client", uuid.New(), "https://app.example.com/callback", nil, challenge, time.Minute,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, HashOAuthAuthorizationCode(plain), code.CodeHash)
+	assert.True(t, code.VerifyPKCE(verifier))
+	assert.False(t, code.VerifyPKCE("another-verifier"))
+	assert.False(t, code.Expired())
+
+	code.Revoke()
+	assert.True(t, code.Used())
+}
+
+func TestOAuthRefreshToken(t *testing.T) {
+	token, plain, err := NewOAuthRefreshToken("client", uuid.New(), nil, time.Hour)
+	require.NoError(t, err)
+	assert.True(t, IsOAuthRefreshToken(plain))
+	assert.Equal(t, HashOAuthRefreshToken(plain), token.TokenHash)
+	assert.True(t, token.Active())
+
+	token.Revoke()
+	assert.False(t, token.Active())
+
+	expired, _, err := NewOAuthRefreshToken("client", uuid.New(), nil, -time.Minute)
+	require.NoError(t, err)
+	assert.False(t, expired.Active())
+}
@@ -0,0 +1,182 @@
+// Package auth provides authentication and authorization functionality.
+// This package handles user authentication through sessions, access tokens, and
+// password management including secure hashing and password reset flows. It manages
+// the complete authentication lifecycle from login through logout, including token
+// generation, validation, and session management.
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prawirdani/golang-restapi/internal/domain"
+	"github.com/prawirdani/golang-restapi/pkg/nullable"
+)
+
+// OAuthRefreshTokenPrefix marks a credential as a refresh token issued by the authorization server.
+const OAuthRefreshTokenPrefix = "ort_"
+
+var (
+	ErrOAuthAuthorizationCodeNotFound = domain.ErrNotFound("OAuth authorization code not found")
+	ErrOAuthRefreshTokenNotFound      = domain.ErrNotFound("OAuth refresh token not found")
+)
+
+// PKCEMethodS256 is the only code challenge method accepted, plain challenges are rejected.
+const PKCEMethodS256 = "S256"
+
+// OAuthAuthorizationCode is issued by the authorize endpoint and redeemed once at the token endpoint.
+// Only the hash of the code is stored.
+type OAuthAuthorizationCode struct {
+	CodeHash      string                       `db:"code_hash"      json:"-"`
+	ClientID      string                       `db:"client_id"      json:"client_id"`
+	UserID        uuid.UUID                    `db:"user_id"        json:"user_id"`
+	RedirectURI   string                       `db:"redirect_uri"   json:"redirect_uri"`
+	Scopes        []string                     `db:"scopes"         json:"scopes"`
+	CodeChallenge string                       `db:"code_challenge" json:"-"`
+	ExpiresAt     time.Time                    `db:"expires_at"     json:"expires_at"`
+	UsedAt        nullable.Nullable[time.Time] `db:"used_at"        json:"used_at"`
+}
+
+// NewOAuthAuthorizationCode creates a code bound to the client, redirect URI and S256 code challenge, returning
+// it along with the plain code.
+func NewOAuthAuthorizationCode(
+	clientID string,
+	userID uuid.UUID,
+	redirectURI string,
+	scopes []string,
+	codeChallenge string,
+	ttl time.Duration,
+) (*OAuthAuthorizationCode, string, error) {
+	bs := make([]byte, 32)
+	if _, err := rand.Read(bs); err != nil {
+		return nil, "", err
+	}
+	plain := hex.EncodeToString(bs)
+
+	return &OAuthAuthorizationCode{
+		CodeHash:      hashOAuthCredential(plain),
+		ClientID:      clientID,
+		UserID:        userID,
+		RedirectURI:   redirectURI,
+		Scopes:        scopes,
+		CodeChallenge: codeChallenge,
+		ExpiresAt:     time.Now().Add(ttl),
+	}, plain, nil
+}
+
+// HashOAuthAuthorizationCode hashes a plain authorization code the way it is stored.
+func HashOAuthAuthorizationCode(code string) string {
+	return hashOAuthCredential(code)
+}
+
+// Expired reports whether the code has passed its expiration time.
+func (c OAuthAuthorizationCode) Expired() bool {
+	return c.ExpiresAt.Before(time.Now())
+}
+
+// Used reports whether the code has already been redeemed.
+func (c OAuthAuthorizationCode) Used() bool {
+	return c.UsedAt.NotNull()
+}
+
+// Revoke marks the code as redeemed.
+func (c *OAuthAuthorizationCode) Revoke() {
+	c.UsedAt = nullable.New(time.Now(), false)
+}
+
+// VerifyPKCE reports whether the code verifier matches the S256 code challenge, as defined by RFC 7636.
+func (c OAuthAuthorizationCode) VerifyPKCE(verifier string) bool {
+	sum := sha256.Sum256([]byte(verifier))
+	computed := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(computed), []byte(c.CodeChallenge)) == 1
+}
+
+// OAuthRefreshToken lets a client obtain new access tokens on behalf of a user. Every use rotates the token,
+// only the hash of the token is stored.
+type OAuthRefreshToken struct {
+	ID        uuid.UUID                    `db:"id"         json:"id"`
+	TokenHash string                       `db:"token_hash" json:"-"`
+	ClientID  string                       `db:"client_id"  json:"client_id"`
+	UserID    uuid.UUID                    `db:"user_id"    json:"user_id"`
+	Scopes    []string                     `db:"scopes"     json:"scopes"`
+	ExpiresAt time.Time                    `db:"expires_at" json:"expires_at"`
+	RevokedAt nullable.Nullable[time.Time] `db:"revoked_at" json:"revoked_at"`
+	CreatedAt time.Time                    `db:"created_at" json:"created_at"`
+}
+
+// NewOAuthRefreshToken creates a refresh token, returning it along with the plain token value.
+func NewOAuthRefreshToken(
+	clientID string,
+	userID uuid.UUID,
+	scopes []string,
+	ttl time.Duration,
+) (*OAuthRefreshToken, string, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, "", err
+	}
+
+	bs := make([]byte, 32)
+	if _, err := rand.Read(bs); err != nil {
+		return nil, "", err
+	}
+	plain := OAuthRefreshTokenPrefix + hex.EncodeToString(bs)
+
+	now := time.Now()
+	return &OAuthRefreshToken{
+		ID:        id,
+		TokenHash: hashOAuthCredential(plain),
+		ClientID:  clientID,
+		UserID:    userID,
+		Scopes:    scopes,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}, plain, nil
+}
+
+// IsOAuthRefreshToken reports whether the credential is a refresh token of the authorization server.
+func IsOAuthRefreshToken(token string) bool {
+	return strings.HasPrefix(token, OAuthRefreshTokenPrefix)
+}
+
+// HashOAuthRefreshToken hashes a plain refresh token the way it is stored.
+func HashOAuthRefreshToken(token string) string {
+	return hashOAuthCredential(token)
+}
+
+// Active reports whether the token is neither expired nor revoked.
+func (t OAuthRefreshToken) Active() bool {
+	return !t.RevokedAt.NotNull() && t.ExpiresAt.After(time.Now())
+}
+
+// Revoke marks the token as revoked immediately.
+func (t *OAuthRefreshToken) Revoke() {
+	t.RevokedAt = nullable.New(time.Now(), false)
+}
+
+// OAuthTokenResponse is the successful response of the token endpoint, RFC 6749 section 5.1.
+type OAuthTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+// OAuthIntrospection is the response of the introspection endpoint, RFC 7662 section 2.2. Inactive tokens only
+// carry the active field.
+type OAuthIntrospection struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty"`
+	ClientID  string `json:"client_id,omitempty"`
+	Subject   string `json:"sub,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+}
@@ -6,25 +6,296 @@
 package auth
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/prawirdani/golang-restapi/config"
 	"github.com/prawirdani/golang-restapi/internal/domain"
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
-var ErrWrongCredentials = domain.ErrUnauthorized("Check your credentials")
+var (
+	ErrWrongCredentials      = domain.ErrUnauthorized("Check your credentials")
+	ErrPasswordResetRequired = domain.ErrForbidden("A password reset is required, check your email for the reset link")
+	ErrPasswordTooLong       = domain.ErrValidation("Password is too long")
+)
+
+// Supported password hashing algorithms.
+const (
+	PasswordAlgorithmArgon2id = "argon2id"
+	PasswordAlgorithmBcrypt   = "bcrypt"
+)
+
+// PasswordHasher is a password hashing algorithm producing self-describing hash strings, so the algorithm and
+// parameters of a stored hash can always be told from the hash itself.
+type PasswordHasher interface {
+	// Hash hashes a plain password.
+	Hash(plain string) (string, error)
+
+	// Verify reports whether the plain password matches a hash produced by this algorithm.
+	Verify(plain, encoded string) (bool, error)
+
+	// Handles reports whether the hash was produced by this algorithm.
+	Handles(encoded string) bool
+
+	// NeedsRehash reports whether the hash was produced with parameters other than the hasher's.
+	NeedsRehash(encoded string) bool
+}
+
+// Argon2idParams are the cost parameters of argon2id hashes.
+type Argon2idParams struct {
+	Memory      uint32 // Memory in KiB
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2idParams follow the OWASP minimum recommendation for argon2id.
+var DefaultArgon2idParams = Argon2idParams{
+	Memory:      19 * 1024,
+	Iterations:  2,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+type argon2idHasher struct {
+	params Argon2idParams
+}
+
+// NewArgon2idHasher creates an argon2id hasher producing PHC formatted hashes, e.g.
+// $argon2id$v=19$m=19456,t=2,p=1$<salt>$<hash>. Zero parameters fall back to [DefaultArgon2idParams].
+func NewArgon2idHasher(params Argon2idParams) PasswordHasher {
+	if params.Memory == 0 {
+		params.Memory = DefaultArgon2idParams.Memory
+	}
+	if params.Iterations == 0 {
+		params.Iterations = DefaultArgon2idParams.Iterations
+	}
+	if params.Parallelism == 0 {
+		params.Parallelism = DefaultArgon2idParams.Parallelism
+	}
+	if params.SaltLength == 0 {
+		params.SaltLength = DefaultArgon2idParams.SaltLength
+	}
+	if params.KeyLength == 0 {
+		params.KeyLength = DefaultArgon2idParams.KeyLength
+	}
+	return &argon2idHasher{params: params}
+}
+
+// Hash implements [PasswordHasher]
+func (h *argon2idHasher) Hash(plain string) (string, error) {
+	salt := make([]byte, h.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	p := h.params
+	key := argon2.IDKey([]byte(plain), salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		p.Memory,
+		p.Iterations,
+		p.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify implements [PasswordHasher]
+func (h *argon2idHasher) Verify(plain, encoded string) (bool, error) {
+	p, salt, key, err := decodeArgon2idHash(encoded)
+	if err != nil {
+		return false, err
+	}
+
+	other := argon2.IDKey([]byte(plain), salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+	return subtle.ConstantTimeCompare(key, other) == 1, nil
+}
 
+// Handles implements [PasswordHasher]
+func (h *argon2idHasher) Handles(encoded string) bool {
+	return strings.HasPrefix(encoded, "$argon2id$")
+}
+
+// NeedsRehash implements [PasswordHasher]
+func (h *argon2idHasher) NeedsRehash(encoded string) bool {
+	p, salt, _, err := decodeArgon2idHash(encoded)
+	if err != nil {
+		return true
+	}
+
+	return p.Memory != h.params.Memory ||
+		p.Iterations != h.params.Iterations ||
+		p.Parallelism != h.params.Parallelism ||
+		p.KeyLength != h.params.KeyLength ||
+		uint32(len(salt)) != h.params.SaltLength
+}
+
+// decodeArgon2idHash parses a PHC formatted argon2id hash into its parameters, salt and key.
+func decodeArgon2idHash(encoded string) (Argon2idParams, []byte, []byte, error) {
+	var p Argon2idParams
+
+	// "", "argon2id", "v=19", "m=..,t=..,p=..", salt, key
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return p, nil, nil, errors.New("invalid argon2id hash format")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return p, nil, nil, fmt.Errorf("invalid argon2id hash version: %w", err)
+	}
+	if version != argon2.Version {
+		return p, nil, nil, fmt.Errorf("unsupported argon2id version %d", version)
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Iterations, &p.Parallelism); err != nil {
+		return p, nil, nil, fmt.Errorf("invalid argon2id hash parameters: %w", err)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return p, nil, nil, fmt.Errorf("invalid argon2id hash salt: %w", err)
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return p, nil, nil, fmt.Errorf("invalid argon2id hash key: %w", err)
+	}
+
+	p.SaltLength = uint32(len(salt))
+	p.KeyLength = uint32(len(key))
+	return p, salt, key, nil
+}
+
+type bcryptHasher struct {
+	cost int
+}
+
+// NewBcryptHasher creates a bcrypt hasher, a zero cost falls back to [bcrypt.DefaultCost].
+// Bcrypt only reads the first 72 bytes of a password, so longer passwords are rejected when hashing.
+func NewBcryptHasher(cost int) PasswordHasher {
+	if cost == 0 {
+		cost = bcrypt.DefaultCost
+	}
+	return &bcryptHasher{cost: cost}
+}
+
+// Hash implements [PasswordHasher]
+func (h *bcryptHasher) Hash(plain string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(plain), h.cost)
+	if err != nil {
+		if errors.Is(err, bcrypt.ErrPasswordTooLong) {
+			return "", ErrPasswordTooLong
+		}
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+// Verify implements [PasswordHasher]
+func (h *bcryptHasher) Verify(plain, encoded string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(plain))
+	if err != nil {
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Handles implements [PasswordHasher]
+func (h *bcryptHasher) Handles(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") ||
+		strings.HasPrefix(encoded, "$2b$") ||
+		strings.HasPrefix(encoded, "$2y$")
+}
+
+// NeedsRehash implements [PasswordHasher]
+func (h *bcryptHasher) NeedsRehash(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return err != nil || cost != h.cost
+}
+
+// PasswordManager hashes new passwords with the preferred algorithm, while still verifying hashes produced by
+// every supported algorithm, so stored hashes can be upgraded as users log in.
+type PasswordManager struct {
+	preferred PasswordHasher
+	hashers   []PasswordHasher
+}
+
+// NewPasswordManager creates a password manager preferring the algorithm and parameters from the config.
+func NewPasswordManager(cfg config.Auth) *PasswordManager {
+	argon2id := NewArgon2idHasher(Argon2idParams{
+		Memory:      cfg.Argon2Memory,
+		Iterations:  cfg.Argon2Iterations,
+		Parallelism: cfg.Argon2Parallelism,
+	})
+	bcryptHasher := NewBcryptHasher(cfg.BcryptCost)
+
+	preferred := argon2id
+	if cfg.PasswordAlgorithm == PasswordAlgorithmBcrypt {
+		preferred = bcryptHasher
+	}
+
+	return &PasswordManager{
+		preferred: preferred,
+		hashers:   []PasswordHasher{argon2id, bcryptHasher},
+	}
+}
+
+// Hash hashes a plain password with the preferred algorithm.
+func (m *PasswordManager) Hash(plain string) (string, error) {
+	return m.preferred.Hash(plain)
+}
+
+// Verify checks the plain password against a stored hash of any supported algorithm, returning
+// [ErrWrongCredentials] on mismatch. The returned bool reports whether the hash should be replaced with a fresh
+// one, because it uses another algorithm or outdated parameters.
+func (m *PasswordManager) Verify(plain, encoded string) (bool, error) {
+	for _, h := range m.hashers {
+		if !h.Handles(encoded) {
+			continue
+		}
+
+		ok, err := h.Verify(plain, encoded)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			return false, ErrWrongCredentials
+		}
+
+		return h != m.preferred || m.preferred.NeedsRehash(encoded), nil
+	}
+
+	return false, ErrWrongCredentials
+}
+
+var defaultPasswordManager = NewPasswordManager(config.Auth{})
+
+// HashPassword hashes a plain password with argon2id and its default parameters.
 func HashPassword(plain string) ([]byte, error) {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(plain), bcrypt.DefaultCost)
+	hashed, err := defaultPasswordManager.Hash(plain)
 	if err != nil {
 		return nil, err
 	}
-	return hashedPassword, nil
+	return []byte(hashed), nil
 }
 
 // VerifyPassword Verify / Decrypt user password
 func VerifyPassword(plain, hashed string) error {
-	err := bcrypt.CompareHashAndPassword([]byte(hashed), []byte(plain))
-	if err != nil {
-		return ErrWrongCredentials
-	}
-	return nil
+	_, err := defaultPasswordManager.Verify(plain, hashed)
+	return err
 }
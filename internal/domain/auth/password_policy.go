@@ -0,0 +1,229 @@
+// Package auth provides authentication and authorization functionality.
+// This package handles user authentication through sessions, access tokens, and
+// password management including secure hashing and password reset flows. It manages
+// the complete authentication lifecycle from login through logout, including token
+// generation, validation, and session management.
+package auth
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/prawirdani/golang-restapi/config"
+)
+
+const (
+	defaultPasswordMinLength = 8
+	defaultPasswordMaxLength = 128
+
+	// personalInfoMinLength is the shortest part of a name or email treated as personal information, shorter parts
+	// are too common to reject passwords over.
+	personalInfoMinLength = 3
+)
+
+// PasswordViolation is a password policy rule a password fails.
+type PasswordViolation struct {
+	Tag     string
+	Message string
+}
+
+// PasswordPolicy holds the rules new passwords must satisfy.
+type PasswordPolicy struct {
+	MinLength          int
+	MaxLength          int
+	MinCharClasses     int  // Distinct classes required among lowercase, uppercase, digits and symbols
+	HistorySize        int  // Most recent passwords, including the current one, that can't be reused
+	RejectPersonalInfo bool // Reject passwords containing the user's name or email
+	breached           *BreachedPasswords
+}
+
+// NewPasswordPolicy creates the password policy from the config, breached may be nil to skip the breach check.
+func NewPasswordPolicy(cfg config.Auth, breached *BreachedPasswords) *PasswordPolicy {
+	p := &PasswordPolicy{
+		MinLength:          cfg.PasswordMinLength,
+		MaxLength:          cfg.PasswordMaxLength,
+		MinCharClasses:     cfg.PasswordMinCharClasses,
+		HistorySize:        cfg.PasswordHistorySize,
+		RejectPersonalInfo: cfg.PasswordRejectPersonalInfo,
+		breached:           breached,
+	}
+	if p.MinLength <= 0 {
+		p.MinLength = defaultPasswordMinLength
+	}
+	if p.MaxLength <= 0 {
+		p.MaxLength = defaultPasswordMaxLength
+	}
+	return p
+}
+
+// Check reports every rule the password fails, given the name and email of its owner.
+// Reuse of previous passwords needs the stored hashes and is checked by the [Service].
+func (p *PasswordPolicy) Check(plain, name, email string) []PasswordViolation {
+	var violations []PasswordViolation
+
+	length := utf8.RuneCountInString(plain)
+	if length < p.MinLength {
+		violations = append(violations, PasswordViolation{
+			Tag:     "min",
+			Message: fmt.Sprintf("Must be at least %d characters long", p.MinLength),
+		})
+	}
+	if length > p.MaxLength {
+		violations = append(violations, PasswordViolation{
+			Tag:     "max",
+			Message: fmt.Sprintf("Must be at most %d characters long", p.MaxLength),
+		})
+	}
+
+	if p.MinCharClasses > 0 && charClasses(plain) < p.MinCharClasses {
+		violations = append(violations, PasswordViolation{
+			Tag: "char_classes",
+			Message: fmt.Sprintf(
+				"Must contain at least %d of: lowercase letters, uppercase letters, digits, symbols",
+				p.MinCharClasses,
+			),
+		})
+	}
+
+	if p.RejectPersonalInfo && containsPersonalInfo(plain, name, email) {
+		violations = append(violations, PasswordViolation{
+			Tag:     "personal_info",
+			Message: "Must not contain your name or email address",
+		})
+	}
+
+	if p.breached.Contains(plain) {
+		violations = append(violations, PasswordViolation{
+			Tag:     "breached",
+			Message: "This password has appeared in a data breach, choose a different one",
+		})
+	}
+
+	return violations
+}
+
+// charClasses counts the distinct character classes used by s.
+func charClasses(s string) int {
+	var lower, upper, digit, symbol int
+	for _, r := range s {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			symbol = 1
+		}
+	}
+	return lower + upper + digit + symbol
+}
+
+// containsPersonalInfo reports whether the password contains the email's local part or a word of the name, or is
+// itself part of them.
+func containsPersonalInfo(plain, name, email string) bool {
+	password := strings.ToLower(plain)
+
+	parts := strings.Fields(strings.ToLower(name))
+	if local, _, ok := strings.Cut(strings.ToLower(email), "@"); ok {
+		parts = append(parts, local)
+	}
+
+	for _, part := range parts {
+		if utf8.RuneCountInString(part) < personalInfoMinLength {
+			continue
+		}
+		if strings.Contains(password, part) || strings.Contains(part, password) {
+			return true
+		}
+	}
+	return false
+}
+
+// BreachedPasswords is an offline list of breached password SHA-1 hashes. Hashes are indexed by their 5 character
+// prefix like the k-anonymity range API of Have I Been Pwned, so a lookup only ever compares suffixes within a
+// single range.
+type BreachedPasswords struct {
+	ranges map[string]map[string]struct{}
+	size   int
+}
+
+// LoadBreachedPasswords loads the breached password list from a file, see [ParseBreachedPasswords] for the format.
+// An empty path returns a nil list, which contains no password.
+func LoadBreachedPasswords(path string) (*BreachedPasswords, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open breached password list: %w", err)
+	}
+	defer f.Close()
+
+	return ParseBreachedPasswords(f)
+}
+
+// ParseBreachedPasswords parses a breached password list holding one hex encoded SHA-1 hash per line, optionally
+// followed by ":<count>" as in the Have I Been Pwned downloads. Blank lines and lines starting with # are skipped.
+func ParseBreachedPasswords(r io.Reader) (*BreachedPasswords, error) {
+	b := &BreachedPasswords{ranges: make(map[string]map[string]struct{})}
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		hash, _, _ := strings.Cut(text, ":")
+		if len(hash) != sha1.Size*2 {
+			return nil, fmt.Errorf("breached password list line %d: invalid sha1 hash", line)
+		}
+		if _, err := hex.DecodeString(hash); err != nil {
+			return nil, fmt.Errorf("breached password list line %d: %w", line, err)
+		}
+
+		hash = strings.ToUpper(hash)
+		prefix, suffix := hash[:5], hash[5:]
+		if b.ranges[prefix] == nil {
+			b.ranges[prefix] = make(map[string]struct{})
+		}
+		b.ranges[prefix][suffix] = struct{}{}
+		b.size++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read breached password list: %w", err)
+	}
+
+	return b, nil
+}
+
+// Len returns the number of hashes in the list.
+func (b *BreachedPasswords) Len() int {
+	if b == nil {
+		return 0
+	}
+	return b.size
+}
+
+// Contains reports whether the password is on the list.
+func (b *BreachedPasswords) Contains(plain string) bool {
+	if b == nil {
+		return false
+	}
+
+	sum := sha1.Sum([]byte(plain))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	_, ok := b.ranges[hash[:5]][hash[5:]]
+	return ok
+}
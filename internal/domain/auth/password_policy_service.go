@@ -0,0 +1,82 @@
+// Package auth provides authentication and authorization functionality.
+// This package handles user authentication through sessions, access tokens, and
+// password management including secure hashing and password reset flows. It manages
+// the complete authentication lifecycle from login through logout, including token
+// generation, validation, and session management.
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prawirdani/golang-restapi/internal/domain/user"
+	"github.com/prawirdani/golang-restapi/pkg/validator"
+)
+
+// checkNewPassword validates a new password of the user against the password policy, including reuse of their
+// recent passwords. Violations are reported under the given request field as a [validator.ValidationError].
+func (s *Service) checkNewPassword(ctx context.Context, field, plain string, usr *user.User) error {
+	violations := s.pwdPolicy.Check(plain, usr.Name, usr.Email)
+
+	reused, err := s.passwordReused(ctx, usr, plain)
+	if err != nil {
+		return err
+	}
+	if reused {
+		violations = append(violations, PasswordViolation{
+			Tag:     "reused",
+			Message: fmt.Sprintf("Must not be one of your last %d passwords", s.pwdPolicy.HistorySize),
+		})
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	verr := new(validator.ValidationError)
+	for _, v := range violations {
+		verr.Add(field, v.Tag, v.Message)
+	}
+	return verr
+}
+
+// passwordReused reports whether the password matches the user's current password or one of the previous
+// passwords kept by the policy history.
+func (s *Service) passwordReused(ctx context.Context, usr *user.User, plain string) (bool, error) {
+	size := s.pwdPolicy.HistorySize
+	if size <= 0 || usr.Password == "" {
+		return false, nil
+	}
+
+	hashes := []string{usr.Password}
+	if size > 1 {
+		history, err := s.authRepo.GetPasswordHistory(ctx, usr.ID.String(), size-1)
+		if err != nil {
+			return false, err
+		}
+		hashes = append(hashes, history...)
+	}
+
+	for _, hash := range hashes {
+		_, err := s.passwords.Verify(plain, hash)
+		if err == nil {
+			return true, nil
+		}
+		if err != ErrWrongCredentials {
+			return false, err
+		}
+	}
+
+	return false, nil
+}
+
+// recordPasswordHistory keeps a replaced password hash, so the policy can reject reusing it.
+func (s *Service) recordPasswordHistory(ctx context.Context, userID, passwordHash string) error {
+	// The current password is always checked, the history only has to hold the ones before it
+	keep := s.pwdPolicy.HistorySize - 1
+	if keep <= 0 || passwordHash == "" {
+		return nil
+	}
+
+	return s.authRepo.StorePasswordHistory(ctx, userID, passwordHash, keep)
+}
@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/prawirdani/golang-restapi/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func violationTags(violations []PasswordViolation) []string {
+	tags := make([]string, 0, len(violations))
+	for _, v := range violations {
+		tags = append(tags, v.Tag)
+	}
+	return tags
+}
+
+func TestNewPasswordPolicyDefaults(t *testing.T) {
+	p := NewPasswordPolicy(config.Auth{}, nil)
+	assert.Equal(t, defaultPasswordMinLength, p.MinLength)
+	assert.Equal(t, defaultPasswordMaxLength, p.MaxLength)
+	assert.Empty(t, p.Check("correct horse battery", "John Doe", "john@example.com"))
+}
+
+func TestPasswordPolicyCheck(t *testing.T) {
+	p := NewPasswordPolicy(config.Auth{
+		PasswordMinLength:          10,
+		PasswordMaxLength:          20,
+		PasswordMinCharClasses:     3,
+		PasswordRejectPersonalInfo: true,
+	}, nil)
+
+	tests := []struct {
+		name     string
+		password string
+		want     []string
+	}{
+		{"valid", "Tr0ub4dor&3x", []string{}},
+		{"too-short", "Ab1!", []string{"min"}},
+		{"too-long", strings.Repeat("Ab1!", 6), []string{"max"}},
+		{"char-classes", "lowercaseonly", []string{"char_classes"}},
+		{"name", "Johnathan#2024", []string{"personal_info"}},
+		{"email-local-part", "xx-JOHN.DOE-99", []string{"personal_info"}},
+		{"multiple", "john", []string{"min", "char_classes", "personal_info"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := p.Check(tt.password, "John Doe", "john.doe@example.com")
+			assert.ElementsMatch(t, tt.want, violationTags(got))
+		})
+	}
+}
+
+func TestCharClasses(t *testing.T) {
+	assert.Equal(t, 0, charClasses(""))
+	assert.Equal(t, 1, charClasses("abc"))
+	assert.Equal(t, 2, charClasses("abcDEF"))
+	assert.Equal(t, 3, charClasses("abcDEF123"))
+	assert.Equal(t, 4, charClasses("abcDEF123!"))
+}
+
+func TestBreachedPasswords(t *testing.T) {
+	sum := sha1.Sum([]byte("password123"))
+	hash := hex.EncodeToString(sum[:])
+
+	list := strings.Join([]string{
+		"# breached passwords",
+		strings.ToUpper(hash) + ":251682",
+		"",
+		"5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8",
+	}, "\n")
+
+	b, err := ParseBreachedPasswords(strings.NewReader(list))
+	require.NoError(t, err)
+	assert.Equal(t, 2, b.Len())
+	assert.True(t, b.Contains("password123"))
+	assert.True(t, b.Contains("password"))
+	assert.False(t, b.Contains("correct horse battery"))
+
+	p := NewPasswordPolicy(config.Auth{}, b)
+	assert.Equal(t, []string{"breached"}, violationTags(p.Check("password123", "", "")))
+
+	t.Run("invalid-hash", func(t *testing.T) {
+		_, err := ParseBreachedPasswords(strings.NewReader("not-a-hash:1"))
+		assert.Error(t, err)
+	})
+
+	t.Run("nil-list", func(t *testing.T) {
+		var b *BreachedPasswords
+		assert.False(t, b.Contains("password"))
+		assert.Zero(t, b.Len())
+	})
+
+	t.Run("empty-path", func(t *testing.T) {
+		b, err := LoadBreachedPasswords("")
+		require.NoError(t, err)
+		assert.Nil(t, b)
+	})
+}
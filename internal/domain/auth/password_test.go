@@ -1,10 +1,13 @@
 package auth
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/prawirdani/golang-restapi/config"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
 )
 
 func TestHashPassword(t *testing.T) {
@@ -31,3 +34,83 @@ func TestVerifyPassword(t *testing.T) {
 		require.Equal(t, err, ErrWrongCredentials)
 	})
 }
+
+func TestArgon2idHasher(t *testing.T) {
+	hasher := NewArgon2idHasher(Argon2idParams{})
+
+	hashed, err := hasher.Hash("my_password")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hashed, "$argon2id$v=19$m=19456,t=2,p=1$"))
+	assert.True(t, hasher.Handles(hashed))
+	assert.False(t, hasher.NeedsRehash(hashed))
+
+	ok, err := hasher.Verify("my_password", hashed)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = hasher.Verify("wrong_password", hashed)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, err = hasher.Verify("my_password", "$argon2id$v=19$broken")
+	assert.Error(t, err)
+
+	stronger := NewArgon2idHasher(Argon2idParams{Iterations: 3})
+	assert.True(t, stronger.NeedsRehash(hashed))
+}
+
+func TestBcryptHasher(t *testing.T) {
+	hasher := NewBcryptHasher(bcrypt.MinCost)
+
+	hashed, err := hasher.Hash("my_password")
+	require.NoError(t, err)
+	assert.True(t, hasher.Handles(hashed))
+	assert.False(t, hasher.NeedsRehash(hashed))
+	assert.True(t, NewBcryptHasher(0).NeedsRehash(hashed))
+
+	ok, err := hasher.Verify("my_password", hashed)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	_, err = hasher.Hash(strings.Repeat("a", 73))
+	assert.ErrorIs(t, err, ErrPasswordTooLong)
+}
+
+func TestPasswordManagerVerify(t *testing.T) {
+	manager := NewPasswordManager(config.Auth{PasswordAlgorithm: PasswordAlgorithmArgon2id})
+
+	t.Run("current", func(t *testing.T) {
+		hashed, err := manager.Hash("my_password")
+		require.NoError(t, err)
+
+		rehash, err := manager.Verify("my_password", hashed)
+		require.NoError(t, err)
+		assert.False(t, rehash)
+	})
+
+	t.Run("legacy-bcrypt", func(t *testing.T) {
+		hashed, err := bcrypt.GenerateFromPassword([]byte("my_password"), bcrypt.MinCost)
+		require.NoError(t, err)
+
+		rehash, err := manager.Verify("my_password", string(hashed))
+		require.NoError(t, err)
+		assert.True(t, rehash)
+
+		_, err = manager.Verify("wrong_password", string(hashed))
+		assert.Equal(t, ErrWrongCredentials, err)
+	})
+
+	t.Run("outdated-params", func(t *testing.T) {
+		hashed, err := NewArgon2idHasher(Argon2idParams{Memory: 8 * 1024}).Hash("my_password")
+		require.NoError(t, err)
+
+		rehash, err := manager.Verify("my_password", hashed)
+		require.NoError(t, err)
+		assert.True(t, rehash)
+	})
+
+	t.Run("unknown-format", func(t *testing.T) {
+		_, err := manager.Verify("my_password", "plain-text")
+		assert.Equal(t, ErrWrongCredentials, err)
+	})
+}
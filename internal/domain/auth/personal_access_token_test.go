@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPersonalAccessToken(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("with expiry", func(t *testing.T) {
+		tok, plain, err := NewPersonalAccessToken(userID, "ci", []string{PermissionUsersRead}, time.Hour)
+		require.NoError(t, err)
+		assert.True(t, IsPersonalAccessToken(plain))
+		assert.Equal(t, HashPersonalAccessToken(plain), tok.TokenHash)
+		assert.NotContains(t, tok.TokenHash, PersonalAccessTokenPrefix)
+		assert.True(t, tok.ExpiresAt.NotNull())
+		assert.False(t, tok.Expired())
+	})
+
+	t.Run("without expiry", func(t *testing.T) {
+		tok, _, err := NewPersonalAccessToken(userID, "ci", nil, 0)
+		require.NoError(t, err)
+		assert.False(t, tok.ExpiresAt.NotNull())
+		assert.False(t, tok.Expired())
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		tok, _, err := NewPersonalAccessToken(userID, "ci", nil, time.Hour)
+		require.NoError(t, err)
+
+		tok.ExpiresAt.Set(time.Now().Add(-time.Minute), false)
+		assert.True(t, tok.Expired())
+	})
+}
+
+func TestIsPersonalAccessToken(t *testing.T) {
+	assert.True(t, IsPersonalAccessToken("pat_abc"))
+	assert.False(t, IsPersonalAccessToken("eyJhbGciOiJIUzI1NiJ9.e30.sig"))
+	assert.False(t, IsPersonalAccessToken(""))
+}
+
+func TestPersonalAccessToken_GrantedPermissions(t *testing.T) {
+	tok := PersonalAccessToken{Scopes: []string{PermissionUsersRead, PermissionRolesWrite}}
+
+	assert.Equal(t, []string{PermissionUsersRead}, tok.GrantedPermissions([]string{"users:*"}))
+	assert.Equal(t, tok.Scopes, tok.GrantedPermissions([]string{PermissionAll}))
+	assert.Empty(t, tok.GrantedPermissions(nil))
+}
@@ -0,0 +1,75 @@
+// Package auth provides authentication and authorization functionality.
+// This package handles user authentication through sessions, access tokens, and
+// password management including secure hashing and password reset flows. It manages
+// the complete authentication lifecycle from login through logout, including token
+// generation, validation, and session management.
+package auth
+
+import (
+	"context"
+
+	"github.com/prawirdani/golang-restapi/internal/domain/audit"
+)
+
+// ListRoles returns every role along with its permissions.
+func (s *Service) ListRoles(ctx context.Context) ([]*Role, error) {
+	return s.roleRepo.ListRoles(ctx)
+}
+
+// GetUserRoles returns the roles assigned to the user.
+func (s *Service) GetUserRoles(ctx context.Context, userID string) ([]*Role, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return s.roleRepo.GetRolesByUser(ctx, userID)
+}
+
+// AssignRole grants a role to the user on behalf of the actor. The new permissions apply once the user's access token
+// is refreshed. Roles holding permissions the actor lacks can't be assigned, so it never escalates privileges.
+func (s *Service) AssignRole(ctx context.Context, actor *AccessTokenClaims, userID string, inp AssignRoleInput) error {
+	// The token permissions may be stale, check the current ones
+	actorGrants, err := s.authorizer.Grants(ctx, actor.UserID)
+	if err != nil {
+		return err
+	}
+
+	if !PermissionGranted(actorGrants.Permissions, PermissionRolesWrite) {
+		return ErrPermissionDenied
+	}
+
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return err
+	}
+
+	role, err := s.roleRepo.GetRoleByName(ctx, inp.Role)
+	if err != nil {
+		return err
+	}
+
+	for _, perm := range role.Permissions {
+		if !PermissionGranted(actorGrants.Permissions, perm) {
+			return ErrRoleNotPermitted
+		}
+	}
+
+	if err := s.roleRepo.AssignRole(ctx, userID, role.ID); err != nil {
+		return err
+	}
+
+	return s.recordEvent(ctx, audit.EventRoleAssigned, userID, audit.Metadata{"role": role.Name})
+}
+
+// RevokeRole removes a role from the user.
+func (s *Service) RevokeRole(ctx context.Context, userID, roleName string) error {
+	role, err := s.roleRepo.GetRoleByName(ctx, roleName)
+	if err != nil {
+		return err
+	}
+
+	if err := s.roleRepo.RevokeRole(ctx, userID, role.ID); err != nil {
+		return err
+	}
+
+	return s.recordEvent(ctx, audit.EventRoleRevoked, userID, audit.Metadata{"role": role.Name})
+}
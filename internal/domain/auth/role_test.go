@@ -0,0 +1,63 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prawirdani/golang-restapi/internal/domain/auth"
+	"github.com/prawirdani/golang-restapi/internal/testing/mocks"
+)
+
+func TestPermissionGranted(t *testing.T) {
+	tests := []struct {
+		name     string
+		granted  []string
+		required string
+		want     bool
+	}{
+		{"exact", []string{"users:read"}, "users:read", true},
+		{"other action", []string{"users:read"}, "users:write", false},
+		{"resource wildcard", []string{"users:*"}, "users:write", true},
+		{"other resource wildcard", []string{"roles:*"}, "users:write", false},
+		{"global wildcard", []string{"*"}, "roles:write", true},
+		{"none", nil, "users:read", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, auth.PermissionGranted(tt.granted, tt.required))
+		})
+	}
+}
+
+func TestAuthorizer(t *testing.T) {
+	ctx := context.Background()
+	userID := "user-id"
+
+	roles := []*auth.Role{
+		{ID: 1, Name: "editor", Permissions: []string{"users:read", "users:write"}},
+		{ID: 2, Name: auth.RoleSupport, Permissions: []string{"users:read"}},
+	}
+
+	t.Run("Grants", func(t *testing.T) {
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockRoleRepo.EXPECT().GetRolesByUser(ctx, userID).Return(roles, nil)
+
+		grants, err := auth.NewAuthorizer(mockRoleRepo).Grants(ctx, userID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"editor", auth.RoleSupport}, grants.Roles)
+		assert.Equal(t, []string{"users:read", "users:write"}, grants.Permissions)
+	})
+
+	t.Run("Authorize", func(t *testing.T) {
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockRoleRepo.EXPECT().GetRolesByUser(ctx, userID).Return(roles, nil)
+
+		authorizer := auth.NewAuthorizer(mockRoleRepo)
+		assert.NoError(t, authorizer.Authorize(ctx, userID, auth.PermissionUsersWrite))
+		assert.Equal(t, auth.ErrPermissionDenied, authorizer.Authorize(ctx, userID, auth.PermissionRolesWrite))
+	})
+}
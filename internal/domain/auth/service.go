@@ -7,135 +7,388 @@ package auth
 
 import (
 	"context"
+	"errors"
+	"strings"
+	"sync"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/prawirdani/golang-restapi/config"
+	"github.com/prawirdani/golang-restapi/internal/domain/audit"
 	"github.com/prawirdani/golang-restapi/internal/domain/user"
 	"github.com/prawirdani/golang-restapi/internal/infrastructure/repository"
 	"github.com/prawirdani/golang-restapi/pkg/log"
 )
 
 type Service struct {
-	cfg        config.Auth
-	transactor repository.Transactor
-	authRepo   Repository
-	userRepo   user.Repository
-	publisher  MessagePublisher
+	cfg         config.Auth
+	keys        *KeySet
+	passwords   *PasswordManager
+	pwdPolicy   *PasswordPolicy
+	transactor  repository.Transactor
+	authRepo    Repository
+	roleRepo    RoleRepository
+	oauthRepo   OAuthRepository
+	authorizer  *Authorizer
+	userRepo    user.Repository
+	publisher   MessagePublisher
+	auditor     audit.Writer
+	revocations TokenRevocationStore
+	webauthn    WebAuthnRelyingParty
+	providers   map[string]IdentityProvider
+
+	dummyHashOnce sync.Once
+	dummyHash     string // Compared against on logins for unknown emails, see [Service.verifyDummyPassword]
 }
 
 func NewService(
 	cfg config.Auth,
+	keys *KeySet,
+	breached *BreachedPasswords,
 	transactor repository.Transactor,
 	userRepo user.Repository,
 	authRepo Repository,
+	roleRepo RoleRepository,
+	oauthRepo OAuthRepository,
 	publisher MessagePublisher,
+	auditor audit.Writer,
+	revocations TokenRevocationStore,
+	relyingParty WebAuthnRelyingParty,
+	providers ...IdentityProvider,
 ) *Service {
+	providerMap := make(map[string]IdentityProvider, len(providers))
+	for _, p := range providers {
+		providerMap[p.Name()] = p
+	}
+
 	return &Service{
-		cfg:        cfg,
-		transactor: transactor,
-		userRepo:   userRepo,
-		authRepo:   authRepo,
-		publisher:  publisher,
+		cfg:         cfg,
+		keys:        keys,
+		passwords:   NewPasswordManager(cfg),
+		pwdPolicy:   NewPasswordPolicy(cfg, breached),
+		transactor:  transactor,
+		userRepo:    userRepo,
+		authRepo:    authRepo,
+		roleRepo:    roleRepo,
+		oauthRepo:   oauthRepo,
+		authorizer:  NewAuthorizer(roleRepo),
+		publisher:   publisher,
+		auditor:     auditor,
+		revocations: revocations,
+		webauthn:    relyingParty,
+		providers:   providerMap,
 	}
 }
 
 func (s *Service) Register(ctx context.Context, inp RegisterInput) error {
+	if err := s.checkNewPassword(ctx, "password", inp.Password, &user.User{Name: inp.Name, Email: inp.Email}); err != nil {
+		return err
+	}
+
 	userExists, err := s.userRepo.GetByEmail(ctx, inp.Email)
 	if err != nil && err != user.ErrNotFound {
 		return err
 	}
 
-	if userExists != nil {
+	if userExists != nil && !s.cfg.EnumerationProtection {
 		return user.ErrEmailExists
 	}
 
-	hashedPassword, err := HashPassword(inp.Password)
+	// Hashed before handling a taken email too, so both outcomes take about as long.
+	hashedPassword, err := s.passwords.Hash(inp.Password)
 	if err != nil {
 		return err
 	}
 
+	if userExists != nil {
+		return s.transactor.Transact(ctx, func(ctx context.Context) error {
+			if err := s.recordEvent(ctx, audit.EventRegistrationDuplicate, userExists.ID.String(), nil); err != nil {
+				return err
+			}
+
+			return s.publisher.SendAccountExistsEmail(ctx, AccountExistsEmailMessage{
+				To:   userExists.Email,
+				Name: userExists.Name,
+			})
+		})
+	}
+
 	newUser, err := user.New(
 		inp.Name,
 		inp.Email,
 		inp.Phone,
-		string(hashedPassword),
+		hashedPassword,
 	)
 	if err != nil {
 		log.ErrorCtx(ctx, "Failed to create new user", err)
 		return err
 	}
 
-	return s.userRepo.Store(ctx, newUser)
+	err = s.transactor.Transact(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Store(ctx, newUser); err != nil {
+			return err
+		}
+
+		if err := s.recordEvent(ctx, audit.EventRegistered, newUser.ID.String(), nil); err != nil {
+			return err
+		}
+
+		return s.sendVerificationEmail(ctx, newUser)
+	})
+
+	// The email is taken by an account GetByEmail doesn't see, soft deleted but not purged yet, or registered
+	// concurrently. Its owner is notified like above, the name they registered with is unknown here and the one in
+	// the input can't be trusted, so they are greeted by their address.
+	if errors.Is(err, user.ErrEmailExists) && s.cfg.EnumerationProtection {
+		return s.publisher.SendAccountExistsEmail(ctx, AccountExistsEmailMessage{
+			To:   inp.Email,
+			Name: inp.Email,
+		})
+	}
+
+	return err
 }
 
-// Login is a method to authenticate the user, returning access token, refresh token, and error if any.
-func (s *Service) Login(
-	ctx context.Context,
-	inp LoginInput,
-) (accessToken string, sessID string, err error) {
+// Login authenticates the user with email and password. When the user has a second factor enabled no session is
+// created yet, instead the result carries an MFA challenge to be completed through [Service.VerifyMFA].
+func (s *Service) Login(ctx context.Context, inp LoginInput) (*LoginResult, error) {
+	if err := s.checkIPThrottle(ctx, inp.IPAddress); err != nil {
+		return nil, err
+	}
+
 	usr, err := s.userRepo.GetByEmail(ctx, inp.Email)
 	if err != nil {
 		if err == user.ErrNotFound {
-			return accessToken, sessID, ErrWrongCredentials
+			// With enumeration protection an unknown email is throttled like an account, and takes as long to reject
+			if s.cfg.EnumerationProtection {
+				if err := s.checkAttemptsThrottle(ctx, UnknownEmailAttemptsKey(inp.Email)); err != nil {
+					return nil, err
+				}
+			}
+			if err := s.recordEvent(ctx, audit.EventLoginFailed, "", audit.Metadata{
+				"email":  inp.Email,
+				"reason": "unknown_email",
+			}); err != nil {
+				return nil, err
+			}
+			if err := s.recordIPFailure(ctx, inp.IPAddress); err != nil {
+				return nil, err
+			}
+			if s.cfg.EnumerationProtection {
+				s.verifyDummyPassword(inp.Password)
+				if err := s.recordUnknownEmailFailure(ctx, inp.Email); err != nil {
+					return nil, err
+				}
+			}
+			return nil, ErrWrongCredentials
 		}
-		return accessToken, sessID, err
+		return nil, err
 	}
 
-	if err := VerifyPassword(inp.Password, usr.Password); err != nil {
-		return accessToken, sessID, err
+	if err := s.checkAccountThrottle(ctx, usr.ID.String()); err != nil {
+		return nil, err
 	}
 
-	accessToken, err = s.generateAccessToken(*usr)
+	rehash, err := s.passwords.Verify(inp.Password, usr.Password)
 	if err != nil {
-		log.ErrorCtx(ctx, "Failed to generate access token", err)
-		return accessToken, sessID, err
+		if err != ErrWrongCredentials {
+			return nil, err
+		}
+		if err := s.recordEvent(ctx, audit.EventLoginFailed, usr.ID.String(), audit.Metadata{
+			"reason": "wrong_password",
+		}); err != nil {
+			return nil, err
+		}
+		if err := s.recordLoginFailure(ctx, usr, inp.IPAddress); err != nil {
+			return nil, err
+		}
+		return nil, ErrWrongCredentials
 	}
 
-	sess, err := NewSession(
-		usr.ID,
-		inp.UserAgent,
-		s.cfg.SessionTTL,
-	)
+	if rehash {
+		s.rehashPassword(ctx, usr, inp.Password)
+	}
+
+	if s.cfg.RequireVerifiedEmail && !usr.IsVerified() {
+		return nil, user.ErrEmailNotVerified
+	}
+
+	res, err := s.completeLogin(ctx, usr, inp.UserAgent, inp.IPAddress, AMRPassword)
 	if err != nil {
-		log.ErrorCtx(ctx, "Failed to create new session", err)
-		return accessToken, sessID, err
+		return nil, err
 	}
 
-	if err = s.authRepo.StoreSession(ctx, sess); err != nil {
-		return accessToken, sessID, err
+	// With a second factor the failures are only forgotten once the MFA challenge is verified as well
+	if !res.MFARequired() {
+		if err := s.resetAccountFailures(ctx, usr.ID.String()); err != nil {
+			return nil, err
+		}
 	}
 
-	return accessToken, sess.ID.String(), nil
+	return res, nil
 }
 
-// TODO: Should also refreshing the refresh token, maybe by checking the exp time, if its nearly N to expire, then
-// refresh it.
-func (s *Service) RefreshAccessToken(
+// completeLogin finishes a login once the user passed the first factor. A new session is started, unless the user
+// has a second factor enabled, in which case only an MFA challenge is returned.
+func (s *Service) completeLogin(
 	ctx context.Context,
-	sessID string,
-) (string, error) {
-	sess, err := s.authRepo.GetSession(ctx, sessID)
+	usr *user.User,
+	userAgent string,
+	ip string,
+	firstFactor string,
+) (*LoginResult, error) {
+	if err := s.checkLoginAllowed(usr); err != nil {
+		return nil, err
+	}
+
+	mfaMethods, err := s.mfaMethods(ctx, usr.ID.String())
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	if sess.IsExpired() {
-		return "", ErrSessionExpired
+	if len(mfaMethods) > 0 {
+		challenge, err := NewMFAChallenge(usr.ID, userAgent, firstFactor, mfaMethods, s.cfg.MFAChallengeTTL)
+		if err != nil {
+			log.ErrorCtx(ctx, "Failed to create mfa challenge", err)
+			return nil, err
+		}
+
+		if err := s.authRepo.StoreMFAChallenge(ctx, challenge); err != nil {
+			return nil, err
+		}
+
+		return &LoginResult{MFAChallenge: challenge}, nil
 	}
 
-	usr, err := s.userRepo.GetByID(ctx, sess.UserID.String())
-	if err != nil {
-		return "", err
+	return s.createSession(ctx, usr, userAgent, ip, firstFactor)
+}
+
+// checkLoginAllowed rejects the login of a suspended user, or of a user who has to choose a new password first.
+func (s *Service) checkLoginAllowed(usr *user.User) error {
+	if usr.IsSuspended() {
+		return user.ErrSuspended
 	}
 
-	newAccessToken, err := s.generateAccessToken(*usr)
+	if usr.PasswordResetRequired {
+		return ErrPasswordResetRequired
+	}
+
+	return nil
+}
+
+// RefreshAccessToken exchanges a session ID for a new access token and rotates the session, so every session ID
+// can only be used once. Presenting an already rotated session ID revokes the whole session family, since it means
+// the refresh token has leaked to another party.
+//
+// A refresh from another user agent than the session was created with is recorded in the audit log. When
+// RejectUserAgentChange is set it revokes the session family as well, failing with [ErrSessionUserAgentChanged].
+func (s *Service) RefreshAccessToken(
+	ctx context.Context,
+	sessID string,
+	userAgent string,
+) (*LoginResult, error) {
+	var (
+		result       *LoginResult
+		reused       bool
+		agentChanged bool
+	)
+
+	err := s.transactor.Transact(ctx, func(ctx context.Context) error {
+		sess, err := s.authRepo.GetSession(ctx, sessID)
+		if err != nil {
+			return err
+		}
+
+		if sess.IsRotated() {
+			// The revocation must commit, so the error is returned outside the transaction
+			reused = true
+			log.WarnCtx(ctx, "Rotated refresh token reused, revoking session family",
+				"family_id", sess.FamilyID.String(),
+				"user_id", sess.UserID.String(),
+			)
+			if err := s.revokeSessionFamily(ctx, sess.FamilyID.String()); err != nil {
+				return err
+			}
+
+			return s.recordEvent(ctx, audit.EventSessionReused, sess.UserID.String(), audit.Metadata{
+				"session_id": sess.FamilyID.String(),
+			})
+		}
+
+		if sess.IsExpired() {
+			return ErrSessionExpired
+		}
+
+		usr, err := s.userRepo.GetByID(ctx, sess.UserID.String())
+		if err != nil {
+			return err
+		}
+
+		if usr.IsSuspended() {
+			return user.ErrSuspended
+		}
+
+		if NormalizeUserAgent(userAgent) != NormalizeUserAgent(sess.UserAgent) {
+			log.WarnCtx(ctx, "Session refreshed from another user agent",
+				"family_id", sess.FamilyID.String(),
+				"user_id", sess.UserID.String(),
+			)
+			if err := s.recordEvent(ctx, audit.EventSessionAgentChanged, sess.UserID.String(), audit.Metadata{
+				"session_id":         sess.FamilyID.String(),
+				"session_user_agent": sess.UserAgent,
+				"rejected":           s.cfg.RejectUserAgentChange,
+			}); err != nil {
+				return err
+			}
+
+			if s.cfg.RejectUserAgentChange {
+				// The revocation must commit, so the error is returned outside the transaction
+				agentChanged = true
+				return s.revokeSessionFamily(ctx, sess.FamilyID.String())
+			}
+		}
+
+		next, err := sess.Rotate(s.cfg.SessionTTL)
+		if err != nil {
+			log.ErrorCtx(ctx, "Failed to rotate session", err)
+			return err
+		}
+
+		if err := s.authRepo.UpdateSession(ctx, sess); err != nil {
+			return err
+		}
+
+		if err := s.authRepo.StoreSession(ctx, next); err != nil {
+			return err
+		}
+
+		accessToken, err := s.generateAccessToken(ctx, *usr, next)
+		if err != nil {
+			log.ErrorCtx(ctx, "Failed to generate new access token", err)
+			return err
+		}
+
+		result = &LoginResult{
+			AccessToken: accessToken,
+			SessionID:   next.ID.String(),
+		}
+		return nil
+	})
 	if err != nil {
-		log.ErrorCtx(ctx, "Failed to generate new access token", err)
-		return "", err
+		return nil, err
 	}
 
-	return newAccessToken, nil
+	if reused {
+		return nil, ErrSessionReused
+	}
+
+	if agentChanged {
+		return nil, ErrSessionUserAgentChanged
+	}
+
+	return result, nil
 }
 
+// Logout revokes the session along with the access tokens issued from it.
 func (s *Service) Logout(ctx context.Context, sessID string) error {
 	session, err := s.authRepo.GetSession(ctx, sessID)
 	if err != nil {
@@ -148,7 +401,17 @@ func (s *Service) Logout(ctx context.Context, sessID string) error {
 
 	session.Revoke()
 
-	return s.authRepo.UpdateSession(ctx, session)
+	if err := s.authRepo.UpdateSession(ctx, session); err != nil {
+		return err
+	}
+
+	if err := s.revokeAccessTokens(ctx, session.FamilyID.String()); err != nil {
+		return err
+	}
+
+	return s.recordEvent(ctx, audit.EventLogout, session.UserID.String(), audit.Metadata{
+		"session_id": session.FamilyID.String(),
+	})
 }
 
 // ForgotPassword initiates the password reset process by sending a reset link or token to the user's email.
@@ -157,31 +420,76 @@ func (s *Service) ForgotPassword(ctx context.Context, inp ForgotPasswordInput) e
 		usr, err := s.userRepo.GetByEmail(ctx, inp.Email)
 		if err != nil {
 			if err == user.ErrNotFound {
-				return user.ErrEmailNotVerified
+				return s.hideUnknownEmail(user.ErrEmailNotVerified)
 			}
 			return err
 		}
 
-		token, err := NewResetPasswordToken(usr.ID, s.cfg.ResetPasswordTTL)
+		if s.cfg.RequireVerifiedEmail && !usr.IsVerified() {
+			return s.hideUnknownEmail(user.ErrEmailNotVerified)
+		}
+
+		if err := s.recordEvent(ctx, audit.EventPasswordResetRequested, usr.ID.String(), nil); err != nil {
+			return err
+		}
+
+		return s.sendResetPasswordEmail(ctx, usr)
+	})
+}
+
+// ForcePasswordReset makes the user choose a new password before signing in again, on behalf of an administrator.
+// Every session of the user is revoked and a reset link is emailed to them.
+func (s *Service) ForcePasswordReset(ctx context.Context, userID string) error {
+	return s.transactor.Transact(ctx, func(ctx context.Context) error {
+		usr, err := s.userRepo.GetByID(ctx, userID)
 		if err != nil {
-			log.ErrorCtx(ctx, "Failed to create reset password token", err)
 			return err
 		}
 
-		// Save token to db
-		if err := s.authRepo.StoreResetPasswordToken(ctx, token); err != nil {
+		usr.PasswordResetRequired = true
+		if err := s.userRepo.Update(ctx, usr); err != nil {
 			return err
 		}
 
-		// Publish email job to message queue
-		msg := ResetPasswordEmailMessage{
-			To:       usr.Email,
-			Name:     usr.Name,
-			ResetURL: s.cfg.ResetPasswordFormEndpoint + "?token=" + token.Value,
-			Expiry:   s.cfg.ResetPasswordTTL,
+		if err := s.revokeUserSessions(ctx, userID, ""); err != nil {
+			return err
+		}
+
+		if err := s.recordEvent(ctx, audit.EventPasswordResetForced, userID, nil); err != nil {
+			return err
 		}
 
-		return s.publisher.SendResetPasswordEmail(ctx, msg)
+		return s.sendResetPasswordEmail(ctx, usr)
+	})
+}
+
+// SuspendUser blocks the user from signing in, on behalf of an administrator. Every session of the user is revoked
+// and the access tokens issued from them are denylisted, as are the grants of OAuth clients, so any access they still
+// have is cut off at once.
+func (s *Service) SuspendUser(ctx context.Context, userID string) error {
+	return s.transactor.Transact(ctx, func(ctx context.Context) error {
+		usr, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		if err := usr.Suspend(); err != nil {
+			return err
+		}
+
+		if err := s.userRepo.Update(ctx, usr); err != nil {
+			return err
+		}
+
+		if err := s.revokeUserSessions(ctx, userID, ""); err != nil {
+			return err
+		}
+
+		if err := s.revokeOAuthGrants(ctx, userID); err != nil {
+			return err
+		}
+
+		return s.recordEvent(ctx, audit.EventAccountSuspended, userID, nil)
 	})
 }
 
@@ -209,19 +517,42 @@ func (s *Service) ResetPassword(ctx context.Context, inp ResetPasswordInput) err
 			return err
 		}
 
-		newHashedPassword, err := HashPassword(inp.NewPassword)
+		if err := s.checkNewPassword(ctx, "new_password", inp.NewPassword, user); err != nil {
+			return err
+		}
+
+		newHashedPassword, err := s.passwords.Hash(inp.NewPassword)
 		if err != nil {
 			log.ErrorCtx(ctx, "Failed to hash new password", err)
 			return err
 		}
-		user.Password = string(newHashedPassword)
+
+		if err := s.recordPasswordHistory(ctx, user.ID.String(), user.Password); err != nil {
+			return err
+		}
+		user.Password = newHashedPassword
+		user.PasswordResetRequired = false
 
 		token.Revoke()
 		if err := s.authRepo.UpdateResetPasswordToken(ctx, token); err != nil {
 			return err
 		}
 
-		return s.userRepo.Update(ctx, user)
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+
+		// A fresh password lifts any lockout caused by guesses at the old one
+		if err := s.resetAccountFailures(ctx, user.ID.String()); err != nil {
+			return err
+		}
+
+		// The password may have been reset because the account was compromised, sign out everywhere
+		if err := s.revokeUserSessions(ctx, user.ID.String(), ""); err != nil {
+			return err
+		}
+
+		return s.recordEvent(ctx, audit.EventPasswordReset, user.ID.String(), nil)
 	})
 }
 
@@ -237,25 +568,383 @@ func (s *Service) ChangePassword(
 	}
 
 	// Verify old password
-	if err := VerifyPassword(inp.Password, u.Password); err != nil {
+	if err := s.confirmPassword(ctx, u, inp.Password, inp.IPAddress); err != nil {
+		return err
+	}
+
+	if err := s.checkNewPassword(ctx, "new_password", inp.NewPassword, u); err != nil {
 		return err
 	}
 
 	// Hash new password
-	newHashedPassword, err := HashPassword(inp.NewPassword)
+	newHashedPassword, err := s.passwords.Hash(inp.NewPassword)
 	if err != nil {
 		return err
 	}
 
-	u.Password = string(newHashedPassword)
+	oldHashedPassword := u.Password
+	u.Password = newHashedPassword
+
+	return s.transactor.Transact(ctx, func(ctx context.Context) error {
+		if err := s.recordPasswordHistory(ctx, userID, oldHashedPassword); err != nil {
+			return err
+		}
+
+		if err := s.userRepo.Update(ctx, u); err != nil {
+			return err
+		}
 
-	return s.userRepo.Update(ctx, u)
+		// Sign out every other device, they may have been using the old password
+		if err := s.revokeUserSessions(ctx, userID, inp.SessionID); err != nil {
+			return err
+		}
+
+		return s.recordEvent(ctx, audit.EventPasswordChanged, userID, nil)
+	})
 }
 
-func (s *Service) generateAccessToken(user user.User) (string, error) {
-	return SignAccessToken(
-		s.cfg.JwtSecret,
-		AccessTokenClaims{UserID: user.ID.String()},
-		s.cfg.JwtTTL,
-	)
+// DeleteAccount soft deletes the user after re-confirming their password and signs out every session.
+// Accounts without a password, e.g. created from an external identity, rely on the recent authentication the route
+// requires instead. The account is purged by the worker once the deletion grace period is over.
+func (s *Service) DeleteAccount(ctx context.Context, userID string, inp DeleteAccountInput) error {
+	usr, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	// Confirmed outside the transaction, a wrong password must be counted even though the deletion is aborted
+	if usr.HasPassword() {
+		if inp.Password == "" {
+			return user.ErrRequiredPassword
+		}
+		if err := s.confirmPassword(ctx, usr, inp.Password, inp.IPAddress); err != nil {
+			return err
+		}
+	}
+
+	return s.transactor.Transact(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Delete(ctx, usr); err != nil {
+			return err
+		}
+
+		if err := s.revokeUserSessions(ctx, userID, ""); err != nil {
+			return err
+		}
+
+		return s.recordEvent(ctx, audit.EventAccountDeleted, userID, nil)
+	})
+}
+
+// VerifyEmail marks the user's email address as verified using a valid verification token from email.
+func (s *Service) VerifyEmail(ctx context.Context, inp VerifyEmailInput) error {
+	return s.transactor.Transact(ctx, func(ctx context.Context) error {
+		token, err := s.authRepo.GetEmailVerificationToken(ctx, inp.Token)
+		if err != nil {
+			return err
+		}
+
+		if token.Expired() || token.Used() {
+			return ErrEmailVerificationTokenInvalid
+		}
+
+		usr, err := s.userRepo.GetByID(ctx, token.UserID.String())
+		if err != nil {
+			return err
+		}
+
+		token.Revoke()
+		if err := s.authRepo.UpdateEmailVerificationToken(ctx, token); err != nil {
+			return err
+		}
+
+		// Token is consumed either way, but keep the original verification time
+		if usr.IsVerified() {
+			return nil
+		}
+
+		usr.MarkVerified()
+		if err := s.userRepo.Update(ctx, usr); err != nil {
+			return err
+		}
+
+		return s.recordEvent(ctx, audit.EventEmailVerified, usr.ID.String(), audit.Metadata{"email": usr.Email})
+	})
+}
+
+// ResendVerificationEmail issues a new verification token and sends it to the user's email.
+func (s *Service) ResendVerificationEmail(
+	ctx context.Context,
+	inp ResendVerificationEmailInput,
+) error {
+	return s.transactor.Transact(ctx, func(ctx context.Context) error {
+		usr, err := s.userRepo.GetByEmail(ctx, inp.Email)
+		if err != nil {
+			if err == user.ErrNotFound {
+				return s.hideUnknownEmail(user.ErrEmailNotVerified)
+			}
+			return err
+		}
+
+		if usr.IsVerified() {
+			return s.hideUnknownEmail(ErrEmailAlreadyVerified)
+		}
+
+		if err := s.recordEvent(ctx, audit.EventEmailVerificationSent, usr.ID.String(), nil); err != nil {
+			return err
+		}
+
+		return s.sendVerificationEmail(ctx, usr)
+	})
+}
+
+// sendVerificationEmail stores a new verification token for the user and publishes the email job.
+// Should be called inside a transaction so the token is discarded when publishing fails.
+func (s *Service) sendVerificationEmail(ctx context.Context, usr *user.User) error {
+	token, err := NewEmailVerificationToken(usr.ID, s.cfg.EmailVerificationTTL)
+	if err != nil {
+		log.ErrorCtx(ctx, "Failed to create email verification token", err)
+		return err
+	}
+
+	if err := s.authRepo.StoreEmailVerificationToken(ctx, token); err != nil {
+		return err
+	}
+
+	msg := VerificationEmailMessage{
+		To:              usr.Email,
+		Name:            usr.Name,
+		VerificationURL: s.cfg.EmailVerificationEndpoint + "?token=" + token.Value,
+		Expiry:          s.cfg.EmailVerificationTTL,
+	}
+
+	return s.publisher.SendVerificationEmail(ctx, msg)
+}
+
+// RequestEmailChange mails a confirmation link to the new address and warns the current address.
+// The email is only swapped once the link is confirmed with [Service.ConfirmEmailChange].
+func (s *Service) RequestEmailChange(ctx context.Context, userID, newEmail string) error {
+	return s.transactor.Transact(ctx, func(ctx context.Context) error {
+		usr, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		if strings.EqualFold(usr.Email, newEmail) {
+			return ErrEmailUnchanged
+		}
+
+		if _, err := s.userRepo.GetByEmail(ctx, newEmail); err == nil {
+			return user.ErrEmailExists
+		} else if err != user.ErrNotFound {
+			return err
+		}
+
+		token, err := NewEmailChangeToken(usr.ID, newEmail, s.cfg.EmailChangeTTL)
+		if err != nil {
+			log.ErrorCtx(ctx, "Failed to create email change token", err)
+			return err
+		}
+
+		if err := s.authRepo.StoreEmailChangeToken(ctx, token); err != nil {
+			return err
+		}
+
+		if err := s.recordEvent(ctx, audit.EventEmailChangeRequested, userID, audit.Metadata{
+			"new_email": newEmail,
+		}); err != nil {
+			return err
+		}
+
+		if err := s.publisher.SendEmailChangeConfirmation(ctx, EmailChangeConfirmationMessage{
+			To:              newEmail,
+			Name:            usr.Name,
+			ConfirmationURL: s.cfg.EmailChangeEndpoint + "?token=" + token.Value,
+			Expiry:          s.cfg.EmailChangeTTL,
+		}); err != nil {
+			return err
+		}
+
+		return s.publisher.SendEmailChangeNotification(ctx, EmailChangeNotificationMessage{
+			To:       usr.Email,
+			Name:     usr.Name,
+			NewEmail: newEmail,
+		})
+	})
+}
+
+// ConfirmEmailChange swaps the user's email for the address the token was mailed to. Receiving the
+// token proves ownership of the new address, so it is marked as verified.
+func (s *Service) ConfirmEmailChange(ctx context.Context, inp ConfirmEmailChangeInput) error {
+	return s.transactor.Transact(ctx, func(ctx context.Context) error {
+		token, err := s.authRepo.GetEmailChangeToken(ctx, inp.Token)
+		if err != nil {
+			return err
+		}
+
+		if token.Expired() || token.Used() {
+			return ErrEmailChangeTokenInvalid
+		}
+
+		usr, err := s.userRepo.GetByID(ctx, token.UserID.String())
+		if err != nil {
+			return err
+		}
+
+		token.Revoke()
+		if err := s.authRepo.UpdateEmailChangeToken(ctx, token); err != nil {
+			return err
+		}
+
+		oldEmail := usr.Email
+		usr.Email = token.NewEmail
+		usr.MarkVerified()
+
+		// Unique constraint still guards against the address being taken since the request
+		if err := s.userRepo.Update(ctx, usr); err != nil {
+			return err
+		}
+
+		return s.recordEvent(ctx, audit.EventEmailChanged, usr.ID.String(), audit.Metadata{
+			"old_email": oldEmail,
+			"new_email": usr.Email,
+		})
+	})
+}
+
+// JWKS returns the public keys that verify access tokens issued by the service.
+func (s *Service) JWKS() JWKSet {
+	return s.keys.JWKS()
+}
+
+// sendResetPasswordEmail stores a new reset password token for the user and publishes the email carrying it.
+func (s *Service) sendResetPasswordEmail(ctx context.Context, usr *user.User) error {
+	token, err := NewResetPasswordToken(usr.ID, s.cfg.ResetPasswordTTL)
+	if err != nil {
+		log.ErrorCtx(ctx, "Failed to create reset password token", err)
+		return err
+	}
+
+	// Save token to db
+	if err := s.authRepo.StoreResetPasswordToken(ctx, token); err != nil {
+		return err
+	}
+
+	// Publish email job to message queue
+	msg := ResetPasswordEmailMessage{
+		To:       usr.Email,
+		Name:     usr.Name,
+		ResetURL: s.cfg.ResetPasswordFormEndpoint + "?token=" + token.Value,
+		Expiry:   s.cfg.ResetPasswordTTL,
+	}
+
+	return s.publisher.SendResetPasswordEmail(ctx, msg)
+}
+
+// rehashPassword replaces a stored password hash that uses an outdated algorithm or parameters with a fresh one.
+// Failing to do so doesn't fail the login, it is retried on the next one.
+func (s *Service) rehashPassword(ctx context.Context, usr *user.User, plain string) {
+	hashed, err := s.passwords.Hash(plain)
+	if err != nil {
+		log.ErrorCtx(ctx, "Failed to rehash password", err)
+		return
+	}
+
+	usr.Password = hashed
+	if err := s.userRepo.Update(ctx, usr); err != nil {
+		log.ErrorCtx(ctx, "Failed to store rehashed password", err)
+	}
+}
+
+// createSession starts a new session for an authenticated user, returning the access token and session ID.
+// The client is recorded among the user's known devices, see [Service.recognizeDevice].
+func (s *Service) createSession(
+	ctx context.Context,
+	usr *user.User,
+	userAgent string,
+	ip string,
+	amr ...string,
+) (*LoginResult, error) {
+	sess, err := NewSession(usr.ID, userAgent, s.cfg.SessionTTL, amr...)
+	if err != nil {
+		log.ErrorCtx(ctx, "Failed to create new session", err)
+		return nil, err
+	}
+
+	accessToken, err := s.generateAccessToken(ctx, *usr, sess)
+	if err != nil {
+		log.ErrorCtx(ctx, "Failed to generate access token", err)
+		return nil, err
+	}
+
+	if err = s.authRepo.StoreSession(ctx, sess); err != nil {
+		return nil, err
+	}
+
+	if err := s.recordEvent(ctx, audit.EventLoginSucceeded, usr.ID.String(), audit.Metadata{
+		"session_id": sess.FamilyID.String(),
+		"amr":        sess.AMR,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := s.recognizeDevice(ctx, usr, sess, ip); err != nil {
+		return nil, err
+	}
+
+	return &LoginResult{
+		AccessToken: accessToken,
+		SessionID:   sess.ID.String(),
+	}, nil
+}
+
+// recordEvent writes an audit event about the user, inside the transaction of ctx if any.
+func (s *Service) recordEvent(ctx context.Context, eventType audit.EventType, userID string, metadata audit.Metadata) error {
+	return audit.Record(ctx, s.auditor, eventType, userID, metadata)
+}
+
+// generateAccessToken signs an access token for the user, bound to the session family it was issued from
+// and carrying the roles and permissions the user currently holds.
+func (s *Service) generateAccessToken(ctx context.Context, user user.User, sess *Session) (string, error) {
+	grants, err := s.authorizer.Grants(ctx, user.ID.String())
+	if err != nil {
+		return "", err
+	}
+
+	claims := AccessTokenClaims{
+		UserID:      user.ID.String(),
+		SessionID:   sess.FamilyID.String(),
+		AMR:         sess.AMR,
+		Roles:       grants.Roles,
+		Permissions: grants.Permissions,
+		AuthTime:    jwt.NewNumericDate(sess.AuthenticatedAt),
+	}
+	if sess.OrganizationID.Valid {
+		claims.OrgID = sess.OrganizationID.UUID.String()
+	}
+
+	return SignAccessToken(s.keys, claims, s.cfg.JwtTTL)
+}
+
+// hideUnknownEmail returns err, or nil when enumeration protection is enabled, so an email based request answers
+// the same whether or not the email belongs to an account in the expected state.
+func (s *Service) hideUnknownEmail(err error) error {
+	if s.cfg.EnumerationProtection {
+		return nil
+	}
+	return err
+}
+
+// verifyDummyPassword compares the password against a throwaway hash, so a login for an unknown email takes about
+// as long as one with a wrong password. The hash is created on first use with the preferred algorithm.
+func (s *Service) verifyDummyPassword(plain string) {
+	s.dummyHashOnce.Do(func() {
+		hashed, err := s.passwords.Hash("enumeration-protection-dummy-password")
+		if err != nil {
+			log.Error("Failed to create dummy password hash", err)
+			return
+		}
+		s.dummyHash = hashed
+	})
+
+	_, _ = s.passwords.Verify(plain, s.dummyHash)
 }
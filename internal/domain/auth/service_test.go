@@ -2,21 +2,36 @@ package auth_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/pquerna/otp/totp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/prawirdani/golang-restapi/config"
+	"github.com/prawirdani/golang-restapi/internal/domain/audit"
 	"github.com/prawirdani/golang-restapi/internal/domain/auth"
 	"github.com/prawirdani/golang-restapi/internal/domain/user"
 	"github.com/prawirdani/golang-restapi/internal/testing/mocks"
+	"github.com/prawirdani/golang-restapi/pkg/validator"
 )
 
+var testKeys = auth.NewHMACKeySet("test-secret")
+
+// expectKnownDevice expects a login to record the first known device of the user, which sends no notification.
+func expectKnownDevice(authRepo *mocks.AuthRepository) {
+	authRepo.EXPECT().ListKnownDevicesByUser(mock.Anything, mock.Anything).Return([]*auth.KnownDevice{}, nil)
+	authRepo.EXPECT().StoreKnownDevice(mock.Anything, mock.AnythingOfType("*auth.KnownDevice")).Return(nil)
+}
+
 func TestService_Register(t *testing.T) {
 	ctx := context.Background()
 	cfg := config.Auth{
@@ -32,9 +47,10 @@ func TestService_Register(t *testing.T) {
 		mockTransactor := mocks.NewTransactor(t)
 		mockUserRepo := mocks.NewUserRepository(t)
 		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
 		mockPublisher := mocks.NewAuthMessagePublisher(t)
 
-		service := auth.NewService(cfg, mockTransactor, mockUserRepo, mockAuthRepo, mockPublisher)
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
 
 		input := auth.RegisterInput{
 			Name:           "John Doe",
@@ -46,7 +62,14 @@ func TestService_Register(t *testing.T) {
 
 		// Mock expectations
 		mockUserRepo.EXPECT().GetByEmail(ctx, input.Email).Return(nil, user.ErrNotFound)
-		mockUserRepo.EXPECT().Store(ctx, mock.AnythingOfType("*user.User")).Return(nil)
+		mockTransactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).Return(nil).Run(func(ctx context.Context, fn func(context.Context) error) {
+			mockUserRepo.EXPECT().Store(ctx, mock.AnythingOfType("*user.User")).Return(nil)
+			mockAuthRepo.EXPECT().StoreEmailVerificationToken(ctx, mock.AnythingOfType("*auth.EmailVerificationToken")).Return(nil)
+			mockPublisher.EXPECT().SendVerificationEmail(ctx, mock.AnythingOfType("auth.VerificationEmailMessage")).Return(nil)
+
+			err := fn(ctx)
+			assert.NoError(t, err)
+		})
 
 		// Execute
 		err := service.Register(ctx, input)
@@ -60,9 +83,10 @@ func TestService_Register(t *testing.T) {
 		mockTransactor := mocks.NewTransactor(t)
 		mockUserRepo := mocks.NewUserRepository(t)
 		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
 		mockPublisher := mocks.NewAuthMessagePublisher(t)
 
-		service := auth.NewService(cfg, mockTransactor, mockUserRepo, mockAuthRepo, mockPublisher)
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
 
 		input := auth.RegisterInput{
 			Name:           "John Doe",
@@ -93,9 +117,10 @@ func TestService_Register(t *testing.T) {
 		mockTransactor := mocks.NewTransactor(t)
 		mockUserRepo := mocks.NewUserRepository(t)
 		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
 		mockPublisher := mocks.NewAuthMessagePublisher(t)
 
-		service := auth.NewService(cfg, mockTransactor, mockUserRepo, mockAuthRepo, mockPublisher)
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
 
 		input := auth.RegisterInput{
 			Name:           "John Doe",
@@ -133,9 +158,10 @@ func TestService_Login(t *testing.T) {
 		mockTransactor := mocks.NewTransactor(t)
 		mockUserRepo := mocks.NewUserRepository(t)
 		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
 		mockPublisher := mocks.NewAuthMessagePublisher(t)
 
-		service := auth.NewService(cfg, mockTransactor, mockUserRepo, mockAuthRepo, mockPublisher)
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
 
 		input := auth.LoginInput{
 			Email:     "john@example.com",
@@ -155,15 +181,69 @@ func TestService_Login(t *testing.T) {
 
 		// Mock expectations
 		mockUserRepo.EXPECT().GetByEmail(ctx, input.Email).Return(testUser, nil)
+		mockAuthRepo.EXPECT().
+			GetTOTPFactor(ctx, testUser.ID.String()).
+			Return(nil, auth.ErrTOTPFactorNotFound)
+		mockRoleRepo.EXPECT().GetRolesByUser(ctx, testUser.ID.String()).Return([]*auth.Role{}, nil)
+		expectKnownDevice(mockAuthRepo)
 		mockAuthRepo.EXPECT().StoreSession(ctx, mock.AnythingOfType("*auth.Session")).Return(nil)
 
 		// Execute
-		accessToken, sessionID, err := service.Login(ctx, input)
+		res, err := service.Login(ctx, input)
 
 		// Assert
-		assert.NoError(t, err)
-		assert.NotEmpty(t, accessToken)
-		assert.NotEmpty(t, sessionID)
+		require.NoError(t, err)
+		assert.False(t, res.MFARequired())
+		assert.NotEmpty(t, res.AccessToken)
+		assert.NotEmpty(t, res.SessionID)
+	})
+
+	t.Run("RehashesLegacyPassword", func(t *testing.T) {
+		// Setup
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		input := auth.LoginInput{
+			Email:    "john@example.com",
+			Password: "password123",
+		}
+
+		legacyHash, err := auth.NewBcryptHasher(0).Hash("password123")
+		require.NoError(t, err)
+
+		testUser := &user.User{
+			ID:       uuid.New(),
+			Name:     "John Doe",
+			Email:    input.Email,
+			Password: legacyHash,
+		}
+
+		// Mock expectations
+		mockUserRepo.EXPECT().GetByEmail(ctx, input.Email).Return(testUser, nil)
+		mockUserRepo.EXPECT().
+			Update(ctx, mock.MatchedBy(func(u *user.User) bool {
+				return strings.HasPrefix(u.Password, "$argon2id$")
+			})).
+			Return(nil)
+		mockAuthRepo.EXPECT().
+			GetTOTPFactor(ctx, testUser.ID.String()).
+			Return(nil, auth.ErrTOTPFactorNotFound)
+		mockRoleRepo.EXPECT().GetRolesByUser(ctx, testUser.ID.String()).Return([]*auth.Role{}, nil)
+		expectKnownDevice(mockAuthRepo)
+		mockAuthRepo.EXPECT().StoreSession(ctx, mock.AnythingOfType("*auth.Session")).Return(nil)
+
+		// Execute
+		res, err := service.Login(ctx, input)
+
+		// Assert
+		require.NoError(t, err)
+		assert.NotEmpty(t, res.AccessToken)
+		assert.NoError(t, auth.VerifyPassword("password123", testUser.Password))
 	})
 
 	t.Run("UserNotFound", func(t *testing.T) {
@@ -171,9 +251,10 @@ func TestService_Login(t *testing.T) {
 		mockTransactor := mocks.NewTransactor(t)
 		mockUserRepo := mocks.NewUserRepository(t)
 		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
 		mockPublisher := mocks.NewAuthMessagePublisher(t)
 
-		service := auth.NewService(cfg, mockTransactor, mockUserRepo, mockAuthRepo, mockPublisher)
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
 
 		input := auth.LoginInput{
 			Email:    "nonexistent@example.com",
@@ -184,13 +265,12 @@ func TestService_Login(t *testing.T) {
 		mockUserRepo.EXPECT().GetByEmail(ctx, input.Email).Return(nil, user.ErrNotFound)
 
 		// Execute
-		accessToken, sessionID, err := service.Login(ctx, input)
+		res, err := service.Login(ctx, input)
 
 		// Assert
 		assert.Error(t, err)
 		assert.Equal(t, auth.ErrWrongCredentials, err)
-		assert.Empty(t, accessToken)
-		assert.Empty(t, sessionID)
+		assert.Nil(t, res)
 	})
 
 	t.Run("WrongPassword", func(t *testing.T) {
@@ -198,9 +278,10 @@ func TestService_Login(t *testing.T) {
 		mockTransactor := mocks.NewTransactor(t)
 		mockUserRepo := mocks.NewUserRepository(t)
 		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
 		mockPublisher := mocks.NewAuthMessagePublisher(t)
 
-		service := auth.NewService(cfg, mockTransactor, mockUserRepo, mockAuthRepo, mockPublisher)
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
 
 		input := auth.LoginInput{
 			Email:    "john@example.com",
@@ -221,414 +302,589 @@ func TestService_Login(t *testing.T) {
 		mockUserRepo.EXPECT().GetByEmail(ctx, input.Email).Return(testUser, nil)
 
 		// Execute
-		accessToken, sessionID, err := service.Login(ctx, input)
+		res, err := service.Login(ctx, input)
 
 		// Assert
 		assert.Error(t, err)
-		assert.Empty(t, accessToken)
-		assert.Empty(t, sessionID)
+		assert.Nil(t, res)
 	})
 }
 
-func TestService_RefreshAccessToken(t *testing.T) {
+func TestService_Login_AccountRestricted(t *testing.T) {
 	ctx := context.Background()
 	cfg := config.Auth{
-		JwtSecret:                 "test-secret",
-		JwtTTL:                    time.Hour,
-		SessionTTL:                24 * time.Hour,
-		ResetPasswordTTL:          time.Hour,
-		ResetPasswordFormEndpoint: "http://localhost:3000/reset-password",
+		JwtSecret:  "test-secret",
+		JwtTTL:     time.Hour,
+		SessionTTL: 24 * time.Hour,
 	}
 
-	t.Run("Success", func(t *testing.T) {
-		// Setup
+	hashedPassword, err := auth.HashPassword("password123")
+	require.NoError(t, err)
+
+	input := auth.LoginInput{
+		Email:    "john@example.com",
+		Password: "password123",
+	}
+
+	t.Run("Suspended", func(t *testing.T) {
 		mockTransactor := mocks.NewTransactor(t)
 		mockUserRepo := mocks.NewUserRepository(t)
 		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
 		mockPublisher := mocks.NewAuthMessagePublisher(t)
 
-		service := auth.NewService(cfg, mockTransactor, mockUserRepo, mockAuthRepo, mockPublisher)
-
-		sessionID := uuid.New().String()
-		userID := uuid.New()
-
-		session, err := auth.NewSession(userID, "test-agent", cfg.SessionTTL)
-		require.NoError(t, err)
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
 
 		testUser := &user.User{
-			ID:    userID,
-			Name:  "John Doe",
-			Email: "john@example.com",
+			ID:       uuid.New(),
+			Name:     "John Doe",
+			Email:    input.Email,
+			Password: string(hashedPassword),
 		}
+		require.NoError(t, testUser.Suspend())
 
-		// Mock expectations
-		mockAuthRepo.EXPECT().GetSession(ctx, sessionID).Return(session, nil)
-		mockUserRepo.EXPECT().GetByID(ctx, userID.String()).Return(testUser, nil)
-
-		// Execute
-		newAccessToken, err := service.RefreshAccessToken(ctx, sessionID)
+		mockUserRepo.EXPECT().GetByEmail(ctx, input.Email).Return(testUser, nil)
 
-		// Assert
-		assert.NoError(t, err)
-		assert.NotEmpty(t, newAccessToken)
+		res, err := service.Login(ctx, input)
+		assert.ErrorIs(t, err, user.ErrSuspended)
+		assert.Nil(t, res)
 	})
 
-	t.Run("SessionExpired", func(t *testing.T) {
-		// Setup
+	t.Run("PasswordResetRequired", func(t *testing.T) {
 		mockTransactor := mocks.NewTransactor(t)
 		mockUserRepo := mocks.NewUserRepository(t)
 		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
 		mockPublisher := mocks.NewAuthMessagePublisher(t)
 
-		service := auth.NewService(cfg, mockTransactor, mockUserRepo, mockAuthRepo, mockPublisher)
-
-		sessionID := uuid.New().String()
-		userID := uuid.New()
-
-		// Create valid session and manually set it as expired
-		session, err := auth.NewSession(userID, "test-agent", cfg.SessionTTL)
-		require.NoError(t, err)
-		session.ExpiresAt = time.Now().Add(-time.Hour) // Set to past
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
 
-		// Mock expectations
-		mockAuthRepo.EXPECT().GetSession(ctx, sessionID).Return(session, nil)
+		testUser := &user.User{
+			ID:                    uuid.New(),
+			Name:                  "John Doe",
+			Email:                 input.Email,
+			Password:              string(hashedPassword),
+			PasswordResetRequired: true,
+		}
 
-		// Execute
-		newAccessToken, err := service.RefreshAccessToken(ctx, sessionID)
+		mockUserRepo.EXPECT().GetByEmail(ctx, input.Email).Return(testUser, nil)
 
-		// Assert
-		assert.Error(t, err)
-		assert.Equal(t, auth.ErrSessionExpired, err)
-		assert.Empty(t, newAccessToken)
+		res, err := service.Login(ctx, input)
+		assert.ErrorIs(t, err, auth.ErrPasswordResetRequired)
+		assert.Nil(t, res)
 	})
 }
 
-func TestService_Logout(t *testing.T) {
+func TestService_Login_Throttle(t *testing.T) {
 	ctx := context.Background()
 	cfg := config.Auth{
-		JwtSecret:                 "test-secret",
-		JwtTTL:                    time.Hour,
-		SessionTTL:                24 * time.Hour,
-		ResetPasswordTTL:          time.Hour,
-		ResetPasswordFormEndpoint: "http://localhost:3000/reset-password",
+		JwtSecret:             "test-secret",
+		JwtTTL:                time.Hour,
+		SessionTTL:            24 * time.Hour,
+		LoginMaxFailures:      3,
+		LoginIPMaxFailures:    10,
+		LoginFailureWindow:    15 * time.Minute,
+		LoginLockoutDuration:  15 * time.Minute,
+		LoginBaseDelay:        time.Second,
+		AccountUnlockTTL:      24 * time.Hour,
+		AccountUnlockEndpoint: "http://localhost:3000/unlock",
+	}
+
+	hashedPassword, err := auth.HashPassword("password123")
+	require.NoError(t, err)
+
+	const ip = "203.0.113.7"
+	ipKey := auth.IPAttemptsKey(ip)
+
+	newUser := func() *user.User {
+		return &user.User{
+			ID:       uuid.New(),
+			Name:     "John Doe",
+			Email:    "john@example.com",
+			Password: string(hashedPassword),
+		}
 	}
 
 	t.Run("Success", func(t *testing.T) {
-		// Setup
 		mockTransactor := mocks.NewTransactor(t)
 		mockUserRepo := mocks.NewUserRepository(t)
 		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
 		mockPublisher := mocks.NewAuthMessagePublisher(t)
 
-		service := auth.NewService(cfg, mockTransactor, mockUserRepo, mockAuthRepo, mockPublisher)
-
-		sessionID := uuid.New().String()
-		userID := uuid.New()
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		testUser := newUser()
+		accountKey := auth.AccountAttemptsKey(testUser.ID.String())
+
+		mockAuthRepo.EXPECT().GetLoginAttempts(ctx, ipKey).Return(&auth.LoginAttempts{Key: ipKey}, nil)
+		mockUserRepo.EXPECT().GetByEmail(ctx, testUser.Email).Return(testUser, nil)
+		mockAuthRepo.EXPECT().
+			GetLoginAttempts(ctx, accountKey).
+			Return(&auth.LoginAttempts{Key: accountKey, Failures: 1, LastFailedAt: time.Now().Add(-time.Minute)}, nil)
+		mockAuthRepo.EXPECT().DeleteLoginAttempts(ctx, accountKey).Return(nil)
+		mockAuthRepo.EXPECT().
+			GetTOTPFactor(ctx, testUser.ID.String()).
+			Return(nil, auth.ErrTOTPFactorNotFound)
+		mockRoleRepo.EXPECT().GetRolesByUser(ctx, testUser.ID.String()).Return([]*auth.Role{}, nil)
+		expectKnownDevice(mockAuthRepo)
+		mockAuthRepo.EXPECT().StoreSession(ctx, mock.AnythingOfType("*auth.Session")).Return(nil)
 
-		session, err := auth.NewSession(userID, "test-agent", cfg.SessionTTL)
+		res, err := service.Login(ctx, auth.LoginInput{Email: testUser.Email, Password: "password123", IPAddress: ip})
 		require.NoError(t, err)
-
-		// Mock expectations
-		mockAuthRepo.EXPECT().GetSession(ctx, sessionID).Return(session, nil)
-		mockAuthRepo.EXPECT().UpdateSession(ctx, mock.AnythingOfType("*auth.Session")).Return(nil)
-
-		// Execute
-		err = service.Logout(ctx, sessionID)
-
-		// Assert
-		assert.NoError(t, err)
+		assert.NotEmpty(t, res.AccessToken)
 	})
 
-	t.Run("SessionAlreadyExpired", func(t *testing.T) {
-		// Setup
+	t.Run("IPBlocked", func(t *testing.T) {
 		mockTransactor := mocks.NewTransactor(t)
 		mockUserRepo := mocks.NewUserRepository(t)
 		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
 		mockPublisher := mocks.NewAuthMessagePublisher(t)
 
-		service := auth.NewService(cfg, mockTransactor, mockUserRepo, mockAuthRepo, mockPublisher)
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
 
-		sessionID := uuid.New().String()
-		userID := uuid.New()
+		blocked := &auth.LoginAttempts{Key: ipKey}
+		blocked.LockedUntil.Set(time.Now().Add(time.Minute), false)
+		mockAuthRepo.EXPECT().GetLoginAttempts(ctx, ipKey).Return(blocked, nil)
 
-		// Create valid session and manually set it as expired
-		session, err := auth.NewSession(userID, "test-agent", cfg.SessionTTL)
-		require.NoError(t, err)
-		session.ExpiresAt = time.Now().Add(-time.Hour) // Set to past
+		res, err := service.Login(ctx, auth.LoginInput{Email: "john@example.com", Password: "password123", IPAddress: ip})
+		assert.ErrorIs(t, err, auth.ErrTooManyLoginAttempts)
+		assert.Nil(t, res)
+	})
 
-		// Mock expectations
-		mockAuthRepo.EXPECT().GetSession(ctx, sessionID).Return(session, nil)
+	t.Run("UnknownEmailLockedWithEnumerationProtection", func(t *testing.T) {
+		protectedCfg := cfg
+		protectedCfg.EnumerationProtection = true
 
-		// Execute
-		err = service.Logout(ctx, sessionID)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
 
-		// Assert
-		assert.NoError(t, err) // Should not error even if session is expired
+		service := auth.NewService(protectedCfg, testKeys, nil, mocks.NewTransactor(t), mockUserRepo, mockAuthRepo, mocks.NewRoleRepository(t), nil, mocks.NewAuthMessagePublisher(t), nil, nil, nil)
+
+		emailKey := auth.UnknownEmailAttemptsKey("ghost@example.com")
+		locked := &auth.LoginAttempts{Key: emailKey, Failures: 3}
+		locked.LockedUntil.Set(time.Now().Add(time.Minute), false)
+
+		mockUserRepo.EXPECT().GetByEmail(ctx, "ghost@example.com").Return(nil, user.ErrNotFound)
+		mockAuthRepo.EXPECT().GetLoginAttempts(ctx, emailKey).Return(locked, nil)
+
+		// Answers like an existing locked account would
+		res, err := service.Login(ctx, auth.LoginInput{Email: "ghost@example.com", Password: "password123"})
+		assert.ErrorIs(t, err, auth.ErrAccountLocked)
+		assert.Nil(t, res)
 	})
-}
 
-func TestService_ForgotPassword(t *testing.T) {
-	ctx := context.Background()
-	cfg := config.Auth{
-		JwtSecret:                 "test-secret",
-		JwtTTL:                    time.Hour,
-		SessionTTL:                24 * time.Hour,
-		ResetPasswordTTL:          time.Hour,
-		ResetPasswordFormEndpoint: "http://localhost:3000/reset-password",
-	}
+	t.Run("UnknownEmailFailureLocksWithEnumerationProtection", func(t *testing.T) {
+		protectedCfg := cfg
+		protectedCfg.EnumerationProtection = true
 
-	t.Run("Success", func(t *testing.T) {
-		// Setup
 		mockTransactor := mocks.NewTransactor(t)
 		mockUserRepo := mocks.NewUserRepository(t)
 		mockAuthRepo := mocks.NewAuthRepository(t)
-		mockPublisher := mocks.NewAuthMessagePublisher(t)
 
-		service := auth.NewService(cfg, mockTransactor, mockUserRepo, mockAuthRepo, mockPublisher)
+		service := auth.NewService(protectedCfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mocks.NewRoleRepository(t), nil, mocks.NewAuthMessagePublisher(t), nil, nil, nil)
+
+		emailKey := auth.UnknownEmailAttemptsKey("Ghost@example.com")
+		assert.Equal(t, auth.UnknownEmailAttemptsKey("ghost@example.com"), emailKey)
+
+		mockUserRepo.EXPECT().GetByEmail(ctx, "Ghost@example.com").Return(nil, user.ErrNotFound)
+		mockAuthRepo.EXPECT().
+			GetLoginAttempts(ctx, emailKey).
+			Return(&auth.LoginAttempts{Key: emailKey, Failures: 2, LastFailedAt: time.Now().Add(-time.Minute)}, nil)
+		mockTransactor.EXPECT().
+			Transact(ctx, mock.AnythingOfType("func(context.Context) error")).
+			RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error { return fn(ctx) })
+		mockAuthRepo.EXPECT().
+			StoreLoginAttempts(ctx, mock.MatchedBy(func(a *auth.LoginAttempts) bool {
+				return a.Key == emailKey && a.LockedUntil.NotNull()
+			})).
+			Return(nil)
+
+		res, err := service.Login(ctx, auth.LoginInput{Email: "Ghost@example.com", Password: "password123"})
+		assert.ErrorIs(t, err, auth.ErrAccountLocked)
+		assert.Nil(t, res)
+	})
 
-		input := auth.ForgotPasswordInput{
-			Email: "john@example.com",
-		}
+	t.Run("UserNotFoundRecordsIPFailure", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
 
-		testUser := &user.User{
-			ID:    uuid.New(),
-			Name:  "John Doe",
-			Email: input.Email,
-		}
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
 
-		// Mock expectations
+		mockAuthRepo.EXPECT().GetLoginAttempts(ctx, ipKey).Return(&auth.LoginAttempts{Key: ipKey}, nil).Once()
+		mockUserRepo.EXPECT().GetByEmail(ctx, "ghost@example.com").Return(nil, user.ErrNotFound)
 		mockTransactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).Return(nil).Run(func(ctx context.Context, fn func(context.Context) error) {
-			// Inside the transaction, we need to set up the expectations
-			mockUserRepo.EXPECT().GetByEmail(ctx, input.Email).Return(testUser, nil)
-			mockAuthRepo.EXPECT().StoreResetPasswordToken(ctx, mock.AnythingOfType("*auth.ResetPasswordToken")).Return(nil)
-			mockPublisher.EXPECT().SendResetPasswordEmail(ctx, mock.AnythingOfType("auth.ResetPasswordEmailMessage")).Return(nil)
+			mockAuthRepo.EXPECT().GetLoginAttempts(ctx, ipKey).Return(&auth.LoginAttempts{Key: ipKey}, nil).Once()
+			mockAuthRepo.EXPECT().
+				StoreLoginAttempts(ctx, mock.MatchedBy(func(a *auth.LoginAttempts) bool {
+					return a.Key == ipKey && a.Failures == 1
+				})).
+				Return(nil)
 
 			err := fn(ctx)
 			assert.NoError(t, err)
 		})
 
-		// Execute
-		err := service.ForgotPassword(ctx, input)
-
-		// Assert
-		assert.NoError(t, err)
+		res, err := service.Login(ctx, auth.LoginInput{Email: "ghost@example.com", Password: "password123", IPAddress: ip})
+		assert.ErrorIs(t, err, auth.ErrWrongCredentials)
+		assert.Nil(t, res)
 	})
 
-	t.Run("UserNotFound", func(t *testing.T) {
-		// Setup
+	t.Run("AccountLocked", func(t *testing.T) {
 		mockTransactor := mocks.NewTransactor(t)
 		mockUserRepo := mocks.NewUserRepository(t)
 		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
 		mockPublisher := mocks.NewAuthMessagePublisher(t)
 
-		service := auth.NewService(cfg, mockTransactor, mockUserRepo, mockAuthRepo, mockPublisher)
-
-		input := auth.ForgotPasswordInput{
-			Email: "nonexistent@example.com",
-		}
-
-		// Mock expectations
-		mockTransactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).Run(func(ctx context.Context, fn func(context.Context) error) {
-			mockUserRepo.EXPECT().GetByEmail(ctx, input.Email).Return(nil, user.ErrNotFound)
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
 
-			err := fn(ctx)
-			assert.Error(t, err)
-			assert.Equal(t, user.ErrEmailNotVerified, err)
-		}).Return(user.ErrEmailNotVerified)
+		testUser := newUser()
+		accountKey := auth.AccountAttemptsKey(testUser.ID.String())
+		locked := &auth.LoginAttempts{Key: accountKey}
+		locked.LockedUntil.Set(time.Now().Add(time.Minute), false)
 
-		// Execute
-		err := service.ForgotPassword(ctx, input)
+		mockAuthRepo.EXPECT().GetLoginAttempts(ctx, ipKey).Return(&auth.LoginAttempts{Key: ipKey}, nil)
+		mockUserRepo.EXPECT().GetByEmail(ctx, testUser.Email).Return(testUser, nil)
+		mockAuthRepo.EXPECT().GetLoginAttempts(ctx, accountKey).Return(locked, nil)
 
-		// Assert
-		assert.Error(t, err)
-		assert.Equal(t, user.ErrEmailNotVerified, err)
+		// Even the right password is rejected while locked
+		res, err := service.Login(ctx, auth.LoginInput{Email: testUser.Email, Password: "password123", IPAddress: ip})
+		assert.ErrorIs(t, err, auth.ErrAccountLocked)
+		assert.Nil(t, res)
 	})
-}
-
-func TestService_ResetPassword(t *testing.T) {
-	ctx := context.Background()
-	cfg := config.Auth{
-		JwtSecret:                 "test-secret",
-		JwtTTL:                    time.Hour,
-		SessionTTL:                24 * time.Hour,
-		ResetPasswordTTL:          time.Hour,
-		ResetPasswordFormEndpoint: "http://localhost:3000/reset-password",
-	}
 
-	t.Run("Success", func(t *testing.T) {
-		// Setup
+	t.Run("ProgressiveDelay", func(t *testing.T) {
 		mockTransactor := mocks.NewTransactor(t)
 		mockUserRepo := mocks.NewUserRepository(t)
 		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
 		mockPublisher := mocks.NewAuthMessagePublisher(t)
 
-		service := auth.NewService(cfg, mockTransactor, mockUserRepo, mockAuthRepo, mockPublisher)
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
 
-		userID := uuid.New()
-		token, err := auth.NewResetPasswordToken(userID, cfg.ResetPasswordTTL)
-		require.NoError(t, err)
+		testUser := newUser()
+		accountKey := auth.AccountAttemptsKey(testUser.ID.String())
 
-		input := auth.ResetPasswordInput{
-			Token:             token.Value,
-			NewPassword:       "newpassword123",
-			RepeatNewPassword: "newpassword123",
-		}
+		mockAuthRepo.EXPECT().GetLoginAttempts(ctx, ipKey).Return(&auth.LoginAttempts{Key: ipKey}, nil)
+		mockUserRepo.EXPECT().GetByEmail(ctx, testUser.Email).Return(testUser, nil)
+		mockAuthRepo.EXPECT().
+			GetLoginAttempts(ctx, accountKey).
+			Return(&auth.LoginAttempts{Key: accountKey, Failures: 2, LastFailedAt: time.Now()}, nil)
 
-		testUser := &user.User{
-			ID:    userID,
-			Name:  "John Doe",
-			Email: "john@example.com",
-		}
+		res, err := service.Login(ctx, auth.LoginInput{Email: testUser.Email, Password: "password123", IPAddress: ip})
+		assert.ErrorIs(t, err, auth.ErrTooManyLoginAttempts)
+		assert.Nil(t, res)
+	})
 
-		// Mock expectations
-		mockTransactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).Return(nil).Run(func(ctx context.Context, fn func(context.Context) error) {
-			mockAuthRepo.EXPECT().GetResetPasswordToken(ctx, input.Token).Return(token, nil)
-			mockUserRepo.EXPECT().GetByID(ctx, userID.String()).Return(testUser, nil)
-			mockAuthRepo.EXPECT().UpdateResetPasswordToken(ctx, mock.AnythingOfType("*auth.ResetPasswordToken")).Return(nil)
-			mockUserRepo.EXPECT().Update(ctx, mock.AnythingOfType("*user.User")).Return(nil)
+	t.Run("LocksAfterMaxFailures", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
 
-			err := fn(ctx)
-			assert.NoError(t, err)
-		})
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		testUser := newUser()
+		accountKey := auth.AccountAttemptsKey(testUser.ID.String())
+		lastFailedAt := time.Now().Add(-time.Minute)
+
+		mockAuthRepo.EXPECT().GetLoginAttempts(ctx, ipKey).Return(&auth.LoginAttempts{Key: ipKey}, nil)
+		mockUserRepo.EXPECT().GetByEmail(ctx, testUser.Email).Return(testUser, nil)
+		mockAuthRepo.EXPECT().
+			GetLoginAttempts(ctx, accountKey).
+			Return(&auth.LoginAttempts{Key: accountKey, Failures: 2, LastFailedAt: lastFailedAt}, nil)
+		mockAuthRepo.EXPECT().StoreLoginAttempts(ctx, mock.MatchedBy(func(a *auth.LoginAttempts) bool {
+			return a.Key == ipKey
+		})).Return(nil)
+		mockAuthRepo.EXPECT().StoreLoginAttempts(ctx, mock.MatchedBy(func(a *auth.LoginAttempts) bool {
+			return a.Key == accountKey && a.Locked(time.Now())
+		})).Return(nil)
+		mockAuthRepo.EXPECT().StoreAccountUnlockToken(ctx, mock.AnythingOfType("*auth.AccountUnlockToken")).Return(nil)
+		mockPublisher.EXPECT().
+			SendAccountLockedEmail(ctx, mock.MatchedBy(func(msg auth.AccountLockedEmailMessage) bool {
+				return msg.To == testUser.Email && msg.IPAddress == ip &&
+					strings.HasPrefix(msg.UnlockURL, cfg.AccountUnlockEndpoint+"?token=")
+			})).
+			Return(nil)
+		mockTransactor.EXPECT().
+			Transact(ctx, mock.AnythingOfType("func(context.Context) error")).
+			RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+				return fn(ctx)
+			}).
+			Twice()
+
+		res, err := service.Login(ctx, auth.LoginInput{Email: testUser.Email, Password: "wrongpassword", IPAddress: ip})
+		assert.ErrorIs(t, err, auth.ErrAccountLocked)
+		assert.Nil(t, res)
+	})
 
-		// Execute
-		err = service.ResetPassword(ctx, input)
+	t.Run("MFARequiredKeepsFailures", func(t *testing.T) {
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
 
-		// Assert
-		assert.NoError(t, err)
+		service := auth.NewService(cfg, testKeys, nil, mocks.NewTransactor(t), mockUserRepo, mockAuthRepo, mocks.NewRoleRepository(t), nil, mocks.NewAuthMessagePublisher(t), nil, nil, nil)
+
+		testUser := newUser()
+		accountKey := auth.AccountAttemptsKey(testUser.ID.String())
+		factor, _, err := auth.NewTOTPFactor(testUser.ID, "test", testUser.Email)
+		require.NoError(t, err)
+		factor.Confirm()
+
+		mockAuthRepo.EXPECT().GetLoginAttempts(ctx, ipKey).Return(&auth.LoginAttempts{Key: ipKey}, nil)
+		mockUserRepo.EXPECT().GetByEmail(ctx, testUser.Email).Return(testUser, nil)
+		mockAuthRepo.EXPECT().
+			GetLoginAttempts(ctx, accountKey).
+			Return(&auth.LoginAttempts{Key: accountKey, Failures: 2, LastFailedAt: time.Now().Add(-time.Minute)}, nil)
+		mockAuthRepo.EXPECT().GetTOTPFactor(ctx, testUser.ID.String()).Return(factor, nil)
+		mockAuthRepo.EXPECT().StoreMFAChallenge(ctx, mock.AnythingOfType("*auth.MFAChallenge")).Return(nil)
+
+		// The failures are kept until the second factor is verified as well, DeleteLoginAttempts isn't expected
+		res, err := service.Login(ctx, auth.LoginInput{Email: testUser.Email, Password: "password123", IPAddress: ip})
+		require.NoError(t, err)
+		assert.True(t, res.MFARequired())
 	})
 
-	t.Run("TokenExpired", func(t *testing.T) {
-		// Setup
+	t.Run("WrongMFACodeRecordsFailure", func(t *testing.T) {
 		mockTransactor := mocks.NewTransactor(t)
 		mockUserRepo := mocks.NewUserRepository(t)
 		mockAuthRepo := mocks.NewAuthRepository(t)
-		mockPublisher := mocks.NewAuthMessagePublisher(t)
 
-		service := auth.NewService(cfg, mockTransactor, mockUserRepo, mockAuthRepo, mockPublisher)
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mocks.NewRoleRepository(t), nil, mocks.NewAuthMessagePublisher(t), nil, nil, nil)
 
-		userID := uuid.New()
-		// Create valid token and manually set it as expired
-		token, err := auth.NewResetPasswordToken(userID, cfg.ResetPasswordTTL)
+		testUser := newUser()
+		accountKey := auth.AccountAttemptsKey(testUser.ID.String())
+		factor, _, err := auth.NewTOTPFactor(testUser.ID, "test", testUser.Email)
+		require.NoError(t, err)
+		factor.Confirm()
+		challenge, err := auth.NewMFAChallenge(testUser.ID, "test-agent", auth.AMRPassword, []string{auth.MFAMethodTOTP}, time.Minute)
 		require.NoError(t, err)
-		token.ExpiresAt = time.Now().Add(-time.Hour) // Set to past
 
-		input := auth.ResetPasswordInput{
-			Token:             token.Value,
-			NewPassword:       "newpassword123",
-			RepeatNewPassword: "newpassword123",
-		}
+		mockTransactor.EXPECT().
+			Transact(ctx, mock.AnythingOfType("func(context.Context) error")).
+			RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error { return fn(ctx) })
+		mockAuthRepo.EXPECT().GetLoginAttempts(ctx, ipKey).Return(&auth.LoginAttempts{Key: ipKey}, nil)
+		mockAuthRepo.EXPECT().GetMFAChallenge(ctx, challenge.Value).Return(challenge, nil)
+		mockUserRepo.EXPECT().GetByID(ctx, testUser.ID.String()).Return(testUser, nil)
+		mockAuthRepo.EXPECT().
+			GetLoginAttempts(ctx, accountKey).
+			Return(&auth.LoginAttempts{Key: accountKey, Failures: 1, LastFailedAt: time.Now().Add(-time.Minute)}, nil)
+		mockAuthRepo.EXPECT().GetTOTPFactor(ctx, testUser.ID.String()).Return(factor, nil)
+		mockAuthRepo.EXPECT().UpdateMFAChallenge(ctx, challenge).Return(nil)
+		mockAuthRepo.EXPECT().StoreLoginAttempts(ctx, mock.MatchedBy(func(a *auth.LoginAttempts) bool {
+			return a.Key == ipKey && a.Failures == 1
+		})).Return(nil)
+		mockAuthRepo.EXPECT().StoreLoginAttempts(ctx, mock.MatchedBy(func(a *auth.LoginAttempts) bool {
+			return a.Key == accountKey && a.Failures == 2
+		})).Return(nil)
+
+		res, err := service.VerifyMFA(ctx, auth.VerifyMFAInput{MFAToken: challenge.Value, Code: "000000", IPAddress: ip})
+		assert.ErrorIs(t, err, auth.ErrMFACodeInvalid)
+		assert.Nil(t, res)
+	})
 
-		// Mock expectations
-		mockTransactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).Run(func(ctx context.Context, fn func(context.Context) error) {
-			mockAuthRepo.EXPECT().GetResetPasswordToken(ctx, input.Token).Return(token, nil)
+	t.Run("WrongPasswordConfirmationRecordsFailure", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
 
-			err := fn(ctx)
-			assert.Error(t, err)
-			assert.Equal(t, auth.ErrResetPasswordTokenInvalid, err)
-		}).Return(auth.ErrResetPasswordTokenInvalid)
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mocks.NewRoleRepository(t), nil, mocks.NewAuthMessagePublisher(t), nil, nil, nil)
+
+		testUser := newUser()
+		accountKey := auth.AccountAttemptsKey(testUser.ID.String())
+
+		mockTransactor.EXPECT().
+			Transact(ctx, mock.AnythingOfType("func(context.Context) error")).
+			RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error { return fn(ctx) }).
+			Twice()
+		mockUserRepo.EXPECT().GetByID(ctx, testUser.ID.String()).Return(testUser, nil)
+		mockAuthRepo.EXPECT().GetLoginAttempts(ctx, accountKey).Return(&auth.LoginAttempts{Key: accountKey}, nil)
+		mockAuthRepo.EXPECT().GetLoginAttempts(ctx, ipKey).Return(&auth.LoginAttempts{Key: ipKey}, nil)
+		mockAuthRepo.EXPECT().StoreLoginAttempts(ctx, mock.MatchedBy(func(a *auth.LoginAttempts) bool {
+			return a.Key == ipKey && a.Failures == 1
+		})).Return(nil)
+		mockAuthRepo.EXPECT().StoreLoginAttempts(ctx, mock.MatchedBy(func(a *auth.LoginAttempts) bool {
+			return a.Key == accountKey && a.Failures == 1
+		})).Return(nil)
+
+		err := service.DisableTOTP(ctx, testUser.ID.String(), auth.DisableTOTPInput{Password: "wrongpassword", IPAddress: ip})
+		assert.ErrorIs(t, err, auth.ErrWrongCredentials)
+	})
 
-		// Execute
-		err = service.ResetPassword(ctx, input)
+	t.Run("PasswordConfirmationThrottled", func(t *testing.T) {
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
 
-		// Assert
-		assert.Error(t, err)
-		assert.Equal(t, auth.ErrResetPasswordTokenInvalid, err)
+		service := auth.NewService(cfg, testKeys, nil, mocks.NewTransactor(t), mockUserRepo, mockAuthRepo, mocks.NewRoleRepository(t), nil, mocks.NewAuthMessagePublisher(t), nil, nil, nil)
+
+		testUser := newUser()
+		accountKey := auth.AccountAttemptsKey(testUser.ID.String())
+		locked := &auth.LoginAttempts{Key: accountKey, Failures: 3}
+		locked.LockedUntil.Set(time.Now().Add(time.Minute), false)
+
+		mockUserRepo.EXPECT().GetByID(ctx, testUser.ID.String()).Return(testUser, nil)
+		mockAuthRepo.EXPECT().GetLoginAttempts(ctx, accountKey).Return(locked, nil)
+
+		// Rejected before the password is checked, even the right one
+		err := service.ChangePassword(ctx, testUser.ID.String(), auth.ChangePasswordInput{
+			Password:          "password123",
+			NewPassword:       "newpassword123",
+			RepeatNewPassword: "newpassword123",
+		})
+		assert.ErrorIs(t, err, auth.ErrAccountLocked)
 	})
 }
 
-func TestService_ChangePassword(t *testing.T) {
+func TestService_UnlockAccount(t *testing.T) {
 	ctx := context.Background()
 	cfg := config.Auth{
-		JwtSecret:                 "test-secret",
-		JwtTTL:                    time.Hour,
-		SessionTTL:                24 * time.Hour,
-		ResetPasswordTTL:          time.Hour,
-		ResetPasswordFormEndpoint: "http://localhost:3000/reset-password",
+		LoginMaxFailures: 3,
+		AccountUnlockTTL: time.Hour,
 	}
 
 	t.Run("Success", func(t *testing.T) {
-		// Setup
 		mockTransactor := mocks.NewTransactor(t)
 		mockUserRepo := mocks.NewUserRepository(t)
 		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
 		mockPublisher := mocks.NewAuthMessagePublisher(t)
 
-		service := auth.NewService(cfg, mockTransactor, mockUserRepo, mockAuthRepo, mockPublisher)
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
 
-		userID := uuid.New().String()
-		oldPassword := "oldpassword123"
-		newPassword := "newpassword123"
+		userID := uuid.New()
+		token, err := auth.NewAccountUnlockToken(userID, cfg.AccountUnlockTTL)
+		require.NoError(t, err)
 
-		hashedOldPassword, err := auth.HashPassword(oldPassword)
+		mockTransactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).Return(nil).Run(func(ctx context.Context, fn func(context.Context) error) {
+			mockAuthRepo.EXPECT().GetAccountUnlockToken(ctx, token.Value).Return(token, nil)
+			mockAuthRepo.EXPECT().
+				UpdateAccountUnlockToken(ctx, mock.AnythingOfType("*auth.AccountUnlockToken")).
+				Return(nil)
+			mockAuthRepo.EXPECT().DeleteLoginAttempts(ctx, auth.AccountAttemptsKey(userID.String())).Return(nil)
+
+			err := fn(ctx)
+			assert.NoError(t, err)
+		})
+
+		err = service.UnlockAccount(ctx, auth.UnlockAccountInput{Token: token.Value})
+		require.NoError(t, err)
+		assert.True(t, token.Used())
+	})
+
+	t.Run("TokenExpired", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		token, err := auth.NewAccountUnlockToken(uuid.New(), -time.Hour)
 		require.NoError(t, err)
 
+		mockTransactor.EXPECT().
+			Transact(ctx, mock.AnythingOfType("func(context.Context) error")).
+			RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+				mockAuthRepo.EXPECT().GetAccountUnlockToken(ctx, token.Value).Return(token, nil)
+				return fn(ctx)
+			})
+
+		err = service.UnlockAccount(ctx, auth.UnlockAccountInput{Token: token.Value})
+		assert.ErrorIs(t, err, auth.ErrAccountUnlockTokenInvalid)
+	})
+}
+
+func TestService_Login_RequireVerifiedEmail(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Auth{
+		JwtSecret:            "test-secret",
+		JwtTTL:               time.Hour,
+		SessionTTL:           24 * time.Hour,
+		RequireVerifiedEmail: true,
+	}
+
+	input := auth.LoginInput{
+		Email:     "john@example.com",
+		Password:  "password123",
+		UserAgent: "test-agent",
+	}
+
+	hashedPassword, err := auth.HashPassword(input.Password)
+	require.NoError(t, err)
+
+	t.Run("UnverifiedEmail", func(t *testing.T) {
+		// Setup
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
 		testUser := &user.User{
-			ID:       uuid.MustParse(userID),
+			ID:       uuid.New(),
 			Name:     "John Doe",
-			Email:    "john@example.com",
-			Password: string(hashedOldPassword),
-		}
-
-		input := auth.ChangePasswordInput{
-			Password:          oldPassword,
-			NewPassword:       newPassword,
-			RepeatNewPassword: newPassword,
+			Email:    input.Email,
+			Password: string(hashedPassword),
 		}
 
 		// Mock expectations
-		mockUserRepo.EXPECT().GetByID(ctx, userID).Return(testUser, nil)
-		mockUserRepo.EXPECT().Update(ctx, mock.AnythingOfType("*user.User")).Return(nil)
+		mockUserRepo.EXPECT().GetByEmail(ctx, input.Email).Return(testUser, nil)
 
 		// Execute
-		err = service.ChangePassword(ctx, userID, input)
+		res, err := service.Login(ctx, input)
 
 		// Assert
-		assert.NoError(t, err)
+		assert.Equal(t, user.ErrEmailNotVerified, err)
+		assert.Nil(t, res)
 	})
 
-	t.Run("WrongCurrentPassword", func(t *testing.T) {
+	t.Run("VerifiedEmail", func(t *testing.T) {
 		// Setup
 		mockTransactor := mocks.NewTransactor(t)
 		mockUserRepo := mocks.NewUserRepository(t)
 		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
 		mockPublisher := mocks.NewAuthMessagePublisher(t)
 
-		service := auth.NewService(cfg, mockTransactor, mockUserRepo, mockAuthRepo, mockPublisher)
-
-		userID := uuid.New().String()
-		oldPassword := "oldpassword123"
-		wrongPassword := "wrongpassword"
-
-		hashedOldPassword, err := auth.HashPassword(oldPassword)
-		require.NoError(t, err)
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
 
 		testUser := &user.User{
-			ID:       uuid.MustParse(userID),
+			ID:       uuid.New(),
 			Name:     "John Doe",
-			Email:    "john@example.com",
-			Password: string(hashedOldPassword),
-		}
-
-		input := auth.ChangePasswordInput{
-			Password:          wrongPassword,
-			NewPassword:       "newpassword123",
-			RepeatNewPassword: "newpassword123",
+			Email:    input.Email,
+			Password: string(hashedPassword),
 		}
+		testUser.MarkVerified()
 
 		// Mock expectations
-		mockUserRepo.EXPECT().GetByID(ctx, userID).Return(testUser, nil)
+		mockUserRepo.EXPECT().GetByEmail(ctx, input.Email).Return(testUser, nil)
+		mockAuthRepo.EXPECT().
+			GetTOTPFactor(ctx, testUser.ID.String()).
+			Return(nil, auth.ErrTOTPFactorNotFound)
+		mockRoleRepo.EXPECT().GetRolesByUser(ctx, testUser.ID.String()).Return([]*auth.Role{}, nil)
+		expectKnownDevice(mockAuthRepo)
+		mockAuthRepo.EXPECT().StoreSession(ctx, mock.AnythingOfType("*auth.Session")).Return(nil)
 
 		// Execute
-		err = service.ChangePassword(ctx, userID, input)
+		res, err := service.Login(ctx, input)
 
 		// Assert
-		assert.Error(t, err)
-		assert.Equal(t, auth.ErrWrongCredentials, err)
+		require.NoError(t, err)
+		assert.False(t, res.MFARequired())
+		assert.NotEmpty(t, res.AccessToken)
+		assert.NotEmpty(t, res.SessionID)
 	})
 }
 
-func TestService_GetResetPasswordToken(t *testing.T) {
+func TestService_RefreshAccessToken(t *testing.T) {
 	ctx := context.Background()
 	cfg := config.Auth{
 		JwtSecret:                 "test-secret",
@@ -643,47 +899,3142 @@ func TestService_GetResetPasswordToken(t *testing.T) {
 		mockTransactor := mocks.NewTransactor(t)
 		mockUserRepo := mocks.NewUserRepository(t)
 		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
 		mockPublisher := mocks.NewAuthMessagePublisher(t)
 
-		service := auth.NewService(cfg, mockTransactor, mockUserRepo, mockAuthRepo, mockPublisher)
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
 
-		tokenValue := "test-token-value"
 		userID := uuid.New()
-		expectedToken, err := auth.NewResetPasswordToken(userID, cfg.ResetPasswordTTL)
+
+		session, err := auth.NewSession(userID, "test-agent", cfg.SessionTTL, auth.AMRPassword)
 		require.NoError(t, err)
-		expectedToken.Value = tokenValue
+		sessionID := session.ID.String()
+
+		testUser := &user.User{
+			ID:    userID,
+			Name:  "John Doe",
+			Email: "john@example.com",
+		}
 
 		// Mock expectations
-		mockAuthRepo.EXPECT().GetResetPasswordToken(ctx, tokenValue).Return(expectedToken, nil)
+		mockTransactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).Return(nil).Run(func(ctx context.Context, fn func(context.Context) error) {
+			mockAuthRepo.EXPECT().GetSession(ctx, sessionID).Return(session, nil)
+			mockUserRepo.EXPECT().GetByID(ctx, userID.String()).Return(testUser, nil)
+			mockRoleRepo.EXPECT().GetRolesByUser(ctx, userID.String()).Return([]*auth.Role{}, nil)
+			mockAuthRepo.EXPECT().UpdateSession(ctx, session).Return(nil)
+			mockAuthRepo.EXPECT().StoreSession(ctx, mock.MatchedBy(func(next *auth.Session) bool {
+				return next.ID != session.ID && next.FamilyID == session.FamilyID
+			})).Return(nil)
+
+			err := fn(ctx)
+			assert.NoError(t, err)
+		})
 
 		// Execute
-		token, err := service.GetResetPasswordToken(ctx, tokenValue)
+		res, err := service.RefreshAccessToken(ctx, sessionID, "test-agent")
 
 		// Assert
-		assert.NoError(t, err)
-		assert.Equal(t, expectedToken, token)
+		require.NoError(t, err)
+		assert.NotEmpty(t, res.AccessToken)
+		assert.NotEqual(t, sessionID, res.SessionID)
+		assert.True(t, session.IsRotated())
 	})
 
-	t.Run("TokenNotFound", func(t *testing.T) {
+	t.Run("SessionExpired", func(t *testing.T) {
 		// Setup
 		mockTransactor := mocks.NewTransactor(t)
 		mockUserRepo := mocks.NewUserRepository(t)
 		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
 		mockPublisher := mocks.NewAuthMessagePublisher(t)
 
-		service := auth.NewService(cfg, mockTransactor, mockUserRepo, mockAuthRepo, mockPublisher)
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
 
-		tokenValue := "nonexistent-token"
+		sessionID := uuid.New().String()
+		userID := uuid.New()
+
+		// Create valid session and manually set it as expired
+		session, err := auth.NewSession(userID, "test-agent", cfg.SessionTTL)
+		require.NoError(t, err)
+		session.ExpiresAt = time.Now().Add(-time.Hour) // Set to past
 
 		// Mock expectations
-		mockAuthRepo.EXPECT().GetResetPasswordToken(ctx, tokenValue).Return(nil, auth.ErrResetPasswordTokenNotFound)
+		mockTransactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).Run(func(ctx context.Context, fn func(context.Context) error) {
+			mockAuthRepo.EXPECT().GetSession(ctx, sessionID).Return(session, nil)
+
+			err := fn(ctx)
+			assert.Equal(t, auth.ErrSessionExpired, err)
+		}).Return(auth.ErrSessionExpired)
 
 		// Execute
-		token, err := service.GetResetPasswordToken(ctx, tokenValue)
+		res, err := service.RefreshAccessToken(ctx, sessionID, "test-agent")
 
 		// Assert
 		assert.Error(t, err)
-		assert.Equal(t, auth.ErrResetPasswordTokenNotFound, err)
-		assert.Nil(t, token)
+		assert.Equal(t, auth.ErrSessionExpired, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("RotatedSessionReused", func(t *testing.T) {
+		// Setup
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		session, err := auth.NewSession(uuid.New(), "test-agent", cfg.SessionTTL)
+		require.NoError(t, err)
+		_, err = session.Rotate(cfg.SessionTTL)
+		require.NoError(t, err)
+		sessionID := session.ID.String()
+
+		// Mock expectations
+		mockTransactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).Return(nil).Run(func(ctx context.Context, fn func(context.Context) error) {
+			mockAuthRepo.EXPECT().GetSession(ctx, sessionID).Return(session, nil)
+			mockAuthRepo.EXPECT().RevokeSessionFamily(ctx, session.FamilyID.String()).Return(nil)
+
+			err := fn(ctx)
+			assert.NoError(t, err)
+		})
+
+		// Execute
+		res, err := service.RefreshAccessToken(ctx, sessionID, "test-agent")
+
+		// Assert
+		assert.Equal(t, auth.ErrSessionReused, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("UserAgentChangedRejected", func(t *testing.T) {
+		rejectCfg := cfg
+		rejectCfg.RejectUserAgentChange = true
+
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+
+		service := auth.NewService(rejectCfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mocks.NewRoleRepository(t), nil, mocks.NewAuthMessagePublisher(t), nil, nil, nil)
+
+		session, err := auth.NewSession(uuid.New(), "Mozilla/5.0 (X11; Linux x86_64) Firefox/126.0", cfg.SessionTTL)
+		require.NoError(t, err)
+		sessionID := session.ID.String()
+
+		mockTransactor.EXPECT().
+			Transact(ctx, mock.AnythingOfType("func(context.Context) error")).
+			RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error { return fn(ctx) })
+		mockAuthRepo.EXPECT().GetSession(ctx, sessionID).Return(session, nil)
+		mockUserRepo.EXPECT().GetByID(ctx, session.UserID.String()).Return(&user.User{ID: session.UserID}, nil)
+		mockAuthRepo.EXPECT().RevokeSessionFamily(ctx, session.FamilyID.String()).Return(nil)
+
+		res, err := service.RefreshAccessToken(ctx, sessionID, "python-requests/2.31.0")
+
+		assert.ErrorIs(t, err, auth.ErrSessionUserAgentChanged)
+		assert.Nil(t, res)
+	})
+
+	t.Run("UserAgentUpdatedAllowed", func(t *testing.T) {
+		rejectCfg := cfg
+		rejectCfg.RejectUserAgentChange = true
+
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+
+		service := auth.NewService(rejectCfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mocks.NewAuthMessagePublisher(t), nil, nil, nil)
+
+		session, err := auth.NewSession(uuid.New(), "Mozilla/5.0 (X11; Linux x86_64) Firefox/126.0", cfg.SessionTTL)
+		require.NoError(t, err)
+		sessionID := session.ID.String()
+
+		mockTransactor.EXPECT().
+			Transact(ctx, mock.AnythingOfType("func(context.Context) error")).
+			RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error { return fn(ctx) })
+		mockAuthRepo.EXPECT().GetSession(ctx, sessionID).Return(session, nil)
+		mockUserRepo.EXPECT().GetByID(ctx, session.UserID.String()).Return(&user.User{ID: session.UserID}, nil)
+		mockRoleRepo.EXPECT().GetRolesByUser(ctx, session.UserID.String()).Return([]*auth.Role{}, nil)
+		mockAuthRepo.EXPECT().UpdateSession(ctx, session).Return(nil)
+		mockAuthRepo.EXPECT().StoreSession(ctx, mock.AnythingOfType("*auth.Session")).Return(nil)
+
+		// A browser update only changes the version numbers
+		res, err := service.RefreshAccessToken(ctx, sessionID, "Mozilla/5.0 (X11; Linux x86_64) Firefox/127.0")
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, res.AccessToken)
+	})
+}
+
+func TestService_KnownDevices(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Auth{
+		JwtSecret:               "test-secret",
+		JwtTTL:                  time.Hour,
+		SessionTTL:              24 * time.Hour,
+		NewDeviceRevokeTTL:      24 * time.Hour,
+		NewDeviceRevokeEndpoint: "http://localhost:3000/sessions/revoke",
+	}
+
+	hashedPassword, err := auth.HashPassword("password123")
+	require.NoError(t, err)
+
+	testUser := &user.User{
+		ID:       uuid.New(),
+		Name:     "John Doe",
+		Email:    "john@example.com",
+		Password: string(hashedPassword),
+	}
+	input := auth.LoginInput{
+		Email:     testUser.Email,
+		Password:  "password123",
+		UserAgent: "Mozilla/5.0 (X11; Linux x86_64) Firefox/126.0",
+		IPAddress: "203.0.113.10",
+	}
+
+	expectLogin := func(mockUserRepo *mocks.UserRepository, mockAuthRepo *mocks.AuthRepository, mockRoleRepo *mocks.RoleRepository) {
+		mockUserRepo.EXPECT().GetByEmail(ctx, input.Email).Return(testUser, nil)
+		mockAuthRepo.EXPECT().GetTOTPFactor(ctx, testUser.ID.String()).Return(nil, auth.ErrTOTPFactorNotFound)
+		mockRoleRepo.EXPECT().GetRolesByUser(ctx, testUser.ID.String()).Return([]*auth.Role{}, nil)
+		mockAuthRepo.EXPECT().StoreSession(ctx, mock.AnythingOfType("*auth.Session")).Return(nil)
+	}
+
+	t.Run("NewDeviceNotifies", func(t *testing.T) {
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mocks.NewTransactor(t), mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		otherDevice, err := auth.NewKnownDevice(testUser.ID, "curl/8.5.0", "198.51.100.1")
+		require.NoError(t, err)
+
+		expectLogin(mockUserRepo, mockAuthRepo, mockRoleRepo)
+		mockAuthRepo.EXPECT().ListKnownDevicesByUser(ctx, testUser.ID.String()).Return([]*auth.KnownDevice{otherDevice}, nil)
+		mockAuthRepo.EXPECT().StoreKnownDevice(ctx, mock.MatchedBy(func(d *auth.KnownDevice) bool {
+			return d.Fingerprint == auth.DeviceFingerprint(input.UserAgent, input.IPAddress)
+		})).Return(nil)
+		mockAuthRepo.EXPECT().StoreSessionRevokeToken(ctx, mock.AnythingOfType("*auth.SessionRevokeToken")).Return(nil)
+		mockPublisher.EXPECT().
+			SendNewDeviceLoginEmail(ctx, mock.MatchedBy(func(msg auth.NewDeviceLoginEmailMessage) bool {
+				return msg.To == testUser.Email &&
+					msg.IPAddress == input.IPAddress &&
+					strings.HasPrefix(msg.RevokeURL, cfg.NewDeviceRevokeEndpoint+"?token=")
+			})).
+			Return(nil)
+
+		res, err := service.Login(ctx, input)
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, res.SessionID)
+	})
+
+	t.Run("KnownDeviceUpdated", func(t *testing.T) {
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mocks.NewTransactor(t), mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mocks.NewAuthMessagePublisher(t), nil, nil, nil)
+
+		// Known from an older browser version on the same network
+		device, err := auth.NewKnownDevice(testUser.ID, "Mozilla/5.0 (X11; Linux x86_64) Firefox/125.0", "203.0.113.99")
+		require.NoError(t, err)
+
+		expectLogin(mockUserRepo, mockAuthRepo, mockRoleRepo)
+		mockAuthRepo.EXPECT().ListKnownDevicesByUser(ctx, testUser.ID.String()).Return([]*auth.KnownDevice{device}, nil)
+		mockAuthRepo.EXPECT().UpdateKnownDevice(ctx, mock.MatchedBy(func(d *auth.KnownDevice) bool {
+			return d.ID == device.ID && d.IPAddress == input.IPAddress
+		})).Return(nil)
+
+		_, err = service.Login(ctx, input)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("RevokeUnrecognizedSession", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mocks.NewUserRepository(t), mockAuthRepo, mocks.NewRoleRepository(t), nil, mocks.NewAuthMessagePublisher(t), nil, nil, nil)
+
+		sess, err := auth.NewSession(testUser.ID, input.UserAgent, cfg.SessionTTL)
+		require.NoError(t, err)
+		device, err := auth.NewKnownDevice(testUser.ID, input.UserAgent, input.IPAddress)
+		require.NoError(t, err)
+		token, err := auth.NewSessionRevokeToken(sess, device, cfg.NewDeviceRevokeTTL)
+		require.NoError(t, err)
+
+		mockTransactor.EXPECT().
+			Transact(ctx, mock.AnythingOfType("func(context.Context) error")).
+			RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error { return fn(ctx) })
+		mockAuthRepo.EXPECT().GetSessionRevokeToken(ctx, token.Value).Return(token, nil)
+		mockAuthRepo.EXPECT().UpdateSessionRevokeToken(ctx, token).Return(nil)
+		mockAuthRepo.EXPECT().RevokeSessionFamily(ctx, sess.FamilyID.String()).Return(nil)
+		mockAuthRepo.EXPECT().DeleteKnownDevice(ctx, testUser.ID.String(), device.ID.String()).Return(nil)
+
+		err = service.RevokeUnrecognizedSession(ctx, auth.RevokeUnrecognizedSessionInput{Token: token.Value})
+
+		require.NoError(t, err)
+		assert.True(t, token.Used())
+	})
+
+	t.Run("RevokeUnrecognizedSessionTokenUsed", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mocks.NewUserRepository(t), mockAuthRepo, mocks.NewRoleRepository(t), nil, mocks.NewAuthMessagePublisher(t), nil, nil, nil)
+
+		token := &auth.SessionRevokeToken{
+			UserID:    testUser.ID,
+			FamilyID:  uuid.New(),
+			DeviceID:  uuid.New(),
+			Value:     "used-token",
+			ExpiresAt: time.Now().Add(time.Hour),
+		}
+		token.Revoke()
+
+		mockTransactor.EXPECT().
+			Transact(ctx, mock.AnythingOfType("func(context.Context) error")).
+			RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error { return fn(ctx) })
+		mockAuthRepo.EXPECT().GetSessionRevokeToken(ctx, token.Value).Return(token, nil)
+
+		err := service.RevokeUnrecognizedSession(ctx, auth.RevokeUnrecognizedSessionInput{Token: token.Value})
+
+		assert.ErrorIs(t, err, auth.ErrSessionRevokeTokenInvalid)
+	})
+}
+
+func TestService_Logout(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Auth{
+		JwtSecret:                 "test-secret",
+		JwtTTL:                    time.Hour,
+		SessionTTL:                24 * time.Hour,
+		ResetPasswordTTL:          time.Hour,
+		ResetPasswordFormEndpoint: "http://localhost:3000/reset-password",
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		// Setup
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		sessionID := uuid.New().String()
+		userID := uuid.New()
+
+		session, err := auth.NewSession(userID, "test-agent", cfg.SessionTTL)
+		require.NoError(t, err)
+
+		// Mock expectations
+		mockAuthRepo.EXPECT().GetSession(ctx, sessionID).Return(session, nil)
+		mockAuthRepo.EXPECT().UpdateSession(ctx, mock.AnythingOfType("*auth.Session")).Return(nil)
+
+		// Execute
+		err = service.Logout(ctx, sessionID)
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("RevokesAccessTokens", func(t *testing.T) {
+		// Setup
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+		mockRevocations := mocks.NewTokenRevocationStore(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, mockRevocations, nil)
+
+		session, err := auth.NewSession(uuid.New(), "test-agent", cfg.SessionTTL)
+		require.NoError(t, err)
+		sessionID := session.ID.String()
+
+		// Mock expectations
+		mockAuthRepo.EXPECT().GetSession(ctx, sessionID).Return(session, nil)
+		mockAuthRepo.EXPECT().UpdateSession(ctx, session).Return(nil)
+		mockRevocations.EXPECT().Revoke(ctx, session.FamilyID.String(), mock.MatchedBy(func(expiresAt time.Time) bool {
+			return time.Until(expiresAt) > cfg.JwtTTL-time.Minute && time.Until(expiresAt) <= cfg.JwtTTL
+		})).Return(nil)
+
+		// Execute
+		err = service.Logout(ctx, sessionID)
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("SessionAlreadyExpired", func(t *testing.T) {
+		// Setup
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		sessionID := uuid.New().String()
+		userID := uuid.New()
+
+		// Create valid session and manually set it as expired
+		session, err := auth.NewSession(userID, "test-agent", cfg.SessionTTL)
+		require.NoError(t, err)
+		session.ExpiresAt = time.Now().Add(-time.Hour) // Set to past
+
+		// Mock expectations
+		mockAuthRepo.EXPECT().GetSession(ctx, sessionID).Return(session, nil)
+
+		// Execute
+		err = service.Logout(ctx, sessionID)
+
+		// Assert
+		assert.NoError(t, err) // Should not error even if session is expired
+	})
+}
+
+func TestService_ForgotPassword(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Auth{
+		JwtSecret:                 "test-secret",
+		JwtTTL:                    time.Hour,
+		SessionTTL:                24 * time.Hour,
+		ResetPasswordTTL:          time.Hour,
+		ResetPasswordFormEndpoint: "http://localhost:3000/reset-password",
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		// Setup
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		input := auth.ForgotPasswordInput{
+			Email: "john@example.com",
+		}
+
+		testUser := &user.User{
+			ID:    uuid.New(),
+			Name:  "John Doe",
+			Email: input.Email,
+		}
+
+		// Mock expectations
+		mockTransactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).Return(nil).Run(func(ctx context.Context, fn func(context.Context) error) {
+			// Inside the transaction, we need to set up the expectations
+			mockUserRepo.EXPECT().GetByEmail(ctx, input.Email).Return(testUser, nil)
+			mockAuthRepo.EXPECT().StoreResetPasswordToken(ctx, mock.AnythingOfType("*auth.ResetPasswordToken")).Return(nil)
+			mockPublisher.EXPECT().SendResetPasswordEmail(ctx, mock.AnythingOfType("auth.ResetPasswordEmailMessage")).Return(nil)
+
+			err := fn(ctx)
+			assert.NoError(t, err)
+		})
+
+		// Execute
+		err := service.ForgotPassword(ctx, input)
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("UserNotFound", func(t *testing.T) {
+		// Setup
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		input := auth.ForgotPasswordInput{
+			Email: "nonexistent@example.com",
+		}
+
+		// Mock expectations
+		mockTransactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).Run(func(ctx context.Context, fn func(context.Context) error) {
+			mockUserRepo.EXPECT().GetByEmail(ctx, input.Email).Return(nil, user.ErrNotFound)
+
+			err := fn(ctx)
+			assert.Error(t, err)
+			assert.Equal(t, user.ErrEmailNotVerified, err)
+		}).Return(user.ErrEmailNotVerified)
+
+		// Execute
+		err := service.ForgotPassword(ctx, input)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Equal(t, user.ErrEmailNotVerified, err)
+	})
+}
+
+func TestService_EnumerationProtection(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Auth{
+		JwtSecret:                 "test-secret",
+		JwtTTL:                    time.Hour,
+		SessionTTL:                24 * time.Hour,
+		ResetPasswordTTL:          time.Hour,
+		ResetPasswordFormEndpoint: "http://localhost:3000/reset-password",
+		EnumerationProtection:     true,
+	}
+
+	existingUser := &user.User{
+		ID:    uuid.New(),
+		Name:  "Existing User",
+		Email: "john@example.com",
+	}
+
+	t.Run("RegisterNotifiesOwner", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mocks.NewAuthRepository(t), mocks.NewRoleRepository(t), nil, mockPublisher, nil, nil, nil)
+
+		mockUserRepo.EXPECT().GetByEmail(ctx, existingUser.Email).Return(existingUser, nil)
+		mockTransactor.EXPECT().
+			Transact(ctx, mock.AnythingOfType("func(context.Context) error")).
+			RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error { return fn(ctx) })
+		mockPublisher.EXPECT().
+			SendAccountExistsEmail(ctx, auth.AccountExistsEmailMessage{To: existingUser.Email, Name: existingUser.Name}).
+			Return(nil)
+
+		err := service.Register(ctx, auth.RegisterInput{
+			Name:           "John Doe",
+			Email:          existingUser.Email,
+			Password:       "password123",
+			RepeatPassword: "password123",
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("RegisterSoftDeletedEmail", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mocks.NewAuthRepository(t), mocks.NewRoleRepository(t), nil, mockPublisher, nil, nil, nil)
+
+		// Soft deleted accounts are hidden from GetByEmail but still hold the email
+		mockUserRepo.EXPECT().GetByEmail(ctx, "deleted@example.com").Return(nil, user.ErrNotFound)
+		mockTransactor.EXPECT().
+			Transact(ctx, mock.AnythingOfType("func(context.Context) error")).
+			RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error { return fn(ctx) })
+		mockUserRepo.EXPECT().Store(ctx, mock.AnythingOfType("*user.User")).Return(user.ErrEmailExists)
+		mockPublisher.EXPECT().
+			SendAccountExistsEmail(ctx, auth.AccountExistsEmailMessage{To: "deleted@example.com", Name: "deleted@example.com"}).
+			Return(nil)
+
+		err := service.Register(ctx, auth.RegisterInput{
+			Name:           "John Doe",
+			Email:          "deleted@example.com",
+			Password:       "password123",
+			RepeatPassword: "password123",
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("LoginUnknownEmail", func(t *testing.T) {
+		mockUserRepo := mocks.NewUserRepository(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mocks.NewTransactor(t), mockUserRepo, mocks.NewAuthRepository(t), mocks.NewRoleRepository(t), nil, mocks.NewAuthMessagePublisher(t), nil, nil, nil)
+
+		mockUserRepo.EXPECT().GetByEmail(ctx, "nonexistent@example.com").Return(nil, user.ErrNotFound)
+
+		res, err := service.Login(ctx, auth.LoginInput{Email: "nonexistent@example.com", Password: "password123"})
+
+		assert.ErrorIs(t, err, auth.ErrWrongCredentials)
+		assert.Nil(t, res)
+	})
+
+	t.Run("ForgotPasswordUnknownEmail", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mocks.NewAuthRepository(t), mocks.NewRoleRepository(t), nil, mocks.NewAuthMessagePublisher(t), nil, nil, nil)
+
+		mockTransactor.EXPECT().
+			Transact(ctx, mock.AnythingOfType("func(context.Context) error")).
+			RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error { return fn(ctx) })
+		mockUserRepo.EXPECT().GetByEmail(ctx, "nonexistent@example.com").Return(nil, user.ErrNotFound)
+
+		err := service.ForgotPassword(ctx, auth.ForgotPasswordInput{Email: "nonexistent@example.com"})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("ResendVerificationAlreadyVerified", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mocks.NewAuthRepository(t), mocks.NewRoleRepository(t), nil, mocks.NewAuthMessagePublisher(t), nil, nil, nil)
+
+		verifiedUser := *existingUser
+		verifiedUser.MarkVerified()
+
+		mockTransactor.EXPECT().
+			Transact(ctx, mock.AnythingOfType("func(context.Context) error")).
+			RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error { return fn(ctx) })
+		mockUserRepo.EXPECT().GetByEmail(ctx, verifiedUser.Email).Return(&verifiedUser, nil)
+
+		err := service.ResendVerificationEmail(ctx, auth.ResendVerificationEmailInput{Email: verifiedUser.Email})
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestService_ForcePasswordReset(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Auth{
+		JwtSecret:                 "test-secret",
+		JwtTTL:                    time.Hour,
+		SessionTTL:                24 * time.Hour,
+		ResetPasswordTTL:          time.Hour,
+		ResetPasswordFormEndpoint: "http://localhost:3000/reset-password",
+	}
+
+	mockTransactor := mocks.NewTransactor(t)
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockAuthRepo := mocks.NewAuthRepository(t)
+	mockRoleRepo := mocks.NewRoleRepository(t)
+	mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+	service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+	testUser := &user.User{
+		ID:    uuid.New(),
+		Name:  "John Doe",
+		Email: "john@example.com",
+	}
+	userID := testUser.ID.String()
+
+	mockTransactor.EXPECT().
+		Transact(ctx, mock.AnythingOfType("func(context.Context) error")).
+		Run(func(ctx context.Context, fn func(context.Context) error) {
+			err := fn(ctx)
+			assert.NoError(t, err)
+		}).
+		Return(nil)
+	mockUserRepo.EXPECT().GetByID(ctx, userID).Return(testUser, nil)
+	mockUserRepo.EXPECT().
+		Update(ctx, mock.MatchedBy(func(u *user.User) bool { return u.PasswordResetRequired })).
+		Return(nil)
+	mockAuthRepo.EXPECT().RevokeSessionsByUser(ctx, userID, "").Return(nil)
+	mockAuthRepo.EXPECT().StoreResetPasswordToken(ctx, mock.AnythingOfType("*auth.ResetPasswordToken")).Return(nil)
+	mockPublisher.EXPECT().SendResetPasswordEmail(ctx, mock.AnythingOfType("auth.ResetPasswordEmailMessage")).Return(nil)
+
+	err := service.ForcePasswordReset(ctx, userID)
+	assert.NoError(t, err)
+}
+
+func TestService_SuspendUser(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Auth{
+		JwtSecret:  "test-secret",
+		JwtTTL:     time.Hour,
+		SessionTTL: 24 * time.Hour,
+	}
+
+	t.Run("RevokesAccess", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockOAuthRepo := mocks.NewOAuthRepository(t)
+		mockAuditor := mocks.NewAuditWriter(t)
+		mockRevocations := mocks.NewTokenRevocationStore(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mocks.NewRoleRepository(t), mockOAuthRepo, mocks.NewAuthMessagePublisher(t), mockAuditor, mockRevocations, nil)
+
+		testUser := &user.User{ID: uuid.New(), Name: "John Doe", Email: "john@example.com"}
+		userID := testUser.ID.String()
+		session, err := auth.NewSession(testUser.ID, "test-agent", cfg.SessionTTL)
+		require.NoError(t, err)
+
+		// The access token the user currently signs in with
+		claims := &auth.AccessTokenClaims{UserID: userID, SessionID: session.FamilyID.String()}
+
+		var revoked []string
+		mockRevocations.EXPECT().
+			Revoke(ctx, mock.Anything, mock.AnythingOfType("time.Time")).
+			RunAndReturn(func(_ context.Context, id string, _ time.Time) error {
+				revoked = append(revoked, id)
+				return nil
+			})
+		mockRevocations.EXPECT().
+			IsRevoked(ctx, mock.Anything).
+			RunAndReturn(func(_ context.Context, ids ...string) (bool, error) {
+				for _, id := range ids {
+					if slices.Contains(revoked, id) {
+						return true, nil
+					}
+				}
+				return false, nil
+			})
+
+		mockTransactor.EXPECT().
+			Transact(ctx, mock.AnythingOfType("func(context.Context) error")).
+			RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error { return fn(ctx) })
+		mockUserRepo.EXPECT().GetByID(ctx, userID).Return(testUser, nil)
+		mockUserRepo.EXPECT().
+			Update(ctx, mock.MatchedBy(func(u *user.User) bool { return u.IsSuspended() })).
+			Return(nil)
+		mockAuthRepo.EXPECT().ListSessionsByUser(ctx, userID).Return([]*auth.Session{session}, nil)
+		mockAuthRepo.EXPECT().RevokeSessionsByUser(ctx, userID, "").Return(nil)
+		mockOAuthRepo.EXPECT().RevokeOAuthGrantsByUser(ctx, userID).Return(nil)
+		mockAuditor.EXPECT().
+			Write(ctx, mock.MatchedBy(func(e *audit.Event) bool {
+				return e.Type == audit.EventAccountSuspended && e.UserID.UUID.String() == userID
+			})).
+			Return(nil)
+
+		require.NoError(t, auth.CheckAccessTokenRevoked(ctx, mockRevocations, claims))
+
+		err = service.SuspendUser(ctx, userID)
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, auth.CheckAccessTokenRevoked(ctx, mockRevocations, claims), auth.ErrAccessTokenRevoked)
+	})
+
+	t.Run("AlreadySuspended", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mocks.NewAuthRepository(t), mocks.NewRoleRepository(t), nil, mocks.NewAuthMessagePublisher(t), nil, nil, nil)
+
+		testUser := &user.User{ID: uuid.New(), Name: "John Doe", Email: "john@example.com"}
+		require.NoError(t, testUser.Suspend())
+
+		mockTransactor.EXPECT().
+			Transact(ctx, mock.AnythingOfType("func(context.Context) error")).
+			RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error { return fn(ctx) })
+		mockUserRepo.EXPECT().GetByID(ctx, testUser.ID.String()).Return(testUser, nil)
+
+		err := service.SuspendUser(ctx, testUser.ID.String())
+		assert.ErrorIs(t, err, user.ErrAlreadySuspended)
+	})
+}
+
+func TestService_ResetPassword(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Auth{
+		JwtSecret:                 "test-secret",
+		JwtTTL:                    time.Hour,
+		SessionTTL:                24 * time.Hour,
+		ResetPasswordTTL:          time.Hour,
+		ResetPasswordFormEndpoint: "http://localhost:3000/reset-password",
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		// Setup
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		userID := uuid.New()
+		token, err := auth.NewResetPasswordToken(userID, cfg.ResetPasswordTTL)
+		require.NoError(t, err)
+
+		input := auth.ResetPasswordInput{
+			Token:             token.Value,
+			NewPassword:       "newpassword123",
+			RepeatNewPassword: "newpassword123",
+		}
+
+		testUser := &user.User{
+			ID:    userID,
+			Name:  "John Doe",
+			Email: "john@example.com",
+		}
+
+		// Mock expectations
+		mockTransactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).Return(nil).Run(func(ctx context.Context, fn func(context.Context) error) {
+			mockAuthRepo.EXPECT().GetResetPasswordToken(ctx, input.Token).Return(token, nil)
+			mockUserRepo.EXPECT().GetByID(ctx, userID.String()).Return(testUser, nil)
+			mockAuthRepo.EXPECT().UpdateResetPasswordToken(ctx, mock.AnythingOfType("*auth.ResetPasswordToken")).Return(nil)
+			mockUserRepo.EXPECT().Update(ctx, mock.AnythingOfType("*user.User")).Return(nil)
+			mockAuthRepo.EXPECT().RevokeSessionsByUser(ctx, userID.String(), "").Return(nil)
+
+			err := fn(ctx)
+			assert.NoError(t, err)
+		})
+
+		// Execute
+		err = service.ResetPassword(ctx, input)
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("TokenExpired", func(t *testing.T) {
+		// Setup
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		userID := uuid.New()
+		// Create valid token and manually set it as expired
+		token, err := auth.NewResetPasswordToken(userID, cfg.ResetPasswordTTL)
+		require.NoError(t, err)
+		token.ExpiresAt = time.Now().Add(-time.Hour) // Set to past
+
+		input := auth.ResetPasswordInput{
+			Token:             token.Value,
+			NewPassword:       "newpassword123",
+			RepeatNewPassword: "newpassword123",
+		}
+
+		// Mock expectations
+		mockTransactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).Run(func(ctx context.Context, fn func(context.Context) error) {
+			mockAuthRepo.EXPECT().GetResetPasswordToken(ctx, input.Token).Return(token, nil)
+
+			err := fn(ctx)
+			assert.Error(t, err)
+			assert.Equal(t, auth.ErrResetPasswordTokenInvalid, err)
+		}).Return(auth.ErrResetPasswordTokenInvalid)
+
+		// Execute
+		err = service.ResetPassword(ctx, input)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Equal(t, auth.ErrResetPasswordTokenInvalid, err)
+	})
+}
+
+func TestService_ChangePassword(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Auth{
+		JwtSecret:                 "test-secret",
+		JwtTTL:                    time.Hour,
+		SessionTTL:                24 * time.Hour,
+		ResetPasswordTTL:          time.Hour,
+		ResetPasswordFormEndpoint: "http://localhost:3000/reset-password",
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		// Setup
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		userID := uuid.New().String()
+		oldPassword := "oldpassword123"
+		newPassword := "newpassword123"
+
+		hashedOldPassword, err := auth.HashPassword(oldPassword)
+		require.NoError(t, err)
+
+		testUser := &user.User{
+			ID:       uuid.MustParse(userID),
+			Name:     "John Doe",
+			Email:    "john@example.com",
+			Password: string(hashedOldPassword),
+		}
+
+		input := auth.ChangePasswordInput{
+			Password:          oldPassword,
+			NewPassword:       newPassword,
+			RepeatNewPassword: newPassword,
+			SessionID:         uuid.New().String(),
+		}
+
+		// Mock expectations
+		mockUserRepo.EXPECT().GetByID(ctx, userID).Return(testUser, nil)
+		mockTransactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).Return(nil).Run(func(ctx context.Context, fn func(context.Context) error) {
+			mockUserRepo.EXPECT().Update(ctx, mock.AnythingOfType("*user.User")).Return(nil)
+			mockAuthRepo.EXPECT().RevokeSessionsByUser(ctx, userID, input.SessionID).Return(nil)
+
+			err := fn(ctx)
+			assert.NoError(t, err)
+		})
+
+		// Execute
+		err = service.ChangePassword(ctx, userID, input)
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("RevokesOtherAccessTokens", func(t *testing.T) {
+		// Setup
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+		mockRevocations := mocks.NewTokenRevocationStore(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, mockRevocations, nil)
+
+		userID := uuid.New()
+		hashedPassword, err := auth.HashPassword("oldpassword123")
+		require.NoError(t, err)
+		testUser := &user.User{ID: userID, Name: "John Doe", Email: "john@example.com", Password: string(hashedPassword)}
+
+		current, err := auth.NewSession(userID, "current-agent", cfg.SessionTTL)
+		require.NoError(t, err)
+		other, err := auth.NewSession(userID, "other-agent", cfg.SessionTTL)
+		require.NoError(t, err)
+
+		input := auth.ChangePasswordInput{
+			Password:          "oldpassword123",
+			NewPassword:       "newpassword123",
+			RepeatNewPassword: "newpassword123",
+			SessionID:         current.FamilyID.String(),
+		}
+
+		// Mock expectations
+		mockUserRepo.EXPECT().GetByID(ctx, userID.String()).Return(testUser, nil)
+		mockTransactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			mockUserRepo.EXPECT().Update(ctx, testUser).Return(nil)
+			mockAuthRepo.EXPECT().ListSessionsByUser(ctx, userID.String()).Return([]*auth.Session{current, other}, nil)
+			mockAuthRepo.EXPECT().RevokeSessionsByUser(ctx, userID.String(), input.SessionID).Return(nil)
+			// Only the other device is signed out, the current access token keeps working
+			mockRevocations.EXPECT().Revoke(ctx, other.FamilyID.String(), mock.AnythingOfType("time.Time")).Return(nil)
+			return fn(ctx)
+		})
+
+		// Execute
+		err = service.ChangePassword(ctx, userID.String(), input)
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("RecordsAuditEvent", func(t *testing.T) {
+		// Setup
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockAuditor := mocks.NewAuditWriter(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mocks.NewRoleRepository(t), nil, mocks.NewAuthMessagePublisher(t), mockAuditor, nil, nil)
+
+		userID := uuid.New()
+		hashedPassword, err := auth.HashPassword("oldpassword123")
+		require.NoError(t, err)
+		testUser := &user.User{ID: userID, Name: "John Doe", Email: "john@example.com", Password: string(hashedPassword)}
+
+		input := auth.ChangePasswordInput{
+			Password:          "oldpassword123",
+			NewPassword:       "newpassword123",
+			RepeatNewPassword: "newpassword123",
+			SessionID:         uuid.New().String(),
+		}
+
+		reqCtx := audit.WithActor(audit.WithRequest(ctx, audit.Request{
+			IPAddress: "203.0.113.7",
+			UserAgent: "Mozilla/5.0",
+			RequestID: "req-1",
+		}), userID.String())
+
+		// Mock expectations
+		mockUserRepo.EXPECT().GetByID(reqCtx, userID.String()).Return(testUser, nil)
+		mockTransactor.EXPECT().Transact(reqCtx, mock.AnythingOfType("func(context.Context) error")).RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			mockUserRepo.EXPECT().Update(ctx, testUser).Return(nil)
+			mockAuthRepo.EXPECT().RevokeSessionsByUser(ctx, userID.String(), input.SessionID).Return(nil)
+			// Written with the transaction context, so the event rolls back along with the change
+			mockAuditor.EXPECT().Write(ctx, mock.MatchedBy(func(e *audit.Event) bool {
+				return e.Type == audit.EventPasswordChanged &&
+					e.UserID.UUID == userID &&
+					e.ActorID.UUID == userID &&
+					e.IPAddress == "203.0.113.7" &&
+					e.UserAgent == "Mozilla/5.0" &&
+					e.RequestID == "req-1"
+			})).Return(nil)
+			return fn(ctx)
+		})
+
+		// Execute
+		err = service.ChangePassword(reqCtx, userID.String(), input)
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("WrongCurrentPassword", func(t *testing.T) {
+		// Setup
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		userID := uuid.New().String()
+		oldPassword := "oldpassword123"
+		wrongPassword := "wrongpassword"
+
+		hashedOldPassword, err := auth.HashPassword(oldPassword)
+		require.NoError(t, err)
+
+		testUser := &user.User{
+			ID:       uuid.MustParse(userID),
+			Name:     "John Doe",
+			Email:    "john@example.com",
+			Password: string(hashedOldPassword),
+		}
+
+		input := auth.ChangePasswordInput{
+			Password:          wrongPassword,
+			NewPassword:       "newpassword123",
+			RepeatNewPassword: "newpassword123",
+		}
+
+		// Mock expectations
+		mockUserRepo.EXPECT().GetByID(ctx, userID).Return(testUser, nil)
+
+		// Execute
+		err = service.ChangePassword(ctx, userID, input)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Equal(t, auth.ErrWrongCredentials, err)
+	})
+}
+
+func TestService_PasswordPolicy(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Auth{
+		JwtSecret:                  "test-secret",
+		JwtTTL:                     time.Hour,
+		SessionTTL:                 24 * time.Hour,
+		PasswordMinLength:          10,
+		PasswordHistorySize:        3,
+		PasswordRejectPersonalInfo: true,
+	}
+
+	t.Run("RegisterViolations", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		err := service.Register(ctx, auth.RegisterInput{
+			Name:           "John Doe",
+			Email:          "john@example.com",
+			Password:       "john123",
+			RepeatPassword: "john123",
+		})
+
+		var verr *validator.ValidationError
+		require.ErrorAs(t, err, &verr)
+		assert.Len(t, verr.GetField("password"), 2)
+	})
+
+	t.Run("ChangePasswordReused", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		currentHash, err := auth.HashPassword("current-secret-1")
+		require.NoError(t, err)
+		previousHash, err := auth.HashPassword("previous-secret-1")
+		require.NoError(t, err)
+
+		testUser := &user.User{
+			ID:       uuid.New(),
+			Name:     "John Doe",
+			Email:    "john@example.com",
+			Password: string(currentHash),
+		}
+		userID := testUser.ID.String()
+
+		mockUserRepo.EXPECT().GetByID(ctx, userID).Return(testUser, nil)
+		mockAuthRepo.EXPECT().GetPasswordHistory(ctx, userID, 2).Return([]string{string(previousHash)}, nil)
+
+		err = service.ChangePassword(ctx, userID, auth.ChangePasswordInput{
+			Password:          "current-secret-1",
+			NewPassword:       "previous-secret-1",
+			RepeatNewPassword: "previous-secret-1",
+		})
+
+		var verr *validator.ValidationError
+		require.ErrorAs(t, err, &verr)
+		assert.Equal(t, []string{"Must not be one of your last 3 passwords"}, verr.GetField("new_password"))
+	})
+
+	t.Run("ChangePasswordRecordsHistory", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		currentHash, err := auth.HashPassword("current-secret-1")
+		require.NoError(t, err)
+
+		testUser := &user.User{
+			ID:       uuid.New(),
+			Name:     "John Doe",
+			Email:    "john@example.com",
+			Password: string(currentHash),
+		}
+		userID := testUser.ID.String()
+
+		input := auth.ChangePasswordInput{
+			Password:          "current-secret-1",
+			NewPassword:       "brand-new-secret-1",
+			RepeatNewPassword: "brand-new-secret-1",
+			SessionID:         uuid.New().String(),
+		}
+
+		mockUserRepo.EXPECT().GetByID(ctx, userID).Return(testUser, nil)
+		mockAuthRepo.EXPECT().GetPasswordHistory(ctx, userID, 2).Return(nil, nil)
+		mockTransactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).Return(nil).Run(func(ctx context.Context, fn func(context.Context) error) {
+			mockAuthRepo.EXPECT().StorePasswordHistory(ctx, userID, string(currentHash), 2).Return(nil)
+			mockUserRepo.EXPECT().Update(ctx, mock.AnythingOfType("*user.User")).Return(nil)
+			mockAuthRepo.EXPECT().RevokeSessionsByUser(ctx, userID, input.SessionID).Return(nil)
+
+			err := fn(ctx)
+			assert.NoError(t, err)
+		})
+
+		err = service.ChangePassword(ctx, userID, input)
+		assert.NoError(t, err)
+	})
+}
+
+func TestService_DeleteAccount(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Auth{
+		JwtSecret:  "test-secret",
+		JwtTTL:     time.Hour,
+		SessionTTL: 24 * time.Hour,
+	}
+
+	hashedPassword, err := auth.HashPassword("password123")
+	require.NoError(t, err)
+
+	t.Run("Success", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		testUser := &user.User{
+			ID:       uuid.New(),
+			Name:     "John Doe",
+			Email:    "john@example.com",
+			Password: string(hashedPassword),
+		}
+		userID := testUser.ID.String()
+
+		mockTransactor.EXPECT().
+			Transact(ctx, mock.AnythingOfType("func(context.Context) error")).
+			Run(func(ctx context.Context, fn func(context.Context) error) {
+				err := fn(ctx)
+				assert.NoError(t, err)
+			}).
+			Return(nil)
+		mockUserRepo.EXPECT().GetByID(ctx, userID).Return(testUser, nil)
+		mockUserRepo.EXPECT().Delete(ctx, testUser).Return(nil)
+		mockAuthRepo.EXPECT().RevokeSessionsByUser(ctx, userID, "").Return(nil)
+
+		err := service.DeleteAccount(ctx, userID, auth.DeleteAccountInput{Password: "password123"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("WrongPassword", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		testUser := &user.User{
+			ID:       uuid.New(),
+			Name:     "John Doe",
+			Email:    "john@example.com",
+			Password: string(hashedPassword),
+		}
+		userID := testUser.ID.String()
+
+		mockUserRepo.EXPECT().GetByID(ctx, userID).Return(testUser, nil)
+
+		err := service.DeleteAccount(ctx, userID, auth.DeleteAccountInput{Password: "wrongpassword"})
+		assert.ErrorIs(t, err, auth.ErrWrongCredentials)
+	})
+
+	t.Run("MissingPassword", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mocks.NewAuthRepository(t), mocks.NewRoleRepository(t), nil, mocks.NewAuthMessagePublisher(t), nil, nil, nil)
+
+		testUser := &user.User{
+			ID:       uuid.New(),
+			Name:     "John Doe",
+			Email:    "john@example.com",
+			Password: string(hashedPassword),
+		}
+		userID := testUser.ID.String()
+
+		mockUserRepo.EXPECT().GetByID(ctx, userID).Return(testUser, nil)
+
+		err := service.DeleteAccount(ctx, userID, auth.DeleteAccountInput{})
+		assert.ErrorIs(t, err, user.ErrRequiredPassword)
+	})
+
+	t.Run("Passwordless", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mocks.NewRoleRepository(t), nil, mocks.NewAuthMessagePublisher(t), nil, nil, nil)
+
+		testUser, err := user.NewExternal("John Doe", "john@example.com")
+		require.NoError(t, err)
+		userID := testUser.ID.String()
+
+		mockTransactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockUserRepo.EXPECT().GetByID(ctx, userID).Return(testUser, nil)
+		mockUserRepo.EXPECT().Delete(ctx, testUser).Return(nil)
+		mockAuthRepo.EXPECT().RevokeSessionsByUser(ctx, userID, "").Return(nil)
+
+		err = service.DeleteAccount(ctx, userID, auth.DeleteAccountInput{})
+		assert.NoError(t, err)
+	})
+}
+
+func TestService_GetResetPasswordToken(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Auth{
+		JwtSecret:                 "test-secret",
+		JwtTTL:                    time.Hour,
+		SessionTTL:                24 * time.Hour,
+		ResetPasswordTTL:          time.Hour,
+		ResetPasswordFormEndpoint: "http://localhost:3000/reset-password",
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		// Setup
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		tokenValue := "test-token-value"
+		userID := uuid.New()
+		expectedToken, err := auth.NewResetPasswordToken(userID, cfg.ResetPasswordTTL)
+		require.NoError(t, err)
+		expectedToken.Value = tokenValue
+
+		// Mock expectations
+		mockAuthRepo.EXPECT().GetResetPasswordToken(ctx, tokenValue).Return(expectedToken, nil)
+
+		// Execute
+		token, err := service.GetResetPasswordToken(ctx, tokenValue)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, expectedToken, token)
+	})
+
+	t.Run("TokenNotFound", func(t *testing.T) {
+		// Setup
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		tokenValue := "nonexistent-token"
+
+		// Mock expectations
+		mockAuthRepo.EXPECT().GetResetPasswordToken(ctx, tokenValue).Return(nil, auth.ErrResetPasswordTokenNotFound)
+
+		// Execute
+		token, err := service.GetResetPasswordToken(ctx, tokenValue)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Equal(t, auth.ErrResetPasswordTokenNotFound, err)
+		assert.Nil(t, token)
+	})
+}
+
+func TestService_VerifyEmail(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Auth{
+		EmailVerificationTTL:      time.Hour,
+		EmailVerificationEndpoint: "http://localhost:3000/verify-email",
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		// Setup
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		userID := uuid.New()
+		token, err := auth.NewEmailVerificationToken(userID, cfg.EmailVerificationTTL)
+		require.NoError(t, err)
+
+		testUser := &user.User{
+			ID:    userID,
+			Name:  "John Doe",
+			Email: "john@example.com",
+		}
+
+		// Mock expectations
+		mockTransactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).Return(nil).Run(func(ctx context.Context, fn func(context.Context) error) {
+			mockAuthRepo.EXPECT().GetEmailVerificationToken(ctx, token.Value).Return(token, nil)
+			mockUserRepo.EXPECT().GetByID(ctx, userID.String()).Return(testUser, nil)
+			mockAuthRepo.EXPECT().UpdateEmailVerificationToken(ctx, token).Return(nil)
+			mockUserRepo.EXPECT().Update(ctx, testUser).Return(nil)
+
+			err := fn(ctx)
+			assert.NoError(t, err)
+		})
+
+		// Execute
+		err = service.VerifyEmail(ctx, auth.VerifyEmailInput{Token: token.Value})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.True(t, token.Used())
+		assert.True(t, testUser.IsVerified())
+	})
+
+	t.Run("TokenUsed", func(t *testing.T) {
+		// Setup
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		token, err := auth.NewEmailVerificationToken(uuid.New(), cfg.EmailVerificationTTL)
+		require.NoError(t, err)
+		token.Revoke()
+
+		// Mock expectations
+		mockTransactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).Run(func(ctx context.Context, fn func(context.Context) error) {
+			mockAuthRepo.EXPECT().GetEmailVerificationToken(ctx, token.Value).Return(token, nil)
+
+			err := fn(ctx)
+			assert.Equal(t, auth.ErrEmailVerificationTokenInvalid, err)
+		}).Return(auth.ErrEmailVerificationTokenInvalid)
+
+		// Execute
+		err = service.VerifyEmail(ctx, auth.VerifyEmailInput{Token: token.Value})
+
+		// Assert
+		assert.Equal(t, auth.ErrEmailVerificationTokenInvalid, err)
+	})
+}
+
+func TestService_RequestEmailChange(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Auth{
+		JwtSecret:           "test-secret",
+		JwtTTL:              time.Hour,
+		SessionTTL:          24 * time.Hour,
+		EmailChangeTTL:      time.Hour,
+		EmailChangeEndpoint: "http://localhost:3000/confirm-email-change",
+	}
+
+	testUser := &user.User{
+		ID:    uuid.New(),
+		Name:  "John Doe",
+		Email: "john@example.com",
+	}
+	userID := testUser.ID.String()
+
+	t.Run("Success", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		mockTransactor.EXPECT().
+			Transact(ctx, mock.AnythingOfType("func(context.Context) error")).
+			Run(func(ctx context.Context, fn func(context.Context) error) {
+				err := fn(ctx)
+				assert.NoError(t, err)
+			}).
+			Return(nil)
+		mockUserRepo.EXPECT().GetByID(ctx, userID).Return(testUser, nil)
+		mockUserRepo.EXPECT().GetByEmail(ctx, "new@example.com").Return(nil, user.ErrNotFound)
+		mockAuthRepo.EXPECT().
+			StoreEmailChangeToken(ctx, mock.MatchedBy(func(tok *auth.EmailChangeToken) bool {
+				return tok.NewEmail == "new@example.com"
+			})).
+			Return(nil)
+		mockPublisher.EXPECT().
+			SendEmailChangeConfirmation(ctx, mock.MatchedBy(func(msg auth.EmailChangeConfirmationMessage) bool {
+				return msg.To == "new@example.com"
+			})).
+			Return(nil)
+		mockPublisher.EXPECT().
+			SendEmailChangeNotification(ctx, auth.EmailChangeNotificationMessage{
+				To:       testUser.Email,
+				Name:     testUser.Name,
+				NewEmail: "new@example.com",
+			}).
+			Return(nil)
+
+		err := service.RequestEmailChange(ctx, userID, "new@example.com")
+		assert.NoError(t, err)
+	})
+
+	t.Run("EmailExists", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		mockTransactor.EXPECT().
+			Transact(ctx, mock.AnythingOfType("func(context.Context) error")).
+			Run(func(ctx context.Context, fn func(context.Context) error) {
+				err := fn(ctx)
+				assert.ErrorIs(t, err, user.ErrEmailExists)
+			}).
+			Return(user.ErrEmailExists)
+		mockUserRepo.EXPECT().GetByID(ctx, userID).Return(testUser, nil)
+		mockUserRepo.EXPECT().GetByEmail(ctx, "taken@example.com").Return(&user.User{ID: uuid.New()}, nil)
+
+		err := service.RequestEmailChange(ctx, userID, "taken@example.com")
+		assert.ErrorIs(t, err, user.ErrEmailExists)
+	})
+}
+
+func TestService_ConfirmEmailChange(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Auth{
+		JwtSecret:  "test-secret",
+		JwtTTL:     time.Hour,
+		SessionTTL: 24 * time.Hour,
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		testUser := &user.User{ID: uuid.New(), Name: "John Doe", Email: "john@example.com"}
+		token, err := auth.NewEmailChangeToken(testUser.ID, "new@example.com", time.Hour)
+		require.NoError(t, err)
+
+		mockTransactor.EXPECT().
+			Transact(ctx, mock.AnythingOfType("func(context.Context) error")).
+			Run(func(ctx context.Context, fn func(context.Context) error) {
+				err := fn(ctx)
+				assert.NoError(t, err)
+			}).
+			Return(nil)
+		mockAuthRepo.EXPECT().GetEmailChangeToken(ctx, token.Value).Return(token, nil)
+		mockUserRepo.EXPECT().GetByID(ctx, testUser.ID.String()).Return(testUser, nil)
+		mockAuthRepo.EXPECT().UpdateEmailChangeToken(ctx, token).Return(nil)
+		mockUserRepo.EXPECT().
+			Update(ctx, mock.MatchedBy(func(u *user.User) bool {
+				return u.Email == "new@example.com" && u.IsVerified()
+			})).
+			Return(nil)
+
+		err = service.ConfirmEmailChange(ctx, auth.ConfirmEmailChangeInput{Token: token.Value})
+		assert.NoError(t, err)
+		assert.True(t, token.Used())
+	})
+
+	t.Run("TokenUsed", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		token, err := auth.NewEmailChangeToken(uuid.New(), "new@example.com", time.Hour)
+		require.NoError(t, err)
+		token.Revoke()
+
+		mockTransactor.EXPECT().
+			Transact(ctx, mock.AnythingOfType("func(context.Context) error")).
+			Run(func(ctx context.Context, fn func(context.Context) error) {
+				err := fn(ctx)
+				assert.ErrorIs(t, err, auth.ErrEmailChangeTokenInvalid)
+			}).
+			Return(auth.ErrEmailChangeTokenInvalid)
+		mockAuthRepo.EXPECT().GetEmailChangeToken(ctx, token.Value).Return(token, nil)
+
+		err = service.ConfirmEmailChange(ctx, auth.ConfirmEmailChangeInput{Token: token.Value})
+		assert.ErrorIs(t, err, auth.ErrEmailChangeTokenInvalid)
+	})
+}
+
+func TestService_ResendVerificationEmail(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Auth{
+		EmailVerificationTTL:      time.Hour,
+		EmailVerificationEndpoint: "http://localhost:3000/verify-email",
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		// Setup
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		input := auth.ResendVerificationEmailInput{Email: "john@example.com"}
+		testUser := &user.User{
+			ID:    uuid.New(),
+			Name:  "John Doe",
+			Email: input.Email,
+		}
+
+		// Mock expectations
+		mockTransactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).Return(nil).Run(func(ctx context.Context, fn func(context.Context) error) {
+			mockUserRepo.EXPECT().GetByEmail(ctx, input.Email).Return(testUser, nil)
+			mockAuthRepo.EXPECT().StoreEmailVerificationToken(ctx, mock.AnythingOfType("*auth.EmailVerificationToken")).Return(nil)
+			mockPublisher.EXPECT().SendVerificationEmail(ctx, mock.MatchedBy(func(msg auth.VerificationEmailMessage) bool {
+				return msg.To == input.Email && strings.HasPrefix(msg.VerificationURL, cfg.EmailVerificationEndpoint+"?token=")
+			})).Return(nil)
+
+			err := fn(ctx)
+			assert.NoError(t, err)
+		})
+
+		// Execute
+		err := service.ResendVerificationEmail(ctx, input)
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("AlreadyVerified", func(t *testing.T) {
+		// Setup
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		input := auth.ResendVerificationEmailInput{Email: "john@example.com"}
+		testUser := &user.User{
+			ID:    uuid.New(),
+			Name:  "John Doe",
+			Email: input.Email,
+		}
+		testUser.MarkVerified()
+
+		// Mock expectations
+		mockTransactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).Run(func(ctx context.Context, fn func(context.Context) error) {
+			mockUserRepo.EXPECT().GetByEmail(ctx, input.Email).Return(testUser, nil)
+
+			err := fn(ctx)
+			assert.Equal(t, auth.ErrEmailAlreadyVerified, err)
+		}).Return(auth.ErrEmailAlreadyVerified)
+
+		// Execute
+		err := service.ResendVerificationEmail(ctx, input)
+
+		// Assert
+		assert.Equal(t, auth.ErrEmailAlreadyVerified, err)
+	})
+}
+
+func TestService_Login_MFARequired(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Auth{
+		JwtSecret:       "test-secret",
+		JwtTTL:          time.Hour,
+		SessionTTL:      24 * time.Hour,
+		MFAChallengeTTL: 5 * time.Minute,
+	}
+
+	// Setup
+	mockTransactor := mocks.NewTransactor(t)
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockAuthRepo := mocks.NewAuthRepository(t)
+	mockRoleRepo := mocks.NewRoleRepository(t)
+	mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+	service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+	input := auth.LoginInput{
+		Email:     "john@example.com",
+		Password:  "password123",
+		UserAgent: "test-agent",
+	}
+
+	hashedPassword, err := auth.HashPassword(input.Password)
+	require.NoError(t, err)
+
+	testUser := &user.User{
+		ID:       uuid.New(),
+		Name:     "John Doe",
+		Email:    input.Email,
+		Password: string(hashedPassword),
+	}
+
+	factor, _, err := auth.NewTOTPFactor(testUser.ID, "test", testUser.Email)
+	require.NoError(t, err)
+	factor.Confirm()
+
+	// Mock expectations
+	mockUserRepo.EXPECT().GetByEmail(ctx, input.Email).Return(testUser, nil)
+	mockAuthRepo.EXPECT().GetTOTPFactor(ctx, testUser.ID.String()).Return(factor, nil)
+	mockAuthRepo.EXPECT().StoreMFAChallenge(ctx, mock.MatchedBy(func(c *auth.MFAChallenge) bool {
+		return c.UserID == testUser.ID && c.UserAgent == input.UserAgent
+	})).Return(nil)
+
+	// Execute
+	res, err := service.Login(ctx, input)
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, res.MFARequired())
+	assert.Empty(t, res.AccessToken)
+	assert.Empty(t, res.SessionID)
+	assert.NotEmpty(t, res.MFAChallenge.Value)
+}
+
+func TestService_RequestMagicLink(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Auth{
+		MagicLinkTTL:      15 * time.Minute,
+		MagicLinkEndpoint: "http://localhost:3000/magic-link",
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		testUser := &user.User{
+			ID:    uuid.New(),
+			Name:  "John Doe",
+			Email: "john@example.com",
+		}
+
+		mockUserRepo.EXPECT().GetByEmail(ctx, testUser.Email).Return(testUser, nil)
+		mockTransactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).Return(nil).Run(func(ctx context.Context, fn func(context.Context) error) {
+			mockAuthRepo.EXPECT().StoreMagicLinkToken(ctx, mock.AnythingOfType("*auth.MagicLinkToken")).Return(nil)
+			mockPublisher.EXPECT().
+				SendMagicLinkEmail(ctx, mock.MatchedBy(func(msg auth.MagicLinkEmailMessage) bool {
+					return msg.To == testUser.Email &&
+						strings.HasPrefix(msg.LoginURL, cfg.MagicLinkEndpoint+"?token=") &&
+						msg.Expiry == cfg.MagicLinkTTL
+				})).
+				Return(nil)
+
+			err := fn(ctx)
+			assert.NoError(t, err)
+		})
+
+		err := service.RequestMagicLink(ctx, auth.MagicLinkInput{Email: testUser.Email})
+		assert.NoError(t, err)
+	})
+
+	t.Run("UnknownEmail", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		mockUserRepo.EXPECT().GetByEmail(ctx, "ghost@example.com").Return(nil, user.ErrNotFound)
+
+		err := service.RequestMagicLink(ctx, auth.MagicLinkInput{Email: "ghost@example.com"})
+		assert.NoError(t, err)
+	})
+}
+
+func TestService_VerifyMagicLink(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Auth{
+		JwtSecret:       "test-secret",
+		JwtTTL:          time.Hour,
+		SessionTTL:      24 * time.Hour,
+		MagicLinkTTL:    15 * time.Minute,
+		MFAChallengeTTL: 5 * time.Minute,
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		testUser := &user.User{
+			ID:    uuid.New(),
+			Name:  "John Doe",
+			Email: "john@example.com",
+		}
+		userID := testUser.ID.String()
+
+		token, err := auth.NewMagicLinkToken(testUser.ID, cfg.MagicLinkTTL)
+		require.NoError(t, err)
+
+		mockTransactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).Return(nil).Run(func(ctx context.Context, fn func(context.Context) error) {
+			mockAuthRepo.EXPECT().GetMagicLinkToken(ctx, token.Value).Return(token, nil)
+			mockAuthRepo.EXPECT().UpdateMagicLinkToken(ctx, token).Return(nil)
+			mockUserRepo.EXPECT().GetByID(ctx, userID).Return(testUser, nil)
+			mockUserRepo.EXPECT().Update(ctx, testUser).Return(nil)
+			mockAuthRepo.EXPECT().GetTOTPFactor(ctx, userID).Return(nil, auth.ErrTOTPFactorNotFound)
+			mockRoleRepo.EXPECT().GetRolesByUser(ctx, userID).Return([]*auth.Role{}, nil)
+			expectKnownDevice(mockAuthRepo)
+			mockAuthRepo.EXPECT().
+				StoreSession(ctx, mock.MatchedBy(func(s *auth.Session) bool {
+					return slices.Equal(s.AMR, []string{auth.AMRMagicLink})
+				})).
+				Return(nil)
+
+			err := fn(ctx)
+			assert.NoError(t, err)
+		})
+
+		res, err := service.VerifyMagicLink(ctx, auth.VerifyMagicLinkInput{Token: token.Value})
+		require.NoError(t, err)
+		assert.NotEmpty(t, res.AccessToken)
+		assert.NotEmpty(t, res.SessionID)
+		assert.True(t, token.Used())
+		assert.True(t, testUser.IsVerified())
+	})
+
+	t.Run("MFARequired", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		testUser := &user.User{
+			ID:    uuid.New(),
+			Name:  "John Doe",
+			Email: "john@example.com",
+		}
+		testUser.MarkVerified()
+		userID := testUser.ID.String()
+
+		token, err := auth.NewMagicLinkToken(testUser.ID, cfg.MagicLinkTTL)
+		require.NoError(t, err)
+
+		factor, _, err := auth.NewTOTPFactor(testUser.ID, "Test", testUser.Email)
+		require.NoError(t, err)
+		factor.Confirm()
+
+		mockTransactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).Return(nil).Run(func(ctx context.Context, fn func(context.Context) error) {
+			mockAuthRepo.EXPECT().GetMagicLinkToken(ctx, token.Value).Return(token, nil)
+			mockAuthRepo.EXPECT().UpdateMagicLinkToken(ctx, token).Return(nil)
+			mockUserRepo.EXPECT().GetByID(ctx, userID).Return(testUser, nil)
+			mockAuthRepo.EXPECT().GetTOTPFactor(ctx, userID).Return(factor, nil)
+			mockAuthRepo.EXPECT().
+				StoreMFAChallenge(ctx, mock.MatchedBy(func(c *auth.MFAChallenge) bool {
+					return c.FirstFactor == auth.AMRMagicLink
+				})).
+				Return(nil)
+
+			err := fn(ctx)
+			assert.NoError(t, err)
+		})
+
+		res, err := service.VerifyMagicLink(ctx, auth.VerifyMagicLinkInput{Token: token.Value})
+		require.NoError(t, err)
+		assert.True(t, res.MFARequired())
+	})
+
+	t.Run("TokenUsed", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		token, err := auth.NewMagicLinkToken(uuid.New(), cfg.MagicLinkTTL)
+		require.NoError(t, err)
+		token.Revoke()
+
+		mockTransactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).Return(auth.ErrMagicLinkTokenInvalid).Run(func(ctx context.Context, fn func(context.Context) error) {
+			mockAuthRepo.EXPECT().GetMagicLinkToken(ctx, token.Value).Return(token, nil)
+
+			err := fn(ctx)
+			assert.ErrorIs(t, err, auth.ErrMagicLinkTokenInvalid)
+		})
+
+		res, err := service.VerifyMagicLink(ctx, auth.VerifyMagicLinkInput{Token: token.Value})
+		assert.ErrorIs(t, err, auth.ErrMagicLinkTokenInvalid)
+		assert.Nil(t, res)
+	})
+}
+
+func TestService_BeginExternalLogin(t *testing.T) {
+	cfg := config.Auth{}
+
+	t.Run("Success", func(t *testing.T) {
+		mockProvider := mocks.NewIdentityProvider(t)
+		mockProvider.EXPECT().Name().Return("google")
+		mockProvider.EXPECT().
+			AuthCodeURL(mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string")).
+			Return("https://accounts.example.com/auth")
+
+		service := auth.NewService(cfg, testKeys, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, mockProvider)
+
+		req, err := service.BeginExternalLogin("google")
+		require.NoError(t, err)
+		assert.Equal(t, "https://accounts.example.com/auth", req.URL)
+		assert.NotEmpty(t, req.State)
+		assert.NotEmpty(t, req.Nonce)
+		assert.NotEmpty(t, req.Verifier)
+		assert.NotEqual(t, req.State, req.Nonce)
+	})
+
+	t.Run("UnknownProvider", func(t *testing.T) {
+		service := auth.NewService(cfg, testKeys, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		req, err := service.BeginExternalLogin("google")
+		assert.ErrorIs(t, err, auth.ErrIdentityProviderNotFound)
+		assert.Nil(t, req)
+	})
+}
+
+func TestService_CompleteExternalLogin(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Auth{
+		JwtSecret:  "test-secret",
+		JwtTTL:     time.Hour,
+		SessionTTL: 24 * time.Hour,
+	}
+	inp := auth.CompleteExternalLoginInput{
+		Provider: "google",
+		Code:     "auth-code",
+		Verifier: "verifier",
+		Nonce:    "nonce",
+	}
+	claims := &auth.ExternalClaims{
+		Subject:       "google-123",
+		Email:         "john@example.com",
+		EmailVerified: true,
+		Name:          "John Doe",
+	}
+
+	newProvider := func(t *testing.T, claims *auth.ExternalClaims) *mocks.IdentityProvider {
+		mockProvider := mocks.NewIdentityProvider(t)
+		mockProvider.EXPECT().Name().Return("google")
+		mockProvider.EXPECT().Exchange(ctx, inp.Code, inp.Verifier, inp.Nonce).Return(claims, nil)
+		return mockProvider
+	}
+
+	federatedSession := mock.MatchedBy(func(s *auth.Session) bool {
+		return slices.Equal(s.AMR, []string{auth.AMRFederated})
+	})
+
+	t.Run("LinkedIdentity", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil, newProvider(t, claims))
+
+		testUser, err := user.NewExternal("John Doe", "john@example.com")
+		require.NoError(t, err)
+		userID := testUser.ID.String()
+		identity := auth.NewExternalIdentity(testUser.ID, "google", claims)
+
+		mockTransactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).Return(nil).Run(func(ctx context.Context, fn func(context.Context) error) {
+			mockAuthRepo.EXPECT().GetExternalIdentity(ctx, "google", claims.Subject).Return(identity, nil)
+			mockUserRepo.EXPECT().GetByID(ctx, userID).Return(testUser, nil)
+			mockAuthRepo.EXPECT().GetTOTPFactor(ctx, userID).Return(nil, auth.ErrTOTPFactorNotFound)
+			mockRoleRepo.EXPECT().GetRolesByUser(ctx, userID).Return([]*auth.Role{}, nil)
+			expectKnownDevice(mockAuthRepo)
+			mockAuthRepo.EXPECT().StoreSession(ctx, federatedSession).Return(nil)
+
+			err := fn(ctx)
+			assert.NoError(t, err)
+		})
+
+		res, err := service.CompleteExternalLogin(ctx, inp)
+		require.NoError(t, err)
+		assert.NotEmpty(t, res.AccessToken)
+		assert.NotEmpty(t, res.SessionID)
+	})
+
+	t.Run("LinkExistingUser", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil, newProvider(t, claims))
+
+		testUser, err := user.New("John Doe", "john@example.com", "", "hashedpassword")
+		require.NoError(t, err)
+		testUser.MarkVerified()
+		userID := testUser.ID.String()
+
+		mockTransactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).Return(nil).Run(func(ctx context.Context, fn func(context.Context) error) {
+			mockAuthRepo.EXPECT().GetExternalIdentity(ctx, "google", claims.Subject).Return(nil, auth.ErrExternalIdentityNotFound)
+			mockUserRepo.EXPECT().GetByEmail(ctx, claims.Email).Return(testUser, nil)
+			mockAuthRepo.EXPECT().
+				StoreExternalIdentity(ctx, mock.MatchedBy(func(i *auth.ExternalIdentity) bool {
+					return i.UserID == testUser.ID && i.Provider == "google" && i.Subject == claims.Subject
+				})).
+				Return(nil)
+			mockAuthRepo.EXPECT().GetTOTPFactor(ctx, userID).Return(nil, auth.ErrTOTPFactorNotFound)
+			mockRoleRepo.EXPECT().GetRolesByUser(ctx, userID).Return([]*auth.Role{}, nil)
+			expectKnownDevice(mockAuthRepo)
+			mockAuthRepo.EXPECT().StoreSession(ctx, federatedSession).Return(nil)
+
+			err := fn(ctx)
+			assert.NoError(t, err)
+		})
+
+		res, err := service.CompleteExternalLogin(ctx, inp)
+		require.NoError(t, err)
+		assert.NotEmpty(t, res.AccessToken)
+		assert.True(t, testUser.HasPassword())
+	})
+
+	t.Run("ClaimUnverifiedUser", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockOAuthRepo := mocks.NewOAuthRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+		mockAuditor := mocks.NewAuditWriter(t)
+		mockRelyingParty := mocks.NewWebAuthnRelyingParty(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, mockOAuthRepo, mockPublisher, mockAuditor, nil, mockRelyingParty, newProvider(t, claims))
+
+		// Registered by someone who never proved owning the address, along with their own credentials
+		testUser, err := user.New("John Doe", "john@example.com", "", "attacker-password")
+		require.NoError(t, err)
+		userID := testUser.ID.String()
+
+		pat, _, err := auth.NewPersonalAccessToken(testUser.ID, "ci", nil, 0)
+		require.NoError(t, err)
+		passkeys := []*auth.WebAuthnCredential{newTestPasskey(testUser.ID)}
+
+		var events []audit.EventType
+		mockAuditor.EXPECT().Write(mock.Anything, mock.AnythingOfType("*audit.Event")).RunAndReturn(func(_ context.Context, e *audit.Event) error {
+			events = append(events, e.Type)
+			return nil
+		})
+
+		mockTransactor.EXPECT().Transact(mock.Anything, mock.AnythingOfType("func(context.Context) error")).RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockAuthRepo.EXPECT().GetExternalIdentity(ctx, "google", claims.Subject).Return(nil, auth.ErrExternalIdentityNotFound)
+		mockUserRepo.EXPECT().GetByEmail(ctx, claims.Email).Return(testUser, nil)
+		mockUserRepo.EXPECT().
+			Update(ctx, mock.MatchedBy(func(u *user.User) bool {
+				return u.IsVerified() && !u.HasPassword()
+			})).
+			Return(nil)
+		mockAuthRepo.EXPECT().RevokeSessionsByUser(ctx, userID, "").Return(nil)
+		mockAuthRepo.EXPECT().ListPersonalAccessTokensByUser(ctx, userID).Return([]*auth.PersonalAccessToken{pat}, nil)
+		mockAuthRepo.EXPECT().DeletePersonalAccessToken(ctx, userID, pat.ID.String()).Return(nil)
+		mockAuthRepo.EXPECT().ListWebAuthnCredentialsByUser(mock.Anything, userID).RunAndReturn(func(context.Context, string) ([]*auth.WebAuthnCredential, error) {
+			return passkeys, nil
+		})
+		mockAuthRepo.EXPECT().DeleteWebAuthnCredential(ctx, userID, passkeys[0].ID).RunAndReturn(func(context.Context, string, string) error {
+			passkeys = nil
+			return nil
+		})
+		mockAuthRepo.EXPECT().DeleteTOTPFactor(ctx, userID).Return(nil)
+		mockAuthRepo.EXPECT().DeleteRecoveryCodes(ctx, userID).Return(nil)
+		mockAuthRepo.EXPECT().RevokePendingTokensByUser(ctx, userID).Return(nil)
+		mockOAuthRepo.EXPECT().RevokeOAuthGrantsByUser(ctx, userID).Return(nil)
+		mockAuthRepo.EXPECT().StoreExternalIdentity(ctx, mock.AnythingOfType("*auth.ExternalIdentity")).Return(nil)
+		mockAuthRepo.EXPECT().GetTOTPFactor(ctx, userID).Return(nil, auth.ErrTOTPFactorNotFound)
+		mockRoleRepo.EXPECT().GetRolesByUser(ctx, userID).Return([]*auth.Role{}, nil)
+		expectKnownDevice(mockAuthRepo)
+		mockAuthRepo.EXPECT().StoreSession(ctx, federatedSession).Return(nil)
+
+		res, err := service.CompleteExternalLogin(ctx, inp)
+		require.NoError(t, err)
+		assert.NotEmpty(t, res.AccessToken)
+		assert.True(t, testUser.IsVerified())
+		assert.False(t, testUser.HasPassword())
+		assert.Contains(t, events, audit.EventAccountClaimed)
+
+		// The previous registrant's passkey no longer signs in
+		challenge, err := auth.NewWebAuthnChallenge(nil, auth.WebAuthnCeremonyLogin, []byte(`{}`), time.Minute)
+		require.NoError(t, err)
+		mockAuthRepo.EXPECT().GetWebAuthnChallenge(mock.Anything, challenge.Value).Return(challenge, nil)
+		mockAuthRepo.EXPECT().UpdateWebAuthnChallenge(mock.Anything, challenge).Return(nil)
+		mockUserRepo.EXPECT().GetByID(mock.Anything, userID).Return(testUser, nil)
+		mockRelyingParty.EXPECT().
+			FinishLogin(mock.Anything, challenge.State, mock.Anything, mock.Anything).
+			RunAndReturn(func(_ context.Context, _, _ []byte, lookup auth.WebAuthnAccountLookup) (*auth.WebAuthnAccount, *auth.WebAuthnCredential, error) {
+				account, err := lookup(testUser.ID)
+				if err != nil {
+					return nil, nil, err
+				}
+				if len(account.Credentials) == 0 {
+					return nil, nil, auth.ErrWebAuthnCredentialInvalid
+				}
+				return account, account.Credentials[0], nil
+			})
+
+		loginRes, err := service.FinishPasskeyLogin(ctx, auth.FinishPasskeyLoginInput{
+			ChallengeID: challenge.Value,
+			Credential:  json.RawMessage(`{"id":"Y3JlZGVudGlhbC1pZA"}`),
+		})
+		assert.ErrorIs(t, err, auth.ErrWebAuthnCredentialInvalid)
+		assert.Nil(t, loginRes)
+	})
+
+	t.Run("CreateUser", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil, newProvider(t, claims))
+
+		var created *user.User
+		mockTransactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).Return(nil).Run(func(ctx context.Context, fn func(context.Context) error) {
+			mockAuthRepo.EXPECT().GetExternalIdentity(ctx, "google", claims.Subject).Return(nil, auth.ErrExternalIdentityNotFound)
+			mockUserRepo.EXPECT().GetByEmail(ctx, claims.Email).Return(nil, user.ErrNotFound)
+			mockUserRepo.EXPECT().
+				Store(ctx, mock.MatchedBy(func(u *user.User) bool {
+					created = u
+					return u.Email == claims.Email && u.Name == claims.Name && !u.HasPassword() && u.IsVerified()
+				})).
+				Return(nil)
+			mockAuthRepo.EXPECT().StoreExternalIdentity(ctx, mock.AnythingOfType("*auth.ExternalIdentity")).Return(nil)
+			mockAuthRepo.EXPECT().GetTOTPFactor(ctx, mock.AnythingOfType("string")).Return(nil, auth.ErrTOTPFactorNotFound)
+			mockRoleRepo.EXPECT().GetRolesByUser(ctx, mock.AnythingOfType("string")).Return([]*auth.Role{}, nil)
+			expectKnownDevice(mockAuthRepo)
+			mockAuthRepo.EXPECT().StoreSession(ctx, federatedSession).Return(nil)
+
+			err := fn(ctx)
+			assert.NoError(t, err)
+		})
+
+		res, err := service.CompleteExternalLogin(ctx, inp)
+		require.NoError(t, err)
+		require.NotNil(t, created)
+
+		tokenClaims, err := auth.VerifyAccessToken(testKeys, res.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, created.ID.String(), tokenClaims.UserID)
+	})
+
+	t.Run("EmailNotVerified", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		unverified := *claims
+		unverified.EmailVerified = false
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil, newProvider(t, &unverified))
+
+		mockTransactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			mockAuthRepo.EXPECT().GetExternalIdentity(ctx, "google", claims.Subject).Return(nil, auth.ErrExternalIdentityNotFound)
+			return fn(ctx)
+		})
+
+		res, err := service.CompleteExternalLogin(ctx, inp)
+		assert.ErrorIs(t, err, auth.ErrExternalEmailNotVerified)
+		assert.Nil(t, res)
+	})
+
+	t.Run("ExchangeFailed", func(t *testing.T) {
+		mockProvider := mocks.NewIdentityProvider(t)
+		mockProvider.EXPECT().Name().Return("google")
+		mockProvider.EXPECT().Exchange(ctx, inp.Code, inp.Verifier, inp.Nonce).Return(nil, auth.ErrExternalLoginInvalid)
+
+		service := auth.NewService(cfg, testKeys, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, mockProvider)
+
+		res, err := service.CompleteExternalLogin(ctx, inp)
+		assert.ErrorIs(t, err, auth.ErrExternalLoginInvalid)
+		assert.Nil(t, res)
+	})
+}
+
+func TestService_VerifyMFA(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Auth{
+		JwtSecret:       "test-secret",
+		JwtTTL:          time.Hour,
+		SessionTTL:      24 * time.Hour,
+		MFAChallengeTTL: 5 * time.Minute,
+	}
+
+	testUser := &user.User{
+		ID:    uuid.New(),
+		Name:  "John Doe",
+		Email: "john@example.com",
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		// Setup
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		factor, enrollment, err := auth.NewTOTPFactor(testUser.ID, "test", testUser.Email)
+		require.NoError(t, err)
+		factor.Confirm()
+
+		code, err := totp.GenerateCode(enrollment.Secret, time.Now())
+		require.NoError(t, err)
+
+		challenge, err := auth.NewMFAChallenge(testUser.ID, "test-agent", auth.AMRPassword, []string{auth.MFAMethodTOTP}, cfg.MFAChallengeTTL)
+		require.NoError(t, err)
+
+		input := auth.VerifyMFAInput{
+			MFAToken:  challenge.Value,
+			Code:      code,
+			UserAgent: "test-agent",
+		}
+
+		// Mock expectations
+		mockTransactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).Return(nil).Run(func(ctx context.Context, fn func(context.Context) error) {
+			mockAuthRepo.EXPECT().GetMFAChallenge(ctx, challenge.Value).Return(challenge, nil)
+			mockAuthRepo.EXPECT().GetTOTPFactor(ctx, testUser.ID.String()).Return(factor, nil)
+			mockAuthRepo.EXPECT().UpdateTOTPFactor(ctx, factor).Return(nil)
+			mockAuthRepo.EXPECT().UpdateMFAChallenge(ctx, challenge).Return(nil)
+			mockUserRepo.EXPECT().GetByID(ctx, testUser.ID.String()).Return(testUser, nil)
+			mockRoleRepo.EXPECT().GetRolesByUser(ctx, testUser.ID.String()).Return([]*auth.Role{
+				{ID: 1, Name: auth.RoleSupport, Permissions: []string{auth.PermissionUsersRead}},
+			}, nil)
+			expectKnownDevice(mockAuthRepo)
+			mockAuthRepo.EXPECT().StoreSession(ctx, mock.MatchedBy(func(s *auth.Session) bool {
+				return slices.Contains(s.AMR, auth.AMRMultiFactor)
+			})).Return(nil)
+
+			err := fn(ctx)
+			assert.NoError(t, err)
+		})
+
+		// Execute
+		res, err := service.VerifyMFA(ctx, input)
+
+		// Assert
+		require.NoError(t, err)
+		assert.NotEmpty(t, res.AccessToken)
+		assert.NotEmpty(t, res.SessionID)
+		assert.True(t, challenge.Used())
+
+		claims, err := auth.VerifyAccessToken(testKeys, res.AccessToken)
+		require.NoError(t, err)
+		assert.True(t, claims.MultiFactor())
+		assert.Equal(t, []string{auth.RoleSupport}, claims.Roles)
+		assert.True(t, claims.HasPermission(auth.PermissionUsersRead))
+	})
+
+	t.Run("SuspendedWhilePending", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mocks.NewRoleRepository(t), nil, mocks.NewAuthMessagePublisher(t), nil, nil, nil)
+
+		factor, enrollment, err := auth.NewTOTPFactor(testUser.ID, "test", testUser.Email)
+		require.NoError(t, err)
+		factor.Confirm()
+
+		code, err := totp.GenerateCode(enrollment.Secret, time.Now())
+		require.NoError(t, err)
+
+		challenge, err := auth.NewMFAChallenge(testUser.ID, "test-agent", auth.AMRPassword, []string{auth.MFAMethodTOTP}, cfg.MFAChallengeTTL)
+		require.NoError(t, err)
+
+		suspendedUser := *testUser
+		require.NoError(t, suspendedUser.Suspend())
+
+		mockTransactor.EXPECT().
+			Transact(ctx, mock.AnythingOfType("func(context.Context) error")).
+			RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error { return fn(ctx) })
+		mockAuthRepo.EXPECT().GetMFAChallenge(ctx, challenge.Value).Return(challenge, nil)
+		mockAuthRepo.EXPECT().GetTOTPFactor(ctx, testUser.ID.String()).Return(factor, nil)
+		mockAuthRepo.EXPECT().UpdateTOTPFactor(ctx, factor).Return(nil)
+		mockAuthRepo.EXPECT().UpdateMFAChallenge(ctx, challenge).Return(nil)
+		mockUserRepo.EXPECT().GetByID(ctx, testUser.ID.String()).Return(&suspendedUser, nil)
+
+		res, err := service.VerifyMFA(ctx, auth.VerifyMFAInput{MFAToken: challenge.Value, Code: code})
+
+		assert.ErrorIs(t, err, user.ErrSuspended)
+		assert.Nil(t, res)
+	})
+
+	t.Run("WrongCode", func(t *testing.T) {
+		// Setup
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		challenge, err := auth.NewMFAChallenge(testUser.ID, "test-agent", auth.AMRPassword, []string{auth.MFAMethodTOTP}, cfg.MFAChallengeTTL)
+		require.NoError(t, err)
+
+		input := auth.VerifyMFAInput{
+			MFAToken:     challenge.Value,
+			RecoveryCode: "abcde-fghij",
+		}
+
+		// Mock expectations
+		mockTransactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).Return(nil).Run(func(ctx context.Context, fn func(context.Context) error) {
+			mockAuthRepo.EXPECT().GetMFAChallenge(ctx, challenge.Value).Return(challenge, nil)
+			mockUserRepo.EXPECT().GetByID(ctx, testUser.ID.String()).Return(testUser, nil)
+			mockAuthRepo.EXPECT().
+				GetRecoveryCode(ctx, testUser.ID.String(), auth.HashRecoveryCode(input.RecoveryCode)).
+				Return(nil, auth.ErrRecoveryCodeNotFound)
+			mockAuthRepo.EXPECT().UpdateMFAChallenge(ctx, challenge).Return(nil)
+
+			err := fn(ctx)
+			assert.NoError(t, err)
+		})
+
+		// Execute
+		res, err := service.VerifyMFA(ctx, input)
+
+		// Assert
+		assert.Equal(t, auth.ErrMFACodeInvalid, err)
+		assert.Nil(t, res)
+		assert.Equal(t, 1, challenge.Attempts)
+	})
+
+	t.Run("ChallengeExpired", func(t *testing.T) {
+		// Setup
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		challenge, err := auth.NewMFAChallenge(testUser.ID, "test-agent", auth.AMRPassword, []string{auth.MFAMethodTOTP}, -time.Minute)
+		require.NoError(t, err)
+
+		input := auth.VerifyMFAInput{
+			MFAToken: challenge.Value,
+			Code:     "123456",
+		}
+
+		// Mock expectations
+		mockTransactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).Run(func(ctx context.Context, fn func(context.Context) error) {
+			mockAuthRepo.EXPECT().GetMFAChallenge(ctx, challenge.Value).Return(challenge, nil)
+
+			err := fn(ctx)
+			assert.Equal(t, auth.ErrMFAChallengeInvalid, err)
+		}).Return(auth.ErrMFAChallengeInvalid)
+
+		// Execute
+		res, err := service.VerifyMFA(ctx, input)
+
+		// Assert
+		assert.Equal(t, auth.ErrMFAChallengeInvalid, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestService_ConfirmTOTP(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Auth{
+		JwtSecret:  "test-secret",
+		JwtTTL:     time.Hour,
+		SessionTTL: 24 * time.Hour,
+	}
+
+	userID := uuid.New()
+
+	t.Run("Success", func(t *testing.T) {
+		// Setup
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		factor, enrollment, err := auth.NewTOTPFactor(userID, "test", "john@example.com")
+		require.NoError(t, err)
+
+		code, err := totp.GenerateCode(enrollment.Secret, time.Now())
+		require.NoError(t, err)
+
+		// Mock expectations
+		mockTransactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).Return(nil).Run(func(ctx context.Context, fn func(context.Context) error) {
+			mockAuthRepo.EXPECT().GetTOTPFactor(ctx, userID.String()).Return(factor, nil)
+			mockAuthRepo.EXPECT().UpdateTOTPFactor(ctx, factor).Return(nil)
+			mockAuthRepo.EXPECT().
+				StoreRecoveryCodes(ctx, userID.String(), mock.AnythingOfType("[]*auth.RecoveryCode")).
+				Return(nil)
+
+			err := fn(ctx)
+			assert.NoError(t, err)
+		})
+
+		// Execute
+		codes, err := service.ConfirmTOTP(ctx, userID.String(), auth.ConfirmTOTPInput{Code: code})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Len(t, codes, 10)
+		assert.True(t, factor.Confirmed())
+	})
+
+	t.Run("EnrollmentMissing", func(t *testing.T) {
+		// Setup
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		// Mock expectations
+		mockTransactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).Run(func(ctx context.Context, fn func(context.Context) error) {
+			mockAuthRepo.EXPECT().GetTOTPFactor(ctx, userID.String()).Return(nil, auth.ErrTOTPFactorNotFound)
+
+			err := fn(ctx)
+			assert.Equal(t, auth.ErrTOTPEnrollmentMissing, err)
+		}).Return(auth.ErrTOTPEnrollmentMissing)
+
+		// Execute
+		codes, err := service.ConfirmTOTP(ctx, userID.String(), auth.ConfirmTOTPInput{Code: "123456"})
+
+		// Assert
+		assert.Equal(t, auth.ErrTOTPEnrollmentMissing, err)
+		assert.Nil(t, codes)
+	})
+}
+
+func TestService_ListSessions(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Auth{
+		JwtSecret:  "test-secret",
+		JwtTTL:     time.Hour,
+		SessionTTL: 24 * time.Hour,
+	}
+
+	// Setup
+	mockTransactor := mocks.NewTransactor(t)
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockAuthRepo := mocks.NewAuthRepository(t)
+	mockRoleRepo := mocks.NewRoleRepository(t)
+	mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+	service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+	userID := uuid.New()
+
+	current, err := auth.NewSession(userID, "current-agent", cfg.SessionTTL, auth.AMRPassword)
+	require.NoError(t, err)
+	other, err := auth.NewSession(userID, "other-agent", cfg.SessionTTL, auth.AMRPassword)
+	require.NoError(t, err)
+
+	// Mock expectations
+	mockAuthRepo.EXPECT().
+		ListSessionsByUser(ctx, userID.String()).
+		Return([]*auth.Session{current, other}, nil)
+
+	// Execute
+	sessions, err := service.ListSessions(ctx, userID.String(), current.FamilyID.String())
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	assert.True(t, sessions[0].Current)
+	assert.Equal(t, current.FamilyID.String(), sessions[0].ID)
+	assert.False(t, sessions[1].Current)
+	assert.Equal(t, "other-agent", sessions[1].UserAgent)
+}
+
+func TestService_RevokeSession(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Auth{
+		JwtSecret:  "test-secret",
+		JwtTTL:     time.Hour,
+		SessionTTL: 24 * time.Hour,
+	}
+
+	userID := uuid.New()
+
+	t.Run("Success", func(t *testing.T) {
+		// Setup
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		session, err := auth.NewSession(userID, "test-agent", cfg.SessionTTL)
+		require.NoError(t, err)
+		familyID := session.FamilyID.String()
+
+		// Mock expectations
+		mockAuthRepo.EXPECT().ListSessionsByUser(ctx, userID.String()).Return([]*auth.Session{session}, nil)
+		mockAuthRepo.EXPECT().RevokeSessionFamily(ctx, familyID).Return(nil)
+
+		// Execute
+		err = service.RevokeSession(ctx, userID.String(), familyID)
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("RevokesAccessTokens", func(t *testing.T) {
+		// Setup
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+		mockRevocations := mocks.NewTokenRevocationStore(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, mockRevocations, nil)
+
+		session, err := auth.NewSession(userID, "test-agent", cfg.SessionTTL)
+		require.NoError(t, err)
+		familyID := session.FamilyID.String()
+
+		// Mock expectations
+		mockAuthRepo.EXPECT().ListSessionsByUser(ctx, userID.String()).Return([]*auth.Session{session}, nil)
+		mockAuthRepo.EXPECT().RevokeSessionFamily(ctx, familyID).Return(nil)
+		mockRevocations.EXPECT().Revoke(ctx, familyID, mock.AnythingOfType("time.Time")).Return(nil)
+
+		// Execute
+		err = service.RevokeSession(ctx, userID.String(), familyID)
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("NotOwnedSession", func(t *testing.T) {
+		// Setup
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		// Mock expectations
+		mockAuthRepo.EXPECT().ListSessionsByUser(ctx, userID.String()).Return([]*auth.Session{}, nil)
+
+		// Execute
+		err := service.RevokeSession(ctx, userID.String(), uuid.New().String())
+
+		// Assert
+		assert.Equal(t, auth.ErrSessionNotFound, err)
+	})
+}
+
+func TestService_SwitchOrganization(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Auth{
+		JwtSecret:  "test-secret",
+		JwtTTL:     time.Hour,
+		SessionTTL: 24 * time.Hour,
+	}
+
+	testUser := &user.User{
+		ID:    uuid.New(),
+		Name:  "John Doe",
+		Email: "john@example.com",
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		// Setup
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		session, err := auth.NewSession(testUser.ID, "test-agent", cfg.SessionTTL)
+		require.NoError(t, err)
+		familyID := session.FamilyID.String()
+		orgID := uuid.New()
+
+		// Mock expectations
+		mockUserRepo.EXPECT().GetByID(ctx, testUser.ID.String()).Return(testUser, nil)
+		mockTransactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockAuthRepo.EXPECT().ListSessionsByUser(ctx, testUser.ID.String()).Return([]*auth.Session{session}, nil)
+		mockAuthRepo.EXPECT().UpdateSession(ctx, mock.MatchedBy(func(s *auth.Session) bool {
+			return s.OrganizationID.Valid && s.OrganizationID.UUID == orgID
+		})).Return(nil)
+		mockRoleRepo.EXPECT().GetRolesByUser(ctx, testUser.ID.String()).Return([]*auth.Role{}, nil)
+
+		// Execute
+		accessToken, err := service.SwitchOrganization(ctx, testUser.ID.String(), familyID, orgID.String())
+
+		// Assert
+		require.NoError(t, err)
+		claims, err := auth.VerifyAccessToken(testKeys, accessToken)
+		require.NoError(t, err)
+		assert.Equal(t, orgID.String(), claims.OrgID)
+		assert.Equal(t, familyID, claims.SessionID)
+	})
+
+	t.Run("NotOwnedSession", func(t *testing.T) {
+		// Setup
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		// Mock expectations
+		mockUserRepo.EXPECT().GetByID(ctx, testUser.ID.String()).Return(testUser, nil)
+		mockTransactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockAuthRepo.EXPECT().ListSessionsByUser(ctx, testUser.ID.String()).Return([]*auth.Session{}, nil)
+
+		// Execute
+		_, err := service.SwitchOrganization(ctx, testUser.ID.String(), uuid.New().String(), "")
+
+		// Assert
+		assert.Equal(t, auth.ErrSessionNotFound, err)
+	})
+}
+
+func TestService_AssignRole(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Auth{}
+
+	userID := uuid.New()
+	testUser := &user.User{
+		ID:    userID,
+		Name:  "John Doe",
+		Email: "john@example.com",
+	}
+
+	actor := &auth.AccessTokenClaims{UserID: uuid.NewString()}
+	adminRole := &auth.Role{ID: 1, Name: auth.RoleAdmin, Permissions: []string{auth.PermissionAll}}
+	roleManager := &auth.Role{ID: 3, Name: "role-manager", Permissions: []string{"roles:write", "users:read"}}
+
+	t.Run("Success", func(t *testing.T) {
+		// Setup
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		role := &auth.Role{ID: 2, Name: auth.RoleSupport, Permissions: []string{"users:read"}}
+
+		// Mock expectations
+		mockRoleRepo.EXPECT().GetRolesByUser(ctx, actor.UserID).Return([]*auth.Role{roleManager}, nil)
+		mockUserRepo.EXPECT().GetByID(ctx, userID.String()).Return(testUser, nil)
+		mockRoleRepo.EXPECT().GetRoleByName(ctx, role.Name).Return(role, nil)
+		mockRoleRepo.EXPECT().AssignRole(ctx, userID.String(), role.ID).Return(nil)
+
+		// Execute
+		err := service.AssignRole(ctx, actor, userID.String(), auth.AssignRoleInput{Role: role.Name})
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("RoleNotFound", func(t *testing.T) {
+		// Setup
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		// Mock expectations
+		mockRoleRepo.EXPECT().GetRolesByUser(ctx, actor.UserID).Return([]*auth.Role{adminRole}, nil)
+		mockUserRepo.EXPECT().GetByID(ctx, userID.String()).Return(testUser, nil)
+		mockRoleRepo.EXPECT().GetRoleByName(ctx, "unknown").Return(nil, auth.ErrRoleNotFound)
+
+		// Execute
+		err := service.AssignRole(ctx, actor, userID.String(), auth.AssignRoleInput{Role: "unknown"})
+
+		// Assert
+		assert.Equal(t, auth.ErrRoleNotFound, err)
+	})
+
+	t.Run("RoleWithPermissionsTheActorLacks", func(t *testing.T) {
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mocks.NewTransactor(t), mockUserRepo, mocks.NewAuthRepository(t), mockRoleRepo, nil, mocks.NewAuthMessagePublisher(t), nil, nil, nil)
+
+		// Holding roles:write must not let the actor assign themselves, or anyone, the admin role
+		mockRoleRepo.EXPECT().GetRolesByUser(ctx, actor.UserID).Return([]*auth.Role{roleManager}, nil)
+		mockUserRepo.EXPECT().GetByID(ctx, actor.UserID).Return(testUser, nil)
+		mockRoleRepo.EXPECT().GetRoleByName(ctx, adminRole.Name).Return(adminRole, nil)
+
+		err := service.AssignRole(ctx, actor, actor.UserID, auth.AssignRoleInput{Role: adminRole.Name})
+		assert.ErrorIs(t, err, auth.ErrRoleNotPermitted)
+	})
+
+	t.Run("ActorLostRolesWrite", func(t *testing.T) {
+		mockRoleRepo := mocks.NewRoleRepository(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mocks.NewTransactor(t), mocks.NewUserRepository(t), mocks.NewAuthRepository(t), mockRoleRepo, nil, mocks.NewAuthMessagePublisher(t), nil, nil, nil)
+
+		// The access token still carries roles:write, but the role granting it has since been revoked
+		mockRoleRepo.EXPECT().GetRolesByUser(ctx, actor.UserID).Return([]*auth.Role{}, nil)
+
+		err := service.AssignRole(ctx, actor, userID.String(), auth.AssignRoleInput{Role: auth.RoleSupport})
+		assert.ErrorIs(t, err, auth.ErrPermissionDenied)
+	})
+}
+
+func TestService_CreatePersonalAccessToken(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Auth{
+		JwtSecret:  "test-secret",
+		JwtTTL:     time.Hour,
+		SessionTTL: 24 * time.Hour,
+	}
+
+	testUser := &user.User{ID: uuid.New(), Name: "John Doe", Email: "john@example.com"}
+	userID := testUser.ID.String()
+	supportRole := &auth.Role{ID: 2, Name: auth.RoleSupport, Permissions: []string{auth.PermissionUsersRead}}
+
+	t.Run("Success", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		mockUserRepo.EXPECT().GetByID(ctx, userID).Return(testUser, nil)
+		mockRoleRepo.EXPECT().GetRolesByUser(ctx, userID).Return([]*auth.Role{supportRole}, nil)
+		mockAuthRepo.EXPECT().
+			StorePersonalAccessToken(ctx, mock.AnythingOfType("*auth.PersonalAccessToken")).
+			Return(nil)
+
+		res, err := service.CreatePersonalAccessToken(ctx, userID, auth.CreatePersonalAccessTokenInput{
+			Name:          "ci",
+			Scopes:        []string{auth.PermissionUsersRead},
+			ExpiresInDays: 30,
+		})
+		require.NoError(t, err)
+		assert.True(t, auth.IsPersonalAccessToken(res.Token))
+		assert.Equal(t, auth.HashPersonalAccessToken(res.Token), res.TokenHash)
+		assert.True(t, res.ExpiresAt.NotNull())
+	})
+
+	t.Run("ScopeNotHeld", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		mockUserRepo.EXPECT().GetByID(ctx, userID).Return(testUser, nil)
+		mockRoleRepo.EXPECT().GetRolesByUser(ctx, userID).Return([]*auth.Role{supportRole}, nil)
+
+		res, err := service.CreatePersonalAccessToken(ctx, userID, auth.CreatePersonalAccessTokenInput{
+			Name:   "ci",
+			Scopes: []string{auth.PermissionUsersWrite},
+		})
+		assert.ErrorIs(t, err, auth.ErrPersonalAccessTokenScope)
+		assert.Nil(t, res)
+	})
+}
+
+func TestService_AuthenticatePersonalAccessToken(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Auth{
+		JwtSecret:  "test-secret",
+		JwtTTL:     time.Hour,
+		SessionTTL: 24 * time.Hour,
+	}
+
+	testUser := &user.User{ID: uuid.New(), Name: "John Doe", Email: "john@example.com"}
+	userID := testUser.ID.String()
+
+	t.Run("Success narrows scopes to current permissions", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		tok, plain, err := auth.NewPersonalAccessToken(
+			testUser.ID,
+			"ci",
+			[]string{auth.PermissionUsersRead, auth.PermissionUsersWrite},
+			0,
+		)
+		require.NoError(t, err)
+
+		mockAuthRepo.EXPECT().GetPersonalAccessTokenByHash(ctx, tok.TokenHash).Return(tok, nil)
+		mockUserRepo.EXPECT().GetByID(ctx, userID).Return(testUser, nil)
+		mockRoleRepo.EXPECT().
+			GetRolesByUser(ctx, userID).
+			Return([]*auth.Role{{Name: auth.RoleSupport, Permissions: []string{auth.PermissionUsersRead}}}, nil)
+		mockAuthRepo.EXPECT().UpdatePersonalAccessToken(ctx, tok).Return(nil)
+
+		claims, err := service.AuthenticatePersonalAccessToken(ctx, plain)
+		require.NoError(t, err)
+		assert.Equal(t, userID, claims.UserID)
+		assert.Equal(t, []string{auth.PermissionUsersRead}, claims.Permissions)
+		assert.Equal(t, []string{auth.AMRPersonalAccessToken}, claims.AMR)
+		assert.True(t, tok.LastUsedAt.NotNull())
+	})
+
+	t.Run("UnknownToken", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		mockAuthRepo.EXPECT().
+			GetPersonalAccessTokenByHash(ctx, auth.HashPersonalAccessToken("pat_unknown")).
+			Return(nil, auth.ErrPersonalAccessTokenNotFound)
+
+		claims, err := service.AuthenticatePersonalAccessToken(ctx, "pat_unknown")
+		assert.ErrorIs(t, err, auth.ErrPersonalAccessTokenInvalid)
+		assert.Nil(t, claims)
+	})
+
+	t.Run("ExpiredToken", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockPublisher := mocks.NewAuthMessagePublisher(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mockPublisher, nil, nil, nil)
+
+		tok, plain, err := auth.NewPersonalAccessToken(testUser.ID, "ci", nil, time.Hour)
+		require.NoError(t, err)
+		tok.ExpiresAt.Set(time.Now().Add(-time.Minute), false)
+
+		mockAuthRepo.EXPECT().GetPersonalAccessTokenByHash(ctx, tok.TokenHash).Return(tok, nil)
+
+		claims, err := service.AuthenticatePersonalAccessToken(ctx, plain)
+		assert.ErrorIs(t, err, auth.ErrPersonalAccessTokenInvalid)
+		assert.Nil(t, claims)
+	})
+}
+
+func TestService_Impersonate(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Auth{
+		JwtSecret:        "test-secret",
+		JwtTTL:           time.Hour,
+		ImpersonationTTL: 10 * time.Minute,
+	}
+
+	adminID := uuid.New()
+	actor := &auth.AccessTokenClaims{UserID: adminID.String()}
+	target := &user.User{
+		ID:    uuid.New(),
+		Name:  "John Doe",
+		Email: "john@example.com",
+	}
+	input := auth.ImpersonateInput{Reason: "Ticket #42"}
+
+	t.Run("Success", func(t *testing.T) {
+		// Setup
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+		mockAuditor := mocks.NewAuditWriter(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mocks.NewTransactor(t), mockUserRepo, mocks.NewAuthRepository(t), mockRoleRepo, nil, mocks.NewAuthMessagePublisher(t), mockAuditor, nil, nil)
+
+		reqCtx := audit.WithActor(ctx, adminID.String())
+
+		// Mock expectations
+		mockRoleRepo.EXPECT().GetRolesByUser(reqCtx, adminID.String()).Return([]*auth.Role{
+			{ID: 1, Name: auth.RoleAdmin, Permissions: []string{auth.PermissionAll}},
+		}, nil)
+		mockUserRepo.EXPECT().GetByID(reqCtx, target.ID.String()).Return(target, nil)
+		mockRoleRepo.EXPECT().GetRolesByUser(reqCtx, target.ID.String()).Return([]*auth.Role{
+			{ID: 2, Name: auth.RoleSupport, Permissions: []string{auth.PermissionUsersRead}},
+		}, nil)
+		mockAuditor.EXPECT().Write(reqCtx, mock.MatchedBy(func(e *audit.Event) bool {
+			return e.Type == audit.EventImpersonationStarted &&
+				e.UserID.UUID == target.ID &&
+				e.ActorID.UUID == adminID &&
+				e.Metadata["reason"] == input.Reason
+		})).Return(nil)
+
+		// Execute
+		result, err := service.Impersonate(reqCtx, actor, target.ID.String(), input)
+
+		// Assert
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(cfg.ImpersonationTTL), result.ExpiresAt, time.Second)
+
+		claims, err := auth.VerifyAccessToken(testKeys, result.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, target.ID.String(), claims.UserID)
+		assert.Equal(t, adminID.String(), claims.ActorID())
+		assert.Empty(t, claims.SessionID)
+		assert.Equal(t, []string{auth.PermissionUsersRead}, claims.Permissions)
+	})
+
+	t.Run("Self", func(t *testing.T) {
+		service := auth.NewService(cfg, testKeys, nil, mocks.NewTransactor(t), mocks.NewUserRepository(t), mocks.NewAuthRepository(t), mocks.NewRoleRepository(t), nil, mocks.NewAuthMessagePublisher(t), nil, nil, nil)
+
+		_, err := service.Impersonate(ctx, actor, adminID.String(), input)
+		assert.Equal(t, auth.ErrImpersonateSelf, err)
+	})
+
+	t.Run("AlreadyImpersonating", func(t *testing.T) {
+		service := auth.NewService(cfg, testKeys, nil, mocks.NewTransactor(t), mocks.NewUserRepository(t), mocks.NewAuthRepository(t), mocks.NewRoleRepository(t), nil, mocks.NewAuthMessagePublisher(t), nil, nil, nil)
+
+		impersonated := &auth.AccessTokenClaims{UserID: uuid.NewString(), Actor: &auth.Actor{UserID: adminID.String()}}
+
+		_, err := service.Impersonate(ctx, impersonated, target.ID.String(), input)
+		assert.Equal(t, auth.ErrImpersonationNotAllowed, err)
+	})
+
+	t.Run("TargetHoldsMorePermissions", func(t *testing.T) {
+		// Setup
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mocks.NewTransactor(t), mockUserRepo, mocks.NewAuthRepository(t), mockRoleRepo, nil, mocks.NewAuthMessagePublisher(t), nil, nil, nil)
+
+		// Mock expectations
+		mockRoleRepo.EXPECT().GetRolesByUser(ctx, adminID.String()).Return([]*auth.Role{
+			{ID: 3, Name: "impersonator", Permissions: []string{auth.PermissionUsersImpersonate, auth.PermissionUsersRead}},
+		}, nil)
+		mockUserRepo.EXPECT().GetByID(ctx, target.ID.String()).Return(target, nil)
+		mockRoleRepo.EXPECT().GetRolesByUser(ctx, target.ID.String()).Return([]*auth.Role{
+			{ID: 1, Name: auth.RoleAdmin, Permissions: []string{auth.PermissionAll}},
+		}, nil)
+
+		// Execute
+		_, err := service.Impersonate(ctx, actor, target.ID.String(), input)
+
+		// Assert
+		assert.Equal(t, auth.ErrImpersonationNotPermitted, err)
+	})
+}
+
+func TestService_StopImpersonation(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Auth{JwtSecret: "test-secret", JwtTTL: time.Hour}
+
+	t.Run("Success", func(t *testing.T) {
+		// Setup
+		mockRevocations := mocks.NewTokenRevocationStore(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mocks.NewTransactor(t), mocks.NewUserRepository(t), mocks.NewAuthRepository(t), mocks.NewRoleRepository(t), nil, mocks.NewAuthMessagePublisher(t), nil, mockRevocations, nil)
+
+		expiresAt := time.Now().Add(5 * time.Minute)
+		claims := &auth.AccessTokenClaims{
+			UserID: uuid.NewString(),
+			Actor:  &auth.Actor{UserID: uuid.NewString()},
+			RegisteredClaims: jwt.RegisteredClaims{
+				ID:        "token-id",
+				ExpiresAt: jwt.NewNumericDate(expiresAt),
+			},
+		}
+
+		// Mock expectations
+		mockRevocations.EXPECT().Revoke(ctx, "token-id", mock.AnythingOfType("time.Time")).Return(nil)
+
+		// Execute
+		err := service.StopImpersonation(ctx, claims)
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("NotImpersonating", func(t *testing.T) {
+		service := auth.NewService(cfg, testKeys, nil, mocks.NewTransactor(t), mocks.NewUserRepository(t), mocks.NewAuthRepository(t), mocks.NewRoleRepository(t), nil, mocks.NewAuthMessagePublisher(t), nil, nil, nil)
+
+		err := service.StopImpersonation(ctx, &auth.AccessTokenClaims{UserID: uuid.NewString()})
+		assert.Equal(t, auth.ErrNotImpersonating, err)
+	})
+}
+
+func TestService_EndExpiredImpersonation(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Auth{JwtSecret: "test-secret", JwtTTL: time.Hour, ImpersonationTTL: 15 * time.Minute}
+
+	actorID := uuid.NewString()
+	impersonated := auth.AccessTokenClaims{
+		UserID: uuid.NewString(),
+		Actor:  &auth.Actor{UserID: actorID},
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID: "token-id",
+		},
+	}
+
+	t.Run("RecordedOnce", func(t *testing.T) {
+		mockRevocations := mocks.NewTokenRevocationStore(t)
+		mockAuditor := mocks.NewAuditWriter(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mocks.NewTransactor(t), mocks.NewUserRepository(t), mocks.NewAuthRepository(t), mocks.NewRoleRepository(t), nil, mocks.NewAuthMessagePublisher(t), mockAuditor, mockRevocations, nil)
+
+		token, err := auth.SignAccessToken(testKeys, impersonated, -time.Minute)
+		require.NoError(t, err)
+
+		mockRevocations.EXPECT().IsRevoked(ctx, []string{"token-id"}).Return(false, nil).Once()
+		mockRevocations.EXPECT().
+			Revoke(ctx, "token-id", mock.MatchedBy(func(expiresAt time.Time) bool {
+				return time.Until(expiresAt) > cfg.ImpersonationTTL-time.Minute
+			})).
+			Return(nil).
+			Once()
+		mockAuditor.EXPECT().
+			Write(mock.Anything, mock.MatchedBy(func(e *audit.Event) bool {
+				return e.Type == audit.EventImpersonationExpired &&
+					e.UserID.UUID.String() == impersonated.UserID &&
+					e.ActorID.UUID.String() == actorID
+			})).
+			Return(nil).
+			Once()
+
+		require.NoError(t, service.EndExpiredImpersonation(ctx, token))
+
+		// Retries with the same token aren't audited again
+		mockRevocations.EXPECT().IsRevoked(ctx, []string{"token-id"}).Return(true, nil).Once()
+		require.NoError(t, service.EndExpiredImpersonation(ctx, token))
+	})
+
+	t.Run("IgnoresOtherTokens", func(t *testing.T) {
+		// Neither the revocation store nor the auditor are expected to be called
+		service := auth.NewService(cfg, testKeys, nil, mocks.NewTransactor(t), mocks.NewUserRepository(t), mocks.NewAuthRepository(t), mocks.NewRoleRepository(t), nil, mocks.NewAuthMessagePublisher(t), mocks.NewAuditWriter(t), mocks.NewTokenRevocationStore(t), nil)
+
+		live, err := auth.SignAccessToken(testKeys, impersonated, time.Minute)
+		require.NoError(t, err)
+		expiredSession, err := auth.SignAccessToken(testKeys, auth.AccessTokenClaims{
+			UserID:    uuid.NewString(),
+			SessionID: "session-id",
+		}, -time.Minute)
+		require.NoError(t, err)
+
+		for _, token := range []string{live, expiredSession, "not-a-token"} {
+			assert.NoError(t, service.EndExpiredImpersonation(ctx, token))
+		}
+	})
+}
+
+func TestService_Reauthenticate(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Auth{
+		JwtSecret:  "test-secret",
+		JwtTTL:     time.Hour,
+		SessionTTL: 24 * time.Hour,
+	}
+
+	userID := uuid.New()
+	hashedPassword, err := auth.HashPassword("password123")
+	require.NoError(t, err)
+	testUser := &user.User{ID: userID, Name: "John Doe", Email: "john@example.com", Password: string(hashedPassword)}
+
+	t.Run("Success", func(t *testing.T) {
+		// Setup
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+		mockRoleRepo := mocks.NewRoleRepository(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mockRoleRepo, nil, mocks.NewAuthMessagePublisher(t), nil, nil, nil)
+
+		session, err := auth.NewSession(userID, "test-agent", cfg.SessionTTL, auth.AMRPassword)
+		require.NoError(t, err)
+		session.AuthenticatedAt = time.Now().Add(-time.Hour)
+
+		input := auth.ReauthenticateInput{
+			Password:  "password123",
+			SessionID: session.FamilyID.String(),
+		}
+
+		// Mock expectations
+		mockUserRepo.EXPECT().GetByID(ctx, userID.String()).Return(testUser, nil)
+		mockTransactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockAuthRepo.EXPECT().ListSessionsByUser(ctx, userID.String()).Return([]*auth.Session{session}, nil)
+		mockAuthRepo.EXPECT().UpdateSession(ctx, mock.MatchedBy(func(s *auth.Session) bool {
+			return time.Since(s.AuthenticatedAt) < time.Minute
+		})).Return(nil)
+		mockRoleRepo.EXPECT().GetRolesByUser(ctx, userID.String()).Return([]*auth.Role{}, nil)
+
+		// Execute
+		accessToken, err := service.Reauthenticate(ctx, userID.String(), input)
+
+		// Assert
+		require.NoError(t, err)
+		claims, err := auth.VerifyAccessToken(testKeys, accessToken)
+		require.NoError(t, err)
+		assert.True(t, claims.AuthenticatedWithin(time.Minute))
+	})
+
+	t.Run("WrongPassword", func(t *testing.T) {
+		// Setup
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuditor := mocks.NewAuditWriter(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mocks.NewAuthRepository(t), mocks.NewRoleRepository(t), nil, mocks.NewAuthMessagePublisher(t), mockAuditor, nil, nil)
+
+		input := auth.ReauthenticateInput{
+			Password:  "wrongpassword",
+			SessionID: uuid.New().String(),
+		}
+
+		// Mock expectations
+		mockUserRepo.EXPECT().GetByID(ctx, userID.String()).Return(testUser, nil)
+		mockTransactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockAuditor.EXPECT().Write(ctx, mock.MatchedBy(func(e *audit.Event) bool {
+			return e.Type == audit.EventReauthenticationFailed && e.UserID.UUID == userID
+		})).Return(nil)
+
+		// Execute
+		accessToken, err := service.Reauthenticate(ctx, userID.String(), input)
+
+		// Assert
+		assert.Equal(t, auth.ErrWrongCredentials, err)
+		assert.Empty(t, accessToken)
+	})
+
+	t.Run("InvalidCode", func(t *testing.T) {
+		// Setup
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockAuthRepo := mocks.NewAuthRepository(t)
+
+		service := auth.NewService(cfg, testKeys, nil, mockTransactor, mockUserRepo, mockAuthRepo, mocks.NewRoleRepository(t), nil, mocks.NewAuthMessagePublisher(t), nil, nil, nil)
+
+		input := auth.ReauthenticateInput{
+			Code:      "123456",
+			SessionID: uuid.New().String(),
+		}
+
+		// Mock expectations
+		mockUserRepo.EXPECT().GetByID(ctx, userID.String()).Return(testUser, nil)
+		mockTransactor.EXPECT().Transact(ctx, mock.AnythingOfType("func(context.Context) error")).RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+		mockAuthRepo.EXPECT().GetTOTPFactor(ctx, userID.String()).Return(nil, auth.ErrTOTPFactorNotFound)
+
+		// Execute
+		_, err := service.Reauthenticate(ctx, userID.String(), input)
+
+		// Assert
+		assert.Equal(t, auth.ErrMFACodeInvalid, err)
 	})
 }
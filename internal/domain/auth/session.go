@@ -11,11 +11,13 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/prawirdani/golang-restapi/internal/domain"
+	"github.com/prawirdani/golang-restapi/pkg/nullable"
 )
 
 var (
 	ErrSessionExpired    = domain.ErrForbidden("Session expired")
 	ErrSessionNotFound   = domain.ErrNotFound("Session not found, please login to proceed")
+	ErrSessionReused     = domain.ErrUnauthorized("Refresh token has already been used, please login again")
 	ErrSessionEmptyUID   = errors.New("user_id must not be empty")
 	ErrSessionInvalidTTL = errors.New("session ttl must be greater than 0")
 )
@@ -31,19 +33,25 @@ var (
 //
 // Security Features:
 //   - Server-side revocation (logout invalidates session immediately)
-//   - User agent tracking (detect token theft across devices)
+//   - User agent tracking (refreshes from another browser or OS are flagged, or rejected, as token theft)
 //   - UUID v7 for session IDs (time-ordered for better DB performance)
+//   - Refresh token rotation (every refresh replaces the session ID within the same family)
+//   - Reuse detection (replaying a rotated session ID revokes the entire family)
 type Session struct {
 	// ID is the session identifier used as the refresh token.
 	// This UUID v7 is exposed to clients via httpOnly cookies and used
 	// to request new access tokens from refresh token endpoint.
 	ID uuid.UUID `db:"id"`
 
+	// FamilyID groups every session rotated from the same login.
+	// It equals the ID of the first session in the family.
+	FamilyID uuid.UUID `db:"family_id"`
+
 	// UserID is the user who owns this session.
 	UserID uuid.UUID `db:"user_id"`
 
 	// UserAgent stores the client's User-Agent header for security tracking.
-	// Used to detect session hijacking when requests come from different devices.
+	// Compared on every refresh, ignoring version numbers, to detect the session being used from another device.
 	UserAgent string `db:"user_agent"`
 
 	// ExpiresAt is when this refresh token expires.
@@ -53,6 +61,22 @@ type Session struct {
 	// AccessedAt tracks the last time this session was used to refresh an access token.
 	// Updated on each successful refresh request for activity monitoring.
 	AccessedAt time.Time `db:"accessed_at"`
+
+	// AMR lists the authentication methods used when the session was created.
+	// Carried over to every access token refreshed from this session.
+	AMR []string `db:"amr"`
+
+	// RotatedAt is when this session was replaced by its successor in the family.
+	// A rotated session can no longer be used, presenting it again is treated as token theft.
+	RotatedAt nullable.Nullable[time.Time] `db:"rotated_at"`
+
+	// OrganizationID is the organization the user switched to within this session, if any.
+	// Carried over to every access token refreshed from this session as the org_id claim.
+	OrganizationID uuid.NullUUID `db:"organization_id"`
+
+	// AuthenticatedAt is the last time the user proved their credentials, at login or when re-authenticating.
+	// Carried over to every access token refreshed from this session as the auth_time claim.
+	AuthenticatedAt time.Time `db:"authenticated_at"`
 }
 
 // NewSession creates a new session for the given user.
@@ -61,6 +85,7 @@ func NewSession(
 	userID uuid.UUID,
 	userAgent string,
 	ttl time.Duration,
+	amr ...string,
 ) (*Session, error) {
 	if ttl <= 0 {
 		return nil, ErrSessionInvalidTTL
@@ -76,11 +101,14 @@ func NewSession(
 
 	now := time.Now()
 	sess := Session{
-		ID:         sessID,
-		UserID:     userID,
-		UserAgent:  userAgent,
-		ExpiresAt:  now.Add(ttl),
-		AccessedAt: now,
+		ID:              sessID,
+		FamilyID:        sessID,
+		UserID:          userID,
+		UserAgent:       userAgent,
+		ExpiresAt:       now.Add(ttl),
+		AccessedAt:      now,
+		AMR:             amr,
+		AuthenticatedAt: now,
 	}
 	return &sess, nil
 }
@@ -98,3 +126,54 @@ func (s Session) IsExpired() bool {
 func (s *Session) Revoke() {
 	s.ExpiresAt = time.Now()
 }
+
+// IsRotated checks if the session has already been replaced by a newer session in its family.
+func (s Session) IsRotated() bool {
+	return s.RotatedAt.NotNull()
+}
+
+// Rotate marks the session as rotated and returns its successor, which carries a new ID within
+// the same family and a renewed expiration.
+func (s *Session) Rotate(ttl time.Duration) (*Session, error) {
+	next, err := NewSession(s.UserID, s.UserAgent, ttl, s.AMR...)
+	if err != nil {
+		return nil, err
+	}
+	next.FamilyID = s.FamilyID
+	next.OrganizationID = s.OrganizationID
+	next.AuthenticatedAt = s.AuthenticatedAt
+
+	now := time.Now()
+	s.RotatedAt = nullable.New(now, false)
+	s.AccessedAt = now
+
+	return next, nil
+}
+
+// SessionInfo is the client-facing view of a login session, one per session family.
+// It never exposes the session ID itself, since that is the refresh token.
+type SessionInfo struct {
+	ID         string    `json:"id"` // The session family ID
+	UserAgent  string    `json:"user_agent"`
+	AMR        []string  `json:"amr"`
+	CreatedAt  time.Time `json:"created_at"`
+	AccessedAt time.Time `json:"accessed_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"` // Whether the request was made from this session
+}
+
+// Info returns the client-facing view of the session. The creation time is derived from the
+// family ID, which is the UUID v7 of the first session of the login.
+func (s Session) Info(currentFamilyID string) SessionInfo {
+	sec, nsec := s.FamilyID.Time().UnixTime()
+
+	return SessionInfo{
+		ID:         s.FamilyID.String(),
+		UserAgent:  s.UserAgent,
+		AMR:        s.AMR,
+		CreatedAt:  time.Unix(sec, nsec),
+		AccessedAt: s.AccessedAt,
+		ExpiresAt:  s.ExpiresAt,
+		Current:    s.FamilyID.String() == currentFamilyID,
+	}
+}
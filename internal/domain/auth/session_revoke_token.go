@@ -0,0 +1,66 @@
+// Package auth provides authentication and authorization functionality.
+// This package handles user authentication through sessions, access tokens, and
+// password management including secure hashing and password reset flows. It manages
+// the complete authentication lifecycle from login through logout, including token
+// generation, validation, and session management.
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prawirdani/golang-restapi/internal/domain"
+	"github.com/prawirdani/golang-restapi/pkg/nullable"
+)
+
+var (
+	// ErrSessionRevokeTokenInvalid is returned when the session revoke token is invalid or expired.
+	ErrSessionRevokeTokenInvalid = domain.ErrForbidden("The link is invalid or expired")
+
+	// ErrSessionRevokeTokenNotFound is returned when no matching session revoke token exists.
+	ErrSessionRevokeTokenNotFound = domain.ErrNotFound("Session revoke token not found")
+)
+
+// SessionRevokeToken represents a one-time token mailed along with a new device login notification, letting the
+// user revoke the session of a login they don't recognize.
+type SessionRevokeToken struct {
+	UserID    uuid.UUID                    `db:"user_id"    json:"user_id"`
+	FamilyID  uuid.UUID                    `db:"family_id"  json:"family_id"`
+	DeviceID  uuid.UUID                    `db:"device_id"  json:"device_id"`
+	Value     string                       `db:"value"      json:"value"`
+	ExpiresAt time.Time                    `db:"expires_at" json:"expires_at"`
+	UsedAt    nullable.Nullable[time.Time] `db:"used_at"    json:"used_at"`
+}
+
+// NewSessionRevokeToken creates a new token revoking the session family logged in from the device.
+func NewSessionRevokeToken(sess *Session, device *KnownDevice, ttl time.Duration) (*SessionRevokeToken, error) {
+	bs := make([]byte, 32)
+	if _, err := rand.Read(bs); err != nil {
+		return nil, err
+	}
+
+	return &SessionRevokeToken{
+		UserID:    sess.UserID,
+		FamilyID:  sess.FamilyID,
+		DeviceID:  device.ID,
+		Value:     hex.EncodeToString(bs),
+		ExpiresAt: time.Now().Add(ttl),
+	}, nil
+}
+
+// Expired reports whether the token has passed its expiration time.
+func (t SessionRevokeToken) Expired() bool {
+	return t.ExpiresAt.Before(time.Now())
+}
+
+// Used reports whether the token has already been used.
+func (t SessionRevokeToken) Used() bool {
+	return t.UsedAt.NotNull()
+}
+
+// Revoke marks the token as used immediately.
+func (t *SessionRevokeToken) Revoke() {
+	t.UsedAt = nullable.New(time.Now(), false)
+}
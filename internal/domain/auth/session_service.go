@@ -0,0 +1,295 @@
+// Package auth provides authentication and authorization functionality.
+// This package handles user authentication through sessions, access tokens, and
+// password management including secure hashing and password reset flows. It manages
+// the complete authentication lifecycle from login through logout, including token
+// generation, validation, and session management.
+package auth
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prawirdani/golang-restapi/internal/domain/audit"
+	"github.com/prawirdani/golang-restapi/internal/domain/user"
+)
+
+// ListSessions returns the active sessions of the user, marking the one identified by currentSessionID,
+// the session family ID carried in the caller's access token.
+func (s *Service) ListSessions(
+	ctx context.Context,
+	userID string,
+	currentSessionID string,
+) ([]SessionInfo, error) {
+	sessions, err := s.authRepo.ListSessionsByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]SessionInfo, 0, len(sessions))
+	for _, sess := range sessions {
+		infos = append(infos, sess.Info(currentSessionID))
+	}
+
+	return infos, nil
+}
+
+// RevokeSession revokes one of the user's sessions, identified by its session family ID.
+func (s *Service) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	sessions, err := s.authRepo.ListSessionsByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, sess := range sessions {
+		if sess.FamilyID.String() == sessionID {
+			if err := s.revokeSessionFamily(ctx, sessionID); err != nil {
+				return err
+			}
+
+			return s.recordEvent(ctx, audit.EventSessionRevoked, userID, audit.Metadata{"session_id": sessionID})
+		}
+	}
+
+	return ErrSessionNotFound
+}
+
+// RevokeOtherSessions revokes every session of the user except the current one.
+func (s *Service) RevokeOtherSessions(ctx context.Context, userID, currentSessionID string) error {
+	if err := s.revokeUserSessions(ctx, userID, currentSessionID); err != nil {
+		return err
+	}
+
+	return s.recordEvent(ctx, audit.EventOtherSessionsRevoked, userID, audit.Metadata{
+		"current_session_id": currentSessionID,
+	})
+}
+
+// SwitchOrganization makes the organization the active one of the session, identified by its family ID, and
+// returns a fresh access token carrying it as the org_id claim. Tokens refreshed from the session keep carrying it.
+// The caller must have checked the user's membership, an empty orgID leaves no organization active.
+func (s *Service) SwitchOrganization(ctx context.Context, userID, sessionID, orgID string) (string, error) {
+	var org uuid.NullUUID
+	if orgID != "" {
+		id, err := uuid.Parse(orgID)
+		if err != nil {
+			return "", err
+		}
+		org = uuid.NullUUID{UUID: id, Valid: true}
+	}
+
+	usr, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.checkLoginAllowed(usr); err != nil {
+		return "", err
+	}
+
+	var accessToken string
+	err = s.transactor.Transact(ctx, func(ctx context.Context) error {
+		sess, err := s.familySession(ctx, userID, sessionID)
+		if err != nil {
+			return err
+		}
+
+		sess.OrganizationID = org
+		if err := s.authRepo.UpdateSession(ctx, sess); err != nil {
+			return err
+		}
+
+		if err := s.recordEvent(ctx, audit.EventOrganizationSwitched, userID, audit.Metadata{
+			"session_id":      sessionID,
+			"organization_id": orgID,
+		}); err != nil {
+			return err
+		}
+
+		accessToken, err = s.generateAccessToken(ctx, *usr, sess)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return accessToken, nil
+}
+
+// Reauthenticate has the user prove their credentials again, with their password, a second factor or a passkey, to
+// perform sensitive operations. The authentication time of the session, identified by its family ID, is refreshed and
+// returned in the auth_time claim of a fresh access token. Failures count against the account like failed logins.
+func (s *Service) Reauthenticate(ctx context.Context, userID string, inp ReauthenticateInput) (string, error) {
+	usr, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.checkLoginAllowed(usr); err != nil {
+		return "", err
+	}
+
+	if err := s.checkAccountThrottle(ctx, userID); err != nil {
+		return "", err
+	}
+
+	var accessToken string
+	err = s.transactor.Transact(ctx, func(ctx context.Context) error {
+		method, err := s.verifyReauthentication(ctx, usr, inp)
+		if err != nil {
+			return err
+		}
+
+		if method == "" {
+			// Commit the failure event, the failure is counted once the transaction is over
+			return s.recordEvent(ctx, audit.EventReauthenticationFailed, userID, audit.Metadata{
+				"session_id": inp.SessionID,
+			})
+		}
+
+		sess, err := s.familySession(ctx, userID, inp.SessionID)
+		if err != nil {
+			return err
+		}
+
+		sess.AuthenticatedAt = time.Now()
+		if err := s.authRepo.UpdateSession(ctx, sess); err != nil {
+			return err
+		}
+
+		if err := s.recordEvent(ctx, audit.EventReauthenticated, userID, audit.Metadata{
+			"session_id": inp.SessionID,
+			"amr":        method,
+		}); err != nil {
+			return err
+		}
+
+		accessToken, err = s.generateAccessToken(ctx, *usr, sess)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if accessToken == "" {
+		if err := s.recordLoginFailure(ctx, usr, inp.IPAddress); err != nil {
+			return "", err
+		}
+		if inp.Password != "" {
+			return "", ErrWrongCredentials
+		}
+		return "", ErrMFACodeInvalid
+	}
+
+	if err := s.resetAccountFailures(ctx, userID); err != nil {
+		return "", err
+	}
+
+	return accessToken, nil
+}
+
+// verifyReauthentication checks the password, second factor or passkey of the input for the user. Returns the AMR
+// value of the verified method, or an empty string when verification failed. Must be called inside a transaction.
+func (s *Service) verifyReauthentication(ctx context.Context, usr *user.User, inp ReauthenticateInput) (string, error) {
+	if inp.Password == "" {
+		return s.verifySecondFactor(ctx, usr.ID.String(), VerifyMFAInput{
+			Code:         inp.Code,
+			RecoveryCode: inp.RecoveryCode,
+			ChallengeID:  inp.ChallengeID,
+			Credential:   inp.Credential,
+		})
+	}
+
+	// Accounts without a password, e.g. created from an external identity, can only use a second factor or a passkey
+	if usr.Password == "" {
+		return "", nil
+	}
+
+	if _, err := s.passwords.Verify(inp.Password, usr.Password); err != nil {
+		if err == ErrWrongCredentials {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return AMRPassword, nil
+}
+
+// familySession returns the current session of the family, [ErrSessionNotFound] unless it is an active session of
+// the user.
+func (s *Service) familySession(ctx context.Context, userID, familyID string) (*Session, error) {
+	sessions, err := s.authRepo.ListSessionsByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	idx := slices.IndexFunc(sessions, func(sess *Session) bool {
+		return sess.FamilyID.String() == familyID
+	})
+	if idx < 0 {
+		return nil, ErrSessionNotFound
+	}
+
+	return sessions[idx], nil
+}
+
+// revokeSessionFamily revokes every session of the family along with the access tokens issued from them.
+func (s *Service) revokeSessionFamily(ctx context.Context, familyID string) error {
+	if err := s.authRepo.RevokeSessionFamily(ctx, familyID); err != nil {
+		return err
+	}
+
+	return s.revokeAccessTokens(ctx, familyID)
+}
+
+// revokeUserSessions revokes every session of the user except the sessions of the given family, along with
+// the access tokens issued from them. An empty exceptFamilyID revokes all sessions.
+func (s *Service) revokeUserSessions(ctx context.Context, userID, exceptFamilyID string) error {
+	var familyIDs []string
+	if s.revocations != nil {
+		sessions, err := s.authRepo.ListSessionsByUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		for _, sess := range sessions {
+			if id := sess.FamilyID.String(); id != exceptFamilyID {
+				familyIDs = append(familyIDs, id)
+			}
+		}
+	}
+
+	if err := s.authRepo.RevokeSessionsByUser(ctx, userID, exceptFamilyID); err != nil {
+		return err
+	}
+
+	return s.revokeAccessTokens(ctx, familyIDs...)
+}
+
+// revokeOAuthGrants revokes the refresh tokens and authorization codes the user granted to OAuth clients. Access
+// tokens already issued to clients aren't accepted by this API and expire on their own.
+func (s *Service) revokeOAuthGrants(ctx context.Context, userID string) error {
+	if s.oauthRepo == nil {
+		return nil
+	}
+
+	return s.oauthRepo.RevokeOAuthGrantsByUser(ctx, userID)
+}
+
+// revokeAccessTokens denylists the access tokens issued from the session families. A token issued right now
+// expires after JwtTTL at the latest, so the entries are kept just as long.
+func (s *Service) revokeAccessTokens(ctx context.Context, familyIDs ...string) error {
+	if s.revocations == nil {
+		return nil
+	}
+
+	expiresAt := time.Now().Add(s.cfg.JwtTTL)
+	for _, id := range familyIDs {
+		if err := s.revocations.Revoke(ctx, id, expiresAt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
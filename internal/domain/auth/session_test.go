@@ -18,6 +18,7 @@ func TestNewSession(t *testing.T) {
 	require.NoError(t, err)
 
 	require.NotEqual(t, uuid.Nil, session.ID)
+	assert.Equal(t, session.ID, session.FamilyID)
 	assert.Equal(t, mockUserID, session.UserID)
 	assert.Equal(t, mockUserAgent, session.UserAgent)
 	assert.WithinDuration(t, time.Now().Add(mockExpiry), session.ExpiresAt, 1*time.Second)
@@ -42,3 +43,40 @@ func TestNewSession(t *testing.T) {
 		assert.True(t, session.IsExpired())
 	})
 }
+
+func TestSessionRotate(t *testing.T) {
+	session, err := NewSession(uuid.New(), "user-agent", time.Hour, AMRPassword)
+	require.NoError(t, err)
+	session.OrganizationID = uuid.NullUUID{UUID: uuid.New(), Valid: true}
+	session.AuthenticatedAt = time.Now().Add(-time.Hour)
+	assert.False(t, session.IsRotated())
+
+	next, err := session.Rotate(2 * time.Hour)
+	require.NoError(t, err)
+
+	assert.True(t, session.IsRotated())
+	assert.False(t, next.IsRotated())
+	assert.NotEqual(t, session.ID, next.ID)
+	assert.Equal(t, session.FamilyID, next.FamilyID)
+	assert.Equal(t, session.UserID, next.UserID)
+	assert.Equal(t, session.UserAgent, next.UserAgent)
+	assert.Equal(t, session.AMR, next.AMR)
+	assert.Equal(t, session.OrganizationID, next.OrganizationID)
+	assert.Equal(t, session.AuthenticatedAt, next.AuthenticatedAt)
+	assert.WithinDuration(t, time.Now().Add(2*time.Hour), next.ExpiresAt, time.Second)
+}
+
+func TestSessionInfo(t *testing.T) {
+	session, err := NewSession(uuid.New(), "user-agent", time.Hour, AMRPassword)
+	require.NoError(t, err)
+
+	next, err := session.Rotate(time.Hour)
+	require.NoError(t, err)
+
+	info := next.Info(session.FamilyID.String())
+	assert.Equal(t, session.FamilyID.String(), info.ID)
+	assert.True(t, info.Current)
+	assert.WithinDuration(t, time.Now(), info.CreatedAt, time.Second)
+
+	assert.False(t, next.Info(uuid.NewString()).Current)
+}
@@ -0,0 +1,184 @@
+// Package auth provides authentication and authorization functionality.
+// This package handles user authentication through sessions, access tokens, and
+// password management including secure hashing and password reset flows. It manages
+// the complete authentication lifecycle from login through logout, including token
+// generation, validation, and session management.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prawirdani/golang-restapi/internal/domain"
+	"github.com/prawirdani/golang-restapi/pkg/nullable"
+)
+
+// AMRHardwareKey is recorded in the amr claim of sessions authenticated with a WebAuthn credential,
+// as a proof-of-possession of a key held by an authenticator.
+const AMRHardwareKey = "hwk"
+
+// Second factors reported in [MFAChallenge.Methods].
+const (
+	MFAMethodTOTP     = "totp"
+	MFAMethodWebAuthn = "webauthn"
+)
+
+// WebAuthn ceremonies a [WebAuthnChallenge] can be completed for.
+const (
+	WebAuthnCeremonyRegistration = "registration"
+	WebAuthnCeremonyLogin        = "login" // Passwordless login with a discoverable credential
+	WebAuthnCeremonyMFA          = "mfa"   // Second factor of a login started by another method, or a re-authentication
+)
+
+const (
+	defaultWebAuthnChallengeTTL = 5 * time.Minute
+	defaultPasskeyName          = "Passkey"
+)
+
+var (
+	ErrWebAuthnDisabled           = domain.ErrNotFound("Passkeys are not enabled")
+	ErrWebAuthnCredentialNotFound = domain.ErrNotFound("Passkey not found")
+	ErrWebAuthnCredentialInvalid  = domain.ErrUnauthorized("The passkey could not be verified")
+	ErrWebAuthnChallengeNotFound  = domain.ErrNotFound("WebAuthn challenge not found")
+	ErrWebAuthnChallengeInvalid   = domain.ErrUnauthorized(
+		"The passkey challenge is invalid or expired, please try again",
+	)
+)
+
+// WebAuthnRelyingParty performs the WebAuthn ceremonies, verifying what the browser returns from
+// navigator.credentials.create() and navigator.credentials.get().
+type WebAuthnRelyingParty interface {
+	// BeginRegistration creates the options to register a new credential for the account. The account's
+	// existing credentials are excluded, so an authenticator can't be registered twice.
+	BeginRegistration(ctx context.Context, account *WebAuthnAccount) (*WebAuthnCeremony, error)
+
+	// FinishRegistration verifies the attestation response against the state of the ceremony and returns
+	// the new credential. Returns [ErrWebAuthnCredentialInvalid] if the response is rejected.
+	FinishRegistration(
+		ctx context.Context,
+		account *WebAuthnAccount,
+		state, response []byte,
+	) (*WebAuthnCredential, error)
+
+	// BeginLogin creates the options to assert one of the account's credentials. A nil account starts a
+	// passwordless login, where the authenticator picks a discoverable credential and user verification is required.
+	BeginLogin(ctx context.Context, account *WebAuthnAccount) (*WebAuthnCeremony, error)
+
+	// FinishLogin verifies the assertion response against the state of the ceremony. The owner of the credential
+	// is resolved through lookup, the returned credential carries the updated sign count and backup state.
+	// Returns [ErrWebAuthnCredentialInvalid] if the response is rejected or the authenticator looks cloned.
+	FinishLogin(
+		ctx context.Context,
+		state, response []byte,
+		lookup WebAuthnAccountLookup,
+	) (*WebAuthnAccount, *WebAuthnCredential, error)
+}
+
+// WebAuthnAccountLookup resolves the account owning a credential from the user handle sent by the authenticator.
+type WebAuthnAccountLookup func(userID uuid.UUID) (*WebAuthnAccount, error)
+
+// WebAuthnAccount is the user a ceremony is performed for. The user ID doubles as the WebAuthn user handle,
+// it carries no personal information.
+type WebAuthnAccount struct {
+	UserID      uuid.UUID
+	Name        string
+	DisplayName string
+	Credentials []*WebAuthnCredential
+}
+
+// WebAuthnCeremony holds the options passed to the browser, and the state needed to verify its response.
+type WebAuthnCeremony struct {
+	Options json.RawMessage
+	State   []byte
+}
+
+// WebAuthnCredential is a public key credential registered by a user, either a synced passkey or a hardware
+// security key. Credentials serve both as a passwordless login and as a second factor.
+type WebAuthnCredential struct {
+	ID              string                       `db:"id"               json:"id"` // Base64url credential ID
+	UserID          uuid.UUID                    `db:"user_id"          json:"-"`
+	Name            string                       `db:"name"             json:"name"`
+	PublicKey       []byte                       `db:"public_key"       json:"-"`
+	AttestationType string                       `db:"attestation_type" json:"-"`
+	Transports      []string                     `db:"transports"       json:"transports"`
+	AAGUID          uuid.UUID                    `db:"aaguid"           json:"aaguid"` // Identifies the authenticator model
+	SignCount       uint32                       `db:"sign_count"       json:"-"`
+	BackupEligible  bool                         `db:"backup_eligible"  json:"backup_eligible"`
+	BackupState     bool                         `db:"backup_state"     json:"backed_up"`
+	LastUsedAt      nullable.Nullable[time.Time] `db:"last_used_at"     json:"last_used_at"`
+	CreatedAt       time.Time                    `db:"created_at"       json:"created_at"`
+}
+
+// MarkUsed records that the credential has just been used.
+func (c *WebAuthnCredential) MarkUsed() {
+	c.LastUsedAt = nullable.New(time.Now(), false)
+}
+
+// WebAuthnChallenge keeps the state of a ceremony between its begin and finish requests.
+type WebAuthnChallenge struct {
+	Value     string                       `db:"value"      json:"challenge_id"`
+	UserID    uuid.NullUUID                `db:"user_id"    json:"-"` // Unset for passwordless logins
+	Ceremony  string                       `db:"ceremony"   json:"-"`
+	State     []byte                       `db:"state"      json:"-"`
+	ExpiresAt time.Time                    `db:"expires_at" json:"expires_at"`
+	UsedAt    nullable.Nullable[time.Time] `db:"used_at"    json:"-"`
+}
+
+// NewWebAuthnChallenge creates a challenge keeping the state of a ceremony, with a specified expiration.
+// A nil userID creates the challenge of a passwordless login, whose user is only known once it completes.
+func NewWebAuthnChallenge(
+	userID *uuid.UUID,
+	ceremony string,
+	state []byte,
+	ttl time.Duration,
+) (*WebAuthnChallenge, error) {
+	bs := make([]byte, 32)
+	if _, err := rand.Read(bs); err != nil {
+		return nil, err
+	}
+
+	c := &WebAuthnChallenge{
+		Value:     hex.EncodeToString(bs),
+		Ceremony:  ceremony,
+		State:     state,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if userID != nil {
+		c.UserID = uuid.NullUUID{UUID: *userID, Valid: true}
+	}
+
+	return c, nil
+}
+
+// Expired reports whether the challenge has passed its expiration time.
+func (c WebAuthnChallenge) Expired() bool {
+	return c.ExpiresAt.Before(time.Now())
+}
+
+// Used reports whether the challenge has already been completed.
+func (c WebAuthnChallenge) Used() bool {
+	return c.UsedAt.NotNull()
+}
+
+// Revoke marks the challenge as used immediately.
+func (c *WebAuthnChallenge) Revoke() {
+	c.UsedAt = nullable.New(time.Now(), false)
+}
+
+// Valid reports whether the challenge can still complete the given ceremony on behalf of userID.
+// An empty userID matches only passwordless login challenges.
+func (c WebAuthnChallenge) Valid(ceremony, userID string) bool {
+	if c.Expired() || c.Used() || c.Ceremony != ceremony {
+		return false
+	}
+
+	if !c.UserID.Valid {
+		return userID == ""
+	}
+
+	return c.UserID.UUID.String() == userID
+}
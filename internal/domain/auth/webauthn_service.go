@@ -0,0 +1,414 @@
+// Package auth provides authentication and authorization functionality.
+// This package handles user authentication through sessions, access tokens, and
+// password management including secure hashing and password reset flows. It manages
+// the complete authentication lifecycle from login through logout, including token
+// generation, validation, and session management.
+package auth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/prawirdani/golang-restapi/internal/domain/audit"
+	"github.com/prawirdani/golang-restapi/internal/domain/user"
+	"github.com/prawirdani/golang-restapi/pkg/log"
+)
+
+// BeginPasskeyRegistration starts the registration of a new passkey for the user.
+// The credential created by the browser is stored through [Service.FinishPasskeyRegistration].
+func (s *Service) BeginPasskeyRegistration(ctx context.Context, userID string) (*WebAuthnOptions, error) {
+	if s.webauthn == nil {
+		return nil, ErrWebAuthnDisabled
+	}
+
+	usr, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.webAuthnAccount(ctx, usr)
+	if err != nil {
+		return nil, err
+	}
+
+	ceremony, err := s.webauthn.BeginRegistration(ctx, account)
+	if err != nil {
+		log.ErrorCtx(ctx, "Failed to begin webauthn registration", err)
+		return nil, err
+	}
+
+	return s.storeWebAuthnChallenge(ctx, &usr.ID, WebAuthnCeremonyRegistration, ceremony)
+}
+
+// FinishPasskeyRegistration verifies and stores the credential created by the browser. Registering the user's first
+// second factor also issues recovery codes, so losing the passkey doesn't lock the user out.
+func (s *Service) FinishPasskeyRegistration(
+	ctx context.Context,
+	userID string,
+	inp FinishPasskeyRegistrationInput,
+) (*RegisteredPasskey, error) {
+	if s.webauthn == nil {
+		return nil, ErrWebAuthnDisabled
+	}
+
+	var result *RegisteredPasskey
+	err := s.transactor.Transact(ctx, func(ctx context.Context) error {
+		challenge, err := s.consumeWebAuthnChallenge(ctx, inp.ChallengeID, WebAuthnCeremonyRegistration, userID)
+		if err != nil {
+			return err
+		}
+
+		usr, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		methods, err := s.mfaMethods(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		account, err := s.webAuthnAccount(ctx, usr)
+		if err != nil {
+			return err
+		}
+
+		credential, err := s.webauthn.FinishRegistration(ctx, account, challenge.State, inp.Credential)
+		if err != nil {
+			return err
+		}
+
+		credential.UserID = usr.ID
+		credential.Name = inp.Name
+		if credential.Name == "" {
+			credential.Name = defaultPasskeyName
+		}
+
+		if err := s.authRepo.StoreWebAuthnCredential(ctx, credential); err != nil {
+			return err
+		}
+
+		result = &RegisteredPasskey{WebAuthnCredential: credential}
+		if len(methods) == 0 {
+			result.RecoveryCodes, err = s.replaceRecoveryCodes(ctx, usr.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		return s.recordEvent(ctx, audit.EventPasskeyRegistered, userID, audit.Metadata{
+			"credential_id": credential.ID,
+			"name":          credential.Name,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ListPasskeys returns the passkeys registered by the user.
+func (s *Service) ListPasskeys(ctx context.Context, userID string) ([]*WebAuthnCredential, error) {
+	return s.authRepo.ListWebAuthnCredentialsByUser(ctx, userID)
+}
+
+// DeletePasskey removes a passkey of the user. Removing the last second factor also removes the recovery codes.
+func (s *Service) DeletePasskey(ctx context.Context, userID, credentialID string) error {
+	return s.transactor.Transact(ctx, func(ctx context.Context) error {
+		if err := s.authRepo.DeleteWebAuthnCredential(ctx, userID, credentialID); err != nil {
+			return err
+		}
+
+		if err := s.recordEvent(ctx, audit.EventPasskeyDeleted, userID, audit.Metadata{
+			"credential_id": credentialID,
+		}); err != nil {
+			return err
+		}
+
+		methods, err := s.mfaMethods(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		if len(methods) > 0 {
+			return nil
+		}
+
+		return s.authRepo.DeleteRecoveryCodes(ctx, userID)
+	})
+}
+
+// BeginPasskeyLogin starts a passwordless login, letting the browser offer the passkeys it holds for the site.
+func (s *Service) BeginPasskeyLogin(ctx context.Context) (*WebAuthnOptions, error) {
+	if s.webauthn == nil {
+		return nil, ErrWebAuthnDisabled
+	}
+
+	ceremony, err := s.webauthn.BeginLogin(ctx, nil)
+	if err != nil {
+		log.ErrorCtx(ctx, "Failed to begin webauthn login", err)
+		return nil, err
+	}
+
+	return s.storeWebAuthnChallenge(ctx, nil, WebAuthnCeremonyLogin, ceremony)
+}
+
+// FinishPasskeyLogin logs the user owning the asserted passkey in. Passkey logins require user verification, so
+// the passkey counts as both factors and no MFA challenge is issued.
+func (s *Service) FinishPasskeyLogin(ctx context.Context, inp FinishPasskeyLoginInput) (*LoginResult, error) {
+	if s.webauthn == nil {
+		return nil, ErrWebAuthnDisabled
+	}
+
+	var result *LoginResult
+	err := s.transactor.Transact(ctx, func(ctx context.Context) error {
+		challenge, err := s.consumeWebAuthnChallenge(ctx, inp.ChallengeID, WebAuthnCeremonyLogin, "")
+		if err != nil {
+			return err
+		}
+
+		var usr *user.User
+		lookup := func(userID uuid.UUID) (*WebAuthnAccount, error) {
+			found, err := s.userRepo.GetByID(ctx, userID.String())
+			if err != nil {
+				if errors.Is(err, user.ErrNotFound) {
+					return nil, ErrWebAuthnCredentialInvalid
+				}
+				return nil, err
+			}
+
+			usr = found
+			return s.webAuthnAccount(ctx, usr)
+		}
+
+		_, credential, err := s.webauthn.FinishLogin(ctx, challenge.State, inp.Credential, lookup)
+		if err != nil {
+			return err
+		}
+
+		credential.MarkUsed()
+		if err := s.authRepo.UpdateWebAuthnCredential(ctx, credential); err != nil {
+			return err
+		}
+
+		if s.cfg.RequireVerifiedEmail && !usr.IsVerified() {
+			return user.ErrEmailNotVerified
+		}
+
+		if err := s.checkLoginAllowed(usr); err != nil {
+			return err
+		}
+
+		result, err = s.createSession(ctx, usr, inp.UserAgent, inp.IPAddress, AMRHardwareKey, AMRMultiFactor)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// BeginMFAPasskey starts the passkey assertion completing a login that is waiting on its second factor.
+// The resulting credential is posted to [Service.VerifyMFA] along with the MFA token.
+func (s *Service) BeginMFAPasskey(ctx context.Context, inp BeginMFAPasskeyInput) (*WebAuthnOptions, error) {
+	if s.webauthn == nil {
+		return nil, ErrWebAuthnDisabled
+	}
+
+	challenge, err := s.authRepo.GetMFAChallenge(ctx, inp.MFAToken)
+	if err != nil {
+		if errors.Is(err, ErrMFAChallengeNotFound) {
+			return nil, ErrMFAChallengeInvalid
+		}
+		return nil, err
+	}
+
+	if challenge.Expired() || challenge.Used() || challenge.Exhausted() {
+		return nil, ErrMFAChallengeInvalid
+	}
+
+	usr, err := s.userRepo.GetByID(ctx, challenge.UserID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.webAuthnAccount(ctx, usr)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(account.Credentials) == 0 {
+		return nil, ErrWebAuthnCredentialNotFound
+	}
+
+	ceremony, err := s.webauthn.BeginLogin(ctx, account)
+	if err != nil {
+		log.ErrorCtx(ctx, "Failed to begin webauthn assertion", err)
+		return nil, err
+	}
+
+	return s.storeWebAuthnChallenge(ctx, &usr.ID, WebAuthnCeremonyMFA, ceremony)
+}
+
+// BeginReauthenticationPasskey starts the passkey assertion of a signed in user re-authenticating, which lets
+// accounts without a password, e.g. passkey or external identity only accounts, re-authenticate. The resulting
+// credential is posted to [Service.Reauthenticate].
+func (s *Service) BeginReauthenticationPasskey(ctx context.Context, userID string) (*WebAuthnOptions, error) {
+	if s.webauthn == nil {
+		return nil, ErrWebAuthnDisabled
+	}
+
+	usr, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.webAuthnAccount(ctx, usr)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(account.Credentials) == 0 {
+		return nil, ErrWebAuthnCredentialNotFound
+	}
+
+	ceremony, err := s.webauthn.BeginLogin(ctx, account)
+	if err != nil {
+		log.ErrorCtx(ctx, "Failed to begin webauthn assertion", err)
+		return nil, err
+	}
+
+	return s.storeWebAuthnChallenge(ctx, &usr.ID, WebAuthnCeremonyMFA, ceremony)
+}
+
+// verifyPasskey checks a passkey assertion given as the second factor of the user. An invalid challenge or
+// assertion reports a failed verification rather than an error, so it counts against the MFA challenge or the
+// account.
+// Must be called inside a transaction.
+func (s *Service) verifyPasskey(ctx context.Context, userID, challengeID string, response []byte) (bool, error) {
+	if s.webauthn == nil {
+		return false, ErrWebAuthnDisabled
+	}
+
+	challenge, err := s.consumeWebAuthnChallenge(ctx, challengeID, WebAuthnCeremonyMFA, userID)
+	if err != nil {
+		if errors.Is(err, ErrWebAuthnChallengeInvalid) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	lookup := func(id uuid.UUID) (*WebAuthnAccount, error) {
+		if id.String() != userID {
+			return nil, ErrWebAuthnCredentialInvalid
+		}
+
+		usr, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		return s.webAuthnAccount(ctx, usr)
+	}
+
+	_, credential, err := s.webauthn.FinishLogin(ctx, challenge.State, response, lookup)
+	if err != nil {
+		if errors.Is(err, ErrWebAuthnCredentialInvalid) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	credential.MarkUsed()
+	return true, s.authRepo.UpdateWebAuthnCredential(ctx, credential)
+}
+
+// hasWebAuthnCredentials reports whether the user has registered a passkey. Passkeys are ignored while WebAuthn
+// is disabled, since they couldn't be used.
+func (s *Service) hasWebAuthnCredentials(ctx context.Context, userID string) (bool, error) {
+	if s.webauthn == nil {
+		return false, nil
+	}
+
+	credentials, err := s.authRepo.ListWebAuthnCredentialsByUser(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	return len(credentials) > 0, nil
+}
+
+// webAuthnAccount describes the user along with their registered credentials for a ceremony.
+func (s *Service) webAuthnAccount(ctx context.Context, usr *user.User) (*WebAuthnAccount, error) {
+	credentials, err := s.authRepo.ListWebAuthnCredentialsByUser(ctx, usr.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return &WebAuthnAccount{
+		UserID:      usr.ID,
+		Name:        usr.Email,
+		DisplayName: usr.Name,
+		Credentials: credentials,
+	}, nil
+}
+
+// storeWebAuthnChallenge keeps the state of a ceremony until the browser responds, returning the options to pass
+// to the browser.
+func (s *Service) storeWebAuthnChallenge(
+	ctx context.Context,
+	userID *uuid.UUID,
+	ceremonyType string,
+	ceremony *WebAuthnCeremony,
+) (*WebAuthnOptions, error) {
+	ttl := s.cfg.WebAuthnChallengeTTL
+	if ttl == 0 {
+		ttl = defaultWebAuthnChallengeTTL
+	}
+
+	challenge, err := NewWebAuthnChallenge(userID, ceremonyType, ceremony.State, ttl)
+	if err != nil {
+		log.ErrorCtx(ctx, "Failed to create webauthn challenge", err)
+		return nil, err
+	}
+
+	if err := s.authRepo.StoreWebAuthnChallenge(ctx, challenge); err != nil {
+		return nil, err
+	}
+
+	return &WebAuthnOptions{
+		ChallengeID: challenge.Value,
+		Options:     ceremony.Options,
+		ExpiresAt:   challenge.ExpiresAt,
+	}, nil
+}
+
+// consumeWebAuthnChallenge retrieves a challenge that is still valid for the ceremony of the user and marks it used,
+// so a browser response can only be verified once. Must be called inside a transaction.
+func (s *Service) consumeWebAuthnChallenge(
+	ctx context.Context,
+	value string,
+	ceremony string,
+	userID string,
+) (*WebAuthnChallenge, error) {
+	challenge, err := s.authRepo.GetWebAuthnChallenge(ctx, value)
+	if err != nil {
+		if errors.Is(err, ErrWebAuthnChallengeNotFound) {
+			return nil, ErrWebAuthnChallengeInvalid
+		}
+		return nil, err
+	}
+
+	if !challenge.Valid(ceremony, userID) {
+		return nil, ErrWebAuthnChallengeInvalid
+	}
+
+	challenge.Revoke()
+	if err := s.authRepo.UpdateWebAuthnChallenge(ctx, challenge); err != nil {
+		return nil, err
+	}
+
+	return challenge, nil
+}
@@ -0,0 +1,572 @@
+package auth_test
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prawirdani/golang-restapi/config"
+	"github.com/prawirdani/golang-restapi/internal/domain/auth"
+	"github.com/prawirdani/golang-restapi/internal/domain/user"
+	"github.com/prawirdani/golang-restapi/internal/testing/mocks"
+)
+
+type webAuthnTestDeps struct {
+	transactor   *mocks.Transactor
+	userRepo     *mocks.UserRepository
+	authRepo     *mocks.AuthRepository
+	roleRepo     *mocks.RoleRepository
+	relyingParty *mocks.WebAuthnRelyingParty
+}
+
+func newWebAuthnTestService(t *testing.T) (*auth.Service, webAuthnTestDeps) {
+	deps := webAuthnTestDeps{
+		transactor:   mocks.NewTransactor(t),
+		userRepo:     mocks.NewUserRepository(t),
+		authRepo:     mocks.NewAuthRepository(t),
+		roleRepo:     mocks.NewRoleRepository(t),
+		relyingParty: mocks.NewWebAuthnRelyingParty(t),
+	}
+	cfg := config.Auth{
+		JwtTTL:          time.Hour,
+		SessionTTL:      24 * time.Hour,
+		MFAChallengeTTL: 5 * time.Minute,
+	}
+
+	svc := auth.NewService(
+		cfg,
+		testKeys,
+		nil,
+		deps.transactor,
+		deps.userRepo,
+		deps.authRepo,
+		deps.roleRepo,
+		nil,
+		mocks.NewAuthMessagePublisher(t),
+		nil,
+		nil,
+		deps.relyingParty,
+	)
+	return svc, deps
+}
+
+// runTransact runs the transaction function right away, returning its error like the real transactor.
+func runTransact(deps webAuthnTestDeps) {
+	deps.transactor.EXPECT().
+		Transact(mock.Anything, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+}
+
+func newTestPasskey(userID uuid.UUID) *auth.WebAuthnCredential {
+	return &auth.WebAuthnCredential{
+		ID:        "Y3JlZGVudGlhbC1pZA",
+		UserID:    userID,
+		Name:      "MacBook",
+		PublicKey: []byte("public-key"),
+		SignCount: 1,
+		CreatedAt: time.Now(),
+	}
+}
+
+func TestService_BeginPasskeyRegistration(t *testing.T) {
+	ctx := context.Background()
+	testUser := &user.User{ID: uuid.New(), Name: "John Doe", Email: "john@example.com"}
+
+	t.Run("Success", func(t *testing.T) {
+		service, deps := newWebAuthnTestService(t)
+		passkey := newTestPasskey(testUser.ID)
+		ceremony := &auth.WebAuthnCeremony{Options: json.RawMessage(`{"publicKey":{}}`), State: []byte(`{}`)}
+
+		deps.userRepo.EXPECT().GetByID(ctx, testUser.ID.String()).Return(testUser, nil)
+		deps.authRepo.EXPECT().
+			ListWebAuthnCredentialsByUser(ctx, testUser.ID.String()).
+			Return([]*auth.WebAuthnCredential{passkey}, nil)
+		deps.relyingParty.EXPECT().
+			BeginRegistration(ctx, mock.MatchedBy(func(a *auth.WebAuthnAccount) bool {
+				return a.UserID == testUser.ID && a.Name == testUser.Email && len(a.Credentials) == 1
+			})).
+			Return(ceremony, nil)
+		deps.authRepo.EXPECT().StoreWebAuthnChallenge(ctx, mock.MatchedBy(func(c *auth.WebAuthnChallenge) bool {
+			return c.Ceremony == auth.WebAuthnCeremonyRegistration && c.UserID.UUID == testUser.ID
+		})).Return(nil)
+
+		options, err := service.BeginPasskeyRegistration(ctx, testUser.ID.String())
+		require.NoError(t, err)
+		assert.NotEmpty(t, options.ChallengeID)
+		assert.JSONEq(t, string(ceremony.Options), string(options.Options))
+		assert.True(t, options.ExpiresAt.After(time.Now()))
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		service := auth.NewService(config.Auth{}, testKeys, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		options, err := service.BeginPasskeyRegistration(ctx, testUser.ID.String())
+		assert.ErrorIs(t, err, auth.ErrWebAuthnDisabled)
+		assert.Nil(t, options)
+	})
+}
+
+func TestService_FinishPasskeyRegistration(t *testing.T) {
+	ctx := context.Background()
+	testUser := &user.User{ID: uuid.New(), Name: "John Doe", Email: "john@example.com"}
+	response := json.RawMessage(`{"id":"Y3JlZGVudGlhbC1pZA"}`)
+
+	t.Run("FirstSecondFactor", func(t *testing.T) {
+		service, deps := newWebAuthnTestService(t)
+		challenge, err := auth.NewWebAuthnChallenge(&testUser.ID, auth.WebAuthnCeremonyRegistration, []byte(`{}`), time.Minute)
+		require.NoError(t, err)
+		passkey := newTestPasskey(uuid.Nil)
+		passkey.Name = ""
+
+		runTransact(deps)
+		deps.authRepo.EXPECT().GetWebAuthnChallenge(mock.Anything, challenge.Value).Return(challenge, nil)
+		deps.authRepo.EXPECT().UpdateWebAuthnChallenge(mock.Anything, challenge).Return(nil)
+		deps.userRepo.EXPECT().GetByID(mock.Anything, testUser.ID.String()).Return(testUser, nil)
+		deps.authRepo.EXPECT().
+			GetTOTPFactor(mock.Anything, testUser.ID.String()).
+			Return(nil, auth.ErrTOTPFactorNotFound)
+		deps.authRepo.EXPECT().
+			ListWebAuthnCredentialsByUser(mock.Anything, testUser.ID.String()).
+			Return([]*auth.WebAuthnCredential{}, nil)
+		deps.relyingParty.EXPECT().
+			FinishRegistration(mock.Anything, mock.Anything, challenge.State, []byte(response)).
+			Return(passkey, nil)
+		deps.authRepo.EXPECT().StoreWebAuthnCredential(mock.Anything, passkey).Return(nil)
+		deps.authRepo.EXPECT().
+			StoreRecoveryCodes(mock.Anything, testUser.ID.String(), mock.AnythingOfType("[]*auth.RecoveryCode")).
+			Return(nil)
+
+		res, err := service.FinishPasskeyRegistration(ctx, testUser.ID.String(), auth.FinishPasskeyRegistrationInput{
+			ChallengeID: challenge.Value,
+			Credential:  response,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, testUser.ID, res.UserID)
+		assert.Equal(t, "Passkey", res.Name)
+		assert.Len(t, res.RecoveryCodes, 10)
+		assert.True(t, challenge.Used())
+	})
+
+	t.Run("AdditionalPasskey", func(t *testing.T) {
+		service, deps := newWebAuthnTestService(t)
+		challenge, err := auth.NewWebAuthnChallenge(&testUser.ID, auth.WebAuthnCeremonyRegistration, []byte(`{}`), time.Minute)
+		require.NoError(t, err)
+		existing := newTestPasskey(testUser.ID)
+		passkey := newTestPasskey(uuid.Nil)
+
+		runTransact(deps)
+		deps.authRepo.EXPECT().GetWebAuthnChallenge(mock.Anything, challenge.Value).Return(challenge, nil)
+		deps.authRepo.EXPECT().UpdateWebAuthnChallenge(mock.Anything, challenge).Return(nil)
+		deps.userRepo.EXPECT().GetByID(mock.Anything, testUser.ID.String()).Return(testUser, nil)
+		deps.authRepo.EXPECT().
+			GetTOTPFactor(mock.Anything, testUser.ID.String()).
+			Return(nil, auth.ErrTOTPFactorNotFound)
+		deps.authRepo.EXPECT().
+			ListWebAuthnCredentialsByUser(mock.Anything, testUser.ID.String()).
+			Return([]*auth.WebAuthnCredential{existing}, nil)
+		deps.relyingParty.EXPECT().
+			FinishRegistration(mock.Anything, mock.Anything, challenge.State, []byte(response)).
+			Return(passkey, nil)
+		deps.authRepo.EXPECT().StoreWebAuthnCredential(mock.Anything, passkey).Return(nil)
+
+		res, err := service.FinishPasskeyRegistration(ctx, testUser.ID.String(), auth.FinishPasskeyRegistrationInput{
+			ChallengeID: challenge.Value,
+			Name:        "YubiKey",
+			Credential:  response,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "YubiKey", res.Name)
+		assert.Empty(t, res.RecoveryCodes)
+	})
+
+	t.Run("ChallengeOfAnotherUser", func(t *testing.T) {
+		service, deps := newWebAuthnTestService(t)
+		otherID := uuid.New()
+		challenge, err := auth.NewWebAuthnChallenge(&otherID, auth.WebAuthnCeremonyRegistration, []byte(`{}`), time.Minute)
+		require.NoError(t, err)
+
+		runTransact(deps)
+		deps.authRepo.EXPECT().GetWebAuthnChallenge(mock.Anything, challenge.Value).Return(challenge, nil)
+
+		res, err := service.FinishPasskeyRegistration(ctx, testUser.ID.String(), auth.FinishPasskeyRegistrationInput{
+			ChallengeID: challenge.Value,
+			Credential:  response,
+		})
+		assert.ErrorIs(t, err, auth.ErrWebAuthnChallengeInvalid)
+		assert.Nil(t, res)
+		assert.False(t, challenge.Used())
+	})
+
+	t.Run("InvalidAttestation", func(t *testing.T) {
+		service, deps := newWebAuthnTestService(t)
+		challenge, err := auth.NewWebAuthnChallenge(&testUser.ID, auth.WebAuthnCeremonyRegistration, []byte(`{}`), time.Minute)
+		require.NoError(t, err)
+
+		runTransact(deps)
+		deps.authRepo.EXPECT().GetWebAuthnChallenge(mock.Anything, challenge.Value).Return(challenge, nil)
+		deps.authRepo.EXPECT().UpdateWebAuthnChallenge(mock.Anything, challenge).Return(nil)
+		deps.userRepo.EXPECT().GetByID(mock.Anything, testUser.ID.String()).Return(testUser, nil)
+		deps.authRepo.EXPECT().
+			GetTOTPFactor(mock.Anything, testUser.ID.String()).
+			Return(nil, auth.ErrTOTPFactorNotFound)
+		deps.authRepo.EXPECT().
+			ListWebAuthnCredentialsByUser(mock.Anything, testUser.ID.String()).
+			Return([]*auth.WebAuthnCredential{}, nil)
+		deps.relyingParty.EXPECT().
+			FinishRegistration(mock.Anything, mock.Anything, challenge.State, []byte(response)).
+			Return(nil, auth.ErrWebAuthnCredentialInvalid)
+
+		res, err := service.FinishPasskeyRegistration(ctx, testUser.ID.String(), auth.FinishPasskeyRegistrationInput{
+			ChallengeID: challenge.Value,
+			Credential:  response,
+		})
+		assert.ErrorIs(t, err, auth.ErrWebAuthnCredentialInvalid)
+		assert.Nil(t, res)
+	})
+}
+
+func TestService_FinishPasskeyLogin(t *testing.T) {
+	ctx := context.Background()
+	response := json.RawMessage(`{"id":"Y3JlZGVudGlhbC1pZA"}`)
+
+	// expectAssertion makes the relying party resolve the asserting user through the lookup, as a real
+	// authenticator returning its user handle would.
+	expectAssertion := func(deps webAuthnTestDeps, challenge *auth.WebAuthnChallenge, usr *user.User, passkey *auth.WebAuthnCredential) {
+		deps.relyingParty.EXPECT().
+			FinishLogin(mock.Anything, challenge.State, []byte(response), mock.Anything).
+			RunAndReturn(func(
+				_ context.Context,
+				_, _ []byte,
+				lookup auth.WebAuthnAccountLookup,
+			) (*auth.WebAuthnAccount, *auth.WebAuthnCredential, error) {
+				account, err := lookup(usr.ID)
+				if err != nil {
+					return nil, nil, err
+				}
+				passkey.SignCount++
+				return account, passkey, nil
+			})
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		service, deps := newWebAuthnTestService(t)
+		testUser := &user.User{ID: uuid.New(), Name: "John Doe", Email: "john@example.com"}
+		passkey := newTestPasskey(testUser.ID)
+		challenge, err := auth.NewWebAuthnChallenge(nil, auth.WebAuthnCeremonyLogin, []byte(`{}`), time.Minute)
+		require.NoError(t, err)
+
+		runTransact(deps)
+		deps.authRepo.EXPECT().GetWebAuthnChallenge(mock.Anything, challenge.Value).Return(challenge, nil)
+		deps.authRepo.EXPECT().UpdateWebAuthnChallenge(mock.Anything, challenge).Return(nil)
+		deps.userRepo.EXPECT().GetByID(mock.Anything, testUser.ID.String()).Return(testUser, nil)
+		deps.authRepo.EXPECT().
+			ListWebAuthnCredentialsByUser(mock.Anything, testUser.ID.String()).
+			Return([]*auth.WebAuthnCredential{passkey}, nil)
+		expectAssertion(deps, challenge, testUser, passkey)
+		deps.authRepo.EXPECT().UpdateWebAuthnCredential(mock.Anything, passkey).Return(nil)
+		deps.roleRepo.EXPECT().GetRolesByUser(mock.Anything, testUser.ID.String()).Return([]*auth.Role{}, nil)
+		expectKnownDevice(deps.authRepo)
+		deps.authRepo.EXPECT().StoreSession(mock.Anything, mock.MatchedBy(func(s *auth.Session) bool {
+			return slices.Equal(s.AMR, []string{auth.AMRHardwareKey, auth.AMRMultiFactor})
+		})).Return(nil)
+
+		res, err := service.FinishPasskeyLogin(ctx, auth.FinishPasskeyLoginInput{
+			ChallengeID: challenge.Value,
+			Credential:  response,
+			UserAgent:   "test-agent",
+		})
+		require.NoError(t, err)
+		assert.False(t, res.MFARequired())
+		assert.NotEmpty(t, res.SessionID)
+		assert.Equal(t, uint32(2), passkey.SignCount)
+		assert.True(t, passkey.LastUsedAt.NotNull())
+
+		claims, err := auth.VerifyAccessToken(testKeys, res.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, testUser.ID.String(), claims.UserID)
+		assert.True(t, claims.MultiFactor())
+	})
+
+	t.Run("Suspended", func(t *testing.T) {
+		service, deps := newWebAuthnTestService(t)
+		testUser := &user.User{ID: uuid.New(), Name: "John Doe", Email: "john@example.com"}
+		require.NoError(t, testUser.Suspend())
+		passkey := newTestPasskey(testUser.ID)
+		challenge, err := auth.NewWebAuthnChallenge(nil, auth.WebAuthnCeremonyLogin, []byte(`{}`), time.Minute)
+		require.NoError(t, err)
+
+		runTransact(deps)
+		deps.authRepo.EXPECT().GetWebAuthnChallenge(mock.Anything, challenge.Value).Return(challenge, nil)
+		deps.authRepo.EXPECT().UpdateWebAuthnChallenge(mock.Anything, challenge).Return(nil)
+		deps.userRepo.EXPECT().GetByID(mock.Anything, testUser.ID.String()).Return(testUser, nil)
+		deps.authRepo.EXPECT().
+			ListWebAuthnCredentialsByUser(mock.Anything, testUser.ID.String()).
+			Return([]*auth.WebAuthnCredential{passkey}, nil)
+		expectAssertion(deps, challenge, testUser, passkey)
+		deps.authRepo.EXPECT().UpdateWebAuthnCredential(mock.Anything, passkey).Return(nil)
+
+		res, err := service.FinishPasskeyLogin(ctx, auth.FinishPasskeyLoginInput{
+			ChallengeID: challenge.Value,
+			Credential:  response,
+		})
+		assert.ErrorIs(t, err, user.ErrSuspended)
+		assert.Nil(t, res)
+	})
+
+	t.Run("UnknownUserHandle", func(t *testing.T) {
+		service, deps := newWebAuthnTestService(t)
+		testUser := &user.User{ID: uuid.New()}
+		challenge, err := auth.NewWebAuthnChallenge(nil, auth.WebAuthnCeremonyLogin, []byte(`{}`), time.Minute)
+		require.NoError(t, err)
+
+		runTransact(deps)
+		deps.authRepo.EXPECT().GetWebAuthnChallenge(mock.Anything, challenge.Value).Return(challenge, nil)
+		deps.authRepo.EXPECT().UpdateWebAuthnChallenge(mock.Anything, challenge).Return(nil)
+		deps.userRepo.EXPECT().GetByID(mock.Anything, testUser.ID.String()).Return(nil, user.ErrNotFound)
+		expectAssertion(deps, challenge, testUser, nil)
+
+		res, err := service.FinishPasskeyLogin(ctx, auth.FinishPasskeyLoginInput{
+			ChallengeID: challenge.Value,
+			Credential:  response,
+		})
+		assert.ErrorIs(t, err, auth.ErrWebAuthnCredentialInvalid)
+		assert.Nil(t, res)
+	})
+
+	t.Run("ChallengeUsed", func(t *testing.T) {
+		service, deps := newWebAuthnTestService(t)
+		challenge, err := auth.NewWebAuthnChallenge(nil, auth.WebAuthnCeremonyLogin, []byte(`{}`), time.Minute)
+		require.NoError(t, err)
+		challenge.Revoke()
+
+		runTransact(deps)
+		deps.authRepo.EXPECT().GetWebAuthnChallenge(mock.Anything, challenge.Value).Return(challenge, nil)
+
+		res, err := service.FinishPasskeyLogin(ctx, auth.FinishPasskeyLoginInput{
+			ChallengeID: challenge.Value,
+			Credential:  response,
+		})
+		assert.ErrorIs(t, err, auth.ErrWebAuthnChallengeInvalid)
+		assert.Nil(t, res)
+	})
+}
+
+func TestService_VerifyMFA_Passkey(t *testing.T) {
+	ctx := context.Background()
+	testUser := &user.User{ID: uuid.New(), Name: "John Doe", Email: "john@example.com"}
+	response := json.RawMessage(`{"id":"Y3JlZGVudGlhbC1pZA"}`)
+
+	t.Run("Success", func(t *testing.T) {
+		service, deps := newWebAuthnTestService(t)
+		passkey := newTestPasskey(testUser.ID)
+		mfaChallenge, err := auth.NewMFAChallenge(testUser.ID, "test-agent", auth.AMRPassword, []string{auth.MFAMethodWebAuthn}, time.Minute)
+		require.NoError(t, err)
+		challenge, err := auth.NewWebAuthnChallenge(&testUser.ID, auth.WebAuthnCeremonyMFA, []byte(`{}`), time.Minute)
+		require.NoError(t, err)
+
+		runTransact(deps)
+		deps.authRepo.EXPECT().GetMFAChallenge(mock.Anything, mfaChallenge.Value).Return(mfaChallenge, nil)
+		deps.authRepo.EXPECT().GetWebAuthnChallenge(mock.Anything, challenge.Value).Return(challenge, nil)
+		deps.authRepo.EXPECT().UpdateWebAuthnChallenge(mock.Anything, challenge).Return(nil)
+		deps.relyingParty.EXPECT().
+			FinishLogin(mock.Anything, challenge.State, []byte(response), mock.Anything).
+			Return(&auth.WebAuthnAccount{UserID: testUser.ID}, passkey, nil)
+		deps.authRepo.EXPECT().UpdateWebAuthnCredential(mock.Anything, passkey).Return(nil)
+		deps.authRepo.EXPECT().UpdateMFAChallenge(mock.Anything, mfaChallenge).Return(nil)
+		deps.userRepo.EXPECT().GetByID(mock.Anything, testUser.ID.String()).Return(testUser, nil)
+		deps.roleRepo.EXPECT().GetRolesByUser(mock.Anything, testUser.ID.String()).Return([]*auth.Role{}, nil)
+		expectKnownDevice(deps.authRepo)
+		deps.authRepo.EXPECT().StoreSession(mock.Anything, mock.MatchedBy(func(s *auth.Session) bool {
+			return slices.Equal(s.AMR, []string{auth.AMRPassword, auth.AMRHardwareKey, auth.AMRMultiFactor})
+		})).Return(nil)
+
+		res, err := service.VerifyMFA(ctx, auth.VerifyMFAInput{
+			MFAToken:    mfaChallenge.Value,
+			ChallengeID: challenge.Value,
+			Credential:  response,
+		})
+		require.NoError(t, err)
+		assert.NotEmpty(t, res.AccessToken)
+		assert.True(t, mfaChallenge.Used())
+		assert.True(t, passkey.LastUsedAt.NotNull())
+	})
+
+	t.Run("InvalidAssertion", func(t *testing.T) {
+		service, deps := newWebAuthnTestService(t)
+		mfaChallenge, err := auth.NewMFAChallenge(testUser.ID, "test-agent", auth.AMRPassword, []string{auth.MFAMethodWebAuthn}, time.Minute)
+		require.NoError(t, err)
+		challenge, err := auth.NewWebAuthnChallenge(&testUser.ID, auth.WebAuthnCeremonyMFA, []byte(`{}`), time.Minute)
+		require.NoError(t, err)
+
+		runTransact(deps)
+		deps.authRepo.EXPECT().GetMFAChallenge(mock.Anything, mfaChallenge.Value).Return(mfaChallenge, nil)
+		deps.userRepo.EXPECT().GetByID(mock.Anything, testUser.ID.String()).Return(testUser, nil)
+		deps.authRepo.EXPECT().GetWebAuthnChallenge(mock.Anything, challenge.Value).Return(challenge, nil)
+		deps.authRepo.EXPECT().UpdateWebAuthnChallenge(mock.Anything, challenge).Return(nil)
+		deps.relyingParty.EXPECT().
+			FinishLogin(mock.Anything, challenge.State, []byte(response), mock.Anything).
+			Return(nil, nil, auth.ErrWebAuthnCredentialInvalid)
+		deps.authRepo.EXPECT().UpdateMFAChallenge(mock.Anything, mfaChallenge).Return(nil)
+
+		res, err := service.VerifyMFA(ctx, auth.VerifyMFAInput{
+			MFAToken:    mfaChallenge.Value,
+			ChallengeID: challenge.Value,
+			Credential:  response,
+		})
+		assert.Equal(t, auth.ErrMFACodeInvalid, err)
+		assert.Nil(t, res)
+		assert.Equal(t, 1, mfaChallenge.Attempts)
+	})
+}
+
+func TestService_BeginReauthenticationPasskey(t *testing.T) {
+	ctx := context.Background()
+	testUser := &user.User{ID: uuid.New(), Name: "John Doe", Email: "john@example.com"}
+
+	t.Run("Success", func(t *testing.T) {
+		service, deps := newWebAuthnTestService(t)
+		passkey := newTestPasskey(testUser.ID)
+		ceremony := &auth.WebAuthnCeremony{Options: json.RawMessage(`{"publicKey":{}}`), State: []byte(`{}`)}
+
+		deps.userRepo.EXPECT().GetByID(ctx, testUser.ID.String()).Return(testUser, nil)
+		deps.authRepo.EXPECT().
+			ListWebAuthnCredentialsByUser(ctx, testUser.ID.String()).
+			Return([]*auth.WebAuthnCredential{passkey}, nil)
+		deps.relyingParty.EXPECT().
+			BeginLogin(ctx, mock.MatchedBy(func(a *auth.WebAuthnAccount) bool {
+				return a.UserID == testUser.ID && len(a.Credentials) == 1
+			})).
+			Return(ceremony, nil)
+		deps.authRepo.EXPECT().StoreWebAuthnChallenge(ctx, mock.MatchedBy(func(c *auth.WebAuthnChallenge) bool {
+			return c.Ceremony == auth.WebAuthnCeremonyMFA && c.UserID.UUID == testUser.ID
+		})).Return(nil)
+
+		options, err := service.BeginReauthenticationPasskey(ctx, testUser.ID.String())
+		require.NoError(t, err)
+		assert.NotEmpty(t, options.ChallengeID)
+		assert.JSONEq(t, string(ceremony.Options), string(options.Options))
+	})
+
+	t.Run("NoPasskey", func(t *testing.T) {
+		service, deps := newWebAuthnTestService(t)
+
+		deps.userRepo.EXPECT().GetByID(ctx, testUser.ID.String()).Return(testUser, nil)
+		deps.authRepo.EXPECT().ListWebAuthnCredentialsByUser(ctx, testUser.ID.String()).Return(nil, nil)
+
+		options, err := service.BeginReauthenticationPasskey(ctx, testUser.ID.String())
+		assert.ErrorIs(t, err, auth.ErrWebAuthnCredentialNotFound)
+		assert.Nil(t, options)
+	})
+}
+
+func TestService_Reauthenticate_Passkey(t *testing.T) {
+	ctx := context.Background()
+	response := json.RawMessage(`{"id":"Y3JlZGVudGlhbC1pZA"}`)
+
+	// Passkey only account, it has no password or TOTP factor to re-authenticate with
+	testUser, err := user.NewExternal("John Doe", "john@example.com")
+	require.NoError(t, err)
+	userID := testUser.ID.String()
+
+	session, err := auth.NewSession(testUser.ID, "test-agent", time.Hour, auth.AMRHardwareKey, auth.AMRMultiFactor)
+	require.NoError(t, err)
+	session.AuthenticatedAt = time.Now().Add(-time.Hour)
+
+	t.Run("Success", func(t *testing.T) {
+		service, deps := newWebAuthnTestService(t)
+		passkey := newTestPasskey(testUser.ID)
+		challenge, err := auth.NewWebAuthnChallenge(&testUser.ID, auth.WebAuthnCeremonyMFA, []byte(`{}`), time.Minute)
+		require.NoError(t, err)
+
+		runTransact(deps)
+		deps.userRepo.EXPECT().GetByID(mock.Anything, userID).Return(testUser, nil)
+		deps.authRepo.EXPECT().GetWebAuthnChallenge(mock.Anything, challenge.Value).Return(challenge, nil)
+		deps.authRepo.EXPECT().UpdateWebAuthnChallenge(mock.Anything, challenge).Return(nil)
+		deps.relyingParty.EXPECT().
+			FinishLogin(mock.Anything, challenge.State, []byte(response), mock.Anything).
+			Return(&auth.WebAuthnAccount{UserID: testUser.ID}, passkey, nil)
+		deps.authRepo.EXPECT().UpdateWebAuthnCredential(mock.Anything, passkey).Return(nil)
+		deps.authRepo.EXPECT().ListSessionsByUser(mock.Anything, userID).Return([]*auth.Session{session}, nil)
+		deps.authRepo.EXPECT().UpdateSession(mock.Anything, mock.MatchedBy(func(s *auth.Session) bool {
+			return time.Since(s.AuthenticatedAt) < time.Minute
+		})).Return(nil)
+		deps.roleRepo.EXPECT().GetRolesByUser(mock.Anything, userID).Return([]*auth.Role{}, nil)
+
+		accessToken, err := service.Reauthenticate(ctx, userID, auth.ReauthenticateInput{
+			ChallengeID: challenge.Value,
+			Credential:  response,
+			SessionID:   session.FamilyID.String(),
+		})
+		require.NoError(t, err)
+
+		claims, err := auth.VerifyAccessToken(testKeys, accessToken)
+		require.NoError(t, err)
+		assert.True(t, claims.AuthenticatedWithin(time.Minute))
+		assert.True(t, passkey.LastUsedAt.NotNull())
+	})
+
+	t.Run("InvalidAssertion", func(t *testing.T) {
+		service, deps := newWebAuthnTestService(t)
+		challenge, err := auth.NewWebAuthnChallenge(&testUser.ID, auth.WebAuthnCeremonyMFA, []byte(`{}`), time.Minute)
+		require.NoError(t, err)
+
+		runTransact(deps)
+		deps.userRepo.EXPECT().GetByID(mock.Anything, userID).Return(testUser, nil)
+		deps.authRepo.EXPECT().GetWebAuthnChallenge(mock.Anything, challenge.Value).Return(challenge, nil)
+		deps.authRepo.EXPECT().UpdateWebAuthnChallenge(mock.Anything, challenge).Return(nil)
+		deps.relyingParty.EXPECT().
+			FinishLogin(mock.Anything, challenge.State, []byte(response), mock.Anything).
+			Return(nil, nil, auth.ErrWebAuthnCredentialInvalid)
+
+		accessToken, err := service.Reauthenticate(ctx, userID, auth.ReauthenticateInput{
+			ChallengeID: challenge.Value,
+			Credential:  response,
+			SessionID:   session.FamilyID.String(),
+		})
+		assert.Equal(t, auth.ErrMFACodeInvalid, err)
+		assert.Empty(t, accessToken)
+	})
+}
+
+func TestService_Login_PasskeyMFARequired(t *testing.T) {
+	ctx := context.Background()
+	service, deps := newWebAuthnTestService(t)
+
+	input := auth.LoginInput{
+		Email:     "john@example.com",
+		Password:  "password123",
+		UserAgent: "test-agent",
+	}
+
+	hashedPassword, err := auth.HashPassword(input.Password)
+	require.NoError(t, err)
+
+	testUser := &user.User{
+		ID:       uuid.New(),
+		Name:     "John Doe",
+		Email:    input.Email,
+		Password: string(hashedPassword),
+	}
+	passkey := newTestPasskey(testUser.ID)
+
+	deps.userRepo.EXPECT().GetByEmail(ctx, input.Email).Return(testUser, nil)
+	deps.authRepo.EXPECT().GetTOTPFactor(ctx, testUser.ID.String()).Return(nil, auth.ErrTOTPFactorNotFound)
+	deps.authRepo.EXPECT().
+		ListWebAuthnCredentialsByUser(ctx, testUser.ID.String()).
+		Return([]*auth.WebAuthnCredential{passkey}, nil)
+	deps.authRepo.EXPECT().StoreMFAChallenge(ctx, mock.AnythingOfType("*auth.MFAChallenge")).Return(nil)
+
+	res, err := service.Login(ctx, input)
+	require.NoError(t, err)
+	assert.True(t, res.MFARequired())
+	assert.Equal(t, []string{auth.MFAMethodWebAuthn}, res.MFAChallenge.Methods)
+}
@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebAuthnChallenge(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("Valid", func(t *testing.T) {
+		challenge, err := NewWebAuthnChallenge(&userID, WebAuthnCeremonyRegistration, []byte(`{}`), time.Minute)
+		require.NoError(t, err)
+		assert.Len(t, challenge.Value, 64)
+		assert.True(t, challenge.UserID.Valid)
+		assert.True(t, challenge.Valid(WebAuthnCeremonyRegistration, userID.String()))
+	})
+
+	t.Run("OtherCeremony", func(t *testing.T) {
+		challenge, err := NewWebAuthnChallenge(&userID, WebAuthnCeremonyRegistration, []byte(`{}`), time.Minute)
+		require.NoError(t, err)
+		assert.False(t, challenge.Valid(WebAuthnCeremonyMFA, userID.String()))
+	})
+
+	t.Run("OtherUser", func(t *testing.T) {
+		challenge, err := NewWebAuthnChallenge(&userID, WebAuthnCeremonyMFA, []byte(`{}`), time.Minute)
+		require.NoError(t, err)
+		assert.False(t, challenge.Valid(WebAuthnCeremonyMFA, uuid.NewString()))
+		assert.False(t, challenge.Valid(WebAuthnCeremonyMFA, ""))
+	})
+
+	t.Run("PasswordlessLogin", func(t *testing.T) {
+		challenge, err := NewWebAuthnChallenge(nil, WebAuthnCeremonyLogin, []byte(`{}`), time.Minute)
+		require.NoError(t, err)
+		assert.False(t, challenge.UserID.Valid)
+		assert.True(t, challenge.Valid(WebAuthnCeremonyLogin, ""))
+		assert.False(t, challenge.Valid(WebAuthnCeremonyLogin, userID.String()))
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		challenge, err := NewWebAuthnChallenge(nil, WebAuthnCeremonyLogin, []byte(`{}`), -time.Minute)
+		require.NoError(t, err)
+		assert.True(t, challenge.Expired())
+		assert.False(t, challenge.Valid(WebAuthnCeremonyLogin, ""))
+	})
+
+	t.Run("Used", func(t *testing.T) {
+		challenge, err := NewWebAuthnChallenge(nil, WebAuthnCeremonyLogin, []byte(`{}`), time.Minute)
+		require.NoError(t, err)
+		challenge.Revoke()
+		assert.True(t, challenge.Used())
+		assert.False(t, challenge.Valid(WebAuthnCeremonyLogin, ""))
+	})
+}
@@ -3,13 +3,15 @@ package domain
 type ErrorKind int8
 
 const (
-	ErrorKindNotFound     ErrorKind = iota // Resource not found
-	ErrorKindDuplicate                     // Already exists or duplicate constraint
-	ErrorKindUnauthorized                  // Not authenticated
-	ErrorKindForbidden                     // No permission / action against bussiness rules
-	ErrorKindValidation                    // Business rule violation
-	ErrorKindTimeout                       // Operation timed out
-	ErrorKindUnavailable                   // Temporarily unavailable
+	ErrorKindNotFound        ErrorKind = iota // Resource not found
+	ErrorKindDuplicate                        // Already exists or duplicate constraint
+	ErrorKindUnauthorized                     // Not authenticated
+	ErrorKindForbidden                        // No permission / action against bussiness rules
+	ErrorKindValidation                       // Business rule violation
+	ErrorKindTimeout                          // Operation timed out
+	ErrorKindUnavailable                      // Temporarily unavailable
+	ErrorKindTooManyRequests                  // Rate limited, retrying later may succeed
+	ErrorKindLocked                           // Resource temporarily locked
 )
 
 // Error is domain error type
@@ -31,12 +33,14 @@ func NewError(kind ErrorKind, message string) *Error {
 }
 
 var (
-	ErrNotFound     = factory(ErrorKindNotFound)
-	ErrDuplicate    = factory(ErrorKindDuplicate)
-	ErrUnauthorized = factory(ErrorKindUnauthorized)
-	ErrForbidden    = factory(ErrorKindForbidden)
-	ErrValidation   = factory(ErrorKindValidation)
-	ErrTimeout      = factory(ErrorKindTimeout)
+	ErrNotFound        = factory(ErrorKindNotFound)
+	ErrDuplicate       = factory(ErrorKindDuplicate)
+	ErrUnauthorized    = factory(ErrorKindUnauthorized)
+	ErrForbidden       = factory(ErrorKindForbidden)
+	ErrValidation      = factory(ErrorKindValidation)
+	ErrTimeout         = factory(ErrorKindTimeout)
+	ErrTooManyRequests = factory(ErrorKindTooManyRequests)
+	ErrLocked          = factory(ErrorKindLocked)
 )
 
 func factory(kind ErrorKind) func(msg string) *Error {
@@ -0,0 +1,23 @@
+// Package organization provides multi-tenancy: organizations, the memberships of users in them with a role per
+// organization, and the email invitations to join them.
+package organization
+
+import "context"
+
+type tenantCtxKey struct{}
+
+// WithTenant makes the organization of the membership the active tenant of ctx, with the membership's user as
+// the caller.
+func WithTenant(ctx context.Context, m *Membership) context.Context {
+	return context.WithValue(ctx, tenantCtxKey{}, m)
+}
+
+// TenantFromContext returns the membership of the caller in the active organization of ctx,
+// [ErrNoTenant] if no organization is active.
+func TenantFromContext(ctx context.Context) (*Membership, error) {
+	m, ok := ctx.Value(tenantCtxKey{}).(*Membership)
+	if !ok || m == nil {
+		return nil, ErrNoTenant
+	}
+	return m, nil
+}
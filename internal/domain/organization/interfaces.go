@@ -0,0 +1,72 @@
+// Package organization provides multi-tenancy: organizations, the memberships of users in them with a role per
+// organization, and the email invitations to join them.
+package organization
+
+import "context"
+
+// Repository persists organizations, memberships and invitations.
+//
+// Methods documented as tenant scoped only see the rows of the active organization of ctx, see [WithTenant],
+// and fail with [ErrNoTenant] when there is none, so a request can never reach into another tenant.
+type Repository interface {
+	// Store creates a new organization. Returns [ErrSlugExists] if the slug is taken.
+	Store(ctx context.Context, org *Organization) error
+
+	// GetByID retrieves an organization by its ID. Returns [ErrNotFound] if it doesn't exist.
+	GetByID(ctx context.Context, orgID string) (*Organization, error)
+
+	// GetBySlug retrieves an organization by its slug. Returns [ErrNotFound] if it doesn't exist.
+	GetBySlug(ctx context.Context, slug string) (*Organization, error)
+
+	// ListByUser retrieves the organizations the user is a member of, along with the user's role.
+	ListByUser(ctx context.Context, userID string) ([]*UserOrganization, error)
+
+	// StoreMembership adds a user to an organization. Returns [ErrAlreadyMember] if the user already is a member.
+	StoreMembership(ctx context.Context, m *Membership) error
+
+	// GetMembership retrieves the membership of the user in the organization. Returns [ErrNotMember] if the user
+	// isn't a member. Used to resolve the tenant, so it is not tenant scoped.
+	GetMembership(ctx context.Context, orgID, userID string) (*Membership, error)
+
+	// ListMembers retrieves the members of the organization, tenant scoped.
+	ListMembers(ctx context.Context) ([]*Member, error)
+
+	// GetMember retrieves the membership of the user in the organization, tenant scoped.
+	// Returns [ErrMemberNotFound] if the user isn't a member.
+	GetMember(ctx context.Context, userID string) (*Membership, error)
+
+	// UpdateMembership updates the role of a membership, tenant scoped.
+	UpdateMembership(ctx context.Context, m *Membership) error
+
+	// DeleteMembership removes the user from the organization, tenant scoped.
+	DeleteMembership(ctx context.Context, userID string) error
+
+	// CountOwners counts the owners of the organization, tenant scoped.
+	CountOwners(ctx context.Context) (int, error)
+
+	// StoreInvitation creates a new invitation, tenant scoped.
+	StoreInvitation(ctx context.Context, inv *Invitation) error
+
+	// ListPendingInvitations retrieves the invitations that are neither answered nor expired, tenant scoped.
+	ListPendingInvitations(ctx context.Context) ([]*Invitation, error)
+
+	// DeleteInvitation removes an invitation, tenant scoped. Returns [ErrInvitationNotFound] if it doesn't exist.
+	DeleteInvitation(ctx context.Context, invitationID string) error
+
+	// DeleteUnansweredInvitations removes the unanswered invitations sent to the email address, tenant scoped.
+	DeleteUnansweredInvitations(ctx context.Context, email string) error
+
+	// GetInvitationByTokenHash retrieves an invitation by the hash of its token, see [HashInvitationToken].
+	// Returns [ErrInvitationNotFound] if no invitation matches.
+	GetInvitationByTokenHash(ctx context.Context, tokenHash string) (*Invitation, error)
+
+	// UpdateInvitation records the answer to an invitation.
+	UpdateInvitation(ctx context.Context, inv *Invitation) error
+}
+
+// MessagePublisher publishes the organization emails to the message queue, for the worker to send.
+type MessagePublisher interface {
+	// SendInvitationEmail publishes a message to trigger an email inviting someone to join an organization.
+	// Returns an error if the message cannot be published to the queue.
+	SendInvitationEmail(ctx context.Context, msg InvitationEmailMessage) error
+}
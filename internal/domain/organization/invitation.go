@@ -0,0 +1,113 @@
+// Package organization provides multi-tenancy: organizations, the memberships of users in them with a role per
+// organization, and the email invitations to join them.
+package organization
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prawirdani/golang-restapi/internal/domain"
+	"github.com/prawirdani/golang-restapi/pkg/nullable"
+)
+
+var (
+	// ErrInvitationInvalid is returned when the invitation token is unknown, expired or already answered.
+	ErrInvitationInvalid = domain.ErrForbidden("The invitation is invalid or expired. Please ask for a new one")
+
+	// ErrInvitationNotFound is returned when no matching invitation exists.
+	ErrInvitationNotFound = domain.ErrNotFound("Invitation not found")
+
+	// ErrInvitationEmailMismatch is returned when the invitation is accepted by an account with another address.
+	ErrInvitationEmailMismatch = domain.ErrForbidden("The invitation was sent to another email address")
+)
+
+// Invitation is an email invitation to join an organization with a role. Only the SHA-256 hash of the token is
+// stored, the plain token is mailed to the invitee and never persisted.
+type Invitation struct {
+	ID             uuid.UUID                    `db:"id"              json:"id"`
+	OrganizationID uuid.UUID                    `db:"organization_id" json:"organization_id"`
+	Email          string                       `db:"email"           json:"email"`
+	Role           Role                         `db:"role"            json:"role"`
+	TokenHash      string                       `db:"token_hash"      json:"-"`
+	InvitedBy      uuid.UUID                    `db:"invited_by"      json:"invited_by"`
+	ExpiresAt      time.Time                    `db:"expires_at"      json:"expires_at"`
+	AcceptedAt     nullable.Nullable[time.Time] `db:"accepted_at"     json:"accepted_at"`
+	DeclinedAt     nullable.Nullable[time.Time] `db:"declined_at"     json:"declined_at"`
+	CreatedAt      time.Time                    `db:"created_at"      json:"created_at"`
+}
+
+// NewInvitation creates an invitation and returns it along with the plain token to mail to the invitee.
+func NewInvitation(
+	orgID uuid.UUID,
+	email string,
+	role Role,
+	invitedBy uuid.UUID,
+	ttl time.Duration,
+) (*Invitation, string, error) {
+	if !role.Valid() {
+		return nil, "", ErrInvalidRole
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, "", err
+	}
+
+	bs := make([]byte, 32)
+	if _, err := rand.Read(bs); err != nil {
+		return nil, "", err
+	}
+	token := hex.EncodeToString(bs)
+
+	now := time.Now()
+	return &Invitation{
+		ID:             id,
+		OrganizationID: orgID,
+		Email:          strings.ToLower(strings.TrimSpace(email)),
+		Role:           role,
+		TokenHash:      HashInvitationToken(token),
+		InvitedBy:      invitedBy,
+		ExpiresAt:      now.Add(ttl),
+		CreatedAt:      now,
+	}, token, nil
+}
+
+// HashInvitationToken returns the hex encoded SHA-256 hash of a plain invitation token, as stored.
+func HashInvitationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Expired reports whether the invitation has passed its expiration time.
+func (i Invitation) Expired() bool {
+	return i.ExpiresAt.Before(time.Now())
+}
+
+// Answered reports whether the invitation has already been accepted or declined.
+func (i Invitation) Answered() bool {
+	return i.AcceptedAt.NotNull() || i.DeclinedAt.NotNull()
+}
+
+// Pending reports whether the invitation can still be accepted or declined.
+func (i Invitation) Pending() bool {
+	return !i.Answered() && !i.Expired()
+}
+
+// For reports whether the invitation was sent to the email address.
+func (i Invitation) For(email string) bool {
+	return strings.EqualFold(i.Email, strings.TrimSpace(email))
+}
+
+// Accept marks the invitation as accepted.
+func (i *Invitation) Accept() {
+	i.AcceptedAt = nullable.New(time.Now(), false)
+}
+
+// Decline marks the invitation as declined.
+func (i *Invitation) Decline() {
+	i.DeclinedAt = nullable.New(time.Now(), false)
+}
@@ -0,0 +1,81 @@
+// Package organization provides multi-tenancy: organizations, the memberships of users in them with a role per
+// organization, and the email invitations to join them.
+package organization
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Role is the role of a user within one organization, independent of their global roles.
+type Role string
+
+const (
+	RoleOwner  Role = "owner"  // Full control, including the other owners
+	RoleAdmin  Role = "admin"  // Manages members and invitations, except owners
+	RoleMember Role = "member" // Regular access to the organization
+)
+
+// Valid reports whether r is a known role.
+func (r Role) Valid() bool {
+	switch r {
+	case RoleOwner, RoleAdmin, RoleMember:
+		return true
+	}
+	return false
+}
+
+// CanManageMembers reports whether the role may invite, update and remove members.
+func (r Role) CanManageMembers() bool {
+	return r == RoleOwner || r == RoleAdmin
+}
+
+// CanManage reports whether a member with role r may grant, change or revoke the other role.
+// Only owners touch owners.
+func (r Role) CanManage(other Role) bool {
+	if !r.CanManageMembers() {
+		return false
+	}
+	return r == RoleOwner || other != RoleOwner
+}
+
+// Membership links a user to an organization with a role.
+type Membership struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	UserID         uuid.UUID `db:"user_id"         json:"user_id"`
+	Role           Role      `db:"role"            json:"role"`
+	CreatedAt      time.Time `db:"created_at"      json:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at"      json:"updated_at"`
+}
+
+// NewMembership creates a membership of the user in the organization.
+func NewMembership(orgID, userID uuid.UUID, role Role) (*Membership, error) {
+	if !role.Valid() {
+		return nil, ErrInvalidRole
+	}
+
+	now := time.Now()
+	return &Membership{
+		OrganizationID: orgID,
+		UserID:         userID,
+		Role:           role,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}, nil
+}
+
+// Member is a membership along with the profile of the user, as listed to the other members.
+type Member struct {
+	UserID   uuid.UUID `db:"user_id"    json:"user_id"`
+	Name     string    `db:"name"       json:"name"`
+	Email    string    `db:"email"      json:"email"`
+	Role     Role      `db:"role"       json:"role"`
+	JoinedAt time.Time `db:"created_at" json:"joined_at"`
+}
+
+// UserOrganization is an organization the user belongs to, along with the user's role in it.
+type UserOrganization struct {
+	Organization
+	Role Role `db:"role" json:"role"`
+}
@@ -0,0 +1,33 @@
+// Package organization provides multi-tenancy: organizations, the memberships of users in them with a role per
+// organization, and the email invitations to join them.
+package organization
+
+import "time"
+
+type CreateOrganizationInput struct {
+	Name string `json:"name" validate:"required,max=100"`
+	Slug string `json:"slug" validate:"required,min=3,max=50"`
+}
+
+type InviteMemberInput struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  Role   `json:"role"  validate:"required,oneof=owner admin member"`
+}
+
+type UpdateMemberRoleInput struct {
+	Role Role `json:"role" validate:"required,oneof=owner admin member"`
+}
+
+type InvitationTokenInput struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// InvitationEmailMessage carries what the worker needs to mail an invitation.
+type InvitationEmailMessage struct {
+	To               string        `json:"to"`                // Invitee's email address
+	OrganizationName string        `json:"organization_name"` // Organization the invitee is invited to
+	InviterName      string        `json:"inviter_name"`      // Member who sent the invitation
+	Role             Role          `json:"role"`              // Role granted on acceptance
+	InvitationURL    string        `json:"invitation_url"`    // Link for accepting or declining
+	Expiry           time.Duration `json:"expiry"`            // Validity of the invitation
+}
@@ -0,0 +1,77 @@
+// Package organization provides multi-tenancy: organizations, the memberships of users in them with a role per
+// organization, and the email invitations to join them.
+package organization
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prawirdani/golang-restapi/internal/domain"
+)
+
+var (
+	ErrRequiredName     = domain.ErrValidation("Organization name is required")
+	ErrInvalidSlug      = domain.ErrValidation("Slug must be 3 to 50 lowercase letters, digits or hyphens")
+	ErrSlugExists       = domain.ErrDuplicate("Organization slug already exists")
+	ErrNotFound         = domain.ErrNotFound("Organization not found")
+	ErrNotMember        = domain.ErrForbidden("You are not a member of this organization")
+	ErrNoTenant         = domain.ErrForbidden("No active organization, select one to proceed")
+	ErrMemberNotFound   = domain.ErrNotFound("Member not found")
+	ErrAlreadyMember    = domain.ErrDuplicate("User is already a member of this organization")
+	ErrInvalidRole      = domain.ErrValidation("Role must be one of owner, admin or member")
+	ErrRoleNotPermitted = domain.ErrForbidden("Your role in this organization does not allow this action")
+	ErrLastOwner        = domain.ErrValidation("An organization must keep at least one owner")
+)
+
+// slugPattern keeps slugs usable as a subdomain label.
+var slugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,48}[a-z0-9]$`)
+
+// Organization is a tenant, a customer account grouping the users who work together.
+type Organization struct {
+	ID        uuid.UUID `db:"id"         json:"id"`
+	Name      string    `db:"name"       json:"name"`
+	Slug      string    `db:"slug"       json:"slug"` // Unique, resolves the organization from a subdomain
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// New creates an organization. The slug is lowercased before being validated.
+func New(name, slug string) (*Organization, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	org := &Organization{
+		ID:        id,
+		Name:      strings.TrimSpace(name),
+		Slug:      strings.ToLower(strings.TrimSpace(slug)),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := org.Validate(); err != nil {
+		return nil, err
+	}
+
+	return org, nil
+}
+
+// Validate checks the organization fields.
+func (o *Organization) Validate() error {
+	if o.Name == "" {
+		return ErrRequiredName
+	}
+	if !ValidSlug(o.Slug) {
+		return ErrInvalidSlug
+	}
+	return nil
+}
+
+// ValidSlug reports whether s is a well-formed organization slug.
+func ValidSlug(s string) bool {
+	return slugPattern.MatchString(s) && !strings.Contains(s, "--")
+}
@@ -0,0 +1,74 @@
+package organization
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name string
+		slug string
+		err  error
+	}{
+		{name: "Acme Inc", slug: "acme", err: nil},
+		{name: "Acme Inc", slug: " Acme-Corp ", err: nil},
+		{name: "", slug: "acme", err: ErrRequiredName},
+		{name: "Acme Inc", slug: "ac", err: ErrInvalidSlug},
+		{name: "Acme Inc", slug: "-acme", err: ErrInvalidSlug},
+		{name: "Acme Inc", slug: "acme-", err: ErrInvalidSlug},
+		{name: "Acme Inc", slug: "acme--corp", err: ErrInvalidSlug},
+		{name: "Acme Inc", slug: "acme.corp", err: ErrInvalidSlug},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.slug, func(t *testing.T) {
+			org, err := New(tt.name, tt.slug)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				assert.Nil(t, org)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotEqual(t, uuid.Nil, org.ID)
+			assert.True(t, ValidSlug(org.Slug))
+		})
+	}
+}
+
+func TestRole_CanManage(t *testing.T) {
+	assert.True(t, RoleOwner.CanManage(RoleOwner))
+	assert.True(t, RoleOwner.CanManage(RoleMember))
+	assert.True(t, RoleAdmin.CanManage(RoleAdmin))
+	assert.True(t, RoleAdmin.CanManage(RoleMember))
+	assert.False(t, RoleAdmin.CanManage(RoleOwner))
+	assert.False(t, RoleMember.CanManage(RoleMember))
+	assert.False(t, Role("guest").Valid())
+}
+
+func TestInvitation(t *testing.T) {
+	inv, token, err := NewInvitation(uuid.New(), " Jane@Example.com ", RoleMember, uuid.New(), time.Hour)
+	require.NoError(t, err)
+
+	assert.Equal(t, "jane@example.com", inv.Email)
+	assert.Equal(t, HashInvitationToken(token), inv.TokenHash)
+	assert.NotEqual(t, token, inv.TokenHash)
+	assert.True(t, inv.For("JANE@example.com"))
+	assert.False(t, inv.For("john@example.com"))
+	assert.True(t, inv.Pending())
+
+	inv.Decline()
+	assert.False(t, inv.Pending())
+
+	expired, _, err := NewInvitation(uuid.New(), "jane@example.com", RoleMember, uuid.New(), -time.Minute)
+	require.NoError(t, err)
+	assert.False(t, expired.Pending())
+
+	_, _, err = NewInvitation(uuid.New(), "jane@example.com", Role("guest"), uuid.New(), time.Hour)
+	assert.ErrorIs(t, err, ErrInvalidRole)
+}
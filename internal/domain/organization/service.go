@@ -0,0 +1,453 @@
+// Package organization provides multi-tenancy: organizations, the memberships of users in them with a role per
+// organization, and the email invitations to join them.
+package organization
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/prawirdani/golang-restapi/config"
+	"github.com/prawirdani/golang-restapi/internal/domain/audit"
+	"github.com/prawirdani/golang-restapi/internal/domain/user"
+	"github.com/prawirdani/golang-restapi/internal/infrastructure/repository"
+	"github.com/prawirdani/golang-restapi/pkg/log"
+)
+
+type Service struct {
+	cfg        config.Organization
+	transactor repository.Transactor
+	repo       Repository
+	userRepo   user.Repository
+	publisher  MessagePublisher
+	auditor    audit.Writer
+}
+
+func NewService(
+	cfg config.Organization,
+	transactor repository.Transactor,
+	repo Repository,
+	userRepo user.Repository,
+	publisher MessagePublisher,
+	auditor audit.Writer,
+) *Service {
+	return &Service{
+		cfg:        cfg,
+		transactor: transactor,
+		repo:       repo,
+		userRepo:   userRepo,
+		publisher:  publisher,
+		auditor:    auditor,
+	}
+}
+
+// CreateOrganization creates an organization owned by the user.
+func (s *Service) CreateOrganization(
+	ctx context.Context,
+	userID string,
+	inp CreateOrganizationInput,
+) (*UserOrganization, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	org, err := New(inp.Name, inp.Slug)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.transactor.Transact(ctx, func(ctx context.Context) error {
+		if err := s.repo.Store(ctx, org); err != nil {
+			return err
+		}
+
+		owner, err := NewMembership(org.ID, uid, RoleOwner)
+		if err != nil {
+			return err
+		}
+
+		if err := s.repo.StoreMembership(ctx, owner); err != nil {
+			return err
+		}
+
+		return s.recordEvent(ctx, audit.EventOrganizationCreated, userID, org.ID, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &UserOrganization{Organization: *org, Role: RoleOwner}, nil
+}
+
+// ListUserOrganizations returns the organizations the user is a member of.
+func (s *Service) ListUserOrganizations(ctx context.Context, userID string) ([]*UserOrganization, error) {
+	return s.repo.ListByUser(ctx, userID)
+}
+
+// ResolveTenant returns the membership of the user in the organization referenced by its ID or slug, to be made
+// the active tenant with [WithTenant]. Unknown organizations are reported as [ErrNotMember] too, so their
+// existence isn't disclosed to outsiders.
+func (s *Service) ResolveTenant(ctx context.Context, userID, ref string) (*Membership, error) {
+	var (
+		org *Organization
+		err error
+	)
+	if _, parseErr := uuid.Parse(ref); parseErr == nil {
+		org, err = s.repo.GetByID(ctx, ref)
+	} else {
+		org, err = s.repo.GetBySlug(ctx, ref)
+	}
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrNotMember
+		}
+		return nil, err
+	}
+
+	return s.repo.GetMembership(ctx, org.ID.String(), userID)
+}
+
+// GetCurrentOrganization returns the active organization of ctx along with the caller's role.
+func (s *Service) GetCurrentOrganization(ctx context.Context) (*UserOrganization, error) {
+	tenant, err := TenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	org, err := s.repo.GetByID(ctx, tenant.OrganizationID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return &UserOrganization{Organization: *org, Role: tenant.Role}, nil
+}
+
+// ListMembers returns the members of the active organization, visible to every member.
+func (s *Service) ListMembers(ctx context.Context) ([]*Member, error) {
+	if _, err := TenantFromContext(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListMembers(ctx)
+}
+
+// UpdateMemberRole changes the role of a member of the active organization. Admins can't touch owners,
+// and the last owner can't be demoted.
+func (s *Service) UpdateMemberRole(ctx context.Context, userID string, inp UpdateMemberRoleInput) error {
+	tenant, err := TenantFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !inp.Role.Valid() {
+		return ErrInvalidRole
+	}
+
+	return s.transactor.Transact(ctx, func(ctx context.Context) error {
+		member, err := s.repo.GetMember(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		if !tenant.Role.CanManage(member.Role) || !tenant.Role.CanManage(inp.Role) {
+			return ErrRoleNotPermitted
+		}
+
+		if member.Role == inp.Role {
+			return nil
+		}
+
+		if member.Role == RoleOwner {
+			if err := s.checkNotLastOwner(ctx); err != nil {
+				return err
+			}
+		}
+
+		prevRole := member.Role
+		member.Role = inp.Role
+		if err := s.repo.UpdateMembership(ctx, member); err != nil {
+			return err
+		}
+
+		return s.recordEvent(ctx, audit.EventMemberRoleChanged, userID, tenant.OrganizationID, audit.Metadata{
+			"previous_role": prevRole,
+			"role":          member.Role,
+		})
+	})
+}
+
+// RemoveMember removes a member from the active organization. Every member can leave on their own, removing
+// someone else requires a role that manages theirs. The last owner can't leave.
+func (s *Service) RemoveMember(ctx context.Context, userID string) error {
+	tenant, err := TenantFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.transactor.Transact(ctx, func(ctx context.Context) error {
+		member, err := s.repo.GetMember(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		self := member.UserID == tenant.UserID
+		if !self && !tenant.Role.CanManage(member.Role) {
+			return ErrRoleNotPermitted
+		}
+
+		if member.Role == RoleOwner {
+			if err := s.checkNotLastOwner(ctx); err != nil {
+				return err
+			}
+		}
+
+		if err := s.repo.DeleteMembership(ctx, userID); err != nil {
+			return err
+		}
+
+		return s.recordEvent(ctx, audit.EventMemberRemoved, userID, tenant.OrganizationID, audit.Metadata{
+			"role": member.Role,
+			"left": self,
+		})
+	})
+}
+
+// InviteMember mails an invitation to join the active organization with the role. Inviting an address again
+// replaces its unanswered invitations, so only the latest link works.
+func (s *Service) InviteMember(ctx context.Context, inp InviteMemberInput) (*Invitation, error) {
+	tenant, err := TenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !inp.Role.Valid() {
+		return nil, ErrInvalidRole
+	}
+
+	if !tenant.Role.CanManage(inp.Role) {
+		return nil, ErrRoleNotPermitted
+	}
+
+	invitee, err := s.userRepo.GetByEmail(ctx, inp.Email)
+	switch {
+	case err == nil:
+		if _, err := s.repo.GetMember(ctx, invitee.ID.String()); err == nil {
+			return nil, ErrAlreadyMember
+		} else if !errors.Is(err, ErrMemberNotFound) {
+			return nil, err
+		}
+	case !errors.Is(err, user.ErrNotFound):
+		return nil, err
+	}
+
+	org, err := s.repo.GetByID(ctx, tenant.OrganizationID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	inviter, err := s.userRepo.GetByID(ctx, tenant.UserID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	var inv *Invitation
+	err = s.transactor.Transact(ctx, func(ctx context.Context) error {
+		var (
+			token string
+			err   error
+		)
+		inv, token, err = NewInvitation(org.ID, inp.Email, inp.Role, inviter.ID, s.cfg.InvitationTTL)
+		if err != nil {
+			log.ErrorCtx(ctx, "Failed to create organization invitation", err)
+			return err
+		}
+
+		if err := s.repo.DeleteUnansweredInvitations(ctx, inv.Email); err != nil {
+			return err
+		}
+
+		if err := s.repo.StoreInvitation(ctx, inv); err != nil {
+			return err
+		}
+
+		if err := s.recordEvent(ctx, audit.EventMemberInvited, "", org.ID, audit.Metadata{
+			"invitation_id": inv.ID.String(),
+			"email":         inv.Email,
+			"role":          inv.Role,
+		}); err != nil {
+			return err
+		}
+
+		msg := InvitationEmailMessage{
+			To:               inv.Email,
+			OrganizationName: org.Name,
+			InviterName:      inviter.Name,
+			Role:             inv.Role,
+			InvitationURL:    s.cfg.InvitationEndpoint + "?token=" + token,
+			Expiry:           s.cfg.InvitationTTL,
+		}
+
+		return s.publisher.SendInvitationEmail(ctx, msg)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return inv, nil
+}
+
+// ListInvitations returns the pending invitations of the active organization.
+func (s *Service) ListInvitations(ctx context.Context) ([]*Invitation, error) {
+	tenant, err := TenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !tenant.Role.CanManageMembers() {
+		return nil, ErrRoleNotPermitted
+	}
+
+	return s.repo.ListPendingInvitations(ctx)
+}
+
+// RevokeInvitation deletes an invitation of the active organization, its link stops working.
+func (s *Service) RevokeInvitation(ctx context.Context, invitationID string) error {
+	tenant, err := TenantFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !tenant.Role.CanManageMembers() {
+		return ErrRoleNotPermitted
+	}
+
+	return s.transactor.Transact(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteInvitation(ctx, invitationID); err != nil {
+			return err
+		}
+
+		return s.recordEvent(ctx, audit.EventInvitationRevoked, "", tenant.OrganizationID, audit.Metadata{
+			"invitation_id": invitationID,
+		})
+	})
+}
+
+// AcceptInvitation makes the user a member of the organization the invitation is for. The invitation must have
+// been sent to the user's email address.
+func (s *Service) AcceptInvitation(
+	ctx context.Context,
+	userID string,
+	inp InvitationTokenInput,
+) (*Membership, error) {
+	usr, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var membership *Membership
+	err = s.transactor.Transact(ctx, func(ctx context.Context) error {
+		inv, err := s.pendingInvitation(ctx, inp.Token)
+		if err != nil {
+			return err
+		}
+
+		if !inv.For(usr.Email) {
+			return ErrInvitationEmailMismatch
+		}
+
+		inv.Accept()
+		if err := s.repo.UpdateInvitation(ctx, inv); err != nil {
+			return err
+		}
+
+		membership, err = NewMembership(inv.OrganizationID, usr.ID, inv.Role)
+		if err != nil {
+			return err
+		}
+
+		// Fails with ErrAlreadyMember, rolling back the acceptance
+		if err := s.repo.StoreMembership(ctx, membership); err != nil {
+			return err
+		}
+
+		return s.recordEvent(ctx, audit.EventInvitationAccepted, userID, inv.OrganizationID, audit.Metadata{
+			"invitation_id": inv.ID.String(),
+			"role":          inv.Role,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return membership, nil
+}
+
+// DeclineInvitation turns an invitation down. The token itself proves the invitee received it, so no login is
+// required.
+func (s *Service) DeclineInvitation(ctx context.Context, inp InvitationTokenInput) error {
+	return s.transactor.Transact(ctx, func(ctx context.Context) error {
+		inv, err := s.pendingInvitation(ctx, inp.Token)
+		if err != nil {
+			return err
+		}
+
+		inv.Decline()
+		if err := s.repo.UpdateInvitation(ctx, inv); err != nil {
+			return err
+		}
+
+		return s.recordEvent(ctx, audit.EventInvitationDeclined, "", inv.OrganizationID, audit.Metadata{
+			"invitation_id": inv.ID.String(),
+			"email":         inv.Email,
+		})
+	})
+}
+
+// pendingInvitation returns the invitation of the plain token, [ErrInvitationInvalid] unless it can still be
+// answered.
+func (s *Service) pendingInvitation(ctx context.Context, token string) (*Invitation, error) {
+	inv, err := s.repo.GetInvitationByTokenHash(ctx, HashInvitationToken(token))
+	if err != nil {
+		if errors.Is(err, ErrInvitationNotFound) {
+			return nil, ErrInvitationInvalid
+		}
+		return nil, err
+	}
+
+	if !inv.Pending() {
+		return nil, ErrInvitationInvalid
+	}
+
+	return inv, nil
+}
+
+// checkNotLastOwner returns [ErrLastOwner] if the active organization has a single owner left.
+func (s *Service) checkNotLastOwner(ctx context.Context) error {
+	owners, err := s.repo.CountOwners(ctx)
+	if err != nil {
+		return err
+	}
+
+	if owners <= 1 {
+		return ErrLastOwner
+	}
+
+	return nil
+}
+
+// recordEvent writes an audit event about the user within the organization, inside the transaction of ctx if any.
+func (s *Service) recordEvent(
+	ctx context.Context,
+	eventType audit.EventType,
+	userID string,
+	orgID uuid.UUID,
+	metadata audit.Metadata,
+) error {
+	if metadata == nil {
+		metadata = audit.Metadata{}
+	}
+	metadata["organization_id"] = orgID.String()
+
+	return audit.Record(ctx, s.auditor, eventType, userID, metadata)
+}
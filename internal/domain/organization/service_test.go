@@ -0,0 +1,293 @@
+package organization_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prawirdani/golang-restapi/config"
+	"github.com/prawirdani/golang-restapi/internal/domain/organization"
+	"github.com/prawirdani/golang-restapi/internal/domain/user"
+	"github.com/prawirdani/golang-restapi/internal/testing/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type serviceDeps struct {
+	transactor *mocks.Transactor
+	repo       *mocks.OrganizationRepository
+	userRepo   *mocks.UserRepository
+	publisher  *mocks.OrganizationMessagePublisher
+}
+
+func newTestService(t *testing.T) (*organization.Service, serviceDeps) {
+	deps := serviceDeps{
+		transactor: mocks.NewTransactor(t),
+		repo:       mocks.NewOrganizationRepository(t),
+		userRepo:   mocks.NewUserRepository(t),
+		publisher:  mocks.NewOrganizationMessagePublisher(t),
+	}
+
+	cfg := config.Organization{
+		InvitationTTL:      24 * time.Hour,
+		InvitationEndpoint: "https://app.example.com/invitations",
+	}
+
+	return organization.NewService(cfg, deps.transactor, deps.repo, deps.userRepo, deps.publisher, nil), deps
+}
+
+func expectTransact(deps serviceDeps) {
+	deps.transactor.EXPECT().
+		Transact(mock.Anything, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+}
+
+func tenantCtx(role organization.Role) (context.Context, *organization.Membership) {
+	tenant := &organization.Membership{
+		OrganizationID: uuid.New(),
+		UserID:         uuid.New(),
+		Role:           role,
+	}
+	return organization.WithTenant(context.Background(), tenant), tenant
+}
+
+func TestService_CreateOrganization(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("Success", func(t *testing.T) {
+		svc, deps := newTestService(t)
+		expectTransact(deps)
+
+		deps.repo.EXPECT().Store(mock.Anything, mock.AnythingOfType("*organization.Organization")).Return(nil)
+		deps.repo.EXPECT().
+			StoreMembership(mock.Anything, mock.MatchedBy(func(m *organization.Membership) bool {
+				return m.UserID == userID && m.Role == organization.RoleOwner
+			})).
+			Return(nil)
+
+		org, err := svc.CreateOrganization(ctx, userID.String(), organization.CreateOrganizationInput{
+			Name: "Acme Inc",
+			Slug: "acme",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "acme", org.Slug)
+		assert.Equal(t, organization.RoleOwner, org.Role)
+	})
+
+	t.Run("Invalid slug", func(t *testing.T) {
+		svc, _ := newTestService(t)
+
+		_, err := svc.CreateOrganization(ctx, userID.String(), organization.CreateOrganizationInput{
+			Name: "Acme Inc",
+			Slug: "a",
+		})
+		assert.ErrorIs(t, err, organization.ErrInvalidSlug)
+	})
+}
+
+func TestService_ResolveTenant(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New().String()
+	org := &organization.Organization{ID: uuid.New(), Name: "Acme Inc", Slug: "acme"}
+
+	t.Run("By slug", func(t *testing.T) {
+		svc, deps := newTestService(t)
+		membership := &organization.Membership{OrganizationID: org.ID, Role: organization.RoleMember}
+
+		deps.repo.EXPECT().GetBySlug(ctx, "acme").Return(org, nil)
+		deps.repo.EXPECT().GetMembership(ctx, org.ID.String(), userID).Return(membership, nil)
+
+		result, err := svc.ResolveTenant(ctx, userID, "acme")
+		require.NoError(t, err)
+		assert.Equal(t, membership, result)
+	})
+
+	t.Run("Unknown organization is not disclosed", func(t *testing.T) {
+		svc, deps := newTestService(t)
+		ref := uuid.New().String()
+
+		deps.repo.EXPECT().GetByID(ctx, ref).Return(nil, organization.ErrNotFound)
+
+		_, err := svc.ResolveTenant(ctx, userID, ref)
+		assert.ErrorIs(t, err, organization.ErrNotMember)
+	})
+}
+
+func TestService_UpdateMemberRole(t *testing.T) {
+	t.Run("No tenant", func(t *testing.T) {
+		svc, _ := newTestService(t)
+
+		err := svc.UpdateMemberRole(context.Background(), uuid.New().String(), organization.UpdateMemberRoleInput{
+			Role: organization.RoleAdmin,
+		})
+		assert.ErrorIs(t, err, organization.ErrNoTenant)
+	})
+
+	t.Run("Admin can't demote an owner", func(t *testing.T) {
+		svc, deps := newTestService(t)
+		ctx, tenant := tenantCtx(organization.RoleAdmin)
+		expectTransact(deps)
+
+		member := &organization.Membership{
+			OrganizationID: tenant.OrganizationID,
+			UserID:         uuid.New(),
+			Role:           organization.RoleOwner,
+		}
+		deps.repo.EXPECT().GetMember(mock.Anything, member.UserID.String()).Return(member, nil)
+
+		err := svc.UpdateMemberRole(ctx, member.UserID.String(), organization.UpdateMemberRoleInput{
+			Role: organization.RoleMember,
+		})
+		assert.ErrorIs(t, err, organization.ErrRoleNotPermitted)
+	})
+
+	t.Run("Last owner", func(t *testing.T) {
+		svc, deps := newTestService(t)
+		ctx, tenant := tenantCtx(organization.RoleOwner)
+		expectTransact(deps)
+
+		deps.repo.EXPECT().GetMember(mock.Anything, tenant.UserID.String()).Return(&organization.Membership{
+			OrganizationID: tenant.OrganizationID,
+			UserID:         tenant.UserID,
+			Role:           organization.RoleOwner,
+		}, nil)
+		deps.repo.EXPECT().CountOwners(mock.Anything).Return(1, nil)
+
+		err := svc.UpdateMemberRole(ctx, tenant.UserID.String(), organization.UpdateMemberRoleInput{
+			Role: organization.RoleAdmin,
+		})
+		assert.ErrorIs(t, err, organization.ErrLastOwner)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		svc, deps := newTestService(t)
+		ctx, tenant := tenantCtx(organization.RoleOwner)
+		expectTransact(deps)
+
+		member := &organization.Membership{
+			OrganizationID: tenant.OrganizationID,
+			UserID:         uuid.New(),
+			Role:           organization.RoleMember,
+		}
+		deps.repo.EXPECT().GetMember(mock.Anything, member.UserID.String()).Return(member, nil)
+		deps.repo.EXPECT().
+			UpdateMembership(mock.Anything, mock.MatchedBy(func(m *organization.Membership) bool {
+				return m.Role == organization.RoleAdmin
+			})).
+			Return(nil)
+
+		err := svc.UpdateMemberRole(ctx, member.UserID.String(), organization.UpdateMemberRoleInput{
+			Role: organization.RoleAdmin,
+		})
+		assert.NoError(t, err)
+	})
+}
+
+func TestService_InviteMember(t *testing.T) {
+	t.Run("Member can't invite", func(t *testing.T) {
+		svc, _ := newTestService(t)
+		ctx, _ := tenantCtx(organization.RoleMember)
+
+		_, err := svc.InviteMember(ctx, organization.InviteMemberInput{
+			Email: "jane@example.com",
+			Role:  organization.RoleMember,
+		})
+		assert.ErrorIs(t, err, organization.ErrRoleNotPermitted)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		svc, deps := newTestService(t)
+		ctx, tenant := tenantCtx(organization.RoleAdmin)
+		expectTransact(deps)
+
+		org := &organization.Organization{ID: tenant.OrganizationID, Name: "Acme Inc", Slug: "acme"}
+		inviter := &user.User{ID: tenant.UserID, Name: "John Doe", Email: "john@example.com"}
+
+		deps.userRepo.EXPECT().GetByEmail(ctx, "jane@example.com").Return(nil, user.ErrNotFound)
+		deps.repo.EXPECT().GetByID(ctx, org.ID.String()).Return(org, nil)
+		deps.userRepo.EXPECT().GetByID(ctx, inviter.ID.String()).Return(inviter, nil)
+		deps.repo.EXPECT().DeleteUnansweredInvitations(mock.Anything, "jane@example.com").Return(nil)
+		deps.repo.EXPECT().StoreInvitation(mock.Anything, mock.AnythingOfType("*organization.Invitation")).Return(nil)
+		deps.publisher.EXPECT().
+			SendInvitationEmail(mock.Anything, mock.MatchedBy(func(msg organization.InvitationEmailMessage) bool {
+				return msg.To == "jane@example.com" &&
+					msg.OrganizationName == "Acme Inc" &&
+					msg.InviterName == "John Doe"
+			})).
+			Return(nil)
+
+		inv, err := svc.InviteMember(ctx, organization.InviteMemberInput{
+			Email: "jane@example.com",
+			Role:  organization.RoleMember,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, org.ID, inv.OrganizationID)
+		assert.True(t, inv.Pending())
+	})
+}
+
+func TestService_AcceptInvitation(t *testing.T) {
+	ctx := context.Background()
+	usr := &user.User{ID: uuid.New(), Name: "Jane Doe", Email: "jane@example.com"}
+
+	t.Run("Email mismatch", func(t *testing.T) {
+		svc, deps := newTestService(t)
+		expectTransact(deps)
+
+		inv, token, err := organization.NewInvitation(
+			uuid.New(),
+			"other@example.com",
+			organization.RoleMember,
+			uuid.New(),
+			time.Hour,
+		)
+		require.NoError(t, err)
+
+		deps.userRepo.EXPECT().GetByID(ctx, usr.ID.String()).Return(usr, nil)
+		deps.repo.EXPECT().GetInvitationByTokenHash(mock.Anything, inv.TokenHash).Return(inv, nil)
+
+		_, err = svc.AcceptInvitation(ctx, usr.ID.String(), organization.InvitationTokenInput{Token: token})
+		assert.ErrorIs(t, err, organization.ErrInvitationEmailMismatch)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		svc, deps := newTestService(t)
+		expectTransact(deps)
+
+		inv, token, err := organization.NewInvitation(uuid.New(), usr.Email, organization.RoleAdmin, uuid.New(), time.Hour)
+		require.NoError(t, err)
+
+		deps.userRepo.EXPECT().GetByID(ctx, usr.ID.String()).Return(usr, nil)
+		deps.repo.EXPECT().GetInvitationByTokenHash(mock.Anything, inv.TokenHash).Return(inv, nil)
+		deps.repo.EXPECT().
+			UpdateInvitation(mock.Anything, mock.MatchedBy(func(i *organization.Invitation) bool {
+				return i.AcceptedAt.NotNull()
+			})).
+			Return(nil)
+		deps.repo.EXPECT().StoreMembership(mock.Anything, mock.AnythingOfType("*organization.Membership")).Return(nil)
+
+		membership, err := svc.AcceptInvitation(ctx, usr.ID.String(), organization.InvitationTokenInput{Token: token})
+		require.NoError(t, err)
+		assert.Equal(t, inv.OrganizationID, membership.OrganizationID)
+		assert.Equal(t, organization.RoleAdmin, membership.Role)
+	})
+}
+
+func TestService_DeclineInvitation(t *testing.T) {
+	t.Run("Unknown token", func(t *testing.T) {
+		svc, deps := newTestService(t)
+		expectTransact(deps)
+
+		deps.repo.EXPECT().
+			GetInvitationByTokenHash(mock.Anything, organization.HashInvitationToken("bogus")).
+			Return(nil, organization.ErrInvitationNotFound)
+
+		err := svc.DeclineInvitation(context.Background(), organization.InvitationTokenInput{Token: "bogus"})
+		assert.ErrorIs(t, err, organization.ErrInvitationInvalid)
+	})
+}
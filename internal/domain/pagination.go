@@ -0,0 +1,58 @@
+package domain
+
+const (
+	DefaultPerPage = 20
+	MaxPerPage     = 100
+)
+
+// PageRequest selects a page of a collection, pages are 1-based.
+type PageRequest struct {
+	Page    int
+	PerPage int
+}
+
+// Normalize returns a copy with out of range values replaced by the defaults.
+func (p PageRequest) Normalize() PageRequest {
+	if p.Page < 1 {
+		p.Page = 1
+	}
+	if p.PerPage < 1 {
+		p.PerPage = DefaultPerPage
+	}
+	if p.PerPage > MaxPerPage {
+		p.PerPage = MaxPerPage
+	}
+	return p
+}
+
+// Offset returns the number of items to skip.
+func (p PageRequest) Offset() int {
+	return (p.Page - 1) * p.PerPage
+}
+
+// Page is a page of a collection along with the total number of items across all pages.
+type Page[T any] struct {
+	Items []T
+	Total int
+	PageRequest
+}
+
+// NewPage creates a page from the items and the total count. Items is never nil so it encodes as an empty list.
+func NewPage[T any](items []T, total int, req PageRequest) *Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return &Page[T]{
+		Items:       items,
+		Total:       total,
+		PageRequest: req,
+	}
+}
+
+// TotalPages returns the number of pages needed to hold every item.
+func (p Page[T]) TotalPages() int {
+	if p.PerPage < 1 {
+		return 0
+	}
+	return (p.Total + p.PerPage - 1) / p.PerPage
+}
@@ -0,0 +1,52 @@
+// Package user provides the domain model and business logic for managing users in system.
+package user
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prawirdani/golang-restapi/internal/domain"
+)
+
+const listDateLayout = "2006-01-02"
+
+// ListUsersInput holds the raw query parameters of a user listing.
+type ListUsersInput struct {
+	Search      string `json:"search"       validate:"omitempty,max=100"`
+	Status      string `json:"status"       validate:"omitempty,oneof=active unverified suspended"`
+	CreatedFrom string `json:"created_from" validate:"omitempty,datetime=2006-01-02"`
+	CreatedTo   string `json:"created_to"   validate:"omitempty,datetime=2006-01-02"`
+	Page        string `json:"page"         validate:"omitempty,number"`
+	PerPage     string `json:"per_page"     validate:"omitempty,number"`
+}
+
+// Filter converts the validated input into a [ListFilter].
+func (i ListUsersInput) Filter() ListFilter {
+	f := ListFilter{
+		Search: i.Search,
+		Status: Status(i.Status),
+	}
+
+	if t, err := time.Parse(listDateLayout, i.CreatedFrom); err == nil {
+		f.CreatedFrom = t
+	}
+	// Inclusive of the whole end day
+	if t, err := time.Parse(listDateLayout, i.CreatedTo); err == nil {
+		f.CreatedTo = t.AddDate(0, 0, 1)
+	}
+
+	f.Page, _ = strconv.Atoi(i.Page)
+	f.PerPage, _ = strconv.Atoi(i.PerPage)
+	f.PageRequest = f.Normalize()
+
+	return f
+}
+
+// ListFilter narrows down a user listing, zero values are ignored.
+type ListFilter struct {
+	Search      string    // Case-insensitive match on name or email
+	Status      Status    // Lifecycle state of the account
+	CreatedFrom time.Time // Inclusive lower bound of the creation time
+	CreatedTo   time.Time // Exclusive upper bound of the creation time
+	domain.PageRequest
+}
@@ -0,0 +1,58 @@
+// Package user provides the domain model and business logic for managing users in system.
+package user
+
+import (
+	"github.com/prawirdani/golang-restapi/pkg/nullable"
+	"github.com/prawirdani/golang-restapi/pkg/strings"
+	"github.com/prawirdani/golang-restapi/pkg/validator"
+)
+
+// UpdateProfileInput is a partial profile update, nil fields are left untouched.
+// An empty Phone clears the phone number. Email changes are not applied directly,
+// they go through the confirmation flow of the auth service.
+type UpdateProfileInput struct {
+	Name  *string `json:"name"  validate:"omitnil,min=1,max=100"`
+	Phone *string `json:"phone" validate:"omitnil,max=30"`
+	Email *string `json:"email" validate:"omitnil,email,max=50"`
+}
+
+// Validate implements [handler.JSONRequestBody]
+func (i *UpdateProfileInput) Validate() error {
+	return validator.Struct(i)
+}
+
+// Sanitize implements [handler.JSONRequestBody]
+func (i *UpdateProfileInput) Sanitize() error {
+	if i.Name != nil {
+		name := strings.TrimSpacesConcat(*i.Name)
+		i.Name = &name
+	}
+	if i.Phone != nil {
+		phone := strings.TrimSpaces(*i.Phone)
+		i.Phone = &phone
+	}
+	if i.Email != nil {
+		email := strings.TrimSpaces(*i.Email)
+		i.Email = &email
+	}
+	return nil
+}
+
+// EmailChangeRequested reports whether the input asks for a different email address.
+func (i *UpdateProfileInput) EmailChangeRequested(current string) bool {
+	return i.Email != nil && *i.Email != current
+}
+
+// apply copies the provided profile fields onto the user, reporting whether anything changed.
+func (i *UpdateProfileInput) apply(u *User) bool {
+	var changed bool
+	if i.Name != nil && *i.Name != u.Name {
+		u.Name = *i.Name
+		changed = true
+	}
+	if i.Phone != nil && *i.Phone != u.Phone.Get() {
+		u.Phone = nullable.New(*i.Phone, false)
+		changed = true
+	}
+	return changed
+}
@@ -3,6 +3,7 @@ package user
 
 import (
 	"context"
+	"time"
 )
 
 // Repository defines the contract for user data persistence operations.
@@ -22,4 +23,21 @@ type Repository interface {
 	// Update modifies an existing user record.
 	// Returns [ErrEmailExists] if updating to an email that already exists.
 	Update(ctx context.Context, u *User) error
+
+	// Delete soft deletes a user, hiding it from every other lookup.
+	Delete(ctx context.Context, u *User) error
+
+	// ListDeleted retrieves up to limit users soft deleted before the given time
+	// that have not been purged yet, oldest first.
+	ListDeleted(ctx context.Context, deletedBefore time.Time, limit int) ([]*User, error)
+
+	// HardDelete permanently removes a user record along with its dependent records.
+	HardDelete(ctx context.Context, userID string) error
+
+	// Anonymize persists an anonymized soft deleted user and marks it as purged.
+	Anonymize(ctx context.Context, u *User) error
+
+	// List retrieves a page of users matching the filter, newest first,
+	// along with the total number of matching users.
+	List(ctx context.Context, filter ListFilter) ([]*User, int, error)
 }
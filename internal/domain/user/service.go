@@ -4,8 +4,11 @@ package user
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/prawirdani/golang-restapi/internal/domain"
+	"github.com/prawirdani/golang-restapi/internal/domain/audit"
 	"github.com/prawirdani/golang-restapi/internal/infrastructure/repository"
 	"github.com/prawirdani/golang-restapi/internal/infrastructure/storage"
 	"github.com/prawirdani/golang-restapi/pkg/log"
@@ -15,17 +18,20 @@ type Service struct {
 	transactor   repository.Transactor
 	userRepo     Repository
 	imageStorage storage.Storage
+	auditor      audit.Writer
 }
 
 func NewService(
 	transactor repository.Transactor,
 	userRepo Repository,
 	imageStorage storage.Storage,
+	auditor audit.Writer,
 ) *Service {
 	return &Service{
 		transactor:   transactor,
 		userRepo:     userRepo,
 		imageStorage: imageStorage,
+		auditor:      auditor,
 	}
 }
 
@@ -55,6 +61,38 @@ func (s *Service) GetUserByEmail(ctx context.Context, email string) (*User, erro
 	return u, nil
 }
 
+// UpdateProfile applies a partial update of the user's name and phone, returning the updated user.
+// The email field of the input is ignored, see [UpdateProfileInput].
+func (s *Service) UpdateProfile(ctx context.Context, userID string, inp UpdateProfileInput) (*User, error) {
+	var u *User
+	err := s.transactor.Transact(ctx, func(ctx context.Context) error {
+		var err error
+		u, err = s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		if !inp.apply(u) {
+			return nil
+		}
+
+		if err := s.userRepo.Update(ctx, u); err != nil {
+			return err
+		}
+
+		return audit.Record(ctx, s.auditor, audit.EventProfileUpdated, userID, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.assignProfileImageURL(ctx, u); err != nil {
+		return nil, err
+	}
+
+	return u, nil
+}
+
 func (s *Service) ChangeProfilePicture(
 	ctx context.Context,
 	userID string,
@@ -98,6 +136,10 @@ func (s *Service) ChangeProfilePicture(
 			return err
 		}
 
+		if err := audit.Record(ctx, s.auditor, audit.EventProfilePictureChanged, userID, nil); err != nil {
+			return err
+		}
+
 		// -- Cleanup old image (Non Fatal: Should not rollback if error)
 		go func(prevImage string) {
 			if prevImage != "" {
@@ -111,6 +153,92 @@ func (s *Service) ChangeProfilePicture(
 	})
 }
 
+// ListUsers returns a page of users matching the filter.
+func (s *Service) ListUsers(ctx context.Context, filter ListFilter) (*domain.Page[*User], error) {
+	filter.PageRequest = filter.Normalize()
+
+	users, total, err := s.userRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, u := range users {
+		if err := s.assignProfileImageURL(ctx, u); err != nil {
+			return nil, err
+		}
+	}
+
+	return domain.NewPage(users, total, filter.PageRequest), nil
+}
+
+// UnsuspendUser lifts the suspension of the user.
+func (s *Service) UnsuspendUser(ctx context.Context, userID string) error {
+	return s.transactor.Transact(ctx, func(ctx context.Context) error {
+		u, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		if err := u.Unsuspend(); err != nil {
+			return err
+		}
+
+		if err := s.userRepo.Update(ctx, u); err != nil {
+			return err
+		}
+
+		return audit.Record(ctx, s.auditor, audit.EventAccountUnsuspended, userID, nil)
+	})
+}
+
+// purgeBatchSize caps the number of users purged per [Service.PurgeDeletedUsers] call.
+const purgeBatchSize = 100
+
+// PurgeDeletedUsers permanently deletes or anonymizes users whose soft deletion is older than
+// the grace period, removing their profile images from storage. Returns the number of purged users.
+// A failure on one user is logged and does not stop the rest of the batch.
+func (s *Service) PurgeDeletedUsers(
+	ctx context.Context,
+	gracePeriod time.Duration,
+	mode PurgeMode,
+) (int, error) {
+	users, err := s.userRepo.ListDeleted(ctx, time.Now().Add(-gracePeriod), purgeBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var purged int
+	for _, u := range users {
+		if err := s.purgeUser(ctx, u, mode); err != nil {
+			log.ErrorCtx(ctx, "Failed to purge deleted user", err, "user_id", u.ID.String())
+			continue
+		}
+		purged++
+	}
+
+	return purged, nil
+}
+
+func (s *Service) purgeUser(ctx context.Context, u *User, mode PurgeMode) error {
+	if u.ProfileImage.Valid() {
+		if err := s.imageStorage.Delete(ctx, s.buildProfileImagePath(u.ProfileImage.Get())); err != nil {
+			return fmt.Errorf("delete profile image: %w", err)
+		}
+	}
+
+	userID := u.ID.String()
+	if mode == PurgeAnonymize {
+		u.Anonymize()
+		if err := s.userRepo.Anonymize(ctx, u); err != nil {
+			return err
+		}
+	} else if err := s.userRepo.HardDelete(ctx, userID); err != nil {
+		return err
+	}
+
+	return audit.Record(ctx, s.auditor, audit.EventAccountPurged, userID, audit.Metadata{"mode": mode})
+}
+
 // imageName + ext
 func (s *Service) buildProfileImagePath(imageName string) string {
 	return fmt.Sprintf("profiles/%s", imageName)
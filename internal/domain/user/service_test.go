@@ -20,7 +20,7 @@ func TestNewUserService(t *testing.T) {
 	mockUserRepo := mocks.NewUserRepository(t)
 	mockImageStorage := mocks.NewStorage(t)
 
-	service := user.NewService(mockTransactor, mockUserRepo, mockImageStorage)
+	service := user.NewService(mockTransactor, mockUserRepo, mockImageStorage, nil)
 
 	require.NotNil(t, service)
 }
@@ -33,7 +33,7 @@ func TestUserService_GetUserByID(t *testing.T) {
 		mockUserRepo := mocks.NewUserRepository(t)
 		mockImageStorage := mocks.NewStorage(t)
 
-		service := user.NewService(mockTransactor, mockUserRepo, mockImageStorage)
+		service := user.NewService(mockTransactor, mockUserRepo, mockImageStorage, nil)
 
 		userID := uuid.New().String()
 		expectedUser := &user.User{
@@ -59,7 +59,7 @@ func TestUserService_GetUserByID(t *testing.T) {
 		mockUserRepo := mocks.NewUserRepository(t)
 		mockImageStorage := mocks.NewStorage(t)
 
-		service := user.NewService(mockTransactor, mockUserRepo, mockImageStorage)
+		service := user.NewService(mockTransactor, mockUserRepo, mockImageStorage, nil)
 
 		userID := uuid.New().String()
 		expectedUser := &user.User{
@@ -88,7 +88,7 @@ func TestUserService_GetUserByID(t *testing.T) {
 		mockUserRepo := mocks.NewUserRepository(t)
 		mockImageStorage := mocks.NewStorage(t)
 
-		service := user.NewService(mockTransactor, mockUserRepo, mockImageStorage)
+		service := user.NewService(mockTransactor, mockUserRepo, mockImageStorage, nil)
 
 		userID := uuid.New()
 		repoError := user.ErrNotFound
@@ -106,7 +106,7 @@ func TestUserService_GetUserByID(t *testing.T) {
 		mockUserRepo := mocks.NewUserRepository(t)
 		mockImageStorage := mocks.NewStorage(t)
 
-		service := user.NewService(mockTransactor, mockUserRepo, mockImageStorage)
+		service := user.NewService(mockTransactor, mockUserRepo, mockImageStorage, nil)
 
 		userID := uuid.New().String()
 		expectedUser := &user.User{
@@ -139,7 +139,7 @@ func TestUserService_GetUserByEmail(t *testing.T) {
 		mockUserRepo := mocks.NewUserRepository(t)
 		mockImageStorage := mocks.NewStorage(t)
 
-		service := user.NewService(mockTransactor, mockUserRepo, mockImageStorage)
+		service := user.NewService(mockTransactor, mockUserRepo, mockImageStorage, nil)
 
 		email := "john@example.com"
 		expectedUser := &user.User{
@@ -165,7 +165,7 @@ func TestUserService_GetUserByEmail(t *testing.T) {
 		mockUserRepo := mocks.NewUserRepository(t)
 		mockImageStorage := mocks.NewStorage(t)
 
-		service := user.NewService(mockTransactor, mockUserRepo, mockImageStorage)
+		service := user.NewService(mockTransactor, mockUserRepo, mockImageStorage, nil)
 
 		email := "john@example.com"
 		expectedUser := &user.User{
@@ -194,7 +194,7 @@ func TestUserService_GetUserByEmail(t *testing.T) {
 		mockUserRepo := mocks.NewUserRepository(t)
 		mockImageStorage := mocks.NewStorage(t)
 
-		service := user.NewService(mockTransactor, mockUserRepo, mockImageStorage)
+		service := user.NewService(mockTransactor, mockUserRepo, mockImageStorage, nil)
 
 		email := "nonexistent@example.com"
 		repoError := user.ErrNotFound
@@ -212,7 +212,7 @@ func TestUserService_GetUserByEmail(t *testing.T) {
 		mockUserRepo := mocks.NewUserRepository(t)
 		mockImageStorage := mocks.NewStorage(t)
 
-		service := user.NewService(mockTransactor, mockUserRepo, mockImageStorage)
+		service := user.NewService(mockTransactor, mockUserRepo, mockImageStorage, nil)
 
 		email := "john@example.com"
 		expectedUser := &user.User{
@@ -246,7 +246,7 @@ func TestUserService_ChangeProfilePicture(t *testing.T) {
 		mockImageStorage := mocks.NewStorage(t)
 		mockFile := mocks.NewFile(t)
 
-		service := user.NewService(mockTransactor, mockUserRepo, mockImageStorage)
+		service := user.NewService(mockTransactor, mockUserRepo, mockImageStorage, nil)
 
 		userID := uuid.New().String()
 		newFileName := "new-profile.jpg"
@@ -290,7 +290,7 @@ func TestUserService_ChangeProfilePicture(t *testing.T) {
 		mockImageStorage := mocks.NewStorage(t)
 		mockFile := mocks.NewFile(t)
 
-		service := user.NewService(mockTransactor, mockUserRepo, mockImageStorage)
+		service := user.NewService(mockTransactor, mockUserRepo, mockImageStorage, nil)
 
 		userID := uuid.New().String()
 		repoError := user.ErrNotFound
@@ -308,7 +308,7 @@ func TestUserService_ChangeProfilePicture(t *testing.T) {
 		mockImageStorage := mocks.NewStorage(t)
 		mockFile := mocks.NewFile(t)
 
-		service := user.NewService(mockTransactor, mockUserRepo, mockImageStorage)
+		service := user.NewService(mockTransactor, mockUserRepo, mockImageStorage, nil)
 
 		userID := uuid.New().String()
 		fileError := errors.New("file error")
@@ -347,7 +347,7 @@ func TestUserService_ChangeProfilePicture(t *testing.T) {
 		mockImageStorage := mocks.NewStorage(t)
 		mockFile := mocks.NewFile(t)
 
-		service := user.NewService(mockTransactor, mockUserRepo, mockImageStorage)
+		service := user.NewService(mockTransactor, mockUserRepo, mockImageStorage, nil)
 
 		userID := uuid.New().String()
 		newFileName := "new-profile.jpg"
@@ -391,7 +391,7 @@ func TestUserService_ChangeProfilePicture(t *testing.T) {
 		mockImageStorage := mocks.NewStorage(t)
 		mockFile := mocks.NewFile(t)
 
-		service := user.NewService(mockTransactor, mockUserRepo, mockImageStorage)
+		service := user.NewService(mockTransactor, mockUserRepo, mockImageStorage, nil)
 
 		userID := uuid.New().String()
 		newFileName := "new-profile.jpg"
@@ -439,7 +439,7 @@ func TestUserService_ChangeProfilePicture(t *testing.T) {
 		mockImageStorage := mocks.NewStorage(t)
 		mockFile := mocks.NewFile(t)
 
-		service := user.NewService(mockTransactor, mockUserRepo, mockImageStorage)
+		service := user.NewService(mockTransactor, mockUserRepo, mockImageStorage, nil)
 
 		userID := uuid.New().String()
 		transactError := errors.New("transaction error")
@@ -451,3 +451,235 @@ func TestUserService_ChangeProfilePicture(t *testing.T) {
 		assert.Equal(t, transactError, err)
 	})
 }
+
+func TestUserService_ListUsers(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success normalizes page request", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockImageStorage := mocks.NewStorage(t)
+
+		service := user.NewService(mockTransactor, mockUserRepo, mockImageStorage, nil)
+
+		users := []*user.User{
+			{ID: uuid.New(), Name: "John Doe", Email: "john@example.com"},
+			{ID: uuid.New(), Name: "Jane Doe", Email: "jane@example.com"},
+		}
+		filter := user.ListFilter{Search: "doe"}
+
+		mockUserRepo.EXPECT().
+			List(ctx, mock.MatchedBy(func(f user.ListFilter) bool {
+				return f.Search == "doe" && f.Page == 1 && f.PerPage == 20
+			})).
+			Return(users, 42, nil)
+
+		page, err := service.ListUsers(ctx, filter)
+		require.NoError(t, err)
+		assert.Equal(t, users, page.Items)
+		assert.Equal(t, 42, page.Total)
+		assert.Equal(t, 3, page.TotalPages())
+	})
+
+	t.Run("Empty result encodes as empty list", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockImageStorage := mocks.NewStorage(t)
+
+		service := user.NewService(mockTransactor, mockUserRepo, mockImageStorage, nil)
+
+		mockUserRepo.EXPECT().List(ctx, mock.Anything).Return(nil, 0, nil)
+
+		page, err := service.ListUsers(ctx, user.ListFilter{})
+		require.NoError(t, err)
+		assert.NotNil(t, page.Items)
+		assert.Empty(t, page.Items)
+		assert.Equal(t, 0, page.TotalPages())
+	})
+}
+
+func TestUserService_UnsuspendUser(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New().String()
+
+	mockTransactor := mocks.NewTransactor(t)
+	mockUserRepo := mocks.NewUserRepository(t)
+	mockImageStorage := mocks.NewStorage(t)
+
+	service := user.NewService(mockTransactor, mockUserRepo, mockImageStorage, nil)
+
+	existingUser := &user.User{ID: uuid.New(), Name: "John Doe", Email: "john@example.com"}
+	require.NoError(t, existingUser.Suspend())
+
+	mockTransactor.EXPECT().
+		Transact(ctx, mock.AnythingOfType("func(context.Context) error")).
+		Run(func(ctx context.Context, fn func(context.Context) error) {
+			err := fn(ctx)
+			assert.NoError(t, err)
+		}).
+		Return(nil)
+	mockUserRepo.EXPECT().GetByID(ctx, userID).Return(existingUser, nil)
+	mockUserRepo.EXPECT().
+		Update(ctx, mock.MatchedBy(func(u *user.User) bool { return !u.IsSuspended() })).
+		Return(nil)
+
+	err := service.UnsuspendUser(ctx, userID)
+	assert.NoError(t, err)
+}
+
+func TestUserService_PurgeDeletedUsers(t *testing.T) {
+	ctx := context.Background()
+	gracePeriod := 30 * 24 * time.Hour
+
+	t.Run("Hard delete removes profile image", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockImageStorage := mocks.NewStorage(t)
+
+		service := user.NewService(mockTransactor, mockUserRepo, mockImageStorage, nil)
+
+		withImage := &user.User{ID: uuid.New(), ProfileImage: nullable.New("avatar.png", false)}
+		withoutImage := &user.User{ID: uuid.New()}
+
+		mockUserRepo.EXPECT().
+			ListDeleted(ctx, mock.AnythingOfType("time.Time"), mock.AnythingOfType("int")).
+			Return([]*user.User{withImage, withoutImage}, nil)
+		mockImageStorage.EXPECT().Delete(ctx, "profiles/avatar.png").Return(nil)
+		mockUserRepo.EXPECT().HardDelete(ctx, withImage.ID.String()).Return(nil)
+		mockUserRepo.EXPECT().HardDelete(ctx, withoutImage.ID.String()).Return(nil)
+
+		purged, err := service.PurgeDeletedUsers(ctx, gracePeriod, user.PurgeDelete)
+		require.NoError(t, err)
+		assert.Equal(t, 2, purged)
+	})
+
+	t.Run("Anonymize", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockImageStorage := mocks.NewStorage(t)
+
+		service := user.NewService(mockTransactor, mockUserRepo, mockImageStorage, nil)
+
+		deletedUser := &user.User{ID: uuid.New(), Name: "John Doe", Email: "john@example.com"}
+
+		mockUserRepo.EXPECT().
+			ListDeleted(ctx, mock.AnythingOfType("time.Time"), mock.AnythingOfType("int")).
+			Return([]*user.User{deletedUser}, nil)
+		mockUserRepo.EXPECT().
+			Anonymize(ctx, mock.MatchedBy(func(u *user.User) bool { return u.Name == "Deleted User" })).
+			Return(nil)
+
+		purged, err := service.PurgeDeletedUsers(ctx, gracePeriod, user.PurgeAnonymize)
+		require.NoError(t, err)
+		assert.Equal(t, 1, purged)
+	})
+
+	t.Run("Failed user does not stop the batch", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockImageStorage := mocks.NewStorage(t)
+
+		service := user.NewService(mockTransactor, mockUserRepo, mockImageStorage, nil)
+
+		failing := &user.User{ID: uuid.New(), ProfileImage: nullable.New("avatar.png", false)}
+		ok := &user.User{ID: uuid.New()}
+
+		mockUserRepo.EXPECT().
+			ListDeleted(ctx, mock.AnythingOfType("time.Time"), mock.AnythingOfType("int")).
+			Return([]*user.User{failing, ok}, nil)
+		mockImageStorage.EXPECT().Delete(ctx, "profiles/avatar.png").Return(errors.New("storage down"))
+		mockUserRepo.EXPECT().HardDelete(ctx, ok.ID.String()).Return(nil)
+
+		purged, err := service.PurgeDeletedUsers(ctx, gracePeriod, user.PurgeDelete)
+		require.NoError(t, err)
+		assert.Equal(t, 1, purged)
+	})
+}
+
+func TestUserService_UpdateProfile(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New().String()
+
+	txRun := func(t *testing.T, mockTransactor *mocks.Transactor) {
+		mockTransactor.EXPECT().
+			Transact(ctx, mock.AnythingOfType("func(context.Context) error")).
+			Run(func(ctx context.Context, fn func(context.Context) error) {
+				err := fn(ctx)
+				assert.NoError(t, err)
+			}).
+			Return(nil)
+	}
+
+	t.Run("Success partial update", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockImageStorage := mocks.NewStorage(t)
+
+		service := user.NewService(mockTransactor, mockUserRepo, mockImageStorage, nil)
+
+		existingUser := &user.User{
+			ID:    uuid.New(),
+			Name:  "John Doe",
+			Email: "john@example.com",
+			Phone: nullable.New("123456789", false),
+		}
+		name := "Johnny Doe"
+
+		txRun(t, mockTransactor)
+		mockUserRepo.EXPECT().GetByID(ctx, userID).Return(existingUser, nil)
+		mockUserRepo.EXPECT().
+			Update(ctx, mock.MatchedBy(func(u *user.User) bool {
+				return u.Name == name && u.Phone.Get() == "123456789"
+			})).
+			Return(nil)
+
+		result, err := service.UpdateProfile(ctx, userID, user.UpdateProfileInput{Name: &name})
+		require.NoError(t, err)
+		assert.Equal(t, name, result.Name)
+	})
+
+	t.Run("Empty phone clears it", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockImageStorage := mocks.NewStorage(t)
+
+		service := user.NewService(mockTransactor, mockUserRepo, mockImageStorage, nil)
+
+		existingUser := &user.User{
+			ID:    uuid.New(),
+			Name:  "John Doe",
+			Email: "john@example.com",
+			Phone: nullable.New("123456789", false),
+		}
+		phone := ""
+
+		txRun(t, mockTransactor)
+		mockUserRepo.EXPECT().GetByID(ctx, userID).Return(existingUser, nil)
+		mockUserRepo.EXPECT().
+			Update(ctx, mock.MatchedBy(func(u *user.User) bool { return !u.Phone.NotNull() })).
+			Return(nil)
+
+		_, err := service.UpdateProfile(ctx, userID, user.UpdateProfileInput{Phone: &phone})
+		require.NoError(t, err)
+	})
+
+	t.Run("Email only does not touch the user", func(t *testing.T) {
+		mockTransactor := mocks.NewTransactor(t)
+		mockUserRepo := mocks.NewUserRepository(t)
+		mockImageStorage := mocks.NewStorage(t)
+
+		service := user.NewService(mockTransactor, mockUserRepo, mockImageStorage, nil)
+
+		existingUser := &user.User{ID: uuid.New(), Name: "John Doe", Email: "john@example.com"}
+		email := "johnny@example.com"
+
+		txRun(t, mockTransactor)
+		mockUserRepo.EXPECT().GetByID(ctx, userID).Return(existingUser, nil)
+
+		inp := user.UpdateProfileInput{Email: &email}
+		result, err := service.UpdateProfile(ctx, userID, inp)
+		require.NoError(t, err)
+		assert.Equal(t, "john@example.com", result.Email)
+		assert.True(t, inp.EmailChangeRequested(result.Email))
+	})
+}
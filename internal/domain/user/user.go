@@ -2,6 +2,7 @@
 package user
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,17 +17,45 @@ var (
 	ErrEmailExists      = domain.ErrDuplicate("Email already exists")
 	ErrNotFound         = domain.ErrNotFound("User not found")
 	ErrEmailNotVerified = domain.ErrForbidden("Email is not registered or not verified")
+	ErrSuspended        = domain.ErrForbidden("Account is suspended, please contact support")
+	ErrAlreadySuspended = domain.ErrValidation("User is already suspended")
+	ErrNotSuspended     = domain.ErrValidation("User is not suspended")
 )
 
+// Status is the lifecycle state of a user account.
+type Status string
+
+const (
+	StatusActive     Status = "active"
+	StatusUnverified Status = "unverified" // Email address not verified yet
+	StatusSuspended  Status = "suspended"  // Blocked from signing in by an administrator
+)
+
+// PurgeMode decides what happens to a soft deleted user once its grace period is over.
+type PurgeMode string
+
+const (
+	PurgeDelete    PurgeMode = "delete"    // Remove the row permanently
+	PurgeAnonymize PurgeMode = "anonymize" // Keep the row but strip every personal detail
+)
+
+// User is a registered account. PasswordResetRequired blocks sign in until the password is reset,
+// it is set when an administrator forces a password reset.
 type User struct {
-	ID           uuid.UUID                 `db:"id"            json:"id"`
-	Name         string                    `db:"name"          json:"name"`
-	Email        string                    `db:"email"         json:"email"`
-	Password     string                    `db:"password"      json:"-"`
-	Phone        nullable.Nullable[string] `db:"phone"         json:"phone"`
-	ProfileImage nullable.Nullable[string] `db:"profile_image" json:"profile_image"`
-	CreatedAt    time.Time                 `db:"created_at"    json:"created_at"`
-	UpdatedAt    time.Time                 `db:"updated_at"    json:"updated_at"`
+	ID                    uuid.UUID                    `db:"id"                      json:"id"`
+	Name                  string                       `db:"name"                    json:"name"`
+	Email                 string                       `db:"email"                   json:"email"`
+	Password              string                       `db:"password"                json:"-"`
+	Phone                 nullable.Nullable[string]    `db:"phone"                   json:"phone"`
+	ProfileImage          nullable.Nullable[string]    `db:"profile_image"           json:"profile_image"`
+	VerifiedAt            nullable.Nullable[time.Time] `db:"verified_at"             json:"verified_at"`
+	SuspendedAt           nullable.Nullable[time.Time] `db:"suspended_at"            json:"suspended_at"`
+	PasswordResetRequired bool                         `db:"password_reset_required" json:"password_reset_required"`
+	CreatedAt             time.Time                    `db:"created_at"              json:"created_at"`
+	UpdatedAt             time.Time                    `db:"updated_at"              json:"updated_at"`
+	DeletedAt             nullable.Nullable[time.Time] `db:"deleted_at"              json:"-"`
+
+	passwordless bool // Signed up through an external identity provider, no local password required
 }
 
 func (u *User) Validate() error {
@@ -36,13 +65,86 @@ func (u *User) Validate() error {
 	if u.Email == "" {
 		return ErrRequiredEmail
 	}
-	if u.Password == "" {
+	if u.Password == "" && !u.passwordless {
 		return ErrRequiredPassword
 	}
 
 	return nil
 }
 
+// HasPassword reports whether the user has a local password to sign in with. Users created through an external
+// identity provider have none until they set one through the reset password flow.
+func (u *User) HasPassword() bool {
+	return u.Password != ""
+}
+
+// ClearPassword removes the local password, leaving the user to sign in through their external identities until
+// they set one through the reset password flow.
+func (u *User) ClearPassword() {
+	u.Password = ""
+	u.passwordless = true
+}
+
+// IsVerified reports whether the user has confirmed ownership of their email address.
+func (u *User) IsVerified() bool {
+	return u.VerifiedAt.NotNull()
+}
+
+// MarkVerified marks the user's email address as verified at the current time.
+func (u *User) MarkVerified() {
+	u.VerifiedAt = nullable.New(time.Now(), false)
+}
+
+// IsSuspended reports whether the account has been suspended.
+func (u *User) IsSuspended() bool {
+	return u.SuspendedAt.NotNull()
+}
+
+// Suspend blocks the account from signing in.
+func (u *User) Suspend() error {
+	if u.IsSuspended() {
+		return ErrAlreadySuspended
+	}
+	u.SuspendedAt = nullable.New(time.Now(), false)
+	return nil
+}
+
+// Unsuspend lifts a suspension.
+func (u *User) Unsuspend() error {
+	if !u.IsSuspended() {
+		return ErrNotSuspended
+	}
+	u.SuspendedAt = nullable.New(time.Time{}, false)
+	return nil
+}
+
+// Status returns the current lifecycle state of the account.
+func (u *User) Status() Status {
+	switch {
+	case u.IsSuspended():
+		return StatusSuspended
+	case !u.IsVerified():
+		return StatusUnverified
+	default:
+		return StatusActive
+	}
+}
+
+// IsDeleted reports whether the account has been soft deleted.
+func (u *User) IsDeleted() bool {
+	return u.DeletedAt.NotNull()
+}
+
+// Anonymize strips every personal detail from the user. The email is replaced with an
+// undeliverable address derived from the ID so it stays unique.
+func (u *User) Anonymize() {
+	u.Name = "Deleted User"
+	u.Email = fmt.Sprintf("deleted-%s@anonymized.invalid", u.ID)
+	u.Phone = nullable.New("", false)
+	u.Password = ""
+	u.ProfileImage = nullable.New("", false)
+}
+
 // New creates new user, returns an error if validation fails.
 func New(name, email, phone, hashedPassword string) (*User, error) {
 	id, err := uuid.NewV7()
@@ -67,3 +169,29 @@ func New(name, email, phone, hashedPassword string) (*User, error) {
 
 	return &u, nil
 }
+
+// NewExternal creates a new user signing up through an external identity provider. The user has no local
+// password, and the email address is already verified by the provider.
+func NewExternal(name, email string) (*User, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	u := User{
+		ID:           id,
+		Name:         name,
+		Email:        email,
+		VerifiedAt:   nullable.New(now, false),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		passwordless: true,
+	}
+
+	if err := u.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &u, nil
+}
@@ -2,8 +2,10 @@ package user
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/prawirdani/golang-restapi/internal/domain"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -155,3 +157,89 @@ func TestNew(t *testing.T) {
 		})
 	}
 }
+
+func TestUser_MarkVerified(t *testing.T) {
+	u, err := New("John Doe", "john@example.com", "", "hashedpassword")
+	require.NoError(t, err)
+	assert.False(t, u.IsVerified())
+
+	u.MarkVerified()
+	assert.True(t, u.IsVerified())
+	assert.False(t, u.VerifiedAt.Get().IsZero())
+}
+
+func TestNewExternal(t *testing.T) {
+	u, err := NewExternal("John Doe", "john@example.com")
+	require.NoError(t, err)
+	assert.False(t, u.HasPassword())
+	assert.True(t, u.IsVerified())
+	assert.Equal(t, StatusActive, u.Status())
+
+	_, err = NewExternal("John Doe", "")
+	assert.ErrorIs(t, err, ErrRequiredEmail)
+}
+
+func TestUser_ClearPassword(t *testing.T) {
+	u, err := New("John Doe", "john@example.com", "", "hashedpassword")
+	require.NoError(t, err)
+	assert.True(t, u.HasPassword())
+
+	u.ClearPassword()
+	assert.False(t, u.HasPassword())
+	assert.NoError(t, u.Validate())
+}
+
+func TestUser_Suspend(t *testing.T) {
+	u, err := New("John Doe", "john@example.com", "", "hashedpassword")
+	require.NoError(t, err)
+	assert.Equal(t, StatusUnverified, u.Status())
+
+	u.MarkVerified()
+	assert.Equal(t, StatusActive, u.Status())
+
+	require.NoError(t, u.Suspend())
+	assert.True(t, u.IsSuspended())
+	assert.Equal(t, StatusSuspended, u.Status())
+	assert.ErrorIs(t, u.Suspend(), ErrAlreadySuspended)
+
+	require.NoError(t, u.Unsuspend())
+	assert.False(t, u.IsSuspended())
+	assert.Equal(t, StatusActive, u.Status())
+	assert.ErrorIs(t, u.Unsuspend(), ErrNotSuspended)
+}
+
+func TestListUsersInput_Filter(t *testing.T) {
+	inp := ListUsersInput{
+		Search:      "john",
+		Status:      "active",
+		CreatedFrom: "2024-01-01",
+		CreatedTo:   "2024-01-31",
+		Page:        "2",
+		PerPage:     "500",
+	}
+
+	filter := inp.Filter()
+	assert.Equal(t, "john", filter.Search)
+	assert.Equal(t, StatusActive, filter.Status)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), filter.CreatedFrom)
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), filter.CreatedTo)
+
+	req := filter.Normalize()
+	assert.Equal(t, 2, req.Page)
+	assert.Equal(t, domain.MaxPerPage, req.PerPage)
+	assert.Equal(t, domain.MaxPerPage, req.Offset())
+}
+
+func TestUser_Anonymize(t *testing.T) {
+	u, err := New("John Doe", "john@example.com", "08123456789", "hashedpassword")
+	require.NoError(t, err)
+	u.ProfileImage.Set("avatar.png", false)
+
+	u.Anonymize()
+
+	assert.Equal(t, "Deleted User", u.Name)
+	assert.Equal(t, "deleted-"+u.ID.String()+"@anonymized.invalid", u.Email)
+	assert.Empty(t, u.Password)
+	assert.False(t, u.Phone.Valid())
+	assert.False(t, u.ProfileImage.Valid())
+}
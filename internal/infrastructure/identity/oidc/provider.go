@@ -0,0 +1,107 @@
+// Package oidc implements an OpenID Connect relying party, letting users sign in with external
+// identity providers through the authorization code flow with PKCE.
+package oidc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/prawirdani/golang-restapi/config"
+	"github.com/prawirdani/golang-restapi/internal/domain/auth"
+	"github.com/prawirdani/golang-restapi/pkg/log"
+	"golang.org/x/oauth2"
+)
+
+// Provider is an external OpenID Connect provider, Implements auth.IdentityProvider interface
+type Provider struct {
+	name     string
+	oauth    oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+// New discovers the provider endpoints and signing keys from its issuer.
+func New(ctx context.Context, cfg config.OIDCProvider) (*Provider, error) {
+	p, err := oidc.NewProvider(ctx, cfg.IssuerURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover oidc provider %s: %w", cfg.Name, err)
+	}
+
+	return &Provider{
+		name: cfg.Name,
+		oauth: oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+			Endpoint:     p.Endpoint(),
+			Scopes:       append([]string{oidc.ScopeOpenID}, cfg.Scopes...),
+		},
+		verifier: p.Verifier(&oidc.Config{ClientID: cfg.ClientID}),
+	}, nil
+}
+
+// NewProviders creates every configured provider.
+func NewProviders(ctx context.Context, cfgs []config.OIDCProvider) ([]auth.IdentityProvider, error) {
+	providers := make([]auth.IdentityProvider, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		p, err := New(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, p)
+	}
+	return providers, nil
+}
+
+// Name implements auth.IdentityProvider
+func (p *Provider) Name() string {
+	return p.name
+}
+
+// AuthCodeURL implements auth.IdentityProvider
+func (p *Provider) AuthCodeURL(state, nonce, verifier string) string {
+	return p.oauth.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier))
+}
+
+// Exchange implements auth.IdentityProvider
+func (p *Provider) Exchange(ctx context.Context, code, verifier, nonce string) (*auth.ExternalClaims, error) {
+	token, err := p.oauth.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		log.WarnCtx(ctx, "Failed to exchange authorization code", "provider", p.name, "error", err.Error())
+		return nil, auth.ErrExternalLoginInvalid
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		log.WarnCtx(ctx, "Token response carries no id token", "provider", p.name)
+		return nil, auth.ErrExternalLoginInvalid
+	}
+
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		log.WarnCtx(ctx, "Failed to verify id token", "provider", p.name, "error", err.Error())
+		return nil, auth.ErrExternalLoginInvalid
+	}
+
+	if idToken.Nonce != nonce {
+		log.WarnCtx(ctx, "ID token nonce mismatch", "provider", p.name)
+		return nil, auth.ErrExternalLoginInvalid
+	}
+
+	var claims struct {
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		Name          string `json:"name"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		log.ErrorCtx(ctx, "Failed to decode id token claims", err)
+		return nil, auth.ErrExternalLoginInvalid
+	}
+
+	return &auth.ExternalClaims{
+		Subject:       idToken.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Name:          claims.Name,
+	}, nil
+}
@@ -0,0 +1,134 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc/oidctest"
+	"github.com/prawirdani/golang-restapi/config"
+	"github.com/prawirdani/golang-restapi/internal/domain/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockProvider is a local OIDC provider issuing an ID token for a single authorization code.
+type mockProvider struct {
+	*httptest.Server
+	key       *rsa.PrivateKey
+	code      string
+	challenge string // S256 code challenge sent with the authorization request
+	nonce     string
+}
+
+func newMockProvider(t *testing.T) *mockProvider {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	m := &mockProvider{key: key, code: "auth-code"}
+	discovery := &oidctest.Server{
+		PublicKeys: []oidctest.PublicKey{{PublicKey: key.Public(), KeyID: "test-key", Algorithm: "RS256"}},
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", discovery)
+	mux.HandleFunc("/token", m.serveToken)
+
+	m.Server = httptest.NewServer(mux)
+	discovery.SetIssuer(m.URL)
+	t.Cleanup(m.Close)
+	return m
+}
+
+// authorize records the PKCE challenge and nonce of an authorization URL, like the consent page would.
+func (m *mockProvider) authorize(t *testing.T, authURL string) {
+	t.Helper()
+
+	u, err := url.Parse(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, "S256", u.Query().Get("code_challenge_method"))
+	m.challenge = u.Query().Get("code_challenge")
+	m.nonce = u.Query().Get("nonce")
+}
+
+func (m *mockProvider) serveToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if r.PostForm.Get("code") != m.code || base64.RawURLEncoding.EncodeToString(sum[:]) != m.challenge {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+		return
+	}
+
+	claims := fmt.Sprintf(
+		`{"iss":%q,"aud":"client-id","sub":"user-123","exp":%d,"iat":%d,"nonce":%q,`+
+			`"email":"john@example.com","email_verified":true,"name":"John Doe"}`,
+		m.URL, time.Now().Add(time.Hour).Unix(), time.Now().Unix(), m.nonce,
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"access_token": "access-token",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     oidctest.SignIDToken(m.key, "test-key", "RS256", claims),
+	})
+}
+
+func TestProvider(t *testing.T) {
+	ctx := context.Background()
+	mock := newMockProvider(t)
+
+	p, err := New(ctx, config.OIDCProvider{
+		Name:        "mock",
+		IssuerURL:   mock.URL,
+		ClientID:    "client-id",
+		RedirectURL: "http://localhost/callback",
+		Scopes:      []string{"email", "profile"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "mock", p.Name())
+
+	t.Run("Success", func(t *testing.T) {
+		mock.authorize(t, p.AuthCodeURL("state", "nonce", "verifier-verifier-verifier-verifier-verifier"))
+
+		claims, err := p.Exchange(ctx, "auth-code", "verifier-verifier-verifier-verifier-verifier", "nonce")
+		require.NoError(t, err)
+		assert.Equal(t, &auth.ExternalClaims{
+			Subject:       "user-123",
+			Email:         "john@example.com",
+			EmailVerified: true,
+			Name:          "John Doe",
+		}, claims)
+	})
+
+	t.Run("WrongVerifier", func(t *testing.T) {
+		mock.authorize(t, p.AuthCodeURL("state", "nonce", "verifier-verifier-verifier-verifier-verifier"))
+
+		_, err := p.Exchange(ctx, "auth-code", "another-verifier-another-verifier-another", "nonce")
+		assert.ErrorIs(t, err, auth.ErrExternalLoginInvalid)
+	})
+
+	t.Run("NonceMismatch", func(t *testing.T) {
+		mock.authorize(t, p.AuthCodeURL("state", "nonce", "verifier-verifier-verifier-verifier-verifier"))
+
+		_, err := p.Exchange(ctx, "auth-code", "verifier-verifier-verifier-verifier-verifier", "other-nonce")
+		assert.ErrorIs(t, err, auth.ErrExternalLoginInvalid)
+	})
+}
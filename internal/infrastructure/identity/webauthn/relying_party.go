@@ -0,0 +1,312 @@
+// Package webauthn implements the WebAuthn relying party, verifying the passkeys users register and sign in with.
+package webauthn
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"github.com/prawirdani/golang-restapi/config"
+	"github.com/prawirdani/golang-restapi/internal/domain/auth"
+	"github.com/prawirdani/golang-restapi/pkg/log"
+)
+
+// RelyingParty performs the WebAuthn ceremonies of the API, Implements auth.WebAuthnRelyingParty interface
+type RelyingParty struct {
+	webauthn *webauthn.WebAuthn
+}
+
+// New creates the relying party bound to the configured domain and origins.
+func New(cfg config.Auth) (*RelyingParty, error) {
+	w, err := webauthn.New(&webauthn.Config{
+		RPID:          cfg.WebAuthnRPID,
+		RPDisplayName: cfg.WebAuthnRPName,
+		RPOrigins:     cfg.WebAuthnOrigins,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure webauthn relying party: %w", err)
+	}
+
+	return &RelyingParty{webauthn: w}, nil
+}
+
+// BeginRegistration implements auth.WebAuthnRelyingParty
+func (rp *RelyingParty) BeginRegistration(
+	ctx context.Context,
+	account *auth.WebAuthnAccount,
+) (*auth.WebAuthnCeremony, error) {
+	u, err := newUser(account)
+	if err != nil {
+		return nil, err
+	}
+
+	creation, session, err := rp.webauthn.BeginRegistration(
+		u,
+		webauthn.WithExclusions(webauthn.Credentials(u.credentials).CredentialDescriptors()),
+		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementPreferred),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return newCeremony(creation, session)
+}
+
+// FinishRegistration implements auth.WebAuthnRelyingParty
+func (rp *RelyingParty) FinishRegistration(
+	ctx context.Context,
+	account *auth.WebAuthnAccount,
+	state, response []byte,
+) (*auth.WebAuthnCredential, error) {
+	var session webauthn.SessionData
+	if err := json.Unmarshal(state, &session); err != nil {
+		return nil, err
+	}
+
+	u, err := newUser(account)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(response)
+	if err != nil {
+		log.WarnCtx(ctx, "Failed to parse webauthn attestation", "error", errorDetails(err))
+		return nil, auth.ErrWebAuthnCredentialInvalid
+	}
+
+	credential, err := rp.webauthn.CreateCredential(u, session, parsed)
+	if err != nil {
+		log.WarnCtx(ctx, "Failed to verify webauthn attestation", "error", errorDetails(err))
+		return nil, auth.ErrWebAuthnCredentialInvalid
+	}
+
+	return fromCredential(credential), nil
+}
+
+// BeginLogin implements auth.WebAuthnRelyingParty
+func (rp *RelyingParty) BeginLogin(ctx context.Context, account *auth.WebAuthnAccount) (*auth.WebAuthnCeremony, error) {
+	if account == nil {
+		assertion, session, err := rp.webauthn.BeginDiscoverableLogin(
+			webauthn.WithUserVerification(protocol.VerificationRequired),
+		)
+		if err != nil {
+			return nil, err
+		}
+		return newCeremony(assertion, session)
+	}
+
+	u, err := newUser(account)
+	if err != nil {
+		return nil, err
+	}
+
+	assertion, session, err := rp.webauthn.BeginLogin(u)
+	if err != nil {
+		return nil, err
+	}
+
+	return newCeremony(assertion, session)
+}
+
+// FinishLogin implements auth.WebAuthnRelyingParty
+func (rp *RelyingParty) FinishLogin(
+	ctx context.Context,
+	state, response []byte,
+	lookup auth.WebAuthnAccountLookup,
+) (*auth.WebAuthnAccount, *auth.WebAuthnCredential, error) {
+	var session webauthn.SessionData
+	if err := json.Unmarshal(state, &session); err != nil {
+		return nil, nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(response)
+	if err != nil {
+		log.WarnCtx(ctx, "Failed to parse webauthn assertion", "error", errorDetails(err))
+		return nil, nil, auth.ErrWebAuthnCredentialInvalid
+	}
+
+	var (
+		account   *auth.WebAuthnAccount
+		lookupErr error
+	)
+	resolve := func(userHandle []byte) (webauthn.User, error) {
+		userID, err := uuid.FromBytes(userHandle)
+		if err != nil {
+			lookupErr = auth.ErrWebAuthnCredentialInvalid
+			return nil, lookupErr
+		}
+
+		account, lookupErr = lookup(userID)
+		if lookupErr != nil {
+			return nil, lookupErr
+		}
+
+		u, err := newUser(account)
+		if err != nil {
+			lookupErr = err
+			return nil, err
+		}
+		return u, nil
+	}
+
+	var credential *webauthn.Credential
+	if len(session.UserID) == 0 {
+		// Passwordless login, the owner is only known from the user handle returned by the authenticator
+		handler := func(rawID, userHandle []byte) (webauthn.User, error) {
+			return resolve(userHandle)
+		}
+		_, credential, err = rp.webauthn.ValidatePasskeyLogin(handler, session, parsed)
+	} else {
+		var u webauthn.User
+		if u, err = resolve(session.UserID); err == nil {
+			credential, err = rp.webauthn.ValidateLogin(u, session, parsed)
+		}
+	}
+	if err != nil {
+		if lookupErr != nil {
+			return nil, nil, lookupErr
+		}
+		log.WarnCtx(ctx, "Failed to verify webauthn assertion", "error", errorDetails(err))
+		return nil, nil, auth.ErrWebAuthnCredentialInvalid
+	}
+
+	if credential.Authenticator.CloneWarning {
+		log.WarnCtx(ctx, "WebAuthn sign count went backwards, the authenticator may be cloned",
+			"user_id", account.UserID.String(),
+		)
+		return nil, nil, auth.ErrWebAuthnCredentialInvalid
+	}
+
+	for _, stored := range account.Credentials {
+		id, err := base64.RawURLEncoding.DecodeString(stored.ID)
+		if err != nil || !bytes.Equal(id, credential.ID) {
+			continue
+		}
+
+		stored.SignCount = credential.Authenticator.SignCount
+		stored.BackupState = credential.Flags.BackupState
+		return account, stored, nil
+	}
+
+	return nil, nil, auth.ErrWebAuthnCredentialInvalid
+}
+
+// newCeremony encodes the options for the browser and the session data verifying its response.
+func newCeremony(options any, session *webauthn.SessionData) (*auth.WebAuthnCeremony, error) {
+	opts, err := json.Marshal(options)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := json.Marshal(session)
+	if err != nil {
+		return nil, err
+	}
+
+	return &auth.WebAuthnCeremony{Options: opts, State: state}, nil
+}
+
+// errorDetails returns the developer information of a protocol error, which says why verification failed.
+func errorDetails(err error) string {
+	var perr *protocol.Error
+	if errors.As(err, &perr) && perr.DevInfo != "" {
+		return perr.Details + ": " + perr.DevInfo
+	}
+	return err.Error()
+}
+
+// user adapts an auth.WebAuthnAccount to the webauthn.User interface.
+type user struct {
+	account     *auth.WebAuthnAccount
+	credentials []webauthn.Credential
+}
+
+func newUser(account *auth.WebAuthnAccount) (*user, error) {
+	credentials := make([]webauthn.Credential, 0, len(account.Credentials))
+	for _, c := range account.Credentials {
+		credential, err := toCredential(c)
+		if err != nil {
+			return nil, err
+		}
+		credentials = append(credentials, credential)
+	}
+
+	return &user{account: account, credentials: credentials}, nil
+}
+
+func (u *user) WebAuthnID() []byte {
+	return u.account.UserID[:]
+}
+
+func (u *user) WebAuthnName() string {
+	return u.account.Name
+}
+
+func (u *user) WebAuthnDisplayName() string {
+	return u.account.DisplayName
+}
+
+func (u *user) WebAuthnCredentials() []webauthn.Credential {
+	return u.credentials
+}
+
+// toCredential converts a stored credential to the record the library verifies assertions against.
+func toCredential(c *auth.WebAuthnCredential) (webauthn.Credential, error) {
+	id, err := base64.RawURLEncoding.DecodeString(c.ID)
+	if err != nil {
+		return webauthn.Credential{}, fmt.Errorf("invalid webauthn credential id: %w", err)
+	}
+
+	transports := make([]protocol.AuthenticatorTransport, len(c.Transports))
+	for i, t := range c.Transports {
+		transports[i] = protocol.AuthenticatorTransport(t)
+	}
+
+	return webauthn.Credential{
+		ID:              id,
+		PublicKey:       c.PublicKey,
+		AttestationType: c.AttestationType,
+		Transport:       transports,
+		Flags: webauthn.CredentialFlags{
+			BackupEligible: c.BackupEligible,
+			BackupState:    c.BackupState,
+		},
+		Authenticator: webauthn.Authenticator{
+			AAGUID:    c.AAGUID[:],
+			SignCount: c.SignCount,
+		},
+	}, nil
+}
+
+// fromCredential converts a newly registered credential for storage.
+func fromCredential(c *webauthn.Credential) *auth.WebAuthnCredential {
+	transports := make([]string, len(c.Transport))
+	for i, t := range c.Transport {
+		transports[i] = string(t)
+	}
+
+	// Authenticators that don't attest report no AAGUID, which is stored as the nil UUID
+	aaguid, err := uuid.FromBytes(c.Authenticator.AAGUID)
+	if err != nil {
+		aaguid = uuid.Nil
+	}
+
+	return &auth.WebAuthnCredential{
+		ID:              base64.RawURLEncoding.EncodeToString(c.ID),
+		PublicKey:       c.PublicKey,
+		AttestationType: c.AttestationType,
+		Transports:      transports,
+		AAGUID:          aaguid,
+		SignCount:       c.Authenticator.SignCount,
+		BackupEligible:  c.Flags.BackupEligible,
+		BackupState:     c.Flags.BackupState,
+		CreatedAt:       time.Now(),
+	}
+}
@@ -0,0 +1,256 @@
+package webauthn
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+	"github.com/google/uuid"
+	"github.com/prawirdani/golang-restapi/config"
+	"github.com/prawirdani/golang-restapi/internal/domain/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testRPID   = "example.com"
+	testOrigin = "https://app.example.com"
+)
+
+// Authenticator data flags, WebAuthn section 6.1
+const (
+	flagUserPresent   = 0x01
+	flagUserVerified  = 0x04
+	flagAttestedCreds = 0x40
+)
+
+var b64 = base64.RawURLEncoding
+
+// softAuthenticator is a software passkey holding a single P-256 credential.
+type softAuthenticator struct {
+	key       *ecdsa.PrivateKey
+	id        []byte
+	userID    []byte
+	signCount uint32
+}
+
+func newSoftAuthenticator(t *testing.T) *softAuthenticator {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	id := make([]byte, 16)
+	_, err = rand.Read(id)
+	require.NoError(t, err)
+
+	return &softAuthenticator{key: key, id: id}
+}
+
+func (a *softAuthenticator) authData(flags byte, attested []byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(testRPID))
+
+	data := append(rpIDHash[:], flags)
+	data = binary.BigEndian.AppendUint32(data, a.signCount)
+	return append(data, attested...)
+}
+
+func clientData(t *testing.T, ceremonyType string, options json.RawMessage) []byte {
+	t.Helper()
+
+	var opts struct {
+		PublicKey struct {
+			Challenge string `json:"challenge"`
+		} `json:"publicKey"`
+	}
+	require.NoError(t, json.Unmarshal(options, &opts))
+
+	data, err := json.Marshal(map[string]string{
+		"type":      ceremonyType,
+		"challenge": opts.PublicKey.Challenge,
+		"origin":    testOrigin,
+	})
+	require.NoError(t, err)
+	return data
+}
+
+// create answers navigator.credentials.create() with a "none" attestation.
+func (a *softAuthenticator) create(t *testing.T, options json.RawMessage) []byte {
+	t.Helper()
+
+	var opts struct {
+		PublicKey struct {
+			User struct {
+				ID string `json:"id"`
+			} `json:"user"`
+		} `json:"publicKey"`
+	}
+	require.NoError(t, json.Unmarshal(options, &opts))
+
+	userID, err := b64.DecodeString(opts.PublicKey.User.ID)
+	require.NoError(t, err)
+	a.userID = userID
+
+	coseKey, err := webauthncbor.Marshal(webauthncose.EC2PublicKeyData{
+		PublicKeyData: webauthncose.PublicKeyData{
+			KeyType:   int64(webauthncose.EllipticKey),
+			Algorithm: int64(webauthncose.AlgES256),
+		},
+		Curve:  int64(webauthncose.P256),
+		XCoord: a.key.X.FillBytes(make([]byte, 32)),
+		YCoord: a.key.Y.FillBytes(make([]byte, 32)),
+	})
+	require.NoError(t, err)
+
+	attested := make([]byte, 16) // Zero AAGUID
+	attested = binary.BigEndian.AppendUint16(attested, uint16(len(a.id)))
+	attested = append(attested, a.id...)
+	attested = append(attested, coseKey...)
+
+	attestation, err := webauthncbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": a.authData(flagUserPresent|flagUserVerified|flagAttestedCreds, attested),
+	})
+	require.NoError(t, err)
+
+	response, err := json.Marshal(map[string]any{
+		"id":    b64.EncodeToString(a.id),
+		"rawId": b64.EncodeToString(a.id),
+		"type":  "public-key",
+		"response": map[string]any{
+			"clientDataJSON":    b64.EncodeToString(clientData(t, "webauthn.create", options)),
+			"attestationObject": b64.EncodeToString(attestation),
+			"transports":        []string{"internal"},
+		},
+	})
+	require.NoError(t, err)
+	return response
+}
+
+// get answers navigator.credentials.get() with an assertion signed by the credential.
+func (a *softAuthenticator) get(t *testing.T, options json.RawMessage) []byte {
+	t.Helper()
+
+	a.signCount++
+	authData := a.authData(flagUserPresent|flagUserVerified, nil)
+	data := clientData(t, "webauthn.get", options)
+
+	clientDataHash := sha256.Sum256(data)
+	digest := sha256.Sum256(append(authData, clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	require.NoError(t, err)
+
+	response, err := json.Marshal(map[string]any{
+		"id":    b64.EncodeToString(a.id),
+		"rawId": b64.EncodeToString(a.id),
+		"type":  "public-key",
+		"response": map[string]any{
+			"clientDataJSON":    b64.EncodeToString(data),
+			"authenticatorData": b64.EncodeToString(authData),
+			"signature":         b64.EncodeToString(signature),
+			"userHandle":        b64.EncodeToString(a.userID),
+		},
+	})
+	require.NoError(t, err)
+	return response
+}
+
+func TestRelyingParty(t *testing.T) {
+	ctx := context.Background()
+
+	rp, err := New(config.Auth{
+		WebAuthnRPID:    testRPID,
+		WebAuthnRPName:  "golang-restapi",
+		WebAuthnOrigins: []string{testOrigin},
+	})
+	require.NoError(t, err)
+
+	account := &auth.WebAuthnAccount{UserID: uuid.New(), Name: "john@example.com", DisplayName: "John Doe"}
+	authenticator := newSoftAuthenticator(t)
+
+	// Registration
+	ceremony, err := rp.BeginRegistration(ctx, account)
+	require.NoError(t, err)
+
+	credential, err := rp.FinishRegistration(ctx, account, ceremony.State, authenticator.create(t, ceremony.Options))
+	require.NoError(t, err)
+	assert.Equal(t, b64.EncodeToString(authenticator.id), credential.ID)
+	assert.Equal(t, []string{"internal"}, credential.Transports)
+	assert.Equal(t, uuid.Nil, credential.AAGUID)
+	assert.Equal(t, account.UserID[:], authenticator.userID)
+
+	credential.UserID = account.UserID
+	account.Credentials = []*auth.WebAuthnCredential{credential}
+	lookup := func(userID uuid.UUID) (*auth.WebAuthnAccount, error) {
+		if userID != account.UserID {
+			return nil, auth.ErrWebAuthnCredentialInvalid
+		}
+		return account, nil
+	}
+
+	t.Run("RegisteredTwice", func(t *testing.T) {
+		ceremony, err := rp.BeginRegistration(ctx, account)
+		require.NoError(t, err)
+		assert.Contains(t, string(ceremony.Options), credential.ID) // Excluded
+	})
+
+	t.Run("PasswordlessLogin", func(t *testing.T) {
+		ceremony, err := rp.BeginLogin(ctx, nil)
+		require.NoError(t, err)
+
+		got, used, err := rp.FinishLogin(ctx, ceremony.State, authenticator.get(t, ceremony.Options), lookup)
+		require.NoError(t, err)
+		assert.Equal(t, account.UserID, got.UserID)
+		assert.Same(t, credential, used)
+		assert.Equal(t, authenticator.signCount, used.SignCount)
+	})
+
+	t.Run("SecondFactor", func(t *testing.T) {
+		ceremony, err := rp.BeginLogin(ctx, account)
+		require.NoError(t, err)
+		assert.Contains(t, string(ceremony.Options), credential.ID) // Allowed
+
+		_, used, err := rp.FinishLogin(ctx, ceremony.State, authenticator.get(t, ceremony.Options), lookup)
+		require.NoError(t, err)
+		assert.Equal(t, authenticator.signCount, used.SignCount)
+	})
+
+	t.Run("ReplayedChallenge", func(t *testing.T) {
+		ceremony, err := rp.BeginLogin(ctx, nil)
+		require.NoError(t, err)
+
+		other, err := rp.BeginLogin(ctx, nil)
+		require.NoError(t, err)
+
+		_, _, err = rp.FinishLogin(ctx, ceremony.State, authenticator.get(t, other.Options), lookup)
+		assert.ErrorIs(t, err, auth.ErrWebAuthnCredentialInvalid)
+	})
+
+	t.Run("ClonedAuthenticator", func(t *testing.T) {
+		ceremony, err := rp.BeginLogin(ctx, nil)
+		require.NoError(t, err)
+
+		authenticator.signCount = 0 // The next assertion reuses a count that has already been seen
+		_, _, err = rp.FinishLogin(ctx, ceremony.State, authenticator.get(t, ceremony.Options), lookup)
+		assert.ErrorIs(t, err, auth.ErrWebAuthnCredentialInvalid)
+	})
+
+	t.Run("UnknownUser", func(t *testing.T) {
+		ceremony, err := rp.BeginLogin(ctx, nil)
+		require.NoError(t, err)
+
+		stranger := newSoftAuthenticator(t)
+		stranger.userID = uuid.New().NodeID()
+		_, _, err = rp.FinishLogin(ctx, ceremony.State, stranger.get(t, ceremony.Options), lookup)
+		assert.ErrorIs(t, err, auth.ErrWebAuthnCredentialInvalid)
+	})
+}
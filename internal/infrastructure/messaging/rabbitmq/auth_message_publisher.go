@@ -15,6 +15,20 @@ const (
 	AuthDirectExchange           = "auth.direct"
 	ResetPasswordEmailRoutingKey = "email.reset-password"
 	ResetPasswordEmailQueue      = "auth.email.reset-password"
+	VerificationEmailRoutingKey  = "email.verification"
+	VerificationEmailQueue       = "auth.email.verification"
+	EmailChangeConfirmRoutingKey = "email.change-confirmation"
+	EmailChangeConfirmQueue      = "auth.email.change-confirmation"
+	EmailChangeNoticeRoutingKey  = "email.change-notification"
+	EmailChangeNoticeQueue       = "auth.email.change-notification"
+	AccountLockedEmailRoutingKey = "email.account-locked"
+	AccountLockedEmailQueue      = "auth.email.account-locked"
+	MagicLinkEmailRoutingKey     = "email.magic-link"
+	MagicLinkEmailQueue          = "auth.email.magic-link"
+	AccountExistsEmailRoutingKey = "email.account-exists"
+	AccountExistsEmailQueue      = "auth.email.account-exists"
+	NewDeviceEmailRoutingKey     = "email.new-device-login"
+	NewDeviceEmailQueue          = "auth.email.new-device-login"
 )
 
 var ResetPasswordEmailTopology = &Topology{
@@ -31,6 +45,104 @@ var ResetPasswordEmailTopology = &Topology{
 	},
 }
 
+var VerificationEmailTopology = &Topology{
+	Name:         "Verification Email Topology",
+	Exchange:     AuthDirectExchange,
+	ExchangeType: "direct",
+	Queue:        VerificationEmailQueue,
+	RoutingKey:   VerificationEmailRoutingKey,
+	Durable:      true,
+	RetryTTL:     5000, // 5 Seconds
+	MaxRetry:     3,
+	QueueArgs: amqp.Table{
+		"x-queue-type": "quorum",
+	},
+}
+
+var EmailChangeConfirmationTopology = &Topology{
+	Name:         "Email Change Confirmation Topology",
+	Exchange:     AuthDirectExchange,
+	ExchangeType: "direct",
+	Queue:        EmailChangeConfirmQueue,
+	RoutingKey:   EmailChangeConfirmRoutingKey,
+	Durable:      true,
+	RetryTTL:     5000, // 5 Seconds
+	MaxRetry:     3,
+	QueueArgs: amqp.Table{
+		"x-queue-type": "quorum",
+	},
+}
+
+var EmailChangeNotificationTopology = &Topology{
+	Name:         "Email Change Notification Topology",
+	Exchange:     AuthDirectExchange,
+	ExchangeType: "direct",
+	Queue:        EmailChangeNoticeQueue,
+	RoutingKey:   EmailChangeNoticeRoutingKey,
+	Durable:      true,
+	RetryTTL:     5000, // 5 Seconds
+	MaxRetry:     3,
+	QueueArgs: amqp.Table{
+		"x-queue-type": "quorum",
+	},
+}
+
+var AccountLockedEmailTopology = &Topology{
+	Name:         "Account Locked Email Topology",
+	Exchange:     AuthDirectExchange,
+	ExchangeType: "direct",
+	Queue:        AccountLockedEmailQueue,
+	RoutingKey:   AccountLockedEmailRoutingKey,
+	Durable:      true,
+	RetryTTL:     5000, // 5 Seconds
+	MaxRetry:     3,
+	QueueArgs: amqp.Table{
+		"x-queue-type": "quorum",
+	},
+}
+
+var MagicLinkEmailTopology = &Topology{
+	Name:         "Magic Link Email Topology",
+	Exchange:     AuthDirectExchange,
+	ExchangeType: "direct",
+	Queue:        MagicLinkEmailQueue,
+	RoutingKey:   MagicLinkEmailRoutingKey,
+	Durable:      true,
+	RetryTTL:     5000, // 5 Seconds
+	MaxRetry:     3,
+	QueueArgs: amqp.Table{
+		"x-queue-type": "quorum",
+	},
+}
+
+var AccountExistsEmailTopology = &Topology{
+	Name:         "Account Exists Email Topology",
+	Exchange:     AuthDirectExchange,
+	ExchangeType: "direct",
+	Queue:        AccountExistsEmailQueue,
+	RoutingKey:   AccountExistsEmailRoutingKey,
+	Durable:      true,
+	RetryTTL:     5000, // 5 Seconds
+	MaxRetry:     3,
+	QueueArgs: amqp.Table{
+		"x-queue-type": "quorum",
+	},
+}
+
+var NewDeviceLoginEmailTopology = &Topology{
+	Name:         "New Device Login Email Topology",
+	Exchange:     AuthDirectExchange,
+	ExchangeType: "direct",
+	Queue:        NewDeviceEmailQueue,
+	RoutingKey:   NewDeviceEmailRoutingKey,
+	Durable:      true,
+	RetryTTL:     5000, // 5 Seconds
+	MaxRetry:     3,
+	QueueArgs: amqp.Table{
+		"x-queue-type": "quorum",
+	},
+}
+
 type AuthMessagePublisher struct {
 	conn *amqp.Connection
 }
@@ -44,6 +156,99 @@ func (mp *AuthMessagePublisher) SendResetPasswordEmail(
 	ctx context.Context,
 	msg auth.ResetPasswordEmailMessage,
 ) error {
+	if err := mp.publish(ctx, ResetPasswordEmailRoutingKey, msg); err != nil {
+		return fmt.Errorf("failed to publish reset password email message: %w", err)
+	}
+
+	return nil
+}
+
+// Implements auth.MessagePublisher
+func (mp *AuthMessagePublisher) SendVerificationEmail(
+	ctx context.Context,
+	msg auth.VerificationEmailMessage,
+) error {
+	if err := mp.publish(ctx, VerificationEmailRoutingKey, msg); err != nil {
+		return fmt.Errorf("failed to publish verification email message: %w", err)
+	}
+
+	return nil
+}
+
+// Implements auth.MessagePublisher
+func (mp *AuthMessagePublisher) SendEmailChangeConfirmation(
+	ctx context.Context,
+	msg auth.EmailChangeConfirmationMessage,
+) error {
+	if err := mp.publish(ctx, EmailChangeConfirmRoutingKey, msg); err != nil {
+		return fmt.Errorf("failed to publish email change confirmation message: %w", err)
+	}
+
+	return nil
+}
+
+// Implements auth.MessagePublisher
+func (mp *AuthMessagePublisher) SendEmailChangeNotification(
+	ctx context.Context,
+	msg auth.EmailChangeNotificationMessage,
+) error {
+	if err := mp.publish(ctx, EmailChangeNoticeRoutingKey, msg); err != nil {
+		return fmt.Errorf("failed to publish email change notification message: %w", err)
+	}
+
+	return nil
+}
+
+// Implements auth.MessagePublisher
+func (mp *AuthMessagePublisher) SendAccountLockedEmail(
+	ctx context.Context,
+	msg auth.AccountLockedEmailMessage,
+) error {
+	if err := mp.publish(ctx, AccountLockedEmailRoutingKey, msg); err != nil {
+		return fmt.Errorf("failed to publish account locked email message: %w", err)
+	}
+
+	return nil
+}
+
+// Implements auth.MessagePublisher
+func (mp *AuthMessagePublisher) SendMagicLinkEmail(
+	ctx context.Context,
+	msg auth.MagicLinkEmailMessage,
+) error {
+	if err := mp.publish(ctx, MagicLinkEmailRoutingKey, msg); err != nil {
+		return fmt.Errorf("failed to publish magic link email message: %w", err)
+	}
+
+	return nil
+}
+
+// Implements auth.MessagePublisher
+func (mp *AuthMessagePublisher) SendAccountExistsEmail(
+	ctx context.Context,
+	msg auth.AccountExistsEmailMessage,
+) error {
+	if err := mp.publish(ctx, AccountExistsEmailRoutingKey, msg); err != nil {
+		return fmt.Errorf("failed to publish account exists email message: %w", err)
+	}
+
+	return nil
+}
+
+// Implements auth.MessagePublisher
+func (mp *AuthMessagePublisher) SendNewDeviceLoginEmail(
+	ctx context.Context,
+	msg auth.NewDeviceLoginEmailMessage,
+) error {
+	if err := mp.publish(ctx, NewDeviceEmailRoutingKey, msg); err != nil {
+		return fmt.Errorf("failed to publish new device login email message: %w", err)
+	}
+
+	return nil
+}
+
+// publish encodes msg as JSON and publishes it to the auth exchange with the given routing key.
+func (mp *AuthMessagePublisher) publish(ctx context.Context, routingKey string, msg any) error {
 	// NOTE: For low to moderate traffic is okay to open channel per function call, but when the traffic goes up it
 	// slightly more overhead per publish (channel open/close is a network round-trip)
 	// TODO: Use thread safe channel or use channel pool
@@ -58,10 +263,10 @@ func (mp *AuthMessagePublisher) SendResetPasswordEmail(
 		return err
 	}
 
-	err = ch.PublishWithContext(
+	return ch.PublishWithContext(
 		ctx,
 		AuthDirectExchange,
-		ResetPasswordEmailRoutingKey,
+		routingKey,
 		false,
 		false,
 		amqp.Publishing{
@@ -71,9 +276,4 @@ func (mp *AuthMessagePublisher) SendResetPasswordEmail(
 			MessageId:   uuid.NewString(),
 		},
 	)
-	if err != nil {
-		return fmt.Errorf("failed to publish reset password email message: %w", err)
-	}
-
-	return nil
 }
@@ -0,0 +1,75 @@
+package rabbitmq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prawirdani/golang-restapi/internal/domain/organization"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	OrganizationDirectExchange = "organization.direct"
+	InvitationEmailRoutingKey  = "email.invitation"
+	InvitationEmailQueue       = "organization.email.invitation"
+)
+
+var InvitationEmailTopology = &Topology{
+	Name:         "Organization Invitation Email Topology",
+	Exchange:     OrganizationDirectExchange,
+	ExchangeType: "direct",
+	Queue:        InvitationEmailQueue,
+	RoutingKey:   InvitationEmailRoutingKey,
+	Durable:      true,
+	RetryTTL:     5000, // 5 Seconds
+	MaxRetry:     3,
+	QueueArgs: amqp.Table{
+		"x-queue-type": "quorum",
+	},
+}
+
+type OrganizationMessagePublisher struct {
+	conn *amqp.Connection
+}
+
+func NewOrganizationMessagePublisher(conn *amqp.Connection) *OrganizationMessagePublisher {
+	return &OrganizationMessagePublisher{conn: conn}
+}
+
+// Implements organization.MessagePublisher
+func (mp *OrganizationMessagePublisher) SendInvitationEmail(
+	ctx context.Context,
+	msg organization.InvitationEmailMessage,
+) error {
+	ch, err := mp.conn.Channel()
+	if err != nil {
+		return fmt.Errorf("failed to open channel: %w", err)
+	}
+	defer ch.Close()
+
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	if err := ch.PublishWithContext(
+		ctx,
+		OrganizationDirectExchange,
+		InvitationEmailRoutingKey,
+		false,
+		false,
+		amqp.Publishing{
+			ContentType: "application/json",
+			Body:        b,
+			Timestamp:   time.Now(),
+			MessageId:   uuid.NewString(),
+		},
+	); err != nil {
+		return fmt.Errorf("failed to publish invitation email message: %w", err)
+	}
+
+	return nil
+}
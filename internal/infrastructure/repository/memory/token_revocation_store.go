@@ -0,0 +1,56 @@
+// Package memory provides in-process implementations of the repositories, for deployments running a
+// single instance. Their state is lost on restart.
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TokenRevocationStore implements [auth.TokenRevocationStore] on an in-memory map.
+// Entries are evicted once expired, on every revocation.
+type TokenRevocationStore struct {
+	mu      sync.RWMutex
+	revoked map[string]time.Time // ID to expiration
+}
+
+func NewTokenRevocationStore() *TokenRevocationStore {
+	return &TokenRevocationStore{
+		revoked: make(map[string]time.Time),
+	}
+}
+
+// Revoke implements [auth.TokenRevocationStore]
+func (s *TokenRevocationStore) Revoke(_ context.Context, id string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for k, exp := range s.revoked {
+		if !exp.After(now) {
+			delete(s.revoked, k)
+		}
+	}
+
+	if expiresAt.After(s.revoked[id]) && expiresAt.After(now) {
+		s.revoked[id] = expiresAt
+	}
+
+	return nil
+}
+
+// IsRevoked implements [auth.TokenRevocationStore]
+func (s *TokenRevocationStore) IsRevoked(_ context.Context, ids ...string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	for _, id := range ids {
+		if exp, ok := s.revoked[id]; ok && exp.After(now) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenRevocationStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Revoke", func(t *testing.T) {
+		store := NewTokenRevocationStore()
+		require.NoError(t, store.Revoke(ctx, "sid", time.Now().Add(time.Minute)))
+
+		revoked, err := store.IsRevoked(ctx, "jti", "sid")
+		require.NoError(t, err)
+		assert.True(t, revoked)
+
+		revoked, err = store.IsRevoked(ctx, "other-jti", "other-sid")
+		require.NoError(t, err)
+		assert.False(t, revoked)
+	})
+
+	t.Run("KeepsLaterExpiration", func(t *testing.T) {
+		store := NewTokenRevocationStore()
+		later := time.Now().Add(time.Hour)
+		require.NoError(t, store.Revoke(ctx, "sid", later))
+		require.NoError(t, store.Revoke(ctx, "sid", time.Now().Add(time.Minute)))
+
+		assert.Equal(t, later, store.revoked["sid"])
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		store := NewTokenRevocationStore()
+		store.revoked["expired"] = time.Now().Add(-time.Second)
+
+		revoked, err := store.IsRevoked(ctx, "expired")
+		require.NoError(t, err)
+		assert.False(t, revoked)
+
+		// Evicted by the next revocation
+		require.NoError(t, store.Revoke(ctx, "sid", time.Now().Add(time.Minute)))
+		assert.NotContains(t, store.revoked, "expired")
+		assert.Contains(t, store.revoked, "sid")
+	})
+}
@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prawirdani/golang-restapi/internal/domain/audit"
+	"github.com/prawirdani/golang-restapi/pkg/log"
+	strs "github.com/prawirdani/golang-restapi/pkg/strings"
+)
+
+const auditEventColumns = "id, type, user_id, actor_id, ip_address, user_agent, request_id, metadata, created_at"
+
+type auditRepository struct {
+	db *db
+}
+
+func NewAuditRepository(pool *pgxpool.Pool) *auditRepository {
+	return &auditRepository{
+		db: &db{pool: pool},
+	}
+}
+
+// Write implements [audit.Writer]
+func (r *auditRepository) Write(ctx context.Context, event *audit.Event) error {
+	if event == nil {
+		log.WarnCtx(ctx, "Write called with nil audit event ptr")
+		return errors.New("audit event is nil")
+	}
+
+	query := strs.Concatenate(
+		"INSERT INTO audit_events(", auditEventColumns, ") ",
+		"VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9)",
+	)
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(
+		ctx,
+		query,
+		event.ID,
+		event.Type,
+		event.UserID,
+		event.ActorID,
+		event.IPAddress,
+		event.UserAgent,
+		event.RequestID,
+		event.Metadata,
+		event.CreatedAt,
+	); err != nil {
+		log.ErrorCtx(ctx, "Failed to write audit event", err, "type", string(event.Type))
+		return err
+	}
+
+	return nil
+}
+
+// List implements [audit.Repository]
+func (r *auditRepository) List(ctx context.Context, filter audit.ListFilter) ([]*audit.Event, int, error) {
+	var (
+		conds []string
+		args  []any
+	)
+	addCond := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if filter.UserID.Valid {
+		addCond("user_id = $%d", filter.UserID.UUID)
+	}
+	if filter.ActorID.Valid {
+		addCond("actor_id = $%d", filter.ActorID.UUID)
+	}
+	if filter.Type != "" {
+		addCond("type = $%d", filter.Type)
+	}
+	if !filter.From.IsZero() {
+		addCond("created_at >= $%d", filter.From)
+	}
+	if !filter.To.IsZero() {
+		addCond("created_at < $%d", filter.To)
+	}
+
+	var where string
+	if len(conds) > 0 {
+		where = " WHERE " + strings.Join(conds, " AND ")
+	}
+
+	conn := r.db.GetConn(ctx)
+
+	var total int
+	countQuery := "SELECT COUNT(*) FROM audit_events" + where
+	if err := conn.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		log.ErrorCtx(ctx, "Failed to count audit events", err)
+		return nil, 0, err
+	}
+
+	args = append(args, filter.PerPage, filter.Offset())
+	query := strs.Concatenate(
+		"SELECT ", auditEventColumns, " FROM audit_events", where,
+		fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", len(args)-1, len(args)),
+	)
+
+	var events []*audit.Event
+	if err := pgxscan.Select(ctx, conn, &events, query, args...); err != nil {
+		log.ErrorCtx(ctx, "Failed to list audit events", err)
+		return nil, 0, err
+	}
+
+	return events, total, nil
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 
 	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/prawirdani/golang-restapi/internal/domain/auth"
 	"github.com/prawirdani/golang-restapi/pkg/log"
@@ -28,9 +29,25 @@ func (r *authRepository) StoreSession(ctx context.Context, session *auth.Session
 		return errors.New("session is nil")
 	}
 
-	query := "INSERT INTO sessions(id, user_id, user_agent, expires_at, accessed_at) VALUES($1, $2, $3, $4, $5)"
+	query := strs.Concatenate(
+		"INSERT INTO sessions",
+		"(id, family_id, user_id, user_agent, expires_at, accessed_at, amr, organization_id, authenticated_at) ",
+		"VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9)",
+	)
 	conn := r.db.GetConn(ctx)
-	if _, err := conn.Exec(ctx, query, session.ID, session.UserID, session.UserAgent, session.ExpiresAt, session.AccessedAt); err != nil {
+	if _, err := conn.Exec(
+		ctx,
+		query,
+		session.ID,
+		session.FamilyID,
+		session.UserID,
+		session.UserAgent,
+		session.ExpiresAt,
+		session.AccessedAt,
+		session.AMR,
+		session.OrganizationID,
+		session.AuthenticatedAt,
+	); err != nil {
 		log.ErrorCtx(ctx, "Failed to store session", err)
 		return err
 	}
@@ -44,8 +61,8 @@ func (r *authRepository) GetSession(
 	sessID string,
 ) (*auth.Session, error) {
 	query := strs.Concatenate(
-		"UPDATE sessions SET accessed_at=NOW() WHERE id",
-		"=$1 RETURNING *",
+		"SELECT id, family_id, user_id, user_agent, expires_at, accessed_at, amr, rotated_at, organization_id, ",
+		"authenticated_at FROM sessions WHERE id=$1",
 	)
 
 	conn := r.db.GetConn(ctx)
@@ -72,10 +89,22 @@ func (r *authRepository) UpdateSession(ctx context.Context, session *auth.Sessio
 		return errors.New("session is nil")
 	}
 
-	query := "UPDATE sessions SET expires_at=$1 WHERE id=$2"
+	query := strs.Concatenate(
+		"UPDATE sessions SET expires_at=$1, accessed_at=$2, rotated_at=$3, organization_id=$4, authenticated_at=$5 ",
+		"WHERE id=$6",
+	)
 	conn := r.db.GetConn(ctx)
 
-	if _, err := conn.Exec(ctx, query, session.ExpiresAt, session.ID); err != nil {
+	if _, err := conn.Exec(
+		ctx,
+		query,
+		session.ExpiresAt,
+		session.AccessedAt,
+		session.RotatedAt,
+		session.OrganizationID,
+		session.AuthenticatedAt,
+		session.ID,
+	); err != nil {
 		log.ErrorCtx(ctx, "Failed to updated session", err)
 		return err
 	}
@@ -83,6 +112,73 @@ func (r *authRepository) UpdateSession(ctx context.Context, session *auth.Sessio
 	return nil
 }
 
+// RevokeSessionFamily implements [auth.Repository]
+func (r *authRepository) RevokeSessionFamily(ctx context.Context, familyID string) error {
+	query := "UPDATE sessions SET expires_at=NOW() WHERE family_id=$1 AND expires_at > NOW()"
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(ctx, query, familyID); err != nil {
+		log.ErrorCtx(ctx, "Failed to revoke session family", err)
+		return err
+	}
+
+	return nil
+}
+
+// ListSessionsByUser implements [auth.Repository]
+func (r *authRepository) ListSessionsByUser(ctx context.Context, userID string) ([]*auth.Session, error) {
+	query := strs.Concatenate(
+		"SELECT id, family_id, user_id, user_agent, expires_at, accessed_at, amr, rotated_at, organization_id, ",
+		"authenticated_at FROM sessions ",
+		"WHERE user_id=$1 AND rotated_at IS NULL AND expires_at > NOW() ORDER BY accessed_at DESC",
+	)
+	conn := r.db.GetConn(ctx)
+
+	var sessions []*auth.Session
+	if err := pgxscan.Select(ctx, conn, &sessions, query, userID); err != nil {
+		log.ErrorCtx(ctx, "Failed to list user sessions", err)
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// RevokeSessionsByUser implements [auth.Repository]
+func (r *authRepository) RevokeSessionsByUser(
+	ctx context.Context,
+	userID string,
+	exceptFamilyID string,
+) error {
+	query := strs.Concatenate(
+		"UPDATE sessions SET expires_at=NOW() ",
+		"WHERE user_id=$1 AND expires_at > NOW() AND family_id::TEXT <> $2",
+	)
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(ctx, query, userID, exceptFamilyID); err != nil {
+		log.ErrorCtx(ctx, "Failed to revoke user sessions", err)
+		return err
+	}
+
+	return nil
+}
+
+// RevokePendingTokensByUser implements [auth.Repository]
+func (r *authRepository) RevokePendingTokensByUser(ctx context.Context, userID string) error {
+	batch := &pgx.Batch{}
+	for _, table := range []string{"reset_password_tokens", "magic_link_tokens", "email_change_tokens"} {
+		batch.Queue("UPDATE "+table+" SET used_at=NOW() WHERE user_id=$1 AND used_at IS NULL", userID)
+	}
+
+	conn := r.db.GetConn(ctx)
+	if err := conn.SendBatch(ctx, batch).Close(); err != nil {
+		log.ErrorCtx(ctx, "Failed to revoke pending user tokens", err)
+		return err
+	}
+
+	return nil
+}
+
 // GetResetPasswordToken implements [auth.Repository]
 func (r *authRepository) GetResetPasswordToken(
 	ctx context.Context,
@@ -148,3 +244,1110 @@ func (r *authRepository) UpdateResetPasswordToken(
 
 	return nil
 }
+
+// GetEmailVerificationToken implements [auth.Repository]
+func (r *authRepository) GetEmailVerificationToken(
+	ctx context.Context,
+	tokenValue string,
+) (*auth.EmailVerificationToken, error) {
+	query := "SELECT user_id, value, expires_at, used_at FROM email_verification_tokens WHERE value=$1"
+
+	conn := r.db.GetConn(ctx)
+	if r.db.IsTxConn(conn) {
+		query += "\nFOR UPDATE"
+	}
+
+	var tokenObj auth.EmailVerificationToken
+	if err := pgxscan.Get(ctx, conn, &tokenObj, query, tokenValue); err != nil {
+		if noRowsErr(err) {
+			return nil, auth.ErrEmailVerificationTokenNotFound
+		}
+		log.ErrorCtx(ctx, "Failed to get email verification token", err)
+		return nil, err
+	}
+
+	return &tokenObj, nil
+}
+
+// StoreEmailVerificationToken implements [auth.Repository]
+func (r *authRepository) StoreEmailVerificationToken(
+	ctx context.Context,
+	token *auth.EmailVerificationToken,
+) error {
+	if token == nil {
+		log.WarnCtx(ctx, "StoreEmailVerificationToken called with nil token ptr")
+		return errors.New("email verification token is nil")
+	}
+
+	query := "INSERT INTO email_verification_tokens(user_id, value, expires_at) VALUES($1, $2, $3)"
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(ctx, query, token.UserID, token.Value, token.ExpiresAt); err != nil {
+		log.ErrorCtx(ctx, "Failed to store email verification token", err)
+		return err
+	}
+
+	return nil
+}
+
+// UpdateEmailVerificationToken implements [auth.Repository]
+func (r *authRepository) UpdateEmailVerificationToken(
+	ctx context.Context,
+	token *auth.EmailVerificationToken,
+) error {
+	if token == nil {
+		log.WarnCtx(ctx, "UpdateEmailVerificationToken called with nil token object")
+		return errors.New("email verification token is nil")
+	}
+
+	query := "UPDATE email_verification_tokens SET used_at=$1 WHERE value=$2"
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(ctx, query, token.UsedAt, token.Value); err != nil {
+		log.ErrorCtx(ctx, "Failed to update email verification token", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetEmailChangeToken implements [auth.Repository]
+func (r *authRepository) GetEmailChangeToken(
+	ctx context.Context,
+	tokenValue string,
+) (*auth.EmailChangeToken, error) {
+	query := "SELECT user_id, new_email, value, expires_at, used_at FROM email_change_tokens WHERE value=$1"
+
+	conn := r.db.GetConn(ctx)
+	if r.db.IsTxConn(conn) {
+		query += "\nFOR UPDATE"
+	}
+
+	var tokenObj auth.EmailChangeToken
+	if err := pgxscan.Get(ctx, conn, &tokenObj, query, tokenValue); err != nil {
+		if noRowsErr(err) {
+			return nil, auth.ErrEmailChangeTokenNotFound
+		}
+		log.ErrorCtx(ctx, "Failed to get email change token", err)
+		return nil, err
+	}
+
+	return &tokenObj, nil
+}
+
+// StoreEmailChangeToken implements [auth.Repository]
+func (r *authRepository) StoreEmailChangeToken(
+	ctx context.Context,
+	token *auth.EmailChangeToken,
+) error {
+	if token == nil {
+		log.WarnCtx(ctx, "StoreEmailChangeToken called with nil token ptr")
+		return errors.New("email change token is nil")
+	}
+
+	query := "INSERT INTO email_change_tokens(user_id, new_email, value, expires_at) VALUES($1, $2, $3, $4)"
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(ctx, query, token.UserID, token.NewEmail, token.Value, token.ExpiresAt); err != nil {
+		log.ErrorCtx(ctx, "Failed to store email change token", err)
+		return err
+	}
+
+	return nil
+}
+
+// UpdateEmailChangeToken implements [auth.Repository]
+func (r *authRepository) UpdateEmailChangeToken(
+	ctx context.Context,
+	token *auth.EmailChangeToken,
+) error {
+	if token == nil {
+		log.WarnCtx(ctx, "UpdateEmailChangeToken called with nil token object")
+		return errors.New("email change token is nil")
+	}
+
+	query := "UPDATE email_change_tokens SET used_at=$1 WHERE value=$2"
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(ctx, query, token.UsedAt, token.Value); err != nil {
+		log.ErrorCtx(ctx, "Failed to update email change token", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetMagicLinkToken implements [auth.Repository]
+func (r *authRepository) GetMagicLinkToken(
+	ctx context.Context,
+	tokenValue string,
+) (*auth.MagicLinkToken, error) {
+	query := "SELECT user_id, value, expires_at, used_at FROM magic_link_tokens WHERE value=$1"
+
+	conn := r.db.GetConn(ctx)
+	if r.db.IsTxConn(conn) {
+		query += "\nFOR UPDATE"
+	}
+
+	var tokenObj auth.MagicLinkToken
+	if err := pgxscan.Get(ctx, conn, &tokenObj, query, tokenValue); err != nil {
+		if noRowsErr(err) {
+			return nil, auth.ErrMagicLinkTokenNotFound
+		}
+		log.ErrorCtx(ctx, "Failed to get magic link token", err)
+		return nil, err
+	}
+
+	return &tokenObj, nil
+}
+
+// StoreMagicLinkToken implements [auth.Repository]
+func (r *authRepository) StoreMagicLinkToken(
+	ctx context.Context,
+	token *auth.MagicLinkToken,
+) error {
+	if token == nil {
+		log.WarnCtx(ctx, "StoreMagicLinkToken called with nil token ptr")
+		return errors.New("magic link token is nil")
+	}
+
+	query := "INSERT INTO magic_link_tokens(user_id, value, expires_at) VALUES($1, $2, $3)"
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(ctx, query, token.UserID, token.Value, token.ExpiresAt); err != nil {
+		log.ErrorCtx(ctx, "Failed to store magic link token", err)
+		return err
+	}
+
+	return nil
+}
+
+// UpdateMagicLinkToken implements [auth.Repository]
+func (r *authRepository) UpdateMagicLinkToken(
+	ctx context.Context,
+	token *auth.MagicLinkToken,
+) error {
+	if token == nil {
+		log.WarnCtx(ctx, "UpdateMagicLinkToken called with nil token object")
+		return errors.New("magic link token is nil")
+	}
+
+	query := "UPDATE magic_link_tokens SET used_at=$1 WHERE value=$2"
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(ctx, query, token.UsedAt, token.Value); err != nil {
+		log.ErrorCtx(ctx, "Failed to update magic link token", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetExternalIdentity implements [auth.Repository]
+func (r *authRepository) GetExternalIdentity(
+	ctx context.Context,
+	provider, subject string,
+) (*auth.ExternalIdentity, error) {
+	query := strs.Concatenate(
+		"SELECT user_id, provider, subject, email, created_at FROM external_identities ",
+		"WHERE provider=$1 AND subject=$2",
+	)
+
+	conn := r.db.GetConn(ctx)
+
+	var identity auth.ExternalIdentity
+	if err := pgxscan.Get(ctx, conn, &identity, query, provider, subject); err != nil {
+		if noRowsErr(err) {
+			return nil, auth.ErrExternalIdentityNotFound
+		}
+		log.ErrorCtx(ctx, "Failed to get external identity", err)
+		return nil, err
+	}
+
+	return &identity, nil
+}
+
+// StoreExternalIdentity implements [auth.Repository]
+func (r *authRepository) StoreExternalIdentity(
+	ctx context.Context,
+	identity *auth.ExternalIdentity,
+) error {
+	if identity == nil {
+		log.WarnCtx(ctx, "StoreExternalIdentity called with nil identity ptr")
+		return errors.New("external identity is nil")
+	}
+
+	query := strs.Concatenate(
+		"INSERT INTO external_identities(provider, subject, user_id, email, created_at) ",
+		"VALUES($1, $2, $3, $4, $5)",
+	)
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(
+		ctx,
+		query,
+		identity.Provider,
+		identity.Subject,
+		identity.UserID,
+		identity.Email,
+		identity.CreatedAt,
+	); err != nil {
+		log.ErrorCtx(ctx, "Failed to store external identity", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetAccountUnlockToken implements [auth.Repository]
+func (r *authRepository) GetAccountUnlockToken(
+	ctx context.Context,
+	tokenValue string,
+) (*auth.AccountUnlockToken, error) {
+	query := "SELECT user_id, value, expires_at, used_at FROM account_unlock_tokens WHERE value=$1"
+
+	conn := r.db.GetConn(ctx)
+	if r.db.IsTxConn(conn) {
+		query += "\nFOR UPDATE"
+	}
+
+	var tokenObj auth.AccountUnlockToken
+	if err := pgxscan.Get(ctx, conn, &tokenObj, query, tokenValue); err != nil {
+		if noRowsErr(err) {
+			return nil, auth.ErrAccountUnlockTokenNotFound
+		}
+		log.ErrorCtx(ctx, "Failed to get account unlock token", err)
+		return nil, err
+	}
+
+	return &tokenObj, nil
+}
+
+// StoreAccountUnlockToken implements [auth.Repository]
+func (r *authRepository) StoreAccountUnlockToken(
+	ctx context.Context,
+	token *auth.AccountUnlockToken,
+) error {
+	if token == nil {
+		log.WarnCtx(ctx, "StoreAccountUnlockToken called with nil token ptr")
+		return errors.New("account unlock token is nil")
+	}
+
+	query := "INSERT INTO account_unlock_tokens(user_id, value, expires_at) VALUES($1, $2, $3)"
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(ctx, query, token.UserID, token.Value, token.ExpiresAt); err != nil {
+		log.ErrorCtx(ctx, "Failed to store account unlock token", err)
+		return err
+	}
+
+	return nil
+}
+
+// UpdateAccountUnlockToken implements [auth.Repository]
+func (r *authRepository) UpdateAccountUnlockToken(
+	ctx context.Context,
+	token *auth.AccountUnlockToken,
+) error {
+	if token == nil {
+		log.WarnCtx(ctx, "UpdateAccountUnlockToken called with nil token object")
+		return errors.New("account unlock token is nil")
+	}
+
+	query := "UPDATE account_unlock_tokens SET used_at=$1 WHERE value=$2"
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(ctx, query, token.UsedAt, token.Value); err != nil {
+		log.ErrorCtx(ctx, "Failed to update account unlock token", err)
+		return err
+	}
+
+	return nil
+}
+
+const knownDeviceColumns = "id, user_id, fingerprint, user_agent, ip_address, created_at, last_seen_at"
+
+// StoreKnownDevice implements [auth.Repository]
+func (r *authRepository) StoreKnownDevice(ctx context.Context, device *auth.KnownDevice) error {
+	if device == nil {
+		log.WarnCtx(ctx, "StoreKnownDevice called with nil device ptr")
+		return errors.New("known device is nil")
+	}
+
+	query := strs.Concatenate(
+		"INSERT INTO known_devices(", knownDeviceColumns, ") VALUES($1, $2, $3, $4, $5, $6, $7)",
+	)
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(
+		ctx,
+		query,
+		device.ID,
+		device.UserID,
+		device.Fingerprint,
+		device.UserAgent,
+		device.IPAddress,
+		device.CreatedAt,
+		device.LastSeenAt,
+	); err != nil {
+		log.ErrorCtx(ctx, "Failed to store known device", err)
+		return err
+	}
+
+	return nil
+}
+
+// ListKnownDevicesByUser implements [auth.Repository]
+func (r *authRepository) ListKnownDevicesByUser(ctx context.Context, userID string) ([]*auth.KnownDevice, error) {
+	query := strs.Concatenate(
+		"SELECT ", knownDeviceColumns, " FROM known_devices ",
+		"WHERE user_id=$1 ORDER BY last_seen_at DESC",
+	)
+	conn := r.db.GetConn(ctx)
+
+	devices := []*auth.KnownDevice{}
+	if err := pgxscan.Select(ctx, conn, &devices, query, userID); err != nil {
+		log.ErrorCtx(ctx, "Failed to list known devices", err)
+		return nil, err
+	}
+
+	return devices, nil
+}
+
+// UpdateKnownDevice implements [auth.Repository]
+func (r *authRepository) UpdateKnownDevice(ctx context.Context, device *auth.KnownDevice) error {
+	if device == nil {
+		log.WarnCtx(ctx, "UpdateKnownDevice called with nil device ptr")
+		return errors.New("known device is nil")
+	}
+
+	query := "UPDATE known_devices SET user_agent=$1, ip_address=$2, last_seen_at=$3 WHERE id=$4"
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(
+		ctx,
+		query,
+		device.UserAgent,
+		device.IPAddress,
+		device.LastSeenAt,
+		device.ID,
+	); err != nil {
+		log.ErrorCtx(ctx, "Failed to update known device", err)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteKnownDevice implements [auth.Repository]
+func (r *authRepository) DeleteKnownDevice(ctx context.Context, userID, deviceID string) error {
+	query := "DELETE FROM known_devices WHERE id::TEXT=$1 AND user_id=$2"
+	conn := r.db.GetConn(ctx)
+
+	tag, err := conn.Exec(ctx, query, deviceID, userID)
+	if err != nil {
+		log.ErrorCtx(ctx, "Failed to delete known device", err)
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return auth.ErrKnownDeviceNotFound
+	}
+
+	return nil
+}
+
+// GetSessionRevokeToken implements [auth.Repository]
+func (r *authRepository) GetSessionRevokeToken(
+	ctx context.Context,
+	tokenValue string,
+) (*auth.SessionRevokeToken, error) {
+	query := "SELECT user_id, family_id, device_id, value, expires_at, used_at FROM session_revoke_tokens WHERE value=$1"
+
+	conn := r.db.GetConn(ctx)
+	if r.db.IsTxConn(conn) {
+		query += "\nFOR UPDATE"
+	}
+
+	var tokenObj auth.SessionRevokeToken
+	if err := pgxscan.Get(ctx, conn, &tokenObj, query, tokenValue); err != nil {
+		if noRowsErr(err) {
+			return nil, auth.ErrSessionRevokeTokenNotFound
+		}
+		log.ErrorCtx(ctx, "Failed to get session revoke token", err)
+		return nil, err
+	}
+
+	return &tokenObj, nil
+}
+
+// StoreSessionRevokeToken implements [auth.Repository]
+func (r *authRepository) StoreSessionRevokeToken(
+	ctx context.Context,
+	token *auth.SessionRevokeToken,
+) error {
+	if token == nil {
+		log.WarnCtx(ctx, "StoreSessionRevokeToken called with nil token ptr")
+		return errors.New("session revoke token is nil")
+	}
+
+	query := strs.Concatenate(
+		"INSERT INTO session_revoke_tokens(user_id, family_id, device_id, value, expires_at) ",
+		"VALUES($1, $2, $3, $4, $5)",
+	)
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(
+		ctx,
+		query,
+		token.UserID,
+		token.FamilyID,
+		token.DeviceID,
+		token.Value,
+		token.ExpiresAt,
+	); err != nil {
+		log.ErrorCtx(ctx, "Failed to store session revoke token", err)
+		return err
+	}
+
+	return nil
+}
+
+// UpdateSessionRevokeToken implements [auth.Repository]
+func (r *authRepository) UpdateSessionRevokeToken(
+	ctx context.Context,
+	token *auth.SessionRevokeToken,
+) error {
+	if token == nil {
+		log.WarnCtx(ctx, "UpdateSessionRevokeToken called with nil token object")
+		return errors.New("session revoke token is nil")
+	}
+
+	query := "UPDATE session_revoke_tokens SET used_at=$1 WHERE value=$2"
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(ctx, query, token.UsedAt, token.Value); err != nil {
+		log.ErrorCtx(ctx, "Failed to update session revoke token", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetLoginAttempts implements [auth.Repository]
+func (r *authRepository) GetLoginAttempts(ctx context.Context, key string) (*auth.LoginAttempts, error) {
+	query := "SELECT key, failures, last_failed_at, locked_until FROM login_attempts WHERE key=$1"
+
+	conn := r.db.GetConn(ctx)
+	if r.db.IsTxConn(conn) {
+		query += "\nFOR UPDATE"
+	}
+
+	var attempts auth.LoginAttempts
+	if err := pgxscan.Get(ctx, conn, &attempts, query, key); err != nil {
+		if noRowsErr(err) {
+			return &auth.LoginAttempts{Key: key}, nil
+		}
+		log.ErrorCtx(ctx, "Failed to get login attempts", err)
+		return nil, err
+	}
+
+	return &attempts, nil
+}
+
+// StoreLoginAttempts implements [auth.Repository]
+func (r *authRepository) StoreLoginAttempts(ctx context.Context, attempts *auth.LoginAttempts) error {
+	if attempts == nil {
+		log.WarnCtx(ctx, "StoreLoginAttempts called with nil attempts ptr")
+		return errors.New("login attempts is nil")
+	}
+
+	query := strs.Concatenate(
+		"INSERT INTO login_attempts(key, failures, last_failed_at, locked_until) VALUES($1, $2, $3, $4) ",
+		"ON CONFLICT (key) DO UPDATE SET failures=EXCLUDED.failures, last_failed_at=EXCLUDED.last_failed_at, ",
+		"locked_until=EXCLUDED.locked_until",
+	)
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(
+		ctx,
+		query,
+		attempts.Key,
+		attempts.Failures,
+		attempts.LastFailedAt,
+		attempts.LockedUntil,
+	); err != nil {
+		log.ErrorCtx(ctx, "Failed to store login attempts", err)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteLoginAttempts implements [auth.Repository]
+func (r *authRepository) DeleteLoginAttempts(ctx context.Context, key string) error {
+	query := "DELETE FROM login_attempts WHERE key=$1"
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(ctx, query, key); err != nil {
+		log.ErrorCtx(ctx, "Failed to delete login attempts", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetPasswordHistory implements [auth.Repository]
+func (r *authRepository) GetPasswordHistory(ctx context.Context, userID string, limit int) ([]string, error) {
+	query := "SELECT password_hash FROM password_history WHERE user_id=$1 ORDER BY created_at DESC, id DESC LIMIT $2"
+	conn := r.db.GetConn(ctx)
+
+	var hashes []string
+	if err := pgxscan.Select(ctx, conn, &hashes, query, userID, limit); err != nil {
+		log.ErrorCtx(ctx, "Failed to get password history", err)
+		return nil, err
+	}
+
+	return hashes, nil
+}
+
+// StorePasswordHistory implements [auth.Repository]
+func (r *authRepository) StorePasswordHistory(ctx context.Context, userID, passwordHash string, keep int) error {
+	conn := r.db.GetConn(ctx)
+
+	query := "INSERT INTO password_history(user_id, password_hash) VALUES($1, $2)"
+	if _, err := conn.Exec(ctx, query, userID, passwordHash); err != nil {
+		log.ErrorCtx(ctx, "Failed to store password history", err)
+		return err
+	}
+
+	query = strs.Concatenate(
+		"DELETE FROM password_history WHERE user_id=$1 AND id NOT IN ",
+		"(SELECT id FROM password_history WHERE user_id=$1 ORDER BY created_at DESC, id DESC LIMIT $2)",
+	)
+	if _, err := conn.Exec(ctx, query, userID, keep); err != nil {
+		log.ErrorCtx(ctx, "Failed to prune password history", err)
+		return err
+	}
+
+	return nil
+}
+
+// StoreTOTPFactor implements [auth.Repository]
+func (r *authRepository) StoreTOTPFactor(ctx context.Context, factor *auth.TOTPFactor) error {
+	if factor == nil {
+		log.WarnCtx(ctx, "StoreTOTPFactor called with nil factor ptr")
+		return errors.New("totp factor is nil")
+	}
+
+	query := strs.Concatenate(
+		"INSERT INTO totp_factors(user_id, secret, confirmed_at, last_used_step, created_at) VALUES($1, $2, $3, $4, $5) ",
+		"ON CONFLICT (user_id) DO UPDATE SET secret=EXCLUDED.secret, confirmed_at=EXCLUDED.confirmed_at, ",
+		"last_used_step=EXCLUDED.last_used_step, created_at=EXCLUDED.created_at",
+	)
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(
+		ctx,
+		query,
+		factor.UserID,
+		factor.Secret,
+		factor.ConfirmedAt,
+		factor.LastUsedStep,
+		factor.CreatedAt,
+	); err != nil {
+		log.ErrorCtx(ctx, "Failed to store totp factor", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetTOTPFactor implements [auth.Repository]
+func (r *authRepository) GetTOTPFactor(ctx context.Context, userID string) (*auth.TOTPFactor, error) {
+	query := "SELECT user_id, secret, confirmed_at, last_used_step, created_at FROM totp_factors WHERE user_id=$1"
+
+	conn := r.db.GetConn(ctx)
+	if r.db.IsTxConn(conn) {
+		query += "\nFOR UPDATE"
+	}
+
+	var factor auth.TOTPFactor
+	if err := pgxscan.Get(ctx, conn, &factor, query, userID); err != nil {
+		if noRowsErr(err) {
+			return nil, auth.ErrTOTPFactorNotFound
+		}
+		log.ErrorCtx(ctx, "Failed to get totp factor", err)
+		return nil, err
+	}
+
+	return &factor, nil
+}
+
+// UpdateTOTPFactor implements [auth.Repository]
+func (r *authRepository) UpdateTOTPFactor(ctx context.Context, factor *auth.TOTPFactor) error {
+	if factor == nil {
+		log.WarnCtx(ctx, "UpdateTOTPFactor called with nil factor ptr")
+		return errors.New("totp factor is nil")
+	}
+
+	query := "UPDATE totp_factors SET confirmed_at=$1, last_used_step=$2 WHERE user_id=$3"
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(ctx, query, factor.ConfirmedAt, factor.LastUsedStep, factor.UserID); err != nil {
+		log.ErrorCtx(ctx, "Failed to update totp factor", err)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteTOTPFactor implements [auth.Repository]
+func (r *authRepository) DeleteTOTPFactor(ctx context.Context, userID string) error {
+	query := "DELETE FROM totp_factors WHERE user_id=$1"
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(ctx, query, userID); err != nil {
+		log.ErrorCtx(ctx, "Failed to delete totp factor", err)
+		return err
+	}
+
+	return nil
+}
+
+// StoreRecoveryCodes implements [auth.Repository]
+func (r *authRepository) StoreRecoveryCodes(
+	ctx context.Context,
+	userID string,
+	codes []*auth.RecoveryCode,
+) error {
+	batch := &pgx.Batch{}
+	batch.Queue("DELETE FROM recovery_codes WHERE user_id=$1", userID)
+	for _, code := range codes {
+		batch.Queue(
+			"INSERT INTO recovery_codes(user_id, code_hash) VALUES($1, $2)",
+			code.UserID,
+			code.CodeHash,
+		)
+	}
+
+	conn := r.db.GetConn(ctx)
+	if err := conn.SendBatch(ctx, batch).Close(); err != nil {
+		log.ErrorCtx(ctx, "Failed to store recovery codes", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetRecoveryCode implements [auth.Repository]
+func (r *authRepository) GetRecoveryCode(
+	ctx context.Context,
+	userID string,
+	codeHash string,
+) (*auth.RecoveryCode, error) {
+	query := "SELECT user_id, code_hash, used_at FROM recovery_codes WHERE user_id=$1 AND code_hash=$2"
+
+	conn := r.db.GetConn(ctx)
+	if r.db.IsTxConn(conn) {
+		query += "\nFOR UPDATE"
+	}
+
+	var code auth.RecoveryCode
+	if err := pgxscan.Get(ctx, conn, &code, query, userID, codeHash); err != nil {
+		if noRowsErr(err) {
+			return nil, auth.ErrRecoveryCodeNotFound
+		}
+		log.ErrorCtx(ctx, "Failed to get recovery code", err)
+		return nil, err
+	}
+
+	return &code, nil
+}
+
+// UpdateRecoveryCode implements [auth.Repository]
+func (r *authRepository) UpdateRecoveryCode(ctx context.Context, code *auth.RecoveryCode) error {
+	if code == nil {
+		log.WarnCtx(ctx, "UpdateRecoveryCode called with nil code ptr")
+		return errors.New("recovery code is nil")
+	}
+
+	query := "UPDATE recovery_codes SET used_at=$1 WHERE user_id=$2 AND code_hash=$3"
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(ctx, query, code.UsedAt, code.UserID, code.CodeHash); err != nil {
+		log.ErrorCtx(ctx, "Failed to update recovery code", err)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteRecoveryCodes implements [auth.Repository]
+func (r *authRepository) DeleteRecoveryCodes(ctx context.Context, userID string) error {
+	query := "DELETE FROM recovery_codes WHERE user_id=$1"
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(ctx, query, userID); err != nil {
+		log.ErrorCtx(ctx, "Failed to delete recovery codes", err)
+		return err
+	}
+
+	return nil
+}
+
+// StoreMFAChallenge implements [auth.Repository]
+func (r *authRepository) StoreMFAChallenge(ctx context.Context, challenge *auth.MFAChallenge) error {
+	if challenge == nil {
+		log.WarnCtx(ctx, "StoreMFAChallenge called with nil challenge ptr")
+		return errors.New("mfa challenge is nil")
+	}
+
+	query := strs.Concatenate(
+		"INSERT INTO mfa_challenges(value, user_id, user_agent, first_factor, expires_at) ",
+		"VALUES($1, $2, $3, $4, $5)",
+	)
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(
+		ctx,
+		query,
+		challenge.Value,
+		challenge.UserID,
+		challenge.UserAgent,
+		challenge.FirstFactor,
+		challenge.ExpiresAt,
+	); err != nil {
+		log.ErrorCtx(ctx, "Failed to store mfa challenge", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetMFAChallenge implements [auth.Repository]
+func (r *authRepository) GetMFAChallenge(ctx context.Context, value string) (*auth.MFAChallenge, error) {
+	query := strs.Concatenate(
+		"SELECT value, user_id, user_agent, first_factor, attempts, expires_at, used_at ",
+		"FROM mfa_challenges WHERE value=$1",
+	)
+
+	conn := r.db.GetConn(ctx)
+	if r.db.IsTxConn(conn) {
+		query += "\nFOR UPDATE"
+	}
+
+	var challenge auth.MFAChallenge
+	if err := pgxscan.Get(ctx, conn, &challenge, query, value); err != nil {
+		if noRowsErr(err) {
+			return nil, auth.ErrMFAChallengeNotFound
+		}
+		log.ErrorCtx(ctx, "Failed to get mfa challenge", err)
+		return nil, err
+	}
+
+	return &challenge, nil
+}
+
+// UpdateMFAChallenge implements [auth.Repository]
+func (r *authRepository) UpdateMFAChallenge(ctx context.Context, challenge *auth.MFAChallenge) error {
+	if challenge == nil {
+		log.WarnCtx(ctx, "UpdateMFAChallenge called with nil challenge ptr")
+		return errors.New("mfa challenge is nil")
+	}
+
+	query := "UPDATE mfa_challenges SET attempts=$1, used_at=$2 WHERE value=$3"
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(ctx, query, challenge.Attempts, challenge.UsedAt, challenge.Value); err != nil {
+		log.ErrorCtx(ctx, "Failed to update mfa challenge", err)
+		return err
+	}
+
+	return nil
+}
+
+const personalAccessTokenColumns = "id, user_id, name, scopes, token_hash, expires_at, last_used_at, created_at"
+
+// StorePersonalAccessToken implements [auth.Repository]
+func (r *authRepository) StorePersonalAccessToken(ctx context.Context, token *auth.PersonalAccessToken) error {
+	if token == nil {
+		log.WarnCtx(ctx, "StorePersonalAccessToken called with nil token ptr")
+		return errors.New("personal access token is nil")
+	}
+
+	query := strs.Concatenate(
+		"INSERT INTO personal_access_tokens(", personalAccessTokenColumns, ") ",
+		"VALUES($1, $2, $3, $4, $5, $6, $7, $8)",
+	)
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(
+		ctx,
+		query,
+		token.ID,
+		token.UserID,
+		token.Name,
+		token.Scopes,
+		token.TokenHash,
+		token.ExpiresAt,
+		token.LastUsedAt,
+		token.CreatedAt,
+	); err != nil {
+		log.ErrorCtx(ctx, "Failed to store personal access token", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetPersonalAccessTokenByHash implements [auth.Repository]
+func (r *authRepository) GetPersonalAccessTokenByHash(
+	ctx context.Context,
+	tokenHash string,
+) (*auth.PersonalAccessToken, error) {
+	query := strs.Concatenate(
+		"SELECT ", personalAccessTokenColumns, " FROM personal_access_tokens WHERE token_hash=$1",
+	)
+	conn := r.db.GetConn(ctx)
+
+	var token auth.PersonalAccessToken
+	if err := pgxscan.Get(ctx, conn, &token, query, tokenHash); err != nil {
+		if noRowsErr(err) {
+			return nil, auth.ErrPersonalAccessTokenNotFound
+		}
+		log.ErrorCtx(ctx, "Failed to get personal access token", err)
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// ListPersonalAccessTokensByUser implements [auth.Repository]
+func (r *authRepository) ListPersonalAccessTokensByUser(
+	ctx context.Context,
+	userID string,
+) ([]*auth.PersonalAccessToken, error) {
+	query := strs.Concatenate(
+		"SELECT ", personalAccessTokenColumns, " FROM personal_access_tokens ",
+		"WHERE user_id=$1 ORDER BY created_at DESC",
+	)
+	conn := r.db.GetConn(ctx)
+
+	tokens := []*auth.PersonalAccessToken{}
+	if err := pgxscan.Select(ctx, conn, &tokens, query, userID); err != nil {
+		log.ErrorCtx(ctx, "Failed to list personal access tokens", err)
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// UpdatePersonalAccessToken implements [auth.Repository]
+func (r *authRepository) UpdatePersonalAccessToken(ctx context.Context, token *auth.PersonalAccessToken) error {
+	if token == nil {
+		log.WarnCtx(ctx, "UpdatePersonalAccessToken called with nil token ptr")
+		return errors.New("personal access token is nil")
+	}
+
+	query := "UPDATE personal_access_tokens SET last_used_at=$1 WHERE id=$2"
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(ctx, query, token.LastUsedAt, token.ID); err != nil {
+		log.ErrorCtx(ctx, "Failed to update personal access token", err)
+		return err
+	}
+
+	return nil
+}
+
+// DeletePersonalAccessToken implements [auth.Repository]
+func (r *authRepository) DeletePersonalAccessToken(ctx context.Context, userID, tokenID string) error {
+	query := "DELETE FROM personal_access_tokens WHERE id::TEXT=$1 AND user_id=$2"
+	conn := r.db.GetConn(ctx)
+
+	tag, err := conn.Exec(ctx, query, tokenID, userID)
+	if err != nil {
+		log.ErrorCtx(ctx, "Failed to delete personal access token", err)
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return auth.ErrPersonalAccessTokenNotFound
+	}
+
+	return nil
+}
+
+const webAuthnCredentialColumns = "id, user_id, name, public_key, attestation_type, transports, aaguid, " +
+	"sign_count, backup_eligible, backup_state, last_used_at, created_at"
+
+// StoreWebAuthnCredential implements [auth.Repository]
+func (r *authRepository) StoreWebAuthnCredential(ctx context.Context, credential *auth.WebAuthnCredential) error {
+	if credential == nil {
+		log.WarnCtx(ctx, "StoreWebAuthnCredential called with nil credential ptr")
+		return errors.New("webauthn credential is nil")
+	}
+
+	query := strs.Concatenate(
+		"INSERT INTO webauthn_credentials(", webAuthnCredentialColumns, ") ",
+		"VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)",
+	)
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(
+		ctx,
+		query,
+		credential.ID,
+		credential.UserID,
+		credential.Name,
+		credential.PublicKey,
+		credential.AttestationType,
+		credential.Transports,
+		credential.AAGUID,
+		credential.SignCount,
+		credential.BackupEligible,
+		credential.BackupState,
+		credential.LastUsedAt,
+		credential.CreatedAt,
+	); err != nil {
+		log.ErrorCtx(ctx, "Failed to store webauthn credential", err)
+		return err
+	}
+
+	return nil
+}
+
+// ListWebAuthnCredentialsByUser implements [auth.Repository]
+func (r *authRepository) ListWebAuthnCredentialsByUser(
+	ctx context.Context,
+	userID string,
+) ([]*auth.WebAuthnCredential, error) {
+	query := strs.Concatenate(
+		"SELECT ", webAuthnCredentialColumns, " FROM webauthn_credentials ",
+		"WHERE user_id=$1 ORDER BY created_at",
+	)
+	conn := r.db.GetConn(ctx)
+
+	credentials := []*auth.WebAuthnCredential{}
+	if err := pgxscan.Select(ctx, conn, &credentials, query, userID); err != nil {
+		log.ErrorCtx(ctx, "Failed to list webauthn credentials", err)
+		return nil, err
+	}
+
+	return credentials, nil
+}
+
+// UpdateWebAuthnCredential implements [auth.Repository]
+func (r *authRepository) UpdateWebAuthnCredential(ctx context.Context, credential *auth.WebAuthnCredential) error {
+	if credential == nil {
+		log.WarnCtx(ctx, "UpdateWebAuthnCredential called with nil credential ptr")
+		return errors.New("webauthn credential is nil")
+	}
+
+	query := "UPDATE webauthn_credentials SET sign_count=$1, backup_state=$2, last_used_at=$3 WHERE id=$4"
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(
+		ctx,
+		query,
+		credential.SignCount,
+		credential.BackupState,
+		credential.LastUsedAt,
+		credential.ID,
+	); err != nil {
+		log.ErrorCtx(ctx, "Failed to update webauthn credential", err)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteWebAuthnCredential implements [auth.Repository]
+func (r *authRepository) DeleteWebAuthnCredential(ctx context.Context, userID, credentialID string) error {
+	query := "DELETE FROM webauthn_credentials WHERE id=$1 AND user_id=$2"
+	conn := r.db.GetConn(ctx)
+
+	tag, err := conn.Exec(ctx, query, credentialID, userID)
+	if err != nil {
+		log.ErrorCtx(ctx, "Failed to delete webauthn credential", err)
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return auth.ErrWebAuthnCredentialNotFound
+	}
+
+	return nil
+}
+
+// StoreWebAuthnChallenge implements [auth.Repository]
+func (r *authRepository) StoreWebAuthnChallenge(ctx context.Context, challenge *auth.WebAuthnChallenge) error {
+	if challenge == nil {
+		log.WarnCtx(ctx, "StoreWebAuthnChallenge called with nil challenge ptr")
+		return errors.New("webauthn challenge is nil")
+	}
+
+	query := strs.Concatenate(
+		"INSERT INTO webauthn_challenges(value, user_id, ceremony, state, expires_at) ",
+		"VALUES($1, $2, $3, $4, $5)",
+	)
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(
+		ctx,
+		query,
+		challenge.Value,
+		challenge.UserID,
+		challenge.Ceremony,
+		challenge.State,
+		challenge.ExpiresAt,
+	); err != nil {
+		log.ErrorCtx(ctx, "Failed to store webauthn challenge", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetWebAuthnChallenge implements [auth.Repository]
+func (r *authRepository) GetWebAuthnChallenge(ctx context.Context, value string) (*auth.WebAuthnChallenge, error) {
+	query := strs.Concatenate(
+		"SELECT value, user_id, ceremony, state, expires_at, used_at ",
+		"FROM webauthn_challenges WHERE value=$1",
+	)
+
+	conn := r.db.GetConn(ctx)
+	if r.db.IsTxConn(conn) {
+		query += "\nFOR UPDATE"
+	}
+
+	var challenge auth.WebAuthnChallenge
+	if err := pgxscan.Get(ctx, conn, &challenge, query, value); err != nil {
+		if noRowsErr(err) {
+			return nil, auth.ErrWebAuthnChallengeNotFound
+		}
+		log.ErrorCtx(ctx, "Failed to get webauthn challenge", err)
+		return nil, err
+	}
+
+	return &challenge, nil
+}
+
+// UpdateWebAuthnChallenge implements [auth.Repository]
+func (r *authRepository) UpdateWebAuthnChallenge(ctx context.Context, challenge *auth.WebAuthnChallenge) error {
+	if challenge == nil {
+		log.WarnCtx(ctx, "UpdateWebAuthnChallenge called with nil challenge ptr")
+		return errors.New("webauthn challenge is nil")
+	}
+
+	query := "UPDATE webauthn_challenges SET used_at=$1 WHERE value=$2"
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(ctx, query, challenge.UsedAt, challenge.Value); err != nil {
+		log.ErrorCtx(ctx, "Failed to update webauthn challenge", err)
+		return err
+	}
+
+	return nil
+}
@@ -19,3 +19,23 @@ func (f *RepositoryFactory) User() *userRepository {
 func (f *RepositoryFactory) Auth() *authRepository {
 	return NewAuthRepository(f.pool)
 }
+
+func (f *RepositoryFactory) Role() *roleRepository {
+	return NewRoleRepository(f.pool)
+}
+
+func (f *RepositoryFactory) OAuth() *oauthRepository {
+	return NewOAuthRepository(f.pool)
+}
+
+func (f *RepositoryFactory) TokenRevocation() *tokenRevocationRepository {
+	return NewTokenRevocationRepository(f.pool)
+}
+
+func (f *RepositoryFactory) Audit() *auditRepository {
+	return NewAuditRepository(f.pool)
+}
+
+func (f *RepositoryFactory) Organization() *organizationRepository {
+	return NewOrganizationRepository(f.pool)
+}
@@ -0,0 +1,299 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prawirdani/golang-restapi/internal/domain/auth"
+	"github.com/prawirdani/golang-restapi/pkg/log"
+	strs "github.com/prawirdani/golang-restapi/pkg/strings"
+)
+
+type oauthRepository struct {
+	db *db
+}
+
+func NewOAuthRepository(pool *pgxpool.Pool) *oauthRepository {
+	return &oauthRepository{
+		db: &db{pool: pool},
+	}
+}
+
+// StoreOAuthClient implements [auth.OAuthRepository]
+func (r *oauthRepository) StoreOAuthClient(ctx context.Context, client *auth.OAuthClient) error {
+	if client == nil {
+		log.WarnCtx(ctx, "StoreOAuthClient called with nil client ptr")
+		return errors.New("oauth client is nil")
+	}
+
+	query := strs.Concatenate(
+		"INSERT INTO oauth_clients(id, name, secret_hash, redirect_uris, grant_types, scopes, created_at) ",
+		"VALUES($1, $2, $3, $4, $5, $6, $7)",
+	)
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(
+		ctx,
+		query,
+		client.ID,
+		client.Name,
+		client.SecretHash,
+		client.RedirectURIs,
+		client.GrantTypes,
+		client.Scopes,
+		client.CreatedAt,
+	); err != nil {
+		log.ErrorCtx(ctx, "Failed to store oauth client", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetOAuthClient implements [auth.OAuthRepository]
+func (r *oauthRepository) GetOAuthClient(ctx context.Context, clientID string) (*auth.OAuthClient, error) {
+	query := strs.Concatenate(
+		"SELECT id, name, secret_hash, redirect_uris, grant_types, scopes, created_at ",
+		"FROM oauth_clients WHERE id=$1",
+	)
+	conn := r.db.GetConn(ctx)
+
+	var client auth.OAuthClient
+	if err := pgxscan.Get(ctx, conn, &client, query, clientID); err != nil {
+		if noRowsErr(err) {
+			return nil, auth.ErrOAuthClientNotFound
+		}
+		log.ErrorCtx(ctx, "Failed to get oauth client", err)
+		return nil, err
+	}
+
+	return &client, nil
+}
+
+// ListOAuthClients implements [auth.OAuthRepository]
+func (r *oauthRepository) ListOAuthClients(ctx context.Context) ([]*auth.OAuthClient, error) {
+	query := strs.Concatenate(
+		"SELECT id, name, secret_hash, redirect_uris, grant_types, scopes, created_at ",
+		"FROM oauth_clients ORDER BY created_at DESC",
+	)
+	conn := r.db.GetConn(ctx)
+
+	clients := []*auth.OAuthClient{}
+	if err := pgxscan.Select(ctx, conn, &clients, query); err != nil {
+		log.ErrorCtx(ctx, "Failed to list oauth clients", err)
+		return nil, err
+	}
+
+	return clients, nil
+}
+
+// DeleteOAuthClient implements [auth.OAuthRepository]
+func (r *oauthRepository) DeleteOAuthClient(ctx context.Context, clientID string) error {
+	query := "DELETE FROM oauth_clients WHERE id=$1"
+	conn := r.db.GetConn(ctx)
+
+	tag, err := conn.Exec(ctx, query, clientID)
+	if err != nil {
+		log.ErrorCtx(ctx, "Failed to delete oauth client", err)
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return auth.ErrOAuthClientNotFound
+	}
+
+	return nil
+}
+
+// StoreOAuthAuthorizationCode implements [auth.OAuthRepository]
+func (r *oauthRepository) StoreOAuthAuthorizationCode(
+	ctx context.Context,
+	code *auth.OAuthAuthorizationCode,
+) error {
+	if code == nil {
+		log.WarnCtx(ctx, "StoreOAuthAuthorizationCode called with nil code ptr")
+		return errors.New("oauth authorization code is nil")
+	}
+
+	query := strs.Concatenate(
+		"INSERT INTO oauth_authorization_codes",
+		"(code_hash, client_id, user_id, redirect_uri, scopes, code_challenge, expires_at) ",
+		"VALUES($1, $2, $3, $4, $5, $6, $7)",
+	)
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(
+		ctx,
+		query,
+		code.CodeHash,
+		code.ClientID,
+		code.UserID,
+		code.RedirectURI,
+		code.Scopes,
+		code.CodeChallenge,
+		code.ExpiresAt,
+	); err != nil {
+		log.ErrorCtx(ctx, "Failed to store oauth authorization code", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetOAuthAuthorizationCode implements [auth.OAuthRepository]
+func (r *oauthRepository) GetOAuthAuthorizationCode(
+	ctx context.Context,
+	codeHash string,
+) (*auth.OAuthAuthorizationCode, error) {
+	query := strs.Concatenate(
+		"SELECT code_hash, client_id, user_id, redirect_uri, scopes, code_challenge, expires_at, used_at ",
+		"FROM oauth_authorization_codes WHERE code_hash=$1",
+	)
+
+	conn := r.db.GetConn(ctx)
+	if r.db.IsTxConn(conn) {
+		query += "\nFOR UPDATE"
+	}
+
+	var code auth.OAuthAuthorizationCode
+	if err := pgxscan.Get(ctx, conn, &code, query, codeHash); err != nil {
+		if noRowsErr(err) {
+			return nil, auth.ErrOAuthAuthorizationCodeNotFound
+		}
+		log.ErrorCtx(ctx, "Failed to get oauth authorization code", err)
+		return nil, err
+	}
+
+	return &code, nil
+}
+
+// UpdateOAuthAuthorizationCode implements [auth.OAuthRepository]
+func (r *oauthRepository) UpdateOAuthAuthorizationCode(
+	ctx context.Context,
+	code *auth.OAuthAuthorizationCode,
+) error {
+	if code == nil {
+		log.WarnCtx(ctx, "UpdateOAuthAuthorizationCode called with nil code ptr")
+		return errors.New("oauth authorization code is nil")
+	}
+
+	query := "UPDATE oauth_authorization_codes SET used_at=$1 WHERE code_hash=$2"
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(ctx, query, code.UsedAt, code.CodeHash); err != nil {
+		log.ErrorCtx(ctx, "Failed to update oauth authorization code", err)
+		return err
+	}
+
+	return nil
+}
+
+// StoreOAuthRefreshToken implements [auth.OAuthRepository]
+func (r *oauthRepository) StoreOAuthRefreshToken(ctx context.Context, token *auth.OAuthRefreshToken) error {
+	if token == nil {
+		log.WarnCtx(ctx, "StoreOAuthRefreshToken called with nil token ptr")
+		return errors.New("oauth refresh token is nil")
+	}
+
+	query := strs.Concatenate(
+		"INSERT INTO oauth_refresh_tokens(id, token_hash, client_id, user_id, scopes, expires_at, created_at) ",
+		"VALUES($1, $2, $3, $4, $5, $6, $7)",
+	)
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(
+		ctx,
+		query,
+		token.ID,
+		token.TokenHash,
+		token.ClientID,
+		token.UserID,
+		token.Scopes,
+		token.ExpiresAt,
+		token.CreatedAt,
+	); err != nil {
+		log.ErrorCtx(ctx, "Failed to store oauth refresh token", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetOAuthRefreshTokenByHash implements [auth.OAuthRepository]
+func (r *oauthRepository) GetOAuthRefreshTokenByHash(
+	ctx context.Context,
+	tokenHash string,
+) (*auth.OAuthRefreshToken, error) {
+	query := strs.Concatenate(
+		"SELECT id, token_hash, client_id, user_id, scopes, expires_at, revoked_at, created_at ",
+		"FROM oauth_refresh_tokens WHERE token_hash=$1",
+	)
+
+	conn := r.db.GetConn(ctx)
+	if r.db.IsTxConn(conn) {
+		query += "\nFOR UPDATE"
+	}
+
+	var token auth.OAuthRefreshToken
+	if err := pgxscan.Get(ctx, conn, &token, query, tokenHash); err != nil {
+		if noRowsErr(err) {
+			return nil, auth.ErrOAuthRefreshTokenNotFound
+		}
+		log.ErrorCtx(ctx, "Failed to get oauth refresh token", err)
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// UpdateOAuthRefreshToken implements [auth.OAuthRepository]
+func (r *oauthRepository) UpdateOAuthRefreshToken(ctx context.Context, token *auth.OAuthRefreshToken) error {
+	if token == nil {
+		log.WarnCtx(ctx, "UpdateOAuthRefreshToken called with nil token ptr")
+		return errors.New("oauth refresh token is nil")
+	}
+
+	query := "UPDATE oauth_refresh_tokens SET revoked_at=$1 WHERE id=$2"
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(ctx, query, token.RevokedAt, token.ID); err != nil {
+		log.ErrorCtx(ctx, "Failed to update oauth refresh token", err)
+		return err
+	}
+
+	return nil
+}
+
+// RevokeOAuthRefreshTokens implements [auth.OAuthRepository]
+func (r *oauthRepository) RevokeOAuthRefreshTokens(ctx context.Context, clientID, userID string) error {
+	query := strs.Concatenate(
+		"UPDATE oauth_refresh_tokens SET revoked_at=NOW() ",
+		"WHERE client_id=$1 AND user_id=$2 AND revoked_at IS NULL",
+	)
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(ctx, query, clientID, userID); err != nil {
+		log.ErrorCtx(ctx, "Failed to revoke oauth refresh tokens", err)
+		return err
+	}
+
+	return nil
+}
+
+// RevokeOAuthGrantsByUser implements [auth.OAuthRepository]
+func (r *oauthRepository) RevokeOAuthGrantsByUser(ctx context.Context, userID string) error {
+	batch := &pgx.Batch{}
+	batch.Queue("UPDATE oauth_refresh_tokens SET revoked_at=NOW() WHERE user_id=$1 AND revoked_at IS NULL", userID)
+	batch.Queue("UPDATE oauth_authorization_codes SET used_at=NOW() WHERE user_id=$1 AND used_at IS NULL", userID)
+
+	conn := r.db.GetConn(ctx)
+	if err := conn.SendBatch(ctx, batch).Close(); err != nil {
+		log.ErrorCtx(ctx, "Failed to revoke user oauth grants", err)
+		return err
+	}
+
+	return nil
+}
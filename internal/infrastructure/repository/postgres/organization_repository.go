@@ -0,0 +1,420 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prawirdani/golang-restapi/internal/domain/organization"
+	"github.com/prawirdani/golang-restapi/pkg/log"
+	strs "github.com/prawirdani/golang-restapi/pkg/strings"
+)
+
+const (
+	organizationColumns = "id, name, slug, created_at, updated_at"
+	membershipColumns   = "organization_id, user_id, role, created_at, updated_at"
+	invitationColumns   = "id, organization_id, email, role, token_hash, invited_by, expires_at, accepted_at, " +
+		"declined_at, created_at"
+)
+
+type organizationRepository struct {
+	db *db
+}
+
+func NewOrganizationRepository(pool *pgxpool.Pool) *organizationRepository {
+	return &organizationRepository{
+		db: &db{pool: pool},
+	}
+}
+
+// Store implements [organization.Repository]
+func (r *organizationRepository) Store(ctx context.Context, org *organization.Organization) error {
+	if org == nil {
+		log.WarnCtx(ctx, "Store called with nil organization ptr")
+		return errors.New("organization is nil")
+	}
+
+	query := strs.Concatenate(
+		"INSERT INTO organizations(", organizationColumns, ") ",
+		"VALUES($1, $2, $3, $4, $5)",
+	)
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(ctx, query, org.ID, org.Name, org.Slug, org.CreatedAt, org.UpdatedAt); err != nil {
+		if uniqueViolationErr(err, "organizations_slug_key") {
+			return organization.ErrSlugExists
+		}
+		log.ErrorCtx(ctx, "Failed to store organization", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetByID implements [organization.Repository]
+func (r *organizationRepository) GetByID(ctx context.Context, orgID string) (*organization.Organization, error) {
+	query := strs.Concatenate("SELECT ", organizationColumns, " FROM organizations WHERE id=$1")
+	return r.getOrganization(ctx, query, orgID)
+}
+
+// GetBySlug implements [organization.Repository]
+func (r *organizationRepository) GetBySlug(ctx context.Context, slug string) (*organization.Organization, error) {
+	query := strs.Concatenate("SELECT ", organizationColumns, " FROM organizations WHERE slug=$1")
+	return r.getOrganization(ctx, query, slug)
+}
+
+func (r *organizationRepository) getOrganization(
+	ctx context.Context,
+	query string,
+	arg any,
+) (*organization.Organization, error) {
+	conn := r.db.GetConn(ctx)
+
+	var org organization.Organization
+	if err := pgxscan.Get(ctx, conn, &org, query, arg); err != nil {
+		if noRowsErr(err) {
+			return nil, organization.ErrNotFound
+		}
+		log.ErrorCtx(ctx, "Failed to get organization", err)
+		return nil, err
+	}
+
+	return &org, nil
+}
+
+// ListByUser implements [organization.Repository]
+func (r *organizationRepository) ListByUser(
+	ctx context.Context,
+	userID string,
+) ([]*organization.UserOrganization, error) {
+	query := strs.Concatenate(
+		"SELECT o.id, o.name, o.slug, o.created_at, o.updated_at, m.role ",
+		"FROM organizations o JOIN organization_memberships m ON m.organization_id = o.id ",
+		"WHERE m.user_id=$1 ORDER BY o.name",
+	)
+	conn := r.db.GetConn(ctx)
+
+	orgs := []*organization.UserOrganization{}
+	if err := pgxscan.Select(ctx, conn, &orgs, query, userID); err != nil {
+		log.ErrorCtx(ctx, "Failed to list user organizations", err)
+		return nil, err
+	}
+
+	return orgs, nil
+}
+
+// StoreMembership implements [organization.Repository]
+func (r *organizationRepository) StoreMembership(ctx context.Context, m *organization.Membership) error {
+	if m == nil {
+		log.WarnCtx(ctx, "StoreMembership called with nil membership ptr")
+		return errors.New("membership is nil")
+	}
+
+	query := strs.Concatenate(
+		"INSERT INTO organization_memberships(", membershipColumns, ") ",
+		"VALUES($1, $2, $3, $4, $5)",
+	)
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(ctx, query, m.OrganizationID, m.UserID, m.Role, m.CreatedAt, m.UpdatedAt); err != nil {
+		if uniqueViolationErr(err, "organization_memberships_pkey") {
+			return organization.ErrAlreadyMember
+		}
+		log.ErrorCtx(ctx, "Failed to store organization membership", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetMembership implements [organization.Repository]
+func (r *organizationRepository) GetMembership(
+	ctx context.Context,
+	orgID, userID string,
+) (*organization.Membership, error) {
+	query := strs.Concatenate(
+		"SELECT ", membershipColumns, " FROM organization_memberships ",
+		"WHERE organization_id=$1 AND user_id=$2",
+	)
+	conn := r.db.GetConn(ctx)
+
+	var m organization.Membership
+	if err := pgxscan.Get(ctx, conn, &m, query, orgID, userID); err != nil {
+		if noRowsErr(err) {
+			return nil, organization.ErrNotMember
+		}
+		log.ErrorCtx(ctx, "Failed to get organization membership", err)
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// ListMembers implements [organization.Repository]
+func (r *organizationRepository) ListMembers(ctx context.Context) ([]*organization.Member, error) {
+	tenantCond, args, err := scopeByTenant(ctx, "m.organization_id", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	query := strs.Concatenate(
+		"SELECT m.user_id, u.name, u.email, m.role, m.created_at ",
+		"FROM organization_memberships m JOIN users u ON u.id = m.user_id ",
+		"WHERE ", tenantCond, " ORDER BY m.created_at",
+	)
+	conn := r.db.GetConn(ctx)
+
+	members := []*organization.Member{}
+	if err := pgxscan.Select(ctx, conn, &members, query, args...); err != nil {
+		log.ErrorCtx(ctx, "Failed to list organization members", err)
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// GetMember implements [organization.Repository]
+func (r *organizationRepository) GetMember(ctx context.Context, userID string) (*organization.Membership, error) {
+	tenantCond, args, err := scopeByTenant(ctx, "organization_id", []any{userID})
+	if err != nil {
+		return nil, err
+	}
+
+	query := strs.Concatenate(
+		"SELECT ", membershipColumns, " FROM organization_memberships ",
+		"WHERE user_id=$1 AND ", tenantCond,
+	)
+	conn := r.db.GetConn(ctx)
+	if r.db.IsTxConn(conn) {
+		query += "\nFOR UPDATE"
+	}
+
+	var m organization.Membership
+	if err := pgxscan.Get(ctx, conn, &m, query, args...); err != nil {
+		if noRowsErr(err) {
+			return nil, organization.ErrMemberNotFound
+		}
+		log.ErrorCtx(ctx, "Failed to get organization member", err)
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// UpdateMembership implements [organization.Repository]
+func (r *organizationRepository) UpdateMembership(ctx context.Context, m *organization.Membership) error {
+	if m == nil {
+		log.WarnCtx(ctx, "UpdateMembership called with nil membership ptr")
+		return errors.New("membership is nil")
+	}
+
+	tenantCond, args, err := scopeByTenant(ctx, "organization_id", []any{m.Role, m.UserID})
+	if err != nil {
+		return err
+	}
+
+	query := "UPDATE organization_memberships SET role=$1, updated_at=NOW() WHERE user_id=$2 AND " + tenantCond
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(ctx, query, args...); err != nil {
+		log.ErrorCtx(ctx, "Failed to update organization membership", err)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteMembership implements [organization.Repository]
+func (r *organizationRepository) DeleteMembership(ctx context.Context, userID string) error {
+	tenantCond, args, err := scopeByTenant(ctx, "organization_id", []any{userID})
+	if err != nil {
+		return err
+	}
+
+	query := "DELETE FROM organization_memberships WHERE user_id=$1 AND " + tenantCond
+	conn := r.db.GetConn(ctx)
+
+	tag, err := conn.Exec(ctx, query, args...)
+	if err != nil {
+		log.ErrorCtx(ctx, "Failed to delete organization membership", err)
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return organization.ErrMemberNotFound
+	}
+
+	return nil
+}
+
+// CountOwners implements [organization.Repository]
+func (r *organizationRepository) CountOwners(ctx context.Context) (int, error) {
+	tenantCond, args, err := scopeByTenant(ctx, "organization_id", []any{organization.RoleOwner})
+	if err != nil {
+		return 0, err
+	}
+
+	query := "SELECT COUNT(*) FROM organization_memberships WHERE role=$1 AND " + tenantCond
+	conn := r.db.GetConn(ctx)
+
+	var count int
+	if err := conn.QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		log.ErrorCtx(ctx, "Failed to count organization owners", err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// StoreInvitation implements [organization.Repository]
+func (r *organizationRepository) StoreInvitation(ctx context.Context, inv *organization.Invitation) error {
+	if inv == nil {
+		log.WarnCtx(ctx, "StoreInvitation called with nil invitation ptr")
+		return errors.New("invitation is nil")
+	}
+
+	// Invitations are only ever created for the active organization
+	tenant, err := organization.TenantFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if inv.OrganizationID != tenant.OrganizationID {
+		return organization.ErrNotMember
+	}
+
+	query := strs.Concatenate(
+		"INSERT INTO organization_invitations(", invitationColumns, ") ",
+		"VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)",
+	)
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(
+		ctx,
+		query,
+		inv.ID,
+		inv.OrganizationID,
+		inv.Email,
+		inv.Role,
+		inv.TokenHash,
+		inv.InvitedBy,
+		inv.ExpiresAt,
+		inv.AcceptedAt,
+		inv.DeclinedAt,
+		inv.CreatedAt,
+	); err != nil {
+		log.ErrorCtx(ctx, "Failed to store organization invitation", err)
+		return err
+	}
+
+	return nil
+}
+
+// ListPendingInvitations implements [organization.Repository]
+func (r *organizationRepository) ListPendingInvitations(ctx context.Context) ([]*organization.Invitation, error) {
+	tenantCond, args, err := scopeByTenant(ctx, "organization_id", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	query := strs.Concatenate(
+		"SELECT ", invitationColumns, " FROM organization_invitations ",
+		"WHERE ", tenantCond, " AND accepted_at IS NULL AND declined_at IS NULL AND expires_at > NOW() ",
+		"ORDER BY created_at DESC",
+	)
+	conn := r.db.GetConn(ctx)
+
+	invitations := []*organization.Invitation{}
+	if err := pgxscan.Select(ctx, conn, &invitations, query, args...); err != nil {
+		log.ErrorCtx(ctx, "Failed to list organization invitations", err)
+		return nil, err
+	}
+
+	return invitations, nil
+}
+
+// DeleteInvitation implements [organization.Repository]
+func (r *organizationRepository) DeleteInvitation(ctx context.Context, invitationID string) error {
+	tenantCond, args, err := scopeByTenant(ctx, "organization_id", []any{invitationID})
+	if err != nil {
+		return err
+	}
+
+	query := "DELETE FROM organization_invitations WHERE id=$1 AND " + tenantCond
+	conn := r.db.GetConn(ctx)
+
+	tag, err := conn.Exec(ctx, query, args...)
+	if err != nil {
+		log.ErrorCtx(ctx, "Failed to delete organization invitation", err)
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return organization.ErrInvitationNotFound
+	}
+
+	return nil
+}
+
+// DeleteUnansweredInvitations implements [organization.Repository]
+func (r *organizationRepository) DeleteUnansweredInvitations(ctx context.Context, email string) error {
+	tenantCond, args, err := scopeByTenant(ctx, "organization_id", []any{email})
+	if err != nil {
+		return err
+	}
+
+	query := strs.Concatenate(
+		"DELETE FROM organization_invitations ",
+		"WHERE email=$1 AND accepted_at IS NULL AND declined_at IS NULL AND ", tenantCond,
+	)
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(ctx, query, args...); err != nil {
+		log.ErrorCtx(ctx, "Failed to delete unanswered organization invitations", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetInvitationByTokenHash implements [organization.Repository]
+func (r *organizationRepository) GetInvitationByTokenHash(
+	ctx context.Context,
+	tokenHash string,
+) (*organization.Invitation, error) {
+	query := strs.Concatenate("SELECT ", invitationColumns, " FROM organization_invitations WHERE token_hash=$1")
+
+	conn := r.db.GetConn(ctx)
+	if r.db.IsTxConn(conn) {
+		query += "\nFOR UPDATE"
+	}
+
+	var inv organization.Invitation
+	if err := pgxscan.Get(ctx, conn, &inv, query, tokenHash); err != nil {
+		if noRowsErr(err) {
+			return nil, organization.ErrInvitationNotFound
+		}
+		log.ErrorCtx(ctx, "Failed to get organization invitation", err)
+		return nil, err
+	}
+
+	return &inv, nil
+}
+
+// UpdateInvitation implements [organization.Repository]
+func (r *organizationRepository) UpdateInvitation(ctx context.Context, inv *organization.Invitation) error {
+	if inv == nil {
+		log.WarnCtx(ctx, "UpdateInvitation called with nil invitation ptr")
+		return errors.New("invitation is nil")
+	}
+
+	query := "UPDATE organization_invitations SET accepted_at=$1, declined_at=$2 WHERE id=$3"
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(ctx, query, inv.AcceptedAt, inv.DeclinedAt, inv.ID); err != nil {
+		log.ErrorCtx(ctx, "Failed to update organization invitation", err)
+		return err
+	}
+
+	return nil
+}
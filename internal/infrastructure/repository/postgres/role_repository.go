@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prawirdani/golang-restapi/internal/domain/auth"
+	"github.com/prawirdani/golang-restapi/pkg/log"
+	strs "github.com/prawirdani/golang-restapi/pkg/strings"
+)
+
+// roleSelectQuery selects roles with their permissions aggregated into an array.
+var roleSelectQuery = strs.Concatenate(
+	"SELECT r.id, r.name, r.description, r.created_at, ",
+	"COALESCE(ARRAY_AGG(rp.permission ORDER BY rp.permission) FILTER (WHERE rp.permission IS NOT NULL), '{}') AS permissions ",
+	"FROM roles r LEFT JOIN role_permissions rp ON rp.role_id = r.id ",
+)
+
+type roleRepository struct {
+	db *db
+}
+
+func NewRoleRepository(pool *pgxpool.Pool) *roleRepository {
+	return &roleRepository{
+		db: &db{pool: pool},
+	}
+}
+
+// ListRoles implements [auth.RoleRepository]
+func (r *roleRepository) ListRoles(ctx context.Context) ([]*auth.Role, error) {
+	query := roleSelectQuery + "GROUP BY r.id ORDER BY r.id"
+	conn := r.db.GetConn(ctx)
+
+	var roles []*auth.Role
+	if err := pgxscan.Select(ctx, conn, &roles, query); err != nil {
+		log.ErrorCtx(ctx, "Failed to list roles", err)
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+// GetRoleByName implements [auth.RoleRepository]
+func (r *roleRepository) GetRoleByName(ctx context.Context, name string) (*auth.Role, error) {
+	query := roleSelectQuery + "WHERE r.name=$1 GROUP BY r.id"
+	conn := r.db.GetConn(ctx)
+
+	var role auth.Role
+	if err := pgxscan.Get(ctx, conn, &role, query, name); err != nil {
+		if noRowsErr(err) {
+			return nil, auth.ErrRoleNotFound
+		}
+		log.ErrorCtx(ctx, "Failed to get role", err)
+		return nil, err
+	}
+
+	return &role, nil
+}
+
+// GetRolesByUser implements [auth.RoleRepository]
+func (r *roleRepository) GetRolesByUser(ctx context.Context, userID string) ([]*auth.Role, error) {
+	query := roleSelectQuery + strs.Concatenate(
+		"JOIN user_roles ur ON ur.role_id = r.id ",
+		"WHERE ur.user_id=$1 GROUP BY r.id ORDER BY r.id",
+	)
+	conn := r.db.GetConn(ctx)
+
+	var roles []*auth.Role
+	if err := pgxscan.Select(ctx, conn, &roles, query, userID); err != nil {
+		log.ErrorCtx(ctx, "Failed to get user roles", err)
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+// AssignRole implements [auth.RoleRepository]
+func (r *roleRepository) AssignRole(ctx context.Context, userID string, roleID int) error {
+	query := "INSERT INTO user_roles(user_id, role_id) VALUES($1, $2) ON CONFLICT DO NOTHING"
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(ctx, query, userID, roleID); err != nil {
+		log.ErrorCtx(ctx, "Failed to assign role", err)
+		return err
+	}
+
+	return nil
+}
+
+// RevokeRole implements [auth.RoleRepository]
+func (r *roleRepository) RevokeRole(ctx context.Context, userID string, roleID int) error {
+	query := "DELETE FROM user_roles WHERE user_id=$1 AND role_id=$2"
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(ctx, query, userID, roleID); err != nil {
+		log.ErrorCtx(ctx, "Failed to revoke role", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,21 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prawirdani/golang-restapi/internal/domain/organization"
+)
+
+// scopeByTenant appends the active organization of ctx to args and returns the condition restricting column to
+// it, as "column = $n". Every tenant scoped query must go through it, so a query can't run without a tenant and
+// never reaches another tenant's rows. Fails with [organization.ErrNoTenant] when ctx has no active organization.
+func scopeByTenant(ctx context.Context, column string, args []any) (string, []any, error) {
+	tenant, err := organization.TenantFromContext(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+
+	args = append(args, tenant.OrganizationID)
+	return fmt.Sprintf("%s = $%d", column, len(args)), args, nil
+}
@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prawirdani/golang-restapi/pkg/log"
+	strs "github.com/prawirdani/golang-restapi/pkg/strings"
+)
+
+type tokenRevocationRepository struct {
+	db *db
+}
+
+func NewTokenRevocationRepository(pool *pgxpool.Pool) *tokenRevocationRepository {
+	return &tokenRevocationRepository{
+		db: &db{pool: pool},
+	}
+}
+
+// Revoke implements [auth.TokenRevocationStore]. Expired entries are evicted along the way,
+// revocations are rare enough for the sweep to stay cheap.
+func (r *tokenRevocationRepository) Revoke(ctx context.Context, id string, expiresAt time.Time) error {
+	query := strs.Concatenate(
+		"INSERT INTO revoked_access_tokens(id, expires_at) VALUES($1, $2) ",
+		"ON CONFLICT (id) DO UPDATE SET expires_at=GREATEST(revoked_access_tokens.expires_at, EXCLUDED.expires_at)",
+	)
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(ctx, query, id, expiresAt); err != nil {
+		log.ErrorCtx(ctx, "Failed to revoke access token", err)
+		return err
+	}
+
+	if _, err := conn.Exec(ctx, "DELETE FROM revoked_access_tokens WHERE expires_at <= NOW()"); err != nil {
+		log.ErrorCtx(ctx, "Failed to evict expired access token revocations", err)
+		return err
+	}
+
+	return nil
+}
+
+// IsRevoked implements [auth.TokenRevocationStore]
+func (r *tokenRevocationRepository) IsRevoked(ctx context.Context, ids ...string) (bool, error) {
+	query := "SELECT EXISTS(SELECT 1 FROM revoked_access_tokens WHERE id = ANY($1) AND expires_at > NOW())"
+	conn := r.db.GetConn(ctx)
+
+	var revoked bool
+	if err := conn.QueryRow(ctx, query, ids).Scan(&revoked); err != nil {
+		log.ErrorCtx(ctx, "Failed to check access token revocation", err)
+		return false, err
+	}
+
+	return revoked, nil
+}
@@ -3,6 +3,8 @@ package postgres
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/georgysavva/scany/v2/pgxscan"
@@ -12,6 +14,12 @@ import (
 	strs "github.com/prawirdani/golang-restapi/pkg/strings"
 )
 
+const userColumns = "id, name, email, phone, password, profile_image, verified_at, suspended_at, " +
+	"password_reset_required, created_at, updated_at, deleted_at"
+
+// likeEscaper escapes the LIKE wildcards of user supplied search terms.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 type userRepository struct {
 	db *db
 }
@@ -29,10 +37,10 @@ func (r *userRepository) Store(ctx context.Context, u *user.User) error {
 		return errors.New("user is nil")
 	}
 
-	query := "INSERT INTO users(id, name, email, phone, password, profile_image) VALUES($1, $2, $3, $4, $5, $6)"
+	query := "INSERT INTO users(id, name, email, phone, password, profile_image, verified_at) VALUES($1, $2, $3, $4, $5, $6, $7)"
 	conn := r.db.GetConn(ctx)
 
-	_, err := conn.Exec(ctx, query, u.ID, u.Name, u.Email, u.Phone, u.Password, u.ProfileImage)
+	_, err := conn.Exec(ctx, query, u.ID, u.Name, u.Email, u.Phone, u.Password, u.ProfileImage, u.VerifiedAt)
 	if err != nil {
 		if uniqueViolationErr(err, "users_email_key") {
 			return user.ErrEmailExists
@@ -61,7 +69,10 @@ func (r *userRepository) Update(ctx context.Context, u *user.User) error {
 		return errors.New("user is nil")
 	}
 
-	query := "UPDATE users SET name=$1, email=$2, phone=$3, password=$4, profile_image=$5, updated_at=$6 WHERE id=$7"
+	query := strs.Concatenate(
+		"UPDATE users SET name=$1, email=$2, phone=$3, password=$4, profile_image=$5, verified_at=$6, ",
+		"suspended_at=$7, password_reset_required=$8, updated_at=$9 WHERE id=$10",
+	)
 	updatedAt := time.Now()
 
 	conn := r.db.GetConn(ctx)
@@ -73,6 +84,9 @@ func (r *userRepository) Update(ctx context.Context, u *user.User) error {
 		u.Phone,
 		u.Password,
 		u.ProfileImage,
+		u.VerifiedAt,
+		u.SuspendedAt,
+		u.PasswordResetRequired,
 		updatedAt,
 		u.ID,
 	)
@@ -95,7 +109,7 @@ func (r *userRepository) Delete(ctx context.Context, u *user.User) error {
 		return errors.New("user is nil")
 	}
 
-	query := "UPDATE users SET deleted_at=$1 WHERE id=$2"
+	query := "UPDATE users SET deleted_at=$1 WHERE id=$2 AND deleted_at IS NULL"
 	conn := r.db.GetConn(ctx)
 
 	deleteTime := time.Now()
@@ -108,13 +122,137 @@ func (r *userRepository) Delete(ctx context.Context, u *user.User) error {
 	return nil
 }
 
+// ListDeleted implements [user.Repository].
+func (r *userRepository) ListDeleted(
+	ctx context.Context,
+	deletedBefore time.Time,
+	limit int,
+) ([]*user.User, error) {
+	query := strs.Concatenate(
+		"SELECT ", userColumns, " FROM users ",
+		"WHERE deleted_at IS NOT NULL AND deleted_at < $1 AND anonymized_at IS NULL ",
+		"ORDER BY deleted_at LIMIT $2",
+	)
+	conn := r.db.GetConn(ctx)
+
+	var users []*user.User
+	if err := pgxscan.Select(ctx, conn, &users, query, deletedBefore, limit); err != nil {
+		log.ErrorCtx(ctx, "Failed to list deleted users", err)
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// HardDelete implements [user.Repository].
+func (r *userRepository) HardDelete(ctx context.Context, userID string) error {
+	query := "DELETE FROM users WHERE id=$1"
+	conn := r.db.GetConn(ctx)
+
+	if _, err := conn.Exec(ctx, query, userID); err != nil {
+		log.ErrorCtx(ctx, "Failed to hard delete user", err)
+		return err
+	}
+
+	return nil
+}
+
+// Anonymize implements [user.Repository].
+func (r *userRepository) Anonymize(ctx context.Context, u *user.User) error {
+	if u == nil {
+		log.WarnCtx(ctx, "Anonymize called with nil user")
+		return errors.New("user is nil")
+	}
+
+	query := strs.Concatenate(
+		"UPDATE users SET name=$1, email=$2, phone=$3, password=$4, profile_image=$5, ",
+		"anonymized_at=$6, updated_at=$6 WHERE id=$7",
+	)
+	conn := r.db.GetConn(ctx)
+
+	_, err := conn.Exec(
+		ctx,
+		query,
+		u.Name,
+		u.Email,
+		u.Phone,
+		u.Password,
+		u.ProfileImage,
+		time.Now(),
+		u.ID,
+	)
+	if err != nil {
+		log.ErrorCtx(ctx, "Failed to anonymize user", err)
+		return err
+	}
+
+	return nil
+}
+
+// List implements [user.Repository].
+func (r *userRepository) List(ctx context.Context, filter user.ListFilter) ([]*user.User, int, error) {
+	var (
+		conds []string
+		args  []any
+	)
+	addCond := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if filter.Search != "" {
+		addCond("(name ILIKE $%[1]d OR email ILIKE $%[1]d)", "%"+likeEscaper.Replace(filter.Search)+"%")
+	}
+	if !filter.CreatedFrom.IsZero() {
+		addCond("created_at >= $%d", filter.CreatedFrom)
+	}
+	if !filter.CreatedTo.IsZero() {
+		addCond("created_at < $%d", filter.CreatedTo)
+	}
+	switch filter.Status {
+	case user.StatusSuspended:
+		conds = append(conds, "suspended_at IS NOT NULL")
+	case user.StatusUnverified:
+		conds = append(conds, "suspended_at IS NULL AND verified_at IS NULL")
+	case user.StatusActive:
+		conds = append(conds, "suspended_at IS NULL AND verified_at IS NOT NULL")
+	}
+
+	where := " WHERE " + strings.Join(append([]string{"deleted_at IS NULL"}, conds...), " AND ")
+
+	conn := r.db.GetConn(ctx)
+
+	var total int
+	countQuery := "SELECT COUNT(*) FROM users" + where
+	if err := conn.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		log.ErrorCtx(ctx, "Failed to count users", err)
+		return nil, 0, err
+	}
+
+	args = append(args, filter.PerPage, filter.Offset())
+	query := strs.Concatenate(
+		"SELECT ", userColumns, " FROM users", where,
+		fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", len(args)-1, len(args)),
+	)
+
+	var users []*user.User
+	if err := pgxscan.Select(ctx, conn, &users, query, args...); err != nil {
+		log.ErrorCtx(ctx, "Failed to list users", err)
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
 func (r *userRepository) getUserBy(
 	ctx context.Context,
 	field string,
 	value any,
 ) (*user.User, error) {
 	query := strs.Concatenate(
-		"SELECT id, name, email, phone, password, profile_image, created_at, updated_at FROM users WHERE ",
+		"SELECT ",
+		userColumns,
+		" FROM users WHERE deleted_at IS NULL AND ",
 		field,
 		"=$1",
 	)
@@ -38,6 +38,348 @@ func (_m *AuthMessagePublisher) EXPECT() *AuthMessagePublisher_Expecter {
 	return &AuthMessagePublisher_Expecter{mock: &_m.Mock}
 }
 
+// SendAccountExistsEmail provides a mock function for the type AuthMessagePublisher
+func (_mock *AuthMessagePublisher) SendAccountExistsEmail(ctx context.Context, msg auth.AccountExistsEmailMessage) error {
+	ret := _mock.Called(ctx, msg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendAccountExistsEmail")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, auth.AccountExistsEmailMessage) error); ok {
+		r0 = returnFunc(ctx, msg)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthMessagePublisher_SendAccountExistsEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendAccountExistsEmail'
+type AuthMessagePublisher_SendAccountExistsEmail_Call struct {
+	*mock.Call
+}
+
+// SendAccountExistsEmail is a helper method to define mock.On call
+//   - ctx context.Context
+//   - msg auth.AccountExistsEmailMessage
+func (_e *AuthMessagePublisher_Expecter) SendAccountExistsEmail(ctx interface{}, msg interface{}) *AuthMessagePublisher_SendAccountExistsEmail_Call {
+	return &AuthMessagePublisher_SendAccountExistsEmail_Call{Call: _e.mock.On("SendAccountExistsEmail", ctx, msg)}
+}
+
+func (_c *AuthMessagePublisher_SendAccountExistsEmail_Call) Run(run func(ctx context.Context, msg auth.AccountExistsEmailMessage)) *AuthMessagePublisher_SendAccountExistsEmail_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 auth.AccountExistsEmailMessage
+		if args[1] != nil {
+			arg1 = args[1].(auth.AccountExistsEmailMessage)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthMessagePublisher_SendAccountExistsEmail_Call) Return(err error) *AuthMessagePublisher_SendAccountExistsEmail_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthMessagePublisher_SendAccountExistsEmail_Call) RunAndReturn(run func(ctx context.Context, msg auth.AccountExistsEmailMessage) error) *AuthMessagePublisher_SendAccountExistsEmail_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SendAccountLockedEmail provides a mock function for the type AuthMessagePublisher
+func (_mock *AuthMessagePublisher) SendAccountLockedEmail(ctx context.Context, msg auth.AccountLockedEmailMessage) error {
+	ret := _mock.Called(ctx, msg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendAccountLockedEmail")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, auth.AccountLockedEmailMessage) error); ok {
+		r0 = returnFunc(ctx, msg)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthMessagePublisher_SendAccountLockedEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendAccountLockedEmail'
+type AuthMessagePublisher_SendAccountLockedEmail_Call struct {
+	*mock.Call
+}
+
+// SendAccountLockedEmail is a helper method to define mock.On call
+//   - ctx context.Context
+//   - msg auth.AccountLockedEmailMessage
+func (_e *AuthMessagePublisher_Expecter) SendAccountLockedEmail(ctx interface{}, msg interface{}) *AuthMessagePublisher_SendAccountLockedEmail_Call {
+	return &AuthMessagePublisher_SendAccountLockedEmail_Call{Call: _e.mock.On("SendAccountLockedEmail", ctx, msg)}
+}
+
+func (_c *AuthMessagePublisher_SendAccountLockedEmail_Call) Run(run func(ctx context.Context, msg auth.AccountLockedEmailMessage)) *AuthMessagePublisher_SendAccountLockedEmail_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 auth.AccountLockedEmailMessage
+		if args[1] != nil {
+			arg1 = args[1].(auth.AccountLockedEmailMessage)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthMessagePublisher_SendAccountLockedEmail_Call) Return(err error) *AuthMessagePublisher_SendAccountLockedEmail_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthMessagePublisher_SendAccountLockedEmail_Call) RunAndReturn(run func(ctx context.Context, msg auth.AccountLockedEmailMessage) error) *AuthMessagePublisher_SendAccountLockedEmail_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SendEmailChangeConfirmation provides a mock function for the type AuthMessagePublisher
+func (_mock *AuthMessagePublisher) SendEmailChangeConfirmation(ctx context.Context, msg auth.EmailChangeConfirmationMessage) error {
+	ret := _mock.Called(ctx, msg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendEmailChangeConfirmation")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, auth.EmailChangeConfirmationMessage) error); ok {
+		r0 = returnFunc(ctx, msg)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthMessagePublisher_SendEmailChangeConfirmation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendEmailChangeConfirmation'
+type AuthMessagePublisher_SendEmailChangeConfirmation_Call struct {
+	*mock.Call
+}
+
+// SendEmailChangeConfirmation is a helper method to define mock.On call
+//   - ctx context.Context
+//   - msg auth.EmailChangeConfirmationMessage
+func (_e *AuthMessagePublisher_Expecter) SendEmailChangeConfirmation(ctx interface{}, msg interface{}) *AuthMessagePublisher_SendEmailChangeConfirmation_Call {
+	return &AuthMessagePublisher_SendEmailChangeConfirmation_Call{Call: _e.mock.On("SendEmailChangeConfirmation", ctx, msg)}
+}
+
+func (_c *AuthMessagePublisher_SendEmailChangeConfirmation_Call) Run(run func(ctx context.Context, msg auth.EmailChangeConfirmationMessage)) *AuthMessagePublisher_SendEmailChangeConfirmation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 auth.EmailChangeConfirmationMessage
+		if args[1] != nil {
+			arg1 = args[1].(auth.EmailChangeConfirmationMessage)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthMessagePublisher_SendEmailChangeConfirmation_Call) Return(err error) *AuthMessagePublisher_SendEmailChangeConfirmation_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthMessagePublisher_SendEmailChangeConfirmation_Call) RunAndReturn(run func(ctx context.Context, msg auth.EmailChangeConfirmationMessage) error) *AuthMessagePublisher_SendEmailChangeConfirmation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SendEmailChangeNotification provides a mock function for the type AuthMessagePublisher
+func (_mock *AuthMessagePublisher) SendEmailChangeNotification(ctx context.Context, msg auth.EmailChangeNotificationMessage) error {
+	ret := _mock.Called(ctx, msg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendEmailChangeNotification")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, auth.EmailChangeNotificationMessage) error); ok {
+		r0 = returnFunc(ctx, msg)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthMessagePublisher_SendEmailChangeNotification_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendEmailChangeNotification'
+type AuthMessagePublisher_SendEmailChangeNotification_Call struct {
+	*mock.Call
+}
+
+// SendEmailChangeNotification is a helper method to define mock.On call
+//   - ctx context.Context
+//   - msg auth.EmailChangeNotificationMessage
+func (_e *AuthMessagePublisher_Expecter) SendEmailChangeNotification(ctx interface{}, msg interface{}) *AuthMessagePublisher_SendEmailChangeNotification_Call {
+	return &AuthMessagePublisher_SendEmailChangeNotification_Call{Call: _e.mock.On("SendEmailChangeNotification", ctx, msg)}
+}
+
+func (_c *AuthMessagePublisher_SendEmailChangeNotification_Call) Run(run func(ctx context.Context, msg auth.EmailChangeNotificationMessage)) *AuthMessagePublisher_SendEmailChangeNotification_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 auth.EmailChangeNotificationMessage
+		if args[1] != nil {
+			arg1 = args[1].(auth.EmailChangeNotificationMessage)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthMessagePublisher_SendEmailChangeNotification_Call) Return(err error) *AuthMessagePublisher_SendEmailChangeNotification_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthMessagePublisher_SendEmailChangeNotification_Call) RunAndReturn(run func(ctx context.Context, msg auth.EmailChangeNotificationMessage) error) *AuthMessagePublisher_SendEmailChangeNotification_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SendMagicLinkEmail provides a mock function for the type AuthMessagePublisher
+func (_mock *AuthMessagePublisher) SendMagicLinkEmail(ctx context.Context, msg auth.MagicLinkEmailMessage) error {
+	ret := _mock.Called(ctx, msg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendMagicLinkEmail")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, auth.MagicLinkEmailMessage) error); ok {
+		r0 = returnFunc(ctx, msg)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthMessagePublisher_SendMagicLinkEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendMagicLinkEmail'
+type AuthMessagePublisher_SendMagicLinkEmail_Call struct {
+	*mock.Call
+}
+
+// SendMagicLinkEmail is a helper method to define mock.On call
+//   - ctx context.Context
+//   - msg auth.MagicLinkEmailMessage
+func (_e *AuthMessagePublisher_Expecter) SendMagicLinkEmail(ctx interface{}, msg interface{}) *AuthMessagePublisher_SendMagicLinkEmail_Call {
+	return &AuthMessagePublisher_SendMagicLinkEmail_Call{Call: _e.mock.On("SendMagicLinkEmail", ctx, msg)}
+}
+
+func (_c *AuthMessagePublisher_SendMagicLinkEmail_Call) Run(run func(ctx context.Context, msg auth.MagicLinkEmailMessage)) *AuthMessagePublisher_SendMagicLinkEmail_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 auth.MagicLinkEmailMessage
+		if args[1] != nil {
+			arg1 = args[1].(auth.MagicLinkEmailMessage)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthMessagePublisher_SendMagicLinkEmail_Call) Return(err error) *AuthMessagePublisher_SendMagicLinkEmail_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthMessagePublisher_SendMagicLinkEmail_Call) RunAndReturn(run func(ctx context.Context, msg auth.MagicLinkEmailMessage) error) *AuthMessagePublisher_SendMagicLinkEmail_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SendNewDeviceLoginEmail provides a mock function for the type AuthMessagePublisher
+func (_mock *AuthMessagePublisher) SendNewDeviceLoginEmail(ctx context.Context, msg auth.NewDeviceLoginEmailMessage) error {
+	ret := _mock.Called(ctx, msg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendNewDeviceLoginEmail")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, auth.NewDeviceLoginEmailMessage) error); ok {
+		r0 = returnFunc(ctx, msg)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthMessagePublisher_SendNewDeviceLoginEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendNewDeviceLoginEmail'
+type AuthMessagePublisher_SendNewDeviceLoginEmail_Call struct {
+	*mock.Call
+}
+
+// SendNewDeviceLoginEmail is a helper method to define mock.On call
+//   - ctx context.Context
+//   - msg auth.NewDeviceLoginEmailMessage
+func (_e *AuthMessagePublisher_Expecter) SendNewDeviceLoginEmail(ctx interface{}, msg interface{}) *AuthMessagePublisher_SendNewDeviceLoginEmail_Call {
+	return &AuthMessagePublisher_SendNewDeviceLoginEmail_Call{Call: _e.mock.On("SendNewDeviceLoginEmail", ctx, msg)}
+}
+
+func (_c *AuthMessagePublisher_SendNewDeviceLoginEmail_Call) Run(run func(ctx context.Context, msg auth.NewDeviceLoginEmailMessage)) *AuthMessagePublisher_SendNewDeviceLoginEmail_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 auth.NewDeviceLoginEmailMessage
+		if args[1] != nil {
+			arg1 = args[1].(auth.NewDeviceLoginEmailMessage)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthMessagePublisher_SendNewDeviceLoginEmail_Call) Return(err error) *AuthMessagePublisher_SendNewDeviceLoginEmail_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthMessagePublisher_SendNewDeviceLoginEmail_Call) RunAndReturn(run func(ctx context.Context, msg auth.NewDeviceLoginEmailMessage) error) *AuthMessagePublisher_SendNewDeviceLoginEmail_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SendResetPasswordEmail provides a mock function for the type AuthMessagePublisher
 func (_mock *AuthMessagePublisher) SendResetPasswordEmail(ctx context.Context, msg auth.ResetPasswordEmailMessage) error {
 	ret := _mock.Called(ctx, msg)
@@ -94,3 +436,60 @@ func (_c *AuthMessagePublisher_SendResetPasswordEmail_Call) RunAndReturn(run fun
 	_c.Call.Return(run)
 	return _c
 }
+
+// SendVerificationEmail provides a mock function for the type AuthMessagePublisher
+func (_mock *AuthMessagePublisher) SendVerificationEmail(ctx context.Context, msg auth.VerificationEmailMessage) error {
+	ret := _mock.Called(ctx, msg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendVerificationEmail")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, auth.VerificationEmailMessage) error); ok {
+		r0 = returnFunc(ctx, msg)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthMessagePublisher_SendVerificationEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendVerificationEmail'
+type AuthMessagePublisher_SendVerificationEmail_Call struct {
+	*mock.Call
+}
+
+// SendVerificationEmail is a helper method to define mock.On call
+//   - ctx context.Context
+//   - msg auth.VerificationEmailMessage
+func (_e *AuthMessagePublisher_Expecter) SendVerificationEmail(ctx interface{}, msg interface{}) *AuthMessagePublisher_SendVerificationEmail_Call {
+	return &AuthMessagePublisher_SendVerificationEmail_Call{Call: _e.mock.On("SendVerificationEmail", ctx, msg)}
+}
+
+func (_c *AuthMessagePublisher_SendVerificationEmail_Call) Run(run func(ctx context.Context, msg auth.VerificationEmailMessage)) *AuthMessagePublisher_SendVerificationEmail_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 auth.VerificationEmailMessage
+		if args[1] != nil {
+			arg1 = args[1].(auth.VerificationEmailMessage)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthMessagePublisher_SendVerificationEmail_Call) Return(err error) *AuthMessagePublisher_SendVerificationEmail_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthMessagePublisher_SendVerificationEmail_Call) RunAndReturn(run func(ctx context.Context, msg auth.VerificationEmailMessage) error) *AuthMessagePublisher_SendVerificationEmail_Call {
+	_c.Call.Return(run)
+	return _c
+}
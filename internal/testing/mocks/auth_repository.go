@@ -38,55 +38,3106 @@ func (_m *AuthRepository) EXPECT() *AuthRepository_Expecter {
 	return &AuthRepository_Expecter{mock: &_m.Mock}
 }
 
+// DeleteKnownDevice provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) DeleteKnownDevice(ctx context.Context, userID string, deviceID string) error {
+	ret := _mock.Called(ctx, userID, deviceID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteKnownDevice")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = returnFunc(ctx, userID, deviceID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthRepository_DeleteKnownDevice_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteKnownDevice'
+type AuthRepository_DeleteKnownDevice_Call struct {
+	*mock.Call
+}
+
+// DeleteKnownDevice is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID string
+//   - deviceID string
+func (_e *AuthRepository_Expecter) DeleteKnownDevice(ctx interface{}, userID interface{}, deviceID interface{}) *AuthRepository_DeleteKnownDevice_Call {
+	return &AuthRepository_DeleteKnownDevice_Call{Call: _e.mock.On("DeleteKnownDevice", ctx, userID, deviceID)}
+}
+
+func (_c *AuthRepository_DeleteKnownDevice_Call) Run(run func(ctx context.Context, userID string, deviceID string)) *AuthRepository_DeleteKnownDevice_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_DeleteKnownDevice_Call) Return(err error) *AuthRepository_DeleteKnownDevice_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthRepository_DeleteKnownDevice_Call) RunAndReturn(run func(ctx context.Context, userID string, deviceID string) error) *AuthRepository_DeleteKnownDevice_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteLoginAttempts provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) DeleteLoginAttempts(ctx context.Context, key string) error {
+	ret := _mock.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteLoginAttempts")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthRepository_DeleteLoginAttempts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteLoginAttempts'
+type AuthRepository_DeleteLoginAttempts_Call struct {
+	*mock.Call
+}
+
+// DeleteLoginAttempts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+func (_e *AuthRepository_Expecter) DeleteLoginAttempts(ctx interface{}, key interface{}) *AuthRepository_DeleteLoginAttempts_Call {
+	return &AuthRepository_DeleteLoginAttempts_Call{Call: _e.mock.On("DeleteLoginAttempts", ctx, key)}
+}
+
+func (_c *AuthRepository_DeleteLoginAttempts_Call) Run(run func(ctx context.Context, key string)) *AuthRepository_DeleteLoginAttempts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_DeleteLoginAttempts_Call) Return(err error) *AuthRepository_DeleteLoginAttempts_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthRepository_DeleteLoginAttempts_Call) RunAndReturn(run func(ctx context.Context, key string) error) *AuthRepository_DeleteLoginAttempts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeletePersonalAccessToken provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) DeletePersonalAccessToken(ctx context.Context, userID string, tokenID string) error {
+	ret := _mock.Called(ctx, userID, tokenID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePersonalAccessToken")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = returnFunc(ctx, userID, tokenID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthRepository_DeletePersonalAccessToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeletePersonalAccessToken'
+type AuthRepository_DeletePersonalAccessToken_Call struct {
+	*mock.Call
+}
+
+// DeletePersonalAccessToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID string
+//   - tokenID string
+func (_e *AuthRepository_Expecter) DeletePersonalAccessToken(ctx interface{}, userID interface{}, tokenID interface{}) *AuthRepository_DeletePersonalAccessToken_Call {
+	return &AuthRepository_DeletePersonalAccessToken_Call{Call: _e.mock.On("DeletePersonalAccessToken", ctx, userID, tokenID)}
+}
+
+func (_c *AuthRepository_DeletePersonalAccessToken_Call) Run(run func(ctx context.Context, userID string, tokenID string)) *AuthRepository_DeletePersonalAccessToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_DeletePersonalAccessToken_Call) Return(err error) *AuthRepository_DeletePersonalAccessToken_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthRepository_DeletePersonalAccessToken_Call) RunAndReturn(run func(ctx context.Context, userID string, tokenID string) error) *AuthRepository_DeletePersonalAccessToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteRecoveryCodes provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) DeleteRecoveryCodes(ctx context.Context, userID string) error {
+	ret := _mock.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteRecoveryCodes")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthRepository_DeleteRecoveryCodes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteRecoveryCodes'
+type AuthRepository_DeleteRecoveryCodes_Call struct {
+	*mock.Call
+}
+
+// DeleteRecoveryCodes is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID string
+func (_e *AuthRepository_Expecter) DeleteRecoveryCodes(ctx interface{}, userID interface{}) *AuthRepository_DeleteRecoveryCodes_Call {
+	return &AuthRepository_DeleteRecoveryCodes_Call{Call: _e.mock.On("DeleteRecoveryCodes", ctx, userID)}
+}
+
+func (_c *AuthRepository_DeleteRecoveryCodes_Call) Run(run func(ctx context.Context, userID string)) *AuthRepository_DeleteRecoveryCodes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_DeleteRecoveryCodes_Call) Return(err error) *AuthRepository_DeleteRecoveryCodes_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthRepository_DeleteRecoveryCodes_Call) RunAndReturn(run func(ctx context.Context, userID string) error) *AuthRepository_DeleteRecoveryCodes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteTOTPFactor provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) DeleteTOTPFactor(ctx context.Context, userID string) error {
+	ret := _mock.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteTOTPFactor")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthRepository_DeleteTOTPFactor_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteTOTPFactor'
+type AuthRepository_DeleteTOTPFactor_Call struct {
+	*mock.Call
+}
+
+// DeleteTOTPFactor is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID string
+func (_e *AuthRepository_Expecter) DeleteTOTPFactor(ctx interface{}, userID interface{}) *AuthRepository_DeleteTOTPFactor_Call {
+	return &AuthRepository_DeleteTOTPFactor_Call{Call: _e.mock.On("DeleteTOTPFactor", ctx, userID)}
+}
+
+func (_c *AuthRepository_DeleteTOTPFactor_Call) Run(run func(ctx context.Context, userID string)) *AuthRepository_DeleteTOTPFactor_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_DeleteTOTPFactor_Call) Return(err error) *AuthRepository_DeleteTOTPFactor_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthRepository_DeleteTOTPFactor_Call) RunAndReturn(run func(ctx context.Context, userID string) error) *AuthRepository_DeleteTOTPFactor_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteWebAuthnCredential provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) DeleteWebAuthnCredential(ctx context.Context, userID string, credentialID string) error {
+	ret := _mock.Called(ctx, userID, credentialID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteWebAuthnCredential")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = returnFunc(ctx, userID, credentialID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthRepository_DeleteWebAuthnCredential_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteWebAuthnCredential'
+type AuthRepository_DeleteWebAuthnCredential_Call struct {
+	*mock.Call
+}
+
+// DeleteWebAuthnCredential is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID string
+//   - credentialID string
+func (_e *AuthRepository_Expecter) DeleteWebAuthnCredential(ctx interface{}, userID interface{}, credentialID interface{}) *AuthRepository_DeleteWebAuthnCredential_Call {
+	return &AuthRepository_DeleteWebAuthnCredential_Call{Call: _e.mock.On("DeleteWebAuthnCredential", ctx, userID, credentialID)}
+}
+
+func (_c *AuthRepository_DeleteWebAuthnCredential_Call) Run(run func(ctx context.Context, userID string, credentialID string)) *AuthRepository_DeleteWebAuthnCredential_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_DeleteWebAuthnCredential_Call) Return(err error) *AuthRepository_DeleteWebAuthnCredential_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthRepository_DeleteWebAuthnCredential_Call) RunAndReturn(run func(ctx context.Context, userID string, credentialID string) error) *AuthRepository_DeleteWebAuthnCredential_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAccountUnlockToken provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) GetAccountUnlockToken(ctx context.Context, value string) (*auth.AccountUnlockToken, error) {
+	ret := _mock.Called(ctx, value)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAccountUnlockToken")
+	}
+
+	var r0 *auth.AccountUnlockToken
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*auth.AccountUnlockToken, error)); ok {
+		return returnFunc(ctx, value)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *auth.AccountUnlockToken); ok {
+		r0 = returnFunc(ctx, value)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*auth.AccountUnlockToken)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, value)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// AuthRepository_GetAccountUnlockToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAccountUnlockToken'
+type AuthRepository_GetAccountUnlockToken_Call struct {
+	*mock.Call
+}
+
+// GetAccountUnlockToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - value string
+func (_e *AuthRepository_Expecter) GetAccountUnlockToken(ctx interface{}, value interface{}) *AuthRepository_GetAccountUnlockToken_Call {
+	return &AuthRepository_GetAccountUnlockToken_Call{Call: _e.mock.On("GetAccountUnlockToken", ctx, value)}
+}
+
+func (_c *AuthRepository_GetAccountUnlockToken_Call) Run(run func(ctx context.Context, value string)) *AuthRepository_GetAccountUnlockToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_GetAccountUnlockToken_Call) Return(accountUnlockToken *auth.AccountUnlockToken, err error) *AuthRepository_GetAccountUnlockToken_Call {
+	_c.Call.Return(accountUnlockToken, err)
+	return _c
+}
+
+func (_c *AuthRepository_GetAccountUnlockToken_Call) RunAndReturn(run func(ctx context.Context, value string) (*auth.AccountUnlockToken, error)) *AuthRepository_GetAccountUnlockToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetEmailChangeToken provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) GetEmailChangeToken(ctx context.Context, value string) (*auth.EmailChangeToken, error) {
+	ret := _mock.Called(ctx, value)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEmailChangeToken")
+	}
+
+	var r0 *auth.EmailChangeToken
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*auth.EmailChangeToken, error)); ok {
+		return returnFunc(ctx, value)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *auth.EmailChangeToken); ok {
+		r0 = returnFunc(ctx, value)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*auth.EmailChangeToken)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, value)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// AuthRepository_GetEmailChangeToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetEmailChangeToken'
+type AuthRepository_GetEmailChangeToken_Call struct {
+	*mock.Call
+}
+
+// GetEmailChangeToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - value string
+func (_e *AuthRepository_Expecter) GetEmailChangeToken(ctx interface{}, value interface{}) *AuthRepository_GetEmailChangeToken_Call {
+	return &AuthRepository_GetEmailChangeToken_Call{Call: _e.mock.On("GetEmailChangeToken", ctx, value)}
+}
+
+func (_c *AuthRepository_GetEmailChangeToken_Call) Run(run func(ctx context.Context, value string)) *AuthRepository_GetEmailChangeToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_GetEmailChangeToken_Call) Return(emailChangeToken *auth.EmailChangeToken, err error) *AuthRepository_GetEmailChangeToken_Call {
+	_c.Call.Return(emailChangeToken, err)
+	return _c
+}
+
+func (_c *AuthRepository_GetEmailChangeToken_Call) RunAndReturn(run func(ctx context.Context, value string) (*auth.EmailChangeToken, error)) *AuthRepository_GetEmailChangeToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetEmailVerificationToken provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) GetEmailVerificationToken(ctx context.Context, value string) (*auth.EmailVerificationToken, error) {
+	ret := _mock.Called(ctx, value)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEmailVerificationToken")
+	}
+
+	var r0 *auth.EmailVerificationToken
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*auth.EmailVerificationToken, error)); ok {
+		return returnFunc(ctx, value)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *auth.EmailVerificationToken); ok {
+		r0 = returnFunc(ctx, value)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*auth.EmailVerificationToken)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, value)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// AuthRepository_GetEmailVerificationToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetEmailVerificationToken'
+type AuthRepository_GetEmailVerificationToken_Call struct {
+	*mock.Call
+}
+
+// GetEmailVerificationToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - value string
+func (_e *AuthRepository_Expecter) GetEmailVerificationToken(ctx interface{}, value interface{}) *AuthRepository_GetEmailVerificationToken_Call {
+	return &AuthRepository_GetEmailVerificationToken_Call{Call: _e.mock.On("GetEmailVerificationToken", ctx, value)}
+}
+
+func (_c *AuthRepository_GetEmailVerificationToken_Call) Run(run func(ctx context.Context, value string)) *AuthRepository_GetEmailVerificationToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_GetEmailVerificationToken_Call) Return(emailVerificationToken *auth.EmailVerificationToken, err error) *AuthRepository_GetEmailVerificationToken_Call {
+	_c.Call.Return(emailVerificationToken, err)
+	return _c
+}
+
+func (_c *AuthRepository_GetEmailVerificationToken_Call) RunAndReturn(run func(ctx context.Context, value string) (*auth.EmailVerificationToken, error)) *AuthRepository_GetEmailVerificationToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetExternalIdentity provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) GetExternalIdentity(ctx context.Context, provider string, subject string) (*auth.ExternalIdentity, error) {
+	ret := _mock.Called(ctx, provider, subject)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetExternalIdentity")
+	}
+
+	var r0 *auth.ExternalIdentity
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (*auth.ExternalIdentity, error)); ok {
+		return returnFunc(ctx, provider, subject)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) *auth.ExternalIdentity); ok {
+		r0 = returnFunc(ctx, provider, subject)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*auth.ExternalIdentity)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, provider, subject)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// AuthRepository_GetExternalIdentity_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetExternalIdentity'
+type AuthRepository_GetExternalIdentity_Call struct {
+	*mock.Call
+}
+
+// GetExternalIdentity is a helper method to define mock.On call
+//   - ctx context.Context
+//   - provider string
+//   - subject string
+func (_e *AuthRepository_Expecter) GetExternalIdentity(ctx interface{}, provider interface{}, subject interface{}) *AuthRepository_GetExternalIdentity_Call {
+	return &AuthRepository_GetExternalIdentity_Call{Call: _e.mock.On("GetExternalIdentity", ctx, provider, subject)}
+}
+
+func (_c *AuthRepository_GetExternalIdentity_Call) Run(run func(ctx context.Context, provider string, subject string)) *AuthRepository_GetExternalIdentity_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_GetExternalIdentity_Call) Return(externalIdentity *auth.ExternalIdentity, err error) *AuthRepository_GetExternalIdentity_Call {
+	_c.Call.Return(externalIdentity, err)
+	return _c
+}
+
+func (_c *AuthRepository_GetExternalIdentity_Call) RunAndReturn(run func(ctx context.Context, provider string, subject string) (*auth.ExternalIdentity, error)) *AuthRepository_GetExternalIdentity_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetLoginAttempts provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) GetLoginAttempts(ctx context.Context, key string) (*auth.LoginAttempts, error) {
+	ret := _mock.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLoginAttempts")
+	}
+
+	var r0 *auth.LoginAttempts
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*auth.LoginAttempts, error)); ok {
+		return returnFunc(ctx, key)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *auth.LoginAttempts); ok {
+		r0 = returnFunc(ctx, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*auth.LoginAttempts)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// AuthRepository_GetLoginAttempts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLoginAttempts'
+type AuthRepository_GetLoginAttempts_Call struct {
+	*mock.Call
+}
+
+// GetLoginAttempts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+func (_e *AuthRepository_Expecter) GetLoginAttempts(ctx interface{}, key interface{}) *AuthRepository_GetLoginAttempts_Call {
+	return &AuthRepository_GetLoginAttempts_Call{Call: _e.mock.On("GetLoginAttempts", ctx, key)}
+}
+
+func (_c *AuthRepository_GetLoginAttempts_Call) Run(run func(ctx context.Context, key string)) *AuthRepository_GetLoginAttempts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_GetLoginAttempts_Call) Return(loginAttempts *auth.LoginAttempts, err error) *AuthRepository_GetLoginAttempts_Call {
+	_c.Call.Return(loginAttempts, err)
+	return _c
+}
+
+func (_c *AuthRepository_GetLoginAttempts_Call) RunAndReturn(run func(ctx context.Context, key string) (*auth.LoginAttempts, error)) *AuthRepository_GetLoginAttempts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetMFAChallenge provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) GetMFAChallenge(ctx context.Context, value string) (*auth.MFAChallenge, error) {
+	ret := _mock.Called(ctx, value)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMFAChallenge")
+	}
+
+	var r0 *auth.MFAChallenge
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*auth.MFAChallenge, error)); ok {
+		return returnFunc(ctx, value)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *auth.MFAChallenge); ok {
+		r0 = returnFunc(ctx, value)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*auth.MFAChallenge)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, value)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// AuthRepository_GetMFAChallenge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMFAChallenge'
+type AuthRepository_GetMFAChallenge_Call struct {
+	*mock.Call
+}
+
+// GetMFAChallenge is a helper method to define mock.On call
+//   - ctx context.Context
+//   - value string
+func (_e *AuthRepository_Expecter) GetMFAChallenge(ctx interface{}, value interface{}) *AuthRepository_GetMFAChallenge_Call {
+	return &AuthRepository_GetMFAChallenge_Call{Call: _e.mock.On("GetMFAChallenge", ctx, value)}
+}
+
+func (_c *AuthRepository_GetMFAChallenge_Call) Run(run func(ctx context.Context, value string)) *AuthRepository_GetMFAChallenge_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_GetMFAChallenge_Call) Return(mFAChallenge *auth.MFAChallenge, err error) *AuthRepository_GetMFAChallenge_Call {
+	_c.Call.Return(mFAChallenge, err)
+	return _c
+}
+
+func (_c *AuthRepository_GetMFAChallenge_Call) RunAndReturn(run func(ctx context.Context, value string) (*auth.MFAChallenge, error)) *AuthRepository_GetMFAChallenge_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetMagicLinkToken provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) GetMagicLinkToken(ctx context.Context, value string) (*auth.MagicLinkToken, error) {
+	ret := _mock.Called(ctx, value)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMagicLinkToken")
+	}
+
+	var r0 *auth.MagicLinkToken
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*auth.MagicLinkToken, error)); ok {
+		return returnFunc(ctx, value)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *auth.MagicLinkToken); ok {
+		r0 = returnFunc(ctx, value)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*auth.MagicLinkToken)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, value)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// AuthRepository_GetMagicLinkToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMagicLinkToken'
+type AuthRepository_GetMagicLinkToken_Call struct {
+	*mock.Call
+}
+
+// GetMagicLinkToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - value string
+func (_e *AuthRepository_Expecter) GetMagicLinkToken(ctx interface{}, value interface{}) *AuthRepository_GetMagicLinkToken_Call {
+	return &AuthRepository_GetMagicLinkToken_Call{Call: _e.mock.On("GetMagicLinkToken", ctx, value)}
+}
+
+func (_c *AuthRepository_GetMagicLinkToken_Call) Run(run func(ctx context.Context, value string)) *AuthRepository_GetMagicLinkToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_GetMagicLinkToken_Call) Return(magicLinkToken *auth.MagicLinkToken, err error) *AuthRepository_GetMagicLinkToken_Call {
+	_c.Call.Return(magicLinkToken, err)
+	return _c
+}
+
+func (_c *AuthRepository_GetMagicLinkToken_Call) RunAndReturn(run func(ctx context.Context, value string) (*auth.MagicLinkToken, error)) *AuthRepository_GetMagicLinkToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPasswordHistory provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) GetPasswordHistory(ctx context.Context, userID string, limit int) ([]string, error) {
+	ret := _mock.Called(ctx, userID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPasswordHistory")
+	}
+
+	var r0 []string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int) ([]string, error)); ok {
+		return returnFunc(ctx, userID, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int) []string); ok {
+		r0 = returnFunc(ctx, userID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = returnFunc(ctx, userID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// AuthRepository_GetPasswordHistory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPasswordHistory'
+type AuthRepository_GetPasswordHistory_Call struct {
+	*mock.Call
+}
+
+// GetPasswordHistory is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID string
+//   - limit int
+func (_e *AuthRepository_Expecter) GetPasswordHistory(ctx interface{}, userID interface{}, limit interface{}) *AuthRepository_GetPasswordHistory_Call {
+	return &AuthRepository_GetPasswordHistory_Call{Call: _e.mock.On("GetPasswordHistory", ctx, userID, limit)}
+}
+
+func (_c *AuthRepository_GetPasswordHistory_Call) Run(run func(ctx context.Context, userID string, limit int)) *AuthRepository_GetPasswordHistory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_GetPasswordHistory_Call) Return(strings []string, err error) *AuthRepository_GetPasswordHistory_Call {
+	_c.Call.Return(strings, err)
+	return _c
+}
+
+func (_c *AuthRepository_GetPasswordHistory_Call) RunAndReturn(run func(ctx context.Context, userID string, limit int) ([]string, error)) *AuthRepository_GetPasswordHistory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPersonalAccessTokenByHash provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) GetPersonalAccessTokenByHash(ctx context.Context, tokenHash string) (*auth.PersonalAccessToken, error) {
+	ret := _mock.Called(ctx, tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPersonalAccessTokenByHash")
+	}
+
+	var r0 *auth.PersonalAccessToken
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*auth.PersonalAccessToken, error)); ok {
+		return returnFunc(ctx, tokenHash)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *auth.PersonalAccessToken); ok {
+		r0 = returnFunc(ctx, tokenHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*auth.PersonalAccessToken)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// AuthRepository_GetPersonalAccessTokenByHash_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPersonalAccessTokenByHash'
+type AuthRepository_GetPersonalAccessTokenByHash_Call struct {
+	*mock.Call
+}
+
+// GetPersonalAccessTokenByHash is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tokenHash string
+func (_e *AuthRepository_Expecter) GetPersonalAccessTokenByHash(ctx interface{}, tokenHash interface{}) *AuthRepository_GetPersonalAccessTokenByHash_Call {
+	return &AuthRepository_GetPersonalAccessTokenByHash_Call{Call: _e.mock.On("GetPersonalAccessTokenByHash", ctx, tokenHash)}
+}
+
+func (_c *AuthRepository_GetPersonalAccessTokenByHash_Call) Run(run func(ctx context.Context, tokenHash string)) *AuthRepository_GetPersonalAccessTokenByHash_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_GetPersonalAccessTokenByHash_Call) Return(personalAccessToken *auth.PersonalAccessToken, err error) *AuthRepository_GetPersonalAccessTokenByHash_Call {
+	_c.Call.Return(personalAccessToken, err)
+	return _c
+}
+
+func (_c *AuthRepository_GetPersonalAccessTokenByHash_Call) RunAndReturn(run func(ctx context.Context, tokenHash string) (*auth.PersonalAccessToken, error)) *AuthRepository_GetPersonalAccessTokenByHash_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRecoveryCode provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) GetRecoveryCode(ctx context.Context, userID string, codeHash string) (*auth.RecoveryCode, error) {
+	ret := _mock.Called(ctx, userID, codeHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRecoveryCode")
+	}
+
+	var r0 *auth.RecoveryCode
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (*auth.RecoveryCode, error)); ok {
+		return returnFunc(ctx, userID, codeHash)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) *auth.RecoveryCode); ok {
+		r0 = returnFunc(ctx, userID, codeHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*auth.RecoveryCode)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, userID, codeHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// AuthRepository_GetRecoveryCode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRecoveryCode'
+type AuthRepository_GetRecoveryCode_Call struct {
+	*mock.Call
+}
+
+// GetRecoveryCode is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID string
+//   - codeHash string
+func (_e *AuthRepository_Expecter) GetRecoveryCode(ctx interface{}, userID interface{}, codeHash interface{}) *AuthRepository_GetRecoveryCode_Call {
+	return &AuthRepository_GetRecoveryCode_Call{Call: _e.mock.On("GetRecoveryCode", ctx, userID, codeHash)}
+}
+
+func (_c *AuthRepository_GetRecoveryCode_Call) Run(run func(ctx context.Context, userID string, codeHash string)) *AuthRepository_GetRecoveryCode_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_GetRecoveryCode_Call) Return(recoveryCode *auth.RecoveryCode, err error) *AuthRepository_GetRecoveryCode_Call {
+	_c.Call.Return(recoveryCode, err)
+	return _c
+}
+
+func (_c *AuthRepository_GetRecoveryCode_Call) RunAndReturn(run func(ctx context.Context, userID string, codeHash string) (*auth.RecoveryCode, error)) *AuthRepository_GetRecoveryCode_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetResetPasswordToken provides a mock function for the type AuthRepository
 func (_mock *AuthRepository) GetResetPasswordToken(ctx context.Context, value string) (*auth.ResetPasswordToken, error) {
 	ret := _mock.Called(ctx, value)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetResetPasswordToken")
+		panic("no return value specified for GetResetPasswordToken")
+	}
+
+	var r0 *auth.ResetPasswordToken
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*auth.ResetPasswordToken, error)); ok {
+		return returnFunc(ctx, value)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *auth.ResetPasswordToken); ok {
+		r0 = returnFunc(ctx, value)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*auth.ResetPasswordToken)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, value)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// AuthRepository_GetResetPasswordToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetResetPasswordToken'
+type AuthRepository_GetResetPasswordToken_Call struct {
+	*mock.Call
+}
+
+// GetResetPasswordToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - value string
+func (_e *AuthRepository_Expecter) GetResetPasswordToken(ctx interface{}, value interface{}) *AuthRepository_GetResetPasswordToken_Call {
+	return &AuthRepository_GetResetPasswordToken_Call{Call: _e.mock.On("GetResetPasswordToken", ctx, value)}
+}
+
+func (_c *AuthRepository_GetResetPasswordToken_Call) Run(run func(ctx context.Context, value string)) *AuthRepository_GetResetPasswordToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_GetResetPasswordToken_Call) Return(resetPasswordToken *auth.ResetPasswordToken, err error) *AuthRepository_GetResetPasswordToken_Call {
+	_c.Call.Return(resetPasswordToken, err)
+	return _c
+}
+
+func (_c *AuthRepository_GetResetPasswordToken_Call) RunAndReturn(run func(ctx context.Context, value string) (*auth.ResetPasswordToken, error)) *AuthRepository_GetResetPasswordToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSession provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) GetSession(ctx context.Context, sessionID string) (*auth.Session, error) {
+	ret := _mock.Called(ctx, sessionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSession")
+	}
+
+	var r0 *auth.Session
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*auth.Session, error)); ok {
+		return returnFunc(ctx, sessionID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *auth.Session); ok {
+		r0 = returnFunc(ctx, sessionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*auth.Session)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, sessionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// AuthRepository_GetSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSession'
+type AuthRepository_GetSession_Call struct {
+	*mock.Call
+}
+
+// GetSession is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sessionID string
+func (_e *AuthRepository_Expecter) GetSession(ctx interface{}, sessionID interface{}) *AuthRepository_GetSession_Call {
+	return &AuthRepository_GetSession_Call{Call: _e.mock.On("GetSession", ctx, sessionID)}
+}
+
+func (_c *AuthRepository_GetSession_Call) Run(run func(ctx context.Context, sessionID string)) *AuthRepository_GetSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_GetSession_Call) Return(session *auth.Session, err error) *AuthRepository_GetSession_Call {
+	_c.Call.Return(session, err)
+	return _c
+}
+
+func (_c *AuthRepository_GetSession_Call) RunAndReturn(run func(ctx context.Context, sessionID string) (*auth.Session, error)) *AuthRepository_GetSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSessionRevokeToken provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) GetSessionRevokeToken(ctx context.Context, value string) (*auth.SessionRevokeToken, error) {
+	ret := _mock.Called(ctx, value)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSessionRevokeToken")
+	}
+
+	var r0 *auth.SessionRevokeToken
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*auth.SessionRevokeToken, error)); ok {
+		return returnFunc(ctx, value)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *auth.SessionRevokeToken); ok {
+		r0 = returnFunc(ctx, value)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*auth.SessionRevokeToken)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, value)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// AuthRepository_GetSessionRevokeToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSessionRevokeToken'
+type AuthRepository_GetSessionRevokeToken_Call struct {
+	*mock.Call
+}
+
+// GetSessionRevokeToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - value string
+func (_e *AuthRepository_Expecter) GetSessionRevokeToken(ctx interface{}, value interface{}) *AuthRepository_GetSessionRevokeToken_Call {
+	return &AuthRepository_GetSessionRevokeToken_Call{Call: _e.mock.On("GetSessionRevokeToken", ctx, value)}
+}
+
+func (_c *AuthRepository_GetSessionRevokeToken_Call) Run(run func(ctx context.Context, value string)) *AuthRepository_GetSessionRevokeToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_GetSessionRevokeToken_Call) Return(sessionRevokeToken *auth.SessionRevokeToken, err error) *AuthRepository_GetSessionRevokeToken_Call {
+	_c.Call.Return(sessionRevokeToken, err)
+	return _c
+}
+
+func (_c *AuthRepository_GetSessionRevokeToken_Call) RunAndReturn(run func(ctx context.Context, value string) (*auth.SessionRevokeToken, error)) *AuthRepository_GetSessionRevokeToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTOTPFactor provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) GetTOTPFactor(ctx context.Context, userID string) (*auth.TOTPFactor, error) {
+	ret := _mock.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTOTPFactor")
+	}
+
+	var r0 *auth.TOTPFactor
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*auth.TOTPFactor, error)); ok {
+		return returnFunc(ctx, userID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *auth.TOTPFactor); ok {
+		r0 = returnFunc(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*auth.TOTPFactor)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// AuthRepository_GetTOTPFactor_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTOTPFactor'
+type AuthRepository_GetTOTPFactor_Call struct {
+	*mock.Call
+}
+
+// GetTOTPFactor is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID string
+func (_e *AuthRepository_Expecter) GetTOTPFactor(ctx interface{}, userID interface{}) *AuthRepository_GetTOTPFactor_Call {
+	return &AuthRepository_GetTOTPFactor_Call{Call: _e.mock.On("GetTOTPFactor", ctx, userID)}
+}
+
+func (_c *AuthRepository_GetTOTPFactor_Call) Run(run func(ctx context.Context, userID string)) *AuthRepository_GetTOTPFactor_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_GetTOTPFactor_Call) Return(tOTPFactor *auth.TOTPFactor, err error) *AuthRepository_GetTOTPFactor_Call {
+	_c.Call.Return(tOTPFactor, err)
+	return _c
+}
+
+func (_c *AuthRepository_GetTOTPFactor_Call) RunAndReturn(run func(ctx context.Context, userID string) (*auth.TOTPFactor, error)) *AuthRepository_GetTOTPFactor_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWebAuthnChallenge provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) GetWebAuthnChallenge(ctx context.Context, value string) (*auth.WebAuthnChallenge, error) {
+	ret := _mock.Called(ctx, value)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWebAuthnChallenge")
+	}
+
+	var r0 *auth.WebAuthnChallenge
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*auth.WebAuthnChallenge, error)); ok {
+		return returnFunc(ctx, value)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *auth.WebAuthnChallenge); ok {
+		r0 = returnFunc(ctx, value)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*auth.WebAuthnChallenge)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, value)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// AuthRepository_GetWebAuthnChallenge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWebAuthnChallenge'
+type AuthRepository_GetWebAuthnChallenge_Call struct {
+	*mock.Call
+}
+
+// GetWebAuthnChallenge is a helper method to define mock.On call
+//   - ctx context.Context
+//   - value string
+func (_e *AuthRepository_Expecter) GetWebAuthnChallenge(ctx interface{}, value interface{}) *AuthRepository_GetWebAuthnChallenge_Call {
+	return &AuthRepository_GetWebAuthnChallenge_Call{Call: _e.mock.On("GetWebAuthnChallenge", ctx, value)}
+}
+
+func (_c *AuthRepository_GetWebAuthnChallenge_Call) Run(run func(ctx context.Context, value string)) *AuthRepository_GetWebAuthnChallenge_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_GetWebAuthnChallenge_Call) Return(webAuthnChallenge *auth.WebAuthnChallenge, err error) *AuthRepository_GetWebAuthnChallenge_Call {
+	_c.Call.Return(webAuthnChallenge, err)
+	return _c
+}
+
+func (_c *AuthRepository_GetWebAuthnChallenge_Call) RunAndReturn(run func(ctx context.Context, value string) (*auth.WebAuthnChallenge, error)) *AuthRepository_GetWebAuthnChallenge_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListKnownDevicesByUser provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) ListKnownDevicesByUser(ctx context.Context, userID string) ([]*auth.KnownDevice, error) {
+	ret := _mock.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListKnownDevicesByUser")
+	}
+
+	var r0 []*auth.KnownDevice
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]*auth.KnownDevice, error)); ok {
+		return returnFunc(ctx, userID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []*auth.KnownDevice); ok {
+		r0 = returnFunc(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*auth.KnownDevice)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// AuthRepository_ListKnownDevicesByUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListKnownDevicesByUser'
+type AuthRepository_ListKnownDevicesByUser_Call struct {
+	*mock.Call
+}
+
+// ListKnownDevicesByUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID string
+func (_e *AuthRepository_Expecter) ListKnownDevicesByUser(ctx interface{}, userID interface{}) *AuthRepository_ListKnownDevicesByUser_Call {
+	return &AuthRepository_ListKnownDevicesByUser_Call{Call: _e.mock.On("ListKnownDevicesByUser", ctx, userID)}
+}
+
+func (_c *AuthRepository_ListKnownDevicesByUser_Call) Run(run func(ctx context.Context, userID string)) *AuthRepository_ListKnownDevicesByUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_ListKnownDevicesByUser_Call) Return(knownDevices []*auth.KnownDevice, err error) *AuthRepository_ListKnownDevicesByUser_Call {
+	_c.Call.Return(knownDevices, err)
+	return _c
+}
+
+func (_c *AuthRepository_ListKnownDevicesByUser_Call) RunAndReturn(run func(ctx context.Context, userID string) ([]*auth.KnownDevice, error)) *AuthRepository_ListKnownDevicesByUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListPersonalAccessTokensByUser provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) ListPersonalAccessTokensByUser(ctx context.Context, userID string) ([]*auth.PersonalAccessToken, error) {
+	ret := _mock.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPersonalAccessTokensByUser")
+	}
+
+	var r0 []*auth.PersonalAccessToken
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]*auth.PersonalAccessToken, error)); ok {
+		return returnFunc(ctx, userID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []*auth.PersonalAccessToken); ok {
+		r0 = returnFunc(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*auth.PersonalAccessToken)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// AuthRepository_ListPersonalAccessTokensByUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListPersonalAccessTokensByUser'
+type AuthRepository_ListPersonalAccessTokensByUser_Call struct {
+	*mock.Call
+}
+
+// ListPersonalAccessTokensByUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID string
+func (_e *AuthRepository_Expecter) ListPersonalAccessTokensByUser(ctx interface{}, userID interface{}) *AuthRepository_ListPersonalAccessTokensByUser_Call {
+	return &AuthRepository_ListPersonalAccessTokensByUser_Call{Call: _e.mock.On("ListPersonalAccessTokensByUser", ctx, userID)}
+}
+
+func (_c *AuthRepository_ListPersonalAccessTokensByUser_Call) Run(run func(ctx context.Context, userID string)) *AuthRepository_ListPersonalAccessTokensByUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_ListPersonalAccessTokensByUser_Call) Return(personalAccessTokens []*auth.PersonalAccessToken, err error) *AuthRepository_ListPersonalAccessTokensByUser_Call {
+	_c.Call.Return(personalAccessTokens, err)
+	return _c
+}
+
+func (_c *AuthRepository_ListPersonalAccessTokensByUser_Call) RunAndReturn(run func(ctx context.Context, userID string) ([]*auth.PersonalAccessToken, error)) *AuthRepository_ListPersonalAccessTokensByUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListSessionsByUser provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) ListSessionsByUser(ctx context.Context, userID string) ([]*auth.Session, error) {
+	ret := _mock.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSessionsByUser")
+	}
+
+	var r0 []*auth.Session
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]*auth.Session, error)); ok {
+		return returnFunc(ctx, userID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []*auth.Session); ok {
+		r0 = returnFunc(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*auth.Session)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// AuthRepository_ListSessionsByUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListSessionsByUser'
+type AuthRepository_ListSessionsByUser_Call struct {
+	*mock.Call
+}
+
+// ListSessionsByUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID string
+func (_e *AuthRepository_Expecter) ListSessionsByUser(ctx interface{}, userID interface{}) *AuthRepository_ListSessionsByUser_Call {
+	return &AuthRepository_ListSessionsByUser_Call{Call: _e.mock.On("ListSessionsByUser", ctx, userID)}
+}
+
+func (_c *AuthRepository_ListSessionsByUser_Call) Run(run func(ctx context.Context, userID string)) *AuthRepository_ListSessionsByUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_ListSessionsByUser_Call) Return(sessions []*auth.Session, err error) *AuthRepository_ListSessionsByUser_Call {
+	_c.Call.Return(sessions, err)
+	return _c
+}
+
+func (_c *AuthRepository_ListSessionsByUser_Call) RunAndReturn(run func(ctx context.Context, userID string) ([]*auth.Session, error)) *AuthRepository_ListSessionsByUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListWebAuthnCredentialsByUser provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) ListWebAuthnCredentialsByUser(ctx context.Context, userID string) ([]*auth.WebAuthnCredential, error) {
+	ret := _mock.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListWebAuthnCredentialsByUser")
+	}
+
+	var r0 []*auth.WebAuthnCredential
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]*auth.WebAuthnCredential, error)); ok {
+		return returnFunc(ctx, userID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []*auth.WebAuthnCredential); ok {
+		r0 = returnFunc(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*auth.WebAuthnCredential)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// AuthRepository_ListWebAuthnCredentialsByUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListWebAuthnCredentialsByUser'
+type AuthRepository_ListWebAuthnCredentialsByUser_Call struct {
+	*mock.Call
+}
+
+// ListWebAuthnCredentialsByUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID string
+func (_e *AuthRepository_Expecter) ListWebAuthnCredentialsByUser(ctx interface{}, userID interface{}) *AuthRepository_ListWebAuthnCredentialsByUser_Call {
+	return &AuthRepository_ListWebAuthnCredentialsByUser_Call{Call: _e.mock.On("ListWebAuthnCredentialsByUser", ctx, userID)}
+}
+
+func (_c *AuthRepository_ListWebAuthnCredentialsByUser_Call) Run(run func(ctx context.Context, userID string)) *AuthRepository_ListWebAuthnCredentialsByUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_ListWebAuthnCredentialsByUser_Call) Return(webAuthnCredentials []*auth.WebAuthnCredential, err error) *AuthRepository_ListWebAuthnCredentialsByUser_Call {
+	_c.Call.Return(webAuthnCredentials, err)
+	return _c
+}
+
+func (_c *AuthRepository_ListWebAuthnCredentialsByUser_Call) RunAndReturn(run func(ctx context.Context, userID string) ([]*auth.WebAuthnCredential, error)) *AuthRepository_ListWebAuthnCredentialsByUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokePendingTokensByUser provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) RevokePendingTokensByUser(ctx context.Context, userID string) error {
+	ret := _mock.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokePendingTokensByUser")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthRepository_RevokePendingTokensByUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokePendingTokensByUser'
+type AuthRepository_RevokePendingTokensByUser_Call struct {
+	*mock.Call
+}
+
+// RevokePendingTokensByUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID string
+func (_e *AuthRepository_Expecter) RevokePendingTokensByUser(ctx interface{}, userID interface{}) *AuthRepository_RevokePendingTokensByUser_Call {
+	return &AuthRepository_RevokePendingTokensByUser_Call{Call: _e.mock.On("RevokePendingTokensByUser", ctx, userID)}
+}
+
+func (_c *AuthRepository_RevokePendingTokensByUser_Call) Run(run func(ctx context.Context, userID string)) *AuthRepository_RevokePendingTokensByUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_RevokePendingTokensByUser_Call) Return(err error) *AuthRepository_RevokePendingTokensByUser_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthRepository_RevokePendingTokensByUser_Call) RunAndReturn(run func(ctx context.Context, userID string) error) *AuthRepository_RevokePendingTokensByUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeSessionFamily provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) RevokeSessionFamily(ctx context.Context, familyID string) error {
+	ret := _mock.Called(ctx, familyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeSessionFamily")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, familyID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthRepository_RevokeSessionFamily_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeSessionFamily'
+type AuthRepository_RevokeSessionFamily_Call struct {
+	*mock.Call
+}
+
+// RevokeSessionFamily is a helper method to define mock.On call
+//   - ctx context.Context
+//   - familyID string
+func (_e *AuthRepository_Expecter) RevokeSessionFamily(ctx interface{}, familyID interface{}) *AuthRepository_RevokeSessionFamily_Call {
+	return &AuthRepository_RevokeSessionFamily_Call{Call: _e.mock.On("RevokeSessionFamily", ctx, familyID)}
+}
+
+func (_c *AuthRepository_RevokeSessionFamily_Call) Run(run func(ctx context.Context, familyID string)) *AuthRepository_RevokeSessionFamily_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_RevokeSessionFamily_Call) Return(err error) *AuthRepository_RevokeSessionFamily_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthRepository_RevokeSessionFamily_Call) RunAndReturn(run func(ctx context.Context, familyID string) error) *AuthRepository_RevokeSessionFamily_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeSessionsByUser provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) RevokeSessionsByUser(ctx context.Context, userID string, exceptFamilyID string) error {
+	ret := _mock.Called(ctx, userID, exceptFamilyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeSessionsByUser")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = returnFunc(ctx, userID, exceptFamilyID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthRepository_RevokeSessionsByUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeSessionsByUser'
+type AuthRepository_RevokeSessionsByUser_Call struct {
+	*mock.Call
+}
+
+// RevokeSessionsByUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID string
+//   - exceptFamilyID string
+func (_e *AuthRepository_Expecter) RevokeSessionsByUser(ctx interface{}, userID interface{}, exceptFamilyID interface{}) *AuthRepository_RevokeSessionsByUser_Call {
+	return &AuthRepository_RevokeSessionsByUser_Call{Call: _e.mock.On("RevokeSessionsByUser", ctx, userID, exceptFamilyID)}
+}
+
+func (_c *AuthRepository_RevokeSessionsByUser_Call) Run(run func(ctx context.Context, userID string, exceptFamilyID string)) *AuthRepository_RevokeSessionsByUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_RevokeSessionsByUser_Call) Return(err error) *AuthRepository_RevokeSessionsByUser_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthRepository_RevokeSessionsByUser_Call) RunAndReturn(run func(ctx context.Context, userID string, exceptFamilyID string) error) *AuthRepository_RevokeSessionsByUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StoreAccountUnlockToken provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) StoreAccountUnlockToken(ctx context.Context, token *auth.AccountUnlockToken) error {
+	ret := _mock.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreAccountUnlockToken")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *auth.AccountUnlockToken) error); ok {
+		r0 = returnFunc(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthRepository_StoreAccountUnlockToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StoreAccountUnlockToken'
+type AuthRepository_StoreAccountUnlockToken_Call struct {
+	*mock.Call
+}
+
+// StoreAccountUnlockToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token *auth.AccountUnlockToken
+func (_e *AuthRepository_Expecter) StoreAccountUnlockToken(ctx interface{}, token interface{}) *AuthRepository_StoreAccountUnlockToken_Call {
+	return &AuthRepository_StoreAccountUnlockToken_Call{Call: _e.mock.On("StoreAccountUnlockToken", ctx, token)}
+}
+
+func (_c *AuthRepository_StoreAccountUnlockToken_Call) Run(run func(ctx context.Context, token *auth.AccountUnlockToken)) *AuthRepository_StoreAccountUnlockToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *auth.AccountUnlockToken
+		if args[1] != nil {
+			arg1 = args[1].(*auth.AccountUnlockToken)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_StoreAccountUnlockToken_Call) Return(err error) *AuthRepository_StoreAccountUnlockToken_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthRepository_StoreAccountUnlockToken_Call) RunAndReturn(run func(ctx context.Context, token *auth.AccountUnlockToken) error) *AuthRepository_StoreAccountUnlockToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StoreEmailChangeToken provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) StoreEmailChangeToken(ctx context.Context, token *auth.EmailChangeToken) error {
+	ret := _mock.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreEmailChangeToken")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *auth.EmailChangeToken) error); ok {
+		r0 = returnFunc(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthRepository_StoreEmailChangeToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StoreEmailChangeToken'
+type AuthRepository_StoreEmailChangeToken_Call struct {
+	*mock.Call
+}
+
+// StoreEmailChangeToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token *auth.EmailChangeToken
+func (_e *AuthRepository_Expecter) StoreEmailChangeToken(ctx interface{}, token interface{}) *AuthRepository_StoreEmailChangeToken_Call {
+	return &AuthRepository_StoreEmailChangeToken_Call{Call: _e.mock.On("StoreEmailChangeToken", ctx, token)}
+}
+
+func (_c *AuthRepository_StoreEmailChangeToken_Call) Run(run func(ctx context.Context, token *auth.EmailChangeToken)) *AuthRepository_StoreEmailChangeToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *auth.EmailChangeToken
+		if args[1] != nil {
+			arg1 = args[1].(*auth.EmailChangeToken)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_StoreEmailChangeToken_Call) Return(err error) *AuthRepository_StoreEmailChangeToken_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthRepository_StoreEmailChangeToken_Call) RunAndReturn(run func(ctx context.Context, token *auth.EmailChangeToken) error) *AuthRepository_StoreEmailChangeToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StoreEmailVerificationToken provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) StoreEmailVerificationToken(ctx context.Context, token *auth.EmailVerificationToken) error {
+	ret := _mock.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreEmailVerificationToken")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *auth.EmailVerificationToken) error); ok {
+		r0 = returnFunc(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthRepository_StoreEmailVerificationToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StoreEmailVerificationToken'
+type AuthRepository_StoreEmailVerificationToken_Call struct {
+	*mock.Call
+}
+
+// StoreEmailVerificationToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token *auth.EmailVerificationToken
+func (_e *AuthRepository_Expecter) StoreEmailVerificationToken(ctx interface{}, token interface{}) *AuthRepository_StoreEmailVerificationToken_Call {
+	return &AuthRepository_StoreEmailVerificationToken_Call{Call: _e.mock.On("StoreEmailVerificationToken", ctx, token)}
+}
+
+func (_c *AuthRepository_StoreEmailVerificationToken_Call) Run(run func(ctx context.Context, token *auth.EmailVerificationToken)) *AuthRepository_StoreEmailVerificationToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *auth.EmailVerificationToken
+		if args[1] != nil {
+			arg1 = args[1].(*auth.EmailVerificationToken)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_StoreEmailVerificationToken_Call) Return(err error) *AuthRepository_StoreEmailVerificationToken_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthRepository_StoreEmailVerificationToken_Call) RunAndReturn(run func(ctx context.Context, token *auth.EmailVerificationToken) error) *AuthRepository_StoreEmailVerificationToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StoreExternalIdentity provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) StoreExternalIdentity(ctx context.Context, identity *auth.ExternalIdentity) error {
+	ret := _mock.Called(ctx, identity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreExternalIdentity")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *auth.ExternalIdentity) error); ok {
+		r0 = returnFunc(ctx, identity)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthRepository_StoreExternalIdentity_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StoreExternalIdentity'
+type AuthRepository_StoreExternalIdentity_Call struct {
+	*mock.Call
+}
+
+// StoreExternalIdentity is a helper method to define mock.On call
+//   - ctx context.Context
+//   - identity *auth.ExternalIdentity
+func (_e *AuthRepository_Expecter) StoreExternalIdentity(ctx interface{}, identity interface{}) *AuthRepository_StoreExternalIdentity_Call {
+	return &AuthRepository_StoreExternalIdentity_Call{Call: _e.mock.On("StoreExternalIdentity", ctx, identity)}
+}
+
+func (_c *AuthRepository_StoreExternalIdentity_Call) Run(run func(ctx context.Context, identity *auth.ExternalIdentity)) *AuthRepository_StoreExternalIdentity_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *auth.ExternalIdentity
+		if args[1] != nil {
+			arg1 = args[1].(*auth.ExternalIdentity)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_StoreExternalIdentity_Call) Return(err error) *AuthRepository_StoreExternalIdentity_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthRepository_StoreExternalIdentity_Call) RunAndReturn(run func(ctx context.Context, identity *auth.ExternalIdentity) error) *AuthRepository_StoreExternalIdentity_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StoreKnownDevice provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) StoreKnownDevice(ctx context.Context, device *auth.KnownDevice) error {
+	ret := _mock.Called(ctx, device)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreKnownDevice")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *auth.KnownDevice) error); ok {
+		r0 = returnFunc(ctx, device)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthRepository_StoreKnownDevice_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StoreKnownDevice'
+type AuthRepository_StoreKnownDevice_Call struct {
+	*mock.Call
+}
+
+// StoreKnownDevice is a helper method to define mock.On call
+//   - ctx context.Context
+//   - device *auth.KnownDevice
+func (_e *AuthRepository_Expecter) StoreKnownDevice(ctx interface{}, device interface{}) *AuthRepository_StoreKnownDevice_Call {
+	return &AuthRepository_StoreKnownDevice_Call{Call: _e.mock.On("StoreKnownDevice", ctx, device)}
+}
+
+func (_c *AuthRepository_StoreKnownDevice_Call) Run(run func(ctx context.Context, device *auth.KnownDevice)) *AuthRepository_StoreKnownDevice_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *auth.KnownDevice
+		if args[1] != nil {
+			arg1 = args[1].(*auth.KnownDevice)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_StoreKnownDevice_Call) Return(err error) *AuthRepository_StoreKnownDevice_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthRepository_StoreKnownDevice_Call) RunAndReturn(run func(ctx context.Context, device *auth.KnownDevice) error) *AuthRepository_StoreKnownDevice_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StoreLoginAttempts provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) StoreLoginAttempts(ctx context.Context, attempts *auth.LoginAttempts) error {
+	ret := _mock.Called(ctx, attempts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreLoginAttempts")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *auth.LoginAttempts) error); ok {
+		r0 = returnFunc(ctx, attempts)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthRepository_StoreLoginAttempts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StoreLoginAttempts'
+type AuthRepository_StoreLoginAttempts_Call struct {
+	*mock.Call
+}
+
+// StoreLoginAttempts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - attempts *auth.LoginAttempts
+func (_e *AuthRepository_Expecter) StoreLoginAttempts(ctx interface{}, attempts interface{}) *AuthRepository_StoreLoginAttempts_Call {
+	return &AuthRepository_StoreLoginAttempts_Call{Call: _e.mock.On("StoreLoginAttempts", ctx, attempts)}
+}
+
+func (_c *AuthRepository_StoreLoginAttempts_Call) Run(run func(ctx context.Context, attempts *auth.LoginAttempts)) *AuthRepository_StoreLoginAttempts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *auth.LoginAttempts
+		if args[1] != nil {
+			arg1 = args[1].(*auth.LoginAttempts)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_StoreLoginAttempts_Call) Return(err error) *AuthRepository_StoreLoginAttempts_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthRepository_StoreLoginAttempts_Call) RunAndReturn(run func(ctx context.Context, attempts *auth.LoginAttempts) error) *AuthRepository_StoreLoginAttempts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StoreMFAChallenge provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) StoreMFAChallenge(ctx context.Context, challenge *auth.MFAChallenge) error {
+	ret := _mock.Called(ctx, challenge)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreMFAChallenge")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *auth.MFAChallenge) error); ok {
+		r0 = returnFunc(ctx, challenge)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthRepository_StoreMFAChallenge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StoreMFAChallenge'
+type AuthRepository_StoreMFAChallenge_Call struct {
+	*mock.Call
+}
+
+// StoreMFAChallenge is a helper method to define mock.On call
+//   - ctx context.Context
+//   - challenge *auth.MFAChallenge
+func (_e *AuthRepository_Expecter) StoreMFAChallenge(ctx interface{}, challenge interface{}) *AuthRepository_StoreMFAChallenge_Call {
+	return &AuthRepository_StoreMFAChallenge_Call{Call: _e.mock.On("StoreMFAChallenge", ctx, challenge)}
+}
+
+func (_c *AuthRepository_StoreMFAChallenge_Call) Run(run func(ctx context.Context, challenge *auth.MFAChallenge)) *AuthRepository_StoreMFAChallenge_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *auth.MFAChallenge
+		if args[1] != nil {
+			arg1 = args[1].(*auth.MFAChallenge)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_StoreMFAChallenge_Call) Return(err error) *AuthRepository_StoreMFAChallenge_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthRepository_StoreMFAChallenge_Call) RunAndReturn(run func(ctx context.Context, challenge *auth.MFAChallenge) error) *AuthRepository_StoreMFAChallenge_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StoreMagicLinkToken provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) StoreMagicLinkToken(ctx context.Context, token *auth.MagicLinkToken) error {
+	ret := _mock.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreMagicLinkToken")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *auth.MagicLinkToken) error); ok {
+		r0 = returnFunc(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthRepository_StoreMagicLinkToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StoreMagicLinkToken'
+type AuthRepository_StoreMagicLinkToken_Call struct {
+	*mock.Call
+}
+
+// StoreMagicLinkToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token *auth.MagicLinkToken
+func (_e *AuthRepository_Expecter) StoreMagicLinkToken(ctx interface{}, token interface{}) *AuthRepository_StoreMagicLinkToken_Call {
+	return &AuthRepository_StoreMagicLinkToken_Call{Call: _e.mock.On("StoreMagicLinkToken", ctx, token)}
+}
+
+func (_c *AuthRepository_StoreMagicLinkToken_Call) Run(run func(ctx context.Context, token *auth.MagicLinkToken)) *AuthRepository_StoreMagicLinkToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *auth.MagicLinkToken
+		if args[1] != nil {
+			arg1 = args[1].(*auth.MagicLinkToken)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_StoreMagicLinkToken_Call) Return(err error) *AuthRepository_StoreMagicLinkToken_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthRepository_StoreMagicLinkToken_Call) RunAndReturn(run func(ctx context.Context, token *auth.MagicLinkToken) error) *AuthRepository_StoreMagicLinkToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StorePasswordHistory provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) StorePasswordHistory(ctx context.Context, userID string, passwordHash string, keep int) error {
+	ret := _mock.Called(ctx, userID, passwordHash, keep)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StorePasswordHistory")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, int) error); ok {
+		r0 = returnFunc(ctx, userID, passwordHash, keep)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthRepository_StorePasswordHistory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StorePasswordHistory'
+type AuthRepository_StorePasswordHistory_Call struct {
+	*mock.Call
+}
+
+// StorePasswordHistory is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID string
+//   - passwordHash string
+//   - keep int
+func (_e *AuthRepository_Expecter) StorePasswordHistory(ctx interface{}, userID interface{}, passwordHash interface{}, keep interface{}) *AuthRepository_StorePasswordHistory_Call {
+	return &AuthRepository_StorePasswordHistory_Call{Call: _e.mock.On("StorePasswordHistory", ctx, userID, passwordHash, keep)}
+}
+
+func (_c *AuthRepository_StorePasswordHistory_Call) Run(run func(ctx context.Context, userID string, passwordHash string, keep int)) *AuthRepository_StorePasswordHistory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_StorePasswordHistory_Call) Return(err error) *AuthRepository_StorePasswordHistory_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthRepository_StorePasswordHistory_Call) RunAndReturn(run func(ctx context.Context, userID string, passwordHash string, keep int) error) *AuthRepository_StorePasswordHistory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StorePersonalAccessToken provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) StorePersonalAccessToken(ctx context.Context, token *auth.PersonalAccessToken) error {
+	ret := _mock.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StorePersonalAccessToken")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *auth.PersonalAccessToken) error); ok {
+		r0 = returnFunc(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthRepository_StorePersonalAccessToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StorePersonalAccessToken'
+type AuthRepository_StorePersonalAccessToken_Call struct {
+	*mock.Call
+}
+
+// StorePersonalAccessToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token *auth.PersonalAccessToken
+func (_e *AuthRepository_Expecter) StorePersonalAccessToken(ctx interface{}, token interface{}) *AuthRepository_StorePersonalAccessToken_Call {
+	return &AuthRepository_StorePersonalAccessToken_Call{Call: _e.mock.On("StorePersonalAccessToken", ctx, token)}
+}
+
+func (_c *AuthRepository_StorePersonalAccessToken_Call) Run(run func(ctx context.Context, token *auth.PersonalAccessToken)) *AuthRepository_StorePersonalAccessToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *auth.PersonalAccessToken
+		if args[1] != nil {
+			arg1 = args[1].(*auth.PersonalAccessToken)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_StorePersonalAccessToken_Call) Return(err error) *AuthRepository_StorePersonalAccessToken_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthRepository_StorePersonalAccessToken_Call) RunAndReturn(run func(ctx context.Context, token *auth.PersonalAccessToken) error) *AuthRepository_StorePersonalAccessToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StoreRecoveryCodes provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) StoreRecoveryCodes(ctx context.Context, userID string, codes []*auth.RecoveryCode) error {
+	ret := _mock.Called(ctx, userID, codes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreRecoveryCodes")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, []*auth.RecoveryCode) error); ok {
+		r0 = returnFunc(ctx, userID, codes)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthRepository_StoreRecoveryCodes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StoreRecoveryCodes'
+type AuthRepository_StoreRecoveryCodes_Call struct {
+	*mock.Call
+}
+
+// StoreRecoveryCodes is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID string
+//   - codes []*auth.RecoveryCode
+func (_e *AuthRepository_Expecter) StoreRecoveryCodes(ctx interface{}, userID interface{}, codes interface{}) *AuthRepository_StoreRecoveryCodes_Call {
+	return &AuthRepository_StoreRecoveryCodes_Call{Call: _e.mock.On("StoreRecoveryCodes", ctx, userID, codes)}
+}
+
+func (_c *AuthRepository_StoreRecoveryCodes_Call) Run(run func(ctx context.Context, userID string, codes []*auth.RecoveryCode)) *AuthRepository_StoreRecoveryCodes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 []*auth.RecoveryCode
+		if args[2] != nil {
+			arg2 = args[2].([]*auth.RecoveryCode)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_StoreRecoveryCodes_Call) Return(err error) *AuthRepository_StoreRecoveryCodes_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthRepository_StoreRecoveryCodes_Call) RunAndReturn(run func(ctx context.Context, userID string, codes []*auth.RecoveryCode) error) *AuthRepository_StoreRecoveryCodes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StoreResetPasswordToken provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) StoreResetPasswordToken(ctx context.Context, token *auth.ResetPasswordToken) error {
+	ret := _mock.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreResetPasswordToken")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *auth.ResetPasswordToken) error); ok {
+		r0 = returnFunc(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthRepository_StoreResetPasswordToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StoreResetPasswordToken'
+type AuthRepository_StoreResetPasswordToken_Call struct {
+	*mock.Call
+}
+
+// StoreResetPasswordToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token *auth.ResetPasswordToken
+func (_e *AuthRepository_Expecter) StoreResetPasswordToken(ctx interface{}, token interface{}) *AuthRepository_StoreResetPasswordToken_Call {
+	return &AuthRepository_StoreResetPasswordToken_Call{Call: _e.mock.On("StoreResetPasswordToken", ctx, token)}
+}
+
+func (_c *AuthRepository_StoreResetPasswordToken_Call) Run(run func(ctx context.Context, token *auth.ResetPasswordToken)) *AuthRepository_StoreResetPasswordToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *auth.ResetPasswordToken
+		if args[1] != nil {
+			arg1 = args[1].(*auth.ResetPasswordToken)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_StoreResetPasswordToken_Call) Return(err error) *AuthRepository_StoreResetPasswordToken_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthRepository_StoreResetPasswordToken_Call) RunAndReturn(run func(ctx context.Context, token *auth.ResetPasswordToken) error) *AuthRepository_StoreResetPasswordToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StoreSession provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) StoreSession(ctx context.Context, session *auth.Session) error {
+	ret := _mock.Called(ctx, session)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreSession")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *auth.Session) error); ok {
+		r0 = returnFunc(ctx, session)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthRepository_StoreSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StoreSession'
+type AuthRepository_StoreSession_Call struct {
+	*mock.Call
+}
+
+// StoreSession is a helper method to define mock.On call
+//   - ctx context.Context
+//   - session *auth.Session
+func (_e *AuthRepository_Expecter) StoreSession(ctx interface{}, session interface{}) *AuthRepository_StoreSession_Call {
+	return &AuthRepository_StoreSession_Call{Call: _e.mock.On("StoreSession", ctx, session)}
+}
+
+func (_c *AuthRepository_StoreSession_Call) Run(run func(ctx context.Context, session *auth.Session)) *AuthRepository_StoreSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *auth.Session
+		if args[1] != nil {
+			arg1 = args[1].(*auth.Session)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_StoreSession_Call) Return(err error) *AuthRepository_StoreSession_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthRepository_StoreSession_Call) RunAndReturn(run func(ctx context.Context, session *auth.Session) error) *AuthRepository_StoreSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StoreSessionRevokeToken provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) StoreSessionRevokeToken(ctx context.Context, token *auth.SessionRevokeToken) error {
+	ret := _mock.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreSessionRevokeToken")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *auth.SessionRevokeToken) error); ok {
+		r0 = returnFunc(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthRepository_StoreSessionRevokeToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StoreSessionRevokeToken'
+type AuthRepository_StoreSessionRevokeToken_Call struct {
+	*mock.Call
+}
+
+// StoreSessionRevokeToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token *auth.SessionRevokeToken
+func (_e *AuthRepository_Expecter) StoreSessionRevokeToken(ctx interface{}, token interface{}) *AuthRepository_StoreSessionRevokeToken_Call {
+	return &AuthRepository_StoreSessionRevokeToken_Call{Call: _e.mock.On("StoreSessionRevokeToken", ctx, token)}
+}
+
+func (_c *AuthRepository_StoreSessionRevokeToken_Call) Run(run func(ctx context.Context, token *auth.SessionRevokeToken)) *AuthRepository_StoreSessionRevokeToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *auth.SessionRevokeToken
+		if args[1] != nil {
+			arg1 = args[1].(*auth.SessionRevokeToken)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_StoreSessionRevokeToken_Call) Return(err error) *AuthRepository_StoreSessionRevokeToken_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthRepository_StoreSessionRevokeToken_Call) RunAndReturn(run func(ctx context.Context, token *auth.SessionRevokeToken) error) *AuthRepository_StoreSessionRevokeToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StoreTOTPFactor provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) StoreTOTPFactor(ctx context.Context, factor *auth.TOTPFactor) error {
+	ret := _mock.Called(ctx, factor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreTOTPFactor")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *auth.TOTPFactor) error); ok {
+		r0 = returnFunc(ctx, factor)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthRepository_StoreTOTPFactor_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StoreTOTPFactor'
+type AuthRepository_StoreTOTPFactor_Call struct {
+	*mock.Call
+}
+
+// StoreTOTPFactor is a helper method to define mock.On call
+//   - ctx context.Context
+//   - factor *auth.TOTPFactor
+func (_e *AuthRepository_Expecter) StoreTOTPFactor(ctx interface{}, factor interface{}) *AuthRepository_StoreTOTPFactor_Call {
+	return &AuthRepository_StoreTOTPFactor_Call{Call: _e.mock.On("StoreTOTPFactor", ctx, factor)}
+}
+
+func (_c *AuthRepository_StoreTOTPFactor_Call) Run(run func(ctx context.Context, factor *auth.TOTPFactor)) *AuthRepository_StoreTOTPFactor_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *auth.TOTPFactor
+		if args[1] != nil {
+			arg1 = args[1].(*auth.TOTPFactor)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_StoreTOTPFactor_Call) Return(err error) *AuthRepository_StoreTOTPFactor_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthRepository_StoreTOTPFactor_Call) RunAndReturn(run func(ctx context.Context, factor *auth.TOTPFactor) error) *AuthRepository_StoreTOTPFactor_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StoreWebAuthnChallenge provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) StoreWebAuthnChallenge(ctx context.Context, challenge *auth.WebAuthnChallenge) error {
+	ret := _mock.Called(ctx, challenge)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreWebAuthnChallenge")
 	}
 
-	var r0 *auth.ResetPasswordToken
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*auth.ResetPasswordToken, error)); ok {
-		return returnFunc(ctx, value)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *auth.WebAuthnChallenge) error); ok {
+		r0 = returnFunc(ctx, challenge)
+	} else {
+		r0 = ret.Error(0)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *auth.ResetPasswordToken); ok {
-		r0 = returnFunc(ctx, value)
+	return r0
+}
+
+// AuthRepository_StoreWebAuthnChallenge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StoreWebAuthnChallenge'
+type AuthRepository_StoreWebAuthnChallenge_Call struct {
+	*mock.Call
+}
+
+// StoreWebAuthnChallenge is a helper method to define mock.On call
+//   - ctx context.Context
+//   - challenge *auth.WebAuthnChallenge
+func (_e *AuthRepository_Expecter) StoreWebAuthnChallenge(ctx interface{}, challenge interface{}) *AuthRepository_StoreWebAuthnChallenge_Call {
+	return &AuthRepository_StoreWebAuthnChallenge_Call{Call: _e.mock.On("StoreWebAuthnChallenge", ctx, challenge)}
+}
+
+func (_c *AuthRepository_StoreWebAuthnChallenge_Call) Run(run func(ctx context.Context, challenge *auth.WebAuthnChallenge)) *AuthRepository_StoreWebAuthnChallenge_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *auth.WebAuthnChallenge
+		if args[1] != nil {
+			arg1 = args[1].(*auth.WebAuthnChallenge)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_StoreWebAuthnChallenge_Call) Return(err error) *AuthRepository_StoreWebAuthnChallenge_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthRepository_StoreWebAuthnChallenge_Call) RunAndReturn(run func(ctx context.Context, challenge *auth.WebAuthnChallenge) error) *AuthRepository_StoreWebAuthnChallenge_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StoreWebAuthnCredential provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) StoreWebAuthnCredential(ctx context.Context, credential *auth.WebAuthnCredential) error {
+	ret := _mock.Called(ctx, credential)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreWebAuthnCredential")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *auth.WebAuthnCredential) error); ok {
+		r0 = returnFunc(ctx, credential)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*auth.ResetPasswordToken)
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthRepository_StoreWebAuthnCredential_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StoreWebAuthnCredential'
+type AuthRepository_StoreWebAuthnCredential_Call struct {
+	*mock.Call
+}
+
+// StoreWebAuthnCredential is a helper method to define mock.On call
+//   - ctx context.Context
+//   - credential *auth.WebAuthnCredential
+func (_e *AuthRepository_Expecter) StoreWebAuthnCredential(ctx interface{}, credential interface{}) *AuthRepository_StoreWebAuthnCredential_Call {
+	return &AuthRepository_StoreWebAuthnCredential_Call{Call: _e.mock.On("StoreWebAuthnCredential", ctx, credential)}
+}
+
+func (_c *AuthRepository_StoreWebAuthnCredential_Call) Run(run func(ctx context.Context, credential *auth.WebAuthnCredential)) *AuthRepository_StoreWebAuthnCredential_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *auth.WebAuthnCredential
+		if args[1] != nil {
+			arg1 = args[1].(*auth.WebAuthnCredential)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_StoreWebAuthnCredential_Call) Return(err error) *AuthRepository_StoreWebAuthnCredential_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthRepository_StoreWebAuthnCredential_Call) RunAndReturn(run func(ctx context.Context, credential *auth.WebAuthnCredential) error) *AuthRepository_StoreWebAuthnCredential_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateAccountUnlockToken provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) UpdateAccountUnlockToken(ctx context.Context, token *auth.AccountUnlockToken) error {
+	ret := _mock.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateAccountUnlockToken")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *auth.AccountUnlockToken) error); ok {
+		r0 = returnFunc(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthRepository_UpdateAccountUnlockToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateAccountUnlockToken'
+type AuthRepository_UpdateAccountUnlockToken_Call struct {
+	*mock.Call
+}
+
+// UpdateAccountUnlockToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token *auth.AccountUnlockToken
+func (_e *AuthRepository_Expecter) UpdateAccountUnlockToken(ctx interface{}, token interface{}) *AuthRepository_UpdateAccountUnlockToken_Call {
+	return &AuthRepository_UpdateAccountUnlockToken_Call{Call: _e.mock.On("UpdateAccountUnlockToken", ctx, token)}
+}
+
+func (_c *AuthRepository_UpdateAccountUnlockToken_Call) Run(run func(ctx context.Context, token *auth.AccountUnlockToken)) *AuthRepository_UpdateAccountUnlockToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *auth.AccountUnlockToken
+		if args[1] != nil {
+			arg1 = args[1].(*auth.AccountUnlockToken)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_UpdateAccountUnlockToken_Call) Return(err error) *AuthRepository_UpdateAccountUnlockToken_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthRepository_UpdateAccountUnlockToken_Call) RunAndReturn(run func(ctx context.Context, token *auth.AccountUnlockToken) error) *AuthRepository_UpdateAccountUnlockToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateEmailChangeToken provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) UpdateEmailChangeToken(ctx context.Context, token *auth.EmailChangeToken) error {
+	ret := _mock.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateEmailChangeToken")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *auth.EmailChangeToken) error); ok {
+		r0 = returnFunc(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthRepository_UpdateEmailChangeToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateEmailChangeToken'
+type AuthRepository_UpdateEmailChangeToken_Call struct {
+	*mock.Call
+}
+
+// UpdateEmailChangeToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token *auth.EmailChangeToken
+func (_e *AuthRepository_Expecter) UpdateEmailChangeToken(ctx interface{}, token interface{}) *AuthRepository_UpdateEmailChangeToken_Call {
+	return &AuthRepository_UpdateEmailChangeToken_Call{Call: _e.mock.On("UpdateEmailChangeToken", ctx, token)}
+}
+
+func (_c *AuthRepository_UpdateEmailChangeToken_Call) Run(run func(ctx context.Context, token *auth.EmailChangeToken)) *AuthRepository_UpdateEmailChangeToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *auth.EmailChangeToken
+		if args[1] != nil {
+			arg1 = args[1].(*auth.EmailChangeToken)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_UpdateEmailChangeToken_Call) Return(err error) *AuthRepository_UpdateEmailChangeToken_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthRepository_UpdateEmailChangeToken_Call) RunAndReturn(run func(ctx context.Context, token *auth.EmailChangeToken) error) *AuthRepository_UpdateEmailChangeToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateEmailVerificationToken provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) UpdateEmailVerificationToken(ctx context.Context, token *auth.EmailVerificationToken) error {
+	ret := _mock.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateEmailVerificationToken")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *auth.EmailVerificationToken) error); ok {
+		r0 = returnFunc(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthRepository_UpdateEmailVerificationToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateEmailVerificationToken'
+type AuthRepository_UpdateEmailVerificationToken_Call struct {
+	*mock.Call
+}
+
+// UpdateEmailVerificationToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token *auth.EmailVerificationToken
+func (_e *AuthRepository_Expecter) UpdateEmailVerificationToken(ctx interface{}, token interface{}) *AuthRepository_UpdateEmailVerificationToken_Call {
+	return &AuthRepository_UpdateEmailVerificationToken_Call{Call: _e.mock.On("UpdateEmailVerificationToken", ctx, token)}
+}
+
+func (_c *AuthRepository_UpdateEmailVerificationToken_Call) Run(run func(ctx context.Context, token *auth.EmailVerificationToken)) *AuthRepository_UpdateEmailVerificationToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *auth.EmailVerificationToken
+		if args[1] != nil {
+			arg1 = args[1].(*auth.EmailVerificationToken)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_UpdateEmailVerificationToken_Call) Return(err error) *AuthRepository_UpdateEmailVerificationToken_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthRepository_UpdateEmailVerificationToken_Call) RunAndReturn(run func(ctx context.Context, token *auth.EmailVerificationToken) error) *AuthRepository_UpdateEmailVerificationToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateKnownDevice provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) UpdateKnownDevice(ctx context.Context, device *auth.KnownDevice) error {
+	ret := _mock.Called(ctx, device)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateKnownDevice")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *auth.KnownDevice) error); ok {
+		r0 = returnFunc(ctx, device)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthRepository_UpdateKnownDevice_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateKnownDevice'
+type AuthRepository_UpdateKnownDevice_Call struct {
+	*mock.Call
+}
+
+// UpdateKnownDevice is a helper method to define mock.On call
+//   - ctx context.Context
+//   - device *auth.KnownDevice
+func (_e *AuthRepository_Expecter) UpdateKnownDevice(ctx interface{}, device interface{}) *AuthRepository_UpdateKnownDevice_Call {
+	return &AuthRepository_UpdateKnownDevice_Call{Call: _e.mock.On("UpdateKnownDevice", ctx, device)}
+}
+
+func (_c *AuthRepository_UpdateKnownDevice_Call) Run(run func(ctx context.Context, device *auth.KnownDevice)) *AuthRepository_UpdateKnownDevice_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *auth.KnownDevice
+		if args[1] != nil {
+			arg1 = args[1].(*auth.KnownDevice)
 		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_UpdateKnownDevice_Call) Return(err error) *AuthRepository_UpdateKnownDevice_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthRepository_UpdateKnownDevice_Call) RunAndReturn(run func(ctx context.Context, device *auth.KnownDevice) error) *AuthRepository_UpdateKnownDevice_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateMFAChallenge provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) UpdateMFAChallenge(ctx context.Context, challenge *auth.MFAChallenge) error {
+	ret := _mock.Called(ctx, challenge)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateMFAChallenge")
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = returnFunc(ctx, value)
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *auth.MFAChallenge) error); ok {
+		r0 = returnFunc(ctx, challenge)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// AuthRepository_GetResetPasswordToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetResetPasswordToken'
-type AuthRepository_GetResetPasswordToken_Call struct {
+// AuthRepository_UpdateMFAChallenge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateMFAChallenge'
+type AuthRepository_UpdateMFAChallenge_Call struct {
 	*mock.Call
 }
 
-// GetResetPasswordToken is a helper method to define mock.On call
+// UpdateMFAChallenge is a helper method to define mock.On call
 //   - ctx context.Context
-//   - value string
-func (_e *AuthRepository_Expecter) GetResetPasswordToken(ctx interface{}, value interface{}) *AuthRepository_GetResetPasswordToken_Call {
-	return &AuthRepository_GetResetPasswordToken_Call{Call: _e.mock.On("GetResetPasswordToken", ctx, value)}
+//   - challenge *auth.MFAChallenge
+func (_e *AuthRepository_Expecter) UpdateMFAChallenge(ctx interface{}, challenge interface{}) *AuthRepository_UpdateMFAChallenge_Call {
+	return &AuthRepository_UpdateMFAChallenge_Call{Call: _e.mock.On("UpdateMFAChallenge", ctx, challenge)}
 }
 
-func (_c *AuthRepository_GetResetPasswordToken_Call) Run(run func(ctx context.Context, value string)) *AuthRepository_GetResetPasswordToken_Call {
+func (_c *AuthRepository_UpdateMFAChallenge_Call) Run(run func(ctx context.Context, challenge *auth.MFAChallenge)) *AuthRepository_UpdateMFAChallenge_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 string
+		var arg1 *auth.MFAChallenge
 		if args[1] != nil {
-			arg1 = args[1].(string)
+			arg1 = args[1].(*auth.MFAChallenge)
 		}
 		run(
 			arg0,
@@ -96,65 +3147,54 @@ func (_c *AuthRepository_GetResetPasswordToken_Call) Run(run func(ctx context.Co
 	return _c
 }
 
-func (_c *AuthRepository_GetResetPasswordToken_Call) Return(resetPasswordToken *auth.ResetPasswordToken, err error) *AuthRepository_GetResetPasswordToken_Call {
-	_c.Call.Return(resetPasswordToken, err)
+func (_c *AuthRepository_UpdateMFAChallenge_Call) Return(err error) *AuthRepository_UpdateMFAChallenge_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *AuthRepository_GetResetPasswordToken_Call) RunAndReturn(run func(ctx context.Context, value string) (*auth.ResetPasswordToken, error)) *AuthRepository_GetResetPasswordToken_Call {
+func (_c *AuthRepository_UpdateMFAChallenge_Call) RunAndReturn(run func(ctx context.Context, challenge *auth.MFAChallenge) error) *AuthRepository_UpdateMFAChallenge_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetSession provides a mock function for the type AuthRepository
-func (_mock *AuthRepository) GetSession(ctx context.Context, sessionID string) (*auth.Session, error) {
-	ret := _mock.Called(ctx, sessionID)
+// UpdateMagicLinkToken provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) UpdateMagicLinkToken(ctx context.Context, token *auth.MagicLinkToken) error {
+	ret := _mock.Called(ctx, token)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetSession")
+		panic("no return value specified for UpdateMagicLinkToken")
 	}
 
-	var r0 *auth.Session
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*auth.Session, error)); ok {
-		return returnFunc(ctx, sessionID)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *auth.Session); ok {
-		r0 = returnFunc(ctx, sessionID)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*auth.Session)
-		}
-	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = returnFunc(ctx, sessionID)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *auth.MagicLinkToken) error); ok {
+		r0 = returnFunc(ctx, token)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// AuthRepository_GetSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSession'
-type AuthRepository_GetSession_Call struct {
+// AuthRepository_UpdateMagicLinkToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateMagicLinkToken'
+type AuthRepository_UpdateMagicLinkToken_Call struct {
 	*mock.Call
 }
 
-// GetSession is a helper method to define mock.On call
+// UpdateMagicLinkToken is a helper method to define mock.On call
 //   - ctx context.Context
-//   - sessionID string
-func (_e *AuthRepository_Expecter) GetSession(ctx interface{}, sessionID interface{}) *AuthRepository_GetSession_Call {
-	return &AuthRepository_GetSession_Call{Call: _e.mock.On("GetSession", ctx, sessionID)}
+//   - token *auth.MagicLinkToken
+func (_e *AuthRepository_Expecter) UpdateMagicLinkToken(ctx interface{}, token interface{}) *AuthRepository_UpdateMagicLinkToken_Call {
+	return &AuthRepository_UpdateMagicLinkToken_Call{Call: _e.mock.On("UpdateMagicLinkToken", ctx, token)}
 }
 
-func (_c *AuthRepository_GetSession_Call) Run(run func(ctx context.Context, sessionID string)) *AuthRepository_GetSession_Call {
+func (_c *AuthRepository_UpdateMagicLinkToken_Call) Run(run func(ctx context.Context, token *auth.MagicLinkToken)) *AuthRepository_UpdateMagicLinkToken_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 string
+		var arg1 *auth.MagicLinkToken
 		if args[1] != nil {
-			arg1 = args[1].(string)
+			arg1 = args[1].(*auth.MagicLinkToken)
 		}
 		run(
 			arg0,
@@ -164,26 +3204,26 @@ func (_c *AuthRepository_GetSession_Call) Run(run func(ctx context.Context, sess
 	return _c
 }
 
-func (_c *AuthRepository_GetSession_Call) Return(session *auth.Session, err error) *AuthRepository_GetSession_Call {
-	_c.Call.Return(session, err)
+func (_c *AuthRepository_UpdateMagicLinkToken_Call) Return(err error) *AuthRepository_UpdateMagicLinkToken_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *AuthRepository_GetSession_Call) RunAndReturn(run func(ctx context.Context, sessionID string) (*auth.Session, error)) *AuthRepository_GetSession_Call {
+func (_c *AuthRepository_UpdateMagicLinkToken_Call) RunAndReturn(run func(ctx context.Context, token *auth.MagicLinkToken) error) *AuthRepository_UpdateMagicLinkToken_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// StoreResetPasswordToken provides a mock function for the type AuthRepository
-func (_mock *AuthRepository) StoreResetPasswordToken(ctx context.Context, token *auth.ResetPasswordToken) error {
+// UpdatePersonalAccessToken provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) UpdatePersonalAccessToken(ctx context.Context, token *auth.PersonalAccessToken) error {
 	ret := _mock.Called(ctx, token)
 
 	if len(ret) == 0 {
-		panic("no return value specified for StoreResetPasswordToken")
+		panic("no return value specified for UpdatePersonalAccessToken")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *auth.ResetPasswordToken) error); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *auth.PersonalAccessToken) error); ok {
 		r0 = returnFunc(ctx, token)
 	} else {
 		r0 = ret.Error(0)
@@ -191,27 +3231,27 @@ func (_mock *AuthRepository) StoreResetPasswordToken(ctx context.Context, token
 	return r0
 }
 
-// AuthRepository_StoreResetPasswordToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StoreResetPasswordToken'
-type AuthRepository_StoreResetPasswordToken_Call struct {
+// AuthRepository_UpdatePersonalAccessToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdatePersonalAccessToken'
+type AuthRepository_UpdatePersonalAccessToken_Call struct {
 	*mock.Call
 }
 
-// StoreResetPasswordToken is a helper method to define mock.On call
+// UpdatePersonalAccessToken is a helper method to define mock.On call
 //   - ctx context.Context
-//   - token *auth.ResetPasswordToken
-func (_e *AuthRepository_Expecter) StoreResetPasswordToken(ctx interface{}, token interface{}) *AuthRepository_StoreResetPasswordToken_Call {
-	return &AuthRepository_StoreResetPasswordToken_Call{Call: _e.mock.On("StoreResetPasswordToken", ctx, token)}
+//   - token *auth.PersonalAccessToken
+func (_e *AuthRepository_Expecter) UpdatePersonalAccessToken(ctx interface{}, token interface{}) *AuthRepository_UpdatePersonalAccessToken_Call {
+	return &AuthRepository_UpdatePersonalAccessToken_Call{Call: _e.mock.On("UpdatePersonalAccessToken", ctx, token)}
 }
 
-func (_c *AuthRepository_StoreResetPasswordToken_Call) Run(run func(ctx context.Context, token *auth.ResetPasswordToken)) *AuthRepository_StoreResetPasswordToken_Call {
+func (_c *AuthRepository_UpdatePersonalAccessToken_Call) Run(run func(ctx context.Context, token *auth.PersonalAccessToken)) *AuthRepository_UpdatePersonalAccessToken_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *auth.ResetPasswordToken
+		var arg1 *auth.PersonalAccessToken
 		if args[1] != nil {
-			arg1 = args[1].(*auth.ResetPasswordToken)
+			arg1 = args[1].(*auth.PersonalAccessToken)
 		}
 		run(
 			arg0,
@@ -221,54 +3261,54 @@ func (_c *AuthRepository_StoreResetPasswordToken_Call) Run(run func(ctx context.
 	return _c
 }
 
-func (_c *AuthRepository_StoreResetPasswordToken_Call) Return(err error) *AuthRepository_StoreResetPasswordToken_Call {
+func (_c *AuthRepository_UpdatePersonalAccessToken_Call) Return(err error) *AuthRepository_UpdatePersonalAccessToken_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *AuthRepository_StoreResetPasswordToken_Call) RunAndReturn(run func(ctx context.Context, token *auth.ResetPasswordToken) error) *AuthRepository_StoreResetPasswordToken_Call {
+func (_c *AuthRepository_UpdatePersonalAccessToken_Call) RunAndReturn(run func(ctx context.Context, token *auth.PersonalAccessToken) error) *AuthRepository_UpdatePersonalAccessToken_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// StoreSession provides a mock function for the type AuthRepository
-func (_mock *AuthRepository) StoreSession(ctx context.Context, session *auth.Session) error {
-	ret := _mock.Called(ctx, session)
+// UpdateRecoveryCode provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) UpdateRecoveryCode(ctx context.Context, code *auth.RecoveryCode) error {
+	ret := _mock.Called(ctx, code)
 
 	if len(ret) == 0 {
-		panic("no return value specified for StoreSession")
+		panic("no return value specified for UpdateRecoveryCode")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *auth.Session) error); ok {
-		r0 = returnFunc(ctx, session)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *auth.RecoveryCode) error); ok {
+		r0 = returnFunc(ctx, code)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// AuthRepository_StoreSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StoreSession'
-type AuthRepository_StoreSession_Call struct {
+// AuthRepository_UpdateRecoveryCode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateRecoveryCode'
+type AuthRepository_UpdateRecoveryCode_Call struct {
 	*mock.Call
 }
 
-// StoreSession is a helper method to define mock.On call
+// UpdateRecoveryCode is a helper method to define mock.On call
 //   - ctx context.Context
-//   - session *auth.Session
-func (_e *AuthRepository_Expecter) StoreSession(ctx interface{}, session interface{}) *AuthRepository_StoreSession_Call {
-	return &AuthRepository_StoreSession_Call{Call: _e.mock.On("StoreSession", ctx, session)}
+//   - code *auth.RecoveryCode
+func (_e *AuthRepository_Expecter) UpdateRecoveryCode(ctx interface{}, code interface{}) *AuthRepository_UpdateRecoveryCode_Call {
+	return &AuthRepository_UpdateRecoveryCode_Call{Call: _e.mock.On("UpdateRecoveryCode", ctx, code)}
 }
 
-func (_c *AuthRepository_StoreSession_Call) Run(run func(ctx context.Context, session *auth.Session)) *AuthRepository_StoreSession_Call {
+func (_c *AuthRepository_UpdateRecoveryCode_Call) Run(run func(ctx context.Context, code *auth.RecoveryCode)) *AuthRepository_UpdateRecoveryCode_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *auth.Session
+		var arg1 *auth.RecoveryCode
 		if args[1] != nil {
-			arg1 = args[1].(*auth.Session)
+			arg1 = args[1].(*auth.RecoveryCode)
 		}
 		run(
 			arg0,
@@ -278,12 +3318,12 @@ func (_c *AuthRepository_StoreSession_Call) Run(run func(ctx context.Context, se
 	return _c
 }
 
-func (_c *AuthRepository_StoreSession_Call) Return(err error) *AuthRepository_StoreSession_Call {
+func (_c *AuthRepository_UpdateRecoveryCode_Call) Return(err error) *AuthRepository_UpdateRecoveryCode_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *AuthRepository_StoreSession_Call) RunAndReturn(run func(ctx context.Context, session *auth.Session) error) *AuthRepository_StoreSession_Call {
+func (_c *AuthRepository_UpdateRecoveryCode_Call) RunAndReturn(run func(ctx context.Context, code *auth.RecoveryCode) error) *AuthRepository_UpdateRecoveryCode_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -401,3 +3441,231 @@ func (_c *AuthRepository_UpdateSession_Call) RunAndReturn(run func(ctx context.C
 	_c.Call.Return(run)
 	return _c
 }
+
+// UpdateSessionRevokeToken provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) UpdateSessionRevokeToken(ctx context.Context, token *auth.SessionRevokeToken) error {
+	ret := _mock.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateSessionRevokeToken")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *auth.SessionRevokeToken) error); ok {
+		r0 = returnFunc(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthRepository_UpdateSessionRevokeToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateSessionRevokeToken'
+type AuthRepository_UpdateSessionRevokeToken_Call struct {
+	*mock.Call
+}
+
+// UpdateSessionRevokeToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token *auth.SessionRevokeToken
+func (_e *AuthRepository_Expecter) UpdateSessionRevokeToken(ctx interface{}, token interface{}) *AuthRepository_UpdateSessionRevokeToken_Call {
+	return &AuthRepository_UpdateSessionRevokeToken_Call{Call: _e.mock.On("UpdateSessionRevokeToken", ctx, token)}
+}
+
+func (_c *AuthRepository_UpdateSessionRevokeToken_Call) Run(run func(ctx context.Context, token *auth.SessionRevokeToken)) *AuthRepository_UpdateSessionRevokeToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *auth.SessionRevokeToken
+		if args[1] != nil {
+			arg1 = args[1].(*auth.SessionRevokeToken)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_UpdateSessionRevokeToken_Call) Return(err error) *AuthRepository_UpdateSessionRevokeToken_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthRepository_UpdateSessionRevokeToken_Call) RunAndReturn(run func(ctx context.Context, token *auth.SessionRevokeToken) error) *AuthRepository_UpdateSessionRevokeToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateTOTPFactor provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) UpdateTOTPFactor(ctx context.Context, factor *auth.TOTPFactor) error {
+	ret := _mock.Called(ctx, factor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTOTPFactor")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *auth.TOTPFactor) error); ok {
+		r0 = returnFunc(ctx, factor)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthRepository_UpdateTOTPFactor_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateTOTPFactor'
+type AuthRepository_UpdateTOTPFactor_Call struct {
+	*mock.Call
+}
+
+// UpdateTOTPFactor is a helper method to define mock.On call
+//   - ctx context.Context
+//   - factor *auth.TOTPFactor
+func (_e *AuthRepository_Expecter) UpdateTOTPFactor(ctx interface{}, factor interface{}) *AuthRepository_UpdateTOTPFactor_Call {
+	return &AuthRepository_UpdateTOTPFactor_Call{Call: _e.mock.On("UpdateTOTPFactor", ctx, factor)}
+}
+
+func (_c *AuthRepository_UpdateTOTPFactor_Call) Run(run func(ctx context.Context, factor *auth.TOTPFactor)) *AuthRepository_UpdateTOTPFactor_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *auth.TOTPFactor
+		if args[1] != nil {
+			arg1 = args[1].(*auth.TOTPFactor)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_UpdateTOTPFactor_Call) Return(err error) *AuthRepository_UpdateTOTPFactor_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthRepository_UpdateTOTPFactor_Call) RunAndReturn(run func(ctx context.Context, factor *auth.TOTPFactor) error) *AuthRepository_UpdateTOTPFactor_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateWebAuthnChallenge provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) UpdateWebAuthnChallenge(ctx context.Context, challenge *auth.WebAuthnChallenge) error {
+	ret := _mock.Called(ctx, challenge)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateWebAuthnChallenge")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *auth.WebAuthnChallenge) error); ok {
+		r0 = returnFunc(ctx, challenge)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthRepository_UpdateWebAuthnChallenge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateWebAuthnChallenge'
+type AuthRepository_UpdateWebAuthnChallenge_Call struct {
+	*mock.Call
+}
+
+// UpdateWebAuthnChallenge is a helper method to define mock.On call
+//   - ctx context.Context
+//   - challenge *auth.WebAuthnChallenge
+func (_e *AuthRepository_Expecter) UpdateWebAuthnChallenge(ctx interface{}, challenge interface{}) *AuthRepository_UpdateWebAuthnChallenge_Call {
+	return &AuthRepository_UpdateWebAuthnChallenge_Call{Call: _e.mock.On("UpdateWebAuthnChallenge", ctx, challenge)}
+}
+
+func (_c *AuthRepository_UpdateWebAuthnChallenge_Call) Run(run func(ctx context.Context, challenge *auth.WebAuthnChallenge)) *AuthRepository_UpdateWebAuthnChallenge_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *auth.WebAuthnChallenge
+		if args[1] != nil {
+			arg1 = args[1].(*auth.WebAuthnChallenge)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_UpdateWebAuthnChallenge_Call) Return(err error) *AuthRepository_UpdateWebAuthnChallenge_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthRepository_UpdateWebAuthnChallenge_Call) RunAndReturn(run func(ctx context.Context, challenge *auth.WebAuthnChallenge) error) *AuthRepository_UpdateWebAuthnChallenge_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateWebAuthnCredential provides a mock function for the type AuthRepository
+func (_mock *AuthRepository) UpdateWebAuthnCredential(ctx context.Context, credential *auth.WebAuthnCredential) error {
+	ret := _mock.Called(ctx, credential)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateWebAuthnCredential")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *auth.WebAuthnCredential) error); ok {
+		r0 = returnFunc(ctx, credential)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// AuthRepository_UpdateWebAuthnCredential_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateWebAuthnCredential'
+type AuthRepository_UpdateWebAuthnCredential_Call struct {
+	*mock.Call
+}
+
+// UpdateWebAuthnCredential is a helper method to define mock.On call
+//   - ctx context.Context
+//   - credential *auth.WebAuthnCredential
+func (_e *AuthRepository_Expecter) UpdateWebAuthnCredential(ctx interface{}, credential interface{}) *AuthRepository_UpdateWebAuthnCredential_Call {
+	return &AuthRepository_UpdateWebAuthnCredential_Call{Call: _e.mock.On("UpdateWebAuthnCredential", ctx, credential)}
+}
+
+func (_c *AuthRepository_UpdateWebAuthnCredential_Call) Run(run func(ctx context.Context, credential *auth.WebAuthnCredential)) *AuthRepository_UpdateWebAuthnCredential_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *auth.WebAuthnCredential
+		if args[1] != nil {
+			arg1 = args[1].(*auth.WebAuthnCredential)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *AuthRepository_UpdateWebAuthnCredential_Call) Return(err error) *AuthRepository_UpdateWebAuthnCredential_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *AuthRepository_UpdateWebAuthnCredential_Call) RunAndReturn(run func(ctx context.Context, credential *auth.WebAuthnCredential) error) *AuthRepository_UpdateWebAuthnCredential_Call {
+	_c.Call.Return(run)
+	return _c
+}
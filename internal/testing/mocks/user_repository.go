@@ -6,6 +6,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/prawirdani/golang-restapi/internal/domain/user"
 	mock "github.com/stretchr/testify/mock"
@@ -38,6 +39,120 @@ func (_m *UserRepository) EXPECT() *UserRepository_Expecter {
 	return &UserRepository_Expecter{mock: &_m.Mock}
 }
 
+// Anonymize provides a mock function for the type UserRepository
+func (_mock *UserRepository) Anonymize(ctx context.Context, u *user.User) error {
+	ret := _mock.Called(ctx, u)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Anonymize")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *user.User) error); ok {
+		r0 = returnFunc(ctx, u)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// UserRepository_Anonymize_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Anonymize'
+type UserRepository_Anonymize_Call struct {
+	*mock.Call
+}
+
+// Anonymize is a helper method to define mock.On call
+//   - ctx context.Context
+//   - u *user.User
+func (_e *UserRepository_Expecter) Anonymize(ctx interface{}, u interface{}) *UserRepository_Anonymize_Call {
+	return &UserRepository_Anonymize_Call{Call: _e.mock.On("Anonymize", ctx, u)}
+}
+
+func (_c *UserRepository_Anonymize_Call) Run(run func(ctx context.Context, u *user.User)) *UserRepository_Anonymize_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *user.User
+		if args[1] != nil {
+			arg1 = args[1].(*user.User)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *UserRepository_Anonymize_Call) Return(err error) *UserRepository_Anonymize_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *UserRepository_Anonymize_Call) RunAndReturn(run func(ctx context.Context, u *user.User) error) *UserRepository_Anonymize_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Delete provides a mock function for the type UserRepository
+func (_mock *UserRepository) Delete(ctx context.Context, u *user.User) error {
+	ret := _mock.Called(ctx, u)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *user.User) error); ok {
+		r0 = returnFunc(ctx, u)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// UserRepository_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type UserRepository_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - u *user.User
+func (_e *UserRepository_Expecter) Delete(ctx interface{}, u interface{}) *UserRepository_Delete_Call {
+	return &UserRepository_Delete_Call{Call: _e.mock.On("Delete", ctx, u)}
+}
+
+func (_c *UserRepository_Delete_Call) Run(run func(ctx context.Context, u *user.User)) *UserRepository_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *user.User
+		if args[1] != nil {
+			arg1 = args[1].(*user.User)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *UserRepository_Delete_Call) Return(err error) *UserRepository_Delete_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *UserRepository_Delete_Call) RunAndReturn(run func(ctx context.Context, u *user.User) error) *UserRepository_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetByEmail provides a mock function for the type UserRepository
 func (_mock *UserRepository) GetByEmail(ctx context.Context, email string) (*user.User, error) {
 	ret := _mock.Called(ctx, email)
@@ -174,6 +289,211 @@ func (_c *UserRepository_GetByID_Call) RunAndReturn(run func(ctx context.Context
 	return _c
 }
 
+// HardDelete provides a mock function for the type UserRepository
+func (_mock *UserRepository) HardDelete(ctx context.Context, userID string) error {
+	ret := _mock.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HardDelete")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// UserRepository_HardDelete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'HardDelete'
+type UserRepository_HardDelete_Call struct {
+	*mock.Call
+}
+
+// HardDelete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID string
+func (_e *UserRepository_Expecter) HardDelete(ctx interface{}, userID interface{}) *UserRepository_HardDelete_Call {
+	return &UserRepository_HardDelete_Call{Call: _e.mock.On("HardDelete", ctx, userID)}
+}
+
+func (_c *UserRepository_HardDelete_Call) Run(run func(ctx context.Context, userID string)) *UserRepository_HardDelete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *UserRepository_HardDelete_Call) Return(err error) *UserRepository_HardDelete_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *UserRepository_HardDelete_Call) RunAndReturn(run func(ctx context.Context, userID string) error) *UserRepository_HardDelete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function for the type UserRepository
+func (_mock *UserRepository) List(ctx context.Context, filter user.ListFilter) ([]*user.User, int, error) {
+	ret := _mock.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []*user.User
+	var r1 int
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, user.ListFilter) ([]*user.User, int, error)); ok {
+		return returnFunc(ctx, filter)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, user.ListFilter) []*user.User); ok {
+		r0 = returnFunc(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*user.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, user.ListFilter) int); ok {
+		r1 = returnFunc(ctx, filter)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, user.ListFilter) error); ok {
+		r2 = returnFunc(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// UserRepository_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type UserRepository_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter user.ListFilter
+func (_e *UserRepository_Expecter) List(ctx interface{}, filter interface{}) *UserRepository_List_Call {
+	return &UserRepository_List_Call{Call: _e.mock.On("List", ctx, filter)}
+}
+
+func (_c *UserRepository_List_Call) Run(run func(ctx context.Context, filter user.ListFilter)) *UserRepository_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 user.ListFilter
+		if args[1] != nil {
+			arg1 = args[1].(user.ListFilter)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *UserRepository_List_Call) Return(users []*user.User, n int, err error) *UserRepository_List_Call {
+	_c.Call.Return(users, n, err)
+	return _c
+}
+
+func (_c *UserRepository_List_Call) RunAndReturn(run func(ctx context.Context, filter user.ListFilter) ([]*user.User, int, error)) *UserRepository_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListDeleted provides a mock function for the type UserRepository
+func (_mock *UserRepository) ListDeleted(ctx context.Context, deletedBefore time.Time, limit int) ([]*user.User, error) {
+	ret := _mock.Called(ctx, deletedBefore, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListDeleted")
+	}
+
+	var r0 []*user.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, int) ([]*user.User, error)); ok {
+		return returnFunc(ctx, deletedBefore, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, int) []*user.User); ok {
+		r0 = returnFunc(ctx, deletedBefore, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*user.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = returnFunc(ctx, deletedBefore, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// UserRepository_ListDeleted_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListDeleted'
+type UserRepository_ListDeleted_Call struct {
+	*mock.Call
+}
+
+// ListDeleted is a helper method to define mock.On call
+//   - ctx context.Context
+//   - deletedBefore time.Time
+//   - limit int
+func (_e *UserRepository_Expecter) ListDeleted(ctx interface{}, deletedBefore interface{}, limit interface{}) *UserRepository_ListDeleted_Call {
+	return &UserRepository_ListDeleted_Call{Call: _e.mock.On("ListDeleted", ctx, deletedBefore, limit)}
+}
+
+func (_c *UserRepository_ListDeleted_Call) Run(run func(ctx context.Context, deletedBefore time.Time, limit int)) *UserRepository_ListDeleted_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 time.Time
+		if args[1] != nil {
+			arg1 = args[1].(time.Time)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *UserRepository_ListDeleted_Call) Return(users []*user.User, err error) *UserRepository_ListDeleted_Call {
+	_c.Call.Return(users, err)
+	return _c
+}
+
+func (_c *UserRepository_ListDeleted_Call) RunAndReturn(run func(ctx context.Context, deletedBefore time.Time, limit int) ([]*user.User, error)) *UserRepository_ListDeleted_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Store provides a mock function for the type UserRepository
 func (_mock *UserRepository) Store(ctx context.Context, u *user.User) error {
 	ret := _mock.Called(ctx, u)
@@ -49,3 +49,209 @@ func (mc *AuthMessageConsumer) EmailResetPasswordHandler(
 
 	return nil
 }
+
+func (mc *AuthMessageConsumer) EmailVerificationHandler(
+	ctx context.Context,
+	d amqp.Delivery,
+) error {
+	msg, err := decodeJsonBody[auth.VerificationEmailMessage](d.Body)
+	if err != nil {
+		return fmt.Errorf("failed to decode body: %w", err)
+	}
+
+	// Execute template
+	var buf bytes.Buffer
+	if err := mc.mailer.Templates.EmailVerification.Execute(&buf, map[string]any{
+		"Name":    msg.Name,
+		"Minutes": msg.Expiry.Minutes(),
+		"URL":     msg.VerificationURL,
+	}); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	if err := mc.mailer.Send(
+		mailer.HeaderParams{To: []string{msg.To}, Subject: "Email Verification"},
+		buf,
+	); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
+
+func (mc *AuthMessageConsumer) EmailChangeConfirmationHandler(
+	ctx context.Context,
+	d amqp.Delivery,
+) error {
+	msg, err := decodeJsonBody[auth.EmailChangeConfirmationMessage](d.Body)
+	if err != nil {
+		return fmt.Errorf("failed to decode body: %w", err)
+	}
+
+	// Execute template
+	var buf bytes.Buffer
+	if err := mc.mailer.Templates.EmailChangeConfirmation.Execute(&buf, map[string]any{
+		"Name":    msg.Name,
+		"Minutes": msg.Expiry.Minutes(),
+		"URL":     msg.ConfirmationURL,
+	}); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	if err := mc.mailer.Send(
+		mailer.HeaderParams{To: []string{msg.To}, Subject: "Confirm Email Change"},
+		buf,
+	); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
+
+func (mc *AuthMessageConsumer) EmailChangeNotificationHandler(
+	ctx context.Context,
+	d amqp.Delivery,
+) error {
+	msg, err := decodeJsonBody[auth.EmailChangeNotificationMessage](d.Body)
+	if err != nil {
+		return fmt.Errorf("failed to decode body: %w", err)
+	}
+
+	// Execute template
+	var buf bytes.Buffer
+	if err := mc.mailer.Templates.EmailChangeNotification.Execute(&buf, map[string]any{
+		"Name":     msg.Name,
+		"NewEmail": msg.NewEmail,
+	}); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	if err := mc.mailer.Send(
+		mailer.HeaderParams{To: []string{msg.To}, Subject: "Email Change Requested"},
+		buf,
+	); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
+
+func (mc *AuthMessageConsumer) AccountLockedEmailHandler(
+	ctx context.Context,
+	d amqp.Delivery,
+) error {
+	msg, err := decodeJsonBody[auth.AccountLockedEmailMessage](d.Body)
+	if err != nil {
+		return fmt.Errorf("failed to decode body: %w", err)
+	}
+
+	// Execute template
+	var buf bytes.Buffer
+	if err := mc.mailer.Templates.AccountLocked.Execute(&buf, map[string]any{
+		"Name":        msg.Name,
+		"IPAddress":   msg.IPAddress,
+		"LockedUntil": msg.LockedUntil.UTC().Format("02 Jan 2006 15:04 MST"),
+		"Minutes":     msg.Expiry.Minutes(),
+		"URL":         msg.UnlockURL,
+	}); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	if err := mc.mailer.Send(
+		mailer.HeaderParams{To: []string{msg.To}, Subject: "Account Locked"},
+		buf,
+	); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
+
+func (mc *AuthMessageConsumer) MagicLinkEmailHandler(
+	ctx context.Context,
+	d amqp.Delivery,
+) error {
+	msg, err := decodeJsonBody[auth.MagicLinkEmailMessage](d.Body)
+	if err != nil {
+		return fmt.Errorf("failed to decode body: %w", err)
+	}
+
+	// Execute template
+	var buf bytes.Buffer
+	if err := mc.mailer.Templates.MagicLink.Execute(&buf, map[string]any{
+		"Name":    msg.Name,
+		"Minutes": msg.Expiry.Minutes(),
+		"URL":     msg.LoginURL,
+	}); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	if err := mc.mailer.Send(
+		mailer.HeaderParams{To: []string{msg.To}, Subject: "Login Link"},
+		buf,
+	); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
+
+func (mc *AuthMessageConsumer) AccountExistsEmailHandler(
+	ctx context.Context,
+	d amqp.Delivery,
+) error {
+	msg, err := decodeJsonBody[auth.AccountExistsEmailMessage](d.Body)
+	if err != nil {
+		return fmt.Errorf("failed to decode body: %w", err)
+	}
+
+	// Execute template
+	var buf bytes.Buffer
+	if err := mc.mailer.Templates.AccountExists.Execute(&buf, map[string]any{
+		"Name":  msg.Name,
+		"Email": msg.To,
+	}); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	if err := mc.mailer.Send(
+		mailer.HeaderParams{To: []string{msg.To}, Subject: "Registration Attempt"},
+		buf,
+	); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
+
+func (mc *AuthMessageConsumer) NewDeviceLoginEmailHandler(
+	ctx context.Context,
+	d amqp.Delivery,
+) error {
+	msg, err := decodeJsonBody[auth.NewDeviceLoginEmailMessage](d.Body)
+	if err != nil {
+		return fmt.Errorf("failed to decode body: %w", err)
+	}
+
+	// Execute template
+	var buf bytes.Buffer
+	if err := mc.mailer.Templates.NewDeviceLogin.Execute(&buf, map[string]any{
+		"Name":      msg.Name,
+		"UserAgent": msg.UserAgent,
+		"IPAddress": msg.IPAddress,
+		"LoginAt":   msg.LoginAt.UTC().Format("02 Jan 2006 15:04 MST"),
+		"Minutes":   msg.Expiry.Minutes(),
+		"URL":       msg.RevokeURL,
+	}); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	if err := mc.mailer.Send(
+		mailer.HeaderParams{To: []string{msg.To}, Subject: "New Login to Your Account"},
+		buf,
+	); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package consumer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"github.com/prawirdani/golang-restapi/internal/domain/organization"
+	"github.com/prawirdani/golang-restapi/pkg/mailer"
+)
+
+type OrganizationMessageConsumer struct {
+	mailer *mailer.Mailer
+}
+
+func NewOrganizationMessageConsumer(mailer *mailer.Mailer) *OrganizationMessageConsumer {
+	return &OrganizationMessageConsumer{
+		mailer: mailer,
+	}
+}
+
+func (mc *OrganizationMessageConsumer) InvitationEmailHandler(
+	ctx context.Context,
+	d amqp.Delivery,
+) error {
+	msg, err := decodeJsonBody[organization.InvitationEmailMessage](d.Body)
+	if err != nil {
+		return fmt.Errorf("failed to decode body: %w", err)
+	}
+
+	// Execute template
+	var buf bytes.Buffer
+	if err := mc.mailer.Templates.OrganizationInvitation.Execute(&buf, map[string]any{
+		"OrganizationName": msg.OrganizationName,
+		"InviterName":      msg.InviterName,
+		"Role":             msg.Role,
+		"Days":             math.Ceil(msg.Expiry.Hours() / 24),
+		"URL":              msg.InvitationURL,
+	}); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	if err := mc.mailer.Send(
+		mailer.HeaderParams{To: []string{msg.To}, Subject: "Organization Invitation"},
+		buf,
+	); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
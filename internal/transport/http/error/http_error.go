@@ -77,10 +77,12 @@ func FromError(err error) *Error {
 
 // domainErrStatusCodes maps domain error into http status codes
 var domainErrStatusCodes = map[domain.ErrorKind]int{
-	domain.ErrorKindUnauthorized: http.StatusUnauthorized,        // 401
-	domain.ErrorKindForbidden:    http.StatusForbidden,           // 403
-	domain.ErrorKindNotFound:     http.StatusNotFound,            // 404
-	domain.ErrorKindDuplicate:    http.StatusConflict,            // 409
-	domain.ErrorKindValidation:   http.StatusUnprocessableEntity, // 422
-	domain.ErrorKindUnavailable:  http.StatusServiceUnavailable,  // 503
+	domain.ErrorKindUnauthorized:    http.StatusUnauthorized,        // 401
+	domain.ErrorKindForbidden:       http.StatusForbidden,           // 403
+	domain.ErrorKindNotFound:        http.StatusNotFound,            // 404
+	domain.ErrorKindDuplicate:       http.StatusConflict,            // 409
+	domain.ErrorKindValidation:      http.StatusUnprocessableEntity, // 422
+	domain.ErrorKindUnavailable:     http.StatusServiceUnavailable,  // 503
+	domain.ErrorKindTooManyRequests: http.StatusTooManyRequests,     // 429
+	domain.ErrorKindLocked:          http.StatusLocked,              // 423
 }
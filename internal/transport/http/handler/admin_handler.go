@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/prawirdani/golang-restapi/internal/domain/audit"
+	"github.com/prawirdani/golang-restapi/internal/domain/auth"
+	"github.com/prawirdani/golang-restapi/internal/domain/user"
+	"github.com/prawirdani/golang-restapi/pkg/log"
+	"github.com/prawirdani/golang-restapi/pkg/validator"
+)
+
+type AdminHandler struct {
+	userService  *user.Service
+	authService  *auth.Service
+	auditService *audit.Service
+}
+
+func NewAdminHandler(
+	userService *user.Service,
+	authService *auth.Service,
+	auditService *audit.Service,
+) *AdminHandler {
+	return &AdminHandler{
+		userService:  userService,
+		authService:  authService,
+		auditService: auditService,
+	}
+}
+
+func (h *AdminHandler) ListUsersHandler(c *Context) error {
+	query := user.ListUsersInput{
+		Search:      c.Query("search"),
+		Status:      c.Query("status"),
+		CreatedFrom: c.Query("created_from"),
+		CreatedTo:   c.Query("created_to"),
+		Page:        c.Query("page"),
+		PerPage:     c.Query("per_page"),
+	}
+	if err := validator.Struct(query); err != nil {
+		log.ErrorCtx(c.Context(), "Failed to validate list users query", err)
+		return err
+	}
+
+	page, err := h.userService.ListUsers(c.Context(), query.Filter())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, NewPaginatedBody(page))
+}
+
+func (h *AdminHandler) GetUserHandler(c *Context) error {
+	usr, err := h.userService.GetUserByID(c.Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Data: usr,
+	})
+}
+
+// SuspendUserHandler blocks the user from signing in, their sessions and grants are revoked right away.
+func (h *AdminHandler) SuspendUserHandler(c *Context) error {
+	if err := h.authService.SuspendUser(c.Context(), c.Param("id")); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Message: "User has been suspended",
+	})
+}
+
+func (h *AdminHandler) UnsuspendUserHandler(c *Context) error {
+	if err := h.userService.UnsuspendUser(c.Context(), c.Param("id")); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Message: "User has been unsuspended",
+	})
+}
+
+func (h *AdminHandler) ForcePasswordResetHandler(c *Context) error {
+	if err := h.authService.ForcePasswordReset(c.Context(), c.Param("id")); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Message: "Password reset has been forced, the user has been emailed a reset link",
+	})
+}
+
+// ImpersonateUserHandler issues a short-lived access token to act as the user. It is only returned in the body,
+// the administrator's own token cookies are left untouched.
+func (h *AdminHandler) ImpersonateUserHandler(c *Context) error {
+	var reqBody auth.ImpersonateInput
+	if err := c.BindValidate(&reqBody); err != nil {
+		log.ErrorCtx(c.Context(), "Failed to bind & validate impersonate input", err)
+		return err
+	}
+
+	claims, err := auth.GetAccessTokenCtx(c.Context())
+	if err != nil {
+		return err
+	}
+
+	result, err := h.authService.Impersonate(c.Context(), claims, c.Param("id"), reqBody)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, &Body{
+		Data:    result,
+		Message: "Impersonation started",
+	})
+}
+
+func (h *AdminHandler) ListAuditEventsHandler(c *Context) error {
+	query := audit.ListEventsInput{
+		UserID:  c.Query("user_id"),
+		ActorID: c.Query("actor_id"),
+		Type:    c.Query("type"),
+		From:    c.Query("from"),
+		To:      c.Query("to"),
+		Page:    c.Query("page"),
+		PerPage: c.Query("per_page"),
+	}
+	if err := validator.Struct(query); err != nil {
+		log.ErrorCtx(c.Context(), "Failed to validate list audit events query", err)
+		return err
+	}
+
+	page, err := h.auditService.ListEvents(c.Context(), query.Filter())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, NewPaginatedBody(page))
+}
@@ -1,6 +1,10 @@
 package handler
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
 	"net/http"
 	"strings"
 	"time"
@@ -9,12 +13,14 @@ import (
 
 	"github.com/prawirdani/golang-restapi/config"
 	"github.com/prawirdani/golang-restapi/internal/domain/auth"
+	"github.com/prawirdani/golang-restapi/internal/domain/organization"
 	"github.com/prawirdani/golang-restapi/internal/domain/user"
 )
 
 type AuthHandler struct {
 	authService *auth.Service
 	userService *user.Service
+	orgService  *organization.Service
 	cfg         *config.Config
 }
 
@@ -22,10 +28,12 @@ func NewAuthHandler(
 	cfg *config.Config,
 	authService *auth.Service,
 	userService *user.Service,
+	orgService *organization.Service,
 ) *AuthHandler {
 	return &AuthHandler{
 		authService: authService,
 		userService: userService,
+		orgService:  orgService,
 		cfg:         cfg,
 	}
 }
@@ -42,7 +50,7 @@ func (h *AuthHandler) RegisterHandler(c *Context) error {
 	}
 
 	return c.JSON(http.StatusCreated, &Body{
-		Message: "Registration successful",
+		Message: "Registration successful, please check your email to continue",
 	})
 }
 
@@ -53,22 +61,384 @@ func (h *AuthHandler) LoginHandler(c *Context) error {
 		return err
 	}
 	reqBody.UserAgent = c.Get("User-Agent")
+	reqBody.IPAddress = c.ClientIP()
 
-	accessToken, sessID, err := h.authService.Login(c.Context(), reqBody)
+	res, err := h.authService.Login(c.Context(), reqBody)
 	if err != nil {
 		return err
 	}
 
-	tp := auth.TokenPair{
-		AccessToken:  accessToken,
-		RefreshToken: sessID,
+	if res.MFARequired() {
+		return c.JSON(http.StatusOK, &Body{
+			Data:    res.MFAChallenge,
+			Message: "Two-factor authentication required",
+		})
 	}
 
-	c.SetCookie(h.createTokenCookie(accessToken, AccessTokenCookie))
-	c.SetCookie(h.createTokenCookie(sessID, RefreshTokenCookie))
+	return c.JSON(http.StatusOK, &Body{
+		Data: h.setTokenCookies(c, res),
+	})
+}
 
-	return c.JSON(200, &Body{
-		Data: tp,
+func (h *AuthHandler) RequestMagicLinkHandler(c *Context) error {
+	var reqBody auth.MagicLinkInput
+	if err := c.BindValidate(&reqBody); err != nil {
+		log.ErrorCtx(c.Context(), "Failed to bind & validate magic link input", err)
+		return err
+	}
+
+	if err := h.authService.RequestMagicLink(c.Context(), reqBody); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Message: "If an account exists for this email, a login link has been sent to it",
+	})
+}
+
+func (h *AuthHandler) VerifyMagicLinkHandler(c *Context) error {
+	var reqBody auth.VerifyMagicLinkInput
+	if err := c.BindValidate(&reqBody); err != nil {
+		log.ErrorCtx(c.Context(), "Failed to bind & validate verify magic link input", err)
+		return err
+	}
+	reqBody.UserAgent = c.Get("User-Agent")
+	reqBody.IPAddress = c.ClientIP()
+
+	res, err := h.authService.VerifyMagicLink(c.Context(), reqBody)
+	if err != nil {
+		return err
+	}
+
+	if res.MFARequired() {
+		return c.JSON(http.StatusOK, &Body{
+			Data:    res.MFAChallenge,
+			Message: "Two-factor authentication required",
+		})
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Data: h.setTokenCookies(c, res),
+	})
+}
+
+// ExternalLoginHandler redirects the user to the consent page of an external identity provider. The state, nonce
+// and PKCE verifier are kept in short-lived cookies until the provider redirects back to the callback.
+func (h *AuthHandler) ExternalLoginHandler(c *Context) error {
+	req, err := h.authService.BeginExternalLogin(c.Param("provider"))
+	if err != nil {
+		return err
+	}
+
+	c.SetCookie(h.createExternalLoginCookie(externalStateCookie, req.State))
+	c.SetCookie(h.createExternalLoginCookie(externalNonceCookie, req.Nonce))
+	c.SetCookie(h.createExternalLoginCookie(externalVerifierCookie, req.Verifier))
+
+	return c.Redirect(http.StatusFound, req.URL)
+}
+
+func (h *AuthHandler) ExternalLoginCallbackHandler(c *Context) error {
+	values := make(map[string]string, 3)
+	for _, name := range []string{externalStateCookie, externalNonceCookie, externalVerifierCookie} {
+		cookie, err := c.GetCookie(name)
+		if err != nil {
+			return auth.ErrExternalLoginInvalid
+		}
+		values[name] = cookie.Value
+	}
+	h.removeExternalLoginCookies(c)
+
+	if errCode := c.Query("error"); errCode != "" {
+		log.WarnCtx(c.Context(), "External login rejected by provider", "error", errCode)
+		return auth.ErrExternalLoginInvalid
+	}
+
+	state := c.Query("state")
+	if state == "" || subtle.ConstantTimeCompare([]byte(state), []byte(values[externalStateCookie])) != 1 {
+		return auth.ErrExternalLoginInvalid
+	}
+
+	if c.Query("code") == "" {
+		return auth.ErrExternalLoginInvalid
+	}
+
+	inp := auth.CompleteExternalLoginInput{
+		Provider:  c.Param("provider"),
+		Code:      c.Query("code"),
+		Verifier:  values[externalVerifierCookie],
+		Nonce:     values[externalNonceCookie],
+		UserAgent: c.Get("User-Agent"),
+		IPAddress: c.ClientIP(),
+	}
+	res, err := h.authService.CompleteExternalLogin(c.Context(), inp)
+	if err != nil {
+		return err
+	}
+
+	if res.MFARequired() {
+		return c.JSON(http.StatusOK, &Body{
+			Data:    res.MFAChallenge,
+			Message: "Two-factor authentication required",
+		})
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Data: h.setTokenCookies(c, res),
+	})
+}
+
+func (h *AuthHandler) VerifyMFAHandler(c *Context) error {
+	var reqBody auth.VerifyMFAInput
+	if err := c.BindValidate(&reqBody); err != nil {
+		log.ErrorCtx(c.Context(), "Failed to bind & validate verify mfa input", err)
+		return err
+	}
+	reqBody.UserAgent = c.Get("User-Agent")
+	reqBody.IPAddress = c.ClientIP()
+
+	res, err := h.authService.VerifyMFA(c.Context(), reqBody)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Data: h.setTokenCookies(c, res),
+	})
+}
+
+// BeginMFAPasskeyHandler returns the options to assert a passkey as the second factor of a pending login.
+// The resulting credential is posted to VerifyMFAHandler along with the MFA token.
+func (h *AuthHandler) BeginMFAPasskeyHandler(c *Context) error {
+	var reqBody auth.BeginMFAPasskeyInput
+	if err := c.BindValidate(&reqBody); err != nil {
+		log.ErrorCtx(c.Context(), "Failed to bind & validate begin mfa passkey input", err)
+		return err
+	}
+
+	options, err := h.authService.BeginMFAPasskey(c.Context(), reqBody)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Data: options,
+	})
+}
+
+func (h *AuthHandler) BeginPasskeyLoginHandler(c *Context) error {
+	options, err := h.authService.BeginPasskeyLogin(c.Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Data: options,
+	})
+}
+
+func (h *AuthHandler) FinishPasskeyLoginHandler(c *Context) error {
+	var reqBody auth.FinishPasskeyLoginInput
+	if err := c.BindValidate(&reqBody); err != nil {
+		log.ErrorCtx(c.Context(), "Failed to bind & validate finish passkey login input", err)
+		return err
+	}
+	reqBody.UserAgent = c.Get("User-Agent")
+	reqBody.IPAddress = c.ClientIP()
+
+	res, err := h.authService.FinishPasskeyLogin(c.Context(), reqBody)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Data: h.setTokenCookies(c, res),
+	})
+}
+
+func (h *AuthHandler) BeginPasskeyRegistrationHandler(c *Context) error {
+	claims, err := auth.GetAccessTokenCtx(c.Context())
+	if err != nil {
+		return err
+	}
+
+	options, err := h.authService.BeginPasskeyRegistration(c.Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Data: options,
+	})
+}
+
+func (h *AuthHandler) FinishPasskeyRegistrationHandler(c *Context) error {
+	var reqBody auth.FinishPasskeyRegistrationInput
+	if err := c.BindValidate(&reqBody); err != nil {
+		log.ErrorCtx(c.Context(), "Failed to bind & validate finish passkey registration input", err)
+		return err
+	}
+
+	claims, err := auth.GetAccessTokenCtx(c.Context())
+	if err != nil {
+		return err
+	}
+
+	passkey, err := h.authService.FinishPasskeyRegistration(c.Context(), claims.UserID, reqBody)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, &Body{
+		Data:    passkey,
+		Message: "Passkey has been registered!",
+	})
+}
+
+func (h *AuthHandler) ListPasskeysHandler(c *Context) error {
+	claims, err := auth.GetAccessTokenCtx(c.Context())
+	if err != nil {
+		return err
+	}
+
+	passkeys, err := h.authService.ListPasskeys(c.Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Data: passkeys,
+	})
+}
+
+func (h *AuthHandler) DeletePasskeyHandler(c *Context) error {
+	claims, err := auth.GetAccessTokenCtx(c.Context())
+	if err != nil {
+		return err
+	}
+
+	if err := h.authService.DeletePasskey(c.Context(), claims.UserID, c.Param("id")); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Message: "Passkey has been removed",
+	})
+}
+
+func (h *AuthHandler) ListKnownDevicesHandler(c *Context) error {
+	claims, err := auth.GetAccessTokenCtx(c.Context())
+	if err != nil {
+		return err
+	}
+
+	devices, err := h.authService.ListKnownDevices(c.Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Data: devices,
+	})
+}
+
+func (h *AuthHandler) ForgetKnownDeviceHandler(c *Context) error {
+	claims, err := auth.GetAccessTokenCtx(c.Context())
+	if err != nil {
+		return err
+	}
+
+	if err := h.authService.ForgetKnownDevice(c.Context(), claims.UserID, c.Param("id")); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Message: "Device has been forgotten",
+	})
+}
+
+func (h *AuthHandler) EnrollTOTPHandler(c *Context) error {
+	claims, err := auth.GetAccessTokenCtx(c.Context())
+	if err != nil {
+		return err
+	}
+
+	enrollment, err := h.authService.EnrollTOTP(c.Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Data:    enrollment,
+		Message: "Scan the QR code with your authenticator app, then confirm with a code",
+	})
+}
+
+func (h *AuthHandler) ConfirmTOTPHandler(c *Context) error {
+	var reqBody auth.ConfirmTOTPInput
+	if err := c.BindValidate(&reqBody); err != nil {
+		log.ErrorCtx(c.Context(), "Failed to bind & validate confirm totp input", err)
+		return err
+	}
+
+	claims, err := auth.GetAccessTokenCtx(c.Context())
+	if err != nil {
+		return err
+	}
+
+	recoveryCodes, err := h.authService.ConfirmTOTP(c.Context(), claims.UserID, reqBody)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Data:    map[string][]string{"recovery_codes": recoveryCodes},
+		Message: "Two-factor authentication has been enabled!",
+	})
+}
+
+func (h *AuthHandler) DisableTOTPHandler(c *Context) error {
+	var reqBody auth.DisableTOTPInput
+	if err := c.BindValidate(&reqBody); err != nil {
+		log.ErrorCtx(c.Context(), "Failed to bind & validate disable totp input", err)
+		return err
+	}
+
+	claims, err := auth.GetAccessTokenCtx(c.Context())
+	if err != nil {
+		return err
+	}
+
+	reqBody.IPAddress = c.ClientIP()
+
+	if err := h.authService.DisableTOTP(c.Context(), claims.UserID, reqBody); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Message: "Two-factor authentication has been disabled!",
+	})
+}
+
+func (h *AuthHandler) RegenerateRecoveryCodesHandler(c *Context) error {
+	var reqBody auth.RegenerateRecoveryCodesInput
+	if err := c.BindValidate(&reqBody); err != nil {
+		log.ErrorCtx(c.Context(), "Failed to bind & validate regenerate recovery codes input", err)
+		return err
+	}
+
+	claims, err := auth.GetAccessTokenCtx(c.Context())
+	if err != nil {
+		return err
+	}
+
+	recoveryCodes, err := h.authService.RegenerateRecoveryCodes(c.Context(), claims.UserID, reqBody)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Data:    map[string][]string{"recovery_codes": recoveryCodes},
+		Message: "Recovery codes have been regenerated!",
 	})
 }
 
@@ -108,19 +478,16 @@ func (h *AuthHandler) RefreshTokenHandler(c *Context) error {
 		return ErrMissingAuthToken
 	}
 
-	newAccessToken, err := h.authService.RefreshAccessToken(c.Context(), refreshToken)
+	res, err := h.authService.RefreshAccessToken(c.Context(), refreshToken, c.Get("User-Agent"))
 	if err != nil {
+		if errors.Is(err, auth.ErrSessionReused) || errors.Is(err, auth.ErrSessionUserAgentChanged) {
+			h.removeTokenCookies(c)
+		}
 		return err
 	}
 
-	d := map[string]string{
-		AccessTokenCookie: newAccessToken,
-	}
-
-	c.SetCookie(h.createTokenCookie(newAccessToken, AccessTokenCookie))
-
 	return c.JSON(http.StatusOK, &Body{
-		Data:    d,
+		Data:    h.setTokenCookies(c, res),
 		Message: "Access token refreshed",
 	})
 }
@@ -152,7 +519,7 @@ func (h *AuthHandler) ForgotPasswordHandler(c *Context) error {
 	}
 
 	return c.JSON(http.StatusOK, &Body{
-		Message: "Password recovery email have been sent!",
+		Message: "If an account exists for this email, a password recovery email has been sent to it",
 	})
 }
 
@@ -197,6 +564,9 @@ func (h *AuthHandler) ChangePasswordHandler(c *Context) error {
 		return err
 	}
 
+	reqBody.SessionID = claims.SessionID
+	reqBody.IPAddress = c.ClientIP()
+
 	if err := h.authService.ChangePassword(c.Context(), claims.UserID, reqBody); err != nil {
 		return err
 	}
@@ -206,6 +576,346 @@ func (h *AuthHandler) ChangePasswordHandler(c *Context) error {
 	})
 }
 
+// BeginReauthenticationPasskeyHandler returns the options to assert a passkey re-authenticating the signed in user.
+// The resulting credential is posted to ReauthenticateHandler.
+func (h *AuthHandler) BeginReauthenticationPasskeyHandler(c *Context) error {
+	claims, err := auth.GetAccessTokenCtx(c.Context())
+	if err != nil {
+		return err
+	}
+
+	options, err := h.authService.BeginReauthenticationPasskey(c.Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Data: options,
+	})
+}
+
+// ReauthenticateHandler has the user prove their credentials again, and replaces the access token with one whose
+// auth_time allows sensitive operations.
+func (h *AuthHandler) ReauthenticateHandler(c *Context) error {
+	var reqBody auth.ReauthenticateInput
+	if err := c.BindValidate(&reqBody); err != nil {
+		log.ErrorCtx(c.Context(), "Failed to bind & validate reauthenticate input", err)
+		return err
+	}
+
+	claims, err := auth.GetAccessTokenCtx(c.Context())
+	if err != nil {
+		return err
+	}
+
+	reqBody.SessionID = claims.SessionID
+	reqBody.IPAddress = c.ClientIP()
+
+	accessToken, err := h.authService.Reauthenticate(c.Context(), claims.UserID, reqBody)
+	if err != nil {
+		return err
+	}
+
+	c.SetCookie(h.createTokenCookie(accessToken, AccessTokenCookie))
+
+	return c.JSON(http.StatusOK, &Body{
+		Data:    map[string]string{"access_token": accessToken},
+		Message: "Re-authenticated",
+	})
+}
+
+func (h *AuthHandler) DeleteAccountHandler(c *Context) error {
+	var reqBody auth.DeleteAccountInput
+	if err := c.BindValidate(&reqBody); err != nil {
+		log.ErrorCtx(c.Context(), "Failed to bind & validate delete account input", err)
+		return err
+	}
+
+	claims, err := auth.GetAccessTokenCtx(c.Context())
+	if err != nil {
+		return err
+	}
+
+	reqBody.IPAddress = c.ClientIP()
+
+	if err := h.authService.DeleteAccount(c.Context(), claims.UserID, reqBody); err != nil {
+		return err
+	}
+
+	h.removeTokenCookies(c)
+
+	return c.JSON(http.StatusOK, &Body{
+		Message: "Account has been deleted",
+	})
+}
+
+func (h *AuthHandler) ConfirmEmailChangeHandler(c *Context) error {
+	var reqBody auth.ConfirmEmailChangeInput
+	if err := c.BindValidate(&reqBody); err != nil {
+		log.ErrorCtx(c.Context(), "Failed to bind & validate confirm email change input", err)
+		return err
+	}
+
+	if err := h.authService.ConfirmEmailChange(c.Context(), reqBody); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Message: "Email has been changed successfully!",
+	})
+}
+
+func (h *AuthHandler) VerifyEmailHandler(c *Context) error {
+	var reqBody auth.VerifyEmailInput
+	if err := c.BindValidate(&reqBody); err != nil {
+		log.ErrorCtx(c.Context(), "Failed to bind & validate verify email input", err)
+		return err
+	}
+
+	if err := h.authService.VerifyEmail(c.Context(), reqBody); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Message: "Email has been verified successfully!",
+	})
+}
+
+func (h *AuthHandler) UnlockAccountHandler(c *Context) error {
+	var reqBody auth.UnlockAccountInput
+	if err := c.BindValidate(&reqBody); err != nil {
+		log.ErrorCtx(c.Context(), "Failed to bind & validate unlock account input", err)
+		return err
+	}
+
+	if err := h.authService.UnlockAccount(c.Context(), reqBody); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Message: "Account has been unlocked, you can now log in",
+	})
+}
+
+func (h *AuthHandler) RevokeUnrecognizedSessionHandler(c *Context) error {
+	var reqBody auth.RevokeUnrecognizedSessionInput
+	if err := c.BindValidate(&reqBody); err != nil {
+		log.ErrorCtx(c.Context(), "Failed to bind & validate revoke unrecognized session input", err)
+		return err
+	}
+
+	if err := h.authService.RevokeUnrecognizedSession(c.Context(), reqBody); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Message: "Session has been revoked, we recommend changing your password",
+	})
+}
+
+func (h *AuthHandler) ResendVerificationEmailHandler(c *Context) error {
+	var reqBody auth.ResendVerificationEmailInput
+	if err := c.BindValidate(&reqBody); err != nil {
+		log.ErrorCtx(c.Context(), "Failed to bind & validate resend verification email input", err)
+		return err
+	}
+
+	if err := h.authService.ResendVerificationEmail(c.Context(), reqBody); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Message: "If an unverified account exists for this email, a verification email has been sent to it",
+	})
+}
+
+func (h *AuthHandler) ListSessionsHandler(c *Context) error {
+	claims, err := auth.GetAccessTokenCtx(c.Context())
+	if err != nil {
+		return err
+	}
+
+	sessions, err := h.authService.ListSessions(c.Context(), claims.UserID, claims.SessionID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Data: sessions,
+	})
+}
+
+// StopImpersonationHandler revokes the impersonation token the request is made with.
+func (h *AuthHandler) StopImpersonationHandler(c *Context) error {
+	claims, err := auth.GetAccessTokenCtx(c.Context())
+	if err != nil {
+		return err
+	}
+
+	if err := h.authService.StopImpersonation(c.Context(), claims); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Message: "Impersonation stopped",
+	})
+}
+
+// SwitchOrganizationHandler makes an organization the caller is a member of the active one of the session, and
+// replaces the access token with one carrying it.
+func (h *AuthHandler) SwitchOrganizationHandler(c *Context) error {
+	var reqBody auth.SwitchOrganizationInput
+	if err := c.BindValidate(&reqBody); err != nil {
+		log.ErrorCtx(c.Context(), "Failed to bind & validate switch organization input", err)
+		return err
+	}
+
+	claims, err := auth.GetAccessTokenCtx(c.Context())
+	if err != nil {
+		return err
+	}
+
+	var orgID string
+	if reqBody.Organization != "" {
+		membership, err := h.orgService.ResolveTenant(c.Context(), claims.UserID, reqBody.Organization)
+		if err != nil {
+			return err
+		}
+		orgID = membership.OrganizationID.String()
+	}
+
+	accessToken, err := h.authService.SwitchOrganization(c.Context(), claims.UserID, claims.SessionID, orgID)
+	if err != nil {
+		return err
+	}
+
+	c.SetCookie(h.createTokenCookie(accessToken, AccessTokenCookie))
+
+	return c.JSON(http.StatusOK, &Body{
+		Data:    map[string]string{"access_token": accessToken},
+		Message: "Active organization switched",
+	})
+}
+
+func (h *AuthHandler) RevokeSessionHandler(c *Context) error {
+	claims, err := auth.GetAccessTokenCtx(c.Context())
+	if err != nil {
+		return err
+	}
+
+	sessionID := c.Param("id")
+	if err := h.authService.RevokeSession(c.Context(), claims.UserID, sessionID); err != nil {
+		return err
+	}
+
+	// Revoking the current session is a logout
+	if sessionID == claims.SessionID {
+		h.removeTokenCookies(c)
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Message: "Session has been revoked",
+	})
+}
+
+func (h *AuthHandler) RevokeOtherSessionsHandler(c *Context) error {
+	claims, err := auth.GetAccessTokenCtx(c.Context())
+	if err != nil {
+		return err
+	}
+
+	if err := h.authService.RevokeOtherSessions(c.Context(), claims.UserID, claims.SessionID); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Message: "All other sessions have been revoked",
+	})
+}
+
+func (h *AuthHandler) CreatePersonalAccessTokenHandler(c *Context) error {
+	var reqBody auth.CreatePersonalAccessTokenInput
+	if err := c.BindValidate(&reqBody); err != nil {
+		log.ErrorCtx(c.Context(), "Failed to bind & validate create personal access token input", err)
+		return err
+	}
+
+	claims, err := auth.GetAccessTokenCtx(c.Context())
+	if err != nil {
+		return err
+	}
+
+	token, err := h.authService.CreatePersonalAccessToken(c.Context(), claims.UserID, reqBody)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, &Body{
+		Data:    token,
+		Message: "Personal access token created, copy it now as it will not be shown again",
+	})
+}
+
+func (h *AuthHandler) ListPersonalAccessTokensHandler(c *Context) error {
+	claims, err := auth.GetAccessTokenCtx(c.Context())
+	if err != nil {
+		return err
+	}
+
+	tokens, err := h.authService.ListPersonalAccessTokens(c.Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Data: tokens,
+	})
+}
+
+func (h *AuthHandler) RevokePersonalAccessTokenHandler(c *Context) error {
+	claims, err := auth.GetAccessTokenCtx(c.Context())
+	if err != nil {
+		return err
+	}
+
+	if err := h.authService.RevokePersonalAccessToken(c.Context(), claims.UserID, c.Param("id")); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Message: "Personal access token has been revoked",
+	})
+}
+
+// JWKSHandler serves the public keys of the access token key set, so other services can verify
+// our tokens without holding the signing key. Responds with the bare JWK Set as required by RFC 7517.
+func (h *AuthHandler) JWKSHandler(c *Context) error {
+	c.Set("Cache-Control", "public, max-age=300")
+	return c.JSON(http.StatusOK, h.authService.JWKS())
+}
+
+// setTokenCookies writes the session tokens of a completed login as cookies and returns them as a [auth.TokenPair].
+// A fresh CSRF token cookie is issued along, required by state-changing requests authenticated by the cookies.
+func (h *AuthHandler) setTokenCookies(c *Context, res *auth.LoginResult) auth.TokenPair {
+	c.SetCookie(h.createTokenCookie(res.AccessToken, AccessTokenCookie))
+	c.SetCookie(h.createTokenCookie(res.SessionID, RefreshTokenCookie))
+
+	if csrfToken, err := newCSRFToken(); err != nil {
+		log.ErrorCtx(c.Context(), "Failed to generate csrf token", err)
+	} else {
+		csrfCookie := h.baseTokenCookie(CSRFTokenCookie)
+		csrfCookie.Value = csrfToken
+		csrfCookie.Expires = time.Now().Add(h.cfg.Auth.SessionTTL) // Lives as long as the session
+		csrfCookie.HttpOnly = false                                // Read by the web app to echo it back
+		c.SetCookie(csrfCookie)
+	}
+
+	return auth.TokenPair{
+		AccessToken:  res.AccessToken,
+		RefreshToken: res.SessionID,
+	}
+}
+
 func (h *AuthHandler) createTokenCookie(
 	token string,
 	label string,
@@ -217,29 +927,73 @@ func (h *AuthHandler) createTokenCookie(
 
 	currTime := time.Now()
 
+	cookie := h.baseTokenCookie(label)
+	cookie.Value = token
+	cookie.Expires = currTime.Add(expiry)
+	return cookie
+}
+
+// baseTokenCookie returns the token cookie attributes shared by every write, so the cookie is replaced
+// rather than duplicated. SameSite=None is only accepted by browsers on secure cookies.
+func (h *AuthHandler) baseTokenCookie(name string) *http.Cookie {
+	sameSite := sameSiteMode(h.cfg.Auth.CookieSameSite)
+
 	return &http.Cookie{
-		Name:     label,
-		Value:    token,
-		Expires:  currTime.Add(expiry),
+		Name:     name,
 		HttpOnly: h.cfg.IsProduction(),
-		Secure:   h.cfg.IsProduction(),
+		Secure:   h.cfg.IsProduction() || sameSite == http.SameSiteNoneMode,
+		SameSite: sameSite,
+		Domain:   h.cfg.Auth.CookieDomain,
 		Path:     "/",
 	}
 }
 
-func (h *AuthHandler) removeTokenCookies(c *Context) {
-	accessTokenCookie := &http.Cookie{
-		Name:     AccessTokenCookie,
-		Value:    "",
-		Expires:  time.Unix(0, 0),
-		HttpOnly: h.cfg.IsProduction(),
+func (h *AuthHandler) createExternalLoginCookie(name, value string) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Expires:  time.Now().Add(externalLoginCookieTTL),
+		HttpOnly: true,
 		Secure:   h.cfg.IsProduction(),
+		// Lax, so the cookie is sent along the top-level redirect back from the provider
+		SameSite: http.SameSiteLaxMode,
 		Path:     "/",
 	}
+}
 
-	sessCookie := *accessTokenCookie
-	sessCookie.Name = RefreshTokenCookie
+func (h *AuthHandler) removeExternalLoginCookies(c *Context) {
+	for _, name := range []string{externalStateCookie, externalNonceCookie, externalVerifierCookie} {
+		cookie := h.createExternalLoginCookie(name, "")
+		cookie.Expires = time.Unix(0, 0)
+		c.SetCookie(cookie)
+	}
+}
 
-	c.SetCookie(accessTokenCookie)
-	c.SetCookie(&sessCookie)
+func (h *AuthHandler) removeTokenCookies(c *Context) {
+	for _, name := range []string{AccessTokenCookie, RefreshTokenCookie, CSRFTokenCookie} {
+		cookie := h.baseTokenCookie(name)
+		cookie.Expires = time.Unix(0, 0)
+		c.SetCookie(cookie)
+	}
+}
+
+// sameSiteMode maps the configured SameSite attribute, defaulting to Lax.
+func sameSiteMode(value string) http.SameSite {
+	switch value {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
+}
+
+// newCSRFToken generates a random token for the double-submit CSRF check.
+func newCSRFToken() (string, error) {
+	bs := make([]byte, 32)
+	if _, err := rand.Read(bs); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bs), nil
 }
@@ -5,6 +5,8 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+
+	"github.com/prawirdani/golang-restapi/internal/domain"
 )
 
 // MaxBodySize maximum read size from request body
@@ -31,6 +33,50 @@ func (b *Body) MarshalJSON() ([]byte, error) {
 	return json.Marshal(m)
 }
 
+// PaginatedBody is json response body for a page of a collection
+type PaginatedBody struct {
+	Data       any        `json:"data"`
+	Message    string     `json:"message"`
+	Pagination Pagination `json:"pagination"`
+}
+
+// Pagination describes the position of a page within its collection
+type Pagination struct {
+	Page       int `json:"page"`
+	PerPage    int `json:"per_page"`
+	Total      int `json:"total"`
+	TotalPages int `json:"total_pages"`
+}
+
+// NewPaginatedBody creates a paginated response body from a domain page
+func NewPaginatedBody[T any](page *domain.Page[T]) *PaginatedBody {
+	return &PaginatedBody{
+		Data: page.Items,
+		Pagination: Pagination{
+			Page:       page.Page,
+			PerPage:    page.PerPage,
+			Total:      page.Total,
+			TotalPages: page.TotalPages(),
+		},
+	}
+}
+
+// MarshalJSON implements json.Marshaller, keeping the message field nullable like [Body]
+func (b *PaginatedBody) MarshalJSON() ([]byte, error) {
+	m := map[string]any{
+		"data":       b.Data,
+		"pagination": b.Pagination,
+	}
+
+	if b.Message != "" {
+		m["message"] = b.Message
+	} else {
+		m["message"] = nil
+	}
+
+	return json.Marshal(m)
+}
+
 // JSONRequestBody is an interface for request body that needs to be validated and sanitized when binding to struct on handler function
 // It is recommended to implement this interface on every request body model struct
 // Should use this alongside with BindValidate function from binder.go
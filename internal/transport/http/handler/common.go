@@ -2,9 +2,12 @@ package handler
 
 import (
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"time"
 
+	"github.com/prawirdani/golang-restapi/internal/domain/auth"
 	httperr "github.com/prawirdani/golang-restapi/internal/transport/http/error"
 )
 
@@ -15,6 +18,16 @@ const (
 	// RefreshTokenCookie used as refresh token cookie name and response body field.
 	// Value based on Session.ID
 	RefreshTokenCookie = "refresh_token"
+	// CSRFTokenCookie holds the CSRF token of a cookie-authenticated client, readable by scripts so it can be
+	// echoed back in the [CSRFTokenHeader] of state-changing requests.
+	CSRFTokenCookie = "csrf_token"
+	CSRFTokenHeader = "X-CSRF-Token"
+
+	// Cookies holding the values of an external login between the redirect to the provider and its callback.
+	externalStateCookie    = "oidc_state"
+	externalNonceCookie    = "oidc_nonce"
+	externalVerifierCookie = "oidc_verifier"
+	externalLoginCookieTTL = 10 * time.Minute
 )
 
 var ErrMissingAuthToken = httperr.New(
@@ -23,6 +36,27 @@ var ErrMissingAuthToken = httperr.New(
 	nil,
 )
 
+// RequireRecentAuth returns an error unless the user proved their credentials within maxAge. The 401 response
+// follows the step-up authentication challenge of RFC 9470, telling the client to re-authenticate through
+// POST /auth/reauthenticate and retry the request.
+func RequireRecentAuth(c *Context, claims *auth.AccessTokenClaims, maxAge time.Duration) error {
+	if claims.AuthenticatedWithin(maxAge) {
+		return nil
+	}
+
+	seconds := int(maxAge.Seconds())
+	c.Set("WWW-Authenticate", fmt.Sprintf(`Bearer error="insufficient_user_authentication", max_age=%d`, seconds))
+
+	return httperr.New(
+		http.StatusUnauthorized,
+		"Please re-authenticate to continue",
+		map[string]any{
+			"error":   "insufficient_user_authentication",
+			"max_age": seconds,
+		},
+	)
+}
+
 func isMissingFileError(err error) bool {
 	if errors.Is(err, http.ErrMissingFile) || errors.Is(err, io.EOF) {
 		return true
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"mime/multipart"
+	"net"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
@@ -34,7 +35,10 @@ func (c *Context) JSON(status int, data any) error {
 				return nil
 			}
 			c.Set("ETag", etag)
-			c.Set("Cache-Control", "private, must-revalidate")
+			// Responses such as issued tokens opt out of caching before writing
+			if c.w.Header().Get("Cache-Control") == "" {
+				c.Set("Cache-Control", "private, must-revalidate")
+			}
 		}
 		c.Set("ETag", etag)
 	}
@@ -52,6 +56,12 @@ func (c *Context) String(status int, format string, values ...any) error {
 	return err
 }
 
+// Redirect replies with a redirect to the given url
+func (c *Context) Redirect(status int, url string) error {
+	http.Redirect(c.w, c.r, url, status)
+	return nil
+}
+
 func (c *Context) Method() string {
 	return c.r.Method
 }
@@ -60,6 +70,25 @@ func (c *Context) URLPath() string {
 	return c.r.URL.Path
 }
 
+// Host returns the host the request was sent to, without the port
+func (c *Context) Host() string {
+	host, _, err := net.SplitHostPort(c.r.Host)
+	if err != nil {
+		return c.r.Host
+	}
+	return host
+}
+
+// RequestURI returns the path and query of the request
+func (c *Context) RequestURI() string {
+	return c.r.URL.RequestURI()
+}
+
+// BasicAuth returns the credentials of the Basic Authorization header
+func (c *Context) BasicAuth() (username, password string, ok bool) {
+	return c.r.BasicAuth()
+}
+
 // Param gets a route parameter by key from Chi's URL params
 func (c *Context) Param(key string) string {
 	return chi.URLParam(c.r, key)
@@ -91,6 +120,32 @@ func (c *Context) Get(key string) string {
 	return c.r.Header.Get(key)
 }
 
+// ClientIP gets the IP address of the client that sent the request, see [RequestClientIP]
+func (c *Context) ClientIP() string {
+	return RequestClientIP(c.r)
+}
+
+type clientIPCtxKey struct{}
+
+// WithClientIP records the IP address of the client resolved from behind the trusted proxies in ctx.
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPCtxKey{}, ip)
+}
+
+// RequestClientIP returns the client IP resolved by the client IP middleware, or the address of the
+// connection when the request didn't go through it.
+func RequestClientIP(r *http.Request) string {
+	if ip, ok := r.Context().Value(clientIPCtxKey{}).(string); ok && ip != "" {
+		return ip
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
 // SetCookie sets cookie
 func (c *Context) SetCookie(cookie *http.Cookie) {
 	http.SetCookie(c.w, cookie)
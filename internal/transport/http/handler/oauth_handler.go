@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/prawirdani/golang-restapi/config"
+	"github.com/prawirdani/golang-restapi/internal/domain/auth"
+	"github.com/prawirdani/golang-restapi/pkg/log"
+)
+
+// OAuthHandler serves the OAuth2 authorization server endpoints. Token, introspection and revocation
+// responses follow the OAuth RFCs rather than the regular [Body] envelope, so standard client libraries
+// can talk to them.
+type OAuthHandler struct {
+	oauthService *auth.OAuthService
+	cfg          *config.Config
+}
+
+func NewOAuthHandler(cfg *config.Config, oauthService *auth.OAuthService) *OAuthHandler {
+	return &OAuthHandler{
+		oauthService: oauthService,
+		cfg:          cfg,
+	}
+}
+
+// AuthorizeHandler issues an authorization code to the signed in user and redirects back to the client.
+// Signed out users are sent to the login page first, which brings them back here once signed in.
+func (h *OAuthHandler) AuthorizeHandler(c *Context) error {
+	claims, err := auth.GetAccessTokenCtx(c.Context())
+	// Only a login session may authorize, delegated tokens can't be used to mint new grants
+	if err != nil || claims.SessionID == "" || claims.ClientID != "" {
+		loginURL := h.cfg.Auth.OAuthLoginEndpoint + "?return_to=" + url.QueryEscape(c.RequestURI())
+		return c.Redirect(http.StatusFound, loginURL)
+	}
+
+	inp := auth.OAuthAuthorizeInput{
+		ResponseType:        c.Query("response_type"),
+		ClientID:            c.Query("client_id"),
+		RedirectURI:         c.Query("redirect_uri"),
+		Scope:               c.Query("scope"),
+		State:               c.Query("state"),
+		CodeChallenge:       c.Query("code_challenge"),
+		CodeChallengeMethod: c.Query("code_challenge_method"),
+	}
+
+	redirectURL, err := h.oauthService.Authorize(c.Context(), claims.UserID, inp)
+	if err != nil {
+		return h.oauthError(c, err)
+	}
+
+	return c.Redirect(http.StatusFound, redirectURL)
+}
+
+func (h *OAuthHandler) TokenHandler(c *Context) error {
+	clientID, clientSecret := h.clientCredentials(c)
+	inp := auth.OAuthTokenInput{
+		GrantType:    c.FormValue("grant_type"),
+		Code:         c.FormValue("code"),
+		RedirectURI:  c.FormValue("redirect_uri"),
+		CodeVerifier: c.FormValue("code_verifier"),
+		RefreshToken: c.FormValue("refresh_token"),
+		Scope:        c.FormValue("scope"),
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+	}
+
+	res, err := h.oauthService.Token(c.Context(), inp)
+	if err != nil {
+		return h.oauthError(c, err)
+	}
+
+	c.Set("Cache-Control", "no-store")
+	return c.JSON(http.StatusOK, res)
+}
+
+func (h *OAuthHandler) IntrospectHandler(c *Context) error {
+	clientID, clientSecret := h.clientCredentials(c)
+
+	res, err := h.oauthService.Introspect(c.Context(), clientID, clientSecret, c.FormValue("token"))
+	if err != nil {
+		return h.oauthError(c, err)
+	}
+
+	c.Set("Cache-Control", "no-store")
+	return c.JSON(http.StatusOK, res)
+}
+
+func (h *OAuthHandler) RevokeHandler(c *Context) error {
+	clientID, clientSecret := h.clientCredentials(c)
+
+	if err := h.oauthService.Revoke(c.Context(), clientID, clientSecret, c.FormValue("token")); err != nil {
+		return h.oauthError(c, err)
+	}
+
+	c.Status(http.StatusOK)
+	return nil
+}
+
+func (h *OAuthHandler) RegisterClientHandler(c *Context) error {
+	var reqBody auth.RegisterOAuthClientInput
+	if err := c.BindValidate(&reqBody); err != nil {
+		log.ErrorCtx(c.Context(), "Failed to bind & validate register oauth client input", err)
+		return err
+	}
+
+	claims, err := auth.GetAccessTokenCtx(c.Context())
+	if err != nil {
+		return err
+	}
+
+	client, err := h.oauthService.RegisterClient(c.Context(), claims.UserID, reqBody)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, &Body{
+		Data:    client,
+		Message: "Store the client secret now, it won't be shown again",
+	})
+}
+
+func (h *OAuthHandler) ListClientsHandler(c *Context) error {
+	clients, err := h.oauthService.ListClients(c.Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Data: clients,
+	})
+}
+
+func (h *OAuthHandler) DeleteClientHandler(c *Context) error {
+	if err := h.oauthService.DeleteClient(c.Context(), c.Param("id")); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Message: "OAuth client has been deleted",
+	})
+}
+
+// clientCredentials reads the client credentials from the Basic Authorization header, falling back to the
+// client_id and client_secret form parameters.
+func (h *OAuthHandler) clientCredentials(c *Context) (string, string) {
+	if id, secret, ok := c.BasicAuth(); ok {
+		// Credentials are form-urlencoded before being put in the header, RFC 6749 section 2.3.1
+		if unescaped, err := url.QueryUnescape(id); err == nil {
+			id = unescaped
+		}
+		if unescaped, err := url.QueryUnescape(secret); err == nil {
+			secret = unescaped
+		}
+		return id, secret
+	}
+
+	return c.FormValue("client_id"), c.FormValue("client_secret")
+}
+
+// oauthError writes an [*auth.OAuthError] as defined by RFC 6749 section 5.2, other errors go through
+// the regular error handling.
+func (h *OAuthHandler) oauthError(c *Context, err error) error {
+	var oauthErr *auth.OAuthError
+	if !errors.As(err, &oauthErr) {
+		return err
+	}
+
+	status := http.StatusBadRequest
+	if oauthErr.Code == auth.OAuthErrInvalidClient {
+		status = http.StatusUnauthorized
+		c.Set("WWW-Authenticate", `Basic realm="oauth"`)
+	}
+
+	c.Set("Cache-Control", "no-store")
+	return c.JSON(status, oauthErr)
+}
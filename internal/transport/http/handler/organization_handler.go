@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/prawirdani/golang-restapi/internal/domain/auth"
+	"github.com/prawirdani/golang-restapi/internal/domain/organization"
+	"github.com/prawirdani/golang-restapi/pkg/log"
+)
+
+type OrganizationHandler struct {
+	orgService *organization.Service
+}
+
+func NewOrganizationHandler(orgService *organization.Service) *OrganizationHandler {
+	return &OrganizationHandler{
+		orgService: orgService,
+	}
+}
+
+func (h *OrganizationHandler) CreateOrganizationHandler(c *Context) error {
+	var reqBody organization.CreateOrganizationInput
+	if err := c.BindValidate(&reqBody); err != nil {
+		log.ErrorCtx(c.Context(), "Failed to bind & validate create organization input", err)
+		return err
+	}
+
+	claims, err := auth.GetAccessTokenCtx(c.Context())
+	if err != nil {
+		return err
+	}
+
+	org, err := h.orgService.CreateOrganization(c.Context(), claims.UserID, reqBody)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, &Body{
+		Data:    org,
+		Message: "Organization created",
+	})
+}
+
+func (h *OrganizationHandler) ListOrganizationsHandler(c *Context) error {
+	claims, err := auth.GetAccessTokenCtx(c.Context())
+	if err != nil {
+		return err
+	}
+
+	orgs, err := h.orgService.ListUserOrganizations(c.Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Data: orgs,
+	})
+}
+
+func (h *OrganizationHandler) GetCurrentOrganizationHandler(c *Context) error {
+	org, err := h.orgService.GetCurrentOrganization(c.Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Data: org,
+	})
+}
+
+func (h *OrganizationHandler) ListMembersHandler(c *Context) error {
+	members, err := h.orgService.ListMembers(c.Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Data: members,
+	})
+}
+
+func (h *OrganizationHandler) UpdateMemberRoleHandler(c *Context) error {
+	var reqBody organization.UpdateMemberRoleInput
+	if err := c.BindValidate(&reqBody); err != nil {
+		log.ErrorCtx(c.Context(), "Failed to bind & validate update member role input", err)
+		return err
+	}
+
+	if err := h.orgService.UpdateMemberRole(c.Context(), c.Param("userID"), reqBody); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Message: "Member role updated",
+	})
+}
+
+func (h *OrganizationHandler) RemoveMemberHandler(c *Context) error {
+	if err := h.orgService.RemoveMember(c.Context(), c.Param("userID")); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Message: "Member removed",
+	})
+}
+
+func (h *OrganizationHandler) InviteMemberHandler(c *Context) error {
+	var reqBody organization.InviteMemberInput
+	if err := c.BindValidate(&reqBody); err != nil {
+		log.ErrorCtx(c.Context(), "Failed to bind & validate invite member input", err)
+		return err
+	}
+
+	inv, err := h.orgService.InviteMember(c.Context(), reqBody)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, &Body{
+		Data:    inv,
+		Message: "Invitation sent",
+	})
+}
+
+func (h *OrganizationHandler) ListInvitationsHandler(c *Context) error {
+	invitations, err := h.orgService.ListInvitations(c.Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Data: invitations,
+	})
+}
+
+func (h *OrganizationHandler) RevokeInvitationHandler(c *Context) error {
+	if err := h.orgService.RevokeInvitation(c.Context(), c.Param("id")); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Message: "Invitation revoked",
+	})
+}
+
+func (h *OrganizationHandler) AcceptInvitationHandler(c *Context) error {
+	var reqBody organization.InvitationTokenInput
+	if err := c.BindValidate(&reqBody); err != nil {
+		log.ErrorCtx(c.Context(), "Failed to bind & validate accept invitation input", err)
+		return err
+	}
+
+	claims, err := auth.GetAccessTokenCtx(c.Context())
+	if err != nil {
+		return err
+	}
+
+	membership, err := h.orgService.AcceptInvitation(c.Context(), claims.UserID, reqBody)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Data:    membership,
+		Message: "Invitation accepted",
+	})
+}
+
+func (h *OrganizationHandler) DeclineInvitationHandler(c *Context) error {
+	var reqBody organization.InvitationTokenInput
+	if err := c.BindValidate(&reqBody); err != nil {
+		log.ErrorCtx(c.Context(), "Failed to bind & validate decline invitation input", err)
+		return err
+	}
+
+	if err := h.orgService.DeclineInvitation(c.Context(), reqBody); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Message: "Invitation declined",
+	})
+}
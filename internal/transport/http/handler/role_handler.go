@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/prawirdani/golang-restapi/internal/domain/auth"
+	"github.com/prawirdani/golang-restapi/pkg/log"
+)
+
+type RoleHandler struct {
+	authService *auth.Service
+}
+
+func NewRoleHandler(authService *auth.Service) *RoleHandler {
+	return &RoleHandler{
+		authService: authService,
+	}
+}
+
+func (h *RoleHandler) ListRolesHandler(c *Context) error {
+	roles, err := h.authService.ListRoles(c.Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Data: roles,
+	})
+}
+
+func (h *RoleHandler) GetUserRolesHandler(c *Context) error {
+	roles, err := h.authService.GetUserRoles(c.Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Data: roles,
+	})
+}
+
+func (h *RoleHandler) AssignRoleHandler(c *Context) error {
+	var reqBody auth.AssignRoleInput
+	if err := c.BindValidate(&reqBody); err != nil {
+		log.ErrorCtx(c.Context(), "Failed to bind & validate assign role input", err)
+		return err
+	}
+
+	claims, err := auth.GetAccessTokenCtx(c.Context())
+	if err != nil {
+		return err
+	}
+
+	if err := h.authService.AssignRole(c.Context(), claims, c.Param("id"), reqBody); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Message: "Role has been assigned",
+	})
+}
+
+func (h *RoleHandler) RevokeRoleHandler(c *Context) error {
+	if err := h.authService.RevokeRole(c.Context(), c.Param("id"), c.Param("role")); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Message: "Role has been revoked",
+	})
+}
@@ -4,20 +4,34 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/prawirdani/golang-restapi/config"
+	"github.com/prawirdani/golang-restapi/internal/domain/audit"
 	"github.com/prawirdani/golang-restapi/internal/domain/auth"
 	"github.com/prawirdani/golang-restapi/internal/domain/user"
 	httperr "github.com/prawirdani/golang-restapi/internal/transport/http/error"
 	"github.com/prawirdani/golang-restapi/internal/transport/http/uploader"
 	"github.com/prawirdani/golang-restapi/pkg/log"
+	"github.com/prawirdani/golang-restapi/pkg/validator"
 )
 
 type UserHandler struct {
-	userService *user.Service
+	userService  *user.Service
+	authService  *auth.Service
+	auditService *audit.Service
+	cfg          *config.Config
 }
 
-func NewUserHandler(userService *user.Service) *UserHandler {
+func NewUserHandler(
+	cfg *config.Config,
+	userService *user.Service,
+	authService *auth.Service,
+	auditService *audit.Service,
+) *UserHandler {
 	return &UserHandler{
-		userService: userService,
+		userService:  userService,
+		authService:  authService,
+		auditService: auditService,
+		cfg:          cfg,
 	}
 }
 
@@ -60,3 +74,75 @@ func (h *UserHandler) ChangeProfilePictureHandler(c *Context) error {
 		Message: "Profile picture updated!",
 	})
 }
+
+func (h *UserHandler) UpdateProfileHandler(c *Context) error {
+	var reqBody user.UpdateProfileInput
+	if err := c.BindValidate(&reqBody); err != nil {
+		log.ErrorCtx(c.Context(), "Failed to bind & validate update profile input", err)
+		return err
+	}
+
+	claims, err := auth.GetAccessTokenCtx(c.Context())
+	if err != nil {
+		return err
+	}
+
+	// Changing the email address hands the account over, the owner has to do it themselves and recently
+	// authenticated. It is checked before the profile is updated, so a rejected request changes nothing.
+	if reqBody.Email != nil {
+		current, err := h.userService.GetUserByID(c.Context(), claims.UserID)
+		if err != nil {
+			return err
+		}
+		if reqBody.EmailChangeRequested(current.Email) {
+			if claims.Impersonated() {
+				return auth.ErrImpersonationNotAllowed
+			}
+			if err := RequireRecentAuth(c, claims, h.cfg.Auth.ReauthenticationMaxAge); err != nil {
+				return err
+			}
+		}
+	}
+
+	usr, err := h.userService.UpdateProfile(c.Context(), claims.UserID, reqBody)
+	if err != nil {
+		return err
+	}
+
+	message := "Profile updated!"
+	if reqBody.EmailChangeRequested(usr.Email) {
+		if err := h.authService.RequestEmailChange(c.Context(), claims.UserID, *reqBody.Email); err != nil {
+			return err
+		}
+		message = "Profile updated! Please confirm the new email address through the link we sent to it"
+	}
+
+	return c.JSON(http.StatusOK, &Body{
+		Data:    usr,
+		Message: message,
+	})
+}
+
+// ListSecurityEventsHandler lists the audit events about the current user's account, newest first.
+func (h *UserHandler) ListSecurityEventsHandler(c *Context) error {
+	query := audit.ListEventsInput{
+		Page:    c.Query("page"),
+		PerPage: c.Query("per_page"),
+	}
+	if err := validator.Struct(query); err != nil {
+		log.ErrorCtx(c.Context(), "Failed to validate list security events query", err)
+		return err
+	}
+
+	claims, err := auth.GetAccessTokenCtx(c.Context())
+	if err != nil {
+		return err
+	}
+
+	page, err := h.auditService.ListUserEvents(c.Context(), claims.UserID, query.Filter().PageRequest)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, NewPaginatedBody(page))
+}
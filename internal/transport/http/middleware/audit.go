@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"github.com/prawirdani/golang-restapi/internal/domain/audit"
+	"github.com/prawirdani/golang-restapi/internal/transport/http/handler"
+)
+
+// AuditRequest injects the client IP address, user agent and request ID into the request context,
+// so audit events recorded while serving the request carry where they came from.
+func AuditRequest(next handler.Func) handler.Func {
+	return func(c *handler.Context) error {
+		ctx := audit.WithRequest(c.Context(), audit.Request{
+			IPAddress: c.ClientIP(),
+			UserAgent: c.Get("User-Agent"),
+			RequestID: c.Get(HeaderXRequestID),
+		})
+
+		return next(c.WithContext(ctx))
+	}
+}
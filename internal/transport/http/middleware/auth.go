@@ -1,46 +1,141 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"strings"
 
+	"github.com/prawirdani/golang-restapi/internal/domain/audit"
 	"github.com/prawirdani/golang-restapi/internal/domain/auth"
 	"github.com/prawirdani/golang-restapi/internal/transport/http/handler"
+	"github.com/prawirdani/golang-restapi/pkg/log"
 )
 
-func Auth(jwtSecret string) func(next handler.Func) handler.Func {
-	return func(next handler.Func) handler.Func {
-		return func(c *handler.Context) error {
-			var tokenStr string
-
-			// Try from cookie
-			if cookie, err := c.GetCookie(handler.AccessTokenCookie); err == nil {
-				tokenStr = cookie.Value
-			}
+// PersonalAccessTokenAuthenticator resolves a personal access token into access token claims,
+// implemented by [auth.Service].
+type PersonalAccessTokenAuthenticator interface {
+	AuthenticatePersonalAccessToken(ctx context.Context, token string) (*auth.AccessTokenClaims, error)
+}
 
-			// Try from Authorization header
-			if tokenStr == "" {
-				authHeader := c.Get("Authorization")
-				if strings.HasPrefix(authHeader, "Bearer ") {
-					tokenStr = authHeader[len("Bearer "):]
-				}
-			}
+// ImpersonationAuditor audits the end of impersonations whose token expired, implemented by [auth.Service].
+type ImpersonationAuditor interface {
+	EndExpiredImpersonation(ctx context.Context, token string) error
+}
 
-			// If missing, return unauthorized error
-			if tokenStr == "" {
-				return handler.ErrMissingAuthToken
+// Auth rejects the request unless it carries a valid credential, either a personal access token or a first-party
+// access token that hasn't been revoked, and injects its claims into the request context. Tokens issued to OAuth
+// clients are rejected, they are meant for the resource servers of the clients. An expired impersonation token ends
+// the impersonation in the audit log, through impersonations when given.
+func Auth(
+	keys *auth.KeySet,
+	revocations auth.TokenRevocationStore,
+	pats PersonalAccessTokenAuthenticator,
+	impersonations ImpersonationAuditor,
+) func(next handler.Func) handler.Func {
+	return func(next handler.Func) handler.Func {
+		return func(c *handler.Context) error {
+			claims, err := authenticate(c, keys, revocations, pats)
+			if errors.Is(err, auth.ErrAccessTokenExpired) && impersonations != nil {
+				auditExpiredImpersonation(c, impersonations)
 			}
-
-			// Validate token
-			claims, err := auth.VerifyAccessToken(jwtSecret, tokenStr)
 			if err != nil {
 				return err
 			}
 
-			// Inject access token claims into request context
-			ctx := auth.SetAccessTokenCtx(c.Context(), claims)
-			c = c.WithContext(ctx)
+			c = c.WithContext(withClaims(c.Context(), claims))
 
 			return next(c)
 		}
 	}
 }
+
+// OptionalAuth injects the access token claims like [Auth] when the request carries a valid credential,
+// and lets the request through unauthenticated otherwise.
+func OptionalAuth(
+	keys *auth.KeySet,
+	revocations auth.TokenRevocationStore,
+	pats PersonalAccessTokenAuthenticator,
+) func(next handler.Func) handler.Func {
+	return func(next handler.Func) handler.Func {
+		return func(c *handler.Context) error {
+			if claims, err := authenticate(c, keys, revocations, pats); err == nil {
+				c = c.WithContext(withClaims(c.Context(), claims))
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// withClaims injects the access token claims into ctx, along with the actor of impersonated requests, who is also
+// the one audited.
+func withClaims(ctx context.Context, claims *auth.AccessTokenClaims) context.Context {
+	ctx = auth.SetAccessTokenCtx(ctx, claims)
+	if claims.Impersonated() {
+		ctx = auth.SetActorCtx(ctx, claims.Actor)
+	}
+	return audit.WithActor(ctx, claims.ActorID())
+}
+
+// authenticate resolves the access token claims of the request, from either a personal access token,
+// the access token cookie or a bearer JWT. JWTs are rejected when issued to an OAuth client, or once they or their
+// session have been revoked.
+func authenticate(
+	c *handler.Context,
+	keys *auth.KeySet,
+	revocations auth.TokenRevocationStore,
+	pats PersonalAccessTokenAuthenticator,
+) (*auth.AccessTokenClaims, error) {
+	var bearer string
+	authHeader := c.Get("Authorization")
+	if strings.HasPrefix(authHeader, "Bearer ") {
+		bearer = authHeader[len("Bearer "):]
+	}
+
+	// Personal access tokens are only accepted from the Authorization header
+	if auth.IsPersonalAccessToken(bearer) {
+		return pats.AuthenticatePersonalAccessToken(c.Context(), bearer)
+	}
+
+	tokenStr := accessToken(c, bearer)
+
+	// If missing, return unauthorized error
+	if tokenStr == "" {
+		return nil, handler.ErrMissingAuthToken
+	}
+
+	// Validate token
+	claims, err := auth.VerifyAccessToken(keys, tokenStr)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := auth.CheckFirstPartyAccessToken(claims); err != nil {
+		return nil, err
+	}
+
+	if err := auth.CheckAccessTokenRevoked(c.Context(), revocations, claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// auditExpiredImpersonation has the impersonation of the request's expired access token audited as ended. A failure
+// is only logged, the request is rejected for its expired token either way.
+func auditExpiredImpersonation(c *handler.Context, impersonations ImpersonationAuditor) {
+	tokenStr := accessToken(c, strings.TrimPrefix(c.Get("Authorization"), "Bearer "))
+
+	if err := impersonations.EndExpiredImpersonation(c.Context(), tokenStr); err != nil {
+		log.ErrorCtx(c.Context(), "Failed to audit expired impersonation", err)
+	}
+}
+
+// accessToken returns the access token of the request, from the token cookie or else the bearer token.
+func accessToken(c *handler.Context, bearer string) string {
+	if cookie, err := c.GetCookie(handler.AccessTokenCookie); err == nil && cookie.Value != "" {
+		return cookie.Value
+	}
+
+	return bearer
+}
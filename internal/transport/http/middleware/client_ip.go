@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/prawirdani/golang-restapi/internal/transport/http/handler"
+)
+
+// ClientIP resolves the IP address of the client from behind the trusted proxies, see [ResolveClientIP], and
+// records it in the request context for [handler.Context.ClientIP].
+func ClientIP(trustedProxies []netip.Prefix) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := handler.WithClientIP(r.Context(), ResolveClientIP(r, trustedProxies))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// ResolveClientIP returns the address of the connection, unless it belongs to a trusted proxy. Then the
+// X-Forwarded-For hops are walked from the right, each appended by the proxy in front of the previous one, and the
+// first address not belonging to a trusted proxy is the client. Hops left of it are set by the client and can't be
+// trusted. When every hop is a trusted proxy the left-most one is returned.
+func ResolveClientIP(r *http.Request, trustedProxies []netip.Prefix) string {
+	remote, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		remote = r.RemoteAddr
+	}
+
+	if !isTrustedProxy(remote, trustedProxies) {
+		return remote
+	}
+
+	var hops []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(header, ",")...)
+	}
+
+	client := remote
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		addr, err := netip.ParseAddr(hop)
+		if err != nil {
+			// Garbage from the client, the last proxy seen is the best known client
+			break
+		}
+
+		client = addr.Unmap().String()
+		if !isTrustedProxy(client, trustedProxies) {
+			break
+		}
+	}
+
+	return client
+}
+
+func isTrustedProxy(ip string, trustedProxies []netip.Prefix) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+
+	for _, prefix := range trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
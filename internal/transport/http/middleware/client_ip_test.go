@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/prawirdani/golang-restapi/internal/transport/http/handler"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveClientIP(t *testing.T) {
+	trusted := []netip.Prefix{
+		netip.MustParsePrefix("172.16.0.0/12"),
+		netip.MustParsePrefix("10.0.0.1/32"),
+	}
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor []string
+		expectedIP   string
+	}{
+		{
+			name:         "UntrustedRemoteIgnoresHeader",
+			remoteAddr:   "203.0.113.7:4321",
+			forwardedFor: []string{"198.51.100.1"},
+			expectedIP:   "203.0.113.7",
+		},
+		{
+			name:         "TrustedProxy",
+			remoteAddr:   "172.18.0.2:4321",
+			forwardedFor: []string{"198.51.100.1"},
+			expectedIP:   "198.51.100.1",
+		},
+		{
+			name:         "SpoofedHopsLeftOfClient",
+			remoteAddr:   "172.18.0.2:4321",
+			forwardedFor: []string{"1.2.3.4, 198.51.100.1"},
+			expectedIP:   "198.51.100.1",
+		},
+		{
+			name:         "ChainedTrustedProxies",
+			remoteAddr:   "172.18.0.2:4321",
+			forwardedFor: []string{"1.2.3.4, 198.51.100.1", "10.0.0.1"},
+			expectedIP:   "198.51.100.1",
+		},
+		{
+			name:         "AllHopsTrusted",
+			remoteAddr:   "172.18.0.2:4321",
+			forwardedFor: []string{"10.0.0.1, 172.18.0.3"},
+			expectedIP:   "10.0.0.1",
+		},
+		{
+			name:         "InvalidHopStops",
+			remoteAddr:   "172.18.0.2:4321",
+			forwardedFor: []string{"198.51.100.1, not-an-ip, 10.0.0.1"},
+			expectedIP:   "10.0.0.1",
+		},
+		{
+			name:       "TrustedProxyWithoutHeader",
+			remoteAddr: "172.18.0.2:4321",
+			expectedIP: "172.18.0.2",
+		},
+		{
+			name:         "IPv4MappedIPv6",
+			remoteAddr:   "[::ffff:172.18.0.2]:4321",
+			forwardedFor: []string{"::ffff:198.51.100.1"},
+			expectedIP:   "198.51.100.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for _, v := range tt.forwardedFor {
+				r.Header.Add("X-Forwarded-For", v)
+			}
+
+			assert.Equal(t, tt.expectedIP, ResolveClientIP(r, trusted))
+		})
+	}
+
+	t.Run("NoTrustedProxies", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = "172.18.0.2:4321"
+		r.Header.Set("X-Forwarded-For", "198.51.100.1")
+
+		assert.Equal(t, "172.18.0.2", ResolveClientIP(r, nil))
+	})
+}
+
+func TestClientIP(t *testing.T) {
+	var got string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = handler.RequestClientIP(r)
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = "172.18.0.2:4321"
+	r.Header.Set("X-Forwarded-For", "198.51.100.1")
+
+	ClientIP([]netip.Prefix{netip.MustParsePrefix("172.16.0.0/12")})(next).ServeHTTP(httptest.NewRecorder(), r)
+	assert.Equal(t, "198.51.100.1", got)
+}
@@ -2,15 +2,24 @@ package middleware
 
 import (
 	"net/http"
+	"slices"
 
 	"github.com/go-chi/cors"
+	"github.com/prawirdani/golang-restapi/internal/transport/http/handler"
 )
 
-func Cors(origins []string, allowCredentials, debug bool) func(next http.Handler) http.Handler {
+// allowedHeaders are the defaults of the cors package, plus the bearer and CSRF token headers.
+var allowedHeaders = []string{"Accept", "Content-Type", "X-Requested-With", "Authorization", handler.CSRFTokenHeader}
+
+// Cors applies the CORS policy, extraHeaders are allowed on top of [allowedHeaders].
+func Cors(origins []string, allowCredentials, debug bool, extraHeaders ...string) func(next http.Handler) http.Handler {
+	headers := append(slices.Clone(allowedHeaders), extraHeaders...)
+
 	return func(next http.Handler) http.Handler {
 		return cors.Handler(
 			cors.Options{
 				AllowedOrigins:   origins,
+				AllowedHeaders:   headers,
 				AllowCredentials: allowCredentials,
 				Debug:            debug,
 			},
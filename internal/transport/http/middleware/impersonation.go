@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"github.com/prawirdani/golang-restapi/internal/domain/auth"
+	"github.com/prawirdani/golang-restapi/internal/transport/http/handler"
+)
+
+// RejectImpersonation rejects requests made with an impersonation token, guarding the sensitive actions only the
+// account owner may take, such as changing the password. Must be placed after [Auth].
+func RejectImpersonation(next handler.Func) handler.Func {
+	return func(c *handler.Context) error {
+		if _, impersonated := auth.GetActorCtx(c.Context()); impersonated {
+			return auth.ErrImpersonationNotAllowed
+		}
+
+		return next(c)
+	}
+}
+
+// RejectPersonalAccessToken rejects requests authenticated with a personal access token, guarding the credential,
+// session and token management a leaked token must not be able to escalate through, such as registering a passkey
+// to sign in with. Must be placed after [Auth].
+func RejectPersonalAccessToken(next handler.Func) handler.Func {
+	return func(c *handler.Context) error {
+		claims, err := auth.GetAccessTokenCtx(c.Context())
+		if err != nil {
+			return err
+		}
+
+		if claims.PersonalAccessToken() {
+			return auth.ErrPersonalAccessTokenDenied
+		}
+
+		return next(c)
+	}
+}
@@ -12,7 +12,7 @@ import (
 func RateLimit(reqLimit int, windowLength time.Duration) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return m.Limit(reqLimit, windowLength,
-			m.WithKeyFuncs(m.KeyByIP, m.KeyByEndpoint),
+			m.WithKeyFuncs(keyByClientIP, m.KeyByEndpoint),
 			m.WithLimitHandler(handler.Handler(func(ctx *handler.Context) error {
 				return httperr.New(
 					http.StatusTooManyRequests,
@@ -23,3 +23,9 @@ func RateLimit(reqLimit int, windowLength time.Duration) func(next http.Handler)
 		)(next)
 	}
 }
+
+// keyByClientIP keys the limit by the client IP resolved by [ClientIP], so clients can't spoof forwarding headers
+// to get a fresh limit.
+func keyByClientIP(r *http.Request) (string, error) {
+	return handler.RequestClientIP(r), nil
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"github.com/prawirdani/golang-restapi/internal/domain/auth"
+	"github.com/prawirdani/golang-restapi/internal/transport/http/handler"
+)
+
+// RequirePermission rejects requests whose access token does not grant the permission.
+// Must be placed after [Auth], which injects the access token claims into the context.
+func RequirePermission(permission string) func(next handler.Func) handler.Func {
+	return func(next handler.Func) handler.Func {
+		return func(c *handler.Context) error {
+			claims, err := auth.GetAccessTokenCtx(c.Context())
+			if err != nil {
+				return err
+			}
+
+			if !claims.HasPermission(permission) {
+				return auth.ErrPermissionDenied
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/prawirdani/golang-restapi/internal/domain/auth"
+	"github.com/prawirdani/golang-restapi/internal/transport/http/handler"
+)
+
+// RequireRecentAuth rejects requests whose user hasn't proved their credentials within maxAge, guarding sensitive
+// operations. Tokens not issued from a session, such as personal access tokens, never pass.
+// Must be placed after [Auth], which injects the access token claims into the context.
+func RequireRecentAuth(maxAge time.Duration) func(next handler.Func) handler.Func {
+	return func(next handler.Func) handler.Func {
+		return func(c *handler.Context) error {
+			claims, err := auth.GetAccessTokenCtx(c.Context())
+			if err != nil {
+				return err
+			}
+
+			if err := handler.RequireRecentAuth(c, claims, maxAge); err != nil {
+				return err
+			}
+
+			return next(c)
+		}
+	}
+}
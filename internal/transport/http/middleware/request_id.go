@@ -21,6 +21,7 @@ func RequestID(next http.Handler) http.Handler {
 
 		ctx := log.WithContext(r.Context(), "request_id", reqID)
 		w.Header().Set(HeaderXRequestID, reqID)
+		r.Header.Set(HeaderXRequestID, reqID) // Visible to the handlers and middlewares down the chain
 
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
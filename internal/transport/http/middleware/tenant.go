@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"github.com/prawirdani/golang-restapi/internal/domain/auth"
+	"github.com/prawirdani/golang-restapi/internal/domain/organization"
+	"github.com/prawirdani/golang-restapi/internal/transport/http/handler"
+)
+
+// TenantResolver resolves the membership of a user in an organization referenced by its ID or slug,
+// implemented by [organization.Service].
+type TenantResolver interface {
+	ResolveTenant(ctx context.Context, userID, ref string) (*organization.Membership, error)
+}
+
+// Tenant resolves the active organization of the request and injects the caller's membership in it into the request
+// context, see [organization.TenantFromContext]. The organization is taken, in order, from the tenant header, the
+// subdomain of baseDomain and the org_id claim of the access token. Must run after [Auth].
+//
+// Requests that name no organization pass through without a tenant, tenant scoped operations then fail with
+// [organization.ErrNoTenant]. Naming an organization the caller isn't a member of is rejected.
+func Tenant(resolver TenantResolver, header, baseDomain string) func(next handler.Func) handler.Func {
+	return func(next handler.Func) handler.Func {
+		return func(c *handler.Context) error {
+			claims, err := auth.GetAccessTokenCtx(c.Context())
+			if err != nil {
+				return err
+			}
+
+			ref := tenantRef(c, claims, header, baseDomain)
+			if ref == "" {
+				return next(c)
+			}
+
+			membership, err := resolver.ResolveTenant(c.Context(), claims.UserID, ref)
+			if err != nil {
+				return err
+			}
+
+			return next(c.WithContext(organization.WithTenant(c.Context(), membership)))
+		}
+	}
+}
+
+func tenantRef(c *handler.Context, claims *auth.AccessTokenClaims, header, baseDomain string) string {
+	if ref := strings.TrimSpace(c.Get(header)); ref != "" {
+		return ref
+	}
+
+	if baseDomain != "" {
+		host := strings.ToLower(c.Host())
+		if sub, ok := strings.CutSuffix(host, "."+baseDomain); ok && organization.ValidSlug(sub) {
+			return sub
+		}
+	}
+
+	return claims.OrgID
+}
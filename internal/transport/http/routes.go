@@ -4,32 +4,190 @@ import (
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/prawirdani/golang-restapi/internal/domain/audit"
+	"github.com/prawirdani/golang-restapi/internal/domain/auth"
 	"github.com/prawirdani/golang-restapi/internal/transport/http/handler"
+	"github.com/prawirdani/golang-restapi/internal/transport/http/middleware"
 )
 
 var fn = handler.Handler
 
 type authMiddleware = func(next http.Handler) http.Handler
 
-func RegisterAuthRoutes(r chi.Router, h *handler.AuthHandler, authMw authMiddleware) {
+// rejectImpersonation adapts [middleware.RejectImpersonation] for chi routers, it must follow the auth middleware.
+var rejectImpersonation = handler.Middleware(middleware.RejectImpersonation)
+
+// rejectPersonalAccessToken adapts [middleware.RejectPersonalAccessToken] for chi routers, it must follow the auth
+// middleware.
+var rejectPersonalAccessToken = handler.Middleware(middleware.RejectPersonalAccessToken)
+
+// requirePermission adapts [middleware.RequirePermission] for chi routers, it must follow the auth middleware.
+func requirePermission(permission string) func(next http.Handler) http.Handler {
+	return handler.Middleware(middleware.RequirePermission(permission))
+}
+
+// RegisterAuthRoutes registers the authentication endpoints. The recent auth middleware guards the sensitive ones,
+// it must follow the auth middleware.
+func RegisterAuthRoutes(r chi.Router, h *handler.AuthHandler, authMw, recentAuthMw authMiddleware) {
 	r.Route("/auth", func(r chi.Router) {
 		r.Post("/login", fn(h.LoginHandler))
+		r.Post("/login/mfa", fn(h.VerifyMFAHandler))
+		r.Post("/login/mfa/passkey", fn(h.BeginMFAPasskeyHandler))
+		r.Post("/login/passkey/begin", fn(h.BeginPasskeyLoginHandler))
+		r.Post("/login/passkey/finish", fn(h.FinishPasskeyLoginHandler))
+		r.Post("/magic-link", fn(h.RequestMagicLinkHandler))
+		r.Post("/magic-link/verify", fn(h.VerifyMagicLinkHandler))
+		r.Get("/oidc/{provider}/login", fn(h.ExternalLoginHandler))
+		r.Get("/oidc/{provider}/callback", fn(h.ExternalLoginCallbackHandler))
 		r.Post("/register", fn(h.RegisterHandler))
 		r.Delete("/logout", fn(h.LogoutHandler))
 		r.Post("/password/forgot", fn(h.ForgotPasswordHandler))
 		r.Get("/password/reset/{token}", fn(h.GetResetPasswordTokenHandler))
 		r.Post("/password/reset", fn(h.ResetPasswordHandler))
+		r.Post("/unlock", fn(h.UnlockAccountHandler))
+		r.Post("/sessions/revoke-unrecognized", fn(h.RevokeUnrecognizedSessionHandler))
+		r.Post("/email/verify", fn(h.VerifyEmailHandler))
+		r.Post("/email/resend", fn(h.ResendVerificationEmailHandler))
+		r.Post("/email/change/confirm", fn(h.ConfirmEmailChangeHandler))
 
 		r.Get("/refresh", fn(h.RefreshTokenHandler))
 		r.With(authMw).Group(func(r chi.Router) {
 			r.Get("/me", fn(h.GetCurrentUserHandler))
-			r.Post("/password/change", fn(h.ChangePasswordHandler))
+			r.Get("/devices", fn(h.ListKnownDevicesHandler))
+			r.Get("/passkeys", fn(h.ListPasskeysHandler))
+			r.Get("/sessions", fn(h.ListSessionsHandler))
+			r.Get("/tokens", fn(h.ListPersonalAccessTokensHandler))
+			r.Post("/organization", fn(h.SwitchOrganizationHandler))
+			r.Delete("/impersonation", fn(h.StopImpersonationHandler))
+
+			// Credentials and sessions are managed by the account owner only, signed in rather than through a token
+			r.With(rejectImpersonation, rejectPersonalAccessToken).Group(func(r chi.Router) {
+				r.Post("/reauthenticate", fn(h.ReauthenticateHandler))
+				r.Post("/reauthenticate/passkey", fn(h.BeginReauthenticationPasskeyHandler))
+				r.Post("/password/change", fn(h.ChangePasswordHandler))
+				r.Delete("/sessions/{id}", fn(h.RevokeSessionHandler))
+				r.Delete("/devices/{id}", fn(h.ForgetKnownDeviceHandler))
+				r.Post("/sessions/revoke-others", fn(h.RevokeOtherSessionsHandler))
+				r.With(recentAuthMw).Post("/tokens", fn(h.CreatePersonalAccessTokenHandler))
+				r.Delete("/tokens/{id}", fn(h.RevokePersonalAccessTokenHandler))
+
+				// A stolen session must not be able to add or remove a factor, the user proves their credentials first
+				r.With(recentAuthMw).Group(func(r chi.Router) {
+					r.Post("/mfa/totp/enroll", fn(h.EnrollTOTPHandler))
+					r.Post("/mfa/totp/confirm", fn(h.ConfirmTOTPHandler))
+					r.Post("/mfa/totp/disable", fn(h.DisableTOTPHandler))
+					r.Post("/mfa/recovery-codes", fn(h.RegenerateRecoveryCodesHandler))
+					r.Post("/passkeys/register/begin", fn(h.BeginPasskeyRegistrationHandler))
+					r.Post("/passkeys/register/finish", fn(h.FinishPasskeyRegistrationHandler))
+					r.Delete("/passkeys/{id}", fn(h.DeletePasskeyHandler))
+				})
+			})
 		})
 	})
 }
 
-func RegisterUserRoutes(r chi.Router, h *handler.UserHandler, authMw authMiddleware) {
+// RegisterOrganizationRoutes registers the organization endpoints. Routes under /organization act on the active
+// organization, resolved by the tenant middleware which must follow the auth middleware.
+func RegisterOrganizationRoutes(
+	r chi.Router,
+	h *handler.OrganizationHandler,
+	authMw authMiddleware,
+	tenantMw authMiddleware,
+) {
+	r.Route("/organizations", func(r chi.Router) {
+		// Declining needs no account, the token proves the invitation was received
+		r.Post("/invitations/decline", fn(h.DeclineInvitationHandler))
+
+		r.With(authMw).Group(func(r chi.Router) {
+			r.Get("/", fn(h.ListOrganizationsHandler))
+			r.Post("/", fn(h.CreateOrganizationHandler))
+			r.Post("/invitations/accept", fn(h.AcceptInvitationHandler))
+		})
+	})
+
+	r.With(authMw, tenantMw).Route("/organization", func(r chi.Router) {
+		r.Get("/", fn(h.GetCurrentOrganizationHandler))
+		r.Get("/members", fn(h.ListMembersHandler))
+		r.Patch("/members/{userID}", fn(h.UpdateMemberRoleHandler))
+		r.Delete("/members/{userID}", fn(h.RemoveMemberHandler))
+		r.Get("/invitations", fn(h.ListInvitationsHandler))
+		r.Post("/invitations", fn(h.InviteMemberHandler))
+		r.Delete("/invitations/{id}", fn(h.RevokeInvitationHandler))
+	})
+}
+
+func RegisterWellKnownRoutes(r chi.Router, h *handler.AuthHandler) {
+	r.Route("/.well-known", func(r chi.Router) {
+		r.Get("/jwks.json", fn(h.JWKSHandler))
+	})
+}
+
+// RegisterOAuthRoutes registers the authorization server endpoints. The authorize endpoint needs the optional auth
+// middleware, since it redirects signed out users to the login page instead of failing. Impersonated users can't
+// authorize clients, the tokens issued would no longer carry the actor.
+func RegisterOAuthRoutes(r chi.Router, h *handler.OAuthHandler, optionalAuthMw authMiddleware) {
+	r.Route("/oauth", func(r chi.Router) {
+		r.With(optionalAuthMw, rejectImpersonation).Get("/authorize", fn(h.AuthorizeHandler))
+		r.Post("/token", fn(h.TokenHandler))
+		r.Post("/introspect", fn(h.IntrospectHandler))
+		r.Post("/revoke", fn(h.RevokeHandler))
+	})
+}
+
+func RegisterUserRoutes(
+	r chi.Router,
+	h *handler.UserHandler,
+	authHandler *handler.AuthHandler,
+	authMw authMiddleware,
+	recentAuthMw authMiddleware,
+) {
 	r.With(authMw).Route("/users", func(r chi.Router) {
 		r.Post("/profile/upload", fn(h.ChangeProfilePictureHandler))
+		r.Patch("/me", fn(h.UpdateProfileHandler))
+		r.With(rejectImpersonation, rejectPersonalAccessToken, recentAuthMw).
+			Delete("/me", fn(authHandler.DeleteAccountHandler))
+		r.Get("/me/security-events", fn(h.ListSecurityEventsHandler))
+	})
+}
+
+func RegisterAdminRoutes(
+	r chi.Router,
+	h *handler.AdminHandler,
+	roleHandler *handler.RoleHandler,
+	oauthHandler *handler.OAuthHandler,
+	authMw authMiddleware,
+) {
+	r.With(authMw).Route("/admin", func(r chi.Router) {
+		r.With(requirePermission(auth.PermissionUsersRead)).Group(func(r chi.Router) {
+			r.Get("/users", fn(h.ListUsersHandler))
+			r.Get("/users/{id}", fn(h.GetUserHandler))
+		})
+		r.With(requirePermission(auth.PermissionUsersWrite)).Group(func(r chi.Router) {
+			r.Post("/users/{id}/suspend", fn(h.SuspendUserHandler))
+			r.Post("/users/{id}/unsuspend", fn(h.UnsuspendUserHandler))
+			r.Post("/users/{id}/password-reset", fn(h.ForcePasswordResetHandler))
+		})
+		r.With(requirePermission(auth.PermissionUsersImpersonate)).Group(func(r chi.Router) {
+			r.Post("/users/{id}/impersonate", fn(h.ImpersonateUserHandler))
+		})
+
+		r.With(requirePermission(auth.PermissionRolesRead)).Group(func(r chi.Router) {
+			r.Get("/roles", fn(roleHandler.ListRolesHandler))
+			r.Get("/users/{id}/roles", fn(roleHandler.GetUserRolesHandler))
+		})
+		r.With(requirePermission(auth.PermissionRolesWrite)).Group(func(r chi.Router) {
+			r.Post("/users/{id}/roles", fn(roleHandler.AssignRoleHandler))
+			r.Delete("/users/{id}/roles/{role}", fn(roleHandler.RevokeRoleHandler))
+		})
+
+		r.With(requirePermission(auth.PermissionOAuthClientsWrite)).Group(func(r chi.Router) {
+			r.Get("/oauth/clients", fn(oauthHandler.ListClientsHandler))
+			r.Post("/oauth/clients", fn(oauthHandler.RegisterClientHandler))
+			r.Delete("/oauth/clients/{id}", fn(oauthHandler.DeleteClientHandler))
+		})
+
+		r.With(requirePermission(audit.PermissionAuditRead)).Group(func(r chi.Router) {
+			r.Get("/audit-events", fn(h.ListAuditEventsHandler))
+		})
 	})
 }
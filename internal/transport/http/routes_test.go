@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/prawirdani/golang-restapi/internal/domain/auth"
+	"github.com/prawirdani/golang-restapi/internal/transport/http/handler"
+	"github.com/prawirdani/golang-restapi/internal/transport/http/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterAuthRoutes_RejectsOAuthTokens(t *testing.T) {
+	keys := auth.NewHMACKeySet("secret")
+	authMw := handler.Middleware(middleware.Auth(keys, nil, nil, nil))
+	passthrough := func(next http.Handler) http.Handler { return next }
+
+	r := chi.NewRouter()
+	RegisterAuthRoutes(r, &handler.AuthHandler{}, authMw, passthrough)
+
+	tokens := map[string]auth.AccessTokenClaims{
+		"AuthorizationCode": {
+			UserID:      "user-id",
+			ClientID:    "client-id",
+			Scope:       "users:read",
+			Permissions: []string{"users:read"},
+			RegisteredClaims: jwt.RegisteredClaims{
+				Audience: jwt.ClaimStrings{auth.OAuthAudience},
+			},
+		},
+		"ClientCredentials": {
+			ClientID:    "client-id",
+			Scope:       "roles:write users:write",
+			Permissions: []string{"roles:write", "users:write"},
+			RegisteredClaims: jwt.RegisteredClaims{
+				Audience: jwt.ClaimStrings{auth.OAuthAudience},
+			},
+		},
+		"ClientIDWithoutAudience": {
+			UserID:   "user-id",
+			ClientID: "client-id",
+		},
+		"EmptyUserID": {
+			SessionID:   "session-id",
+			Permissions: []string{"users:write"},
+		},
+	}
+
+	requests := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodGet, "/auth/me"},
+		{http.MethodPost, "/auth/sessions/revoke-others"},
+	}
+
+	for name, claims := range tokens {
+		token, err := auth.SignAccessToken(keys, claims, time.Minute)
+		require.NoError(t, err)
+
+		for _, req := range requests {
+			t.Run(name+req.path, func(t *testing.T) {
+				rec := httptest.NewRecorder()
+				httpReq := httptest.NewRequest(req.method, req.path, nil)
+				httpReq.Header.Set("Authorization", "Bearer "+token)
+
+				r.ServeHTTP(rec, httpReq)
+				assert.Equal(t, http.StatusUnauthorized, rec.Code)
+			})
+		}
+	}
+}
+
+// stubPersonalAccessTokens authenticates every personal access token as a token of the same user.
+type stubPersonalAccessTokens struct{}
+
+func (stubPersonalAccessTokens) AuthenticatePersonalAccessToken(
+	_ context.Context,
+	_ string,
+) (*auth.AccessTokenClaims, error) {
+	return &auth.AccessTokenClaims{
+		UserID: "user-id",
+		AMR:    []string{auth.AMRPersonalAccessToken},
+	}, nil
+}
+
+func TestRegisterAuthRoutes_RejectsPersonalAccessTokens(t *testing.T) {
+	keys := auth.NewHMACKeySet("secret")
+	authMw := handler.Middleware(middleware.Auth(keys, nil, stubPersonalAccessTokens{}, nil))
+	passthrough := func(next http.Handler) http.Handler { return next }
+
+	r := chi.NewRouter()
+	RegisterAuthRoutes(r, &handler.AuthHandler{}, authMw, passthrough)
+
+	requests := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodPost, "/auth/passkeys/register/begin"},
+		{http.MethodPost, "/auth/passkeys/register/finish"},
+		{http.MethodPost, "/auth/mfa/totp/enroll"},
+		{http.MethodPost, "/auth/mfa/totp/disable"},
+		{http.MethodDelete, "/auth/passkeys/passkey-id"},
+		{http.MethodPost, "/auth/sessions/revoke-others"},
+		{http.MethodPost, "/auth/tokens"},
+	}
+
+	for _, req := range requests {
+		t.Run(req.method+req.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			httpReq := httptest.NewRequest(req.method, req.path, nil)
+			httpReq.Header.Set("Authorization", "Bearer "+auth.PersonalAccessTokenPrefix+"secret")
+
+			r.ServeHTTP(rec, httpReq)
+			assert.Equal(t, http.StatusForbidden, rec.Code)
+		})
+	}
+}
+
+func TestRegisterAuthRoutes_RequiresRecentAuthForFactors(t *testing.T) {
+	keys := auth.NewHMACKeySet("secret")
+	authMw := handler.Middleware(middleware.Auth(keys, nil, nil, nil))
+	recentAuthMw := handler.Middleware(middleware.RequireRecentAuth(5 * time.Minute))
+
+	r := chi.NewRouter()
+	RegisterAuthRoutes(r, &handler.AuthHandler{}, authMw, recentAuthMw)
+
+	// Signed in an hour ago, the session is still valid but its authentication isn't recent
+	token, err := auth.SignAccessToken(keys, auth.AccessTokenClaims{
+		UserID:    "user-id",
+		SessionID: "session-id",
+		AuthTime:  jwt.NewNumericDate(time.Now().Add(-time.Hour)),
+	}, time.Minute)
+	require.NoError(t, err)
+
+	requests := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodPost, "/auth/passkeys/register/begin"},
+		{http.MethodPost, "/auth/passkeys/register/finish"},
+		{http.MethodDelete, "/auth/passkeys/passkey-id"},
+		{http.MethodPost, "/auth/mfa/totp/enroll"},
+		{http.MethodPost, "/auth/mfa/totp/confirm"},
+		{http.MethodPost, "/auth/mfa/totp/disable"},
+		{http.MethodPost, "/auth/mfa/recovery-codes"},
+	}
+
+	for _, req := range requests {
+		t.Run(req.method+req.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			httpReq := httptest.NewRequest(req.method, req.path, nil)
+			httpReq.Header.Set("Authorization", "Bearer "+token)
+
+			r.ServeHTTP(rec, httpReq)
+			assert.Equal(t, http.StatusUnauthorized, rec.Code)
+			assert.Contains(t, rec.Header().Get("WWW-Authenticate"), `error="insufficient_user_authentication"`)
+			assert.Contains(t, rec.Body.String(), "insufficient_user_authentication")
+		})
+	}
+}
+
+// recordingImpersonationAuditor keeps the tokens it is asked to audit as expired impersonations.
+type recordingImpersonationAuditor struct {
+	tokens []string
+}
+
+func (a *recordingImpersonationAuditor) EndExpiredImpersonation(_ context.Context, token string) error {
+	a.tokens = append(a.tokens, token)
+	return nil
+}
+
+func TestRegisterAuthRoutes_AuditsExpiredImpersonation(t *testing.T) {
+	keys := auth.NewHMACKeySet("secret")
+	auditor := &recordingImpersonationAuditor{}
+	authMw := handler.Middleware(middleware.Auth(keys, nil, nil, auditor))
+	passthrough := func(next http.Handler) http.Handler { return next }
+
+	r := chi.NewRouter()
+	RegisterAuthRoutes(r, &handler.AuthHandler{}, authMw, passthrough)
+
+	token, err := auth.SignAccessToken(keys, auth.AccessTokenClaims{
+		UserID: "user-id",
+		Actor:  &auth.Actor{UserID: "admin-id"},
+	}, -time.Minute)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/auth/me", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, []string{token}, auditor.tokens)
+}
@@ -0,0 +1,56 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT
+  'up SQL query';
+
+CREATE TABLE IF NOT EXISTS totp_factors (
+  user_id UUID NOT NULL PRIMARY KEY,
+  secret VARCHAR(255) NOT NULL,
+  confirmed_at TIMESTAMPTZ,
+  last_used_step BIGINT NOT NULL DEFAULT 0,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT fk_totp_factor_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS recovery_codes (
+  user_id UUID NOT NULL,
+  code_hash VARCHAR(255) NOT NULL,
+  used_at TIMESTAMPTZ,
+  PRIMARY KEY (user_id, code_hash),
+  CONSTRAINT fk_recovery_code_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS mfa_challenges (
+  value VARCHAR(255) NOT NULL PRIMARY KEY,
+  user_id UUID NOT NULL,
+  user_agent VARCHAR(255) NOT NULL,
+  attempts INT NOT NULL DEFAULT 0,
+  expires_at TIMESTAMPTZ NOT NULL,
+  used_at TIMESTAMPTZ,
+  CONSTRAINT fk_mfa_challenge_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+ALTER TABLE sessions
+ADD COLUMN IF NOT EXISTS amr TEXT[] DEFAULT '{}';
+
+-- Every session created before this migration was password based
+UPDATE sessions
+SET
+  amr = '{pwd}';
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+SELECT
+  'down SQL query';
+
+ALTER TABLE sessions
+DROP COLUMN IF EXISTS amr;
+
+DROP TABLE IF EXISTS mfa_challenges;
+
+DROP TABLE IF EXISTS recovery_codes;
+
+DROP TABLE IF EXISTS totp_factors;
+
+-- +goose StatementEnd
@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT
+  'up SQL query';
+
+ALTER TABLE sessions
+ADD COLUMN IF NOT EXISTS family_id UUID,
+ADD COLUMN IF NOT EXISTS rotated_at TIMESTAMPTZ;
+
+-- Existing sessions start their own family
+UPDATE sessions
+SET
+  family_id = id
+WHERE
+  family_id IS NULL;
+
+ALTER TABLE sessions
+ALTER COLUMN family_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_sessions_family_id ON sessions (family_id);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+SELECT
+  'down SQL query';
+
+DROP INDEX IF EXISTS idx_sessions_family_id;
+
+ALTER TABLE sessions
+DROP COLUMN IF EXISTS rotated_at,
+DROP COLUMN IF EXISTS family_id;
+
+-- +goose StatementEnd
@@ -0,0 +1,71 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT
+  'up SQL query';
+
+CREATE TABLE IF NOT EXISTS roles (
+  id SERIAL PRIMARY KEY,
+  name VARCHAR(50) NOT NULL UNIQUE,
+  description VARCHAR(255) NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+  role_id INT NOT NULL,
+  permission VARCHAR(100) NOT NULL,
+  PRIMARY KEY (role_id, permission),
+  CONSTRAINT fk_role_permission_role FOREIGN KEY (role_id) REFERENCES roles (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+  user_id UUID NOT NULL,
+  role_id INT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (user_id, role_id),
+  CONSTRAINT fk_user_role_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+  CONSTRAINT fk_user_role_role FOREIGN KEY (role_id) REFERENCES roles (id) ON DELETE CASCADE
+);
+
+-- Built-in roles
+INSERT INTO
+  roles (name, description)
+VALUES
+  ('admin', 'Full access to every resource'),
+  ('support', 'Read access to user accounts')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO
+  role_permissions (role_id, permission)
+SELECT
+  id,
+  '*'
+FROM
+  roles
+WHERE
+  name = 'admin'
+ON CONFLICT DO NOTHING;
+
+INSERT INTO
+  role_permissions (role_id, permission)
+SELECT
+  id,
+  'users:read'
+FROM
+  roles
+WHERE
+  name = 'support'
+ON CONFLICT DO NOTHING;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+SELECT
+  'down SQL query';
+
+DROP TABLE IF EXISTS user_roles;
+
+DROP TABLE IF EXISTS role_permissions;
+
+DROP TABLE IF EXISTS roles;
+
+-- +goose StatementEnd
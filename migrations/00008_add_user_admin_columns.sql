@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT
+  'up SQL query';
+
+ALTER TABLE users
+ADD COLUMN IF NOT EXISTS suspended_at TIMESTAMPTZ,
+ADD COLUMN IF NOT EXISTS password_reset_required BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_users_created_at ON users (created_at);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+SELECT
+  'down SQL query';
+
+DROP INDEX IF EXISTS idx_users_created_at;
+
+ALTER TABLE users
+DROP COLUMN IF EXISTS password_reset_required,
+DROP COLUMN IF EXISTS suspended_at;
+
+-- +goose StatementEnd
@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT
+  'up SQL query';
+
+ALTER TABLE users
+ADD COLUMN IF NOT EXISTS anonymized_at TIMESTAMPTZ;
+
+-- Soft deleted users waiting to be purged once their grace period ends
+CREATE INDEX IF NOT EXISTS idx_users_pending_purge ON users (deleted_at)
+WHERE
+  deleted_at IS NOT NULL
+  AND anonymized_at IS NULL;
+
+ALTER TABLE sessions
+DROP CONSTRAINT IF EXISTS fk_user_id,
+ADD CONSTRAINT fk_user_id FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+SELECT
+  'down SQL query';
+
+ALTER TABLE sessions
+DROP CONSTRAINT IF EXISTS fk_user_id,
+ADD CONSTRAINT fk_user_id FOREIGN KEY (user_id) REFERENCES users (id);
+
+DROP INDEX IF EXISTS idx_users_pending_purge;
+
+ALTER TABLE users
+DROP COLUMN IF EXISTS anonymized_at;
+
+-- +goose StatementEnd
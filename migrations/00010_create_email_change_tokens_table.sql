@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT
+  'up SQL query';
+
+CREATE TABLE IF NOT EXISTS email_change_tokens (
+  user_id UUID NOT NULL,
+  new_email VARCHAR(50) NOT NULL,
+  value VARCHAR(255) NOT NULL,
+  expires_at TIMESTAMPTZ NOT NULL,
+  used_at TIMESTAMPTZ,
+  PRIMARY KEY (user_id, value),
+  CONSTRAINT fk_email_change_token_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+SELECT
+  'down SQL query';
+
+DROP TABLE IF EXISTS email_change_tokens;
+
+-- +goose StatementEnd
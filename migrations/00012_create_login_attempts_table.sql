@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT
+  'up SQL query';
+
+CREATE TABLE IF NOT EXISTS login_attempts (
+  key VARCHAR(100) PRIMARY KEY,
+  failures INT NOT NULL DEFAULT 0,
+  last_failed_at TIMESTAMPTZ NOT NULL,
+  locked_until TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS account_unlock_tokens (
+  user_id UUID NOT NULL,
+  value VARCHAR(255) NOT NULL,
+  expires_at TIMESTAMPTZ NOT NULL,
+  used_at TIMESTAMPTZ,
+  PRIMARY KEY (user_id, value),
+  CONSTRAINT fk_account_unlock_token_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+SELECT
+  'down SQL query';
+
+DROP TABLE IF EXISTS account_unlock_tokens;
+
+DROP TABLE IF EXISTS login_attempts;
+
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT
+  'up SQL query';
+
+CREATE TABLE IF NOT EXISTS magic_link_tokens (
+  user_id UUID NOT NULL,
+  value VARCHAR(255) NOT NULL,
+  expires_at TIMESTAMPTZ NOT NULL,
+  used_at TIMESTAMPTZ,
+  PRIMARY KEY (user_id, value),
+  CONSTRAINT fk_magic_link_token_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+-- Factor passed before the challenge, so sessions record how the login started
+ALTER TABLE mfa_challenges
+ADD COLUMN IF NOT EXISTS first_factor VARCHAR(10) NOT NULL DEFAULT 'pwd';
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+SELECT
+  'down SQL query';
+
+ALTER TABLE mfa_challenges
+DROP COLUMN IF EXISTS first_factor;
+
+DROP TABLE IF EXISTS magic_link_tokens;
+
+-- +goose StatementEnd
@@ -0,0 +1,56 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT
+  'up SQL query';
+
+CREATE TABLE IF NOT EXISTS oauth_clients (
+  id VARCHAR(36) PRIMARY KEY,
+  name VARCHAR(100) NOT NULL,
+  secret_hash VARCHAR(64) NOT NULL DEFAULT '', -- Empty for public clients
+  redirect_uris TEXT[] NOT NULL DEFAULT '{}',
+  grant_types TEXT[] NOT NULL DEFAULT '{}',
+  scopes TEXT[] NOT NULL DEFAULT '{}',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS oauth_authorization_codes (
+  code_hash VARCHAR(64) PRIMARY KEY,
+  client_id VARCHAR(36) NOT NULL,
+  user_id UUID NOT NULL,
+  redirect_uri TEXT NOT NULL,
+  scopes TEXT[] NOT NULL DEFAULT '{}',
+  code_challenge VARCHAR(128) NOT NULL,
+  expires_at TIMESTAMPTZ NOT NULL,
+  used_at TIMESTAMPTZ,
+  CONSTRAINT fk_oauth_code_client FOREIGN KEY (client_id) REFERENCES oauth_clients (id) ON DELETE CASCADE,
+  CONSTRAINT fk_oauth_code_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS oauth_refresh_tokens (
+  id UUID PRIMARY KEY,
+  token_hash VARCHAR(64) NOT NULL UNIQUE,
+  client_id VARCHAR(36) NOT NULL,
+  user_id UUID NOT NULL,
+  scopes TEXT[] NOT NULL DEFAULT '{}',
+  expires_at TIMESTAMPTZ NOT NULL,
+  revoked_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT fk_oauth_refresh_token_client FOREIGN KEY (client_id) REFERENCES oauth_clients (id) ON DELETE CASCADE,
+  CONSTRAINT fk_oauth_refresh_token_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_oauth_refresh_tokens_client_user ON oauth_refresh_tokens (client_id, user_id);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+SELECT
+  'down SQL query';
+
+DROP TABLE IF EXISTS oauth_refresh_tokens;
+
+DROP TABLE IF EXISTS oauth_authorization_codes;
+
+DROP TABLE IF EXISTS oauth_clients;
+
+-- +goose StatementEnd
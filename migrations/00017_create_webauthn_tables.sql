@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT
+  'up SQL query';
+
+CREATE TABLE IF NOT EXISTS webauthn_credentials (
+  id VARCHAR(1400) PRIMARY KEY, -- Base64url credential ID, at most 1023 bytes raw
+  user_id UUID NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  public_key BYTEA NOT NULL,
+  attestation_type VARCHAR(50) NOT NULL DEFAULT '',
+  transports TEXT[] NOT NULL DEFAULT '{}',
+  aaguid UUID NOT NULL,
+  sign_count BIGINT NOT NULL DEFAULT 0,
+  backup_eligible BOOLEAN NOT NULL DEFAULT FALSE,
+  backup_state BOOLEAN NOT NULL DEFAULT FALSE,
+  last_used_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT fk_webauthn_credential_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_webauthn_credentials_user_id ON webauthn_credentials (user_id);
+
+CREATE TABLE IF NOT EXISTS webauthn_challenges (
+  value VARCHAR(255) NOT NULL PRIMARY KEY,
+  user_id UUID, -- Unset for passwordless logins
+  ceremony VARCHAR(20) NOT NULL,
+  state JSONB NOT NULL,
+  expires_at TIMESTAMPTZ NOT NULL,
+  used_at TIMESTAMPTZ,
+  CONSTRAINT fk_webauthn_challenge_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+SELECT
+  'down SQL query';
+
+DROP TABLE IF EXISTS webauthn_challenges;
+
+DROP TABLE IF EXISTS webauthn_credentials;
+
+-- +goose StatementEnd
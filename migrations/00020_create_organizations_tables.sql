@@ -0,0 +1,63 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT
+  'up SQL query';
+
+CREATE TABLE IF NOT EXISTS organizations (
+  id UUID PRIMARY KEY,
+  name VARCHAR(100) NOT NULL,
+  slug VARCHAR(50) NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT organizations_slug_key UNIQUE (slug)
+);
+
+CREATE TABLE IF NOT EXISTS organization_memberships (
+  organization_id UUID NOT NULL,
+  user_id UUID NOT NULL,
+  role VARCHAR(20) NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT organization_memberships_pkey PRIMARY KEY (organization_id, user_id),
+  CONSTRAINT fk_organization_membership_organization FOREIGN KEY (organization_id) REFERENCES organizations (id) ON DELETE CASCADE,
+  CONSTRAINT fk_organization_membership_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_organization_memberships_user_id ON organization_memberships (user_id);
+
+CREATE TABLE IF NOT EXISTS organization_invitations (
+  id UUID PRIMARY KEY,
+  organization_id UUID NOT NULL,
+  email VARCHAR(255) NOT NULL,
+  role VARCHAR(20) NOT NULL,
+  token_hash VARCHAR(64) NOT NULL UNIQUE,
+  invited_by UUID NOT NULL,
+  expires_at TIMESTAMPTZ NOT NULL,
+  accepted_at TIMESTAMPTZ,
+  declined_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT fk_organization_invitation_organization FOREIGN KEY (organization_id) REFERENCES organizations (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_organization_invitations_organization_id ON organization_invitations (organization_id, email);
+
+-- Active organization of the session, carried into the access tokens refreshed from it
+ALTER TABLE sessions
+ADD COLUMN IF NOT EXISTS organization_id UUID;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+SELECT
+  'down SQL query';
+
+ALTER TABLE sessions
+DROP COLUMN IF EXISTS organization_id;
+
+DROP TABLE IF EXISTS organization_invitations;
+
+DROP TABLE IF EXISTS organization_memberships;
+
+DROP TABLE IF EXISTS organizations;
+
+-- +goose StatementEnd
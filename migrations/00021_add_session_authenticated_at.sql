@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT
+  'up SQL query';
+
+-- Last time the user proved their credentials within the session, the auth_time claim of its access tokens
+ALTER TABLE sessions
+ADD COLUMN IF NOT EXISTS authenticated_at TIMESTAMPTZ;
+
+-- When existing sessions authenticated is unknown, they have to re-authenticate before a sensitive operation
+UPDATE sessions
+SET
+  authenticated_at = 'epoch'
+WHERE
+  authenticated_at IS NULL;
+
+ALTER TABLE sessions
+ALTER COLUMN authenticated_at SET NOT NULL;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+SELECT
+  'down SQL query';
+
+ALTER TABLE sessions
+DROP COLUMN IF EXISTS authenticated_at;
+
+-- +goose StatementEnd
@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT
+  'up SQL query';
+
+CREATE TABLE IF NOT EXISTS known_devices (
+  id UUID PRIMARY KEY,
+  user_id UUID NOT NULL,
+  fingerprint VARCHAR(64) NOT NULL, -- SHA-256 of the normalized user agent and the client IP network
+  user_agent TEXT NOT NULL DEFAULT '',
+  ip_address VARCHAR(45) NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  last_seen_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT uq_known_device_fingerprint UNIQUE (user_id, fingerprint),
+  CONSTRAINT fk_known_device_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS session_revoke_tokens (
+  value VARCHAR(255) NOT NULL PRIMARY KEY,
+  user_id UUID NOT NULL,
+  family_id UUID NOT NULL,
+  device_id UUID NOT NULL, -- Not a foreign key, the device may be forgotten before the link is used
+  expires_at TIMESTAMPTZ NOT NULL,
+  used_at TIMESTAMPTZ,
+  CONSTRAINT fk_session_revoke_token_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+SELECT
+  'down SQL query';
+
+DROP TABLE IF EXISTS session_revoke_tokens;
+
+DROP TABLE IF EXISTS known_devices;
+
+-- +goose StatementEnd
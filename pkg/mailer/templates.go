@@ -9,7 +9,17 @@ import (
 var templatesFS embed.FS
 
 type Templates struct {
-	ResetPassword *template.Template
+	ResetPassword     *template.Template
+	EmailVerification *template.Template
+
+	EmailChangeConfirmation *template.Template
+	EmailChangeNotification *template.Template
+	AccountLocked           *template.Template
+	MagicLink               *template.Template
+	AccountExists           *template.Template
+	NewDeviceLogin          *template.Template
+
+	OrganizationInvitation *template.Template
 }
 
 func parseTemplates() *Templates {
@@ -17,5 +27,29 @@ func parseTemplates() *Templates {
 		ResetPassword: template.Must(
 			template.ParseFS(templatesFS, "templates/reset-password-mail.html"),
 		),
+		EmailVerification: template.Must(
+			template.ParseFS(templatesFS, "templates/verification-mail.html"),
+		),
+		EmailChangeConfirmation: template.Must(
+			template.ParseFS(templatesFS, "templates/email-change-confirmation-mail.html"),
+		),
+		EmailChangeNotification: template.Must(
+			template.ParseFS(templatesFS, "templates/email-change-notification-mail.html"),
+		),
+		AccountLocked: template.Must(
+			template.ParseFS(templatesFS, "templates/account-locked-mail.html"),
+		),
+		MagicLink: template.Must(
+			template.ParseFS(templatesFS, "templates/magic-link-mail.html"),
+		),
+		AccountExists: template.Must(
+			template.ParseFS(templatesFS, "templates/account-exists-mail.html"),
+		),
+		NewDeviceLogin: template.Must(
+			template.ParseFS(templatesFS, "templates/new-device-login-mail.html"),
+		),
+		OrganizationInvitation: template.Must(
+			template.ParseFS(templatesFS, "templates/organization-invitation-mail.html"),
+		),
 	}
 }
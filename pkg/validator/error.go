@@ -36,6 +36,15 @@ func (v *ValidationError) Error() string {
 	return strings.Join(msgs, "; ")
 }
 
+// Add appends an error for a field, for rules checked outside of struct tags
+func (v *ValidationError) Add(field, tag, message string) {
+	if v.Details == nil {
+		v.Details = make(map[string][]string)
+	}
+	v.Errors = append(v.Errors, FieldError{Field: field, Tag: tag, Message: message})
+	v.Details[field] = append(v.Details[field], message)
+}
+
 // HasField checks if a specific field has validation errors
 func (v *ValidationError) HasField(field string) bool {
 	_, exists := v.Details[field]